@@ -21,6 +21,7 @@ import (
 	"fashion-shop/internal/delivery/http/middleware"
 	"fashion-shop/internal/delivery/http/routes"
 	"fashion-shop/internal/infrastructure/persistence"
+	"fashion-shop/internal/infrastructure/scheduler"
 )
 
 func main() {
@@ -55,8 +56,12 @@ func main() {
 	rateLimiter := middleware.NewRateLimiter(redisClient, cfg.RateLimit.Requests, cfg.RateLimit.Duration)
 	router.Use(rateLimiter.Middleware())
 
-	// Register routes
-	routes.RegisterRoutes(router, repos, redisClient, cfg)
+	// Register routes and their background jobs
+	jobs := scheduler.NewScheduler()
+	routes.RegisterRoutes(router, repos, redisClient, cfg, jobs)
+
+	// Start background jobs
+	jobs.Start()
 
 	// Start server
 	srv := &http.Server{
@@ -82,6 +87,7 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	jobs.Stop()
 
 	log.Println("Server exiting")
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"fashion-shop/internal/config"
+	"fashion-shop/internal/domain/usecase/impl"
+	"fashion-shop/internal/infrastructure/persistence"
+	"fashion-shop/internal/infrastructure/search"
+)
+
+// reindex rebuilds the external product search index from the database
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+
+	// Initialize configuration
+	cfg := config.NewConfig()
+
+	searchIndex, err := search.NewSearchIndex(
+		cfg.Search.Engine,
+		cfg.Search.MeilisearchURL,
+		cfg.Search.MeilisearchAPIKey,
+		cfg.Search.MeilisearchIndex,
+	)
+	if err != nil {
+		log.Fatalf("Failed to configure search index: %v", err)
+	}
+	if searchIndex == nil {
+		log.Fatalf("Search engine %q has no external index to rebuild", cfg.Search.Engine)
+	}
+	if searchIndex.Name() == search.EngineMemory {
+		log.Fatalf("The in-memory search index is rebuilt by the API server on start")
+	}
+
+	// Set up database connection
+	db, err := setupDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	repos := persistence.NewRepositories(db)
+
+	indexed, err := impl.NewSearchIndexUseCase(repos.ProductSearch, searchIndex).Reindex(context.Background())
+	if err != nil {
+		log.Fatalf("Reindex failed after %d products: %v", indexed, err)
+	}
+
+	log.Printf("Indexed %d products in %s", indexed, searchIndex.Name())
+}
+
+func setupDatabase(cfg *config.Config) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Jakarta",
+		cfg.Database.Host,
+		cfg.Database.User,
+		cfg.Database.Password,
+		cfg.Database.Name,
+		cfg.Database.Port,
+	)
+
+	gormConfig := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	}
+
+	return gorm.Open(postgres.Open(dsn), gormConfig)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -40,10 +41,67 @@ type Config struct {
 	}
 	Midtrans struct {
 		ServerKey      string
-		ClientKey      string
 		Environment    string
 		PaymentWebhook string
 	}
+	Xendit struct {
+		SecretKey     string
+		CallbackToken string
+		URL           string
+	}
+	FakeGateway struct {
+		Enabled    bool
+		Secret     string
+		WebhookURL string
+		AutoSettle time.Duration
+	}
+	PaymentGateway struct {
+		Default      string
+		CreditCard   string
+		BankTransfer string
+		EWallet      string
+		QRIS         string
+		CallbackURL  string // where e-wallet apps redirect after payment
+	}
+	PaymentExpiry struct {
+		CreditCard    time.Duration
+		BankTransfer  time.Duration
+		EWallet       time.Duration
+		QRIS          time.Duration
+		CheckInterval time.Duration
+	}
+	Shipping struct {
+		FlatRate float64 // charged for shipping each order
+	}
+	Returns struct {
+		Window        time.Duration // how long after delivery items can be returned; zero means no limit
+		TopUpWindow   time.Duration // how long a replacement order waits for the price difference of an exchange
+		CheckInterval time.Duration
+	}
+	FlashSale struct {
+		CheckInterval time.Duration // how often campaigns are started and ended
+	}
+	Loyalty struct {
+		RupiahPerPoint float64 // amount paid to earn one point; zero disables earning
+		PointValue     float64 // discount in rupiah per redeemed point; zero disables redeeming
+		ExpiryMonths   int     // zero means points never expire
+		CheckInterval  time.Duration
+	}
+	GiftCard struct {
+		MinAmount float64
+		MaxAmount float64       // zero means no limit
+		Validity  time.Duration // how long a card can be redeemed after it is activated
+	}
+	Reconciliation struct {
+		Interval time.Duration // zero disables the scheduled status check
+	}
+	Search struct {
+		Engine            string // postgres, meilisearch or memory
+		MeilisearchURL    string
+		MeilisearchAPIKey string
+		MeilisearchIndex  string
+		SyncInterval      time.Duration // how often catalog changes are sent to an external index
+	}
 	SMTP struct {
 		Host     string
 		Port     int
@@ -54,9 +112,35 @@ type Config struct {
 	Storage struct {
 		Type      string // local, s3, etc.
 		LocalPath string
+		BaseURL   string // public URL stored files are served from, a path is served by the API itself
 		S3Bucket  string
 		S3Region  string
 	}
+	Images struct {
+		MaxUploadSize int64 // in bytes
+		MinWidth      int
+		MinHeight     int
+		MaxWidth      int
+		MaxHeight     int
+		MaxPixels     int    // width times height, bounds the memory one decoded image takes
+		MaxConcurrent int    // images processed at once, uploads beyond it wait
+		Quality       int    // JPEG and WebP quality, 1-100
+		CWebPPath     string // cwebp binary used for WebP renditions, WebP is skipped when it is not installed
+	}
+	COD struct {
+		Enabled       bool
+		MaxOrderValue float64
+		Cities        []string // empty allows every city
+		Couriers      []string
+	}
+	Shop struct {
+		Name       string
+		Phone      string
+		Address    string
+		City       string
+		Province   string
+		PostalCode string
+	}
 }
 
 // NewConfig creates a new Config instance
@@ -95,10 +179,67 @@ func NewConfig() *Config {
 
 	// Midtrans configuration
 	cfg.Midtrans.ServerKey = getEnvAsString("MIDTRANS_SERVER_KEY", "")
-	cfg.Midtrans.ClientKey = getEnvAsString("MIDTRANS_CLIENT_KEY", "")
 	cfg.Midtrans.Environment = getEnvAsString("MIDTRANS_ENVIRONMENT", "sandbox")
 	cfg.Midtrans.PaymentWebhook = getEnvAsString("MIDTRANS_PAYMENT_WEBHOOK", "/api/v1/payments/webhook")
 
+	// Xendit configuration
+	cfg.Xendit.SecretKey = getEnvAsString("XENDIT_SECRET_KEY", "")
+	cfg.Xendit.CallbackToken = getEnvAsString("XENDIT_CALLBACK_TOKEN", "")
+	cfg.Xendit.URL = getEnvAsString("XENDIT_URL", "https://api.xendit.co")
+
+	// Fake payment gateway configuration (tests and local development only)
+	cfg.FakeGateway.Enabled = getEnvAsString("FAKE_GATEWAY_ENABLED", "false") == "true"
+	cfg.FakeGateway.Secret = getEnvAsString("FAKE_GATEWAY_SECRET", "fake-gateway-secret")
+	cfg.FakeGateway.WebhookURL = getEnvAsString("FAKE_GATEWAY_WEBHOOK_URL", "http://localhost:8080/api/v1/payments/webhook/fake")
+	cfg.FakeGateway.AutoSettle = getEnvAsDuration("FAKE_GATEWAY_AUTO_SETTLE", 0)
+
+	// Payment gateway per payment method; empty uses the default gateway
+	cfg.PaymentGateway.Default = getEnvAsString("PAYMENT_GATEWAY_DEFAULT", "midtrans")
+	cfg.PaymentGateway.CreditCard = getEnvAsString("PAYMENT_GATEWAY_CREDIT_CARD", "")
+	cfg.PaymentGateway.BankTransfer = getEnvAsString("PAYMENT_GATEWAY_BANK_TRANSFER", "")
+	cfg.PaymentGateway.EWallet = getEnvAsString("PAYMENT_GATEWAY_E_WALLET", "")
+	cfg.PaymentGateway.QRIS = getEnvAsString("PAYMENT_GATEWAY_QRIS", "")
+	cfg.PaymentGateway.CallbackURL = getEnvAsString("PAYMENT_CALLBACK_URL", "")
+
+	// Payment windows per payment method before unpaid orders are cancelled
+	cfg.PaymentExpiry.CreditCard = getEnvAsDuration("PAYMENT_EXPIRY_CREDIT_CARD", 24*time.Hour)
+	cfg.PaymentExpiry.BankTransfer = getEnvAsDuration("PAYMENT_EXPIRY_BANK_TRANSFER", 24*time.Hour)
+	cfg.PaymentExpiry.EWallet = getEnvAsDuration("PAYMENT_EXPIRY_E_WALLET", 15*time.Minute)
+	cfg.PaymentExpiry.QRIS = getEnvAsDuration("PAYMENT_EXPIRY_QRIS", 15*time.Minute)
+	cfg.PaymentExpiry.CheckInterval = getEnvAsDuration("PAYMENT_EXPIRY_CHECK_INTERVAL", time.Minute)
+
+	// Shipping configuration
+	cfg.Shipping.FlatRate = float64(getEnvAsInt("SHIPPING_FLAT_RATE", 20000))
+
+	// Returns configuration
+	cfg.Returns.Window = getEnvAsDuration("RETURN_WINDOW", 7*24*time.Hour)
+	cfg.Returns.TopUpWindow = getEnvAsDuration("EXCHANGE_TOP_UP_WINDOW", 48*time.Hour)
+	cfg.Returns.CheckInterval = getEnvAsDuration("RETURN_CHECK_INTERVAL", 15*time.Minute)
+
+	// Flash sale configuration
+	cfg.FlashSale.CheckInterval = getEnvAsDuration("FLASH_SALE_CHECK_INTERVAL", time.Minute)
+
+	// Loyalty points configuration
+	cfg.Loyalty.RupiahPerPoint = float64(getEnvAsInt("LOYALTY_RUPIAH_PER_POINT", 1000))
+	cfg.Loyalty.PointValue = float64(getEnvAsInt("LOYALTY_POINT_VALUE", 1))
+	cfg.Loyalty.ExpiryMonths = getEnvAsInt("LOYALTY_EXPIRY_MONTHS", 12)
+	cfg.Loyalty.CheckInterval = getEnvAsDuration("LOYALTY_CHECK_INTERVAL", 15*time.Minute)
+
+	// Gift card configuration
+	cfg.GiftCard.MinAmount = float64(getEnvAsInt("GIFT_CARD_MIN_AMOUNT", 50000))
+	cfg.GiftCard.MaxAmount = float64(getEnvAsInt("GIFT_CARD_MAX_AMOUNT", 5000000))
+	cfg.GiftCard.Validity = getEnvAsDuration("GIFT_CARD_VALIDITY", 365*24*time.Hour)
+
+	// Payment reconciliation configuration
+	cfg.Reconciliation.Interval = getEnvAsDuration("RECONCILIATION_INTERVAL", 24*time.Hour)
+
+	// Product search configuration
+	cfg.Search.Engine = getEnvAsString("SEARCH_ENGINE", "postgres")
+	cfg.Search.MeilisearchURL = getEnvAsString("MEILISEARCH_URL", "http://localhost:7700")
+	cfg.Search.MeilisearchAPIKey = getEnvAsString("MEILISEARCH_API_KEY", "")
+	cfg.Search.MeilisearchIndex = getEnvAsString("MEILISEARCH_INDEX", "products")
+	cfg.Search.SyncInterval = getEnvAsDuration("SEARCH_SYNC_INTERVAL", 30*time.Second)
+
 	// SMTP configuration
 	cfg.SMTP.Host = getEnvAsString("SMTP_HOST", "")
 	cfg.SMTP.Port = getEnvAsInt("SMTP_PORT", 587)
@@ -109,9 +250,35 @@ func NewConfig() *Config {
 	// Storage configuration
 	cfg.Storage.Type = getEnvAsString("STORAGE_TYPE", "local")
 	cfg.Storage.LocalPath = getEnvAsString("STORAGE_LOCAL_PATH", "./uploads")
+	cfg.Storage.BaseURL = getEnvAsString("STORAGE_BASE_URL", "/uploads")
 	cfg.Storage.S3Bucket = getEnvAsString("STORAGE_S3_BUCKET", "")
 	cfg.Storage.S3Region = getEnvAsString("STORAGE_S3_REGION", "")
 
+	// Image processing configuration
+	cfg.Images.MaxUploadSize = int64(getEnvAsInt("IMAGE_MAX_UPLOAD_SIZE", 10<<20))
+	cfg.Images.MinWidth = getEnvAsInt("IMAGE_MIN_WIDTH", 200)
+	cfg.Images.MinHeight = getEnvAsInt("IMAGE_MIN_HEIGHT", 200)
+	cfg.Images.MaxWidth = getEnvAsInt("IMAGE_MAX_WIDTH", 8000)
+	cfg.Images.MaxHeight = getEnvAsInt("IMAGE_MAX_HEIGHT", 8000)
+	cfg.Images.MaxPixels = getEnvAsInt("IMAGE_MAX_PIXELS", 25_000_000)
+	cfg.Images.MaxConcurrent = getEnvAsInt("IMAGE_MAX_CONCURRENT", 2)
+	cfg.Images.Quality = getEnvAsInt("IMAGE_QUALITY", 82)
+	cfg.Images.CWebPPath = getEnvAsString("IMAGE_CWEBP_PATH", "cwebp")
+
+	// Cash-on-delivery configuration
+	cfg.COD.Enabled = getEnvAsString("COD_ENABLED", "true") == "true"
+	cfg.COD.MaxOrderValue = float64(getEnvAsInt("COD_MAX_ORDER_VALUE", 2000000))
+	cfg.COD.Cities = getEnvAsSlice("COD_CITIES", nil)
+	cfg.COD.Couriers = getEnvAsSlice("COD_COURIERS", []string{"jne", "jnt", "sicepat"})
+
+	// Shop configuration (sender details printed on shipping labels)
+	cfg.Shop.Name = getEnvAsString("SHOP_NAME", "Fashion Shop")
+	cfg.Shop.Phone = getEnvAsString("SHOP_PHONE", "")
+	cfg.Shop.Address = getEnvAsString("SHOP_ADDRESS", "")
+	cfg.Shop.City = getEnvAsString("SHOP_CITY", "")
+	cfg.Shop.Province = getEnvAsString("SHOP_PROVINCE", "")
+	cfg.Shop.PostalCode = getEnvAsString("SHOP_POSTAL_CODE", "")
+
 	return cfg
 }
 
@@ -140,3 +307,16 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	if value, exists := os.LookupEnv(key); exists {
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	return defaultValue
+}
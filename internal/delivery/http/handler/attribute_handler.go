@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttributeHandler handles product attribute schema HTTP requests
+type AttributeHandler struct {
+	attributeUseCase usecase.AttributeUseCase
+}
+
+// NewAttributeHandler creates a new AttributeHandler instance
+func NewAttributeHandler(attributeUseCase usecase.AttributeUseCase) *AttributeHandler {
+	return &AttributeHandler{
+		attributeUseCase: attributeUseCase,
+	}
+}
+
+// GetCategoryAttributes handles getting the attributes of a category, including inherited ones
+func (h *AttributeHandler) GetCategoryAttributes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	attributes, err := h.attributeUseCase.GetCategoryAttributes(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attributes": attributes})
+}
+
+// CreateAttribute handles defining an attribute on a category (admin only)
+func (h *AttributeHandler) CreateAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var attribute entity.AttributeDefinition
+	if err := c.ShouldBindJSON(&attribute); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	created, err := h.attributeUseCase.CreateAttribute(c, uint(id), &attribute)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"attribute": created})
+}
+
+// UpdateAttribute handles updating an attribute definition (admin only)
+func (h *AttributeHandler) UpdateAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attribute ID"})
+		return
+	}
+
+	var attribute entity.AttributeDefinition
+	if err := c.ShouldBindJSON(&attribute); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	updated, err := h.attributeUseCase.UpdateAttribute(c, uint(id), &attribute)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attribute": updated})
+}
+
+// DeleteAttribute handles deleting an attribute definition (admin only)
+func (h *AttributeHandler) DeleteAttribute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attribute ID"})
+		return
+	}
+
+	if err := h.attributeUseCase.DeleteAttribute(c, uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attribute deleted successfully"})
+}
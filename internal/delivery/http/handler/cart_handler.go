@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartHandler handles shopping cart HTTP requests
+type CartHandler struct {
+	cartUseCase usecase.CartUseCase
+}
+
+// NewCartHandler creates a new CartHandler instance
+func NewCartHandler(cartUseCase usecase.CartUseCase) *CartHandler {
+	return &CartHandler{
+		cartUseCase: cartUseCase,
+	}
+}
+
+// GetCart handles getting the current user's cart with its totals
+func (h *CartHandler) GetCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	cart, err := h.cartUseCase.GetCart(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalItems, totalAmount, err := h.cartUseCase.GetCartTotals(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cart":         cart,
+		"total_items":  totalItems,
+		"total_amount": totalAmount,
+	})
+}
+
+// AddToCart handles adding a product variant to the current user's cart
+func (h *CartHandler) AddToCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		ProductID uint `json:"product_id" binding:"required"`
+		VariantID uint `json:"variant_id" binding:"required"`
+		Quantity  int  `json:"quantity" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.cartUseCase.AddToCart(c, userID, request.ProductID, request.VariantID, request.Quantity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item added to cart successfully"})
+}
+
+// UpdateCartItem handles changing the quantity of an item in the current user's cart
+func (h *CartHandler) UpdateCartItem(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	var request struct {
+		Quantity int `json:"quantity" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.cartUseCase.UpdateCartItem(c, userID, uint(id), request.Quantity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cart item updated successfully"})
+}
+
+// RemoveFromCart handles removing an item from the current user's cart
+func (h *CartHandler) RemoveFromCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cart item ID"})
+		return
+	}
+
+	if err := h.cartUseCase.RemoveFromCart(c, userID, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removed from cart successfully"})
+}
+
+// ClearCart handles emptying the current user's cart
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if err := h.cartUseCase.ClearCart(c, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cart cleared successfully"})
+}
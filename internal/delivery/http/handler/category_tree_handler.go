@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryTreeHandler handles category hierarchy HTTP requests
+type CategoryTreeHandler struct {
+	categoryTreeUseCase usecase.CategoryTreeUseCase
+}
+
+// NewCategoryTreeHandler creates a new CategoryTreeHandler instance
+func NewCategoryTreeHandler(categoryTreeUseCase usecase.CategoryTreeUseCase) *CategoryTreeHandler {
+	return &CategoryTreeHandler{
+		categoryTreeUseCase: categoryTreeUseCase,
+	}
+}
+
+// GetTree handles getting the full nested category tree
+func (h *CategoryTreeHandler) GetTree(c *gin.Context) {
+	categories, err := h.categoryTreeUseCase.GetTree(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// GetSubtree handles getting a category with its nested subcategories
+func (h *CategoryTreeHandler) GetSubtree(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := h.categoryTreeUseCase.GetSubtree(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// GetBreadcrumbs handles getting the breadcrumb path of a category
+func (h *CategoryTreeHandler) GetBreadcrumbs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	breadcrumbs, err := h.categoryTreeUseCase.GetBreadcrumbs(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"breadcrumbs": breadcrumbs})
+}
+
+// GetProductBreadcrumbs handles getting the breadcrumb path of a product's category
+func (h *CategoryTreeHandler) GetProductBreadcrumbs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	breadcrumbs, err := h.categoryTreeUseCase.GetProductBreadcrumbs(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"breadcrumbs": breadcrumbs})
+}
+
+// MoveCategory handles moving a category under another parent or reordering it among its siblings (admin only)
+func (h *CategoryTreeHandler) MoveCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var request struct {
+		ParentID *uint `json:"parent_id"` // null moves the category to the top level
+		Position int   `json:"position" binding:"min=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	category, err := h.categoryTreeUseCase.MoveCategory(c, uint(id), request.ParentID, request.Position)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// DeleteCategory handles deleting a category using the restrict, reparent
+// or cascade rule for its subcategories and products (admin only)
+func (h *CategoryTreeHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	rule := entity.CategoryDeleteRule(c.Query("rule"))
+	if err := h.categoryTreeUseCase.DeleteCategory(c, uint(id), rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRemittanceReportSize caps the size of an uploaded remittance report
+const maxRemittanceReportSize = 10 << 20
+
+// CODHandler handles cash-on-delivery HTTP requests
+type CODHandler struct {
+	codUseCase usecase.CODUseCase
+}
+
+// NewCODHandler creates a new CODHandler instance
+func NewCODHandler(codUseCase usecase.CODUseCase) *CODHandler {
+	return &CODHandler{
+		codUseCase: codUseCase,
+	}
+}
+
+// CheckEligibility handles checking whether an order can be paid on delivery
+func (h *CODHandler) CheckEligibility(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	if err := h.codUseCase.CheckEligibility(c, uint(orderID), userID); err != nil {
+		c.JSON(http.StatusOK, gin.H{"eligible": false, "reason": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"eligible": true})
+}
+
+// PlaceCODPayment handles choosing cash on delivery for an order
+func (h *CODHandler) PlaceCODPayment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		OrderID uint `json:"order_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	payment, err := h.codUseCase.PlaceCODPayment(c, request.OrderID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"payment": payment})
+}
+
+// ConfirmCODPayment handles confirming delivery and cash remittance of a payment (admin only)
+func (h *CODHandler) ConfirmCODPayment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	var request struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.codUseCase.ConfirmCODPayment(c, uint(id), request.Amount); err != nil {
+		c.JSON(orderUpdateStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment confirmed successfully"})
+}
+
+// MarkCODUndelivered handles recording a failed cash-on-delivery delivery (admin only)
+func (h *CODHandler) MarkCODUndelivered(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.codUseCase.MarkCODUndelivered(c, uint(id), request.Reason); err != nil {
+		c.JSON(orderUpdateStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order marked as undelivered"})
+}
+
+// ImportRemittanceReport handles uploading a courier remittance CSV (admin only)
+func (h *CODHandler) ImportRemittanceReport(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	courier := c.PostForm("courier")
+	if courier == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Courier is required"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Remittance report file is required"})
+		return
+	}
+
+	if file.Size > maxRemittanceReportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Remittance report is too large"})
+		return
+	}
+
+	report, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read remittance report"})
+		return
+	}
+	defer report.Close()
+
+	batch, err := h.codUseCase.ImportRemittanceReport(c, courier, file.Filename, report, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"batch": batch})
+}
+
+// ListRemittanceBatches handles listing imported remittance reports (admin only)
+func (h *CODHandler) ListRemittanceBatches(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		batches, cursors, err := h.codUseCase.ListRemittanceBatchesByCursor(c, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"batches": batches, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	batches, count, err := h.codUseCase.ListRemittanceBatches(c, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batches": batches,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetRemittanceBatch handles getting an imported remittance report with its lines (admin only)
+func (h *CODHandler) GetRemittanceBatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	batch, err := h.codUseCase.GetRemittanceBatch(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch": batch})
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeHandler handles exchange HTTP requests
+type ExchangeHandler struct {
+	exchangeUseCase usecase.ExchangeUseCase
+}
+
+// NewExchangeHandler creates a new ExchangeHandler instance
+func NewExchangeHandler(exchangeUseCase usecase.ExchangeUseCase) *ExchangeHandler {
+	return &ExchangeHandler{
+		exchangeUseCase: exchangeUseCase,
+	}
+}
+
+// CreateExchange handles requesting a size or color exchange for items of a delivered order
+func (h *ExchangeHandler) CreateExchange(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		OrderID uint `json:"order_id" binding:"required"`
+		Items   []struct {
+			OrderItemID  uint `json:"order_item_id" binding:"required"`
+			NewVariantID uint `json:"new_variant_id" binding:"required"`
+			Quantity     int  `json:"quantity" binding:"required,gt=0"`
+		} `json:"items" binding:"required,min=1,dive"`
+		Reason      string `json:"reason" binding:"required,oneof=wrong_size too_small too_large defective not_as_described wrong_item changed_mind other"`
+		Description string `json:"description"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	items := make([]usecase.ExchangeItemRequest, 0, len(request.Items))
+	for _, item := range request.Items {
+		items = append(items, usecase.ExchangeItemRequest{
+			OrderItemID:  item.OrderItemID,
+			NewVariantID: item.NewVariantID,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	exchange, err := h.exchangeUseCase.CreateExchange(c, userID, request.OrderID, items, entity.ReturnReason(request.Reason), request.Description)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"exchange": exchange})
+}
+
+// GetUserExchanges handles listing the current user's exchange requests
+func (h *ExchangeHandler) GetUserExchanges(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	exchanges, count, err := h.exchangeUseCase.GetUserExchanges(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exchanges": exchanges,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetExchangeByID handles getting one of the current user's exchange requests
+func (h *ExchangeHandler) GetExchangeByID(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	exchange, err := h.exchangeUseCase.GetExchangeByID(c, uint(id), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exchange": exchange})
+}
+
+// CancelExchange handles withdrawing an exchange request
+func (h *ExchangeHandler) CancelExchange(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	if err := h.exchangeUseCase.CancelExchange(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange cancelled successfully"})
+}
+
+// SubmitExchangeShipment handles submitting the tracking number of the original items sent back
+func (h *ExchangeHandler) SubmitExchangeShipment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	var request struct {
+		Courier        string `json:"courier" binding:"required"`
+		TrackingNumber string `json:"tracking_number" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.exchangeUseCase.SubmitExchangeShipment(c, uint(id), userID, request.Courier, request.TrackingNumber); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange shipment submitted successfully"})
+}
+
+// ListExchanges handles listing all exchange requests (admin only)
+func (h *ExchangeHandler) ListExchanges(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if reason := c.Query("reason"); reason != "" {
+		filter["reason"] = reason
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		exchanges, cursors, err := h.exchangeUseCase.ListExchangesByCursor(c, filter, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"exchanges": exchanges, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	exchanges, count, err := h.exchangeUseCase.ListExchanges(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exchanges": exchanges,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetExchange handles getting any exchange request (admin only)
+func (h *ExchangeHandler) GetExchange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	exchange, err := h.exchangeUseCase.GetExchange(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exchange": exchange})
+}
+
+// ApproveExchange handles approving an exchange request and creating its replacement order (admin only)
+func (h *ExchangeHandler) ApproveExchange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	var request struct {
+		Note string `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	order, err := h.exchangeUseCase.ApproveExchange(c, uint(id), request.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange approved successfully", "replacement_order": order})
+}
+
+// RejectExchange handles rejecting an exchange request (admin only)
+func (h *ExchangeHandler) RejectExchange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	var request struct {
+		Note string `json:"note" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.exchangeUseCase.RejectExchange(c, uint(id), request.Note); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rejected successfully"})
+}
+
+// ReceiveExchange handles receiving the original items, restocking them and refunding any price difference (admin only)
+func (h *ExchangeHandler) ReceiveExchange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	if err := h.exchangeUseCase.ReceiveExchange(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange received successfully"})
+}
+
+// CompleteExchange handles retrying the completion of a received exchange (admin only)
+func (h *ExchangeHandler) CompleteExchange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange ID"})
+		return
+	}
+
+	if err := h.exchangeUseCase.CompleteExchange(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange completed successfully"})
+}
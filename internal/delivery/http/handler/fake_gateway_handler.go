@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/infrastructure/gateway"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FakeGatewayHandler handles HTTP requests that drive the fake payment gateway
+type FakeGatewayHandler struct {
+	fakeGateway *gateway.FakeGateway
+}
+
+// NewFakeGatewayHandler creates a new FakeGatewayHandler instance
+func NewFakeGatewayHandler(fakeGateway *gateway.FakeGateway) *FakeGatewayHandler {
+	return &FakeGatewayHandler{
+		fakeGateway: fakeGateway,
+	}
+}
+
+// Simulate handles moving a fake transaction to a new status and sending its webhook (admin only)
+func (h *FakeGatewayHandler) Simulate(c *gin.Context) {
+	var request struct {
+		OrderID string `json:"order_id" binding:"required"`
+		Status  string `json:"status" binding:"required,oneof=challenge paid failed cancelled expired refunded"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.fakeGateway.Simulate(c, request.OrderID, entity.PaymentStatus(request.Status)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification sent"})
+}
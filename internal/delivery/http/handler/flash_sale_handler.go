@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlashSaleHandler handles flash sale HTTP requests
+type FlashSaleHandler struct {
+	flashSaleUseCase usecase.FlashSaleUseCase
+}
+
+// NewFlashSaleHandler creates a new FlashSaleHandler instance
+func NewFlashSaleHandler(flashSaleUseCase usecase.FlashSaleUseCase) *FlashSaleHandler {
+	return &FlashSaleHandler{
+		flashSaleUseCase: flashSaleUseCase,
+	}
+}
+
+// flashSaleRequest is the body of the admin create and update flash sale requests
+type flashSaleRequest struct {
+	Name        string    `json:"name" binding:"required"`
+	Description string    `json:"description"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required"`
+	Items       []struct {
+		VariantID    uint    `json:"variant_id" binding:"required"`
+		SalePrice    float64 `json:"sale_price" binding:"required,gt=0"`
+		Quota        int     `json:"quota" binding:"required,gt=0"`
+		PerUserLimit int     `json:"per_user_limit" binding:"gte=0"`
+	} `json:"items" binding:"required,min=1,dive"`
+}
+
+// toEntity builds the flash sale and item requests described by the request
+func (r *flashSaleRequest) toEntity() (*entity.FlashSale, []usecase.FlashSaleItemRequest) {
+	sale := &entity.FlashSale{
+		Name:        r.Name,
+		Description: r.Description,
+		StartsAt:    r.StartsAt,
+		EndsAt:      r.EndsAt,
+	}
+
+	items := make([]usecase.FlashSaleItemRequest, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, usecase.FlashSaleItemRequest{
+			VariantID:    item.VariantID,
+			SalePrice:    item.SalePrice,
+			Quota:        item.Quota,
+			PerUserLimit: item.PerUserLimit,
+		})
+	}
+
+	return sale, items
+}
+
+// GetCurrentFlashSales handles listing running and upcoming flash sales
+func (h *FlashSaleHandler) GetCurrentFlashSales(c *gin.Context) {
+	sales, err := h.flashSaleUseCase.GetCurrentFlashSales(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flash_sales": sales})
+}
+
+// GetFlashSale handles getting a flash sale with its items
+func (h *FlashSaleHandler) GetFlashSale(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flash sale ID"})
+		return
+	}
+
+	sale, err := h.flashSaleUseCase.GetFlashSale(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flash_sale": sale})
+}
+
+// CreateFlashSale handles scheduling a flash sale (admin only)
+func (h *FlashSaleHandler) CreateFlashSale(c *gin.Context) {
+	var request flashSaleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	sale, items := request.toEntity()
+	if err := h.flashSaleUseCase.CreateFlashSale(c, sale, items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"flash_sale": sale})
+}
+
+// UpdateFlashSale handles changing a flash sale that has not started (admin only)
+func (h *FlashSaleHandler) UpdateFlashSale(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flash sale ID"})
+		return
+	}
+
+	var request flashSaleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	sale, items := request.toEntity()
+	sale.ID = uint(id)
+	if err := h.flashSaleUseCase.UpdateFlashSale(c, sale, items); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flash_sale": sale})
+}
+
+// CancelFlashSale handles stopping a flash sale (admin only)
+func (h *FlashSaleHandler) CancelFlashSale(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid flash sale ID"})
+		return
+	}
+
+	if err := h.flashSaleUseCase.CancelFlashSale(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Flash sale cancelled successfully"})
+}
+
+// ListFlashSales handles listing all flash sales (admin only)
+func (h *FlashSaleHandler) ListFlashSales(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	sales, count, err := h.flashSaleUseCase.ListFlashSales(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flash_sales": sales,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
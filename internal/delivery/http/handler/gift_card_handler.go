@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GiftCardHandler handles gift card HTTP requests
+type GiftCardHandler struct {
+	giftCardUseCase usecase.GiftCardUseCase
+}
+
+// NewGiftCardHandler creates a new GiftCardHandler instance
+func NewGiftCardHandler(giftCardUseCase usecase.GiftCardUseCase) *GiftCardHandler {
+	return &GiftCardHandler{
+		giftCardUseCase: giftCardUseCase,
+	}
+}
+
+// PurchaseGiftCard handles buying a gift card; the returned order is paid like any other
+func (h *GiftCardHandler) PurchaseGiftCard(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Amount         float64 `json:"amount" binding:"required,gt=0"`
+		RecipientName  string  `json:"recipient_name" binding:"max=100"`
+		RecipientEmail string  `json:"recipient_email" binding:"omitempty,email"`
+		Message        string  `json:"message" binding:"max=500"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	card, order, err := h.giftCardUseCase.PurchaseGiftCard(c, userID, usecase.GiftCardPurchase{
+		Amount:         request.Amount,
+		RecipientName:  request.RecipientName,
+		RecipientEmail: request.RecipientEmail,
+		Message:        request.Message,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"gift_card": card, "order": order})
+}
+
+// GetUserGiftCards handles listing the gift cards bought by the current user
+func (h *GiftCardHandler) GetUserGiftCards(c *gin.Context) {
+	userID := c.GetUint("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	cards, count, err := h.giftCardUseCase.GetUserGiftCards(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"gift_cards": cards,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RedeemGiftCard handles redeeming a gift card code into the current user's wallet
+func (h *GiftCardHandler) RedeemGiftCard(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	wallet, err := h.giftCardUseCase.RedeemGiftCard(c, userID, request.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wallet": wallet})
+}
+
+// IssueGiftCards handles issuing gift cards without a purchase (admin only)
+func (h *GiftCardHandler) IssueGiftCards(c *gin.Context) {
+	var request struct {
+		Amount    float64    `json:"amount" binding:"required,gt=0"`
+		Quantity  int        `json:"quantity" binding:"required,gt=0"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	cards, err := h.giftCardUseCase.IssueGiftCards(c, request.Amount, request.Quantity, request.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"gift_cards": cards})
+}
+
+// ListGiftCards handles listing gift cards (admin only)
+func (h *GiftCardHandler) ListGiftCards(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if code := c.Query("code"); code != "" {
+		filter["code"] = code
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		cards, cursors, err := h.giftCardUseCase.ListGiftCardsByCursor(c, filter, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"gift_cards": cards, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	cards, count, err := h.giftCardUseCase.ListGiftCards(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"gift_cards": cards,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// DisableGiftCard handles disabling an unredeemed gift card (admin only)
+func (h *GiftCardHandler) DisableGiftCard(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gift card ID"})
+		return
+	}
+
+	if err := h.giftCardUseCase.DisableGiftCard(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Gift card disabled successfully"})
+}
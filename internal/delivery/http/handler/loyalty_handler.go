@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoyaltyHandler handles loyalty points HTTP requests
+type LoyaltyHandler struct {
+	loyaltyUseCase usecase.LoyaltyUseCase
+}
+
+// NewLoyaltyHandler creates a new LoyaltyHandler instance
+func NewLoyaltyHandler(loyaltyUseCase usecase.LoyaltyUseCase) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyUseCase: loyaltyUseCase,
+	}
+}
+
+// GetPoints handles getting the current user's points balance and ledger
+func (h *LoyaltyHandler) GetPoints(c *gin.Context) {
+	h.respondWithLedger(c, c.GetUint("userID"))
+}
+
+// RedeemForOrder handles spending points on an unpaid order at checkout
+func (h *LoyaltyHandler) RedeemForOrder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var request struct {
+		Points int `json:"points" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	order, err := h.loyaltyUseCase.RedeemForOrder(c, userID, uint(id), request.Points)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// GetUserPoints handles getting a user's points balance and ledger (admin only)
+func (h *LoyaltyHandler) GetUserPoints(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	h.respondWithLedger(c, uint(id))
+}
+
+// ListCategoryMultipliers handles listing the category points multipliers (admin only)
+func (h *LoyaltyHandler) ListCategoryMultipliers(c *gin.Context) {
+	multipliers, err := h.loyaltyUseCase.ListCategoryMultipliers(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"multipliers": multipliers})
+}
+
+// SetCategoryMultiplier handles setting the points multiplier of a category (admin only)
+func (h *LoyaltyHandler) SetCategoryMultiplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("category_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var request struct {
+		Multiplier float64 `json:"multiplier" binding:"gte=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	multiplier, err := h.loyaltyUseCase.SetCategoryMultiplier(c, uint(id), request.Multiplier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"multiplier": multiplier})
+}
+
+// DeleteCategoryMultiplier handles removing the points multiplier of a category (admin only)
+func (h *LoyaltyHandler) DeleteCategoryMultiplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("category_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	if err := h.loyaltyUseCase.DeleteCategoryMultiplier(c, uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Multiplier deleted successfully"})
+}
+
+// respondWithLedger writes a user's points balance and a page of their ledger
+func (h *LoyaltyHandler) respondWithLedger(c *gin.Context, userID uint) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	account, err := h.loyaltyUseCase.GetAccount(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactions, count, err := h.loyaltyUseCase.GetLedger(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"account":      account,
+		"transactions": transactions,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
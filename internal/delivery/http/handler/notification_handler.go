@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles notification HTTP requests
+type NotificationHandler struct {
+	notificationUseCase usecase.NotificationUseCase
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance
+func NewNotificationHandler(notificationUseCase usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// GetNotifications handles listing the current user's notifications, newest first
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, limit := pagination(c)
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		notifications, cursors, err := h.notificationUseCase.GetNotificationsByCursor(c, userID, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"notifications": notifications, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	notifications, count, err := h.notificationUseCase.GetNotifications(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetUnreadNotifications handles listing the current user's unread notifications
+func (h *NotificationHandler) GetUnreadNotifications(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	notifications, err := h.notificationUseCase.GetUnreadNotifications(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "count": len(notifications)})
+}
+
+// MarkAsRead handles marking one of the current user's notifications as read
+func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationUseCase.MarkAsRead(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllAsRead handles marking every notification of the current user as read
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if err := h.notificationUseCase.MarkAllAsRead(c, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
+
+// DeleteNotification handles deleting one of the current user's notifications
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationUseCase.DeleteNotification(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted successfully"})
+}
@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler handles order and shipping HTTP requests
+type OrderHandler struct {
+	orderUseCase    usecase.OrderUseCase
+	paymentUseCase  usecase.PaymentUseCase
+	shippingUseCase usecase.ShippingUseCase
+}
+
+// NewOrderHandler creates a new OrderHandler instance
+func NewOrderHandler(orderUseCase usecase.OrderUseCase, paymentUseCase usecase.PaymentUseCase, shippingUseCase usecase.ShippingUseCase) *OrderHandler {
+	return &OrderHandler{
+		orderUseCase:    orderUseCase,
+		paymentUseCase:  paymentUseCase,
+		shippingUseCase: shippingUseCase,
+	}
+}
+
+// CreateOrder handles placing an order for the current user's cart. Orders
+// paid online get their payment started right away; when that fails the
+// order stays pending and can be paid through the payments endpoint.
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		AddressID      uint    `json:"address_id" binding:"required"`
+		PaymentMethod  string  `json:"payment_method" binding:"required,oneof=credit_card bank_transfer e_wallet qris cod wallet"`
+		Channel        string  `json:"channel" binding:"omitempty,oneof=bca bni bri permata gopay shopeepay qris"`
+		ShippingMethod string  `json:"shipping_method" binding:"required"`
+		Notes          string  `json:"notes"`
+		WalletAmount   float64 `json:"wallet_amount" binding:"min=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	method := entity.PaymentMethod(request.PaymentMethod)
+	order, err := h.orderUseCase.CreateOrder(c, userID, request.AddressID, method, request.ShippingMethod, request.Notes, request.WalletAmount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{"message": "Order created successfully", "order": order}
+	if method != entity.PaymentMethodCOD && order.Status == entity.OrderStatusPending {
+		payment, err := h.paymentUseCase.ProcessPayment(c, order.ID, method, request.Channel)
+		if err != nil {
+			response["payment_error"] = err.Error()
+		} else {
+			response["payment"] = payment
+		}
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetUserOrders handles listing the current user's orders
+func (h *OrderHandler) GetUserOrders(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, limit := pagination(c)
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		orders, cursors, err := h.orderUseCase.GetUserOrdersByCursor(c, userID, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"orders": orders, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	orders, count, err := h.orderUseCase.GetUserOrders(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetOrderByID handles getting one of the current user's orders by ID
+func (h *OrderHandler) GetOrderByID(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := h.orderUseCase.GetOrderByID(c, uint(id), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// GetOrderByNumber handles getting one of the current user's orders by order number
+func (h *OrderHandler) GetOrderByNumber(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	order, err := h.orderUseCase.GetOrderByNumber(c, c.Param("number"), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// CancelOrder handles cancelling one of the current user's orders
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	if err := h.orderUseCase.CancelOrder(c, uint(id), userID); err != nil {
+		c.JSON(orderUpdateStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order cancelled successfully"})
+}
+
+// GetAllOrders handles listing every order, optionally of one status or user (admin only)
+func (h *OrderHandler) GetAllOrders(c *gin.Context) {
+	page, limit := pagination(c)
+
+	filter := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter["user_id"] = uint(id)
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		orders, cursors, err := h.orderUseCase.GetAllOrdersByCursor(c, filter, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"orders": orders, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	orders, count, err := h.orderUseCase.GetAllOrders(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// UpdateOrderStatus handles moving an order to a new status (admin only)
+func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
+	adminID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var request struct {
+		Status string `json:"status" binding:"required,oneof=pending processing shipped delivered cancelled refunded"`
+		Note   string `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.orderUseCase.UpdateOrderStatus(c, uint(id), entity.OrderStatus(request.Status), adminID, request.Note); err != nil {
+		c.JSON(orderUpdateStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order status updated successfully"})
+}
+
+// UpdateShippingInfo handles setting the tracking number of an order (admin only)
+func (h *OrderHandler) UpdateShippingInfo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var request struct {
+		TrackingNumber string `json:"tracking_number" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.orderUseCase.UpdateShippingInfo(c, uint(id), request.TrackingNumber); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shipping info updated successfully"})
+}
+
+// GetSalesReport handles getting the orders and revenue of a period given as
+// start_date and end_date in YYYY-MM-DD, both inclusive (admin only)
+func (h *OrderHandler) GetSalesReport(c *gin.Context) {
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date, use YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date, use YYYY-MM-DD"})
+		return
+	}
+
+	orders, total, err := h.orderUseCase.GetSalesReport(c, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders":        orders,
+		"total_orders":  len(orders),
+		"total_revenue": total,
+	})
+}
+
+// GetProvinces handles listing the provinces shipping is available to
+func (h *OrderHandler) GetProvinces(c *gin.Context) {
+	provinces, err := h.shippingUseCase.GetProvinces(c)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"provinces": provinces})
+}
+
+// GetCities handles listing the cities of a province given as province_id
+func (h *OrderHandler) GetCities(c *gin.Context) {
+	cities, err := h.shippingUseCase.GetCities(c, c.Query("province_id"))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cities": cities})
+}
+
+// CalculateShipping handles quoting the courier services between two cities
+func (h *OrderHandler) CalculateShipping(c *gin.Context) {
+	var request struct {
+		Origin      int    `json:"origin" binding:"required"`
+		Destination int    `json:"destination" binding:"required"`
+		Weight      int    `json:"weight" binding:"required,gt=0"` // in grams
+		Courier     string `json:"courier" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	costs, err := h.shippingUseCase.CalculateShipping(c, request.Origin, request.Destination, request.Weight, request.Courier)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"costs": costs})
+}
+
+// TrackShipment handles tracking a shipment by courier and waybill number
+func (h *OrderHandler) TrackShipment(c *gin.Context) {
+	tracking, err := h.shippingUseCase.TrackShipment(c, c.Param("waybill"), c.Param("courier"))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tracking": tracking})
+}
+
+// orderUpdateStatus returns the HTTP status of a failed order update: 409 when
+// the order cannot move to the requested status, 400 otherwise
+func orderUpdateStatus(err error) int {
+	var invalid *usecase.InvalidOrderTransitionError
+	if errors.As(err, &invalid) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultCursorLimit = 10
+	maxCursorLimit     = 100
+	defaultPageLimit   = 10
+	maxPageLimit       = 100
+)
+
+// pagination parses page number pagination parameters, keeping the page at
+// least 1 and the limit between 1 and maxPageLimit
+func pagination(c *gin.Context) (page, limit int) {
+	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	return max(page, 1), min(limit, maxPageLimit)
+}
+
+// cursorPagination parses keyset pagination parameters. Requests with an
+// after or before parameter, even an empty one, are paged by cursor; the
+// others keep using page numbers and ok is false.
+func cursorPagination(c *gin.Context) (page *entity.CursorPage, ok bool, err error) {
+	after, hasAfter := c.GetQuery("after")
+	before, hasBefore := c.GetQuery("before")
+	if !hasAfter && !hasBefore {
+		return nil, false, nil
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultCursorLimit)))
+	if limit <= 0 {
+		limit = defaultCursorLimit
+	}
+	page = &entity.CursorPage{Limit: min(limit, maxCursorLimit)}
+
+	if after != "" {
+		if page.After, err = utils.DecodeCursor(after); err != nil {
+			return nil, true, err
+		}
+	}
+	if before != "" {
+		if page.Before, err = utils.DecodeCursor(before); err != nil {
+			return nil, true, err
+		}
+	}
+	return page, true, nil
+}
+
+// cursorMeta builds the meta of a response paged by cursor
+func cursorMeta(page *entity.CursorPage, cursors *entity.PageCursors) gin.H {
+	meta := gin.H{
+		"limit":       page.Limit,
+		"next_cursor": nil,
+		"prev_cursor": nil,
+	}
+	if cursors.Next != nil {
+		meta["next_cursor"] = utils.EncodeCursor(cursors.Next)
+	}
+	if cursors.Prev != nil {
+		meta["prev_cursor"] = utils.EncodeCursor(cursors.Prev)
+	}
+	return meta
+}
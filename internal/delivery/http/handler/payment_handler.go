@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler handles payment-related HTTP requests
+type PaymentHandler struct {
+	paymentUseCase usecase.PaymentUseCase
+}
+
+// NewPaymentHandler creates a new PaymentHandler instance
+func NewPaymentHandler(paymentUseCase usecase.PaymentUseCase) *PaymentHandler {
+	return &PaymentHandler{
+		paymentUseCase: paymentUseCase,
+	}
+}
+
+// ProcessPayment handles creating a payment for an order
+func (h *PaymentHandler) ProcessPayment(c *gin.Context) {
+	var request struct {
+		OrderID       uint   `json:"order_id" binding:"required"`
+		PaymentMethod string `json:"payment_method" binding:"required,oneof=credit_card bank_transfer e_wallet qris cod"`
+		Channel       string `json:"channel" binding:"omitempty,oneof=bca bni bri permata gopay shopeepay qris"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	payment, err := h.paymentUseCase.ProcessPayment(c, request.OrderID, entity.PaymentMethod(request.PaymentMethod), request.Channel)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"payment": payment})
+}
+
+// GetPaymentByID handles getting a payment by ID
+func (h *PaymentHandler) GetPaymentByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	payment, err := h.paymentUseCase.GetPaymentByID(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment": payment})
+}
+
+// GetPaymentByOrderID handles getting the payment of an order
+func (h *PaymentHandler) GetPaymentByOrderID(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	payment, err := h.paymentUseCase.GetPaymentByOrderID(c, uint(orderID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment": payment})
+}
+
+// HandleWebhook handles payment gateway notifications
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification body"})
+		return
+	}
+
+	// Midtrans notifications predate per-provider webhook URLs
+	provider := c.Param("provider")
+	if provider == "" {
+		provider = gateway.ProviderMidtrans
+	}
+
+	err = h.paymentUseCase.HandlePaymentCallback(c, provider, c.Request.Header, payload)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPaymentSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		// Retrying a notification that cannot be read, or is for no gateway we use, would not help
+		if errors.Is(err, usecase.ErrInvalidPaymentNotification) || errors.Is(err, gateway.ErrUnknownGateway) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// A non-2xx response makes the gateway retry the notification
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification processed"})
+}
+
+// ListPayments handles listing payments (admin only)
+func (h *PaymentHandler) ListPayments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if method := c.Query("payment_method"); method != "" {
+		filter["payment_method"] = method
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		payments, cursors, err := h.paymentUseCase.ListPaymentsByCursor(c, filter, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"payments": payments, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	payments, count, err := h.paymentUseCase.ListPayments(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payments": payments,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RefundPayment handles refunding a payment (admin only)
+func (h *PaymentHandler) RefundPayment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	var request struct {
+		Amount   float64 `json:"amount" binding:"required,gt=0"`
+		Reason   string  `json:"reason" binding:"required"`
+		ToWallet bool    `json:"to_wallet"` // pay the refund into the customer's store credit instead
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	err = h.paymentUseCase.RefundPayment(c, uint(id), request.Amount, request.Reason, request.ToWallet)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment refunded successfully"})
+}
+
+// ApprovePayment handles approving a payment challenged by fraud detection (admin only)
+func (h *PaymentHandler) ApprovePayment(c *gin.Context) {
+	h.reviewPayment(c, true)
+}
+
+// DenyPayment handles denying a payment challenged by fraud detection (admin only)
+func (h *PaymentHandler) DenyPayment(c *gin.Context) {
+	h.reviewPayment(c, false)
+}
+
+// reviewPayment approves or denies a challenged payment
+func (h *PaymentHandler) reviewPayment(c *gin.Context, approve bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	err = h.paymentUseCase.ReviewChallengedPayment(c, uint(id), approve)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if approve {
+		c.JSON(http.StatusOK, gin.H{"message": "Payment approved successfully"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Payment denied successfully"})
+}
@@ -0,0 +1,735 @@
+package handler
+
+import (
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// featuredLimit is how many products the featured lists return by default
+const featuredLimit = 10
+
+// ProductHandler handles product, category and review HTTP requests
+type ProductHandler struct {
+	productUseCase  usecase.ProductUseCase
+	categoryUseCase usecase.CategoryUseCase
+	reviewUseCase   usecase.ReviewUseCase
+}
+
+// NewProductHandler creates a new ProductHandler instance
+func NewProductHandler(productUseCase usecase.ProductUseCase, categoryUseCase usecase.CategoryUseCase, reviewUseCase usecase.ReviewUseCase) *ProductHandler {
+	return &ProductHandler{
+		productUseCase:  productUseCase,
+		categoryUseCase: categoryUseCase,
+		reviewUseCase:   reviewUseCase,
+	}
+}
+
+// productRequest is the body of a product create or update request
+type productRequest struct {
+	Name          string                   `json:"name" binding:"required"`
+	Slug          string                   `json:"slug"`
+	Description   string                   `json:"description"`
+	Price         float64                  `json:"price" binding:"required,gt=0"`
+	DiscountPrice *float64                 `json:"discount_price"`
+	CategoryID    uint                     `json:"category_id" binding:"required"`
+	Attributes    entity.ProductAttributes `json:"attributes"`
+	Tags          []string                 `json:"tags"`
+	IsActive      *bool                    `json:"is_active"`
+}
+
+// variantRequest is the body of a variant create or update request
+type variantRequest struct {
+	SKU    string  `json:"sku" binding:"required"`
+	Size   string  `json:"size"`
+	Color  string  `json:"color"`
+	Stock  int     `json:"stock" binding:"min=0"`
+	Weight float64 `json:"weight" binding:"required,gt=0"`
+}
+
+// toProduct builds the product a request describes
+func (r *productRequest) toProduct() *entity.Product {
+	product := &entity.Product{
+		Name:          r.Name,
+		Slug:          r.Slug,
+		Description:   r.Description,
+		Price:         r.Price,
+		DiscountPrice: r.DiscountPrice,
+		CategoryID:    r.CategoryID,
+		Attributes:    r.Attributes,
+		IsActive:      r.IsActive == nil || *r.IsActive,
+	}
+	if r.Tags != nil {
+		product.Tags = make([]entity.Tag, 0, len(r.Tags))
+		for _, name := range r.Tags {
+			product.Tags = append(product.Tags, entity.Tag{Name: name})
+		}
+	}
+	return product
+}
+
+// toVariant builds the variant a request describes
+func (r *variantRequest) toVariant() *entity.ProductVariant {
+	return &entity.ProductVariant{
+		SKU:    r.SKU,
+		Size:   r.Size,
+		Color:  r.Color,
+		Stock:  r.Stock,
+		Weight: r.Weight,
+	}
+}
+
+// SearchProducts handles searching products by keyword
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	page, limit := pagination(c)
+
+	filter := map[string]interface{}{"is_active": true}
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		filter["category_id"] = uint(id)
+	}
+
+	products, count, err := h.productUseCase.SearchProducts(c, c.Query("q"), filter, c.Query("sort"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetBestSellers handles getting the best-selling products
+func (h *ProductHandler) GetBestSellers(c *gin.Context) {
+	products, err := h.productUseCase.GetBestSellers(c, featuredLimitOf(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"products": products})
+}
+
+// GetNewArrivals handles getting the newest products
+func (h *ProductHandler) GetNewArrivals(c *gin.Context) {
+	products, err := h.productUseCase.GetNewArrivals(c, featuredLimitOf(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"products": products})
+}
+
+// GetTopRated handles getting the best-rated products
+func (h *ProductHandler) GetTopRated(c *gin.Context) {
+	products, err := h.productUseCase.GetTopRated(c, featuredLimitOf(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"products": products})
+}
+
+// GetProductByID handles getting a product by ID
+func (h *ProductHandler) GetProductByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := h.productUseCase.GetProductByID(c, uint(id))
+	if err != nil || !product.IsActive {
+		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"product": product})
+}
+
+// GetProductBySlug handles getting a product by slug
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	product, err := h.productUseCase.GetProductBySlug(c, c.Param("slug"))
+	if err != nil || !product.IsActive {
+		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"product": product})
+}
+
+// GetProductReviews handles listing the reviews of a product
+func (h *ProductHandler) GetProductReviews(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	page, limit := pagination(c)
+
+	rating, err := h.reviewUseCase.GetAverageRating(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		reviews, cursors, err := h.reviewUseCase.GetProductReviewsByCursor(c, uint(id), cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"reviews": reviews, "average_rating": rating, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	reviews, count, err := h.reviewUseCase.GetProductReviews(c, uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews":        reviews,
+		"average_rating": rating,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ListCategories handles listing the top-level categories, or the
+// subcategories of the category given as parent_id
+func (h *ProductHandler) ListCategories(c *gin.Context) {
+	var parentID *uint
+	if raw := c.Query("parent_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent ID"})
+			return
+		}
+		parent := uint(id)
+		parentID = &parent
+	}
+
+	categories, err := h.categoryUseCase.ListCategories(c, parentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// GetCategoryByID handles getting a category by ID
+func (h *ProductHandler) GetCategoryByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := h.categoryUseCase.GetCategoryByID(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// GetCategoryBySlug handles getting a category by slug
+func (h *ProductHandler) GetCategoryBySlug(c *gin.Context) {
+	category, err := h.categoryUseCase.GetCategoryBySlug(c, c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// CreateReview handles reviewing a product from a delivered order, with up
+// to five photos sent as multipart form files named images
+func (h *ProductHandler) CreateReview(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		ProductID uint   `form:"product_id" binding:"required"`
+		OrderID   uint   `form:"order_id" binding:"required"`
+		Rating    int    `form:"rating" binding:"required,min=1,max=5"`
+		Comment   string `form:"comment"`
+		Fit       string `form:"fit" binding:"omitempty,oneof=too_small true_to_size too_large"`
+	}
+
+	if err := c.ShouldBind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	var images []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		images = form.File["images"]
+	}
+
+	review, err := h.reviewUseCase.CreateReview(c, userID, request.ProductID, request.OrderID, request.Rating, request.Comment, entity.FitFeedback(request.Fit), images)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"review": review})
+}
+
+// GetUserReviews handles listing the current user's reviews
+func (h *ProductHandler) GetUserReviews(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, limit := pagination(c)
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		reviews, cursors, err := h.reviewUseCase.GetUserReviewsByCursor(c, userID, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"reviews": reviews, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	reviews, count, err := h.reviewUseCase.GetUserReviews(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// UpdateReview handles updating one of the current user's reviews
+func (h *ProductHandler) UpdateReview(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var request struct {
+		Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+		Comment string `json:"comment"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	review, err := h.reviewUseCase.UpdateReview(c, uint(id), userID, request.Rating, request.Comment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review updated successfully", "review": review})
+}
+
+// DeleteReview handles deleting one of the current user's reviews
+func (h *ProductHandler) DeleteReview(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	if err := h.reviewUseCase.DeleteReview(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// CreateProduct handles creating a product with its variants (admin only)
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	var request struct {
+		productRequest
+		Variants []variantRequest `json:"variants" binding:"dive"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	product := request.toProduct()
+	for _, variant := range request.Variants {
+		product.Variants = append(product.Variants, *variant.toVariant())
+	}
+
+	product, err := h.productUseCase.CreateProduct(c, product)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"product": product})
+}
+
+// UpdateProduct handles updating a product (admin only). A missing
+// is_active keeps the product's current visibility.
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var request productRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if request.IsActive == nil {
+		existing, err := h.productUseCase.GetProductByID(c, uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		request.IsActive = &existing.IsActive
+	}
+
+	product, err := h.productUseCase.UpdateProduct(c, uint(id), request.toProduct())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product updated successfully", "product": product})
+}
+
+// DeleteProduct handles deleting a product (admin only)
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := h.productUseCase.DeleteProduct(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+}
+
+// BulkUploadProducts handles creating products from a CSV file sent as the
+// multipart form file named file (admin only)
+func (h *ProductHandler) BulkUploadProducts(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required"})
+		return
+	}
+
+	created, err := h.productUseCase.BulkUploadProducts(c, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "created": created})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Products uploaded successfully", "created": created})
+}
+
+// UploadProductImage handles uploading an image of a product sent as the
+// multipart form file named image (admin only)
+func (h *ProductHandler) UploadProductImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image file is required"})
+		return
+	}
+
+	image, err := h.productUseCase.UploadProductImage(c, uint(id), file, c.PostForm("is_primary") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"image": image})
+}
+
+// DeleteProductImage handles deleting a product image (admin only)
+func (h *ProductHandler) DeleteProductImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID"})
+		return
+	}
+
+	if err := h.productUseCase.DeleteProductImage(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
+}
+
+// SetPrimaryImage handles making an image the primary image of its product (admin only)
+func (h *ProductHandler) SetPrimaryImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image ID"})
+		return
+	}
+
+	var request struct {
+		ProductID uint `json:"product_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.productUseCase.SetPrimaryImage(c, uint(id), request.ProductID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Primary image updated successfully"})
+}
+
+// AddVariant handles adding a variant to a product (admin only)
+func (h *ProductHandler) AddVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var request variantRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	variant, err := h.productUseCase.AddVariant(c, uint(id), request.toVariant())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"variant": variant})
+}
+
+// UpdateVariant handles updating a variant (admin only)
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	var request variantRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	variant, err := h.productUseCase.UpdateVariant(c, uint(id), request.toVariant())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variant updated successfully", "variant": variant})
+}
+
+// DeleteVariant handles deleting a variant (admin only)
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	if err := h.productUseCase.DeleteVariant(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variant deleted successfully"})
+}
+
+// UpdateStock handles setting the stock of a variant (admin only)
+func (h *ProductHandler) UpdateStock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	var request struct {
+		Stock *int `json:"stock" binding:"required,min=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.productUseCase.UpdateStock(c, uint(id), *request.Stock); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock updated successfully"})
+}
+
+// CreateCategory handles creating a category (admin only)
+func (h *ProductHandler) CreateCategory(c *gin.Context) {
+	var request struct {
+		Name        string `json:"name" binding:"required"`
+		Slug        string `json:"slug"`
+		Description string `json:"description"`
+		ParentID    *uint  `json:"parent_id"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	category, err := h.categoryUseCase.CreateCategory(c, &entity.Category{
+		Name:        request.Name,
+		Slug:        request.Slug,
+		Description: request.Description,
+		ParentID:    request.ParentID,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"category": category})
+}
+
+// UpdateCategory handles updating the name, slug and description of a category (admin only)
+func (h *ProductHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var request struct {
+		Name        string `json:"name" binding:"required"`
+		Slug        string `json:"slug"`
+		Description string `json:"description"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	category, err := h.categoryUseCase.UpdateCategory(c, uint(id), &entity.Category{
+		Name:        request.Name,
+		Slug:        request.Slug,
+		Description: request.Description,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category updated successfully", "category": category})
+}
+
+// UploadCategoryImage handles uploading the image of a category sent as the
+// multipart form file named image (admin only)
+func (h *ProductHandler) UploadCategoryImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image file is required"})
+		return
+	}
+
+	category, err := h.categoryUseCase.UploadCategoryImage(c, uint(id), file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// featuredLimitOf parses the limit of a featured product list
+func featuredLimitOf(c *gin.Context) int {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(featuredLimit)))
+	if limit <= 0 {
+		return featuredLimit
+	}
+	return min(limit, maxPageLimit)
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromotionHandler handles promotion HTTP requests
+type PromotionHandler struct {
+	promotionUseCase usecase.PromotionUseCase
+}
+
+// NewPromotionHandler creates a new PromotionHandler instance
+func NewPromotionHandler(promotionUseCase usecase.PromotionUseCase) *PromotionHandler {
+	return &PromotionHandler{
+		promotionUseCase: promotionUseCase,
+	}
+}
+
+// promotionRequest is the body of the admin create and update promotion requests
+type promotionRequest struct {
+	Name               string    `json:"name" binding:"required"`
+	Description        string    `json:"description"`
+	Type               string    `json:"type" binding:"required,oneof=buy_x_get_y bundle tiered"`
+	Priority           int       `json:"priority"`
+	StartsAt           time.Time `json:"starts_at" binding:"required"`
+	EndsAt             time.Time `json:"ends_at" binding:"required"`
+	IsActive           *bool     `json:"is_active"`
+	BuyQuantity        int       `json:"buy_quantity" binding:"gte=0"`
+	GetQuantity        int       `json:"get_quantity" binding:"gte=0"`
+	GetDiscountPercent float64   `json:"get_discount_percent" binding:"gte=0,lte=100"`
+	BundlePrice        float64   `json:"bundle_price" binding:"gte=0"`
+	BundleItems        []struct {
+		ProductID  *uint `json:"product_id"`
+		CategoryID *uint `json:"category_id"`
+		Quantity   int   `json:"quantity" binding:"required,gt=0"`
+	} `json:"bundle_items" binding:"dive"`
+	Tiers []struct {
+		MinSpend        float64 `json:"min_spend" binding:"required,gt=0"`
+		DiscountPercent float64 `json:"discount_percent" binding:"required,gt=0,lte=100"`
+		MaxDiscount     float64 `json:"max_discount" binding:"gte=0"`
+	} `json:"tiers" binding:"dive"`
+	ProductIDs  []uint `json:"product_ids"`
+	CategoryIDs []uint `json:"category_ids"`
+}
+
+// toEntity builds the promotion described by the request
+func (r *promotionRequest) toEntity() *entity.Promotion {
+	promotion := &entity.Promotion{
+		Name:               r.Name,
+		Description:        r.Description,
+		Type:               entity.PromotionType(r.Type),
+		Priority:           r.Priority,
+		StartsAt:           r.StartsAt,
+		EndsAt:             r.EndsAt,
+		IsActive:           true,
+		BuyQuantity:        r.BuyQuantity,
+		GetQuantity:        r.GetQuantity,
+		GetDiscountPercent: r.GetDiscountPercent,
+		BundlePrice:        r.BundlePrice,
+	}
+	if r.IsActive != nil {
+		promotion.IsActive = *r.IsActive
+	}
+	for _, item := range r.BundleItems {
+		promotion.BundleItems = append(promotion.BundleItems, entity.PromotionBundleItem{
+			ProductID:  item.ProductID,
+			CategoryID: item.CategoryID,
+			Quantity:   item.Quantity,
+		})
+	}
+	for _, tier := range r.Tiers {
+		promotion.Tiers = append(promotion.Tiers, entity.PromotionTier{
+			MinSpend:        tier.MinSpend,
+			DiscountPercent: tier.DiscountPercent,
+			MaxDiscount:     tier.MaxDiscount,
+		})
+	}
+	return promotion
+}
+
+// EvaluateCart handles showing the promotions the current user's cart qualifies for
+func (h *PromotionHandler) EvaluateCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	result, err := h.promotionUseCase.EvaluateCart(c, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"promotions": result})
+}
+
+// CreatePromotion handles creating a promotion (admin only)
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var request promotionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	promotion := request.toEntity()
+	if err := h.promotionUseCase.CreatePromotion(c, promotion, request.ProductIDs, request.CategoryIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"promotion": promotion})
+}
+
+// UpdatePromotion handles updating a promotion (admin only)
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+		return
+	}
+
+	var request promotionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	promotion := request.toEntity()
+	promotion.ID = uint(id)
+	if err := h.promotionUseCase.UpdatePromotion(c, promotion, request.ProductIDs, request.CategoryIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"promotion": promotion})
+}
+
+// DeletePromotion handles deleting a promotion (admin only)
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+		return
+	}
+
+	if err := h.promotionUseCase.DeletePromotion(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promotion deleted successfully"})
+}
+
+// GetPromotion handles getting a promotion (admin only)
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promotion ID"})
+		return
+	}
+
+	promotion, err := h.promotionUseCase.GetPromotion(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"promotion": promotion})
+}
+
+// ListPromotions handles listing promotions (admin only)
+func (h *PromotionHandler) ListPromotions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if promotionType := c.Query("type"); promotionType != "" {
+		filter["type"] = promotionType
+	}
+	if active := c.Query("is_active"); active != "" {
+		filter["is_active"] = active == "true"
+	}
+
+	promotions, count, err := h.promotionUseCase.ListPromotions(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promotions": promotions,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSettlementReportSize caps the size of an uploaded settlement report
+const maxSettlementReportSize = 20 << 20
+
+// ReconciliationHandler handles payment reconciliation HTTP requests
+type ReconciliationHandler struct {
+	reconciliationUseCase usecase.ReconciliationUseCase
+}
+
+// NewReconciliationHandler creates a new ReconciliationHandler instance
+func NewReconciliationHandler(reconciliationUseCase usecase.ReconciliationUseCase) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationUseCase: reconciliationUseCase,
+	}
+}
+
+// ImportSettlementReport handles uploading a gateway settlement CSV (admin only)
+func (h *ReconciliationHandler) ImportSettlementReport(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	start, end, err := parsePeriod(c.PostForm("start_date"), c.PostForm("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Settlement report file is required"})
+		return
+	}
+
+	if file.Size > maxSettlementReportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Settlement report is too large"})
+		return
+	}
+
+	report, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read settlement report"})
+		return
+	}
+	defer report.Close()
+
+	provider := c.DefaultPostForm("provider", "midtrans")
+	run, err := h.reconciliationUseCase.ImportSettlementReport(c, provider, file.Filename, report, start, end, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"run": run})
+}
+
+// RunStatusCheck handles reconciling payments against the gateway transaction status (admin only)
+func (h *ReconciliationHandler) RunStatusCheck(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Provider  string `json:"provider"`
+		StartDate string `json:"start_date" binding:"required"`
+		EndDate   string `json:"end_date" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	start, end, err := parsePeriod(request.StartDate, request.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := h.reconciliationUseCase.RunStatusCheck(c, request.Provider, start, end, &userID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecase.ErrGatewayUnavailable) {
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"run": run})
+}
+
+// ListRuns handles listing reconciliation runs (admin only)
+func (h *ReconciliationHandler) ListRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		runs, cursors, err := h.reconciliationUseCase.ListRunsByCursor(c, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"runs": runs, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	runs, count, err := h.reconciliationUseCase.ListRuns(c, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"runs": runs,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetRun handles getting a reconciliation report, optionally filtered by item status (admin only)
+func (h *ReconciliationHandler) GetRun(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reconciliation ID"})
+		return
+	}
+
+	run, err := h.reconciliationUseCase.GetRun(c, uint(id), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"run": run})
+}
+
+// parsePeriod parses an inclusive YYYY-MM-DD date range into a half-open time range
+func parsePeriod(startDate, endDate string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid start_date, expected YYYY-MM-DD")
+	}
+	end, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Invalid end_date, expected YYYY-MM-DD")
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
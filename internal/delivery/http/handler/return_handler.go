@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReturnHandler handles return (RMA) HTTP requests
+type ReturnHandler struct {
+	returnUseCase usecase.ReturnUseCase
+}
+
+// NewReturnHandler creates a new ReturnHandler instance
+func NewReturnHandler(returnUseCase usecase.ReturnUseCase) *ReturnHandler {
+	return &ReturnHandler{
+		returnUseCase: returnUseCase,
+	}
+}
+
+// CreateReturn handles requesting the return of items from a delivered order
+func (h *ReturnHandler) CreateReturn(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		OrderID uint `json:"order_id" binding:"required"`
+		Items   []struct {
+			OrderItemID uint `json:"order_item_id" binding:"required"`
+			Quantity    int  `json:"quantity" binding:"required,gt=0"`
+		} `json:"items" binding:"required,min=1,dive"`
+		Reason      string   `json:"reason" binding:"required,oneof=wrong_size too_small too_large defective not_as_described wrong_item changed_mind other"`
+		Description string   `json:"description"`
+		PhotoURLs   []string `json:"photo_urls" binding:"max=5,dive,url"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	items := make([]usecase.ReturnItemRequest, 0, len(request.Items))
+	for _, item := range request.Items {
+		items = append(items, usecase.ReturnItemRequest{OrderItemID: item.OrderItemID, Quantity: item.Quantity})
+	}
+
+	ret, err := h.returnUseCase.CreateReturn(c, userID, request.OrderID, items, entity.ReturnReason(request.Reason), request.Description, request.PhotoURLs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"return": ret})
+}
+
+// GetUserReturns handles listing the current user's return requests
+func (h *ReturnHandler) GetUserReturns(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	returns, count, err := h.returnUseCase.GetUserReturns(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"returns": returns,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetReturnByID handles getting one of the current user's return requests
+func (h *ReturnHandler) GetReturnByID(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	ret, err := h.returnUseCase.GetReturnByID(c, uint(id), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"return": ret})
+}
+
+// CancelReturn handles withdrawing a return request
+func (h *ReturnHandler) CancelReturn(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	if err := h.returnUseCase.CancelReturn(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return cancelled successfully"})
+}
+
+// SubmitReturnShipment handles submitting the tracking number of a return shipment
+func (h *ReturnHandler) SubmitReturnShipment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	var request struct {
+		Courier        string `json:"courier" binding:"required"`
+		TrackingNumber string `json:"tracking_number" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.returnUseCase.SubmitReturnShipment(c, uint(id), userID, request.Courier, request.TrackingNumber); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return shipment submitted successfully"})
+}
+
+// ListReturns handles listing all return requests (admin only)
+func (h *ReturnHandler) ListReturns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if reason := c.Query("reason"); reason != "" {
+		filter["reason"] = reason
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		returns, cursors, err := h.returnUseCase.ListReturnsByCursor(c, filter, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"returns": returns, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	returns, count, err := h.returnUseCase.ListReturns(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"returns": returns,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetReturn handles getting any return request (admin only)
+func (h *ReturnHandler) GetReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	ret, err := h.returnUseCase.GetReturn(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"return": ret})
+}
+
+// ApproveReturn handles approving a return request (admin only)
+func (h *ReturnHandler) ApproveReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	var request struct {
+		Note string `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.returnUseCase.ApproveReturn(c, uint(id), request.Note); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return approved successfully"})
+}
+
+// RejectReturn handles rejecting a return request (admin only)
+func (h *ReturnHandler) RejectReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	var request struct {
+		Note string `json:"note" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.returnUseCase.RejectReturn(c, uint(id), request.Note); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return rejected successfully"})
+}
+
+// ReceiveReturn handles receiving returned items, restocking them and refunding the customer (admin only)
+func (h *ReturnHandler) ReceiveReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	if err := h.returnUseCase.ReceiveReturn(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return received and refunded successfully"})
+}
+
+// RefundReturn handles retrying the refund of a received return (admin only)
+func (h *ReturnHandler) RefundReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	if err := h.returnUseCase.RefundReturn(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return refunded successfully"})
+}
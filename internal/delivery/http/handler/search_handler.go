@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// attributeFilterPrefix prefixes the query parameters filtering on product attributes
+const attributeFilterPrefix = "attr."
+
+// SearchHandler handles product search HTTP requests
+type SearchHandler struct {
+	productSearchUseCase usecase.ProductSearchUseCase
+}
+
+// NewSearchHandler creates a new SearchHandler instance
+func NewSearchHandler(productSearchUseCase usecase.ProductSearchUseCase) *SearchHandler {
+	return &SearchHandler{
+		productSearchUseCase: productSearchUseCase,
+	}
+}
+
+// ListProducts handles listing products with storefront filters and their facet counts
+func (h *SearchHandler) ListProducts(c *gin.Context) {
+	page, limit := pagination(c)
+
+	filter := &entity.ProductFilter{
+		Keyword: c.Query("q"),
+		Sizes:   c.QueryArray("size"),
+		Colors:  c.QueryArray("color"),
+		Tags:    c.QueryArray("tag"),
+		InStock: c.Query("in_stock") == "true",
+	}
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		category := uint(id)
+		filter.CategoryID = &category
+	}
+	for param, target := range map[string]**float64{
+		"min_price":  &filter.MinPrice,
+		"max_price":  &filter.MaxPrice,
+		"min_rating": &filter.MinRating,
+	} {
+		if raw := c.Query(param); raw != "" {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = &value
+		}
+	}
+	// Attribute filters are passed as attr.<code>, e.g. attr.material=cotton
+	for param, values := range c.Request.URL.Query() {
+		if code, ok := strings.CutPrefix(param, attributeFilterPrefix); ok && code != "" {
+			if filter.Attributes == nil {
+				filter.Attributes = make(map[string][]string)
+			}
+			filter.Attributes[code] = values
+		}
+	}
+
+	facets, err := h.productSearchUseCase.GetFacets(c, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		products, cursors, err := h.productSearchUseCase.FilterProductsByCursor(c, filter, c.Query("sort"), cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"products": products, "facets": facets, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	products, count, err := h.productSearchUseCase.FilterProducts(c, filter, c.Query("sort"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"facets":   facets,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetSuggestions handles getting autocomplete suggestions for the search box
+func (h *SearchHandler) GetSuggestions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	suggestions, err := h.productSearchUseCase.GetSuggestions(c, c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
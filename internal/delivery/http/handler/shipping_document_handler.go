@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShippingDocumentHandler handles shipping document HTTP requests
+type ShippingDocumentHandler struct {
+	shippingDocumentUseCase usecase.ShippingDocumentUseCase
+}
+
+// NewShippingDocumentHandler creates a new ShippingDocumentHandler instance
+func NewShippingDocumentHandler(shippingDocumentUseCase usecase.ShippingDocumentUseCase) *ShippingDocumentHandler {
+	return &ShippingDocumentHandler{
+		shippingDocumentUseCase: shippingDocumentUseCase,
+	}
+}
+
+// GetPackingSlip handles downloading the packing slip of an order (admin only)
+func (h *ShippingDocumentHandler) GetPackingSlip(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	pdf, err := h.shippingDocumentUseCase.GetPackingSlip(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("packing-slip-%d.pdf", id), pdf)
+}
+
+// GetShippingLabel handles downloading the shipping label of an order (admin only)
+func (h *ShippingDocumentHandler) GetShippingLabel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	pdf, err := h.shippingDocumentUseCase.GetShippingLabel(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("shipping-label-%d.pdf", id), pdf)
+}
+
+// GetBulkShippingLabels handles downloading the shipping labels of many orders as one PDF (admin only)
+func (h *ShippingDocumentHandler) GetBulkShippingLabels(c *gin.Context) {
+	var request struct {
+		OrderIDs []uint `json:"order_ids" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	pdf, err := h.shippingDocumentUseCase.GetBulkShippingLabels(c, request.OrderIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("shipping-labels-%s.pdf", time.Now().Format("20060102-150405")), pdf)
+}
+
+// sendPDF writes a PDF document as a downloadable attachment
+func sendPDF(c *gin.Context, filename string, pdf []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SizeChartHandler handles size chart, body profile and size recommendation HTTP requests
+type SizeChartHandler struct {
+	sizeChartUseCase usecase.SizeChartUseCase
+}
+
+// NewSizeChartHandler creates a new SizeChartHandler instance
+func NewSizeChartHandler(sizeChartUseCase usecase.SizeChartUseCase) *SizeChartHandler {
+	return &SizeChartHandler{
+		sizeChartUseCase: sizeChartUseCase,
+	}
+}
+
+// sizeChartRequest is the body of size chart create and update requests
+type sizeChartRequest struct {
+	Name       string `json:"name" binding:"required"`
+	ProductID  *uint  `json:"product_id"`
+	CategoryID *uint  `json:"category_id"`
+	Entries    []struct {
+		Size         string                  `json:"size" binding:"required"`
+		Measurements entity.SizeMeasurements `json:"measurements" binding:"required"` // in cm, keyed by measurement
+	} `json:"entries" binding:"required,min=1,dive"` // smallest size first
+}
+
+// toSizeChart converts the request into a size chart
+func (r *sizeChartRequest) toSizeChart() *entity.SizeChart {
+	chart := &entity.SizeChart{
+		Name:       r.Name,
+		ProductID:  r.ProductID,
+		CategoryID: r.CategoryID,
+		Entries:    make([]entity.SizeChartEntry, len(r.Entries)),
+	}
+	for i, entry := range r.Entries {
+		chart.Entries[i] = entity.SizeChartEntry{Size: entry.Size, Measurements: entry.Measurements}
+	}
+	return chart
+}
+
+// GetProductSizeChart handles getting the size chart of a product with its fit feedback
+func (h *SizeChartHandler) GetProductSizeChart(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	chart, fit, err := h.sizeChartUseCase.GetProductSizeChart(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"size_chart": chart, "fit": fit})
+}
+
+// RecommendSize handles suggesting a product size from the user's body profile
+func (h *SizeChartHandler) RecommendSize(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	recommendation, err := h.sizeChartUseCase.RecommendSize(c, userID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recommendation": recommendation})
+}
+
+// GetBodyProfile handles getting the user's body measurements
+func (h *SizeChartHandler) GetBodyProfile(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	profile, err := h.sizeChartUseCase.GetBodyProfile(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"body_profile": profile})
+}
+
+// SaveBodyProfile handles setting the user's body measurements
+func (h *SizeChartHandler) SaveBodyProfile(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Height        *float64 `json:"height"`
+		Chest         *float64 `json:"chest"`
+		Waist         *float64 `json:"waist"`
+		Hips          *float64 `json:"hips"`
+		Inseam        *float64 `json:"inseam"`
+		FootLength    *float64 `json:"foot_length"`
+		Weight        *float64 `json:"weight"`
+		FitPreference string   `json:"fit_preference" binding:"omitempty,oneof=snug regular loose"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	profile, err := h.sizeChartUseCase.SaveBodyProfile(c, userID, &entity.BodyProfile{
+		Height:        request.Height,
+		Chest:         request.Chest,
+		Waist:         request.Waist,
+		Hips:          request.Hips,
+		Inseam:        request.Inseam,
+		FootLength:    request.FootLength,
+		Weight:        request.Weight,
+		FitPreference: entity.FitPreference(request.FitPreference),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"body_profile": profile})
+}
+
+// SetReviewFit handles recording how the size fit the author of a review
+func (h *SizeChartHandler) SetReviewFit(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var request struct {
+		Fit string `json:"fit" binding:"required,oneof=too_small true_to_size too_large"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	review, err := h.sizeChartUseCase.SetReviewFit(c, userID, uint(id), entity.FitFeedback(request.Fit))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"review": review})
+}
+
+// ListSizeCharts handles listing size charts (admin only)
+func (h *SizeChartHandler) ListSizeCharts(c *gin.Context) {
+	page, limit := pagination(c)
+
+	charts, count, err := h.sizeChartUseCase.ListSizeCharts(c, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"size_charts": charts,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// CreateSizeChart handles creating the size chart of a product or category (admin only)
+func (h *SizeChartHandler) CreateSizeChart(c *gin.Context) {
+	var request sizeChartRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	chart, err := h.sizeChartUseCase.CreateSizeChart(c, request.toSizeChart())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"size_chart": chart})
+}
+
+// GetSizeChart handles getting a size chart (admin only)
+func (h *SizeChartHandler) GetSizeChart(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size chart ID"})
+		return
+	}
+
+	chart, err := h.sizeChartUseCase.GetSizeChart(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"size_chart": chart})
+}
+
+// UpdateSizeChart handles updating a size chart (admin only)
+func (h *SizeChartHandler) UpdateSizeChart(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size chart ID"})
+		return
+	}
+
+	var request sizeChartRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	chart, err := h.sizeChartUseCase.UpdateSizeChart(c, uint(id), request.toSizeChart())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"size_chart": chart})
+}
+
+// DeleteSizeChart handles deleting a size chart (admin only)
+func (h *SizeChartHandler) DeleteSizeChart(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size chart ID"})
+		return
+	}
+
+	if err := h.sizeChartUseCase.DeleteSizeChart(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Size chart deleted successfully"})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"fashion-shop/internal/domain/entity"
 	"fashion-shop/internal/domain/usecase"
 	"fashion-shop/internal/utils"
 
@@ -210,6 +211,22 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		users, cursors, err := h.userUseCase.GetUsersByCursor(c, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"users": users, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
 	users, count, err := h.userUseCase.GetUsers(c, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -295,3 +312,156 @@ func (h *UserHandler) ResetUserPassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "User password reset successfully"})
 }
+
+// addressRequest is the body of an address create or update request
+type addressRequest struct {
+	Label       string `json:"label" binding:"required"`
+	Recipient   string `json:"recipient" binding:"required"`
+	Phone       string `json:"phone" binding:"required"`
+	Province    string `json:"province" binding:"required"`
+	City        string `json:"city" binding:"required"`
+	District    string `json:"district" binding:"required"`
+	PostalCode  string `json:"postal_code" binding:"required"`
+	FullAddress string `json:"full_address" binding:"required"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+// toAddress builds the address a request describes
+func (r *addressRequest) toAddress() *entity.Address {
+	return &entity.Address{
+		Label:       r.Label,
+		Recipient:   r.Recipient,
+		Phone:       r.Phone,
+		Province:    r.Province,
+		City:        r.City,
+		District:    r.District,
+		PostalCode:  r.PostalCode,
+		FullAddress: r.FullAddress,
+		IsDefault:   r.IsDefault,
+	}
+}
+
+// GetAddresses handles listing the user's addresses
+func (h *UserHandler) GetAddresses(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	addresses, err := h.addressUseCase.GetAddresses(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"addresses": addresses})
+}
+
+// CreateAddress handles adding an address for the user
+func (h *UserHandler) CreateAddress(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request addressRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	address, err := h.addressUseCase.CreateAddress(c, userID, request.toAddress())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Address created successfully", "address": address})
+}
+
+// GetAddressByID handles getting one of the user's addresses
+func (h *UserHandler) GetAddressByID(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	address, err := h.addressUseCase.GetAddressByID(c, uint(id), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"address": address})
+}
+
+// UpdateAddress handles updating one of the user's addresses
+func (h *UserHandler) UpdateAddress(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	var request addressRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	address, err := h.addressUseCase.UpdateAddress(c, uint(id), userID, request.toAddress())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Address updated successfully", "address": address})
+}
+
+// DeleteAddress handles deleting one of the user's addresses
+func (h *UserHandler) DeleteAddress(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	if err := h.addressUseCase.DeleteAddress(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Address deleted successfully"})
+}
+
+// SetDefaultAddress handles making one of the user's addresses the default
+func (h *UserHandler) SetDefaultAddress(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address ID"})
+		return
+	}
+
+	if err := h.addressUseCase.SetDefaultAddress(c, uint(id), userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Default address updated successfully"})
+}
+
+// GetDefaultAddress handles getting the user's default address
+func (h *UserHandler) GetDefaultAddress(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	address, err := h.addressUseCase.GetDefaultAddress(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"address": address})
+}
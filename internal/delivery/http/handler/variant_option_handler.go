@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VariantOptionHandler handles product option and variant matrix HTTP requests
+type VariantOptionHandler struct {
+	variantOptionUseCase usecase.VariantOptionUseCase
+}
+
+// NewVariantOptionHandler creates a new VariantOptionHandler instance
+func NewVariantOptionHandler(variantOptionUseCase usecase.VariantOptionUseCase) *VariantOptionHandler {
+	return &VariantOptionHandler{
+		variantOptionUseCase: variantOptionUseCase,
+	}
+}
+
+// GetOptions handles getting the options a product varies by
+func (h *VariantOptionHandler) GetOptions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	options, err := h.variantOptionUseCase.GetOptions(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"options": options})
+}
+
+// GenerateVariants handles setting the options of a product and generating its variant matrix (admin only)
+func (h *VariantOptionHandler) GenerateVariants(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var request struct {
+		Options []struct {
+			Name   string   `json:"name" binding:"required"`
+			Values []string `json:"values" binding:"required,min=1"`
+		} `json:"options" binding:"required,min=1,dive"`
+		SKUTemplate string  `json:"sku_template"`           // defaults to the product's last template, then {product}-{options}
+		Weight      float64 `json:"weight" binding:"gte=0"` // of new variants, defaults to the existing variants' weight
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	options := make([]*entity.ProductOption, len(request.Options))
+	for i, option := range request.Options {
+		options[i] = &entity.ProductOption{Name: option.Name}
+		for _, value := range option.Values {
+			options[i].Values = append(options[i].Values, entity.ProductOptionValue{Value: value})
+		}
+	}
+
+	variants, err := h.variantOptionUseCase.GenerateVariants(c, uint(id), options, request.SKUTemplate, request.Weight)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"variants": variants})
+}
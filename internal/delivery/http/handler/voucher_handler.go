@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VoucherHandler handles voucher HTTP requests
+type VoucherHandler struct {
+	voucherUseCase usecase.VoucherUseCase
+}
+
+// NewVoucherHandler creates a new VoucherHandler instance
+func NewVoucherHandler(voucherUseCase usecase.VoucherUseCase) *VoucherHandler {
+	return &VoucherHandler{
+		voucherUseCase: voucherUseCase,
+	}
+}
+
+// voucherRequest is the body of the admin create and update voucher requests
+type voucherRequest struct {
+	Code              string    `json:"code" binding:"required,max=50"`
+	Name              string    `json:"name" binding:"required"`
+	Description       string    `json:"description"`
+	Type              string    `json:"type" binding:"required,oneof=percentage fixed free_shipping"`
+	Value             float64   `json:"value" binding:"gte=0"`
+	MinSpend          float64   `json:"min_spend" binding:"gte=0"`
+	MaxDiscount       float64   `json:"max_discount" binding:"gte=0"`
+	UsageLimit        int       `json:"usage_limit" binding:"gte=0"`
+	UsageLimitPerUser int       `json:"usage_limit_per_user" binding:"gte=0"`
+	StartsAt          time.Time `json:"starts_at" binding:"required"`
+	EndsAt            time.Time `json:"ends_at" binding:"required"`
+	IsActive          *bool     `json:"is_active"`
+	ProductIDs        []uint    `json:"product_ids"`
+	CategoryIDs       []uint    `json:"category_ids"`
+}
+
+// toEntity builds the voucher described by the request
+func (r *voucherRequest) toEntity() *entity.Voucher {
+	voucher := &entity.Voucher{
+		Code:              r.Code,
+		Name:              r.Name,
+		Description:       r.Description,
+		Type:              entity.VoucherType(r.Type),
+		Value:             r.Value,
+		MinSpend:          r.MinSpend,
+		MaxDiscount:       r.MaxDiscount,
+		UsageLimit:        r.UsageLimit,
+		UsageLimitPerUser: r.UsageLimitPerUser,
+		StartsAt:          r.StartsAt,
+		EndsAt:            r.EndsAt,
+		IsActive:          true,
+	}
+	if r.IsActive != nil {
+		voucher.IsActive = *r.IsActive
+	}
+	return voucher
+}
+
+// ApplyToCart handles applying a voucher to the current user's cart
+func (h *VoucherHandler) ApplyToCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	quote, err := h.voucherUseCase.ApplyToCart(c, userID, request.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"voucher": quote})
+}
+
+// RemoveFromCart handles removing the voucher from the current user's cart
+func (h *VoucherHandler) RemoveFromCart(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if err := h.voucherUseCase.RemoveFromCart(c, userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Voucher removed successfully"})
+}
+
+// RedeemForOrder handles applying a voucher to an unpaid order at checkout
+func (h *VoucherHandler) RedeemForOrder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	order, quote, err := h.voucherUseCase.RedeemForOrder(c, userID, uint(id), request.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order, "voucher": quote})
+}
+
+// CreateVoucher handles creating a voucher (admin only)
+func (h *VoucherHandler) CreateVoucher(c *gin.Context) {
+	var request voucherRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	voucher := request.toEntity()
+	if err := h.voucherUseCase.CreateVoucher(c, voucher, request.ProductIDs, request.CategoryIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"voucher": voucher})
+}
+
+// UpdateVoucher handles updating a voucher (admin only)
+func (h *VoucherHandler) UpdateVoucher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid voucher ID"})
+		return
+	}
+
+	var request voucherRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	voucher := request.toEntity()
+	voucher.ID = uint(id)
+	if err := h.voucherUseCase.UpdateVoucher(c, voucher, request.ProductIDs, request.CategoryIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"voucher": voucher})
+}
+
+// DeleteVoucher handles deleting a voucher (admin only)
+func (h *VoucherHandler) DeleteVoucher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid voucher ID"})
+		return
+	}
+
+	if err := h.voucherUseCase.DeleteVoucher(c, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Voucher deleted successfully"})
+}
+
+// GetVoucher handles getting a voucher (admin only)
+func (h *VoucherHandler) GetVoucher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid voucher ID"})
+		return
+	}
+
+	voucher, err := h.voucherUseCase.GetVoucher(c, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"voucher": voucher})
+}
+
+// ListVouchers handles listing vouchers (admin only)
+func (h *VoucherHandler) ListVouchers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := make(map[string]interface{})
+	if voucherType := c.Query("type"); voucherType != "" {
+		filter["type"] = voucherType
+	}
+	if active := c.Query("is_active"); active != "" {
+		filter["is_active"] = active == "true"
+	}
+
+	if cursor, ok, err := cursorPagination(c); ok {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		vouchers, cursors, err := h.voucherUseCase.ListVouchersByCursor(c, filter, cursor)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"vouchers": vouchers, "meta": cursorMeta(cursor, cursors)})
+		return
+	}
+
+	vouchers, count, err := h.voucherUseCase.ListVouchers(c, filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vouchers": vouchers,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WalletHandler handles store credit wallet HTTP requests
+type WalletHandler struct {
+	walletUseCase usecase.WalletUseCase
+}
+
+// NewWalletHandler creates a new WalletHandler instance
+func NewWalletHandler(walletUseCase usecase.WalletUseCase) *WalletHandler {
+	return &WalletHandler{
+		walletUseCase: walletUseCase,
+	}
+}
+
+// GetWallet handles getting the current user's store credit balance and ledger
+func (h *WalletHandler) GetWallet(c *gin.Context) {
+	userID := c.GetUint("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	wallet, err := h.walletUseCase.GetWallet(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactions, count, err := h.walletUseCase.GetTransactions(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wallet":       wallet,
+		"transactions": transactions,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// ApplyToOrder handles paying part or all of an unpaid order with store credit
+func (h *WalletHandler) ApplyToOrder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var request struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	order, err := h.walletUseCase.ApplyToOrder(c, userID, uint(id), request.Amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
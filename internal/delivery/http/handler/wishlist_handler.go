@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WishlistHandler handles wishlist HTTP requests
+type WishlistHandler struct {
+	wishlistUseCase usecase.WishlistUseCase
+}
+
+// NewWishlistHandler creates a new WishlistHandler instance
+func NewWishlistHandler(wishlistUseCase usecase.WishlistUseCase) *WishlistHandler {
+	return &WishlistHandler{
+		wishlistUseCase: wishlistUseCase,
+	}
+}
+
+// GetWishlist handles listing the items of the current user's wishlist
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, limit := pagination(c)
+
+	wishlist, items, count, err := h.wishlistUseCase.GetWishlist(c, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wishlist": wishlist,
+		"items":    items,
+		"meta": gin.H{
+			"total": count,
+			"page":  page,
+			"limit": limit,
+			"pages": (count + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// AddToWishlist handles adding a product to the current user's wishlist
+func (h *WishlistHandler) AddToWishlist(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		ProductID uint `json:"product_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.GetValidationErrorMessage(err)})
+		return
+	}
+
+	if err := h.wishlistUseCase.AddToWishlist(c, userID, request.ProductID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product added to wishlist successfully"})
+}
+
+// RemoveFromWishlist handles removing an item from the current user's wishlist
+func (h *WishlistHandler) RemoveFromWishlist(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist item ID"})
+		return
+	}
+
+	if err := h.wishlistUseCase.RemoveFromWishlist(c, userID, uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removed from wishlist successfully"})
+}
+
+// IsInWishlist handles checking whether a product is in the current user's wishlist
+func (h *WishlistHandler) IsInWishlist(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	inWishlist, err := h.wishlistUseCase.IsInWishlist(c, userID, uint(productID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"in_wishlist": inWishlist})
+}
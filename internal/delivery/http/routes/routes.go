@@ -1,21 +1,33 @@
 package routes
 
 import (
+	"context"
 	"fashion-shop/internal/config"
 	"fashion-shop/internal/delivery/http/handler"
 	"fashion-shop/internal/delivery/http/middleware"
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
 	"fashion-shop/internal/domain/usecase/impl"
 	"fashion-shop/internal/infrastructure/auth"
+	"fashion-shop/internal/infrastructure/document"
+	"fashion-shop/internal/infrastructure/gateway"
+	"fashion-shop/internal/infrastructure/imaging"
 	"fashion-shop/internal/infrastructure/persistence"
+	"fashion-shop/internal/infrastructure/scheduler"
+	"fashion-shop/internal/infrastructure/search"
+	"fashion-shop/internal/infrastructure/storage"
 	"fashion-shop/internal/infrastructure/third_party"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
-// RegisterRoutes registers all routes
-func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisClient *redis.Client, cfg *config.Config) {
+// RegisterRoutes registers all routes and adds the background jobs to jobs,
+// which the caller starts and stops alongside the server
+func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisClient *redis.Client, cfg *config.Config, jobs *scheduler.Scheduler) {
 	// Initialize services
 	jwtService := auth.NewJWTService(
 		cfg.JWT.Secret,
@@ -34,41 +46,354 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 		cfg.SMTP.From,
 	)
 
+	shippingDocumentService := document.NewPDFShippingDocumentService(document.SenderInfo{
+		Name:       cfg.Shop.Name,
+		Phone:      cfg.Shop.Phone,
+		Address:    cfg.Shop.Address,
+		City:       cfg.Shop.City,
+		Province:   cfg.Shop.Province,
+		PostalCode: cfg.Shop.PostalCode,
+	})
+
+	// Initialize file storage and image processing
+	fileStorage, err := storage.NewStorage(cfg.Storage.Type, cfg.Storage.LocalPath, cfg.Storage.BaseURL)
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %v", err)
+	}
+	if cfg.Storage.Type == storage.TypeLocal && strings.HasPrefix(cfg.Storage.BaseURL, "/") {
+		router.Static(cfg.Storage.BaseURL, cfg.Storage.LocalPath)
+	}
+	webpEncoder, err := imaging.NewCWebPEncoder(cfg.Images.CWebPPath)
+	if err != nil {
+		log.Printf("WebP image renditions are disabled: %v", err)
+	}
+	imageProcessor := imaging.NewProcessor(imaging.Options{
+		MinWidth:      cfg.Images.MinWidth,
+		MinHeight:     cfg.Images.MinHeight,
+		MaxWidth:      cfg.Images.MaxWidth,
+		MaxHeight:     cfg.Images.MaxHeight,
+		MaxPixels:     cfg.Images.MaxPixels,
+		MaxConcurrent: cfg.Images.MaxConcurrent,
+		Quality:       cfg.Images.Quality,
+	}, webpEncoder)
+
 	// Initialize third-party services
 	rajaOngkirService := third_party.NewRajaOngkirService(cfg.RajaOngkir.APIKey, cfg.RajaOngkir.URL)
-	midtransService := third_party.NewMidtransService(
-		cfg.Midtrans.ServerKey,
-		cfg.Midtrans.ClientKey,
-		cfg.Midtrans.Environment,
-	)
+
+	// Initialize payment gateways
+	paymentGateways := []gateway.PaymentGateway{
+		gateway.NewMidtransGateway(cfg.Midtrans.ServerKey, cfg.Midtrans.Environment),
+		gateway.NewXenditGateway(cfg.Xendit.SecretKey, cfg.Xendit.CallbackToken, cfg.Xendit.URL),
+	}
+	var fakeGateway *gateway.FakeGateway
+	if cfg.FakeGateway.Enabled {
+		fakeGateway = gateway.NewFakeGateway(
+			cfg.FakeGateway.Secret,
+			cfg.FakeGateway.WebhookURL,
+			cfg.FakeGateway.AutoSettle,
+		)
+		paymentGateways = append(paymentGateways, fakeGateway)
+	}
+	gatewayRegistry, err := gateway.NewRegistry(cfg.PaymentGateway.Default, map[entity.PaymentMethod]string{
+		entity.PaymentMethodCreditCard:   cfg.PaymentGateway.CreditCard,
+		entity.PaymentMethodBankTransfer: cfg.PaymentGateway.BankTransfer,
+		entity.PaymentMethodEWallet:      cfg.PaymentGateway.EWallet,
+		entity.PaymentMethodQRIS:         cfg.PaymentGateway.QRIS,
+	}, paymentGateways...)
+	if err != nil {
+		log.Fatalf("Failed to configure payment gateways: %v", err)
+	}
 
 	// Initialize use cases
 	userUseCase := impl.NewUserUseCase(repos.User, jwtService, emailService)
 	addressUseCase := impl.NewAddressUseCase(repos.Address)
-	productUseCase := impl.NewProductUseCase(repos.Product, repos.ProductImage, repos.ProductVariant, repos.Category)
-	categoryUseCase := impl.NewCategoryUseCase(repos.Category)
-	reviewUseCase := impl.NewReviewUseCase(repos.Review)
-	cartUseCase := impl.NewCartUseCase(repos.Cart, repos.Product, repos.ProductVariant)
+	flashSaleUseCase := impl.NewFlashSaleUseCase(
+		repos.FlashSale,
+		persistence.NewFlashSaleQuotaRepository(redisClient),
+		repos.Product,
+		repos.ProductVariant,
+	)
+	productSearchUseCase := impl.NewProductSearchUseCase(repos.ProductSearch, repos.Attribute, flashSaleUseCase)
+	searchIndex, err := search.NewSearchIndex(
+		cfg.Search.Engine,
+		cfg.Search.MeilisearchURL,
+		cfg.Search.MeilisearchAPIKey,
+		cfg.Search.MeilisearchIndex,
+	)
+	if err != nil {
+		log.Fatalf("Failed to configure search index: %v", err)
+	}
+	var productSearcher usecase.ProductSearcher = productSearchUseCase
+	var searchIndexUseCase usecase.SearchIndexUseCase
+	if searchIndex != nil {
+		// Catalog changes only reach an external index through the sync job
+		if cfg.Search.SyncInterval <= 0 {
+			log.Fatalf("SEARCH_SYNC_INTERVAL must be positive with the %s search engine", searchIndex.Name())
+		}
+		searchIndexUseCase = impl.NewSearchIndexUseCase(repos.ProductSearch, searchIndex)
+		productSearcher = searchIndexUseCase
+	}
+	attributeUseCase := impl.NewAttributeUseCase(repos.Attribute, repos.CategoryTree)
+	variantOptionUseCase := impl.NewVariantOptionUseCase(repos.Product, repos.ProductVariant)
+	imageUseCase := impl.NewImageUseCase(imageProcessor, fileStorage, cfg.Images.MaxUploadSize, repos.ProductImage)
+	sizeChartUseCase := impl.NewSizeChartUseCase(repos.SizeChart, repos.BodyProfile, repos.Product, repos.ProductVariant, repos.CategoryTree, repos.Review)
+	productUseCase := impl.NewFlashSalePricedProductUseCase(
+		impl.NewAttributeValidatedProductUseCase(
+			impl.NewFullTextSearchProductUseCase(
+				impl.NewProcessedImageProductUseCase(
+					impl.NewProductUseCase(repos.Product, repos.ProductImage, repos.ProductVariant, repos.Category, repos.Tag, fileStorage),
+					imageUseCase,
+					repos.Product,
+					repos.ProductImage,
+				),
+				productSearcher,
+			),
+			attributeUseCase,
+		),
+		flashSaleUseCase,
+	)
+	categoryUseCase := impl.NewProcessedImageCategoryUseCase(impl.NewCategoryUseCase(repos.Category, repos.CategoryTree, fileStorage), imageUseCase, repos.Category)
+	categoryTreeUseCase := impl.NewCategoryTreeUseCase(repos.CategoryTree, repos.Category, repos.Product)
+	reviewUseCase := impl.NewReviewUseCase(repos.Review, repos.Order, imageUseCase)
+	promotionUseCase := impl.NewPromotionUseCase(repos.Promotion, repos.Cart, repos.Product, repos.Category, flashSaleUseCase)
+	cartUseCase := impl.NewCartUseCase(repos.Cart, repos.Product, repos.ProductVariant, promotionUseCase)
 	wishlistUseCase := impl.NewWishlistUseCase(repos.Wishlist, repos.Product)
-	orderUseCase := impl.NewOrderUseCase(
+	voucherUseCase := impl.NewVoucherUseCase(
+		repos.Voucher,
+		repos.Cart,
+		repos.Order,
+		repos.OrderItem,
+		repos.Payment,
+		repos.Product,
+		repos.Category,
+	)
+	loyaltyUseCase := impl.NewLoyaltyUseCase(
+		repos.Loyalty,
+		repos.Order,
+		repos.OrderItem,
+		repos.Payment,
+		repos.Product,
+		repos.Category,
+		impl.LoyaltyPolicy{
+			RupiahPerPoint: cfg.Loyalty.RupiahPerPoint,
+			PointValue:     cfg.Loyalty.PointValue,
+			ExpiryMonths:   cfg.Loyalty.ExpiryMonths,
+		},
+	)
+	walletUseCase := impl.NewWalletUseCase(
+		repos.Wallet,
+		repos.GiftCard,
+		repos.Order,
+		repos.Payment,
+	)
+	notificationUseCase := impl.NewNotificationUseCase(repos.Notification)
+	giftCardUseCase := impl.NewGiftCardUseCase(
+		repos.GiftCard,
+		repos.Wallet,
+		repos.Order,
+		repos.OrderStatusHistory,
+		notificationUseCase,
+		emailService,
+		impl.GiftCardPolicy{
+			MinAmount: cfg.GiftCard.MinAmount,
+			MaxAmount: cfg.GiftCard.MaxAmount,
+			Validity:  cfg.GiftCard.Validity,
+		},
+	)
+	paymentUseCase := impl.NewPaymentUseCase(
+		repos.Payment,
+		repos.PaymentRefund,
+		repos.PaymentEvent,
 		repos.Order,
 		repos.OrderItem,
+		repos.OrderStatusHistory,
+		repos.User,
+		repos.Wallet,
+		notificationUseCase,
+		voucherUseCase,
+		flashSaleUseCase,
+		loyaltyUseCase,
+		walletUseCase,
+		giftCardUseCase,
+		gatewayRegistry,
+		map[entity.PaymentMethod]time.Duration{
+			entity.PaymentMethodCreditCard:   cfg.PaymentExpiry.CreditCard,
+			entity.PaymentMethodBankTransfer: cfg.PaymentExpiry.BankTransfer,
+			entity.PaymentMethodEWallet:      cfg.PaymentExpiry.EWallet,
+			entity.PaymentMethodQRIS:         cfg.PaymentExpiry.QRIS,
+		},
+		cfg.PaymentGateway.CallbackURL,
+	)
+	orderUseCase := impl.NewOrderUseCase(
+		repos.Order,
+		repos.OrderStatusHistory,
 		repos.Cart,
 		repos.Product,
 		repos.ProductVariant,
 		repos.Address,
+		voucherUseCase,
+		flashSaleUseCase,
+		promotionUseCase,
+		loyaltyUseCase,
+		walletUseCase,
+		paymentUseCase,
+		cfg.Shipping.FlatRate,
+	)
+	codUseCase := impl.NewCODUseCase(
+		repos.Payment,
+		repos.Order,
+		repos.OrderStatusHistory,
+		repos.CODRemittance,
+		voucherUseCase,
+		flashSaleUseCase,
+		loyaltyUseCase,
+		walletUseCase,
+		impl.CODPolicy{
+			Enabled:       cfg.COD.Enabled,
+			MaxOrderValue: cfg.COD.MaxOrderValue,
+			Cities:        cfg.COD.Cities,
+			Couriers:      cfg.COD.Couriers,
+		},
+	)
+	returnUseCase := impl.NewReturnUseCase(
+		repos.Return,
+		repos.Order,
+		repos.OrderItem,
+		repos.Payment,
+		repos.Voucher,
+		paymentUseCase,
+		notificationUseCase,
+		cfg.Returns.Window,
+	)
+	exchangeUseCase := impl.NewExchangeUseCase(
+		repos.Exchange,
+		repos.Order,
+		repos.OrderItem,
+		repos.OrderStatusHistory,
+		repos.Payment,
+		repos.Product,
+		repos.ProductVariant,
+		paymentUseCase,
+		notificationUseCase,
+		cfg.Returns.Window,
+		cfg.Returns.TopUpWindow,
+	)
+	reconciliationUseCase := impl.NewReconciliationUseCase(
+		repos.Payment,
+		repos.Order,
+		repos.Reconciliation,
+		gatewayRegistry,
 	)
-	paymentUseCase := impl.NewPaymentUseCase(repos.Payment, repos.Order, midtransService)
 	shippingUseCase := impl.NewShippingUseCase(rajaOngkirService)
-	notificationUseCase := impl.NewNotificationUseCase(repos.Notification)
+	shippingDocumentUseCase := impl.NewShippingDocumentUseCase(
+		repos.Order,
+		repos.OrderItem,
+		repos.ProductVariant,
+		shippingDocumentService,
+	)
+
+	// Register background jobs
+	jobs.Every("expire-unpaid-payments", cfg.PaymentExpiry.CheckInterval, func(ctx context.Context) error {
+		expired, err := paymentUseCase.ExpireOverduePayments(ctx)
+		if expired > 0 {
+			log.Printf("Expired %d unpaid payments", expired)
+		}
+		return err
+	})
+	jobs.Every("expire-unstarted-orders", cfg.PaymentExpiry.CheckInterval, func(ctx context.Context) error {
+		expired, err := paymentUseCase.ExpireUnstartedOrders(ctx)
+		if expired > 0 {
+			log.Printf("Cancelled %d orders whose payment was never started", expired)
+		}
+		return err
+	})
+	jobs.Every("release-cancelled-orders", cfg.PaymentExpiry.CheckInterval, func(ctx context.Context) error {
+		released, err := paymentUseCase.ReleaseCancelledOrders(ctx)
+		if released > 0 {
+			log.Printf("Released what %d cancelled orders still held", released)
+		}
+		return err
+	})
+	jobs.Every("cancel-unpaid-exchanges", cfg.Returns.CheckInterval, func(ctx context.Context) error {
+		cancelled, err := exchangeUseCase.CancelUnpaidExchanges(ctx)
+		if cancelled > 0 {
+			log.Printf("Cancelled %d exchanges whose price difference was not paid", cancelled)
+		}
+		return err
+	})
+	jobs.Every("sync-flash-sales", cfg.FlashSale.CheckInterval, func(ctx context.Context) error {
+		changed, err := flashSaleUseCase.SyncFlashSales(ctx)
+		if changed > 0 {
+			log.Printf("Started or ended %d flash sales", changed)
+		}
+		return err
+	})
+	jobs.Every("award-loyalty-points", cfg.Loyalty.CheckInterval, func(ctx context.Context) error {
+		awarded, err := loyaltyUseCase.AwardDeliveredOrders(ctx)
+		if awarded > 0 {
+			log.Printf("Awarded loyalty points for %d delivered orders", awarded)
+		}
+		return err
+	})
+	jobs.Every("expire-loyalty-points", cfg.Loyalty.CheckInterval, func(ctx context.Context) error {
+		expired, err := loyaltyUseCase.ExpirePoints(ctx)
+		if expired > 0 {
+			log.Printf("Expired %d loyalty point entries", expired)
+		}
+		return err
+	})
+	if searchIndexUseCase != nil {
+		// The in-memory index starts empty on every boot
+		if searchIndex.Name() == search.EngineMemory {
+			if _, err := searchIndexUseCase.Reindex(context.Background()); err != nil {
+				log.Printf("Failed to build the in-memory search index: %v", err)
+			}
+		}
+		jobs.Every("sync-search-index", cfg.Search.SyncInterval, func(ctx context.Context) error {
+			synced, err := searchIndexUseCase.SyncChanges(ctx)
+			if synced > 0 {
+				log.Printf("Synced %d products to the %s search index", synced, searchIndex.Name())
+			}
+			return err
+		})
+	}
+	if cfg.Reconciliation.Interval > 0 {
+		jobs.Every("reconcile-payments", cfg.Reconciliation.Interval, func(ctx context.Context) error {
+			run, err := reconciliationUseCase.RunScheduledStatusCheck(ctx)
+			if err != nil || run == nil {
+				return err
+			}
+			if discrepancies := run.TotalItems - run.MatchedItems; discrepancies > 0 {
+				log.Printf("Payment reconciliation %d found %d discrepancies", run.ID, discrepancies)
+			}
+			return nil
+		})
+	}
 
 	// Initialize handlers
 	userHandler := handler.NewUserHandler(userUseCase, addressUseCase)
 	productHandler := handler.NewProductHandler(productUseCase, categoryUseCase, reviewUseCase)
+	searchHandler := handler.NewSearchHandler(productSearchUseCase)
+	categoryTreeHandler := handler.NewCategoryTreeHandler(categoryTreeUseCase)
+	attributeHandler := handler.NewAttributeHandler(attributeUseCase)
+	variantOptionHandler := handler.NewVariantOptionHandler(variantOptionUseCase)
+	sizeChartHandler := handler.NewSizeChartHandler(sizeChartUseCase)
 	cartHandler := handler.NewCartHandler(cartUseCase)
 	wishlistHandler := handler.NewWishlistHandler(wishlistUseCase)
 	orderHandler := handler.NewOrderHandler(orderUseCase, paymentUseCase, shippingUseCase)
 	paymentHandler := handler.NewPaymentHandler(paymentUseCase)
+	codHandler := handler.NewCODHandler(codUseCase)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationUseCase)
+	returnHandler := handler.NewReturnHandler(returnUseCase)
+	exchangeHandler := handler.NewExchangeHandler(exchangeUseCase)
+	voucherHandler := handler.NewVoucherHandler(voucherUseCase)
+	flashSaleHandler := handler.NewFlashSaleHandler(flashSaleUseCase)
+	promotionHandler := handler.NewPromotionHandler(promotionUseCase)
+	loyaltyHandler := handler.NewLoyaltyHandler(loyaltyUseCase)
+	walletHandler := handler.NewWalletHandler(walletUseCase)
+	giftCardHandler := handler.NewGiftCardHandler(giftCardUseCase)
+	shippingDocumentHandler := handler.NewShippingDocumentHandler(shippingDocumentUseCase)
+	notificationHandler := handler.NewNotificationHandler(notificationUseCase)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
@@ -91,22 +416,37 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 		// Product routes
 		products := v1.Group("/products")
 		{
-			products.GET("", productHandler.ListProducts)
+			products.GET("", searchHandler.ListProducts)
 			products.GET("/search", productHandler.SearchProducts)
+			products.GET("/search/suggestions", searchHandler.GetSuggestions)
 			products.GET("/best-sellers", productHandler.GetBestSellers)
 			products.GET("/new-arrivals", productHandler.GetNewArrivals)
 			products.GET("/top-rated", productHandler.GetTopRated)
 			products.GET("/:id", productHandler.GetProductByID)
 			products.GET("/slug/:slug", productHandler.GetProductBySlug)
 			products.GET("/:id/reviews", productHandler.GetProductReviews)
+			products.GET("/:id/breadcrumbs", categoryTreeHandler.GetProductBreadcrumbs)
+			products.GET("/:id/options", variantOptionHandler.GetOptions)
+			products.GET("/:id/size-chart", sizeChartHandler.GetProductSizeChart)
 		}
 
 		// Category routes
 		categories := v1.Group("/categories")
 		{
 			categories.GET("", productHandler.ListCategories)
+			categories.GET("/tree", categoryTreeHandler.GetTree)
 			categories.GET("/:id", productHandler.GetCategoryByID)
 			categories.GET("/slug/:slug", productHandler.GetCategoryBySlug)
+			categories.GET("/:id/tree", categoryTreeHandler.GetSubtree)
+			categories.GET("/:id/breadcrumbs", categoryTreeHandler.GetBreadcrumbs)
+			categories.GET("/:id/attributes", attributeHandler.GetCategoryAttributes)
+		}
+
+		// Flash sale routes
+		flashSales := v1.Group("/flash-sales")
+		{
+			flashSales.GET("", flashSaleHandler.GetCurrentFlashSales)
+			flashSales.GET("/:id", flashSaleHandler.GetFlashSale)
 		}
 
 		// Shipping routes
@@ -116,8 +456,9 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			shipping.GET("/cities", orderHandler.GetCities)
 		}
 
-		// Payment webhook
+		// Payment webhooks
 		v1.POST(cfg.Midtrans.PaymentWebhook, paymentHandler.HandleWebhook)
+		v1.POST("/payments/webhook/:provider", paymentHandler.HandleWebhook)
 	}
 
 	// Protected routes (require authentication)
@@ -131,6 +472,8 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			user.PUT("/profile", userHandler.UpdateProfile)
 			user.PUT("/password", userHandler.ChangePassword)
 			user.POST("/logout", userHandler.Logout)
+			user.GET("/body-profile", sizeChartHandler.GetBodyProfile)
+			user.PUT("/body-profile", sizeChartHandler.SaveBodyProfile)
 
 			// Address routes
 			addresses := user.Group("/addresses")
@@ -145,6 +488,9 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			}
 		}
 
+		// Size recommendation
+		protected.GET("/products/:id/size-recommendation", sizeChartHandler.RecommendSize)
+
 		// Cart routes
 		cart := protected.Group("/cart")
 		{
@@ -153,6 +499,9 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			cart.PUT("/items/:id", cartHandler.UpdateCartItem)
 			cart.DELETE("/items/:id", cartHandler.RemoveFromCart)
 			cart.DELETE("", cartHandler.ClearCart)
+			cart.POST("/voucher", voucherHandler.ApplyToCart)
+			cart.DELETE("/voucher", voucherHandler.RemoveFromCart)
+			cart.GET("/promotions", promotionHandler.EvaluateCart)
 		}
 
 		// Wishlist routes
@@ -172,6 +521,43 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			orders.GET("/:id", orderHandler.GetOrderByID)
 			orders.GET("/number/:number", orderHandler.GetOrderByNumber)
 			orders.PUT("/:id/cancel", orderHandler.CancelOrder)
+			orders.POST("/:id/voucher", voucherHandler.RedeemForOrder)
+			orders.POST("/:id/loyalty-points", loyaltyHandler.RedeemForOrder)
+			orders.POST("/:id/wallet", walletHandler.ApplyToOrder)
+		}
+
+		// Loyalty points routes
+		protected.GET("/loyalty/points", loyaltyHandler.GetPoints)
+
+		// Store credit wallet routes
+		protected.GET("/wallet", walletHandler.GetWallet)
+
+		// Gift card routes
+		giftCards := protected.Group("/gift-cards")
+		{
+			giftCards.POST("", giftCardHandler.PurchaseGiftCard)
+			giftCards.GET("", giftCardHandler.GetUserGiftCards)
+			giftCards.POST("/redeem", giftCardHandler.RedeemGiftCard)
+		}
+
+		// Return routes
+		returns := protected.Group("/returns")
+		{
+			returns.POST("", returnHandler.CreateReturn)
+			returns.GET("", returnHandler.GetUserReturns)
+			returns.GET("/:id", returnHandler.GetReturnByID)
+			returns.PUT("/:id/cancel", returnHandler.CancelReturn)
+			returns.PUT("/:id/shipment", returnHandler.SubmitReturnShipment)
+		}
+
+		// Exchange routes
+		exchanges := protected.Group("/exchanges")
+		{
+			exchanges.POST("", exchangeHandler.CreateExchange)
+			exchanges.GET("", exchangeHandler.GetUserExchanges)
+			exchanges.GET("/:id", exchangeHandler.GetExchangeByID)
+			exchanges.PUT("/:id/cancel", exchangeHandler.CancelExchange)
+			exchanges.PUT("/:id/shipment", exchangeHandler.SubmitExchangeShipment)
 		}
 
 		// Payment routes
@@ -180,6 +566,10 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			payments.POST("", paymentHandler.ProcessPayment)
 			payments.GET("/:id", paymentHandler.GetPaymentByID)
 			payments.GET("/order/:order_id", paymentHandler.GetPaymentByOrderID)
+
+			// Cash on delivery
+			payments.GET("/cod/eligibility/:order_id", codHandler.CheckEligibility)
+			payments.POST("/cod", codHandler.PlaceCODPayment)
 		}
 
 		// Shipping calculation
@@ -196,6 +586,7 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			reviews.GET("/user", productHandler.GetUserReviews)
 			reviews.PUT("/:id", productHandler.UpdateReview)
 			reviews.DELETE("/:id", productHandler.DeleteReview)
+			reviews.PUT("/:id/fit", sizeChartHandler.SetReviewFit)
 		}
 
 		// Notification routes
@@ -220,6 +611,7 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			users.GET("/:id", userHandler.GetUserByID)
 			users.PUT("/:id/active", userHandler.ToggleUserActive)
 			users.PUT("/:id/reset-password", userHandler.ResetUserPassword)
+			users.GET("/:id/loyalty-points", loyaltyHandler.GetUserPoints)
 		}
 
 		// Product management
@@ -237,6 +629,7 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 
 			// Product variants
 			products.POST("/:id/variants", productHandler.AddVariant)
+			products.PUT("/:id/options", variantOptionHandler.GenerateVariants)
 			products.PUT("/variants/:id", productHandler.UpdateVariant)
 			products.DELETE("/variants/:id", productHandler.DeleteVariant)
 			products.PUT("/variants/:id/stock", productHandler.UpdateStock)
@@ -247,8 +640,27 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 		{
 			categories.POST("", productHandler.CreateCategory)
 			categories.PUT("/:id", productHandler.UpdateCategory)
-			categories.DELETE("/:id", productHandler.DeleteCategory)
+			categories.PUT("/:id/move", categoryTreeHandler.MoveCategory)
+			categories.DELETE("/:id", categoryTreeHandler.DeleteCategory)
 			categories.POST("/:id/image", productHandler.UploadCategoryImage)
+			categories.POST("/:id/attributes", attributeHandler.CreateAttribute)
+		}
+
+		// Product attribute management
+		attributes := admin.Group("/attributes")
+		{
+			attributes.PUT("/:id", attributeHandler.UpdateAttribute)
+			attributes.DELETE("/:id", attributeHandler.DeleteAttribute)
+		}
+
+		// Size chart management
+		sizeCharts := admin.Group("/size-charts")
+		{
+			sizeCharts.GET("", sizeChartHandler.ListSizeCharts)
+			sizeCharts.POST("", sizeChartHandler.CreateSizeChart)
+			sizeCharts.GET("/:id", sizeChartHandler.GetSizeChart)
+			sizeCharts.PUT("/:id", sizeChartHandler.UpdateSizeChart)
+			sizeCharts.DELETE("/:id", sizeChartHandler.DeleteSizeChart)
 		}
 
 		// Order management
@@ -258,6 +670,79 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 			orders.PUT("/:id/status", orderHandler.UpdateOrderStatus)
 			orders.PUT("/:id/shipping", orderHandler.UpdateShippingInfo)
 			orders.GET("/sales-report", orderHandler.GetSalesReport)
+
+			// Shipping documents
+			orders.GET("/:id/packing-slip", shippingDocumentHandler.GetPackingSlip)
+			orders.GET("/:id/shipping-label", shippingDocumentHandler.GetShippingLabel)
+			orders.POST("/shipping-labels", shippingDocumentHandler.GetBulkShippingLabels)
+		}
+
+		// Return management
+		returns := admin.Group("/returns")
+		{
+			returns.GET("", returnHandler.ListReturns)
+			returns.GET("/:id", returnHandler.GetReturn)
+			returns.POST("/:id/approve", returnHandler.ApproveReturn)
+			returns.POST("/:id/reject", returnHandler.RejectReturn)
+			returns.POST("/:id/receive", returnHandler.ReceiveReturn)
+			returns.POST("/:id/refund", returnHandler.RefundReturn)
+		}
+
+		// Exchange management
+		exchanges := admin.Group("/exchanges")
+		{
+			exchanges.GET("", exchangeHandler.ListExchanges)
+			exchanges.GET("/:id", exchangeHandler.GetExchange)
+			exchanges.POST("/:id/approve", exchangeHandler.ApproveExchange)
+			exchanges.POST("/:id/reject", exchangeHandler.RejectExchange)
+			exchanges.POST("/:id/receive", exchangeHandler.ReceiveExchange)
+			exchanges.POST("/:id/complete", exchangeHandler.CompleteExchange)
+		}
+
+		// Voucher management
+		vouchers := admin.Group("/vouchers")
+		{
+			vouchers.GET("", voucherHandler.ListVouchers)
+			vouchers.POST("", voucherHandler.CreateVoucher)
+			vouchers.GET("/:id", voucherHandler.GetVoucher)
+			vouchers.PUT("/:id", voucherHandler.UpdateVoucher)
+			vouchers.DELETE("/:id", voucherHandler.DeleteVoucher)
+		}
+
+		// Promotion management
+		promotions := admin.Group("/promotions")
+		{
+			promotions.GET("", promotionHandler.ListPromotions)
+			promotions.POST("", promotionHandler.CreatePromotion)
+			promotions.GET("/:id", promotionHandler.GetPromotion)
+			promotions.PUT("/:id", promotionHandler.UpdatePromotion)
+			promotions.DELETE("/:id", promotionHandler.DeletePromotion)
+		}
+
+		// Loyalty points management
+		loyalty := admin.Group("/loyalty")
+		{
+			loyalty.GET("/multipliers", loyaltyHandler.ListCategoryMultipliers)
+			loyalty.PUT("/multipliers/:category_id", loyaltyHandler.SetCategoryMultiplier)
+			loyalty.DELETE("/multipliers/:category_id", loyaltyHandler.DeleteCategoryMultiplier)
+		}
+
+		// Gift card management
+		giftCards := admin.Group("/gift-cards")
+		{
+			giftCards.GET("", giftCardHandler.ListGiftCards)
+			giftCards.POST("", giftCardHandler.IssueGiftCards)
+			giftCards.POST("/:id/disable", giftCardHandler.DisableGiftCard)
+		}
+
+		// Flash sale management
+		flashSales := admin.Group("/flash-sales")
+		{
+			flashSales.GET("", flashSaleHandler.ListFlashSales)
+			flashSales.POST("", flashSaleHandler.CreateFlashSale)
+			flashSales.GET("/:id", flashSaleHandler.GetFlashSale)
+			flashSales.PUT("/:id", flashSaleHandler.UpdateFlashSale)
+			flashSales.POST("/:id/cancel", flashSaleHandler.CancelFlashSale)
 		}
 
 		// Payment management
@@ -265,6 +750,26 @@ func RegisterRoutes(router *gin.Engine, repos *persistence.Repositories, redisCl
 		{
 			payments.GET("", paymentHandler.ListPayments)
 			payments.POST("/:id/refund", paymentHandler.RefundPayment)
+			payments.POST("/:id/approve", paymentHandler.ApprovePayment)
+			payments.POST("/:id/deny", paymentHandler.DenyPayment)
+
+			// Cash on delivery
+			payments.POST("/:id/cod-confirm", codHandler.ConfirmCODPayment)
+			payments.POST("/:id/cod-undelivered", codHandler.MarkCODUndelivered)
+			payments.POST("/cod/remittances", codHandler.ImportRemittanceReport)
+			payments.GET("/cod/remittances", codHandler.ListRemittanceBatches)
+			payments.GET("/cod/remittances/:id", codHandler.GetRemittanceBatch)
+
+			// Reconciliation against gateway settlement reports
+			payments.POST("/reconciliation/import", reconciliationHandler.ImportSettlementReport)
+			payments.POST("/reconciliation/status-check", reconciliationHandler.RunStatusCheck)
+			payments.GET("/reconciliation", reconciliationHandler.ListRuns)
+			payments.GET("/reconciliation/:id", reconciliationHandler.GetRun)
+
+			// Driving the fake gateway in development and tests
+			if fakeGateway != nil {
+				payments.POST("/fake/simulate", handler.NewFakeGatewayHandler(fakeGateway).Simulate)
+			}
 		}
 	}
 }
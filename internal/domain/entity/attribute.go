@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AttributeType represents the kind of value a product attribute holds
+type AttributeType string
+
+const (
+	AttributeTypeText        AttributeType = "text"
+	AttributeTypeNumber      AttributeType = "number"
+	AttributeTypeBoolean     AttributeType = "boolean"
+	AttributeTypeSelect      AttributeType = "select"       // one of the allowed values
+	AttributeTypeMultiSelect AttributeType = "multi_select" // any of the allowed values
+)
+
+// AttributeDefinition represents an attribute the products of a category and
+// its subcategories describe, such as material, fit or sleeve length
+type AttributeDefinition struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	CategoryID    uint          `gorm:"index;not null" json:"category_id"`
+	Code          string        `gorm:"type:varchar(50);not null" json:"code"` // key of the value in Product.Attributes
+	Name          string        `gorm:"type:varchar(100);not null" json:"name"`
+	Type          AttributeType `gorm:"type:varchar(20);not null" json:"type"`
+	AllowedValues StringList    `gorm:"type:jsonb;not null;default:'[]'" json:"allowed_values"` // select and multi_select only
+	Unit          string        `gorm:"type:varchar(20)" json:"unit,omitempty"`                 // number only, e.g. cm
+	Required      bool          `gorm:"default:false" json:"required"`
+	Filterable    bool          `gorm:"default:false" json:"filterable"` // offered as a storefront filter and facet
+	SortOrder     int           `gorm:"default:0" json:"sort_order"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// ProductAttributes holds the attribute values of a product keyed by attribute
+// code: a string, number, boolean or list of strings depending on the type
+type ProductAttributes map[string]interface{}
+
+// Value stores the attributes as a JSON object
+func (a ProductAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+// Scan reads the attributes from a JSON object
+func (a *ProductAttributes) Scan(value interface{}) error {
+	return scanJSON(value, a)
+}
+
+// ProductAttributeValue represents a product attribute value described by its definition
+type ProductAttributeValue struct {
+	Code  string        `json:"code"`
+	Name  string        `json:"name"`
+	Type  AttributeType `json:"type"`
+	Value interface{}   `json:"value"`
+	Unit  string        `json:"unit,omitempty"`
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Value stores the list as a JSON array
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	return string(data), err
+}
+
+// Scan reads the list from a JSON array
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSON(value, l)
+}
+
+// scanJSON decodes a JSON column value
+func scanJSON(value interface{}, target interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, target)
+	case string:
+		return json.Unmarshal([]byte(v), target)
+	default:
+		return errors.New("unsupported JSON column value")
+	}
+}
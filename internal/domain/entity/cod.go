@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+)
+
+// CODRemittanceStatus represents the matching outcome of a remittance line
+type CODRemittanceStatus string
+
+const (
+	CODRemittanceStatusMatched        CODRemittanceStatus = "matched"
+	CODRemittanceStatusAmountMismatch CODRemittanceStatus = "amount_mismatch"
+	CODRemittanceStatusUnmatched      CODRemittanceStatus = "unmatched" // no cash-on-delivery order found
+	CODRemittanceStatusDuplicate      CODRemittanceStatus = "duplicate" // payment was already settled
+)
+
+// CODRemittanceBatch represents an imported courier cash-on-delivery remittance report
+type CODRemittanceBatch struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	Courier        string          `gorm:"type:varchar(20);not null" json:"courier"`
+	Filename       string          `json:"filename"`
+	TotalRows      int             `gorm:"not null" json:"total_rows"`
+	MatchedRows    int             `gorm:"not null" json:"matched_rows"`
+	MismatchedRows int             `gorm:"not null" json:"mismatched_rows"`
+	UnmatchedRows  int             `gorm:"not null" json:"unmatched_rows"`
+	DuplicateRows  int             `gorm:"not null" json:"duplicate_rows"`
+	TotalAmount    float64         `gorm:"not null" json:"total_amount"`
+	ImportedBy     uint            `json:"imported_by"`
+	Lines          []CODRemittance `gorm:"foreignKey:BatchID" json:"lines,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// CODRemittance represents one line of a courier remittance report
+type CODRemittance struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	BatchID        uint                `gorm:"index;not null" json:"batch_id"`
+	TrackingNumber string              `gorm:"index" json:"tracking_number"`
+	OrderNumber    string              `json:"order_number,omitempty"`
+	Amount         float64             `gorm:"not null" json:"amount"`
+	RemittedAt     *time.Time          `json:"remitted_at,omitempty"`
+	OrderID        *uint               `gorm:"index" json:"order_id,omitempty"`
+	PaymentID      *uint               `json:"payment_id,omitempty"`
+	Status         CODRemittanceStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Message        string              `json:"message,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+)
+
+// ExchangeStatus represents the status of an exchange request
+type ExchangeStatus string
+
+const (
+	ExchangeStatusRequested ExchangeStatus = "requested" // replacement stock is reserved
+	ExchangeStatusApproved  ExchangeStatus = "approved"  // replacement order was created
+	ExchangeStatusRejected  ExchangeStatus = "rejected"
+	ExchangeStatusCancelled ExchangeStatus = "cancelled"
+	ExchangeStatusShipped   ExchangeStatus = "shipped"   // customer sent the original items back
+	ExchangeStatusReceived  ExchangeStatus = "received"  // original items arrived and were restocked
+	ExchangeStatusCompleted ExchangeStatus = "completed" // any price difference was refunded
+)
+
+// ExchangeRequest represents a customer request to swap delivered items for
+// other variants of the same products
+type ExchangeRequest struct {
+	ID                   uint           `gorm:"primaryKey" json:"id"`
+	ExchangeNumber       string         `gorm:"uniqueIndex;not null" json:"exchange_number"`
+	OrderID              uint           `gorm:"index;not null" json:"order_id"`
+	UserID               uint           `gorm:"index;not null" json:"user_id"`
+	ReplacementOrderID   *uint          `json:"replacement_order_id,omitempty"`
+	Status               ExchangeStatus `gorm:"type:varchar(20);default:requested" json:"status"`
+	Reason               ReturnReason   `gorm:"type:varchar(20);not null" json:"reason"`
+	Description          string         `json:"description,omitempty"`
+	PriceDifference      float64        `gorm:"not null" json:"price_difference"` // positive is owed by the customer, negative is refunded to them
+	RefundedAmount       float64        `gorm:"default:0" json:"refunded_amount"`
+	ReturnCourier        string         `json:"return_courier,omitempty"`
+	ReturnTrackingNumber string         `json:"return_tracking_number,omitempty"`
+	AdminNote            string         `json:"admin_note,omitempty"`
+	Items                []ExchangeItem `gorm:"foreignKey:ExchangeRequestID" json:"items,omitempty"`
+	ApprovedAt           *time.Time     `json:"approved_at,omitempty"`
+	ShippedAt            *time.Time     `json:"shipped_at,omitempty"`
+	ReceivedAt           *time.Time     `json:"received_at,omitempty"`
+	CompletedAt          *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+}
+
+// ExchangeItem represents an order item, or part of its quantity, swapped for another variant
+type ExchangeItem struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ExchangeRequestID uint      `gorm:"index;not null" json:"exchange_request_id"`
+	OrderItemID       uint      `gorm:"index;not null" json:"order_item_id"`
+	ProductID         uint      `gorm:"not null" json:"product_id"`
+	ProductName       string    `json:"product_name"`
+	OldVariantID      uint      `gorm:"not null" json:"old_variant_id"`
+	NewVariantID      uint      `gorm:"not null" json:"new_variant_id"`
+	NewVariantInfo    string    `json:"new_variant_info"` // JSON string containing size and color
+	Quantity          int       `gorm:"not null" json:"quantity"`
+	PaidUnitPrice     float64   `gorm:"not null" json:"paid_unit_price"`
+	NewUnitPrice      float64   `gorm:"not null" json:"new_unit_price"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+)
+
+// FlashSaleStatus represents the state of a flash sale campaign
+type FlashSaleStatus string
+
+const (
+	FlashSaleStatusScheduled FlashSaleStatus = "scheduled"
+	FlashSaleStatusActive    FlashSaleStatus = "active"
+	FlashSaleStatusEnded     FlashSaleStatus = "ended"
+	FlashSaleStatusCancelled FlashSaleStatus = "cancelled"
+)
+
+// FlashSale represents a time-boxed campaign selling variants at a sale price
+type FlashSale struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Name        string          `gorm:"not null" json:"name"`
+	Description string          `json:"description,omitempty"`
+	StartsAt    time.Time       `gorm:"index;not null" json:"starts_at"`
+	EndsAt      time.Time       `gorm:"index;not null" json:"ends_at"`
+	Status      FlashSaleStatus `gorm:"type:varchar(20);not null;default:'scheduled'" json:"status"`
+	Items       []FlashSaleItem `gorm:"foreignKey:FlashSaleID" json:"items,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// IsLive reports whether the campaign is selling at the given time
+func (f *FlashSale) IsLive(at time.Time) bool {
+	return f.Status == FlashSaleStatusActive && !at.Before(f.StartsAt) && at.Before(f.EndsAt)
+}
+
+// FlashSaleItem represents a variant on sale in a campaign with a limited quota
+type FlashSaleItem struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	FlashSaleID  uint       `gorm:"index;not null" json:"flash_sale_id"`
+	FlashSale    *FlashSale `gorm:"foreignKey:FlashSaleID" json:"flash_sale,omitempty"`
+	ProductID    uint       `gorm:"index;not null" json:"product_id"`
+	VariantID    uint       `gorm:"index;not null" json:"variant_id"`
+	SalePrice    float64    `gorm:"not null" json:"sale_price"`
+	Quota        int        `gorm:"not null" json:"quota"`
+	Sold         int        `gorm:"default:0" json:"sold"`
+	PerUserLimit int        `gorm:"default:0" json:"per_user_limit"` // zero means unlimited
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// FlashSalePurchase represents flash sale quota taken by an order
+type FlashSalePurchase struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	FlashSaleItemID uint       `gorm:"index;not null" json:"flash_sale_item_id"`
+	UserID          uint       `gorm:"index;not null" json:"user_id"`
+	OrderID         uint       `gorm:"index;not null" json:"order_id"`
+	Quantity        int        `gorm:"not null" json:"quantity"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty"` // set when the order was cancelled and the quota given back
+	CreatedAt       time.Time  `json:"created_at"`
+}
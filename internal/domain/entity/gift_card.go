@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+)
+
+// GiftCardStatus represents the status of a gift card
+type GiftCardStatus string
+
+const (
+	GiftCardStatusPending   GiftCardStatus = "pending"   // bought, awaiting payment
+	GiftCardStatusActive    GiftCardStatus = "active"    // can be redeemed
+	GiftCardStatusRedeemed  GiftCardStatus = "redeemed"  // balance moved into a wallet
+	GiftCardStatusCancelled GiftCardStatus = "cancelled" // never paid for, or disabled by an admin
+)
+
+// GiftCard represents a digital gift card. Its balance is redeemed into the
+// store credit wallet of whoever enters the code.
+type GiftCard struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Code           string         `gorm:"uniqueIndex;not null" json:"code"`
+	Amount         float64        `gorm:"not null" json:"amount"`
+	Balance        float64        `gorm:"not null" json:"balance"`
+	Status         GiftCardStatus `gorm:"type:varchar(20);default:pending" json:"status"`
+	ExpiresAt      time.Time      `gorm:"not null" json:"expires_at"`
+	PurchaserID    *uint          `gorm:"index" json:"purchaser_id,omitempty"` // empty on cards issued by an admin
+	OrderID        *uint          `gorm:"index" json:"order_id,omitempty"`     // the order that paid for the card
+	RecipientName  string         `json:"recipient_name,omitempty"`
+	RecipientEmail string         `json:"recipient_email,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	RedeemedBy     *uint          `json:"redeemed_by,omitempty"`
+	RedeemedAt     *time.Time     `json:"redeemed_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// IsRedeemable reports whether the card can be redeemed at the given time
+func (g *GiftCard) IsRedeemable(at time.Time) bool {
+	return g.Status == GiftCardStatusActive && g.Balance > 0 && at.Before(g.ExpiresAt)
+}
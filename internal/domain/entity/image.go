@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Image rendition sizes, smallest first
+const (
+	ImageSizeThumbnail = "thumbnail"
+	ImageSizeMedium    = "medium"
+	ImageSizeLarge     = "large"
+)
+
+// Image rendition formats
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatPNG  = "png" // used instead of JPEG for images with transparency
+	ImageFormatWebP = "webp"
+)
+
+// ImageRendition represents one stored size and format of an uploaded image
+type ImageRendition struct {
+	Size   string `json:"size"`
+	Format string `json:"format"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Bytes  int    `json:"bytes"`
+}
+
+// ImageRenditions is a list of image renditions stored as a JSON array
+type ImageRenditions []ImageRendition
+
+// Value stores the renditions as a JSON array
+func (r ImageRenditions) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(r)
+	return string(data), err
+}
+
+// Scan reads the renditions from a JSON array
+func (r *ImageRenditions) Scan(value interface{}) error {
+	return scanJSON(value, r)
+}
+
+// URL returns the URL of a rendition, or an empty string when there is none
+func (r ImageRenditions) URL(size, format string) string {
+	for _, rendition := range r {
+		if rendition.Size == size && rendition.Format == format {
+			return rendition.URL
+		}
+	}
+	return ""
+}
+
+// SrcSets builds the srcset attribute value of every format the renditions
+// exist in, e.g. "https://cdn/thumbnail.webp 200w, https://cdn/medium.webp 800w"
+func (r ImageRenditions) SrcSets() ImageSrcSet {
+	candidates := make(map[string][]string)
+	for _, rendition := range r {
+		candidates[rendition.Format] = append(candidates[rendition.Format], fmt.Sprintf("%s %dw", rendition.URL, rendition.Width))
+	}
+
+	srcSet := make(ImageSrcSet, len(candidates))
+	for format, values := range candidates {
+		srcSet[format] = strings.Join(values, ", ")
+	}
+	return srcSet
+}
+
+// ImageSrcSet maps an image format to its srcset attribute value, stored as a JSON object
+type ImageSrcSet map[string]string
+
+// Value stores the srcset as a JSON object
+func (s ImageSrcSet) Value() (driver.Value, error) {
+	if s == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+// Scan reads the srcset from a JSON object
+func (s *ImageSrcSet) Scan(value interface{}) error {
+	return scanJSON(value, s)
+}
+
+// StoredImage represents an uploaded image after its renditions were stored
+type StoredImage struct {
+	Hash       string // SHA-256 of the uploaded file, renditions are shared by uploads with the same content
+	Width      int    // after applying the EXIF orientation
+	Height     int
+	URL        string // large rendition in JPEG, or PNG with transparency
+	Renditions ImageRenditions
+	SrcSet     ImageSrcSet
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+)
+
+// LoyaltyTransactionType represents why a customer's points changed
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyTransactionEarn     LoyaltyTransactionType = "earn"     // earned on a delivered order
+	LoyaltyTransactionRedeem   LoyaltyTransactionType = "redeem"   // spent as a discount on an order
+	LoyaltyTransactionRestore  LoyaltyTransactionType = "restore"  // given back when the order they were spent on was cancelled
+	LoyaltyTransactionExpire   LoyaltyTransactionType = "expire"   // not spent before they expired
+	LoyaltyTransactionClawback LoyaltyTransactionType = "clawback" // taken back when the order they were earned on was refunded
+)
+
+// LoyaltyAccount represents a customer's points balance
+type LoyaltyAccount struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	Balance        int       `gorm:"default:0" json:"balance"` // can go negative when refunded points were already spent
+	LifetimeEarned int       `gorm:"default:0" json:"lifetime_earned"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// LoyaltyTransaction represents an entry in a customer's points ledger.
+// Entries that add points are spent oldest-expiry first; Remaining tracks what
+// is left of them.
+type LoyaltyTransaction struct {
+	ID          uint                   `gorm:"primaryKey" json:"id"`
+	UserID      uint                   `gorm:"index;not null" json:"user_id"`
+	OrderID     *uint                  `gorm:"index" json:"order_id,omitempty"`
+	Type        LoyaltyTransactionType `gorm:"type:varchar(20);not null" json:"type"`
+	Points      int                    `gorm:"not null" json:"points"` // negative when points are taken away
+	Remaining   int                    `gorm:"default:0" json:"-"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	Description string                 `json:"description"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// LoyaltyLotUsage records how many points a debit took from an entry that
+// added them, so a cancelled redemption can give them back with the expiry
+// they had
+type LoyaltyLotUsage struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	DebitID   uint      `gorm:"index;not null" json:"debit_id"`
+	LotID     uint      `gorm:"not null" json:"lot_id"`
+	Points    int       `gorm:"not null" json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoyaltyCategoryMultiplier represents a points multiplier for products in a category
+type LoyaltyCategoryMultiplier struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CategoryID uint      `gorm:"uniqueIndex;not null" json:"category_id"`
+	Multiplier float64   `gorm:"not null" json:"multiplier"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
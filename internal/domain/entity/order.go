@@ -22,11 +22,14 @@ const (
 type PaymentStatus string
 
 const (
-	PaymentStatusPending  PaymentStatus = "pending"
-	PaymentStatusPaid     PaymentStatus = "paid"
-	PaymentStatusFailed   PaymentStatus = "failed"
-	PaymentStatusRefunded PaymentStatus = "refunded"
-	PaymentStatusExpired  PaymentStatus = "expired"
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusChallenge         PaymentStatus = "challenge" // flagged by fraud detection, awaiting admin review
+	PaymentStatusPaid              PaymentStatus = "paid"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusCancelled         PaymentStatus = "cancelled"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+	PaymentStatusExpired           PaymentStatus = "expired"
 )
 
 // PaymentMethod represents the payment method
@@ -36,30 +39,77 @@ const (
 	PaymentMethodCreditCard   PaymentMethod = "credit_card"
 	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
 	PaymentMethodEWallet      PaymentMethod = "e_wallet"
+	PaymentMethodQRIS         PaymentMethod = "qris"
 	PaymentMethodCOD          PaymentMethod = "cod"
+	PaymentMethodWallet       PaymentMethod = "wallet" // paid in full with store credit
 )
 
 // Order represents an order in the system
 type Order struct {
-	ID                     uint           `gorm:"primaryKey" json:"id"`
-	UserID                 uint           `gorm:"index;not null" json:"user_id"`
-	User                   User           `gorm:"foreignKey:UserID" json:"-"`
-	OrderNumber            string         `gorm:"uniqueIndex;not null" json:"order_number"`
-	Status                 OrderStatus    `gorm:"type:varchar(20);default:pending" json:"status"`
-	TotalAmount            float64        `gorm:"not null" json:"total_amount"`
-	ShippingCost           float64        `gorm:"not null" json:"shipping_cost"`
-	DiscountAmount         float64        `gorm:"default:0" json:"discount_amount"`
-	FinalAmount            float64        `gorm:"not null" json:"final_amount"`
-	ShippingAddress        Address        `gorm:"embedded" json:"shipping_address"`
-	ShippingMethod         string         `json:"shipping_method"`
-	ShippingTrackingNumber string         `json:"shipping_tracking_number,omitempty"`
-	PaymentID              *uint          `json:"payment_id,omitempty"`
-	Payment                *Payment       `gorm:"foreignKey:PaymentID" json:"payment,omitempty"`
-	OrderItems             []OrderItem    `gorm:"foreignKey:OrderID" json:"order_items,omitempty"`
-	Notes                  string         `json:"notes,omitempty"`
-	CreatedAt              time.Time      `json:"created_at"`
-	UpdatedAt              time.Time      `json:"updated_at"`
-	DeletedAt              gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                     uint                 `gorm:"primaryKey" json:"id"`
+	UserID                 uint                 `gorm:"index;not null" json:"user_id"`
+	User                   User                 `gorm:"foreignKey:UserID" json:"-"`
+	OrderNumber            string               `gorm:"uniqueIndex;not null" json:"order_number"`
+	Status                 OrderStatus          `gorm:"type:varchar(20);default:pending" json:"status"`
+	TotalAmount            float64              `gorm:"not null" json:"total_amount"`
+	ShippingCost           float64              `gorm:"not null" json:"shipping_cost"`
+	DiscountAmount         float64              `gorm:"default:0" json:"discount_amount"`
+	VoucherCode            string               `json:"voucher_code,omitempty"`
+	LoyaltyPointsRedeemed  int                  `gorm:"default:0" json:"loyalty_points_redeemed,omitempty"`
+	FinalAmount            float64              `gorm:"not null" json:"final_amount"`
+	WalletAmount           float64              `gorm:"default:0" json:"wallet_amount,omitempty"` // part of FinalAmount paid with store credit
+	ShippingAddress        ShippingAddress      `gorm:"embedded;embeddedPrefix:shipping_address_" json:"shipping_address"`
+	ShippingMethod         string               `json:"shipping_method"`
+	ShippingTrackingNumber string               `json:"shipping_tracking_number,omitempty"`
+	PaymentID              *uint                `json:"payment_id,omitempty"`
+	Payment                *Payment             `gorm:"foreignKey:PaymentID" json:"payment,omitempty"`
+	OrderItems             []OrderItem          `gorm:"foreignKey:OrderID" json:"order_items,omitempty"`
+	Promotions             []OrderPromotion     `gorm:"foreignKey:OrderID" json:"promotions,omitempty"`
+	StatusHistory          []OrderStatusHistory `gorm:"foreignKey:OrderID" json:"status_history,omitempty"`
+	Notes                  string               `json:"notes,omitempty"`
+	OriginalOrderID        *uint                `gorm:"index" json:"original_order_id,omitempty"` // set on replacement orders created by an exchange
+	DeliveredAt            *time.Time           `json:"delivered_at,omitempty"`
+	HoldsReleasedAt        *time.Time           `json:"-"` // set once a cancelled order has given back what it held
+	CreatedAt              time.Time            `json:"created_at"`
+	UpdatedAt              time.Time            `json:"updated_at"`
+	DeletedAt              gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+// ShippingAddress is the address an order ships to, copied from one of the customer's addresses
+type ShippingAddress struct {
+	Recipient   string `gorm:"not null" json:"recipient"`
+	Phone       string `gorm:"not null" json:"phone"`
+	Province    string `gorm:"not null" json:"province"`
+	City        string `gorm:"not null" json:"city"`
+	District    string `gorm:"not null" json:"district"`
+	PostalCode  string `gorm:"not null" json:"postal_code"`
+	FullAddress string `gorm:"not null" json:"full_address"`
+}
+
+// OrderStatusActor represents who changed the status of an order
+type OrderStatusActor string
+
+const (
+	OrderStatusActorCustomer OrderStatusActor = "customer"
+	OrderStatusActorAdmin    OrderStatusActor = "admin"
+	OrderStatusActorSystem   OrderStatusActor = "system" // payment notifications and background jobs
+)
+
+// OrderStatusHistory represents a change of an order's status
+type OrderStatusHistory struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	OrderID    uint             `gorm:"index;not null" json:"order_id"`
+	FromStatus OrderStatus      `gorm:"type:varchar(20)" json:"from_status,omitempty"` // empty when the order was created
+	ToStatus   OrderStatus      `gorm:"type:varchar(20);not null" json:"to_status"`
+	ActorType  OrderStatusActor `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *uint            `json:"actor_id,omitempty"`
+	Note       string           `json:"note,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// TableName returns the table name of the order status history
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
 }
 
 // OrderItem represents an item in an order
@@ -72,35 +122,119 @@ type OrderItem struct {
 	VariantInfo   string    `json:"variant_info"` // JSON string containing size, color, etc.
 	Quantity      int       `gorm:"not null" json:"quantity"`
 	Price         float64   `gorm:"not null" json:"price"`
-	DiscountPrice float64   `gorm:"default:0" json:"discount_price"`
-	FinalPrice    float64   `gorm:"not null" json:"final_price"`
+	DiscountPrice float64   `gorm:"default:0" json:"discount_price"` // promotion discount per unit
+	FinalPrice    float64   `gorm:"not null" json:"final_price"`     // price paid per unit
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // Payment represents a payment for an order
 type Payment struct {
-	ID            uint          `gorm:"primaryKey" json:"id"`
-	OrderID       uint          `gorm:"uniqueIndex;not null" json:"order_id"`
-	PaymentMethod PaymentMethod `gorm:"type:varchar(20);not null" json:"payment_method"`
-	Amount        float64       `gorm:"not null" json:"amount"`
-	Status        PaymentStatus `gorm:"type:varchar(20);default:pending" json:"status"`
-	TransactionID string        `json:"transaction_id,omitempty"`
-	PaymentURL    string        `json:"payment_url,omitempty"`
-	PaidAt        *time.Time    `json:"paid_at,omitempty"`
-	ExpiredAt     *time.Time    `json:"expired_at,omitempty"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	OrderID        uint            `gorm:"uniqueIndex;not null" json:"order_id"`
+	PaymentMethod  PaymentMethod   `gorm:"type:varchar(20);not null" json:"payment_method"`
+	Provider       string          `gorm:"type:varchar(20);not null;default:'midtrans'" json:"provider"`
+	Amount         float64         `gorm:"not null" json:"amount"` // part charged to the payment method
+	RefundedAmount float64         `gorm:"default:0" json:"refunded_amount"`
+	WalletAmount   float64         `gorm:"default:0" json:"wallet_amount"`   // part paid with store credit
+	WalletRefunded float64         `gorm:"default:0" json:"wallet_refunded"` // refunds paid into the wallet
+	Status         PaymentStatus   `gorm:"type:varchar(20);default:pending" json:"status"`
+	TransactionID  string          `json:"transaction_id,omitempty"`
+	GatewayOrderID string          `gorm:"type:varchar(60)" json:"gateway_order_id,omitempty"` // order_id of the current attempt at the gateway
+	Attempts       int             `gorm:"not null" json:"attempts"`                           // charges created; from the second one the gateway order_id is suffixed -N
+	PaymentURL     string          `json:"payment_url,omitempty"`
+	Channel        string          `gorm:"type:varchar(20)" json:"channel,omitempty"` // bca, bni, bri, permata, gopay, shopeepay or qris for direct charges
+	VANumber       string          `json:"va_number,omitempty"`
+	QRString       string          `json:"qr_string,omitempty"`
+	QRCodeURL      string          `json:"qr_code_url,omitempty"`
+	DeeplinkURL    string          `json:"deeplink_url,omitempty"`
+	PaidAt         *time.Time      `json:"paid_at,omitempty"`
+	ExpiredAt      *time.Time      `json:"expired_at,omitempty"`
+	Refunds        []PaymentRefund `gorm:"foreignKey:PaymentID" json:"refunds,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// PaidAmount is what was paid for an order, through the payment method and with store credit
+func (p *Payment) PaidAmount() float64 {
+	return p.Amount + p.WalletAmount
+}
+
+// RefundedTotal is what was refunded of a payment, to the payment method and into the wallet
+func (p *Payment) RefundedTotal() float64 {
+	return p.RefundedAmount + p.WalletRefunded
+}
+
+// RefundStatus derives the refund status from the refunded total of a payment
+func (p *Payment) RefundStatus() PaymentStatus {
+	if p.RefundedTotal() >= p.PaidAmount()-0.01 {
+		return PaymentStatusRefunded
+	}
+	return PaymentStatusPartiallyRefunded
+}
+
+// PaymentRefundStatus represents the status of a payment refund
+type PaymentRefundStatus string
+
+const (
+	PaymentRefundStatusPending   PaymentRefundStatus = "pending" // recorded, not yet confirmed by the gateway
+	PaymentRefundStatusSucceeded PaymentRefundStatus = "succeeded"
+	PaymentRefundStatusFailed    PaymentRefundStatus = "failed"
+)
+
+// PaymentRefund represents a full or partial refund of a payment
+type PaymentRefund struct {
+	ID        uint                `gorm:"primaryKey" json:"id"`
+	PaymentID uint                `gorm:"index;not null" json:"payment_id"`
+	RefundKey string              `gorm:"uniqueIndex;not null" json:"refund_key"`
+	Amount    float64             `gorm:"not null" json:"amount"`
+	Reason    string              `json:"reason,omitempty"`
+	Source    string              `gorm:"type:varchar(20);not null" json:"source"` // "admin", "gateway" or "wallet" for refunds paid into store credit
+	Status    PaymentRefundStatus `gorm:"type:varchar(20);not null" json:"status"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// PaymentEventStatus represents the processing outcome of a payment notification
+type PaymentEventStatus string
+
+const (
+	PaymentEventStatusReceived  PaymentEventStatus = "received"
+	PaymentEventStatusProcessed PaymentEventStatus = "processed"
+	PaymentEventStatusIgnored   PaymentEventStatus = "ignored"
+	PaymentEventStatusRejected  PaymentEventStatus = "rejected"
+	PaymentEventStatusFailed    PaymentEventStatus = "failed"
+)
+
+// PaymentEvent represents a raw payment notification received from a payment gateway
+type PaymentEvent struct {
+	ID                uint               `gorm:"primaryKey" json:"id"`
+	Provider          string             `gorm:"type:varchar(20);not null" json:"provider"`
+	OrderNumber       string             `gorm:"index" json:"order_number"`
+	PaymentID         *uint              `gorm:"index" json:"payment_id,omitempty"`
+	TransactionID     string             `gorm:"index" json:"transaction_id"`
+	TransactionStatus string             `json:"transaction_status"`
+	FraudStatus       string             `json:"fraud_status,omitempty"`
+	StatusCode        string             `json:"status_code"`
+	GrossAmount       string             `json:"gross_amount"`
+	SignatureValid    bool               `gorm:"default:false" json:"signature_valid"`
+	Payload           string             `gorm:"type:jsonb;not null" json:"payload"` // raw notification body
+	Status            PaymentEventStatus `gorm:"type:varchar(20);default:received" json:"status"`
+	Message           string             `json:"message,omitempty"`
+	ProcessedAt       *time.Time         `json:"processed_at,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
 }
 
 // Cart represents a user's shopping cart
 type Cart struct {
-	ID        uint       `gorm:"primaryKey" json:"id"`
-	UserID    uint       `gorm:"uniqueIndex;not null" json:"user_id"`
-	User      User       `gorm:"foreignKey:UserID" json:"-"`
-	Items     []CartItem `gorm:"foreignKey:CartID" json:"items,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	User        User       `gorm:"foreignKey:UserID" json:"-"`
+	Items       []CartItem `gorm:"foreignKey:CartID" json:"items,omitempty"`
+	VoucherCode string     `json:"voucher_code,omitempty"` // applied at checkout
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // CartItem represents an item in a user's shopping cart
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+)
+
+// Cursor is a position in a list ordered by creation time, newest first
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// CursorPage selects a page of a list relative to a cursor. Without a cursor
+// the newest items are returned.
+type CursorPage struct {
+	After  *Cursor // items older than the cursor, the next page
+	Before *Cursor // items newer than the cursor, the previous page
+	Limit  int
+}
+
+// PageCursors holds the cursors of the pages around a page, nil where the list ends
+type PageCursors struct {
+	Next *Cursor
+	Prev *Cursor
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,58 +9,149 @@ import (
 
 // Category represents a product category
 type Category struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"not null" json:"name"`
-	Slug        string         `gorm:"uniqueIndex;not null" json:"slug"`
-	Description string         `json:"description"`
-	ParentID    *uint          `json:"parent_id,omitempty"`
-	Parent      *Category      `gorm:"foreignKey:ParentID" json:"-"`
-	Image       string         `json:"image,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	Name            string          `gorm:"not null" json:"name"`
+	Slug            string          `gorm:"uniqueIndex;not null" json:"slug"`
+	Description     string          `json:"description"`
+	ParentID        *uint           `json:"parent_id,omitempty"`
+	Parent          *Category       `gorm:"foreignKey:ParentID" json:"-"`
+	Path            string          `gorm:"->" json:"-"` // materialised path of ancestor IDs such as /1/4/9/, kept by the database
+	Depth           int             `gorm:"->" json:"depth"`
+	SortOrder       int             `gorm:"default:0" json:"sort_order"` // position among its siblings
+	Children        []*Category     `gorm:"-" json:"children,omitempty"`
+	Image           string          `json:"image,omitempty"`
+	ImageHash       string          `gorm:"type:varchar(64)" json:"-"` // SHA-256 of the uploaded image file
+	ImageRenditions ImageRenditions `gorm:"type:jsonb;not null;default:'[]'" json:"image_renditions,omitempty"`
+	ImageSrcSet     ImageSrcSet     `gorm:"type:jsonb;not null;default:'{}'" json:"image_srcset,omitempty"` // keyed by format
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt  `gorm:"index" json:"-"`
 }
 
+// CategoryDeleteRule decides what happens to the subcategories and products
+// of a deleted category
+type CategoryDeleteRule string
+
+const (
+	CategoryDeleteRestrict CategoryDeleteRule = "restrict" // refuse while it has subcategories or products
+	CategoryDeleteReparent CategoryDeleteRule = "reparent" // hand subcategories and products to its parent
+	CategoryDeleteCascade  CategoryDeleteRule = "cascade"  // delete the whole subtree and deactivate its products
+)
+
 // Product represents a product in the system
 type Product struct {
-	ID            uint             `gorm:"primaryKey" json:"id"`
-	Name          string           `gorm:"not null" json:"name"`
-	Slug          string           `gorm:"uniqueIndex;not null" json:"slug"`
-	Description   string           `json:"description"`
-	Price         float64          `gorm:"not null" json:"price"`
-	DiscountPrice *float64         `json:"discount_price,omitempty"`
-	CategoryID    uint             `gorm:"index;not null" json:"category_id"`
-	Category      Category         `gorm:"foreignKey:CategoryID" json:"-"`
-	Images        []ProductImage   `gorm:"foreignKey:ProductID" json:"images,omitempty"`
-	Variants      []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
-	Tags          []Tag            `gorm:"many2many:product_tags;" json:"tags,omitempty"`
-	IsActive      bool             `gorm:"default:true" json:"is_active"`
-	CreatedAt     time.Time        `json:"created_at"`
-	UpdatedAt     time.Time        `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt   `gorm:"index" json:"-"`
+	ID              uint                    `gorm:"primaryKey" json:"id"`
+	Name            string                  `gorm:"not null" json:"name"`
+	Slug            string                  `gorm:"uniqueIndex;not null" json:"slug"`
+	Description     string                  `json:"description"`
+	Price           float64                 `gorm:"not null" json:"price"`
+	DiscountPrice   *float64                `json:"discount_price,omitempty"`
+	CategoryID      uint                    `gorm:"index;not null" json:"category_id"`
+	Category        Category                `gorm:"foreignKey:CategoryID" json:"-"`
+	Images          []ProductImage          `gorm:"foreignKey:ProductID" json:"images,omitempty"`
+	Variants        []ProductVariant        `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
+	Options         []ProductOption         `gorm:"foreignKey:ProductID" json:"options,omitempty"`
+	SKUTemplate     string                  `gorm:"type:varchar(100)" json:"sku_template,omitempty"` // SKU pattern of generated variants
+	Tags            []Tag                   `gorm:"many2many:product_tags;" json:"tags,omitempty"`
+	Attributes      ProductAttributes       `gorm:"type:jsonb;not null;default:'{}'" json:"attributes"`
+	AttributeValues []ProductAttributeValue `gorm:"-" json:"attribute_values,omitempty"` // attributes with their names, on product detail
+	IsActive        bool                    `gorm:"default:true" json:"is_active"`
+	EffectivePrice  float64                 `gorm:"-" json:"effective_price"` // lowest price a customer pays now, including flash sales
+	FlashSaleEndsAt *time.Time              `gorm:"-" json:"flash_sale_ends_at,omitempty"`
+	Highlight       *SearchHighlight        `gorm:"-" json:"highlight,omitempty"` // matched terms of a search result
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt          `gorm:"index" json:"-"`
+}
+
+// SellingPrice returns the price the product sells for outside flash sales:
+// its discount price when that is lower than the regular price
+func (p *Product) SellingPrice() float64 {
+	if p.DiscountPrice != nil && *p.DiscountPrice > 0 && *p.DiscountPrice < p.Price {
+		return *p.DiscountPrice
+	}
+	return p.Price
+}
+
+// AfterFind starts the effective price of a loaded product at its selling
+// price; running flash sales are applied on top where products are shown
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	p.EffectivePrice = p.SellingPrice()
+	return nil
 }
 
 // ProductImage represents a product image
 type ProductImage struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	ProductID uint      `gorm:"index;not null" json:"product_id"`
-	URL       string    `gorm:"not null" json:"url"`
-	IsPrimary bool      `gorm:"default:false" json:"is_primary"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	ProductID   uint            `gorm:"index;not null" json:"product_id"`
+	URL         string          `gorm:"not null" json:"url"` // large rendition in JPEG, or PNG with transparency
+	IsPrimary   bool            `gorm:"default:false" json:"is_primary"`
+	ContentHash string          `gorm:"type:varchar(64);index" json:"content_hash,omitempty"` // SHA-256 of the uploaded file
+	Width       int             `json:"width,omitempty"`                                      // of the upload after applying its EXIF orientation
+	Height      int             `json:"height,omitempty"`
+	Renditions  ImageRenditions `gorm:"type:jsonb;not null;default:'[]'" json:"renditions"`
+	SrcSet      ImageSrcSet     `gorm:"type:jsonb;not null;default:'{}'" json:"srcset"` // keyed by format
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
 }
 
-// ProductVariant represents a product variant (size, color, etc.)
+// ProductVariant represents a product variant, one combination of the product's option values
 type ProductVariant struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	ProductID uint      `gorm:"index;not null" json:"product_id"`
-	Size      string    `json:"size"`
-	Color     string    `json:"color"`
-	SKU       string    `gorm:"uniqueIndex;not null" json:"sku"`
-	Stock     int       `gorm:"not null" json:"stock"`
-	Weight    float64   `gorm:"not null" json:"weight"` // in grams
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint                 `gorm:"primaryKey" json:"id"`
+	ProductID    uint                 `gorm:"index;not null" json:"product_id"`
+	Size         string               `json:"size"`  // value of the Size option, kept for storefront filters
+	Color        string               `json:"color"` // value of the Color option, kept for storefront filters
+	OptionValues []ProductOptionValue `gorm:"many2many:product_variant_options;joinForeignKey:VariantID;joinReferences:OptionValueID" json:"option_values,omitempty"`
+	SKU          string               `gorm:"uniqueIndex;not null" json:"sku"`
+	Stock        int                  `gorm:"not null" json:"stock"`
+	Weight       float64              `gorm:"not null" json:"weight"`        // in grams
+	IsActive     bool                 `gorm:"default:true" json:"is_active"` // false once its combination is removed from the option matrix
+	FlashSale    *FlashSaleItem       `gorm:"-" json:"flash_sale,omitempty"` // live flash sale of the variant, if any
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// Options returns the option values of the variant keyed by lowercase option
+// name, or its size and color when the option values are not loaded
+func (v *ProductVariant) Options() map[string]string {
+	options := make(map[string]string, len(v.OptionValues))
+	for _, value := range v.OptionValues {
+		if value.Option != nil {
+			options[strings.ToLower(value.Option.Name)] = value.Value
+		}
+	}
+	if len(options) == 0 {
+		options["size"] = v.Size
+		options["color"] = v.Color
+	}
+	return options
+}
+
+// ProductOption represents an option a product varies by, such as size,
+// color, length, cup size or pack count
+type ProductOption struct {
+	ID        uint                 `gorm:"primaryKey" json:"id"`
+	ProductID uint                 `gorm:"index;not null" json:"product_id"`
+	Name      string               `gorm:"type:varchar(50);not null" json:"name"`
+	Position  int                  `gorm:"default:0" json:"position"`
+	Values    []ProductOptionValue `gorm:"foreignKey:OptionID" json:"values"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// ProductOptionValue represents a value of a product option
+type ProductOptionValue struct {
+	ID       uint           `gorm:"primaryKey" json:"id"`
+	OptionID uint           `gorm:"index;not null" json:"option_id"`
+	Option   *ProductOption `gorm:"foreignKey:OptionID" json:"-"`
+	Value    string         `gorm:"type:varchar(50);not null" json:"value"`
+	Position int            `gorm:"default:0" json:"position"`
+}
+
+// ProductVariantOption links a variant to one of its option values
+type ProductVariantOption struct {
+	VariantID     uint `gorm:"primaryKey"`
+	OptionValueID uint `gorm:"primaryKey"`
 }
 
 // Tag represents a product tag
@@ -72,15 +164,16 @@ type Tag struct {
 
 // Review represents a product review
 type Review struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	ProductID uint      `gorm:"index;not null" json:"product_id"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"-"`
-	UserID    uint      `gorm:"index;not null" json:"user_id"`
-	User      User      `gorm:"foreignKey:UserID" json:"-"`
-	OrderID   uint      `gorm:"index;not null" json:"order_id"`
-	Rating    int       `gorm:"not null" json:"rating"` // 1-5
-	Comment   string    `json:"comment"`
-	Images    []string  `gorm:"-" json:"images,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	ProductID uint        `gorm:"index;not null" json:"product_id"`
+	Product   Product     `gorm:"foreignKey:ProductID" json:"-"`
+	UserID    uint        `gorm:"index;not null" json:"user_id"`
+	User      User        `gorm:"foreignKey:UserID" json:"-"`
+	OrderID   uint        `gorm:"index;not null" json:"order_id"`
+	Rating    int         `gorm:"not null" json:"rating"` // 1-5
+	Comment   string      `json:"comment"`
+	Fit       FitFeedback `gorm:"type:varchar(20)" json:"fit,omitempty"` // how the size fit the reviewer
+	Images    []string    `gorm:"-" json:"images,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
 }
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PromotionType represents the kind of rule a promotion applies
+type PromotionType string
+
+const (
+	PromotionTypeBuyXGetY PromotionType = "buy_x_get_y" // buy BuyQuantity, get GetQuantity at GetDiscountPercent off
+	PromotionTypeBundle   PromotionType = "bundle"      // BundleItems together for BundlePrice
+	PromotionTypeTiered   PromotionType = "tiered"      // percentage off once spend reaches a tier
+)
+
+// Promotion represents an automatic cart promotion rule
+type Promotion struct {
+	ID                 uint                  `gorm:"primaryKey" json:"id"`
+	Name               string                `gorm:"not null" json:"name"`
+	Description        string                `json:"description,omitempty"`
+	Type               PromotionType         `gorm:"type:varchar(20);not null" json:"type"`
+	Priority           int                   `gorm:"default:0" json:"priority"` // lower runs first
+	StartsAt           time.Time             `gorm:"not null" json:"starts_at"`
+	EndsAt             time.Time             `gorm:"not null" json:"ends_at"`
+	IsActive           bool                  `gorm:"not null" json:"is_active"`
+	BuyQuantity        int                   `gorm:"default:0" json:"buy_quantity,omitempty"`
+	GetQuantity        int                   `gorm:"default:0" json:"get_quantity,omitempty"`
+	GetDiscountPercent float64               `gorm:"default:0" json:"get_discount_percent,omitempty"` // 100 means free
+	BundlePrice        float64               `gorm:"default:0" json:"bundle_price,omitempty"`
+	BundleItems        []PromotionBundleItem `gorm:"foreignKey:PromotionID" json:"bundle_items,omitempty"`
+	Tiers              []PromotionTier       `gorm:"foreignKey:PromotionID" json:"tiers,omitempty"`
+	Products           []Product             `gorm:"many2many:promotion_products;" json:"products,omitempty"`     // empty applies to every product
+	Categories         []Category            `gorm:"many2many:promotion_categories;" json:"categories,omitempty"` // empty applies to every category
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt        `gorm:"index" json:"-"`
+}
+
+// PromotionBundleItem represents a component of a bundle promotion, matched by product or category
+type PromotionBundleItem struct {
+	ID          uint  `gorm:"primaryKey" json:"id"`
+	PromotionID uint  `gorm:"index;not null" json:"promotion_id"`
+	ProductID   *uint `json:"product_id,omitempty"`
+	CategoryID  *uint `json:"category_id,omitempty"`
+	Quantity    int   `gorm:"not null" json:"quantity"`
+}
+
+// PromotionTier represents a spend threshold of a tiered promotion
+type PromotionTier struct {
+	ID              uint    `gorm:"primaryKey" json:"id"`
+	PromotionID     uint    `gorm:"index;not null" json:"promotion_id"`
+	MinSpend        float64 `gorm:"not null" json:"min_spend"`
+	DiscountPercent float64 `gorm:"not null" json:"discount_percent"`
+	MaxDiscount     float64 `gorm:"default:0" json:"max_discount"` // zero means no cap
+}
+
+// OrderPromotion represents a promotion applied to an order
+type OrderPromotion struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrderID        uint      `gorm:"index;not null" json:"order_id"`
+	PromotionID    uint      `gorm:"index;not null" json:"promotion_id"`
+	Name           string    `gorm:"not null" json:"name"`
+	DiscountAmount float64   `gorm:"not null" json:"discount_amount"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReconciliationSource represents where the gateway side of a reconciliation came from
+type ReconciliationSource string
+
+const (
+	ReconciliationSourceSettlementReport ReconciliationSource = "settlement_report" // imported settlement CSV
+	ReconciliationSourceStatusCheck      ReconciliationSource = "status_check"      // transaction status pulled from the gateway
+)
+
+// ReconciliationItemStatus represents the outcome of reconciling one transaction
+type ReconciliationItemStatus string
+
+const (
+	ReconciliationItemStatusMatched        ReconciliationItemStatus = "matched"
+	ReconciliationItemStatusAmountMismatch ReconciliationItemStatus = "amount_mismatch"
+	ReconciliationItemStatusStatusMismatch ReconciliationItemStatus = "status_mismatch"
+	ReconciliationItemStatusMissing        ReconciliationItemStatus = "missing" // paid locally but not settled at the gateway
+	ReconciliationItemStatusOrphan         ReconciliationItemStatus = "orphan"  // settled at the gateway without a matching payment
+)
+
+// ReconciliationRun represents one reconciliation of payments against a gateway
+type ReconciliationRun struct {
+	ID                 uint                 `gorm:"primaryKey" json:"id"`
+	Provider           string               `gorm:"type:varchar(20)" json:"provider,omitempty"` // empty for every gateway
+	Source             ReconciliationSource `gorm:"type:varchar(20);not null" json:"source"`
+	Filename           string               `json:"filename,omitempty"`
+	PeriodStart        time.Time            `gorm:"not null" json:"period_start"`
+	PeriodEnd          time.Time            `gorm:"not null" json:"period_end"`
+	TotalItems         int                  `gorm:"not null" json:"total_items"`
+	MatchedItems       int                  `gorm:"not null" json:"matched_items"`
+	AmountMismatches   int                  `gorm:"not null" json:"amount_mismatches"`
+	StatusMismatches   int                  `gorm:"not null" json:"status_mismatches"`
+	MissingPayments    int                  `gorm:"not null" json:"missing_payments"`
+	OrphanTransactions int                  `gorm:"not null" json:"orphan_transactions"`
+	CreatedBy          *uint                `json:"created_by,omitempty"` // empty for scheduled runs
+	Items              []ReconciliationItem `gorm:"foreignKey:RunID" json:"items,omitempty"`
+	CreatedAt          time.Time            `json:"created_at"`
+	UpdatedAt          time.Time            `json:"updated_at"`
+}
+
+// ReconciliationItem represents one reconciled transaction
+type ReconciliationItem struct {
+	ID            uint                     `gorm:"primaryKey" json:"id"`
+	RunID         uint                     `gorm:"index;not null" json:"run_id"`
+	PaymentID     *uint                    `gorm:"index" json:"payment_id,omitempty"`
+	OrderNumber   string                   `json:"order_number,omitempty"`
+	TransactionID string                   `json:"transaction_id,omitempty"`
+	LocalAmount   float64                  `json:"local_amount"`
+	GatewayAmount float64                  `json:"gateway_amount"`
+	LocalStatus   string                   `gorm:"type:varchar(20)" json:"local_status,omitempty"`
+	GatewayStatus string                   `gorm:"type:varchar(30)" json:"gateway_status,omitempty"`
+	Status        ReconciliationItemStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Message       string                   `json:"message,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+}
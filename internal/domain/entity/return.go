@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReturnStatus represents the status of a return request
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+	ReturnStatusShipped   ReturnStatus = "shipped"  // customer sent the items back
+	ReturnStatusReceived  ReturnStatus = "received" // items arrived at the warehouse and were restocked
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+	ReturnStatusCancelled ReturnStatus = "cancelled"
+)
+
+// ReturnReason represents why a customer returns items
+type ReturnReason string
+
+const (
+	ReturnReasonWrongSize      ReturnReason = "wrong_size"
+	ReturnReasonTooSmall       ReturnReason = "too_small" // wrong size, the item was smaller than expected
+	ReturnReasonTooLarge       ReturnReason = "too_large" // wrong size, the item was larger than expected
+	ReturnReasonDefective      ReturnReason = "defective"
+	ReturnReasonNotAsDescribed ReturnReason = "not_as_described"
+	ReturnReasonWrongItem      ReturnReason = "wrong_item"
+	ReturnReasonChangedMind    ReturnReason = "changed_mind"
+	ReturnReasonOther          ReturnReason = "other"
+)
+
+// ReturnRequest represents a customer request to return delivered items (RMA)
+type ReturnRequest struct {
+	ID                   uint          `gorm:"primaryKey" json:"id"`
+	RMANumber            string        `gorm:"uniqueIndex;not null" json:"rma_number"`
+	OrderID              uint          `gorm:"index;not null" json:"order_id"`
+	UserID               uint          `gorm:"index;not null" json:"user_id"`
+	Status               ReturnStatus  `gorm:"type:varchar(20);default:requested" json:"status"`
+	Reason               ReturnReason  `gorm:"type:varchar(20);not null" json:"reason"`
+	Description          string        `json:"description,omitempty"`
+	RefundAmount         float64       `gorm:"not null" json:"refund_amount"`
+	ReturnCourier        string        `json:"return_courier,omitempty"`
+	ReturnTrackingNumber string        `json:"return_tracking_number,omitempty"`
+	AdminNote            string        `json:"admin_note,omitempty"`
+	Items                []ReturnItem  `gorm:"foreignKey:ReturnRequestID" json:"items,omitempty"`
+	Photos               []ReturnPhoto `gorm:"foreignKey:ReturnRequestID" json:"photos,omitempty"`
+	ApprovedAt           *time.Time    `json:"approved_at,omitempty"`
+	ShippedAt            *time.Time    `json:"shipped_at,omitempty"`
+	ReceivedAt           *time.Time    `json:"received_at,omitempty"`
+	RefundedAt           *time.Time    `json:"refunded_at,omitempty"`
+	CreatedAt            time.Time     `json:"created_at"`
+	UpdatedAt            time.Time     `json:"updated_at"`
+}
+
+// ReturnItem represents an order item, or part of its quantity, being returned
+type ReturnItem struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ReturnRequestID uint      `gorm:"index;not null" json:"return_request_id"`
+	OrderItemID     uint      `gorm:"index;not null" json:"order_item_id"`
+	VariantID       uint      `json:"variant_id"`
+	ProductName     string    `json:"product_name"`
+	Quantity        int       `gorm:"not null" json:"quantity"`
+	RefundAmount    float64   `gorm:"not null" json:"refund_amount"` // unit final price times quantity
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ReturnPhoto represents a photo the customer attached to a return request
+type ReturnPhoto struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ReturnRequestID uint      `gorm:"index;not null" json:"return_request_id"`
+	URL             string    `gorm:"not null" json:"url"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"html"
+	"strings"
+	"time"
+)
+
+// SearchSuggestionType represents what a search suggestion points to
+type SearchSuggestionType string
+
+const (
+	SearchSuggestionProduct  SearchSuggestionType = "product"
+	SearchSuggestionCategory SearchSuggestionType = "category"
+	SearchSuggestionTag      SearchSuggestionType = "tag"
+)
+
+// SearchHighlight holds snippets of a search result with the matched terms
+// wrapped in <mark> tags. The rest of the text is HTML-escaped, so a snippet
+// can be shown as HTML as it is.
+type SearchHighlight struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Search engines wrap matched terms in these Unicode private use characters,
+// which product text does not show, so the text can be escaped before the
+// terms are marked
+const (
+	SearchMatchStart = "\uE000"
+	SearchMatchStop  = "\uE001"
+)
+
+// searchMarker turns escaped snippets with matched terms between
+// SearchMatchStart and SearchMatchStop into HTML
+var searchMarker = strings.NewReplacer(SearchMatchStart, "<mark>", SearchMatchStop, "</mark>")
+
+// SearchSnippet escapes the HTML in a snippet whose matched terms are wrapped
+// in SearchMatchStart and SearchMatchStop, then marks those terms with <mark>
+// tags. It returns "" when no term is matched.
+func SearchSnippet(snippet string) string {
+	if !strings.Contains(snippet, SearchMatchStart) {
+		return ""
+	}
+	return searchMarker.Replace(html.EscapeString(snippet))
+}
+
+// SearchSuggestion represents an autocomplete suggestion for the search box
+type SearchSuggestion struct {
+	Text string               `json:"text"`
+	Type SearchSuggestionType `json:"type"`
+	Slug string               `json:"slug,omitempty"` // product or category slug
+}
+
+// ProductFilter represents the storefront filters of a product listing.
+// Values within a filter are alternatives; different filters must all match.
+type ProductFilter struct {
+	Keyword    string              `json:"q,omitempty"`
+	CategoryID *uint               `json:"category_id,omitempty"` // includes its subcategories
+	MinPrice   *float64            `json:"min_price,omitempty"`   // on the effective price
+	MaxPrice   *float64            `json:"max_price,omitempty"`
+	Sizes      []string            `json:"sizes,omitempty"`
+	Colors     []string            `json:"colors,omitempty"`
+	Tags       []string            `json:"tags,omitempty"`
+	InStock    bool                `json:"in_stock,omitempty"`   // only products with a variant in stock
+	MinRating  *float64            `json:"min_rating,omitempty"` // average review rating
+	Attributes map[string][]string `json:"attributes,omitempty"` // filterable attribute values keyed by code
+}
+
+// FacetCount represents how many products match a filter value
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// PriceRange represents the lowest and highest effective price of matching products
+type PriceRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// ProductFacets represents the facet counts of a product listing. Each facet
+// is counted with every filter applied except its own, so the storefront can
+// offer the other values of a filter that is already selected.
+type ProductFacets struct {
+	Categories []FacetCount     `json:"categories"`
+	Sizes      []FacetCount     `json:"sizes"`
+	Colors     []FacetCount     `json:"colors"`
+	Tags       []FacetCount     `json:"tags"`
+	Ratings    []FacetCount     `json:"ratings"` // products rated at least the value
+	Price      PriceRange       `json:"price"`
+	InStock    int64            `json:"in_stock"`
+	Attributes []AttributeFacet `json:"attributes"`
+}
+
+// AttributeFacet represents the facet counts of a filterable product attribute
+type AttributeFacet struct {
+	Code   string       `json:"code"`
+	Name   string       `json:"name"`
+	Values []FacetCount `json:"values"`
+}
+
+// SearchIndexChange marks a product whose document must be sent to the
+// external search index
+type SearchIndexChange struct {
+	ProductID uint      `gorm:"primaryKey" json:"product_id"`
+	ChangedAt time.Time `gorm:"not null" json:"changed_at"`
+}
+
+// ProductSearchDocument represents a product as stored in the external search index
+type ProductSearchDocument struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
+	Description string   `json:"description"`
+	CategoryID  uint     `json:"category_id"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	Sizes       []string `json:"sizes"`
+	Colors      []string `json:"colors"`
+	Price       float64  `json:"price"` // discounted price, without flash sales
+	InStock     bool     `json:"in_stock"`
+	CreatedAt   int64    `json:"created_at"` // unix seconds, for sorting
+	IndexedAt   int64    `json:"indexed_at"` // unix milliseconds of the sync that wrote it
+}
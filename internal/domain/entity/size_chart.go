@@ -0,0 +1,141 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Body measurements a size chart or body profile holds, in cm
+const (
+	MeasurementHeight     = "height"
+	MeasurementChest      = "chest"
+	MeasurementWaist      = "waist"
+	MeasurementHips       = "hips"
+	MeasurementInseam     = "inseam"
+	MeasurementFootLength = "foot_length"
+)
+
+// FitFeedback represents how the size of a product fit a customer
+type FitFeedback string
+
+const (
+	FitFeedbackTooSmall   FitFeedback = "too_small"
+	FitFeedbackTrueToSize FitFeedback = "true_to_size"
+	FitFeedbackTooLarge   FitFeedback = "too_large"
+)
+
+// FitTendency represents how a product fits compared to its size chart
+type FitTendency string
+
+const (
+	FitTendencyRunsSmall  FitTendency = "runs_small"
+	FitTendencyTrueToSize FitTendency = "true_to_size"
+	FitTendencyRunsLarge  FitTendency = "runs_large"
+)
+
+// FitPreference represents how loosely a customer likes clothes to fit
+type FitPreference string
+
+const (
+	FitPreferenceSnug    FitPreference = "snug"
+	FitPreferenceRegular FitPreference = "regular"
+	FitPreferenceLoose   FitPreference = "loose"
+)
+
+// SizeChart represents the body measurements each size of a product, or of
+// the products in a category and its subcategories, is made for. A product
+// chart takes precedence over the chart of its category.
+type SizeChart struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	Name       string           `gorm:"type:varchar(100);not null" json:"name"`
+	ProductID  *uint            `gorm:"uniqueIndex" json:"product_id,omitempty"`
+	CategoryID *uint            `gorm:"uniqueIndex" json:"category_id,omitempty"`
+	Entries    []SizeChartEntry `gorm:"foreignKey:SizeChartID" json:"entries"` // smallest size first
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// SizeChartEntry represents the measurement ranges of one size
+type SizeChartEntry struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	SizeChartID  uint             `gorm:"index;not null" json:"size_chart_id"`
+	Size         string           `gorm:"type:varchar(50);not null" json:"size"` // matches ProductVariant.Size
+	Position     int              `gorm:"default:0" json:"position"`
+	Measurements SizeMeasurements `gorm:"type:jsonb;not null;default:'{}'" json:"measurements"`
+}
+
+// MeasurementRange represents the body measurement range a size fits, in cm
+type MeasurementRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// SizeMeasurements holds the measurement ranges of a size keyed by measurement
+type SizeMeasurements map[string]MeasurementRange
+
+// Value stores the measurements as a JSON object
+func (m SizeMeasurements) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(m)
+	return string(data), err
+}
+
+// Scan reads the measurements from a JSON object
+func (m *SizeMeasurements) Scan(value interface{}) error {
+	return scanJSON(value, m)
+}
+
+// BodyProfile represents the body measurements a customer shares for size recommendations
+type BodyProfile struct {
+	UserID        uint          `gorm:"primaryKey" json:"user_id"`
+	Height        *float64      `json:"height,omitempty"` // in cm, like the other measurements
+	Chest         *float64      `json:"chest,omitempty"`
+	Waist         *float64      `json:"waist,omitempty"`
+	Hips          *float64      `json:"hips,omitempty"`
+	Inseam        *float64      `json:"inseam,omitempty"`
+	FootLength    *float64      `json:"foot_length,omitempty"`
+	Weight        *float64      `json:"weight,omitempty"` // in kg, not used for matching
+	FitPreference FitPreference `gorm:"type:varchar(20);default:regular" json:"fit_preference"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// Measurements returns the measurements the customer filled in keyed like size chart measurements
+func (p *BodyProfile) Measurements() map[string]float64 {
+	measurements := make(map[string]float64)
+	for name, value := range map[string]*float64{
+		MeasurementHeight:     p.Height,
+		MeasurementChest:      p.Chest,
+		MeasurementWaist:      p.Waist,
+		MeasurementHips:       p.Hips,
+		MeasurementInseam:     p.Inseam,
+		MeasurementFootLength: p.FootLength,
+	} {
+		if value != nil {
+			measurements[name] = *value
+		}
+	}
+	return measurements
+}
+
+// FitSummary represents the size feedback customers gave on a product
+// through returns, exchanges and reviews
+type FitSummary struct {
+	TooSmall   int64       `json:"too_small"`
+	TrueToSize int64       `json:"true_to_size"`
+	TooLarge   int64       `json:"too_large"`
+	Tendency   FitTendency `json:"tendency,omitempty"` // empty until there is enough feedback
+}
+
+// SizeRecommendation represents the size suggested to a customer for a product
+type SizeRecommendation struct {
+	ProductID  uint              `json:"product_id"`
+	Size       string            `json:"size"`
+	Confidence string            `json:"confidence"` // high, medium or low
+	Variants   []*ProductVariant `json:"variants"`   // active variants of the size
+	Fit        FitSummary        `json:"fit"`
+	Note       string            `json:"note,omitempty"`
+}
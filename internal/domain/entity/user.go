@@ -48,3 +48,16 @@ type Address struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+// ShippingAddress copies the address to ship an order to
+func (a *Address) ShippingAddress() ShippingAddress {
+	return ShippingAddress{
+		Recipient:   a.Recipient,
+		Phone:       a.Phone,
+		Province:    a.Province,
+		City:        a.City,
+		District:    a.District,
+		PostalCode:  a.PostalCode,
+		FullAddress: a.FullAddress,
+	}
+}
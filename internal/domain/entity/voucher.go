@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VoucherType represents how a voucher discounts an order
+type VoucherType string
+
+const (
+	VoucherTypePercentage   VoucherType = "percentage"    // Value percent off the eligible items
+	VoucherTypeFixed        VoucherType = "fixed"         // Value off the eligible items
+	VoucherTypeFreeShipping VoucherType = "free_shipping" // shipping cost off, up to MaxDiscount
+)
+
+// Voucher represents a discount code customers apply at checkout
+type Voucher struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Code              string         `gorm:"uniqueIndex;not null" json:"code"`
+	Name              string         `gorm:"not null" json:"name"`
+	Description       string         `json:"description,omitempty"`
+	Type              VoucherType    `gorm:"type:varchar(20);not null" json:"type"`
+	Value             float64        `gorm:"not null" json:"value"`
+	MinSpend          float64        `gorm:"default:0" json:"min_spend"`
+	MaxDiscount       float64        `gorm:"default:0" json:"max_discount"`         // zero means no cap
+	UsageLimit        int            `gorm:"default:0" json:"usage_limit"`          // zero means unlimited
+	UsageLimitPerUser int            `gorm:"default:0" json:"usage_limit_per_user"` // zero means unlimited
+	UsedCount         int            `gorm:"default:0" json:"used_count"`
+	StartsAt          time.Time      `gorm:"not null" json:"starts_at"`
+	EndsAt            time.Time      `gorm:"not null" json:"ends_at"`
+	IsActive          bool           `gorm:"not null" json:"is_active"`
+	Products          []Product      `gorm:"many2many:voucher_products;" json:"products,omitempty"`     // empty applies to every product
+	Categories        []Category     `gorm:"many2many:voucher_categories;" json:"categories,omitempty"` // empty applies to every category
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// VoucherRedemption represents a voucher used on an order
+type VoucherRedemption struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	VoucherID        uint       `gorm:"index;not null" json:"voucher_id"`
+	UserID           uint       `gorm:"index;not null" json:"user_id"`
+	OrderID          uint       `gorm:"uniqueIndex;not null" json:"order_id"`
+	DiscountAmount   float64    `gorm:"not null" json:"discount_amount"`
+	ShippingDiscount float64    `gorm:"default:0" json:"shipping_discount"`
+	ReleasedAt       *time.Time `json:"released_at,omitempty"` // set when the order was cancelled and the use given back
+	CreatedAt        time.Time  `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+)
+
+// WalletTransactionType represents why a customer's store credit changed
+type WalletTransactionType string
+
+const (
+	WalletTransactionGiftCard WalletTransactionType = "gift_card" // a gift card was redeemed into the wallet
+	WalletTransactionPayment  WalletTransactionType = "payment"   // store credit paid for part or all of an order
+	WalletTransactionRelease  WalletTransactionType = "release"   // store credit given back when the order it paid for was cancelled
+	WalletTransactionRefund   WalletTransactionType = "refund"    // a payment refund paid into the wallet
+)
+
+// Wallet represents a customer's store credit balance
+type Wallet struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	Balance   float64   `gorm:"default:0" json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WalletTransaction represents an entry in a customer's store credit ledger
+type WalletTransaction struct {
+	ID           uint                  `gorm:"primaryKey" json:"id"`
+	UserID       uint                  `gorm:"index;not null" json:"user_id"`
+	Type         WalletTransactionType `gorm:"type:varchar(20);not null" json:"type"`
+	Amount       float64               `gorm:"not null" json:"amount"` // negative when store credit is spent
+	BalanceAfter float64               `gorm:"not null" json:"balance_after"`
+	OrderID      *uint                 `gorm:"index" json:"order_id,omitempty"`
+	GiftCardID   *uint                 `json:"gift_card_id,omitempty"`
+	Reference    string                `gorm:"uniqueIndex;not null" json:"-"` // makes retried credits and debits apply once
+	Description  string                `json:"description"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
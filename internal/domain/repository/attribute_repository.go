@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ErrAttributeCodeExists is returned when an attribute code is already defined
+// on the category, one of its ancestors or one of its subcategories
+var ErrAttributeCodeExists = errors.New("attribute is already defined on this category, a parent or a subcategory")
+
+// AttributeRepository defines the interface for product attribute definition data access
+type AttributeRepository interface {
+	Create(ctx context.Context, attribute *entity.AttributeDefinition) error
+	GetByID(ctx context.Context, id uint) (*entity.AttributeDefinition, error)
+	Update(ctx context.Context, attribute *entity.AttributeDefinition) error
+	Delete(ctx context.Context, id uint) error
+	GetByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]*entity.AttributeDefinition, error)
+	GetFilterable(ctx context.Context, categoryID *uint) ([]*entity.AttributeDefinition, error) // defined on the category's path or subtree, all when nil
+	CodeExists(ctx context.Context, categoryID uint, code string, excludeID uint) (bool, error) // on the category's path or subtree
+}
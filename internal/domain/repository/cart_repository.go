@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// CartRepository defines the interface for cart data access
+type CartRepository interface {
+	GetOrCreate(ctx context.Context, userID uint) (*entity.Cart, error)
+	GetByID(ctx context.Context, id uint) (*entity.Cart, error)
+	GetByUserID(ctx context.Context, userID uint) (*entity.Cart, error)
+	AddItem(ctx context.Context, cartID uint, item *entity.CartItem) error
+	UpdateItem(ctx context.Context, item *entity.CartItem) error
+	RemoveItem(ctx context.Context, itemID uint) error
+	ClearCart(ctx context.Context, cartID uint) error
+	GetTotalItems(ctx context.Context, cartID uint) (int, error)
+	GetTotalAmount(ctx context.Context, cartID uint) (float64, error)
+	SetVoucherCode(ctx context.Context, cartID uint, code string) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// Errors returned by CategoryTreeRepository
+var (
+	ErrCategoryCycle    = errors.New("category cannot be moved under itself or its subcategories")
+	ErrCategoryNotEmpty = errors.New("category has subcategories or products")
+	ErrCategoryNoParent = errors.New("top-level category has no parent to take over its products")
+)
+
+// CategoryTreeRepository defines the interface for category hierarchy data access
+type CategoryTreeRepository interface {
+	GetAll(ctx context.Context) ([]*entity.Category, error)                // by depth, then position among siblings
+	GetSubtree(ctx context.Context, id uint) ([]*entity.Category, error)   // the category and all its descendants
+	GetAncestors(ctx context.Context, id uint) ([]*entity.Category, error) // from the root down to the category itself
+	Move(ctx context.Context, id uint, parentID *uint, position int) error // nil parentID moves it to the top level
+	Delete(ctx context.Context, id uint, rule entity.CategoryDeleteRule) error
+}
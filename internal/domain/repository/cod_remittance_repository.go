@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// CODSettlement settles the payment a matched remittance line pays for
+type CODSettlement struct {
+	Line      int // index of the line in the batch
+	PaymentID uint
+	PaidAt    time.Time
+	Changes   []*entity.OrderStatusHistory // order status changes, applied in order
+}
+
+// CODRemittanceRepository defines the interface for cash-on-delivery remittance data access
+type CODRemittanceRepository interface {
+	CreateBatch(ctx context.Context, batch *entity.CODRemittanceBatch, settlements []*CODSettlement) error // creates the batch lines and settles the payments in one transaction; a line whose payment is no longer pending becomes a duplicate
+	GetBatchByID(ctx context.Context, id uint) (*entity.CODRemittanceBatch, error)
+	ListBatches(ctx context.Context, offset, limit int) ([]*entity.CODRemittanceBatch, int64, error)
+	ListBatchesByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.CODRemittanceBatch, *entity.PageCursors, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ExchangeRepository defines the interface for exchange request data access
+type ExchangeRepository interface {
+	NextExchangeSequence(ctx context.Context) (int64, error)
+	Create(ctx context.Context, exchange *entity.ExchangeRequest, check AfterSalesCheck) error // also creates the items and reserves the replacement stock, failing with the error of check
+	GetByID(ctx context.Context, id uint) (*entity.ExchangeRequest, error)
+	GetByOrderID(ctx context.Context, orderID uint) ([]*entity.ExchangeRequest, error)
+	GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.ExchangeRequest, int64, error)
+	UpdateIfStatus(ctx context.Context, exchange *entity.ExchangeRequest, expected ...entity.ExchangeStatus) (bool, error)                      // only updates while the stored status is one of expected
+	Approve(ctx context.Context, exchange *entity.ExchangeRequest, replacement *entity.Order, created *entity.OrderStatusHistory) (bool, error) // also creates the replacement order; only while the exchange is still requested
+	Receive(ctx context.Context, exchange *entity.ExchangeRequest, expected ...entity.ExchangeStatus) (bool, error)                             // UpdateIfStatus that also restocks the original items
+	GetAwaitingTopUp(ctx context.Context, approvedBefore time.Time, limit int) ([]*entity.ExchangeRequest, error)                               // approved exchanges whose replacement order is cancelled, or still unpaid since before approvedBefore
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.ExchangeRequest, int64, error)
+	ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ExchangeRequest, *entity.PageCursors, error)
+}
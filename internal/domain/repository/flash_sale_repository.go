@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// Errors returned by FlashSaleQuotaRepository.Reserve
+var (
+	ErrFlashSaleQuotaNotLoaded = errors.New("flash sale quota not loaded")
+	ErrFlashSaleSoldOut        = errors.New("flash sale is sold out")
+	ErrFlashSaleUserLimit      = errors.New("flash sale limit per customer reached")
+)
+
+// FlashSaleRepository defines the interface for flash sale data access
+type FlashSaleRepository interface {
+	Create(ctx context.Context, sale *entity.FlashSale) error
+	GetByID(ctx context.Context, id uint) (*entity.FlashSale, error)
+	Update(ctx context.Context, sale *entity.FlashSale) error // replaces the sale's items
+	UpdateIfStatus(ctx context.Context, sale *entity.FlashSale, expected ...entity.FlashSaleStatus) (bool, error)
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.FlashSale, int64, error)
+	GetCurrent(ctx context.Context, at time.Time) ([]*entity.FlashSale, error)
+	GetDueForStatusChange(ctx context.Context, at time.Time) ([]*entity.FlashSale, error)
+	GetOverlappingVariantIDs(ctx context.Context, variantIDs []uint, start, end time.Time, excludeSaleID uint) ([]uint, error)
+	GetLiveItemsByProductIDs(ctx context.Context, productIDs []uint, at time.Time) ([]*entity.FlashSaleItem, error)
+	GetLiveItemByVariantID(ctx context.Context, variantID uint, at time.Time) (*entity.FlashSaleItem, error) // nil when the variant is not on sale
+	CountUserPurchased(ctx context.Context, itemID, userID uint) (int, error)
+	ReleasePurchases(ctx context.Context, orderID uint) ([]*entity.FlashSalePurchase, error)
+}
+
+// FlashSaleQuotaRepository defines the interface for the live flash sale
+// quota counters that admit purchases during a sale
+type FlashSaleQuotaRepository interface {
+	LoadRemaining(ctx context.Context, itemID uint, remaining int, ttl time.Duration) error
+	LoadUserPurchased(ctx context.Context, itemID, userID uint, purchased int, ttl time.Duration) error
+	Reserve(ctx context.Context, itemID, userID uint, quantity, perUserLimit int) error
+	Release(ctx context.Context, itemID, userID uint, quantity int) error
+	Clear(ctx context.Context, itemID uint) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// GiftCardRepository defines the interface for gift card data access
+type GiftCardRepository interface {
+	Create(ctx context.Context, card *entity.GiftCard) error
+	GetByID(ctx context.Context, id uint) (*entity.GiftCard, error)
+	GetByCode(ctx context.Context, code string) (*entity.GiftCard, error)
+	GetByOrderID(ctx context.Context, orderID uint) ([]*entity.GiftCard, error)
+	GetByPurchaser(ctx context.Context, userID uint, offset, limit int) ([]*entity.GiftCard, int64, error)
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.GiftCard, int64, error)
+	ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.GiftCard, *entity.PageCursors, error)
+	UpdateIfStatus(ctx context.Context, card *entity.GiftCard, expected ...entity.GiftCardStatus) (bool, error)
+	Redeem(ctx context.Context, card *entity.GiftCard, transaction *entity.WalletTransaction) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ErrInsufficientPoints is returned when a customer spends more points than they have
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+// LoyaltyRepository defines the interface for loyalty points data access
+type LoyaltyRepository interface {
+	GetAccount(ctx context.Context, userID uint) (*entity.LoyaltyAccount, error)
+	GetTransactions(ctx context.Context, userID uint, offset, limit int) ([]*entity.LoyaltyTransaction, int64, error)
+	GetOrderTransactions(ctx context.Context, orderID uint) ([]*entity.LoyaltyTransaction, error)
+	Credit(ctx context.Context, transaction *entity.LoyaltyTransaction) (bool, error)
+	Debit(ctx context.Context, transaction *entity.LoyaltyTransaction, allowOverdraft bool) (bool, error)
+	Redeem(ctx context.Context, transaction *entity.LoyaltyTransaction, order *entity.Order) error // spends the points and updates the pending order atomically
+	Restore(ctx context.Context, orderID uint) error                                               // gives back an order's redeemed points with their original expiry
+	ExpireDue(ctx context.Context, at time.Time, limit int) (int, error)
+	GetDeliveredOrdersWithoutEarn(ctx context.Context, limit int) ([]*entity.Order, error)
+	ListMultipliers(ctx context.Context) ([]*entity.LoyaltyCategoryMultiplier, error)
+	SetMultiplier(ctx context.Context, multiplier *entity.LoyaltyCategoryMultiplier) error
+	DeleteMultiplier(ctx context.Context, categoryID uint) error
+}
@@ -11,6 +11,7 @@ type NotificationRepository interface {
 	Create(ctx context.Context, notification *entity.Notification) error
 	GetByID(ctx context.Context, id uint) (*entity.Notification, error)
 	GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.Notification, int64, error)
+	GetByUserIDByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Notification, *entity.PageCursors, error)
 	GetUnreadByUserID(ctx context.Context, userID uint) ([]*entity.Notification, error)
 	MarkAsRead(ctx context.Context, id uint) error
 	MarkAllAsRead(ctx context.Context, userID uint) error
@@ -7,17 +7,42 @@ import (
 	"fashion-shop/internal/domain/entity"
 )
 
+// OrderPlacement is what placing an order takes besides the order itself
+type OrderPlacement struct {
+	CartID     uint                        // emptied once the order is placed
+	Creation   *entity.OrderStatusHistory  // records the order's creation
+	Voucher    *entity.VoucherRedemption   // use of the cart's voucher; nil without one
+	FlashSales []*entity.FlashSalePurchase // units bought at flash sale prices, reserved beforehand
+	Wallet     *entity.WalletTransaction   // store credit spent on the order; nil without any
+	Payment    *WalletPayment              // set when the store credit covers the whole order
+}
+
 // OrderRepository defines the interface for order data access
 type OrderRepository interface {
 	Create(ctx context.Context, order *entity.Order) error
+	Place(ctx context.Context, order *entity.Order, placement *OrderPlacement) error // takes what the order needs, creates it, records its creation and empties the cart atomically
 	GetByID(ctx context.Context, id uint) (*entity.Order, error)
 	GetByOrderNumber(ctx context.Context, orderNumber string) (*entity.Order, error)
+	GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Order, error)
+	GetOrderNumbers(ctx context.Context, ids []uint) (map[uint]string, error) // keyed by order ID; missing orders are left out
 	GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.Order, int64, error)
-	Update(ctx context.Context, order *entity.Order) error
-	UpdateStatus(ctx context.Context, id uint, status entity.OrderStatus) error
+	GetByUserIDByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error)
+	Update(ctx context.Context, order *entity.Order) error         // leaves the status alone; see OrderStatusHistoryRepository.Transition
+	SetPayment(ctx context.Context, orderID, paymentID uint) error // sets only the order's current payment
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Order, int64, error)
+	ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error)
 	GetSalesReport(ctx context.Context, startDate, endDate time.Time) ([]*entity.Order, float64, error)
+	GetUnstarted(ctx context.Context, placedBefore time.Time, limit int) ([]*entity.Order, error) // pending orders placed before placedBefore without a payment, oldest first
+	GetUnreleased(ctx context.Context, limit int) ([]*entity.Order, error)                        // cancelled orders that still hold a voucher use, quota, points, credit or gift cards, oldest first
+	MarkHoldsReleased(ctx context.Context, id uint) error
+}
+
+// OrderStatusHistoryRepository defines the interface for order status history data access
+type OrderStatusHistoryRepository interface {
+	Transition(ctx context.Context, change *entity.OrderStatusHistory) (bool, error) // moves the order from FromStatus to ToStatus, records the change and restocks a cancelled order atomically; false if the order is no longer in FromStatus
+	Record(ctx context.Context, change *entity.OrderStatusHistory) error             // records a change without touching the order, such as its creation
+	GetByOrderID(ctx context.Context, orderID uint) ([]*entity.OrderStatusHistory, error)
 }
 
 // OrderItemRepository defines the interface for order item data access
@@ -28,38 +53,3 @@ type OrderItemRepository interface {
 	Update(ctx context.Context, item *entity.OrderItem) error
 	Delete(ctx context.Context, id uint) error
 }
-
-// PaymentRepository defines the interface for payment data access
-type PaymentRepository interface {
-	Create(ctx context.Context, payment *entity.Payment) error
-	GetByID(ctx context.Context, id uint) (*entity.Payment, error)
-	GetByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error)
-	GetByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error)
-	Update(ctx context.Context, payment *entity.Payment) error
-	UpdateStatus(ctx context.Context, id uint, status entity.PaymentStatus) error
-	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Payment, int64, error)
-}
-
-// CartRepository defines the interface for cart data access
-type CartRepository interface {
-	GetOrCreate(ctx context.Context, userID uint) (*entity.Cart, error)
-	GetByID(ctx context.Context, id uint) (*entity.Cart, error)
-	GetByUserID(ctx context.Context, userID uint) (*entity.Cart, error)
-	AddItem(ctx context.Context, cartID uint, item *entity.CartItem) error
-	UpdateItem(ctx context.Context, item *entity.CartItem) error
-	RemoveItem(ctx context.Context, itemID uint) error
-	ClearCart(ctx context.Context, cartID uint) error
-	GetTotalItems(ctx context.Context, cartID uint) (int, error)
-	GetTotalAmount(ctx context.Context, cartID uint) (float64, error)
-}
-
-// WishlistRepository defines the interface for wishlist data access
-type WishlistRepository interface {
-	GetOrCreate(ctx context.Context, userID uint) (*entity.Wishlist, error)
-	GetByID(ctx context.Context, id uint) (*entity.Wishlist, error)
-	GetByUserID(ctx context.Context, userID uint) (*entity.Wishlist, error)
-	AddItem(ctx context.Context, wishlistID uint, productID uint) error
-	RemoveItem(ctx context.Context, itemID uint) error
-	IsProductInWishlist(ctx context.Context, wishlistID uint, productID uint) (bool, error)
-	GetItems(ctx context.Context, wishlistID uint, offset, limit int) ([]*entity.WishlistItem, int64, error)
-}
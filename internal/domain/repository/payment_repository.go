@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// PaymentRepository defines the interface for payment data access
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *entity.Payment) error
+	GetByID(ctx context.Context, id uint) (*entity.Payment, error)
+	GetByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error)
+	GetByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error)
+	GetByGatewayOrderID(ctx context.Context, gatewayOrderID string) (*entity.Payment, error)
+	GetByTransactionIDs(ctx context.Context, transactionIDs []string) ([]*entity.Payment, error)
+	GetByGatewayOrderIDs(ctx context.Context, gatewayOrderIDs []string) ([]*entity.Payment, error)
+	Update(ctx context.Context, payment *entity.Payment) error
+	UpdateStatus(ctx context.Context, id uint, status entity.PaymentStatus) error
+	UpdateIfStatus(ctx context.Context, payment *entity.Payment, expected ...entity.PaymentStatus) (bool, error) // only updates while the stored status is one of expected
+	Expire(ctx context.Context, payment *entity.Payment, cancel *entity.OrderStatusHistory) (bool, error)        // expires a pending payment and, with cancel, cancels its order and returns its stock atomically
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Payment, int64, error)
+	ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Payment, *entity.PageCursors, error)
+	GetExpiredPending(ctx context.Context, before time.Time, limit int) ([]*entity.Payment, error)
+	GetPaidBetween(ctx context.Context, provider string, start, end time.Time) ([]*entity.Payment, error)    // empty provider matches every provider
+	GetCreatedBetween(ctx context.Context, provider string, start, end time.Time) ([]*entity.Payment, error) // empty provider matches every provider
+}
+
+// ErrRefundExceedsPayment is returned when refunds exceed what is left to refund of a payment
+var ErrRefundExceedsPayment = errors.New("refund exceeds the refundable amount")
+
+// PaymentRefundRepository defines the interface for payment refund data access
+type PaymentRefundRepository interface {
+	Create(ctx context.Context, refund *entity.PaymentRefund) error
+	Reserve(ctx context.Context, paymentID uint, keyPrefix string, refunds []*entity.PaymentRefund) error                        // records refunds as pending while the payment is locked, keyed keyPrefix-refund-N after the payment's earlier refunds; ErrRefundExceedsPayment when they exceed what is left to refund
+	Complete(ctx context.Context, refunds []*entity.PaymentRefund, credits []*entity.WalletTransaction) (*entity.Payment, error) // marks pending refunds succeeded, adds them to the payment and records the store credits atomically
+	Fail(ctx context.Context, refunds []*entity.PaymentRefund) error                                                             // marks pending refunds failed so their amount can be refunded again
+	GetByPaymentID(ctx context.Context, paymentID uint) ([]*entity.PaymentRefund, error)
+}
+
+// PaymentEventRepository defines the interface for payment notification data access
+type PaymentEventRepository interface {
+	Create(ctx context.Context, event *entity.PaymentEvent) error
+	Update(ctx context.Context, event *entity.PaymentEvent) error
+	GetByPaymentID(ctx context.Context, paymentID uint) ([]*entity.PaymentEvent, error)
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"fashion-shop/internal/domain/entity"
 )
 
+// ErrProductVariantNotFound is returned when no product variant matches a lookup
+var ErrProductVariantNotFound = errors.New("product variant not found")
+
+// ErrProductImageExists is returned when a product already has an image with the same content
+var ErrProductImageExists = errors.New("product already has this image")
+
 // ProductRepository defines the interface for product data access
 type ProductRepository interface {
 	Create(ctx context.Context, product *entity.Product) error
@@ -33,12 +40,13 @@ type CategoryRepository interface {
 
 // ProductImageRepository defines the interface for product image data access
 type ProductImageRepository interface {
-	Create(ctx context.Context, image *entity.ProductImage) error
+	Create(ctx context.Context, image *entity.ProductImage) error // ErrProductImageExists when the product has an image with the same content hash
 	GetByID(ctx context.Context, id uint) (*entity.ProductImage, error)
 	GetByProductID(ctx context.Context, productID uint) ([]*entity.ProductImage, error)
 	Update(ctx context.Context, image *entity.ProductImage) error
 	Delete(ctx context.Context, id uint) error
 	SetPrimary(ctx context.Context, id uint, productID uint) error
+	FindByContentHash(ctx context.Context, hash string) (*entity.ProductImage, error) // an image of any product uploaded with the same content, nil when there is none
 }
 
 // ProductVariantRepository defines the interface for product variant data access
@@ -50,6 +58,11 @@ type ProductVariantRepository interface {
 	Update(ctx context.Context, variant *entity.ProductVariant) error
 	Delete(ctx context.Context, id uint) error
 	UpdateStock(ctx context.Context, id uint, quantity int) error
+	AdjustStock(ctx context.Context, id uint, delta int) error // atomically adds delta, failing if stock would go negative
+
+	// Option matrix
+	GetOptions(ctx context.Context, productID uint) ([]*entity.ProductOption, error)
+	SaveMatrix(ctx context.Context, productID uint, skuTemplate string, options []*entity.ProductOption, variants []*entity.ProductVariant) error // variants left out are deleted, or deactivated when still referenced
 }
 
 // ReviewRepository defines the interface for review data access
@@ -57,9 +70,9 @@ type ReviewRepository interface {
 	Create(ctx context.Context, review *entity.Review) error
 	GetByID(ctx context.Context, id uint) (*entity.Review, error)
 	GetByProductID(ctx context.Context, productID uint, offset, limit int) ([]*entity.Review, int64, error)
+	GetByProductIDByCursor(ctx context.Context, productID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error)
 	GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.Review, int64, error)
-	Update(ctx context.Context, review *entity.Review) error
-	Delete(ctx context.Context,  error)
+	GetByUserIDByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error)
 	Update(ctx context.Context, review *entity.Review) error
 	Delete(ctx context.Context, id uint) error
 	GetAverageRatingByProductID(ctx context.Context, productID uint) (float64, error)
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ProductSearchRepository defines the interface for full-text product search,
+// faceted product filtering and feeding the external search index
+type ProductSearchRepository interface {
+	Search(ctx context.Context, keyword string, filter map[string]interface{}, sort string, offset, limit int) ([]*entity.Product, int64, error)
+	Suggest(ctx context.Context, keyword string, limit int) ([]*entity.SearchSuggestion, error)
+	Filter(ctx context.Context, filter *entity.ProductFilter, sort string, offset, limit int) ([]*entity.Product, int64, error)
+	FilterByCursor(ctx context.Context, filter *entity.ProductFilter, page *entity.CursorPage) ([]*entity.Product, *entity.PageCursors, error) // newest first
+	Facets(ctx context.Context, filter *entity.ProductFilter) (*entity.ProductFacets, error)
+	AttributeFacet(ctx context.Context, filter *entity.ProductFilter, code string) ([]entity.FacetCount, error)
+	GetActiveByIDs(ctx context.Context, ids []uint) ([]*entity.Product, error) // in the order of ids, skipping missing and inactive products
+
+	// External search index sync
+	GetIndexChanges(ctx context.Context, limit int) ([]*entity.SearchIndexChange, error)
+	DeleteIndexChanges(ctx context.Context, changes []*entity.SearchIndexChange) error // keeps changes recorded again since
+	GetForIndex(ctx context.Context, ids []uint) ([]*entity.Product, error)            // active products only
+	ListForIndex(ctx context.Context, afterID uint, limit int) ([]*entity.Product, error)
+}
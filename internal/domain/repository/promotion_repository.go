@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// PromotionRepository defines the interface for promotion data access
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *entity.Promotion) error
+	GetByID(ctx context.Context, id uint) (*entity.Promotion, error)
+	Update(ctx context.Context, promotion *entity.Promotion) error // replaces the promotion's rules and scope
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Promotion, int64, error)
+	GetActive(ctx context.Context, at time.Time) ([]*entity.Promotion, error)
+}
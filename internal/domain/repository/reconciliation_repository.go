@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ReconciliationRepository defines the interface for payment reconciliation data access
+type ReconciliationRepository interface {
+	CreateRun(ctx context.Context, run *entity.ReconciliationRun) error                            // also creates the run items
+	GetRunByID(ctx context.Context, id uint, itemStatus string) (*entity.ReconciliationRun, error) // empty itemStatus loads every item
+	ListRuns(ctx context.Context, offset, limit int) ([]*entity.ReconciliationRun, int64, error)
+	ListRunsByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.ReconciliationRun, *entity.PageCursors, error)
+	GetLastScheduledEnd(ctx context.Context, source entity.ReconciliationSource) (*time.Time, error) // end of the latest scheduled run's period; nil before the first one
+}
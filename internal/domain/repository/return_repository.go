@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// AfterSalesCheck checks a new return or exchange request against the returns
+// and exchanges its order already has. Repositories run it with the order
+// locked, so requests for the same order are checked one at a time.
+type AfterSalesCheck func(returns []*entity.ReturnRequest, exchanges []*entity.ExchangeRequest) error
+
+// ReturnRepository defines the interface for return request data access
+type ReturnRepository interface {
+	NextRMASequence(ctx context.Context) (int64, error)
+	Create(ctx context.Context, ret *entity.ReturnRequest, check AfterSalesCheck) error // also creates the items and photos, failing with the error of check
+	GetByID(ctx context.Context, id uint) (*entity.ReturnRequest, error)
+	GetByOrderID(ctx context.Context, orderID uint) ([]*entity.ReturnRequest, error)
+	GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.ReturnRequest, int64, error)
+	UpdateIfStatus(ctx context.Context, ret *entity.ReturnRequest, expected ...entity.ReturnStatus) (bool, error) // only updates while the stored status is one of expected
+	Receive(ctx context.Context, ret *entity.ReturnRequest, expected ...entity.ReturnStatus) (bool, error)        // UpdateIfStatus that also restocks the items
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.ReturnRequest, int64, error)
+	ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ReturnRequest, *entity.PageCursors, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// SizeChartRepository defines the interface for size chart data access
+type SizeChartRepository interface {
+	Create(ctx context.Context, chart *entity.SizeChart) error
+	GetByID(ctx context.Context, id uint) (*entity.SizeChart, error)
+	Update(ctx context.Context, chart *entity.SizeChart) error // replaces its entries
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int) ([]*entity.SizeChart, int64, error)
+	FindForProduct(ctx context.Context, productID uint, categoryIDs []uint) (*entity.SizeChart, error) // categoryIDs nearest first
+	ExistsFor(ctx context.Context, productID, categoryID *uint, excludeID uint) (bool, error)
+	GetFitSummary(ctx context.Context, productID uint) (*entity.FitSummary, error) // counts only, without a tendency
+}
+
+// BodyProfileRepository defines the interface for customer body measurement data access
+type BodyProfileRepository interface {
+	GetByUserID(ctx context.Context, userID uint) (*entity.BodyProfile, error)
+	Save(ctx context.Context, profile *entity.BodyProfile) error // creates or replaces the profile of the user
+}
@@ -14,6 +14,7 @@ type UserRepository interface {
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, offset, limit int) ([]*entity.User, int64, error)
+	ListByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.User, *entity.PageCursors, error)
 	UpdateLastLogin(ctx context.Context, id uint) error
 	ChangePassword(ctx context.Context, id uint, hashedPassword string) error
 	ToggleActive(ctx context.Context, id uint, isActive bool) error
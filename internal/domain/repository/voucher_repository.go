@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ErrVoucherRedemptionNotFound is returned when an order did not use a voucher
+var ErrVoucherRedemptionNotFound = errors.New("voucher redemption not found")
+
+// VoucherRepository defines the interface for voucher data access
+type VoucherRepository interface {
+	Create(ctx context.Context, voucher *entity.Voucher) error
+	GetByID(ctx context.Context, id uint) (*entity.Voucher, error)
+	GetByCode(ctx context.Context, code string) (*entity.Voucher, error)
+	Update(ctx context.Context, voucher *entity.Voucher) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Voucher, int64, error)
+	ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Voucher, *entity.PageCursors, error)
+	CountUserRedemptions(ctx context.Context, voucherID, userID uint) (int64, error)
+	GetRedemption(ctx context.Context, orderID uint) (*entity.VoucherRedemption, error)
+	Redeem(ctx context.Context, redemption *entity.VoucherRedemption, order *entity.Order) error // counts the use and updates the pending order atomically
+	Release(ctx context.Context, orderID uint) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ErrInsufficientWalletBalance is returned when a customer spends more store credit than they have
+var ErrInsufficientWalletBalance = errors.New("insufficient store credit")
+
+// WalletRepository defines the interface for store credit wallet data access
+type WalletRepository interface {
+	GetWallet(ctx context.Context, userID uint) (*entity.Wallet, error)
+	GetTransactions(ctx context.Context, userID uint, offset, limit int) ([]*entity.WalletTransaction, int64, error)
+	GetOrderTransactions(ctx context.Context, orderID uint) ([]*entity.WalletTransaction, error)
+	Record(ctx context.Context, transaction *entity.WalletTransaction) (bool, error)
+	Pay(ctx context.Context, transaction *entity.WalletTransaction, order *entity.Order, payment *WalletPayment) error // spends the credit on a pending order and updates it atomically
+}
+
+// WalletPayment is the payment of an order covered in full by store credit,
+// recorded with the debit
+type WalletPayment struct {
+	Payment *entity.Payment
+	Paid    *entity.OrderStatusHistory // moves the order on to processing
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// WishlistRepository defines the interface for wishlist data access
+type WishlistRepository interface {
+	GetOrCreate(ctx context.Context, userID uint) (*entity.Wishlist, error)
+	GetByID(ctx context.Context, id uint) (*entity.Wishlist, error)
+	GetByUserID(ctx context.Context, userID uint) (*entity.Wishlist, error)
+	AddItem(ctx context.Context, wishlistID uint, productID uint) error
+	RemoveItem(ctx context.Context, itemID uint) error
+	IsProductInWishlist(ctx context.Context, wishlistID uint, productID uint) (bool, error)
+	GetItems(ctx context.Context, wishlistID uint, offset, limit int) ([]*entity.WishlistItem, int64, error)
+}
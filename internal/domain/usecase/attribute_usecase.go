@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// AttributeUseCase defines the interface for product attribute schema business logic
+type AttributeUseCase interface {
+	GetCategoryAttributes(ctx context.Context, categoryID uint) ([]*entity.AttributeDefinition, error) // including those inherited from ancestors
+	CreateAttribute(ctx context.Context, categoryID uint, attribute *entity.AttributeDefinition) (*entity.AttributeDefinition, error)
+	UpdateAttribute(ctx context.Context, id uint, attribute *entity.AttributeDefinition) (*entity.AttributeDefinition, error)
+	DeleteAttribute(ctx context.Context, id uint) error
+	ValidateProductAttributes(ctx context.Context, categoryID uint, attributes entity.ProductAttributes) (entity.ProductAttributes, error)
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// CategoryTreeUseCase defines the interface for category hierarchy business logic
+type CategoryTreeUseCase interface {
+	GetTree(ctx context.Context) ([]*entity.Category, error)
+	GetSubtree(ctx context.Context, id uint) (*entity.Category, error)
+	GetBreadcrumbs(ctx context.Context, categoryID uint) ([]*entity.Category, error)
+	GetProductBreadcrumbs(ctx context.Context, productID uint) ([]*entity.Category, error)
+	MoveCategory(ctx context.Context, id uint, parentID *uint, position int) (*entity.Category, error)
+	DeleteCategory(ctx context.Context, id uint, rule entity.CategoryDeleteRule) error
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// FlashSaleItemRequest is a variant to put on sale, its sale price and quota
+type FlashSaleItemRequest struct {
+	VariantID    uint
+	SalePrice    float64
+	Quota        int
+	PerUserLimit int // zero means unlimited
+}
+
+// FlashSaleUseCase defines the interface for flash sale business logic
+type FlashSaleUseCase interface {
+	GetCurrentFlashSales(ctx context.Context) ([]*entity.FlashSale, error)
+	GetFlashSale(ctx context.Context, id uint) (*entity.FlashSale, error)
+	ApplyEffectivePrices(ctx context.Context, products ...*entity.Product) error
+
+	// Checkout
+	Reserve(ctx context.Context, userID, variantID uint, quantity int) (*entity.FlashSaleItem, error) // nil when the variant is not on sale
+	CancelReservation(ctx context.Context, purchase *entity.FlashSalePurchase) error
+	ReleaseForOrder(ctx context.Context, orderID uint) error
+
+	// Admin functions
+	CreateFlashSale(ctx context.Context, sale *entity.FlashSale, items []FlashSaleItemRequest) error
+	UpdateFlashSale(ctx context.Context, sale *entity.FlashSale, items []FlashSaleItemRequest) error
+	CancelFlashSale(ctx context.Context, id uint) error
+	ListFlashSales(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.FlashSale, int64, error)
+	SyncFlashSales(ctx context.Context) (int, error) // returns the number of campaigns started or ended
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// GiftCardPurchase is a gift card a customer wants to buy
+type GiftCardPurchase struct {
+	Amount         float64
+	RecipientName  string
+	RecipientEmail string
+	Message        string
+}
+
+// GiftCardUseCase defines the interface for gift card business logic
+type GiftCardUseCase interface {
+	// PurchaseGiftCard creates the card and the order that pays for it; the
+	// card is activated once the order is paid
+	PurchaseGiftCard(ctx context.Context, userID uint, purchase GiftCardPurchase) (*entity.GiftCard, *entity.Order, error)
+	GetUserGiftCards(ctx context.Context, userID uint, page, limit int) ([]*entity.GiftCard, int64, error)
+	RedeemGiftCard(ctx context.Context, userID uint, code string) (*entity.Wallet, error)
+	IssueForOrder(ctx context.Context, order *entity.Order) error
+	CancelForOrder(ctx context.Context, orderID uint) error
+
+	// Admin functions
+	IssueGiftCards(ctx context.Context, amount float64, quantity int, expiresAt *time.Time) ([]*entity.GiftCard, error)
+	ListGiftCards(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.GiftCard, int64, error)
+	ListGiftCardsByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.GiftCard, *entity.PageCursors, error)
+	DisableGiftCard(ctx context.Context, id uint) error
+}
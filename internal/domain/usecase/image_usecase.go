@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"mime/multipart"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ImageUseCase defines the interface for processing and storing uploaded images
+type ImageUseCase interface {
+	StoreUpload(ctx context.Context, file *multipart.FileHeader) (*entity.StoredImage, error)
+}
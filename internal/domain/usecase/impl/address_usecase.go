@@ -0,0 +1,122 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type addressUseCase struct {
+	addressRepo repository.AddressRepository
+}
+
+// NewAddressUseCase creates a new AddressUseCase instance
+func NewAddressUseCase(addressRepo repository.AddressRepository) usecase.AddressUseCase {
+	return &addressUseCase{
+		addressRepo: addressRepo,
+	}
+}
+
+// CreateAddress adds an address to a user's address book. The first address
+// becomes the default.
+func (uc *addressUseCase) CreateAddress(ctx context.Context, userID uint, address *entity.Address) (*entity.Address, error) {
+	existing, err := uc.addressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	makeDefault := address.IsDefault || len(existing) == 0
+	address.ID = 0
+	address.UserID = userID
+	address.IsDefault = false
+	if err := uc.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+
+	if makeDefault {
+		if err := uc.addressRepo.SetDefault(ctx, address.ID, userID); err != nil {
+			return nil, err
+		}
+		address.IsDefault = true
+	}
+	return address, nil
+}
+
+// GetAddresses gets a user's addresses
+func (uc *addressUseCase) GetAddresses(ctx context.Context, userID uint) ([]*entity.Address, error) {
+	return uc.addressRepo.GetByUserID(ctx, userID)
+}
+
+// GetAddressByID gets one of a user's addresses
+func (uc *addressUseCase) GetAddressByID(ctx context.Context, id uint, userID uint) (*entity.Address, error) {
+	address, err := uc.addressRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if address.UserID != userID {
+		return nil, errors.New("address not found")
+	}
+	return address, nil
+}
+
+// UpdateAddress updates one of a user's addresses
+func (uc *addressUseCase) UpdateAddress(ctx context.Context, id uint, userID uint, address *entity.Address) (*entity.Address, error) {
+	existing, err := uc.GetAddressByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Label = address.Label
+	existing.Recipient = address.Recipient
+	existing.Phone = address.Phone
+	existing.Province = address.Province
+	existing.City = address.City
+	existing.District = address.District
+	existing.PostalCode = address.PostalCode
+	existing.FullAddress = address.FullAddress
+	if err := uc.addressRepo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	if address.IsDefault && !existing.IsDefault {
+		if err := uc.addressRepo.SetDefault(ctx, existing.ID, userID); err != nil {
+			return nil, err
+		}
+		existing.IsDefault = true
+	}
+	return existing, nil
+}
+
+// DeleteAddress deletes one of a user's addresses. When the default address
+// is deleted, the oldest remaining address becomes the default.
+func (uc *addressUseCase) DeleteAddress(ctx context.Context, id uint, userID uint) error {
+	address, err := uc.GetAddressByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.addressRepo.Delete(ctx, address.ID); err != nil {
+		return err
+	}
+	if !address.IsDefault {
+		return nil
+	}
+
+	remaining, err := uc.addressRepo.GetByUserID(ctx, userID)
+	if err != nil || len(remaining) == 0 {
+		return err
+	}
+	return uc.addressRepo.SetDefault(ctx, remaining[0].ID, userID)
+}
+
+// SetDefaultAddress makes one of a user's addresses the default
+func (uc *addressUseCase) SetDefaultAddress(ctx context.Context, id uint, userID uint) error {
+	return uc.addressRepo.SetDefault(ctx, id, userID)
+}
+
+// GetDefaultAddress gets a user's default address
+func (uc *addressUseCase) GetDefaultAddress(ctx context.Context, userID uint) (*entity.Address, error) {
+	return uc.addressRepo.GetDefaultByUserID(ctx, userID)
+}
@@ -0,0 +1,267 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// attributeCodePattern is the form of attribute codes, used as keys in product attributes
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type attributeUseCase struct {
+	attributeRepo    repository.AttributeRepository
+	categoryTreeRepo repository.CategoryTreeRepository
+}
+
+// NewAttributeUseCase creates a new AttributeUseCase instance
+func NewAttributeUseCase(
+	attributeRepo repository.AttributeRepository,
+	categoryTreeRepo repository.CategoryTreeRepository,
+) usecase.AttributeUseCase {
+	return &attributeUseCase{
+		attributeRepo:    attributeRepo,
+		categoryTreeRepo: categoryTreeRepo,
+	}
+}
+
+// GetCategoryAttributes gets the attributes the products of a category
+// describe, those of its ancestors first
+func (uc *attributeUseCase) GetCategoryAttributes(ctx context.Context, categoryID uint) ([]*entity.AttributeDefinition, error) {
+	ancestors, err := uc.categoryTreeRepo.GetAncestors(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	depths := make(map[uint]int, len(ancestors))
+	ids := make([]uint, len(ancestors))
+	for i, category := range ancestors {
+		depths[category.ID] = category.Depth
+		ids[i] = category.ID
+	}
+
+	attributes, err := uc.attributeRepo.GetByCategoryIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(attributes, func(i, j int) bool {
+		return depths[attributes[i].CategoryID] < depths[attributes[j].CategoryID]
+	})
+	return attributes, nil
+}
+
+// CreateAttribute defines an attribute for the products of a category and
+// its subcategories (admin function)
+func (uc *attributeUseCase) CreateAttribute(ctx context.Context, categoryID uint, attribute *entity.AttributeDefinition) (*entity.AttributeDefinition, error) {
+	attribute.ID = 0
+	attribute.CategoryID = categoryID
+	attribute.Code = strings.TrimSpace(attribute.Code)
+	if !attributeCodePattern.MatchString(attribute.Code) {
+		return nil, errors.New("attribute code must start with a letter and hold only lowercase letters, digits and underscores")
+	}
+	if err := normalizeAttributeDefinition(attribute); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.categoryTreeRepo.GetAncestors(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	exists, err := uc.attributeRepo.CodeExists(ctx, categoryID, attribute.Code, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("attribute %q is already defined on this category, a parent or a subcategory", attribute.Code)
+	}
+
+	if err := uc.attributeRepo.Create(ctx, attribute); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
+
+// UpdateAttribute updates an attribute definition. Its code, type and
+// category are kept so values stored on products stay valid (admin function)
+func (uc *attributeUseCase) UpdateAttribute(ctx context.Context, id uint, attribute *entity.AttributeDefinition) (*entity.AttributeDefinition, error) {
+	existing, err := uc.attributeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attribute.Code != "" && attribute.Code != existing.Code || attribute.Type != "" && attribute.Type != existing.Type {
+		return nil, errors.New("attribute code and type cannot be changed")
+	}
+
+	existing.Name = attribute.Name
+	existing.AllowedValues = attribute.AllowedValues
+	existing.Unit = attribute.Unit
+	existing.Required = attribute.Required
+	existing.Filterable = attribute.Filterable
+	existing.SortOrder = attribute.SortOrder
+	if err := normalizeAttributeDefinition(existing); err != nil {
+		return nil, err
+	}
+
+	if err := uc.attributeRepo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// DeleteAttribute deletes an attribute definition (admin function)
+func (uc *attributeUseCase) DeleteAttribute(ctx context.Context, id uint) error {
+	if _, err := uc.attributeRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.attributeRepo.Delete(ctx, id)
+}
+
+// ValidateProductAttributes checks product attribute values against the
+// attributes of its category and returns them normalized
+func (uc *attributeUseCase) ValidateProductAttributes(ctx context.Context, categoryID uint, attributes entity.ProductAttributes) (entity.ProductAttributes, error) {
+	definitions, err := uc.GetCategoryAttributes(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string]*entity.AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		byCode[definition.Code] = definition
+	}
+	for code := range attributes {
+		if byCode[code] == nil {
+			return nil, fmt.Errorf("attribute %q is not defined for this category", code)
+		}
+	}
+
+	normalized := make(entity.ProductAttributes, len(attributes))
+	for _, definition := range definitions {
+		value, err := normalizeAttributeValue(definition, attributes[definition.Code])
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			if definition.Required {
+				return nil, fmt.Errorf("%s is required", definition.Name)
+			}
+			continue
+		}
+		normalized[definition.Code] = value
+	}
+	return normalized, nil
+}
+
+// normalizeAttributeDefinition trims an attribute definition and checks its
+// settings fit its type
+func normalizeAttributeDefinition(attribute *entity.AttributeDefinition) error {
+	attribute.Name = strings.TrimSpace(attribute.Name)
+	if attribute.Name == "" {
+		return errors.New("attribute name is required")
+	}
+
+	switch attribute.Type {
+	case entity.AttributeTypeSelect, entity.AttributeTypeMultiSelect:
+		attribute.AllowedValues = trimValues(attribute.AllowedValues)
+		if len(attribute.AllowedValues) == 0 {
+			return errors.New("allowed values are required for select attributes")
+		}
+	case entity.AttributeTypeText, entity.AttributeTypeNumber, entity.AttributeTypeBoolean:
+		attribute.AllowedValues = entity.StringList{}
+		if attribute.Type == entity.AttributeTypeText && attribute.Filterable {
+			return errors.New("text attributes cannot be filterable")
+		}
+	default:
+		return errors.New("invalid attribute type")
+	}
+
+	attribute.Unit = strings.TrimSpace(attribute.Unit)
+	if attribute.Type != entity.AttributeTypeNumber {
+		attribute.Unit = ""
+	}
+	return nil
+}
+
+// normalizeAttributeValue checks a product attribute value against its
+// definition, returning nil when no value is given
+func normalizeAttributeValue(definition *entity.AttributeDefinition, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch definition.Type {
+	case entity.AttributeTypeText:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be text", definition.Name)
+		}
+		if text = strings.TrimSpace(text); text == "" {
+			return nil, nil
+		}
+		return text, nil
+
+	case entity.AttributeTypeNumber:
+		switch number := value.(type) {
+		case float64:
+			return number, nil
+		case int:
+			return float64(number), nil
+		default:
+			return nil, fmt.Errorf("%s must be a number", definition.Name)
+		}
+
+	case entity.AttributeTypeBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be true or false", definition.Name)
+		}
+		return flag, nil
+
+	case entity.AttributeTypeSelect:
+		choice, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be one of %s", definition.Name, strings.Join(definition.AllowedValues, ", "))
+		}
+		if choice = strings.TrimSpace(choice); choice == "" {
+			return nil, nil
+		}
+		if !containsString(definition.AllowedValues, choice) {
+			return nil, fmt.Errorf("%s must be one of %s", definition.Name, strings.Join(definition.AllowedValues, ", "))
+		}
+		return choice, nil
+
+	case entity.AttributeTypeMultiSelect:
+		var choices []string
+		switch list := value.(type) {
+		case []interface{}:
+			for _, item := range list {
+				choice, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s must be a list of text values", definition.Name)
+				}
+				choices = append(choices, choice)
+			}
+		case []string:
+			choices = list
+		default:
+			return nil, fmt.Errorf("%s must be a list of text values", definition.Name)
+		}
+
+		choices = trimValues(choices)
+		for _, choice := range choices {
+			if !containsString(definition.AllowedValues, choice) {
+				return nil, fmt.Errorf("%s values must be among %s", definition.Name, strings.Join(definition.AllowedValues, ", "))
+			}
+		}
+		if len(choices) == 0 {
+			return nil, nil
+		}
+		return choices, nil
+	}
+	return nil, fmt.Errorf("attribute %q has an unknown type", definition.Code)
+}
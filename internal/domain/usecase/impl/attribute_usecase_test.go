@@ -0,0 +1,30 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+)
+
+func TestCreateAttributeRefusesCodeDefinedConcurrently(t *testing.T) {
+	// The check passes, but another definition of the code along the path
+	// was committed before this one and the database refuses it
+	attributes := &fakeAttributeRepo{createErr: repository.ErrAttributeCodeExists}
+	uc := &attributeUseCase{
+		attributeRepo:    attributes,
+		categoryTreeRepo: &fakeCategoryTreeRepo{categories: map[uint]*entity.Category{3: {ID: 3, Path: "/1/3/"}}},
+	}
+
+	_, err := uc.CreateAttribute(context.Background(), 3, &entity.AttributeDefinition{
+		Code: "material", Name: "Material", Type: entity.AttributeTypeSelect, AllowedValues: entity.StringList{"Katun"},
+	})
+	if !errors.Is(err, repository.ErrAttributeCodeExists) {
+		t.Fatalf("CreateAttribute() error = %v, want ErrAttributeCodeExists", err)
+	}
+	if len(attributes.created) != 0 {
+		t.Errorf("created %d attributes, want none", len(attributes.created))
+	}
+}
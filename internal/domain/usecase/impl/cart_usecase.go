@@ -0,0 +1,162 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type cartUseCase struct {
+	cartRepo         repository.CartRepository
+	productRepo      repository.ProductRepository
+	variantRepo      repository.ProductVariantRepository
+	promotionUseCase usecase.PromotionUseCase
+}
+
+// NewCartUseCase creates a new CartUseCase instance
+func NewCartUseCase(
+	cartRepo repository.CartRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	promotionUseCase usecase.PromotionUseCase,
+) usecase.CartUseCase {
+	return &cartUseCase{
+		cartRepo:         cartRepo,
+		productRepo:      productRepo,
+		variantRepo:      variantRepo,
+		promotionUseCase: promotionUseCase,
+	}
+}
+
+// GetCart gets the cart of a user, creating an empty one on first use
+func (uc *cartUseCase) GetCart(ctx context.Context, userID uint) (*entity.Cart, error) {
+	return uc.cartRepo.GetOrCreate(ctx, userID)
+}
+
+// AddToCart adds a variant of an active product to the user's cart. Stock is
+// checked against what the cart will hold but only taken at checkout.
+func (uc *cartUseCase) AddToCart(ctx context.Context, userID, productID, variantID uint, quantity int) error {
+	if quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if !product.IsActive {
+		return errors.New("product is not available")
+	}
+	variant, err := uc.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return err
+	}
+	if variant.ProductID != productID {
+		return errors.New("variant does not belong to this product")
+	}
+	if !variant.IsActive {
+		return errors.New("variant is not available")
+	}
+
+	cart, err := uc.cartRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return err
+	}
+	inCart := 0
+	for _, item := range cart.Items {
+		if item.VariantID == variantID {
+			inCart += item.Quantity
+		}
+	}
+	if variant.Stock < inCart+quantity {
+		return fmt.Errorf("insufficient stock for %s, %d left", product.Name, variant.Stock)
+	}
+
+	return uc.cartRepo.AddItem(ctx, cart.ID, &entity.CartItem{
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+	})
+}
+
+// UpdateCartItem sets the quantity of an item in the user's cart
+func (uc *cartUseCase) UpdateCartItem(ctx context.Context, userID, itemID uint, quantity int) error {
+	if quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+
+	item, err := uc.getOwnedItem(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+	variant, err := uc.variantRepo.GetByID(ctx, item.VariantID)
+	if err != nil {
+		return err
+	}
+	if !variant.IsActive {
+		return errors.New("variant is not available")
+	}
+	if variant.Stock < quantity {
+		return fmt.Errorf("insufficient stock, %d left", variant.Stock)
+	}
+
+	item.Quantity = quantity
+	return uc.cartRepo.UpdateItem(ctx, item)
+}
+
+// RemoveFromCart removes an item from the user's cart
+func (uc *cartUseCase) RemoveFromCart(ctx context.Context, userID, itemID uint) error {
+	if _, err := uc.getOwnedItem(ctx, userID, itemID); err != nil {
+		return err
+	}
+	return uc.cartRepo.RemoveItem(ctx, itemID)
+}
+
+// ClearCart removes every item from the user's cart
+func (uc *cartUseCase) ClearCart(ctx context.Context, userID uint) error {
+	cart, err := uc.cartRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return uc.cartRepo.ClearCart(ctx, cart.ID)
+}
+
+// GetCartTotals gets the number of units in the user's cart and what they
+// cost after the running promotions
+func (uc *cartUseCase) GetCartTotals(ctx context.Context, userID uint) (int, float64, error) {
+	cart, err := uc.cartRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	totalItems, err := uc.cartRepo.GetTotalItems(ctx, cart.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if totalItems == 0 {
+		return 0, 0, nil
+	}
+
+	result, err := uc.promotionUseCase.EvaluateCart(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return totalItems, result.Total, nil
+}
+
+// getOwnedItem gets an item of the user's cart
+func (uc *cartUseCase) getOwnedItem(ctx context.Context, userID, itemID uint) (*entity.CartItem, error) {
+	cart, err := uc.cartRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cart.Items {
+		if cart.Items[i].ID == itemID {
+			return &cart.Items[i], nil
+		}
+	}
+	return nil, errors.New("cart item not found")
+}
@@ -0,0 +1,24 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestAddToCartRejectsInactiveVariant(t *testing.T) {
+	cart := &entity.Cart{ID: 1, UserID: 7}
+	uc := &cartUseCase{
+		cartRepo:    &fakeCartRepo{cart: cart},
+		productRepo: newFakeProductRepo(&entity.Product{ID: 1, Name: "Linen Shirt", Price: 100000, IsActive: true}),
+		variantRepo: newFakeVariantRepo(&entity.ProductVariant{ID: 10, ProductID: 1, Stock: 5, IsActive: false}),
+	}
+
+	if err := uc.AddToCart(context.Background(), 7, 1, 10, 1); err == nil {
+		t.Fatal("AddToCart() succeeded with a deactivated variant")
+	}
+	if len(cart.Items) != 0 {
+		t.Errorf("cart holds %d items, want none", len(cart.Items))
+	}
+}
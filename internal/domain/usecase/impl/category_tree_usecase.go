@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type categoryTreeUseCase struct {
+	categoryTreeRepo repository.CategoryTreeRepository
+	categoryRepo     repository.CategoryRepository
+	productRepo      repository.ProductRepository
+}
+
+// NewCategoryTreeUseCase creates a new CategoryTreeUseCase instance
+func NewCategoryTreeUseCase(
+	categoryTreeRepo repository.CategoryTreeRepository,
+	categoryRepo repository.CategoryRepository,
+	productRepo repository.ProductRepository,
+) usecase.CategoryTreeUseCase {
+	return &categoryTreeUseCase{
+		categoryTreeRepo: categoryTreeRepo,
+		categoryRepo:     categoryRepo,
+		productRepo:      productRepo,
+	}
+}
+
+// GetTree gets every top-level category with its subcategories nested inside
+func (uc *categoryTreeUseCase) GetTree(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := uc.categoryTreeRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+// GetSubtree gets a category with its subcategories nested inside
+func (uc *categoryTreeUseCase) GetSubtree(ctx context.Context, id uint) (*entity.Category, error) {
+	categories, err := uc.categoryTreeRepo.GetSubtree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := buildCategoryTree(categories)
+	if len(roots) == 0 {
+		return nil, errors.New("category not found")
+	}
+	return roots[0], nil
+}
+
+// GetBreadcrumbs gets the categories from the root down to a category
+func (uc *categoryTreeUseCase) GetBreadcrumbs(ctx context.Context, categoryID uint) ([]*entity.Category, error) {
+	return uc.categoryTreeRepo.GetAncestors(ctx, categoryID)
+}
+
+// GetProductBreadcrumbs gets the categories from the root down to the category of a product
+func (uc *categoryTreeUseCase) GetProductBreadcrumbs(ctx context.Context, productID uint) ([]*entity.Category, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsActive {
+		return nil, errors.New("product not found")
+	}
+	return uc.categoryTreeRepo.GetAncestors(ctx, product.CategoryID)
+}
+
+// MoveCategory moves a category under another parent, or to the top level
+// when parentID is nil, at a position among its new siblings (admin function)
+func (uc *categoryTreeUseCase) MoveCategory(ctx context.Context, id uint, parentID *uint, position int) (*entity.Category, error) {
+	if parentID != nil && *parentID == id {
+		return nil, repository.ErrCategoryCycle
+	}
+	if position < 0 {
+		return nil, errors.New("position cannot be negative")
+	}
+
+	if err := uc.categoryTreeRepo.Move(ctx, id, parentID, position); err != nil {
+		return nil, err
+	}
+	return uc.categoryRepo.GetByID(ctx, id)
+}
+
+// DeleteCategory deletes a category, by default only when it has no
+// subcategories or products (admin function)
+func (uc *categoryTreeUseCase) DeleteCategory(ctx context.Context, id uint, rule entity.CategoryDeleteRule) error {
+	switch rule {
+	case "":
+		rule = entity.CategoryDeleteRestrict
+	case entity.CategoryDeleteRestrict, entity.CategoryDeleteReparent, entity.CategoryDeleteCascade:
+	default:
+		return errors.New("invalid delete rule")
+	}
+	return uc.categoryTreeRepo.Delete(ctx, id, rule)
+}
+
+// buildCategoryTree nests categories listed parents first under their
+// parents, returning the ones whose parent is not in the list
+func buildCategoryTree(categories []*entity.Category) []*entity.Category {
+	byID := make(map[uint]*entity.Category, len(categories))
+	roots := make([]*entity.Category, 0)
+	for _, category := range categories {
+		category.Children = nil
+		byID[category.ID] = category
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+	return roots
+}
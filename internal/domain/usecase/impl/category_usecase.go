@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/storage"
+)
+
+type categoryUseCase struct {
+	categoryRepo     repository.CategoryRepository
+	categoryTreeRepo repository.CategoryTreeRepository
+	store            storage.Storage
+}
+
+// NewCategoryUseCase creates a new CategoryUseCase instance
+func NewCategoryUseCase(
+	categoryRepo repository.CategoryRepository,
+	categoryTreeRepo repository.CategoryTreeRepository,
+	store storage.Storage,
+) usecase.CategoryUseCase {
+	return &categoryUseCase{
+		categoryRepo:     categoryRepo,
+		categoryTreeRepo: categoryTreeRepo,
+		store:            store,
+	}
+}
+
+// CreateCategory creates a category, at the end of its parent's subcategories (admin function)
+func (uc *categoryUseCase) CreateCategory(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if category.Slug == "" {
+		category.Slug = slugify(category.Name)
+	}
+	if existing, err := uc.categoryRepo.GetBySlug(ctx, category.Slug); err == nil && existing != nil {
+		return nil, errors.New("slug is already used by another category")
+	}
+
+	siblings, err := uc.categoryRepo.List(ctx, category.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if category.ParentID != nil {
+		if _, err := uc.categoryRepo.GetByID(ctx, *category.ParentID); err != nil {
+			return nil, fmt.Errorf("parent %w", err)
+		}
+	}
+	category.SortOrder = len(siblings)
+
+	if err := uc.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// GetCategoryByID gets a category by ID
+func (uc *categoryUseCase) GetCategoryByID(ctx context.Context, id uint) (*entity.Category, error) {
+	return uc.categoryRepo.GetByID(ctx, id)
+}
+
+// GetCategoryBySlug gets a category by slug
+func (uc *categoryUseCase) GetCategoryBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	return uc.categoryRepo.GetBySlug(ctx, slug)
+}
+
+// UpdateCategory updates the name, slug and description of a category; it is
+// moved within the tree through the CategoryTreeUseCase (admin function)
+func (uc *categoryUseCase) UpdateCategory(ctx context.Context, id uint, category *entity.Category) (*entity.Category, error) {
+	existing, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(category.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if category.Slug != "" && category.Slug != existing.Slug {
+		if other, err := uc.categoryRepo.GetBySlug(ctx, category.Slug); err == nil && other.ID != id {
+			return nil, errors.New("slug is already used by another category")
+		}
+		existing.Slug = category.Slug
+	}
+
+	existing.Name = name
+	existing.Description = category.Description
+	if err := uc.categoryRepo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// DeleteCategory deletes a category that has no subcategories or products (admin function)
+func (uc *categoryUseCase) DeleteCategory(ctx context.Context, id uint) error {
+	return uc.categoryTreeRepo.Delete(ctx, id, entity.CategoryDeleteRestrict)
+}
+
+// ListCategories lists the subcategories of a category, or the top-level
+// categories when parentID is nil
+func (uc *categoryUseCase) ListCategories(ctx context.Context, parentID *uint) ([]*entity.Category, error) {
+	return uc.categoryRepo.List(ctx, parentID)
+}
+
+// UploadCategoryImage stores the image of a category as it is (admin function)
+func (uc *categoryUseCase) UploadCategoryImage(ctx context.Context, id uint, file *multipart.FileHeader) (*entity.Category, error) {
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := saveUpload(ctx, uc.store, fmt.Sprintf("categories/%d", id), file)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Image = url
+	if err := uc.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
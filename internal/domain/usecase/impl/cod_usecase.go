@@ -0,0 +1,631 @@
+package impl
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// codProvider is the payment provider recorded on cash-on-delivery payments
+const codProvider = "cod"
+
+// maxRemittanceRows caps the size of an imported remittance report
+const maxRemittanceRows = 10000
+
+// remittanceColumns maps the accepted report headers to the fields they hold
+var remittanceColumns = map[string]string{
+	"tracking_number": "tracking_number",
+	"awb":             "tracking_number",
+	"waybill":         "tracking_number",
+	"order_number":    "order_number",
+	"reference":       "order_number",
+	"amount":          "amount",
+	"cod_amount":      "amount",
+	"remitted_at":     "remitted_at",
+	"date":            "remitted_at",
+}
+
+// CODPolicy holds the rules an order must meet to be paid on delivery
+type CODPolicy struct {
+	Enabled       bool
+	MaxOrderValue float64  // zero means no limit
+	Cities        []string // empty allows every city
+	Couriers      []string // empty allows every courier
+}
+
+type codUseCase struct {
+	paymentRepo       repository.PaymentRepository
+	orderRepo         repository.OrderRepository
+	orderStatusRepo   repository.OrderStatusHistoryRepository
+	codRemittanceRepo repository.CODRemittanceRepository
+	voucherUseCase    usecase.VoucherUseCase
+	flashSaleUseCase  usecase.FlashSaleUseCase
+	loyaltyUseCase    usecase.LoyaltyUseCase
+	walletUseCase     usecase.WalletUseCase
+	policy            CODPolicy
+}
+
+// NewCODUseCase creates a new CODUseCase instance
+func NewCODUseCase(
+	paymentRepo repository.PaymentRepository,
+	orderRepo repository.OrderRepository,
+	orderStatusRepo repository.OrderStatusHistoryRepository,
+	codRemittanceRepo repository.CODRemittanceRepository,
+	voucherUseCase usecase.VoucherUseCase,
+	flashSaleUseCase usecase.FlashSaleUseCase,
+	loyaltyUseCase usecase.LoyaltyUseCase,
+	walletUseCase usecase.WalletUseCase,
+	policy CODPolicy,
+) usecase.CODUseCase {
+	return &codUseCase{
+		paymentRepo:       paymentRepo,
+		orderRepo:         orderRepo,
+		orderStatusRepo:   orderStatusRepo,
+		codRemittanceRepo: codRemittanceRepo,
+		voucherUseCase:    voucherUseCase,
+		flashSaleUseCase:  flashSaleUseCase,
+		loyaltyUseCase:    loyaltyUseCase,
+		walletUseCase:     walletUseCase,
+		policy:            policy,
+	}
+}
+
+// CheckEligibility checks whether an order can be paid on delivery
+func (uc *codUseCase) CheckEligibility(ctx context.Context, orderID, userID uint) error {
+	order, err := uc.getUserOrder(ctx, orderID, userID)
+	if err != nil {
+		return err
+	}
+	return uc.checkEligibility(order)
+}
+
+// PlaceCODPayment records a cash-on-delivery payment without going through a
+// gateway and releases the order for fulfilment
+func (uc *codUseCase) PlaceCODPayment(ctx context.Context, orderID, userID uint) (*entity.Payment, error) {
+	order, err := uc.getUserOrder(ctx, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != entity.OrderStatusPending {
+		return nil, errors.New("order is not awaiting payment")
+	}
+
+	if err := uc.checkEligibility(order); err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, order.ID)
+	if err == nil {
+		if payment.PaymentMethod == entity.PaymentMethodCOD {
+			return payment, nil
+		}
+		if payment.Status != entity.PaymentStatusPending || payment.TransactionID != "" {
+			return nil, errors.New("an online payment has already been started for this order")
+		}
+	} else {
+		payment = &entity.Payment{OrderID: order.ID}
+	}
+
+	// Cash is collected by the courier, so nothing expires and no gateway is involved
+	payment.PaymentMethod = entity.PaymentMethodCOD
+	payment.Provider = codProvider
+	payment.Channel = ""
+	payment.Amount = order.FinalAmount - order.WalletAmount
+	payment.WalletAmount = order.WalletAmount
+	payment.Status = entity.PaymentStatusPending
+	payment.PaymentURL = ""
+	payment.ExpiredAt = nil
+
+	if payment.ID == 0 {
+		if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+			return nil, err
+		}
+	} else if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.orderRepo.SetPayment(ctx, order.ID, payment.ID); err != nil {
+		return nil, err
+	}
+	order.PaymentID = &payment.ID
+
+	if err := transitionOrder(ctx, uc.orderStatusRepo, order, entity.OrderStatusProcessing, customerActor(userID), "Cash on delivery chosen"); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// ConfirmCODPayment marks a cash-on-delivery payment paid once the courier has
+// confirmed delivery and remitted the cash (admin function)
+func (uc *codUseCase) ConfirmCODPayment(ctx context.Context, paymentID uint, amount float64) error {
+	payment, order, err := uc.getCODPayment(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status != entity.PaymentStatusPending {
+		return errors.New("payment is already " + string(payment.Status))
+	}
+
+	if order.Status != entity.OrderStatusShipped && order.Status != entity.OrderStatusDelivered {
+		return errors.New("order has not been shipped yet")
+	}
+
+	if math.Abs(amount-payment.Amount) > 0.01 {
+		return fmt.Errorf("remitted amount %.2f does not match payment amount %.2f", amount, payment.Amount)
+	}
+
+	applied, err := uc.settle(ctx, payment, order, nil)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("payment was updated concurrently")
+	}
+
+	return nil
+}
+
+// MarkCODUndelivered fails a cash-on-delivery payment when the courier could
+// not pick up, deliver or collect before the order shipped, cancels the order,
+// restocks its items and gives back its voucher use and flash sale quota.
+// Orders are only cancelled before they ship (admin function).
+func (uc *codUseCase) MarkCODUndelivered(ctx context.Context, paymentID uint, reason string) error {
+	payment, order, err := uc.getCODPayment(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status != entity.PaymentStatusPending {
+		return errors.New("payment is already " + string(payment.Status))
+	}
+
+	if order.Status != entity.OrderStatusProcessing {
+		return &usecase.InvalidOrderTransitionError{From: order.Status, To: entity.OrderStatusCancelled}
+	}
+
+	payment.Status = entity.PaymentStatusFailed
+	applied, err := uc.paymentRepo.UpdateIfStatus(ctx, payment, entity.PaymentStatusPending)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("payment was updated concurrently")
+	}
+
+	note := "Not delivered"
+	if reason != "" {
+		note += ": " + reason
+	}
+	if err := transitionOrder(ctx, uc.orderStatusRepo, order, entity.OrderStatusCancelled, systemActor, note); err != nil {
+		return err
+	}
+
+	if err := uc.voucherUseCase.ReleaseForOrder(ctx, order.ID); err != nil {
+		return err
+	}
+	if err := uc.flashSaleUseCase.ReleaseForOrder(ctx, order.ID); err != nil {
+		return err
+	}
+	if err := uc.loyaltyUseCase.ReleaseForOrder(ctx, order.ID); err != nil {
+		return err
+	}
+	if err := uc.walletUseCase.ReleaseForOrder(ctx, order.ID); err != nil {
+		return err
+	}
+	return uc.orderRepo.MarkHoldsReleased(ctx, order.ID)
+}
+
+// ImportRemittanceReport imports a courier remittance CSV, matches every line
+// against cash-on-delivery orders by order number or tracking number, and
+// settles the payments that match (admin function)
+func (uc *codUseCase) ImportRemittanceReport(ctx context.Context, courier, filename string, report io.Reader, importedBy uint) (*entity.CODRemittanceBatch, error) {
+	lines, err := parseRemittanceReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &entity.CODRemittanceBatch{
+		Courier:    strings.ToLower(courier),
+		Filename:   filename,
+		TotalRows:  len(lines),
+		ImportedBy: importedBy,
+		Lines:      make([]entity.CODRemittance, 0, len(lines)),
+	}
+
+	var settlements []*repository.CODSettlement
+	for i, line := range lines {
+		settlement, err := uc.matchRemittance(ctx, batch.Courier, line)
+		if err != nil {
+			return nil, err
+		}
+		if settlement != nil {
+			settlement.Line = i
+			settlements = append(settlements, settlement)
+		}
+
+		batch.TotalAmount += line.Amount
+		switch line.Status {
+		case entity.CODRemittanceStatusMatched:
+			batch.MatchedRows++
+		case entity.CODRemittanceStatusAmountMismatch:
+			batch.MismatchedRows++
+		case entity.CODRemittanceStatusDuplicate:
+			batch.DuplicateRows++
+		default:
+			batch.UnmatchedRows++
+		}
+		batch.Lines = append(batch.Lines, *line)
+	}
+
+	if err := uc.codRemittanceRepo.CreateBatch(ctx, batch, settlements); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// GetRemittanceBatch gets an imported remittance report with its lines (admin function)
+func (uc *codUseCase) GetRemittanceBatch(ctx context.Context, id uint) (*entity.CODRemittanceBatch, error) {
+	return uc.codRemittanceRepo.GetBatchByID(ctx, id)
+}
+
+// ListRemittanceBatches lists imported remittance reports (admin function)
+func (uc *codUseCase) ListRemittanceBatches(ctx context.Context, page, limit int) ([]*entity.CODRemittanceBatch, int64, error) {
+	offset := (page - 1) * limit
+	return uc.codRemittanceRepo.ListBatches(ctx, offset, limit)
+}
+
+// ListRemittanceBatchesByCursor lists remittance batches, one page after or before a cursor (admin function)
+func (uc *codUseCase) ListRemittanceBatchesByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.CODRemittanceBatch, *entity.PageCursors, error) {
+	return uc.codRemittanceRepo.ListBatchesByCursor(ctx, page)
+}
+
+// matchRemittance matches a remittance line to its order and, when the courier
+// and amount agree, returns the settlement of its payment. Only database
+// failures are returned as errors.
+func (uc *codUseCase) matchRemittance(ctx context.Context, courier string, line *entity.CODRemittance) (*repository.CODSettlement, error) {
+	var order *entity.Order
+	var err error
+	if line.OrderNumber != "" {
+		order, err = uc.orderRepo.GetByOrderNumber(ctx, line.OrderNumber)
+	} else {
+		order, err = uc.orderRepo.GetByTrackingNumber(ctx, line.TrackingNumber)
+	}
+	if err != nil {
+		line.Status = entity.CODRemittanceStatusUnmatched
+		line.Message = "order not found"
+		return nil, nil
+	}
+	line.OrderID = &order.ID
+
+	// A courier only remits cash for the parcels it carried
+	if shippedWith := courierOf(order.ShippingMethod); !strings.EqualFold(shippedWith, courier) {
+		line.Status = entity.CODRemittanceStatusUnmatched
+		line.Message = "order was shipped with " + shippedWith
+		return nil, nil
+	}
+	if line.TrackingNumber != "" && !strings.EqualFold(line.TrackingNumber, order.ShippingTrackingNumber) {
+		line.Status = entity.CODRemittanceStatusUnmatched
+		line.Message = "tracking number does not match the shipment"
+		return nil, nil
+	}
+
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil || payment.PaymentMethod != entity.PaymentMethodCOD {
+		line.Status = entity.CODRemittanceStatusUnmatched
+		line.Message = "order is not cash on delivery"
+		return nil, nil
+	}
+	line.PaymentID = &payment.ID
+
+	switch payment.Status {
+	case entity.PaymentStatusPending:
+	case entity.PaymentStatusPaid:
+		line.Status = entity.CODRemittanceStatusDuplicate
+		line.Message = "payment was already settled"
+		return nil, nil
+	default:
+		line.Status = entity.CODRemittanceStatusUnmatched
+		line.Message = "payment is " + string(payment.Status)
+		return nil, nil
+	}
+
+	if math.Abs(line.Amount-payment.Amount) > 0.01 {
+		line.Status = entity.CODRemittanceStatusAmountMismatch
+		line.Message = fmt.Sprintf("expected %.2f", payment.Amount)
+		return nil, nil
+	}
+
+	line.Status = entity.CODRemittanceStatusMatched
+	settlement := &repository.CODSettlement{
+		PaymentID: payment.ID,
+		PaidAt:    time.Now(),
+		Changes:   deliveryChanges(order, "Cash remitted by courier"),
+	}
+	if line.RemittedAt != nil {
+		settlement.PaidAt = *line.RemittedAt
+	}
+	return settlement, nil
+}
+
+// deliveryChanges returns the status changes that take an order to delivered.
+// The courier only remits cash for parcels it delivered, so an order still
+// marked processing was shipped and delivered without being updated.
+func deliveryChanges(order *entity.Order, note string) []*entity.OrderStatusHistory {
+	var changes []*entity.OrderStatusHistory
+	from := order.Status
+	for _, to := range []entity.OrderStatus{entity.OrderStatusShipped, entity.OrderStatusDelivered} {
+		if !canTransitionOrder(from, to) {
+			continue
+		}
+		changes = append(changes, &entity.OrderStatusHistory{
+			OrderID:    order.ID,
+			FromStatus: from,
+			ToStatus:   to,
+			ActorType:  systemActor.Type,
+			ActorID:    systemActor.ID,
+			Note:       note,
+		})
+		from = to
+	}
+	return changes
+}
+
+// settle marks a pending cash-on-delivery payment paid and its order delivered.
+// It returns false if the payment was no longer pending.
+func (uc *codUseCase) settle(ctx context.Context, payment *entity.Payment, order *entity.Order, paidAt *time.Time) (bool, error) {
+	if paidAt == nil {
+		now := time.Now()
+		paidAt = &now
+	}
+
+	payment.Status = entity.PaymentStatusPaid
+	payment.PaidAt = paidAt
+	applied, err := uc.paymentRepo.UpdateIfStatus(ctx, payment, entity.PaymentStatusPending)
+	if err != nil || !applied {
+		return false, err
+	}
+
+	// The courier only remits cash for parcels it delivered, so an order still
+	// marked processing was shipped and delivered without being updated
+	if order.Status == entity.OrderStatusProcessing {
+		if err := transitionOrder(ctx, uc.orderStatusRepo, order, entity.OrderStatusShipped, systemActor, "Cash remitted by courier"); err != nil {
+			return true, err
+		}
+	}
+	if order.Status == entity.OrderStatusShipped {
+		if err := transitionOrder(ctx, uc.orderStatusRepo, order, entity.OrderStatusDelivered, systemActor, "Cash remitted by courier"); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
+// checkEligibility applies the cash-on-delivery policy to an order
+func (uc *codUseCase) checkEligibility(order *entity.Order) error {
+	if !uc.policy.Enabled {
+		return errors.New("cash on delivery is not available")
+	}
+
+	if uc.policy.MaxOrderValue > 0 && order.FinalAmount > uc.policy.MaxOrderValue {
+		return fmt.Errorf("cash on delivery is only available for orders up to %.0f", uc.policy.MaxOrderValue)
+	}
+
+	if len(uc.policy.Cities) > 0 && !containsFold(uc.policy.Cities, order.ShippingAddress.City) {
+		return fmt.Errorf("cash on delivery is not available in %s", order.ShippingAddress.City)
+	}
+
+	courier := courierOf(order.ShippingMethod)
+	if len(uc.policy.Couriers) > 0 && !containsFold(uc.policy.Couriers, courier) {
+		return fmt.Errorf("cash on delivery is not available with courier %s", courier)
+	}
+
+	return nil
+}
+
+// getUserOrder gets an order and checks that it belongs to the user
+func (uc *codUseCase) getUserOrder(ctx context.Context, orderID, userID uint) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	return order, nil
+}
+
+// getCODPayment gets a cash-on-delivery payment with its order
+func (uc *codUseCase) getCODPayment(ctx context.Context, paymentID uint) (*entity.Payment, *entity.Order, error) {
+	payment, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment.PaymentMethod != entity.PaymentMethodCOD {
+		return nil, nil, errors.New("payment is not cash on delivery")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, payment.OrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payment, order, nil
+}
+
+// parseRemittanceReport reads the lines of a remittance CSV. The header row
+// must name a tracking number or order number column and an amount column.
+func parseRemittanceReport(report io.Reader) ([]*entity.CODRemittance, error) {
+	reader := csv.NewReader(report)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("remittance report is empty or not a CSV file")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := remittanceColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+
+	_, hasTracking := columns["tracking_number"]
+	_, hasOrder := columns["order_number"]
+	if _, ok := columns["amount"]; !ok || (!hasTracking && !hasOrder) {
+		return nil, errors.New("remittance report needs an amount column and a tracking_number or order_number column")
+	}
+
+	var lines []*entity.CODRemittance
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		if len(lines) >= maxRemittanceRows {
+			return nil, fmt.Errorf("remittance report has more than %d rows", maxRemittanceRows)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line := &entity.CODRemittance{
+			TrackingNumber: field("tracking_number"),
+			OrderNumber:    field("order_number"),
+		}
+		if line.TrackingNumber == "" && line.OrderNumber == "" {
+			// Skip blank and summary rows
+			continue
+		}
+
+		amount, err := parseAmount(field("amount"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount %q", row, field("amount"))
+		}
+		line.Amount = amount
+
+		if value := field("remitted_at"); value != "" {
+			remittedAt, err := parseReportDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid date %q", row, value)
+			}
+			line.RemittedAt = &remittedAt
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("remittance report has no rows")
+	}
+
+	return lines, nil
+}
+
+// parseAmount parses a rupiah amount such as "150000", "150.000", "Rp 150,000",
+// "150000.00" or "1.500.000,50". A separator followed by three digits groups
+// thousands unless a different separator comes before it; any other last
+// separator is the decimal point. Negative amounts are rejected.
+func parseAmount(value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	for _, prefix := range []string{"rp.", "rp", "idr"} {
+		if strings.HasPrefix(strings.ToLower(s), prefix) {
+			s = strings.TrimSpace(s[len(prefix):])
+			break
+		}
+	}
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "(") {
+		return 0, errors.New("amount is negative")
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != '.' && r != ',' {
+			return 0, fmt.Errorf("unexpected character %q in amount", r)
+		}
+	}
+
+	whole, decimals := s, ""
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 {
+		separator := s[i : i+1]
+		mixed := strings.ContainsAny(s[:i], strings.Trim(".,", separator))
+		if mixed || (strings.Count(s, separator) == 1 && len(s)-i-1 != 3) {
+			whole, decimals = s[:i], s[i+1:]
+		}
+	}
+	if len(decimals) > 2 {
+		return 0, errors.New("amount has more than two decimals")
+	}
+
+	// Every group after the first holds exactly three digits
+	groups := strings.FieldsFunc(whole, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) == 0 || len(groups) != strings.Count(whole, ".")+strings.Count(whole, ",")+1 {
+		return 0, errors.New("amount has no digits")
+	}
+	if strings.Contains(whole, ".") && strings.Contains(whole, ",") {
+		return 0, errors.New("amount mixes thousands separators")
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return 0, errors.New("amount has a misplaced thousands separator")
+		}
+	}
+
+	number := strings.Join(groups, "")
+	if decimals != "" {
+		number += "." + decimals
+	}
+	return strconv.ParseFloat(number, 64)
+}
+
+// parseReportDate parses the date formats couriers use in their reports
+func parseReportDate(value string) (time.Time, error) {
+	layouts := []string{"2006-01-02 15:04:05", "2006-01-02", "02/01/2006 15:04", "02/01/2006", "02-01-2006"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unknown date format")
+}
+
+// courierOf extracts the courier code from a shipping method such as "jne:REG" or "JNE REG"
+func courierOf(shippingMethod string) string {
+	method := strings.ToLower(strings.TrimSpace(shippingMethod))
+	if i := strings.IndexAny(method, ": -_/"); i >= 0 {
+		method = method[:i]
+	}
+	return method
+}
+
+// containsFold reports whether a slice contains a string, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
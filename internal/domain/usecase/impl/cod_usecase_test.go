@@ -0,0 +1,138 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "150000", want: 150000},
+		{value: "150.000", want: 150000},
+		{value: "Rp 150,000", want: 150000},
+		{value: "Rp. 1.500.000", want: 1500000},
+		{value: "150000.00", want: 150000},
+		{value: "150000,50", want: 150000.5},
+		{value: "1.500.000,50", want: 1500000.5},
+		{value: "1,500,000.50", want: 1500000.5},
+		{value: "-150000", wantErr: true},
+		{value: "Rp -150.000", wantErr: true},
+		{value: "(150000)", wantErr: true},
+		{value: "1.50.000", wantErr: true},
+		{value: "1,500.000", wantErr: true},
+		{value: "150k", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAmount(%q) = %v, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+// fakeCODRemittanceRepo keeps the last created batch and its settlements
+type fakeCODRemittanceRepo struct {
+	repository.CODRemittanceRepository
+	batch       *entity.CODRemittanceBatch
+	settlements []*repository.CODSettlement
+}
+
+func (r *fakeCODRemittanceRepo) CreateBatch(ctx context.Context, batch *entity.CODRemittanceBatch, settlements []*repository.CODSettlement) error {
+	r.batch = batch
+	r.settlements = settlements
+	return nil
+}
+
+func TestImportRemittanceReportChecksTheShipment(t *testing.T) {
+	orders := newFakeOrderRepo(
+		&entity.Order{ID: 1, OrderNumber: "ORD-1", Status: entity.OrderStatusShipped, ShippingMethod: "jne:REG", ShippingTrackingNumber: "JNE1"},
+		&entity.Order{ID: 2, OrderNumber: "ORD-2", Status: entity.OrderStatusShipped, ShippingMethod: "sicepat:REG", ShippingTrackingNumber: "SCP2"},
+		&entity.Order{ID: 3, OrderNumber: "ORD-3", Status: entity.OrderStatusProcessing, ShippingMethod: "jne:YES", ShippingTrackingNumber: "JNE3"},
+	)
+	payments := newFakePaymentRepo(
+		&entity.Payment{ID: 1, OrderID: 1, PaymentMethod: entity.PaymentMethodCOD, Amount: 150000, Status: entity.PaymentStatusPending},
+		&entity.Payment{ID: 2, OrderID: 2, PaymentMethod: entity.PaymentMethodCOD, Amount: 80000, Status: entity.PaymentStatusPending},
+		&entity.Payment{ID: 3, OrderID: 3, PaymentMethod: entity.PaymentMethodCOD, Amount: 99000, Status: entity.PaymentStatusPending},
+	)
+	remittances := &fakeCODRemittanceRepo{}
+	uc := &codUseCase{paymentRepo: payments, orderRepo: orders, codRemittanceRepo: remittances}
+
+	report := "order_number,awb,amount\n" +
+		"ORD-1,JNE1,\"Rp 150.000\"\n" + // matched
+		"ORD-2,SCP2,80000\n" + // shipped with another courier
+		"ORD-3,JNE9,99000\n" // tracking number of another parcel
+	batch, err := uc.ImportRemittanceReport(context.Background(), "JNE", "jne.csv", strings.NewReader(report), 1)
+	if err != nil {
+		t.Fatalf("ImportRemittanceReport() error = %v", err)
+	}
+
+	if batch.MatchedRows != 1 || batch.UnmatchedRows != 2 {
+		t.Fatalf("matched %d, unmatched %d; want 1 and 2", batch.MatchedRows, batch.UnmatchedRows)
+	}
+	if batch.Lines[1].Message != "order was shipped with sicepat" {
+		t.Errorf("line 2 message = %q", batch.Lines[1].Message)
+	}
+	if batch.Lines[2].Message != "tracking number does not match the shipment" {
+		t.Errorf("line 3 message = %q", batch.Lines[2].Message)
+	}
+
+	// Payments are only settled together with the batch
+	if payments.payments[1].Status != entity.PaymentStatusPending {
+		t.Fatalf("payment settled before the batch was stored")
+	}
+	if len(remittances.settlements) != 1 {
+		t.Fatalf("settlements = %d, want 1", len(remittances.settlements))
+	}
+	settlement := remittances.settlements[0]
+	if settlement.Line != 0 || settlement.PaymentID != 1 {
+		t.Errorf("settlement = %+v, want line 0 of payment 1", settlement)
+	}
+	if len(settlement.Changes) != 1 || settlement.Changes[0].ToStatus != entity.OrderStatusDelivered {
+		t.Errorf("settlement changes = %+v, want shipped -> delivered", settlement.Changes)
+	}
+}
+
+func TestDeliveryChangesOfProcessingOrder(t *testing.T) {
+	changes := deliveryChanges(&entity.Order{ID: 1, Status: entity.OrderStatusProcessing}, "Cash remitted by courier")
+
+	if len(changes) != 2 {
+		t.Fatalf("changes = %d, want 2", len(changes))
+	}
+	if changes[0].FromStatus != entity.OrderStatusProcessing || changes[0].ToStatus != entity.OrderStatusShipped ||
+		changes[1].FromStatus != entity.OrderStatusShipped || changes[1].ToStatus != entity.OrderStatusDelivered {
+		t.Errorf("changes = %+v, %+v", changes[0], changes[1])
+	}
+}
+
+func TestMarkCODUndeliveredLeavesShippedOrders(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, OrderNumber: "ORD-1", Status: entity.OrderStatusShipped})
+	payments := newFakePaymentRepo(&entity.Payment{ID: 1, OrderID: 1, PaymentMethod: entity.PaymentMethodCOD, Amount: 150000, Status: entity.PaymentStatusPending})
+	uc := &codUseCase{paymentRepo: payments, orderRepo: orders, orderStatusRepo: &fakeOrderStatusRepo{orders: orders}}
+
+	err := uc.MarkCODUndelivered(context.Background(), 1, "recipient not home")
+	var invalid *usecase.InvalidOrderTransitionError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("MarkCODUndelivered() error = %v, want InvalidOrderTransitionError", err)
+	}
+	if orders.orders[1].Status != entity.OrderStatusShipped || payments.payments[1].Status != entity.PaymentStatusPending {
+		t.Errorf("order %s, payment %s; want both left as they were", orders.orders[1].Status, payments.payments[1].Status)
+	}
+}
@@ -0,0 +1,538 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// topUpBatchSize caps the number of unpaid replacement orders cancelled per run
+const topUpBatchSize = 100
+
+type exchangeUseCase struct {
+	exchangeRepo        repository.ExchangeRepository
+	orderRepo           repository.OrderRepository
+	orderItemRepo       repository.OrderItemRepository
+	orderStatusRepo     repository.OrderStatusHistoryRepository
+	paymentRepo         repository.PaymentRepository
+	productRepo         repository.ProductRepository
+	variantRepo         repository.ProductVariantRepository
+	paymentUseCase      usecase.PaymentUseCase
+	notificationUseCase usecase.NotificationUseCase
+	returnWindow        time.Duration
+	topUpWindow         time.Duration
+}
+
+// NewExchangeUseCase creates a new ExchangeUseCase instance
+func NewExchangeUseCase(
+	exchangeRepo repository.ExchangeRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	orderStatusRepo repository.OrderStatusHistoryRepository,
+	paymentRepo repository.PaymentRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	paymentUseCase usecase.PaymentUseCase,
+	notificationUseCase usecase.NotificationUseCase,
+	returnWindow time.Duration,
+	topUpWindow time.Duration,
+) usecase.ExchangeUseCase {
+	return &exchangeUseCase{
+		exchangeRepo:        exchangeRepo,
+		orderRepo:           orderRepo,
+		orderItemRepo:       orderItemRepo,
+		orderStatusRepo:     orderStatusRepo,
+		paymentRepo:         paymentRepo,
+		productRepo:         productRepo,
+		variantRepo:         variantRepo,
+		paymentUseCase:      paymentUseCase,
+		notificationUseCase: notificationUseCase,
+		returnWindow:        returnWindow,
+		topUpWindow:         topUpWindow,
+	}
+}
+
+// CreateExchange requests swapping delivered items for other variants of the
+// same products and reserves the replacement stock
+func (uc *exchangeUseCase) CreateExchange(ctx context.Context, userID, orderID uint, items []usecase.ExchangeItemRequest, reason entity.ReturnReason, description string) (*entity.ExchangeRequest, error) {
+	if len(items) == 0 {
+		return nil, errors.New("at least one item must be exchanged")
+	}
+
+	order, err := checkAfterSalesEligibility(ctx, uc.orderRepo, uc.paymentRepo, orderID, userID, uc.returnWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	orderItems, err := uc.orderItemRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := uc.exchangeRepo.NextExchangeSequence(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := &entity.ExchangeRequest{
+		ExchangeNumber: fmt.Sprintf("EXC-%s-%d", order.OrderNumber, sequence),
+		OrderID:        order.ID,
+		UserID:         userID,
+		Status:         entity.ExchangeStatusRequested,
+		Reason:         reason,
+		Description:    description,
+	}
+
+	requested := make(map[uint]bool)
+	for _, item := range items {
+		if requested[item.OrderItemID] {
+			return nil, fmt.Errorf("order item %d is listed more than once", item.OrderItemID)
+		}
+		requested[item.OrderItemID] = true
+
+		orderItem := findOrderItem(orderItems, item.OrderItemID)
+		if orderItem == nil {
+			return nil, fmt.Errorf("order item %d is not part of this order", item.OrderItemID)
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity of %s must be positive", orderItem.ProductName)
+		}
+
+		variant, err := uc.variantRepo.GetByID(ctx, item.NewVariantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ProductID != orderItem.ProductID {
+			return nil, fmt.Errorf("%s can only be exchanged for another variant of the same product", orderItem.ProductName)
+		}
+		if variant.ID == orderItem.VariantID {
+			return nil, fmt.Errorf("%s must be exchanged for a different variant", orderItem.ProductName)
+		}
+		if !variant.IsActive {
+			return nil, fmt.Errorf("%s is no longer available in this variant", orderItem.ProductName)
+		}
+
+		product, err := uc.productRepo.GetByID(ctx, orderItem.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if !product.IsActive {
+			return nil, fmt.Errorf("%s is no longer available", orderItem.ProductName)
+		}
+
+		newUnitPrice := product.SellingPrice()
+		exchange.Items = append(exchange.Items, entity.ExchangeItem{
+			OrderItemID:    orderItem.ID,
+			ProductID:      orderItem.ProductID,
+			ProductName:    orderItem.ProductName,
+			OldVariantID:   orderItem.VariantID,
+			NewVariantID:   variant.ID,
+			NewVariantInfo: variantInfo(variant),
+			Quantity:       item.Quantity,
+			PaidUnitPrice:  orderItem.FinalPrice,
+			NewUnitPrice:   newUnitPrice,
+		})
+		// The difference is how far the price moved since the order, so the
+		// discount the customer got is kept; a price drop never refunds more
+		// than was paid for the item
+		difference := math.Max(newUnitPrice-orderItem.Price, -orderItem.FinalPrice)
+		exchange.PriceDifference += difference * float64(item.Quantity)
+	}
+
+	// The replacement stock is reserved together with the request
+	check := func(returns []*entity.ReturnRequest, exchanges []*entity.ExchangeRequest) error {
+		claimed := afterSalesQuantities(returns, exchanges)
+		for _, item := range exchange.Items {
+			if err := checkReturnable(findOrderItem(orderItems, item.OrderItemID), claimed, item.Quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := uc.exchangeRepo.Create(ctx, exchange, check); err != nil {
+		return nil, err
+	}
+
+	return exchange, nil
+}
+
+// GetExchangeByID gets an exchange request of a user
+func (uc *exchangeUseCase) GetExchangeByID(ctx context.Context, id, userID uint) (*entity.ExchangeRequest, error) {
+	exchange, err := uc.exchangeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if exchange.UserID != userID {
+		return nil, errors.New("exchange request not found")
+	}
+	return exchange, nil
+}
+
+// GetUserExchanges gets the exchange requests of a user
+func (uc *exchangeUseCase) GetUserExchanges(ctx context.Context, userID uint, page, limit int) ([]*entity.ExchangeRequest, int64, error) {
+	offset := (page - 1) * limit
+	return uc.exchangeRepo.GetByUserID(ctx, userID, offset, limit)
+}
+
+// CancelExchange withdraws an exchange request before it is approved and releases the reserved stock
+func (uc *exchangeUseCase) CancelExchange(ctx context.Context, id, userID uint) error {
+	exchange, err := uc.GetExchangeByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	exchange.Status = entity.ExchangeStatusCancelled
+	if err := uc.transition(ctx, exchange, "only exchanges that have not been approved can be cancelled", entity.ExchangeStatusRequested); err != nil {
+		return err
+	}
+
+	return uc.releaseReplacementStock(ctx, exchange.Items)
+}
+
+// SubmitExchangeShipment records the courier and tracking number the customer sent the original items with
+func (uc *exchangeUseCase) SubmitExchangeShipment(ctx context.Context, id, userID uint, courier, trackingNumber string) error {
+	exchange, err := uc.GetExchangeByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.checkTopUpPaid(ctx, exchange); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	exchange.Status = entity.ExchangeStatusShipped
+	exchange.ReturnCourier = courier
+	exchange.ReturnTrackingNumber = trackingNumber
+	exchange.ShippedAt = &now
+	return uc.transition(ctx, exchange, "only approved exchanges can be shipped", entity.ExchangeStatusApproved)
+}
+
+// ListExchanges lists exchange requests with filter and pagination (admin function)
+func (uc *exchangeUseCase) ListExchanges(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.ExchangeRequest, int64, error) {
+	offset := (page - 1) * limit
+	return uc.exchangeRepo.List(ctx, filter, offset, limit)
+}
+
+// ListExchangesByCursor lists exchange requests with filter, one page after or before a cursor (admin function)
+func (uc *exchangeUseCase) ListExchangesByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ExchangeRequest, *entity.PageCursors, error) {
+	return uc.exchangeRepo.ListByCursor(ctx, filter, page)
+}
+
+// GetExchange gets any exchange request (admin function)
+func (uc *exchangeUseCase) GetExchange(ctx context.Context, id uint) (*entity.ExchangeRequest, error) {
+	return uc.exchangeRepo.GetByID(ctx, id)
+}
+
+// ApproveExchange accepts an exchange request and creates the replacement
+// order. The replacement order is free unless the new items cost more, in
+// which case it waits for the customer to pay the difference before it ships
+// and before the original items can be sent back. (admin function)
+func (uc *exchangeUseCase) ApproveExchange(ctx context.Context, id uint, note string) (*entity.Order, error) {
+	exchange, err := uc.exchangeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	original, err := uc.orderRepo.GetByID(ctx, exchange.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	exchange.Status = entity.ExchangeStatusApproved
+	exchange.AdminNote = note
+	exchange.ApprovedAt = &now
+
+	// The exchange and its replacement order are saved together
+	replacement := newReplacementOrder(original, exchange)
+	applied, err := uc.exchangeRepo.Approve(ctx, exchange, replacement, &entity.OrderStatusHistory{
+		ToStatus:  replacement.Status,
+		ActorType: systemActor.Type,
+		Note:      "Created for exchange " + exchange.ExchangeNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, errors.New("only requested exchanges can be approved")
+	}
+
+	message := fmt.Sprintf("Your exchange %s was approved. Please send the original items back and submit the tracking number.", exchange.ExchangeNumber)
+	if replacement.Status == entity.OrderStatusPending {
+		message = fmt.Sprintf("Your exchange %s was approved. Please pay the price difference of %.2f on replacement order %s within %s, then send the original items back and submit the tracking number.",
+			exchange.ExchangeNumber, replacement.FinalAmount, replacement.OrderNumber, uc.topUpWindow)
+	}
+	uc.notify(ctx, exchange, "Exchange approved", message)
+
+	return replacement, nil
+}
+
+// RejectExchange declines an exchange request and releases the reserved stock (admin function)
+func (uc *exchangeUseCase) RejectExchange(ctx context.Context, id uint, note string) error {
+	exchange, err := uc.exchangeRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	exchange.Status = entity.ExchangeStatusRejected
+	exchange.AdminNote = note
+	if err := uc.transition(ctx, exchange, "only requested exchanges can be rejected", entity.ExchangeStatusRequested); err != nil {
+		return err
+	}
+
+	if err := uc.releaseReplacementStock(ctx, exchange.Items); err != nil {
+		return err
+	}
+
+	uc.notify(ctx, exchange, "Exchange rejected",
+		fmt.Sprintf("Your exchange %s was rejected: %s", exchange.ExchangeNumber, note))
+	return nil
+}
+
+// ReceiveExchange records that the original items arrived, puts them back in
+// stock and refunds any price difference owed to the customer (admin function)
+func (uc *exchangeUseCase) ReceiveExchange(ctx context.Context, id uint) error {
+	exchange, err := uc.exchangeRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.checkTopUpPaid(ctx, exchange); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	exchange.Status = entity.ExchangeStatusReceived
+	exchange.ReceivedAt = &now
+	applied, err := uc.exchangeRepo.Receive(ctx, exchange, entity.ExchangeStatusApproved, entity.ExchangeStatusShipped)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("only approved or shipped exchanges can be received")
+	}
+
+	return uc.complete(ctx, exchange)
+}
+
+// CompleteExchange retries completing a received exchange (admin function)
+func (uc *exchangeUseCase) CompleteExchange(ctx context.Context, id uint) error {
+	exchange, err := uc.exchangeRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exchange.Status != entity.ExchangeStatusReceived {
+		return errors.New("only received exchanges can be completed")
+	}
+
+	return uc.complete(ctx, exchange)
+}
+
+// complete completes a received exchange, refunding the price difference when
+// the new items cost less. The exchange is marked completed first so that
+// concurrent calls cannot refund it twice.
+func (uc *exchangeUseCase) complete(ctx context.Context, exchange *entity.ExchangeRequest) error {
+	now := time.Now()
+	exchange.Status = entity.ExchangeStatusCompleted
+	exchange.CompletedAt = &now
+
+	if exchange.PriceDifference > -0.01 {
+		return uc.transition(ctx, exchange, "exchange is already being completed", entity.ExchangeStatusReceived)
+	}
+
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, exchange.OrderID)
+	if err != nil {
+		return err
+	}
+	amount := math.Min(-exchange.PriceDifference, payment.PaidAmount()-payment.RefundedTotal())
+	if amount <= 0 {
+		return errors.New("payment has already been refunded in full")
+	}
+
+	exchange.RefundedAmount = amount
+	if err := uc.transition(ctx, exchange, "exchange is already being completed", entity.ExchangeStatusReceived); err != nil {
+		return err
+	}
+
+	if err := uc.paymentUseCase.RefundPayment(ctx, payment.ID, amount, "Exchange "+exchange.ExchangeNumber, false); err != nil {
+		exchange.Status = entity.ExchangeStatusReceived
+		exchange.RefundedAmount = 0
+		exchange.CompletedAt = nil
+		if _, revertErr := uc.exchangeRepo.UpdateIfStatus(ctx, exchange, entity.ExchangeStatusCompleted); revertErr != nil {
+			return fmt.Errorf("refund failed: %v, and the exchange could not be reverted: %w", err, revertErr)
+		}
+		return fmt.Errorf("refund failed: %w", err)
+	}
+
+	uc.notify(ctx, exchange, "Exchange completed",
+		fmt.Sprintf("We received the items of exchange %s and refunded the price difference of %.2f.", exchange.ExchangeNumber, amount))
+	return nil
+}
+
+// CancelUnpaidExchanges cancels approved exchanges whose price difference was
+// not paid within the top-up window, together with their replacement orders,
+// and releases the replacement stock. Exchanges whose replacement order was
+// already cancelled, such as by an expired payment, are cancelled as well.
+// Each exchange is cancelled on its own and the failures are joined into the
+// returned error. It returns the number of exchanges cancelled.
+func (uc *exchangeUseCase) CancelUnpaidExchanges(ctx context.Context) (int, error) {
+	exchanges, err := uc.exchangeRepo.GetAwaitingTopUp(ctx, time.Now().Add(-uc.topUpWindow), topUpBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	var failures []error
+	for _, exchange := range exchanges {
+		ok, err := uc.cancelUnpaid(ctx, exchange)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("cancel exchange %s: %w", exchange.ExchangeNumber, err))
+		}
+		if ok {
+			cancelled++
+		}
+	}
+
+	return cancelled, errors.Join(failures...)
+}
+
+// cancelUnpaid cancels a single exchange whose price difference was not paid
+func (uc *exchangeUseCase) cancelUnpaid(ctx context.Context, exchange *entity.ExchangeRequest) (bool, error) {
+	replacement, err := uc.orderRepo.GetByID(ctx, *exchange.ReplacementOrderID)
+	if err != nil {
+		return false, err
+	}
+
+	if replacement.Status == entity.OrderStatusPending {
+		// A payment the customer started is expired, with the order, by the
+		// payment expiry job; it may still be paid until then
+		if replacement.PaymentID != nil {
+			payment, err := uc.paymentRepo.GetByID(ctx, *replacement.PaymentID)
+			if err != nil {
+				return false, err
+			}
+			if payment.Status == entity.PaymentStatusPending || payment.Status == entity.PaymentStatusChallenge {
+				return false, nil
+			}
+		}
+
+		// The replacement order is restocked with the status change
+		err := transitionOrder(ctx, uc.orderStatusRepo, replacement, entity.OrderStatusCancelled, systemActor,
+			"Price difference of exchange "+exchange.ExchangeNumber+" not paid")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	exchange.Status = entity.ExchangeStatusCancelled
+	exchange.AdminNote = "Price difference not paid"
+	applied, err := uc.exchangeRepo.UpdateIfStatus(ctx, exchange, entity.ExchangeStatusApproved)
+	if err != nil || !applied {
+		return false, err
+	}
+
+	uc.notify(ctx, exchange, "Exchange cancelled",
+		fmt.Sprintf("Your exchange %s was cancelled because the price difference on replacement order %s was not paid.", exchange.ExchangeNumber, replacement.OrderNumber))
+	return true, nil
+}
+
+// checkTopUpPaid checks that the replacement order of an exchange no longer
+// awaits the price difference
+func (uc *exchangeUseCase) checkTopUpPaid(ctx context.Context, exchange *entity.ExchangeRequest) error {
+	if exchange.ReplacementOrderID == nil {
+		return nil
+	}
+	replacement, err := uc.orderRepo.GetByID(ctx, *exchange.ReplacementOrderID)
+	if err != nil {
+		return err
+	}
+
+	switch replacement.Status {
+	case entity.OrderStatusPending:
+		return fmt.Errorf("the price difference of %.2f must be paid on order %s first", replacement.FinalAmount, replacement.OrderNumber)
+	case entity.OrderStatusCancelled:
+		return fmt.Errorf("replacement order %s was cancelled", replacement.OrderNumber)
+	}
+	return nil
+}
+
+// releaseReplacementStock returns the stock reserved for replacement items
+func (uc *exchangeUseCase) releaseReplacementStock(ctx context.Context, items []entity.ExchangeItem) error {
+	for _, item := range items {
+		if err := uc.variantRepo.AdjustStock(ctx, item.NewVariantID, item.Quantity); err != nil {
+			return fmt.Errorf("release stock of variant %d: %w", item.NewVariantID, err)
+		}
+	}
+	return nil
+}
+
+// transition saves an exchange request whose status was changed, failing with
+// message when its stored status is not one of expected
+func (uc *exchangeUseCase) transition(ctx context.Context, exchange *entity.ExchangeRequest, message string, expected ...entity.ExchangeStatus) error {
+	applied, err := uc.exchangeRepo.UpdateIfStatus(ctx, exchange, expected...)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// notify tells the customer about a change to their exchange
+func (uc *exchangeUseCase) notify(ctx context.Context, exchange *entity.ExchangeRequest, title, message string) {
+	// The exchange is already updated; a failed notification must not undo that
+	_ = uc.notificationUseCase.CreateNotification(ctx, exchange.UserID, entity.NotificationTypeOrder, title, message,
+		map[string]interface{}{"exchange_id": exchange.ID, "exchange_number": exchange.ExchangeNumber, "order_id": exchange.OrderID},
+	)
+}
+
+// newReplacementOrder builds the order that ships the replacement items of an
+// exchange. What the customer paid for the original items is applied as a
+// discount, so only a price increase is left to pay.
+func newReplacementOrder(original *entity.Order, exchange *entity.ExchangeRequest) *entity.Order {
+	order := &entity.Order{
+		UserID:          original.UserID,
+		OrderNumber:     exchange.ExchangeNumber,
+		Status:          entity.OrderStatusProcessing,
+		ShippingAddress: original.ShippingAddress,
+		ShippingMethod:  original.ShippingMethod,
+		OriginalOrderID: &original.ID,
+		Notes:           fmt.Sprintf("Replacement for exchange %s of order %s", exchange.ExchangeNumber, original.OrderNumber),
+	}
+
+	for _, item := range exchange.Items {
+		order.OrderItems = append(order.OrderItems, entity.OrderItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			VariantID:   item.NewVariantID,
+			VariantInfo: item.NewVariantInfo,
+			Quantity:    item.Quantity,
+			Price:       item.NewUnitPrice,
+			FinalPrice:  item.NewUnitPrice,
+		})
+		order.TotalAmount += item.NewUnitPrice * float64(item.Quantity)
+	}
+
+	// What was paid for the returned items is credited; only a price rise is owed
+	order.FinalAmount = roundPrice(math.Max(exchange.PriceDifference, 0))
+	order.DiscountAmount = math.Max(order.TotalAmount-order.FinalAmount, 0)
+	if order.FinalAmount > 0.01 {
+		order.Status = entity.OrderStatusPending
+	}
+
+	return order
+}
+
+// variantInfo describes a variant the way order items store it, by its
+// option values such as {"size":"M","length":"32"}
+func variantInfo(variant *entity.ProductVariant) string {
+	info, _ := json.Marshal(variant.Options())
+	return string(info)
+}
@@ -0,0 +1,253 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// fakeExchangeRepo keeps exchanges and creates replacement orders in a fakeOrderRepo
+type fakeExchangeRepo struct {
+	repository.ExchangeRepository
+	exchanges map[uint]*entity.ExchangeRequest
+	orders    *fakeOrderRepo
+	variants  *fakeVariantRepo
+	sequence  int64
+}
+
+func (r *fakeExchangeRepo) NextExchangeSequence(ctx context.Context) (int64, error) {
+	r.sequence++
+	return r.sequence, nil
+}
+
+func (r *fakeExchangeRepo) Create(ctx context.Context, exchange *entity.ExchangeRequest, check repository.AfterSalesCheck) error {
+	var exchanges []*entity.ExchangeRequest
+	for _, existing := range r.exchanges {
+		if existing.OrderID == exchange.OrderID {
+			exchanges = append(exchanges, existing)
+		}
+	}
+	if err := check(nil, exchanges); err != nil {
+		return err
+	}
+	for _, item := range exchange.Items {
+		if err := r.variants.AdjustStock(ctx, item.NewVariantID, -item.Quantity); err != nil {
+			return err
+		}
+	}
+	exchange.ID = uint(len(r.exchanges) + 1)
+	r.exchanges[exchange.ID] = exchange
+	return nil
+}
+
+func (r *fakeExchangeRepo) GetByID(ctx context.Context, id uint) (*entity.ExchangeRequest, error) {
+	exchange, ok := r.exchanges[id]
+	if !ok {
+		return nil, errors.New("exchange request not found")
+	}
+	copied := *exchange
+	return &copied, nil
+}
+
+func (r *fakeExchangeRepo) UpdateIfStatus(ctx context.Context, exchange *entity.ExchangeRequest, expected ...entity.ExchangeStatus) (bool, error) {
+	stored := r.exchanges[exchange.ID]
+	for _, status := range expected {
+		if stored.Status == status {
+			copied := *exchange
+			r.exchanges[exchange.ID] = &copied
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeExchangeRepo) Approve(ctx context.Context, exchange *entity.ExchangeRequest, replacement *entity.Order, created *entity.OrderStatusHistory) (bool, error) {
+	if r.exchanges[exchange.ID].Status != entity.ExchangeStatusRequested {
+		return false, nil
+	}
+	replacement.ID = uint(100 + len(r.orders.orders))
+	r.orders.orders[replacement.ID] = replacement
+	exchange.ReplacementOrderID = &replacement.ID
+	return r.UpdateIfStatus(ctx, exchange, entity.ExchangeStatusRequested)
+}
+
+func (r *fakeExchangeRepo) GetAwaitingTopUp(ctx context.Context, approvedBefore time.Time, limit int) ([]*entity.ExchangeRequest, error) {
+	var exchanges []*entity.ExchangeRequest
+	for _, exchange := range r.exchanges {
+		if exchange.Status != entity.ExchangeStatusApproved || exchange.ReplacementOrderID == nil {
+			continue
+		}
+		order := r.orders.orders[*exchange.ReplacementOrderID]
+		if order.Status == entity.OrderStatusCancelled ||
+			(order.Status == entity.OrderStatusPending && exchange.ApprovedAt.Before(approvedBefore)) {
+			copied := *exchange
+			exchanges = append(exchanges, &copied)
+		}
+	}
+	return exchanges, nil
+}
+
+type exchangeTest struct {
+	uc        *exchangeUseCase
+	exchanges *fakeExchangeRepo
+	orders    *fakeOrderRepo
+	items     *fakeOrderItemRepo
+	variants  *fakeVariantRepo
+	payments  *fakePaymentRepo
+}
+
+// newExchangeTest sets up a delivered order of two M shirts that sold for
+// 100000 each; the shirt now sells for 120000
+func newExchangeTest() *exchangeTest {
+	deliveredAt := time.Now()
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, UserID: 5, OrderNumber: "ORD-1", Status: entity.OrderStatusDelivered, DeliveredAt: &deliveredAt})
+	variants := newFakeVariantRepo(
+		&entity.ProductVariant{ID: 7, ProductID: 3, Stock: 0, IsActive: true},
+		&entity.ProductVariant{ID: 8, ProductID: 3, Stock: 5, IsActive: true},
+	)
+	items := &fakeOrderItemRepo{}
+	payments := newFakePaymentRepo(&entity.Payment{ID: 1, OrderID: 1, Amount: 200000, Status: entity.PaymentStatusPaid})
+	exchanges := &fakeExchangeRepo{exchanges: make(map[uint]*entity.ExchangeRequest), orders: orders, variants: variants}
+
+	uc := NewExchangeUseCase(exchanges, orders, items, &fakeOrderStatusRepo{orders: orders, items: items, variants: variants}, payments,
+		newFakeProductRepo(&entity.Product{ID: 3, Price: 120000, IsActive: true}), variants,
+		&fakePaymentUseCase{}, &fakeNotificationUseCase{}, 14*24*time.Hour, 48*time.Hour).(*exchangeUseCase)
+	items.items = []*entity.OrderItem{
+		{ID: 11, OrderID: 1, ProductID: 3, VariantID: 7, ProductName: "Linen Shirt", Quantity: 2, Price: 100000, FinalPrice: 100000},
+	}
+	return &exchangeTest{uc: uc, exchanges: exchanges, orders: orders, items: items, variants: variants, payments: payments}
+}
+
+func (tt *exchangeTest) createAndApprove(t *testing.T, quantity int) (*entity.ExchangeRequest, *entity.Order) {
+	t.Helper()
+	exchange, err := tt.uc.CreateExchange(context.Background(), 5, 1,
+		[]usecase.ExchangeItemRequest{{OrderItemID: 11, NewVariantID: 8, Quantity: quantity}}, entity.ReturnReasonTooSmall, "")
+	if err != nil {
+		t.Fatalf("CreateExchange() error = %v", err)
+	}
+	replacement, err := tt.uc.ApproveExchange(context.Background(), exchange.ID, "")
+	if err != nil {
+		t.Fatalf("ApproveExchange() error = %v", err)
+	}
+	return exchange, replacement
+}
+
+func TestCreateExchangeNumbersFromSequenceAndChecksQuantity(t *testing.T) {
+	tt := newExchangeTest()
+	tt.exchanges.sequence = 41
+
+	exchange, err := tt.uc.CreateExchange(context.Background(), 5, 1,
+		[]usecase.ExchangeItemRequest{{OrderItemID: 11, NewVariantID: 8, Quantity: 1}}, entity.ReturnReasonTooSmall, "")
+	if err != nil {
+		t.Fatalf("CreateExchange() error = %v", err)
+	}
+	if exchange.ExchangeNumber != "EXC-ORD-1-42" {
+		t.Errorf("ExchangeNumber = %q, want EXC-ORD-1-42", exchange.ExchangeNumber)
+	}
+
+	_, err = tt.uc.CreateExchange(context.Background(), 5, 1,
+		[]usecase.ExchangeItemRequest{{OrderItemID: 11, NewVariantID: 8, Quantity: 2}}, entity.ReturnReasonTooSmall, "")
+	if err == nil || !strings.Contains(err.Error(), "at most 1") {
+		t.Fatalf("CreateExchange() error = %v, want at most 1 exchangeable", err)
+	}
+	if stock := tt.variants.variants[8].Stock; stock != 4 {
+		t.Errorf("stock of the replacement variant = %d, want 4", stock)
+	}
+}
+
+func TestApproveExchangeWithTopUpGatesShipment(t *testing.T) {
+	tt := newExchangeTest()
+	exchange, replacement := tt.createAndApprove(t, 1)
+
+	if replacement.Status != entity.OrderStatusPending || replacement.FinalAmount != 20000 {
+		t.Fatalf("replacement order is %s for %.2f, want pending for 20000", replacement.Status, replacement.FinalAmount)
+	}
+
+	err := tt.uc.SubmitExchangeShipment(context.Background(), exchange.ID, 5, "jne", "JNE123")
+	if err == nil || !strings.Contains(err.Error(), "must be paid") {
+		t.Fatalf("SubmitExchangeShipment() error = %v, want the price difference to be paid first", err)
+	}
+
+	tt.orders.orders[replacement.ID].Status = entity.OrderStatusProcessing
+	if err := tt.uc.SubmitExchangeShipment(context.Background(), exchange.ID, 5, "jne", "JNE123"); err != nil {
+		t.Fatalf("SubmitExchangeShipment() after the top-up error = %v", err)
+	}
+}
+
+func TestCreateExchangeKeepsDiscountOfSameProduct(t *testing.T) {
+	tt := newExchangeTest()
+	// The shirts sold at 100000 with 20000 off each, and the price is unchanged
+	tt.items.items[0].FinalPrice = 80000
+	tt.uc.productRepo = newFakeProductRepo(&entity.Product{ID: 3, Price: 100000, IsActive: true})
+
+	exchange, replacement := tt.createAndApprove(t, 1)
+	if exchange.PriceDifference != 0 {
+		t.Errorf("price difference = %.0f, want 0 for a size swap at an unchanged price", exchange.PriceDifference)
+	}
+	if replacement.Status != entity.OrderStatusProcessing || replacement.FinalAmount != 0 {
+		t.Errorf("replacement order is %s for %.0f, want processing for 0", replacement.Status, replacement.FinalAmount)
+	}
+}
+
+func TestApproveExchangeReportsConcurrentApproval(t *testing.T) {
+	tt := newExchangeTest()
+	exchange, _ := tt.createAndApprove(t, 1)
+
+	if _, err := tt.uc.ApproveExchange(context.Background(), exchange.ID, ""); err == nil {
+		t.Fatal("second ApproveExchange() succeeded")
+	}
+	if len(tt.orders.orders) != 2 {
+		t.Errorf("%d orders, want the original and one replacement", len(tt.orders.orders))
+	}
+}
+
+func TestCancelUnpaidExchangesReleasesReplacementStock(t *testing.T) {
+	tt := newExchangeTest()
+	exchange, replacement := tt.createAndApprove(t, 1)
+	if stock := tt.variants.variants[8].Stock; stock != 4 {
+		t.Fatalf("stock of the replacement variant = %d, want 4", stock)
+	}
+	tt.items.items = append(tt.items.items, &entity.OrderItem{ID: 21, OrderID: replacement.ID, VariantID: 8, Quantity: 1})
+
+	// Still within the top-up window
+	if cancelled, err := tt.uc.CancelUnpaidExchanges(context.Background()); err != nil || cancelled != 0 {
+		t.Fatalf("CancelUnpaidExchanges() = %d, %v, want nothing cancelled yet", cancelled, err)
+	}
+
+	approvedAt := time.Now().Add(-72 * time.Hour)
+	tt.exchanges.exchanges[exchange.ID].ApprovedAt = &approvedAt
+	cancelled, err := tt.uc.CancelUnpaidExchanges(context.Background())
+	if err != nil || cancelled != 1 {
+		t.Fatalf("CancelUnpaidExchanges() = %d, %v, want 1", cancelled, err)
+	}
+	if status := tt.orders.orders[replacement.ID].Status; status != entity.OrderStatusCancelled {
+		t.Errorf("replacement order is %s, want cancelled", status)
+	}
+	if status := tt.exchanges.exchanges[exchange.ID].Status; status != entity.ExchangeStatusCancelled {
+		t.Errorf("exchange is %s, want cancelled", status)
+	}
+	if stock := tt.variants.variants[8].Stock; stock != 5 {
+		t.Errorf("stock of the replacement variant = %d, want 5", stock)
+	}
+}
+
+func TestCancelUnpaidExchangesLeavesStartedPayments(t *testing.T) {
+	tt := newExchangeTest()
+	exchange, replacement := tt.createAndApprove(t, 1)
+
+	payment := &entity.Payment{ID: 2, OrderID: replacement.ID, Amount: 20000, Status: entity.PaymentStatusPending}
+	tt.payments.payments[payment.ID] = payment
+	tt.orders.orders[replacement.ID].PaymentID = &payment.ID
+	approvedAt := time.Now().Add(-72 * time.Hour)
+	tt.exchanges.exchanges[exchange.ID].ApprovedAt = &approvedAt
+
+	if cancelled, err := tt.uc.CancelUnpaidExchanges(context.Background()); err != nil || cancelled != 0 {
+		t.Fatalf("CancelUnpaidExchanges() = %d, %v, want the pending payment left to expire", cancelled, err)
+	}
+}
@@ -0,0 +1,1022 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+)
+
+// The fakes below embed the interface they stand in for, so a test only has
+// to implement the methods the code under test calls; any other call panics.
+
+type fakeOrderRepo struct {
+	repository.OrderRepository
+	orders     map[uint]*entity.Order
+	placements []*repository.OrderPlacement // what Place took for each order
+}
+
+func newFakeOrderRepo(orders ...*entity.Order) *fakeOrderRepo {
+	r := &fakeOrderRepo{orders: make(map[uint]*entity.Order)}
+	for _, order := range orders {
+		r.orders[order.ID] = order
+	}
+	return r
+}
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, id uint) (*entity.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, errors.New("order not found")
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (r *fakeOrderRepo) GetByOrderNumber(ctx context.Context, orderNumber string) (*entity.Order, error) {
+	for _, order := range r.orders {
+		if order.OrderNumber == orderNumber {
+			copied := *order
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("order not found")
+}
+
+func (r *fakeOrderRepo) GetOrderNumbers(ctx context.Context, ids []uint) (map[uint]string, error) {
+	numbers := make(map[uint]string)
+	for _, id := range ids {
+		if order, ok := r.orders[id]; ok {
+			numbers[id] = order.OrderNumber
+		}
+	}
+	return numbers, nil
+}
+
+// Place stores the order and what it took; stock is left to the test
+func (r *fakeOrderRepo) Place(ctx context.Context, order *entity.Order, placement *repository.OrderPlacement) error {
+	order.ID = uint(len(r.orders) + 1)
+	if placement.Voucher != nil {
+		placement.Voucher.OrderID = order.ID
+	}
+	for _, purchase := range placement.FlashSales {
+		purchase.OrderID = order.ID
+	}
+	if placement.Payment != nil {
+		placement.Payment.Payment.ID = order.ID
+		order.Status = placement.Payment.Paid.ToStatus
+	}
+	r.placements = append(r.placements, placement)
+	copied := *order
+	r.orders[order.ID] = &copied
+	return nil
+}
+
+// GetUnstarted gets every pending order; the tests only hold overdue ones
+func (r *fakeOrderRepo) GetUnstarted(ctx context.Context, placedBefore time.Time, limit int) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	for _, order := range r.orders {
+		if order.Status == entity.OrderStatusPending {
+			copied := *order
+			orders = append(orders, &copied)
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeOrderRepo) GetUnreleased(ctx context.Context, limit int) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	for _, order := range r.orders {
+		if order.Status == entity.OrderStatusCancelled && order.HoldsReleasedAt == nil {
+			copied := *order
+			orders = append(orders, &copied)
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeOrderRepo) MarkHoldsReleased(ctx context.Context, id uint) error {
+	order, ok := r.orders[id]
+	if !ok {
+		return errors.New("order not found")
+	}
+	now := time.Now()
+	order.HoldsReleasedAt = &now
+	return nil
+}
+
+func (r *fakeOrderRepo) Update(ctx context.Context, order *entity.Order) error {
+	copied := *order
+	copied.HoldsReleasedAt = r.orders[order.ID].HoldsReleasedAt
+	r.orders[order.ID] = &copied
+	return nil
+}
+
+func (r *fakeOrderRepo) SetPayment(ctx context.Context, orderID, paymentID uint) error {
+	order, ok := r.orders[orderID]
+	if !ok {
+		return errors.New("order not found")
+	}
+	order.PaymentID = &paymentID
+	return nil
+}
+
+// fakeOrderStatusRepo applies transitions to the orders of a fakeOrderRepo.
+// With items and variants set, cancelled orders are restocked as well.
+type fakeOrderStatusRepo struct {
+	repository.OrderStatusHistoryRepository
+	orders   *fakeOrderRepo
+	items    *fakeOrderItemRepo
+	variants *fakeVariantRepo
+	history  []*entity.OrderStatusHistory
+}
+
+func (r *fakeOrderStatusRepo) Transition(ctx context.Context, change *entity.OrderStatusHistory) (bool, error) {
+	order, ok := r.orders.orders[change.OrderID]
+	if !ok || order.Status != change.FromStatus {
+		return false, nil
+	}
+	if change.ToStatus == entity.OrderStatusCancelled && r.items != nil {
+		items, _ := r.items.GetByOrderID(ctx, change.OrderID)
+		for _, item := range items {
+			if err := r.variants.AdjustStock(ctx, item.VariantID, item.Quantity); err != nil {
+				return false, err
+			}
+		}
+	}
+	order.Status = change.ToStatus
+	r.history = append(r.history, change)
+	return true, nil
+}
+
+func (r *fakeOrderStatusRepo) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.OrderStatusHistory, error) {
+	var history []*entity.OrderStatusHistory
+	for _, change := range r.history {
+		if change.OrderID == orderID {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+type fakePaymentRepo struct {
+	repository.PaymentRepository
+	payments     map[uint]*entity.Payment
+	orders       *fakeOrderRepo // orders cancelled by Expire
+	batchLookups int
+}
+
+func newFakePaymentRepo(payments ...*entity.Payment) *fakePaymentRepo {
+	r := &fakePaymentRepo{payments: make(map[uint]*entity.Payment)}
+	for _, payment := range payments {
+		r.payments[payment.ID] = payment
+	}
+	return r
+}
+
+func (r *fakePaymentRepo) GetByID(ctx context.Context, id uint) (*entity.Payment, error) {
+	payment, ok := r.payments[id]
+	if !ok {
+		return nil, errors.New("payment not found")
+	}
+	copied := *payment
+	return &copied, nil
+}
+
+func (r *fakePaymentRepo) GetByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			copied := *payment
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("payment not found")
+}
+
+func (r *fakePaymentRepo) GetByGatewayOrderID(ctx context.Context, gatewayOrderID string) (*entity.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.GatewayOrderID == gatewayOrderID {
+			copied := *payment
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("payment not found")
+}
+
+func (r *fakePaymentRepo) GetExpiredPending(ctx context.Context, before time.Time, limit int) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	for id := uint(1); id <= uint(len(r.payments)); id++ {
+		payment := r.payments[id]
+		if payment.Status == entity.PaymentStatusPending && payment.ExpiredAt != nil && payment.ExpiredAt.Before(before) {
+			copied := *payment
+			payments = append(payments, &copied)
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakePaymentRepo) Expire(ctx context.Context, payment *entity.Payment, cancel *entity.OrderStatusHistory) (bool, error) {
+	stored, ok := r.payments[payment.ID]
+	if !ok || stored.Status != entity.PaymentStatusPending {
+		return false, nil
+	}
+	stored.Status = entity.PaymentStatusExpired
+	payment.Status = entity.PaymentStatusExpired
+	if cancel != nil && r.orders != nil {
+		if order, ok := r.orders.orders[cancel.OrderID]; ok && order.Status == cancel.FromStatus {
+			order.Status = cancel.ToStatus
+			cancel.ID = 1
+		}
+	}
+	return true, nil
+}
+
+func (r *fakePaymentRepo) GetCreatedBetween(ctx context.Context, provider string, start, end time.Time) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	for id := uint(1); id <= uint(len(r.payments)); id++ {
+		payment := r.payments[id]
+		if !payment.CreatedAt.Before(start) && payment.CreatedAt.Before(end) && (provider == "" || payment.Provider == provider) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakePaymentRepo) GetPaidBetween(ctx context.Context, provider string, start, end time.Time) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	for id := uint(1); id <= uint(len(r.payments)); id++ {
+		payment := r.payments[id]
+		if payment.PaidAt != nil && !payment.PaidAt.Before(start) && payment.PaidAt.Before(end) && (provider == "" || payment.Provider == provider) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakePaymentRepo) GetByTransactionIDs(ctx context.Context, transactionIDs []string) ([]*entity.Payment, error) {
+	r.batchLookups++
+	var payments []*entity.Payment
+	for _, payment := range r.payments {
+		for _, id := range transactionIDs {
+			if payment.TransactionID == id {
+				payments = append(payments, payment)
+			}
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakePaymentRepo) GetByGatewayOrderIDs(ctx context.Context, gatewayOrderIDs []string) ([]*entity.Payment, error) {
+	r.batchLookups++
+	var payments []*entity.Payment
+	for _, payment := range r.payments {
+		for _, id := range gatewayOrderIDs {
+			if payment.GatewayOrderID == id {
+				payments = append(payments, payment)
+			}
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakePaymentRepo) GetByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.TransactionID == transactionID {
+			copied := *payment
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("payment not found")
+}
+
+func (r *fakePaymentRepo) Create(ctx context.Context, payment *entity.Payment) error {
+	payment.ID = uint(len(r.payments) + 1)
+	copied := *payment
+	r.payments[payment.ID] = &copied
+	return nil
+}
+
+func (r *fakePaymentRepo) Update(ctx context.Context, payment *entity.Payment) error {
+	copied := *payment
+	r.payments[payment.ID] = &copied
+	return nil
+}
+
+func (r *fakePaymentRepo) UpdateIfStatus(ctx context.Context, payment *entity.Payment, expected ...entity.PaymentStatus) (bool, error) {
+	stored, ok := r.payments[payment.ID]
+	if !ok {
+		return false, nil
+	}
+	for _, status := range expected {
+		if stored.Status == status {
+			copied := *payment
+			r.payments[payment.ID] = &copied
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakePaymentRefundRepo keeps refunds with the payments of a fakePaymentRepo
+type fakePaymentRefundRepo struct {
+	repository.PaymentRefundRepository
+	payments *fakePaymentRepo
+	refunds  []*entity.PaymentRefund
+	credits  []*entity.WalletTransaction
+}
+
+func (r *fakePaymentRefundRepo) Create(ctx context.Context, refund *entity.PaymentRefund) error {
+	refund.ID = uint(len(r.refunds) + 1)
+	r.refunds = append(r.refunds, refund)
+	return nil
+}
+
+func (r *fakePaymentRefundRepo) Reserve(ctx context.Context, paymentID uint, keyPrefix string, refunds []*entity.PaymentRefund) error {
+	payment := r.payments.payments[paymentID]
+	pending := 0.0
+	for _, refund := range r.refunds {
+		if refund.PaymentID == paymentID && refund.Status == entity.PaymentRefundStatusPending {
+			pending += refund.Amount
+		}
+	}
+	requested := 0.0
+	for _, refund := range refunds {
+		requested += refund.Amount
+	}
+	if requested > payment.PaidAmount()-payment.RefundedTotal()-pending+0.01 {
+		return repository.ErrRefundExceedsPayment
+	}
+	count := 0
+	for _, refund := range r.refunds {
+		if refund.PaymentID == paymentID {
+			count++
+		}
+	}
+	for i, refund := range refunds {
+		refund.PaymentID = paymentID
+		refund.RefundKey = fmt.Sprintf("%s-refund-%d", keyPrefix, count+i+1)
+		refund.Status = entity.PaymentRefundStatusPending
+		if err := r.Create(ctx, refund); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakePaymentRefundRepo) Complete(ctx context.Context, refunds []*entity.PaymentRefund, credits []*entity.WalletTransaction) (*entity.Payment, error) {
+	payment := r.payments.payments[refunds[0].PaymentID]
+	for _, refund := range refunds {
+		if refund.Status != entity.PaymentRefundStatusPending {
+			continue
+		}
+		refund.Status = entity.PaymentRefundStatusSucceeded
+		if refund.Source == "wallet" {
+			payment.WalletRefunded += refund.Amount
+		} else {
+			payment.RefundedAmount += refund.Amount
+		}
+	}
+	payment.Status = payment.RefundStatus()
+	r.credits = append(r.credits, credits...)
+	copied := *payment
+	return &copied, nil
+}
+
+func (r *fakePaymentRefundRepo) Fail(ctx context.Context, refunds []*entity.PaymentRefund) error {
+	for _, refund := range refunds {
+		refund.Status = entity.PaymentRefundStatusFailed
+	}
+	return nil
+}
+
+type fakePaymentEventRepo struct {
+	repository.PaymentEventRepository
+	events []*entity.PaymentEvent
+}
+
+func (r *fakePaymentEventRepo) Create(ctx context.Context, event *entity.PaymentEvent) error {
+	event.ID = uint(len(r.events) + 1)
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakePaymentEventRepo) Update(ctx context.Context, event *entity.PaymentEvent) error {
+	return nil
+}
+
+type fakeLoyaltyUseCase struct {
+	usecase.LoyaltyUseCase
+	clawedBack float64
+	released   []uint
+}
+
+func (uc *fakeLoyaltyUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	uc.released = append(uc.released, orderID)
+	return nil
+}
+
+func (uc *fakeLoyaltyUseCase) ClawbackForRefund(ctx context.Context, orderID uint, refundedTotal float64) error {
+	uc.clawedBack = refundedTotal
+	return nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	return &entity.User{ID: id, Email: "customer@example.com"}, nil
+}
+
+type fakeOrderItemRepo struct {
+	repository.OrderItemRepository
+	items []*entity.OrderItem
+}
+
+func (r *fakeOrderItemRepo) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.OrderItem, error) {
+	var items []*entity.OrderItem
+	for _, item := range r.items {
+		if item.OrderID == orderID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// stubGateway is a payment gateway whose answers are set by the test
+type stubGateway struct {
+	gateway.PaymentGateway
+	name        string
+	charges     []*gateway.ChargeRequest
+	refundErr   error
+	refunds     []*gateway.RefundRequest
+	parseErr    error
+	transaction *gateway.Transaction
+	lookupErr   error
+	lookups     int
+	onCharge    func() // runs while a payment is charged
+	onReview    func() // runs while a challenged payment is approved or denied
+}
+
+func (g *stubGateway) Name() string {
+	return g.name
+}
+
+func (g *stubGateway) Channels(method entity.PaymentMethod) []string {
+	return map[entity.PaymentMethod][]string{
+		entity.PaymentMethodBankTransfer: {"bca"},
+		entity.PaymentMethodQRIS:         {"qris"},
+	}[method]
+}
+
+func (g *stubGateway) Charge(ctx context.Context, req *gateway.ChargeRequest) (*gateway.ChargeResult, error) {
+	g.charges = append(g.charges, req)
+	if g.onCharge != nil {
+		g.onCharge()
+	}
+	return &gateway.ChargeResult{TransactionID: fmt.Sprintf("tx-%d", len(g.charges)), VANumber: "8808"}, nil
+}
+
+func (g *stubGateway) Refund(ctx context.Context, req *gateway.RefundRequest) error {
+	if g.refundErr != nil {
+		return g.refundErr
+	}
+	g.refunds = append(g.refunds, req)
+	return nil
+}
+
+func (g *stubGateway) Approve(ctx context.Context, transactionID string) error {
+	if g.onReview != nil {
+		g.onReview()
+	}
+	return nil
+}
+
+func (g *stubGateway) Deny(ctx context.Context, transactionID string) error {
+	if g.onReview != nil {
+		g.onReview()
+	}
+	return nil
+}
+
+func (g *stubGateway) ParseNotification(header http.Header, payload []byte) (*gateway.Notification, error) {
+	if g.parseErr != nil {
+		return nil, g.parseErr
+	}
+	var body struct {
+		OrderID       string `json:"order_id"`
+		TransactionID string `json:"transaction_id"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, err
+	}
+	return &gateway.Notification{OrderID: body.OrderID, TransactionID: body.TransactionID, SignatureValid: true}, nil
+}
+
+func (g *stubGateway) GetTransaction(ctx context.Context, orderID, transactionID string) (*gateway.Transaction, error) {
+	g.lookups++
+	if g.lookupErr != nil {
+		return nil, g.lookupErr
+	}
+	if g.transaction == nil {
+		return nil, gateway.ErrTransactionNotFound
+	}
+	return g.transaction, nil
+}
+
+// newStubRegistry creates a gateway registry that sends every payment method to gw
+func newStubRegistry(gw *stubGateway) *gateway.Registry {
+	registry, err := gateway.NewRegistry(gw.name, nil, gw)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+type fakeNotificationUseCase struct {
+	usecase.NotificationUseCase
+	titles []string
+}
+
+func (uc *fakeNotificationUseCase) CreateNotification(ctx context.Context, userID uint, notificationType entity.NotificationType, title, message string, data map[string]interface{}) error {
+	uc.titles = append(uc.titles, title)
+	return nil
+}
+
+type fakePaymentUseCase struct {
+	usecase.PaymentUseCase
+	refunded []float64
+}
+
+func (uc *fakePaymentUseCase) RefundPayment(ctx context.Context, paymentID uint, amount float64, reason string, toWallet bool) error {
+	uc.refunded = append(uc.refunded, amount)
+	return nil
+}
+
+// fakeVariantRepo keeps variants and the stock changes made to them
+type fakeVariantRepo struct {
+	repository.ProductVariantRepository
+	variants map[uint]*entity.ProductVariant
+	skuErr   error // returned by GetBySKU instead of looking the SKU up
+}
+
+func newFakeVariantRepo(variants ...*entity.ProductVariant) *fakeVariantRepo {
+	r := &fakeVariantRepo{variants: make(map[uint]*entity.ProductVariant)}
+	for _, variant := range variants {
+		r.variants[variant.ID] = variant
+	}
+	return r
+}
+
+func (r *fakeVariantRepo) GetByID(ctx context.Context, id uint) (*entity.ProductVariant, error) {
+	variant, ok := r.variants[id]
+	if !ok {
+		return nil, repository.ErrProductVariantNotFound
+	}
+	copied := *variant
+	return &copied, nil
+}
+
+func (r *fakeVariantRepo) GetBySKU(ctx context.Context, sku string) (*entity.ProductVariant, error) {
+	if r.skuErr != nil {
+		return nil, r.skuErr
+	}
+	for _, variant := range r.variants {
+		if variant.SKU == sku {
+			copied := *variant
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrProductVariantNotFound
+}
+
+func (r *fakeVariantRepo) GetByProductID(ctx context.Context, productID uint) ([]*entity.ProductVariant, error) {
+	var variants []*entity.ProductVariant
+	for _, variant := range r.variants {
+		if variant.ProductID == productID {
+			copied := *variant
+			variants = append(variants, &copied)
+		}
+	}
+	return variants, nil
+}
+
+func (r *fakeVariantRepo) AdjustStock(ctx context.Context, id uint, delta int) error {
+	variant, ok := r.variants[id]
+	if !ok {
+		return repository.ErrProductVariantNotFound
+	}
+	if variant.Stock+delta < 0 {
+		return errors.New("insufficient stock")
+	}
+	variant.Stock += delta
+	return nil
+}
+
+type fakeProductRepo struct {
+	repository.ProductRepository
+	products map[uint]*entity.Product
+}
+
+func newFakeProductRepo(products ...*entity.Product) *fakeProductRepo {
+	r := &fakeProductRepo{products: make(map[uint]*entity.Product)}
+	for _, product := range products {
+		r.products[product.ID] = product
+	}
+	return r
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, id uint) (*entity.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, errors.New("product not found")
+	}
+	copied := *product
+	return &copied, nil
+}
+
+type fakeCartRepo struct {
+	repository.CartRepository
+	cart *entity.Cart
+}
+
+func (r *fakeCartRepo) GetOrCreate(ctx context.Context, userID uint) (*entity.Cart, error) {
+	return r.cart, nil
+}
+
+func (r *fakeCartRepo) GetByUserID(ctx context.Context, userID uint) (*entity.Cart, error) {
+	return r.cart, nil
+}
+
+type fakeAddressRepo struct {
+	repository.AddressRepository
+}
+
+func (r *fakeAddressRepo) GetByID(ctx context.Context, id uint) (*entity.Address, error) {
+	return &entity.Address{ID: id, UserID: 7, City: "Bandung"}, nil
+}
+
+// fakeVoucherRepo keeps vouchers by code and the orders whose use was released
+type fakeVoucherRepo struct {
+	repository.VoucherRepository
+	vouchers map[string]*entity.Voucher
+	redeemed []*entity.VoucherRedemption
+	released []uint
+}
+
+func (r *fakeVoucherRepo) GetByCode(ctx context.Context, code string) (*entity.Voucher, error) {
+	voucher, ok := r.vouchers[code]
+	if !ok {
+		return nil, errors.New("voucher not found")
+	}
+	return voucher, nil
+}
+
+func (r *fakeVoucherRepo) CountUserRedemptions(ctx context.Context, voucherID, userID uint) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeVoucherRepo) GetRedemption(ctx context.Context, orderID uint) (*entity.VoucherRedemption, error) {
+	for _, redemption := range r.redeemed {
+		if redemption.OrderID == orderID {
+			return redemption, nil
+		}
+	}
+	return nil, repository.ErrVoucherRedemptionNotFound
+}
+
+func (r *fakeVoucherRepo) Redeem(ctx context.Context, redemption *entity.VoucherRedemption, order *entity.Order) error {
+	r.redeemed = append(r.redeemed, redemption)
+	return nil
+}
+
+func (r *fakeVoucherRepo) Release(ctx context.Context, orderID uint) error {
+	r.released = append(r.released, orderID)
+	return nil
+}
+
+type fakeWalletUseCase struct {
+	usecase.WalletUseCase
+	released   []uint
+	releaseErr error // returned by ReleaseForOrder instead of releasing
+}
+
+func (uc *fakeWalletUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	if uc.releaseErr != nil {
+		return uc.releaseErr
+	}
+	uc.released = append(uc.released, orderID)
+	return nil
+}
+
+// fakeWalletRepo keeps the store credit spent on orders with the order and
+// payment it was spent with, and the entries recorded for orders
+type fakeWalletRepo struct {
+	repository.WalletRepository
+	debits       []*entity.WalletTransaction
+	orders       []entity.Order
+	payments     []*repository.WalletPayment
+	transactions []*entity.WalletTransaction
+}
+
+func (r *fakeWalletRepo) GetOrderTransactions(ctx context.Context, orderID uint) ([]*entity.WalletTransaction, error) {
+	var transactions []*entity.WalletTransaction
+	for _, transaction := range r.transactions {
+		if transaction.OrderID != nil && *transaction.OrderID == orderID {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+func (r *fakeWalletRepo) Record(ctx context.Context, transaction *entity.WalletTransaction) (bool, error) {
+	for _, recorded := range r.transactions {
+		if recorded.Reference == transaction.Reference {
+			return false, nil
+		}
+	}
+	r.transactions = append(r.transactions, transaction)
+	return true, nil
+}
+
+func (r *fakeWalletRepo) Pay(ctx context.Context, transaction *entity.WalletTransaction, order *entity.Order, payment *repository.WalletPayment) error {
+	r.debits = append(r.debits, transaction)
+	r.orders = append(r.orders, *order)
+	r.payments = append(r.payments, payment)
+	if payment != nil {
+		payment.Payment.ID = 1
+	}
+	return nil
+}
+
+// fakeGiftCardRepo keeps gift cards by ID
+type fakeGiftCardRepo struct {
+	repository.GiftCardRepository
+	cards map[uint]*entity.GiftCard
+}
+
+func (r *fakeGiftCardRepo) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.GiftCard, error) {
+	var cards []*entity.GiftCard
+	for _, card := range r.cards {
+		if card.OrderID != nil && *card.OrderID == orderID {
+			copied := *card
+			cards = append(cards, &copied)
+		}
+	}
+	return cards, nil
+}
+
+func (r *fakeGiftCardRepo) UpdateIfStatus(ctx context.Context, card *entity.GiftCard, expected ...entity.GiftCardStatus) (bool, error) {
+	stored, ok := r.cards[card.ID]
+	if !ok || !slices.Contains(expected, stored.Status) {
+		return false, nil
+	}
+	copied := *card
+	r.cards[card.ID] = &copied
+	return true, nil
+}
+
+// fakeLoyaltyRepo keeps the redemptions with the order they were made on,
+// failing them with err when set
+type fakeLoyaltyRepo struct {
+	repository.LoyaltyRepository
+	err          error
+	redeemed     []*entity.LoyaltyTransaction
+	orders       []entity.Order
+	restored     []uint
+	transactions []*entity.LoyaltyTransaction // credited and debited
+}
+
+func (r *fakeLoyaltyRepo) Credit(ctx context.Context, transaction *entity.LoyaltyTransaction) (bool, error) {
+	r.transactions = append(r.transactions, transaction)
+	return true, nil
+}
+
+func (r *fakeLoyaltyRepo) Debit(ctx context.Context, transaction *entity.LoyaltyTransaction, allowNegative bool) (bool, error) {
+	r.transactions = append(r.transactions, transaction)
+	return true, nil
+}
+
+func (r *fakeLoyaltyRepo) GetOrderTransactions(ctx context.Context, orderID uint) ([]*entity.LoyaltyTransaction, error) {
+	var transactions []*entity.LoyaltyTransaction
+	for _, transaction := range r.transactions {
+		if transaction.OrderID != nil && *transaction.OrderID == orderID {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+func (r *fakeLoyaltyRepo) ListMultipliers(ctx context.Context) ([]*entity.LoyaltyCategoryMultiplier, error) {
+	return nil, nil
+}
+
+func (r *fakeLoyaltyRepo) Redeem(ctx context.Context, transaction *entity.LoyaltyTransaction, order *entity.Order) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.redeemed = append(r.redeemed, transaction)
+	r.orders = append(r.orders, *order)
+	return nil
+}
+
+func (r *fakeLoyaltyRepo) Restore(ctx context.Context, orderID uint) error {
+	r.restored = append(r.restored, orderID)
+	return nil
+}
+
+// fakeFlashSaleUseCase puts the variants in sales on sale and keeps the quota
+// given back, by flash sale item ID
+type fakeFlashSaleUseCase struct {
+	usecase.FlashSaleUseCase
+	sales     map[uint]*entity.FlashSaleItem // by variant ID
+	cancelled map[uint]int
+	released  []uint
+}
+
+func (uc *fakeFlashSaleUseCase) Reserve(ctx context.Context, userID, variantID uint, quantity int) (*entity.FlashSaleItem, error) {
+	item, ok := uc.sales[variantID]
+	if !ok {
+		return nil, nil
+	}
+	if item.Sold+quantity > item.Quota {
+		return nil, repository.ErrFlashSaleSoldOut
+	}
+	item.Sold += quantity
+	return item, nil
+}
+
+func (uc *fakeFlashSaleUseCase) ApplyEffectivePrices(ctx context.Context, products ...*entity.Product) error {
+	for _, product := range products {
+		for i := range product.Variants {
+			product.Variants[i].FlashSale = uc.sales[product.Variants[i].ID]
+		}
+	}
+	return nil
+}
+
+func (uc *fakeFlashSaleUseCase) CancelReservation(ctx context.Context, purchase *entity.FlashSalePurchase) error {
+	if uc.cancelled == nil {
+		uc.cancelled = make(map[uint]int)
+	}
+	uc.cancelled[purchase.FlashSaleItemID] += purchase.Quantity
+	return nil
+}
+
+func (uc *fakeFlashSaleUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	uc.released = append(uc.released, orderID)
+	return nil
+}
+
+type fakePromotionRepo struct {
+	repository.PromotionRepository
+	promotions []*entity.Promotion
+}
+
+func (r *fakePromotionRepo) GetActive(ctx context.Context, at time.Time) ([]*entity.Promotion, error) {
+	return r.promotions, nil
+}
+
+type fakeProductSearchRepo struct {
+	repository.ProductSearchRepository
+	products map[uint]*entity.Product
+}
+
+func (r *fakeProductSearchRepo) GetActiveByIDs(ctx context.Context, ids []uint) ([]*entity.Product, error) {
+	products := []*entity.Product{}
+	for _, id := range ids {
+		if product, ok := r.products[id]; ok && product.IsActive {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+type fakeCategoryTreeRepo struct {
+	repository.CategoryTreeRepository
+	categories map[uint]*entity.Category
+}
+
+func (r *fakeCategoryTreeRepo) GetAncestors(ctx context.Context, id uint) ([]*entity.Category, error) {
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, errors.New("category not found")
+	}
+	return []*entity.Category{category}, nil
+}
+
+type fakeAttributeRepo struct {
+	repository.AttributeRepository
+	createErr error
+	created   []*entity.AttributeDefinition
+}
+
+func (r *fakeAttributeRepo) CodeExists(ctx context.Context, categoryID uint, code string, excludeID uint) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeAttributeRepo) Create(ctx context.Context, attribute *entity.AttributeDefinition) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.created = append(r.created, attribute)
+	return nil
+}
+
+type fakeReviewRepo struct {
+	repository.ReviewRepository
+	reviews map[uint]*entity.Review
+	created []*entity.Review
+}
+
+func (r *fakeReviewRepo) Create(ctx context.Context, review *entity.Review) error {
+	r.created = append(r.created, review)
+	return nil
+}
+
+func (r *fakeReviewRepo) GetByID(ctx context.Context, id uint) (*entity.Review, error) {
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, errors.New("review not found")
+	}
+	copied := *review
+	return &copied, nil
+}
+
+func (r *fakeReviewRepo) Update(ctx context.Context, review *entity.Review) error {
+	copied := *review
+	r.reviews[review.ID] = &copied
+	return nil
+}
+
+type fakeBodyProfileRepo struct {
+	repository.BodyProfileRepository
+	profiles map[uint]*entity.BodyProfile
+}
+
+func (r *fakeBodyProfileRepo) GetByUserID(ctx context.Context, userID uint) (*entity.BodyProfile, error) {
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, errors.New("body profile not found")
+	}
+	return profile, nil
+}
+
+func (r *fakeBodyProfileRepo) Save(ctx context.Context, profile *entity.BodyProfile) error {
+	r.profiles[profile.UserID] = profile
+	return nil
+}
+
+type fakeSizeChartRepo struct {
+	repository.SizeChartRepository
+	fit entity.FitSummary
+}
+
+func (r *fakeSizeChartRepo) GetFitSummary(ctx context.Context, productID uint) (*entity.FitSummary, error) {
+	fit := r.fit
+	return &fit, nil
+}
+
+type fakeImageUseCase struct {
+	stored *entity.StoredImage
+}
+
+func (uc *fakeImageUseCase) StoreUpload(ctx context.Context, file *multipart.FileHeader) (*entity.StoredImage, error) {
+	return uc.stored, nil
+}
+
+type fakeProductImageRepo struct {
+	repository.ProductImageRepository
+	images    []*entity.ProductImage
+	racedWith *entity.ProductImage // stored by a concurrent upload when Create is called
+}
+
+func (r *fakeProductImageRepo) GetByProductID(ctx context.Context, productID uint) ([]*entity.ProductImage, error) {
+	var images []*entity.ProductImage
+	for _, image := range r.images {
+		if image.ProductID == productID {
+			copied := *image
+			images = append(images, &copied)
+		}
+	}
+	return images, nil
+}
+
+func (r *fakeProductImageRepo) Create(ctx context.Context, image *entity.ProductImage) error {
+	if r.racedWith != nil {
+		r.images = append(r.images, r.racedWith)
+		return repository.ErrProductImageExists
+	}
+	image.ID = uint(len(r.images) + 1)
+	r.images = append(r.images, image)
+	return nil
+}
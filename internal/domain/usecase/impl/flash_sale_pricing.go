@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"context"
+	"math"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// flashSalePricedProductUseCase wraps a ProductUseCase so every product it
+// returns to customers carries its effective price
+type flashSalePricedProductUseCase struct {
+	usecase.ProductUseCase
+	flashSaleUseCase usecase.FlashSaleUseCase
+}
+
+// NewFlashSalePricedProductUseCase creates a ProductUseCase that applies flash sale prices to the products it returns
+func NewFlashSalePricedProductUseCase(productUseCase usecase.ProductUseCase, flashSaleUseCase usecase.FlashSaleUseCase) usecase.ProductUseCase {
+	return &flashSalePricedProductUseCase{
+		ProductUseCase:   productUseCase,
+		flashSaleUseCase: flashSaleUseCase,
+	}
+}
+
+// GetProductByID gets a product by ID with its effective price
+func (uc *flashSalePricedProductUseCase) GetProductByID(ctx context.Context, id uint) (*entity.Product, error) {
+	product, err := uc.ProductUseCase.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return product, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, product)
+}
+
+// GetProductBySlug gets a product by slug with its effective price
+func (uc *flashSalePricedProductUseCase) GetProductBySlug(ctx context.Context, slug string) (*entity.Product, error) {
+	product, err := uc.ProductUseCase.GetProductBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return product, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, product)
+}
+
+// ListProducts lists products with their effective prices
+func (uc *flashSalePricedProductUseCase) ListProducts(ctx context.Context, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	products, count, err := uc.ProductUseCase.ListProducts(ctx, filter, sort, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, count, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// SearchProducts searches products and returns them with their effective prices
+func (uc *flashSalePricedProductUseCase) SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	products, count, err := uc.ProductUseCase.SearchProducts(ctx, keyword, filter, sort, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, count, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// GetBestSellers gets best-selling products with their effective prices
+func (uc *flashSalePricedProductUseCase) GetBestSellers(ctx context.Context, limit int) ([]*entity.Product, error) {
+	products, err := uc.ProductUseCase.GetBestSellers(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return products, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// GetNewArrivals gets new products with their effective prices
+func (uc *flashSalePricedProductUseCase) GetNewArrivals(ctx context.Context, limit int) ([]*entity.Product, error) {
+	products, err := uc.ProductUseCase.GetNewArrivals(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return products, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// GetTopRated gets top-rated products with their effective prices
+func (uc *flashSalePricedProductUseCase) GetTopRated(ctx context.Context, limit int) ([]*entity.Product, error) {
+	products, err := uc.ProductUseCase.GetTopRated(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return products, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// variantPrice returns what a variant of a product sells for now: its flash
+// sale price while the sale has units left, or else the product's selling
+// price. The product's flash sales must be applied with ApplyEffectivePrices.
+func variantPrice(product *entity.Product, variantID uint) float64 {
+	for _, variant := range product.Variants {
+		if variant.ID == variantID && variant.FlashSale != nil && variant.FlashSale.Sold < variant.FlashSale.Quota {
+			return math.Min(variant.FlashSale.SalePrice, product.SellingPrice())
+		}
+	}
+	return product.SellingPrice()
+}
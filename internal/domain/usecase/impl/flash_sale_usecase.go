@@ -0,0 +1,362 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// flashSaleQuotaGrace keeps live quota counters around a little after a sale
+// ends so late cancellations can still give units back
+const flashSaleQuotaGrace = time.Hour
+
+type flashSaleUseCase struct {
+	flashSaleRepo repository.FlashSaleRepository
+	quotaRepo     repository.FlashSaleQuotaRepository
+	productRepo   repository.ProductRepository
+	variantRepo   repository.ProductVariantRepository
+}
+
+// NewFlashSaleUseCase creates a new FlashSaleUseCase instance
+func NewFlashSaleUseCase(
+	flashSaleRepo repository.FlashSaleRepository,
+	quotaRepo repository.FlashSaleQuotaRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+) usecase.FlashSaleUseCase {
+	return &flashSaleUseCase{
+		flashSaleRepo: flashSaleRepo,
+		quotaRepo:     quotaRepo,
+		productRepo:   productRepo,
+		variantRepo:   variantRepo,
+	}
+}
+
+// GetCurrentFlashSales gets the flash sales that are running or still to come
+func (uc *flashSaleUseCase) GetCurrentFlashSales(ctx context.Context) ([]*entity.FlashSale, error) {
+	return uc.flashSaleRepo.GetCurrent(ctx, time.Now())
+}
+
+// GetFlashSale gets a flash sale with its items
+func (uc *flashSaleUseCase) GetFlashSale(ctx context.Context, id uint) (*entity.FlashSale, error) {
+	return uc.flashSaleRepo.GetByID(ctx, id)
+}
+
+// ApplyEffectivePrices sets the price customers pay now on each product and
+// marks the variants that are in a running flash sale. Prices are worked out
+// on every read, so they revert by themselves once a sale ends.
+func (uc *flashSaleUseCase) ApplyEffectivePrices(ctx context.Context, products ...*entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		product.EffectivePrice = product.SellingPrice()
+		product.FlashSaleEndsAt = nil
+		productIDs = append(productIDs, product.ID)
+	}
+
+	items, err := uc.flashSaleRepo.GetLiveItemsByProductIDs(ctx, productIDs, time.Now())
+	if err != nil {
+		return err
+	}
+
+	byVariant := make(map[uint]*entity.FlashSaleItem, len(items))
+	for _, item := range items {
+		byVariant[item.VariantID] = item
+	}
+
+	for _, product := range products {
+		for i := range product.Variants {
+			product.Variants[i].FlashSale = byVariant[product.Variants[i].ID]
+		}
+		for _, item := range items {
+			if item.ProductID != product.ID || item.Sold >= item.Quota {
+				continue
+			}
+			if item.SalePrice < product.EffectivePrice {
+				product.EffectivePrice = item.SalePrice
+				endsAt := item.FlashSale.EndsAt
+				product.FlashSaleEndsAt = &endsAt
+			}
+		}
+	}
+
+	return nil
+}
+
+// Reserve takes flash sale quota for a variant being checked out. It returns
+// the sale item, whose SalePrice the order item must use, or nil when the
+// variant is not on sale. The purchase is recorded when the order is placed;
+// when it is not, the reservation must be given back with CancelReservation.
+func (uc *flashSaleUseCase) Reserve(ctx context.Context, userID, variantID uint, quantity int) (*entity.FlashSaleItem, error) {
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+
+	item, err := uc.flashSaleRepo.GetLiveItemByVariantID(ctx, variantID, time.Now())
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	if item.PerUserLimit > 0 && quantity > item.PerUserLimit {
+		return nil, fmt.Errorf("flash sale limit is %d per customer", item.PerUserLimit)
+	}
+
+	for loaded := false; ; loaded = true {
+		err := uc.quotaRepo.Reserve(ctx, item.ID, userID, quantity, item.PerUserLimit)
+		switch {
+		case err == nil:
+			return item, nil
+		case errors.Is(err, repository.ErrFlashSaleQuotaNotLoaded) && !loaded:
+			// The counters expired or were lost; rebuild them from the recorded purchases
+			if err := uc.loadQuota(ctx, item, userID); err != nil {
+				return nil, err
+			}
+		case errors.Is(err, repository.ErrFlashSaleUserLimit):
+			return nil, fmt.Errorf("flash sale limit is %d per customer", item.PerUserLimit)
+		default:
+			return nil, err
+		}
+	}
+}
+
+// CancelReservation gives back quota reserved for a purchase whose order was not placed
+func (uc *flashSaleUseCase) CancelReservation(ctx context.Context, purchase *entity.FlashSalePurchase) error {
+	return uc.quotaRepo.Release(ctx, purchase.FlashSaleItemID, purchase.UserID, purchase.Quantity)
+}
+
+// ReleaseForOrder gives back the flash sale units of a cancelled order
+func (uc *flashSaleUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	purchases, err := uc.flashSaleRepo.ReleasePurchases(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, purchase := range purchases {
+		if err := uc.quotaRepo.Release(ctx, purchase.FlashSaleItemID, purchase.UserID, purchase.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateFlashSale schedules a flash sale (admin function)
+func (uc *flashSaleUseCase) CreateFlashSale(ctx context.Context, sale *entity.FlashSale, items []usecase.FlashSaleItemRequest) error {
+	if err := uc.buildItems(ctx, sale, items); err != nil {
+		return err
+	}
+
+	sale.Status = entity.FlashSaleStatusScheduled
+	if err := uc.flashSaleRepo.Create(ctx, sale); err != nil {
+		return err
+	}
+
+	if !sale.StartsAt.After(time.Now()) {
+		return uc.start(ctx, sale)
+	}
+	return nil
+}
+
+// UpdateFlashSale changes a flash sale that has not started yet (admin function)
+func (uc *flashSaleUseCase) UpdateFlashSale(ctx context.Context, sale *entity.FlashSale, items []usecase.FlashSaleItemRequest) error {
+	existing, err := uc.flashSaleRepo.GetByID(ctx, sale.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Status != entity.FlashSaleStatusScheduled {
+		return errors.New("only scheduled flash sales can be changed")
+	}
+
+	if err := uc.buildItems(ctx, sale, items); err != nil {
+		return err
+	}
+
+	sale.Status = entity.FlashSaleStatusScheduled
+	sale.CreatedAt = existing.CreatedAt
+	if err := uc.flashSaleRepo.Update(ctx, sale); err != nil {
+		return err
+	}
+
+	if !sale.StartsAt.After(time.Now()) {
+		return uc.start(ctx, sale)
+	}
+	return nil
+}
+
+// CancelFlashSale stops a scheduled or running flash sale; prices revert immediately (admin function)
+func (uc *flashSaleUseCase) CancelFlashSale(ctx context.Context, id uint) error {
+	sale, err := uc.flashSaleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	sale.Status = entity.FlashSaleStatusCancelled
+	applied, err := uc.flashSaleRepo.UpdateIfStatus(ctx, sale, entity.FlashSaleStatusScheduled, entity.FlashSaleStatusActive)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("flash sale has already ended")
+	}
+
+	return uc.clearQuota(ctx, sale)
+}
+
+// ListFlashSales lists flash sales with filter and pagination (admin function)
+func (uc *flashSaleUseCase) ListFlashSales(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.FlashSale, int64, error) {
+	offset := (page - 1) * limit
+	return uc.flashSaleRepo.List(ctx, filter, offset, limit)
+}
+
+// SyncFlashSales starts scheduled flash sales whose start time has passed and
+// ends running ones whose end time has passed. It returns the number of
+// campaigns changed.
+func (uc *flashSaleUseCase) SyncFlashSales(ctx context.Context) (int, error) {
+	sales, err := uc.flashSaleRepo.GetDueForStatusChange(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, sale := range sales {
+		if sale.EndsAt.After(time.Now()) {
+			err = uc.start(ctx, sale)
+		} else {
+			err = uc.end(ctx, sale)
+		}
+		if err != nil {
+			return changed, fmt.Errorf("update flash sale %d: %w", sale.ID, err)
+		}
+		changed++
+	}
+
+	return changed, nil
+}
+
+// start opens a scheduled flash sale and loads its quotas
+func (uc *flashSaleUseCase) start(ctx context.Context, sale *entity.FlashSale) error {
+	sale.Status = entity.FlashSaleStatusActive
+	applied, err := uc.flashSaleRepo.UpdateIfStatus(ctx, sale, entity.FlashSaleStatusScheduled)
+	if err != nil || !applied {
+		return err
+	}
+
+	ttl := time.Until(sale.EndsAt) + flashSaleQuotaGrace
+	for _, item := range sale.Items {
+		if err := uc.quotaRepo.LoadRemaining(ctx, item.ID, item.Quota-item.Sold, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// end closes a flash sale whose time is up
+func (uc *flashSaleUseCase) end(ctx context.Context, sale *entity.FlashSale) error {
+	sale.Status = entity.FlashSaleStatusEnded
+	applied, err := uc.flashSaleRepo.UpdateIfStatus(ctx, sale, entity.FlashSaleStatusScheduled, entity.FlashSaleStatusActive)
+	if err != nil || !applied {
+		return err
+	}
+	return uc.clearQuota(ctx, sale)
+}
+
+// clearQuota removes the live quota counters of a flash sale
+func (uc *flashSaleUseCase) clearQuota(ctx context.Context, sale *entity.FlashSale) error {
+	for _, item := range sale.Items {
+		if err := uc.quotaRepo.Clear(ctx, item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadQuota rebuilds the live quota counters of an item for a user from the recorded purchases
+func (uc *flashSaleUseCase) loadQuota(ctx context.Context, item *entity.FlashSaleItem, userID uint) error {
+	ttl := time.Until(item.FlashSale.EndsAt) + flashSaleQuotaGrace
+	if err := uc.quotaRepo.LoadRemaining(ctx, item.ID, item.Quota-item.Sold, ttl); err != nil {
+		return err
+	}
+
+	if item.PerUserLimit == 0 {
+		return nil
+	}
+
+	purchased, err := uc.flashSaleRepo.CountUserPurchased(ctx, item.ID, userID)
+	if err != nil {
+		return err
+	}
+	return uc.quotaRepo.LoadUserPurchased(ctx, item.ID, userID, purchased, ttl)
+}
+
+// buildItems validates a flash sale and its requested items and sets them on the sale
+func (uc *flashSaleUseCase) buildItems(ctx context.Context, sale *entity.FlashSale, items []usecase.FlashSaleItemRequest) error {
+	if sale.Name == "" {
+		return errors.New("flash sale name is required")
+	}
+	if !sale.EndsAt.After(sale.StartsAt) {
+		return errors.New("flash sale must end after it starts")
+	}
+	if !sale.EndsAt.After(time.Now()) {
+		return errors.New("flash sale must end in the future")
+	}
+	if len(items) == 0 {
+		return errors.New("at least one variant must be on sale")
+	}
+
+	sale.Items = make([]entity.FlashSaleItem, 0, len(items))
+	variantIDs := make([]uint, 0, len(items))
+	seen := make(map[uint]bool, len(items))
+	for _, req := range items {
+		if seen[req.VariantID] {
+			return fmt.Errorf("variant %d is listed more than once", req.VariantID)
+		}
+		seen[req.VariantID] = true
+
+		variant, err := uc.variantRepo.GetByID(ctx, req.VariantID)
+		if err != nil {
+			return fmt.Errorf("variant %d: %w", req.VariantID, err)
+		}
+		product, err := uc.productRepo.GetByID(ctx, variant.ProductID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case req.SalePrice <= 0 || req.SalePrice >= product.SellingPrice():
+			return fmt.Errorf("sale price of variant %d must be below its current price", req.VariantID)
+		case req.Quota <= 0:
+			return fmt.Errorf("quota of variant %d must be greater than 0", req.VariantID)
+		case req.Quota > variant.Stock:
+			return fmt.Errorf("quota of variant %d exceeds its stock of %d", req.VariantID, variant.Stock)
+		case req.PerUserLimit < 0:
+			return fmt.Errorf("per customer limit of variant %d cannot be negative", req.VariantID)
+		}
+
+		sale.Items = append(sale.Items, entity.FlashSaleItem{
+			ProductID:    product.ID,
+			VariantID:    variant.ID,
+			SalePrice:    roundPrice(req.SalePrice),
+			Quota:        req.Quota,
+			PerUserLimit: req.PerUserLimit,
+		})
+		variantIDs = append(variantIDs, variant.ID)
+	}
+
+	overlapping, err := uc.flashSaleRepo.GetOverlappingVariantIDs(ctx, variantIDs, sale.StartsAt, sale.EndsAt, sale.ID)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return fmt.Errorf("variant %d is already in another flash sale at that time", overlapping[0])
+	}
+
+	return nil
+}
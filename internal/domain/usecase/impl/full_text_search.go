@@ -0,0 +1,28 @@
+package impl
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// fullTextSearchProductUseCase wraps a ProductUseCase so product searches go
+// through the ranked full-text search, in the database or an external index
+type fullTextSearchProductUseCase struct {
+	usecase.ProductUseCase
+	searcher usecase.ProductSearcher
+}
+
+// NewFullTextSearchProductUseCase creates a ProductUseCase that searches products with full-text search
+func NewFullTextSearchProductUseCase(productUseCase usecase.ProductUseCase, searcher usecase.ProductSearcher) usecase.ProductUseCase {
+	return &fullTextSearchProductUseCase{
+		ProductUseCase: productUseCase,
+		searcher:       searcher,
+	}
+}
+
+// SearchProducts searches products ranked by relevance with highlighted matches
+func (uc *fullTextSearchProductUseCase) SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	return uc.searcher.SearchProducts(ctx, keyword, filter, sort, page, limit)
+}
@@ -0,0 +1,351 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/auth"
+)
+
+// giftCardCodeAlphabet leaves out characters that are easily confused when typed
+const giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// maxGiftCardBatch caps how many gift cards an admin can issue at once
+const maxGiftCardBatch = 500
+
+// GiftCardPolicy holds the rules for buying and issuing gift cards
+type GiftCardPolicy struct {
+	MinAmount float64
+	MaxAmount float64       // zero means no limit
+	Validity  time.Duration // how long a card can be redeemed after it is activated
+}
+
+type giftCardUseCase struct {
+	giftCardRepo        repository.GiftCardRepository
+	walletRepo          repository.WalletRepository
+	orderRepo           repository.OrderRepository
+	orderStatusRepo     repository.OrderStatusHistoryRepository
+	notificationUseCase usecase.NotificationUseCase
+	emailService        auth.EmailService
+	policy              GiftCardPolicy
+}
+
+// NewGiftCardUseCase creates a new GiftCardUseCase instance
+func NewGiftCardUseCase(
+	giftCardRepo repository.GiftCardRepository,
+	walletRepo repository.WalletRepository,
+	orderRepo repository.OrderRepository,
+	orderStatusRepo repository.OrderStatusHistoryRepository,
+	notificationUseCase usecase.NotificationUseCase,
+	emailService auth.EmailService,
+	policy GiftCardPolicy,
+) usecase.GiftCardUseCase {
+	return &giftCardUseCase{
+		giftCardRepo:        giftCardRepo,
+		walletRepo:          walletRepo,
+		orderRepo:           orderRepo,
+		orderStatusRepo:     orderStatusRepo,
+		notificationUseCase: notificationUseCase,
+		emailService:        emailService,
+		policy:              policy,
+	}
+}
+
+// PurchaseGiftCard creates a gift card awaiting payment and the order that
+// pays for it. The order has no items and is paid like any other order.
+func (uc *giftCardUseCase) PurchaseGiftCard(ctx context.Context, userID uint, purchase usecase.GiftCardPurchase) (*entity.GiftCard, *entity.Order, error) {
+	amount := roundPrice(purchase.Amount)
+	if err := uc.validateAmount(amount); err != nil {
+		return nil, nil, err
+	}
+
+	code, err := newGiftCardCode()
+	if err != nil {
+		return nil, nil, err
+	}
+	// The order number is seen by the payment gateway, so it must not reveal the code
+	reference, err := newGiftCardCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := &entity.Order{
+		UserID:      userID,
+		OrderNumber: "GC-" + strings.ReplaceAll(reference, "-", "")[:10],
+		Status:      entity.OrderStatusPending,
+		TotalAmount: amount,
+		FinalAmount: amount,
+		Notes:       "Gift card",
+	}
+	if err := uc.orderRepo.Create(ctx, order); err != nil {
+		return nil, nil, err
+	}
+
+	// The history is informational; a failure to record it must not fail the purchase
+	_ = uc.orderStatusRepo.Record(ctx, &entity.OrderStatusHistory{
+		OrderID:   order.ID,
+		ToStatus:  order.Status,
+		ActorType: entity.OrderStatusActorCustomer,
+		ActorID:   &userID,
+		Note:      "Gift card purchase",
+	})
+
+	card := &entity.GiftCard{
+		Code:           code,
+		Amount:         amount,
+		Balance:        amount,
+		Status:         entity.GiftCardStatusPending,
+		ExpiresAt:      time.Now().Add(uc.policy.Validity),
+		PurchaserID:    &userID,
+		OrderID:        &order.ID,
+		RecipientName:  strings.TrimSpace(purchase.RecipientName),
+		RecipientEmail: strings.TrimSpace(purchase.RecipientEmail),
+		Message:        strings.TrimSpace(purchase.Message),
+	}
+	if err := uc.giftCardRepo.Create(ctx, card); err != nil {
+		return nil, nil, err
+	}
+
+	return card, order, nil
+}
+
+// GetUserGiftCards gets the gift cards bought by a user
+func (uc *giftCardUseCase) GetUserGiftCards(ctx context.Context, userID uint, page, limit int) ([]*entity.GiftCard, int64, error) {
+	offset := (page - 1) * limit
+	return uc.giftCardRepo.GetByPurchaser(ctx, userID, offset, limit)
+}
+
+// RedeemGiftCard moves the balance of a gift card into the user's wallet
+func (uc *giftCardUseCase) RedeemGiftCard(ctx context.Context, userID uint, code string) (*entity.Wallet, error) {
+	card, err := uc.giftCardRepo.GetByCode(ctx, normalizeGiftCardCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !card.IsRedeemable(now) {
+		switch {
+		case card.Status == entity.GiftCardStatusRedeemed:
+			return nil, errors.New("gift card has already been redeemed")
+		case card.Status == entity.GiftCardStatusActive && !now.Before(card.ExpiresAt):
+			return nil, errors.New("gift card has expired")
+		default:
+			return nil, errors.New("gift card cannot be redeemed")
+		}
+	}
+
+	card.RedeemedBy = &userID
+	card.RedeemedAt = &now
+	err = uc.giftCardRepo.Redeem(ctx, card, &entity.WalletTransaction{
+		UserID:      userID,
+		Type:        entity.WalletTransactionGiftCard,
+		Amount:      card.Balance,
+		GiftCardID:  &card.ID,
+		Reference:   fmt.Sprintf("gift-card-%d", card.ID),
+		Description: "Gift card redeemed",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.walletRepo.GetWallet(ctx, userID)
+}
+
+// IssueForOrder activates the gift cards paid for by an order, sends them to
+// their recipients and completes the order, since there is nothing to ship
+func (uc *giftCardUseCase) IssueForOrder(ctx context.Context, order *entity.Order) error {
+	cards, err := uc.giftCardRepo.GetByOrderID(ctx, order.ID)
+	if err != nil || len(cards) == 0 {
+		return err
+	}
+
+	for _, card := range cards {
+		card.Status = entity.GiftCardStatusActive
+		card.ExpiresAt = time.Now().Add(uc.policy.Validity)
+		applied, err := uc.giftCardRepo.UpdateIfStatus(ctx, card, entity.GiftCardStatusPending)
+		if err != nil {
+			return err
+		}
+		if applied {
+			uc.deliver(ctx, order.UserID, card)
+		}
+	}
+
+	if order.Status != entity.OrderStatusProcessing {
+		return nil
+	}
+	for _, status := range []entity.OrderStatus{entity.OrderStatusShipped, entity.OrderStatusDelivered} {
+		if err := transitionOrder(ctx, uc.orderStatusRepo, order, status, systemActor, "Gift card issued"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CancelForOrder cancels the gift cards of an order that was cancelled or
+// refunded, so they cannot be redeemed any more. Cards whose balance was
+// already moved into a wallet cannot be taken back, so their order cannot be
+// refunded.
+func (uc *giftCardUseCase) CancelForOrder(ctx context.Context, orderID uint) error {
+	cards, err := uc.giftCardRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, card := range cards {
+		if card.Status == entity.GiftCardStatusCancelled {
+			continue
+		}
+		card.Status = entity.GiftCardStatusCancelled
+		applied, err := uc.giftCardRepo.UpdateIfStatus(ctx, card, entity.GiftCardStatusPending, entity.GiftCardStatusActive)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return fmt.Errorf("gift card %d has already been redeemed", card.ID)
+		}
+	}
+	return nil
+}
+
+// IssueGiftCards issues active gift cards without a purchase, e.g. for
+// promotions or customer service (admin function)
+func (uc *giftCardUseCase) IssueGiftCards(ctx context.Context, amount float64, quantity int, expiresAt *time.Time) ([]*entity.GiftCard, error) {
+	amount = roundPrice(amount)
+	if amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	if quantity < 1 || quantity > maxGiftCardBatch {
+		return nil, fmt.Errorf("quantity must be between 1 and %d", maxGiftCardBatch)
+	}
+
+	expiry := time.Now().Add(uc.policy.Validity)
+	if expiresAt != nil {
+		if !expiresAt.After(time.Now()) {
+			return nil, errors.New("expiry must be in the future")
+		}
+		expiry = *expiresAt
+	}
+
+	cards := make([]*entity.GiftCard, 0, quantity)
+	for i := 0; i < quantity; i++ {
+		code, err := newGiftCardCode()
+		if err != nil {
+			return cards, err
+		}
+		card := &entity.GiftCard{
+			Code:      code,
+			Amount:    amount,
+			Balance:   amount,
+			Status:    entity.GiftCardStatusActive,
+			ExpiresAt: expiry,
+		}
+		if err := uc.giftCardRepo.Create(ctx, card); err != nil {
+			return cards, err
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
+// ListGiftCards lists gift cards (admin function)
+func (uc *giftCardUseCase) ListGiftCards(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.GiftCard, int64, error) {
+	offset := (page - 1) * limit
+	return uc.giftCardRepo.List(ctx, filter, offset, limit)
+}
+
+// ListGiftCardsByCursor lists gift cards with filter, one page after or before a cursor (admin function)
+func (uc *giftCardUseCase) ListGiftCardsByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.GiftCard, *entity.PageCursors, error) {
+	return uc.giftCardRepo.ListByCursor(ctx, filter, page)
+}
+
+// DisableGiftCard stops a gift card from being redeemed (admin function)
+func (uc *giftCardUseCase) DisableGiftCard(ctx context.Context, id uint) error {
+	card, err := uc.giftCardRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	card.Status = entity.GiftCardStatusCancelled
+	applied, err := uc.giftCardRepo.UpdateIfStatus(ctx, card, entity.GiftCardStatusPending, entity.GiftCardStatusActive)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("only unredeemed gift cards can be disabled")
+	}
+	return nil
+}
+
+// validateAmount checks a purchased gift card amount against the policy
+func (uc *giftCardUseCase) validateAmount(amount float64) error {
+	if amount <= 0 || amount < uc.policy.MinAmount {
+		return fmt.Errorf("gift card amount must be at least %.0f", uc.policy.MinAmount)
+	}
+	if uc.policy.MaxAmount > 0 && amount > uc.policy.MaxAmount {
+		return fmt.Errorf("gift card amount must be at most %.0f", uc.policy.MaxAmount)
+	}
+	return nil
+}
+
+// deliver sends an activated gift card to its recipient, or to the buyer when
+// there is none. The card is already active; failed messages must not undo that.
+func (uc *giftCardUseCase) deliver(ctx context.Context, purchaserID uint, card *entity.GiftCard) {
+	message := fmt.Sprintf("Your gift card of %.0f is ready. Code: %s, valid until %s.",
+		card.Amount, card.Code, card.ExpiresAt.Format("2 January 2006"))
+	if card.RecipientEmail != "" {
+		message = fmt.Sprintf("Your gift card of %.0f was sent to %s.", card.Amount, card.RecipientEmail)
+
+		body := fmt.Sprintf("Hi %s,\n\nYou received a gift card worth %.0f.\n\n", card.RecipientName, card.Amount)
+		if card.Message != "" {
+			body += card.Message + "\n\n"
+		}
+		body += fmt.Sprintf("Code: %s\nValid until: %s\n\nRedeem it into your store credit wallet when you sign in.",
+			card.Code, card.ExpiresAt.Format("2 January 2006"))
+		_ = uc.emailService.SendEmail(card.RecipientEmail, "You received a gift card", body)
+	}
+
+	_ = uc.notificationUseCase.CreateNotification(ctx, purchaserID, entity.NotificationTypeOrder,
+		"Gift card issued", message,
+		map[string]interface{}{"gift_card_id": card.ID, "order_id": card.OrderID},
+	)
+}
+
+// newGiftCardCode generates a random code in the form XXXX-XXXX-XXXX-XXXX
+func newGiftCardCode() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, b := range random {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(giftCardCodeAlphabet[int(b)%len(giftCardCodeAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// normalizeGiftCardCode formats a code the way it is stored, whatever case and
+// grouping the customer typed it in
+func normalizeGiftCardCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	var normalized strings.Builder
+	for i, r := range code {
+		if i > 0 && i%4 == 0 {
+			normalized.WriteByte('-')
+		}
+		normalized.WriteRune(r)
+	}
+	return normalized.String()
+}
@@ -0,0 +1,115 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/imaging"
+	"fashion-shop/internal/infrastructure/storage"
+)
+
+type imageUseCase struct {
+	processor        *imaging.Processor
+	store            storage.Storage
+	maxUploadSize    int64
+	productImageRepo repository.ProductImageRepository
+}
+
+// NewImageUseCase creates a new ImageUseCase instance
+func NewImageUseCase(
+	processor *imaging.Processor,
+	store storage.Storage,
+	maxUploadSize int64,
+	productImageRepo repository.ProductImageRepository,
+) usecase.ImageUseCase {
+	return &imageUseCase{
+		processor:        processor,
+		store:            store,
+		maxUploadSize:    maxUploadSize,
+		productImageRepo: productImageRepo,
+	}
+}
+
+// StoreUpload validates an uploaded image and stores it in every size and
+// format. Renditions are stored under the content hash of the upload, so an
+// image uploaded before reuses the renditions already stored for it.
+func (uc *imageUseCase) StoreUpload(ctx context.Context, file *multipart.FileHeader) (*entity.StoredImage, error) {
+	data, err := uc.readUpload(file)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := imaging.Hash(data)
+	existing, err := uc.productImageRepo.FindByContentHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && len(existing.Renditions) > 0 {
+		return &entity.StoredImage{
+			Hash:       hash,
+			Width:      existing.Width,
+			Height:     existing.Height,
+			URL:        existing.URL,
+			Renditions: existing.Renditions,
+			SrcSet:     existing.Renditions.SrcSets(),
+		}, nil
+	}
+
+	processed, err := uc.processor.Process(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	stored := &entity.StoredImage{
+		Hash:   processed.Hash,
+		Width:  processed.Width,
+		Height: processed.Height,
+	}
+	for _, rendition := range processed.Renditions {
+		key := fmt.Sprintf("images/%s/%s/%s.%s", hash[:2], hash, rendition.Size, rendition.Extension())
+		url, err := uc.store.Save(ctx, key, rendition.Data, rendition.ContentType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store image: %w", err)
+		}
+		stored.Renditions = append(stored.Renditions, entity.ImageRendition{
+			Size:   rendition.Size,
+			Format: rendition.Format,
+			URL:    url,
+			Width:  rendition.Width,
+			Height: rendition.Height,
+			Bytes:  len(rendition.Data),
+		})
+		if rendition.Size == entity.ImageSizeLarge && rendition.Format != entity.ImageFormatWebP {
+			stored.URL = url
+		}
+	}
+	stored.SrcSet = stored.Renditions.SrcSets()
+	return stored, nil
+}
+
+// readUpload reads an uploaded file, rejecting files over the upload limit
+func (uc *imageUseCase) readUpload(file *multipart.FileHeader) ([]byte, error) {
+	tooLarge := fmt.Errorf("image must be at most %.1f MB", float64(uc.maxUploadSize)/(1<<20))
+	if file.Size > uc.maxUploadSize {
+		return nil, tooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, uc.maxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > uc.maxUploadSize {
+		return nil, tooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,336 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// loyaltyBatchSize caps how many orders or expired entries one job run handles
+const loyaltyBatchSize = 100
+
+// LoyaltyPolicy holds the rates of the loyalty points program
+type LoyaltyPolicy struct {
+	RupiahPerPoint float64 // amount paid to earn one point; zero disables earning
+	PointValue     float64 // discount in rupiah a redeemed point gives; zero disables redeeming
+	ExpiryMonths   int     // how long points stay valid; zero means they never expire
+}
+
+type loyaltyUseCase struct {
+	loyaltyRepo   repository.LoyaltyRepository
+	orderRepo     repository.OrderRepository
+	orderItemRepo repository.OrderItemRepository
+	paymentRepo   repository.PaymentRepository
+	productRepo   repository.ProductRepository
+	categoryRepo  repository.CategoryRepository
+	policy        LoyaltyPolicy
+}
+
+// NewLoyaltyUseCase creates a new LoyaltyUseCase instance
+func NewLoyaltyUseCase(
+	loyaltyRepo repository.LoyaltyRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	paymentRepo repository.PaymentRepository,
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+	policy LoyaltyPolicy,
+) usecase.LoyaltyUseCase {
+	return &loyaltyUseCase{
+		loyaltyRepo:   loyaltyRepo,
+		orderRepo:     orderRepo,
+		orderItemRepo: orderItemRepo,
+		paymentRepo:   paymentRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		policy:        policy,
+	}
+}
+
+// GetAccount gets the points balance of a user
+func (uc *loyaltyUseCase) GetAccount(ctx context.Context, userID uint) (*entity.LoyaltyAccount, error) {
+	return uc.loyaltyRepo.GetAccount(ctx, userID)
+}
+
+// GetLedger gets the points ledger of a user
+func (uc *loyaltyUseCase) GetLedger(ctx context.Context, userID uint, page, limit int) ([]*entity.LoyaltyTransaction, int64, error) {
+	offset := (page - 1) * limit
+	return uc.loyaltyRepo.GetTransactions(ctx, userID, offset, limit)
+}
+
+// RedeemForOrder spends points as a discount on a pending order before it is paid
+func (uc *loyaltyUseCase) RedeemForOrder(ctx context.Context, userID, orderID uint, points int) (*entity.Order, error) {
+	if uc.policy.PointValue <= 0 {
+		return nil, errors.New("loyalty points cannot be redeemed")
+	}
+	if points <= 0 {
+		return nil, errors.New("points must be greater than zero")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != entity.OrderStatusPending {
+		return nil, errors.New("order is " + string(order.Status))
+	}
+	if order.LoyaltyPointsRedeemed > 0 {
+		return nil, errors.New("points already redeemed on this order")
+	}
+	if order.OriginalOrderID != nil {
+		return nil, errors.New("points cannot be redeemed on replacement orders")
+	}
+	if _, err := uc.paymentRepo.GetByOrderID(ctx, order.ID); err == nil || order.WalletAmount > 0 {
+		return nil, errors.New("points must be redeemed before payment")
+	}
+
+	maxPoints := int(math.Floor(order.FinalAmount / uc.policy.PointValue))
+	if points > maxPoints {
+		return nil, fmt.Errorf("at most %d points can be redeemed on this order", maxPoints)
+	}
+
+	order.LoyaltyPointsRedeemed = points
+	order.DiscountAmount += roundPrice(float64(points) * uc.policy.PointValue)
+	order.FinalAmount = math.Max(order.TotalAmount+order.ShippingCost-order.DiscountAmount, 0)
+	err = uc.loyaltyRepo.Redeem(ctx, &entity.LoyaltyTransaction{
+		UserID:      userID,
+		OrderID:     &order.ID,
+		Type:        entity.LoyaltyTransactionRedeem,
+		Points:      -points,
+		Description: "Redeemed on order " + order.OrderNumber,
+	}, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ReleaseForOrder gives back the points spent on a cancelled order. They
+// keep the expiry they had, so cancelling does not extend them.
+func (uc *loyaltyUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	return uc.loyaltyRepo.Restore(ctx, orderID)
+}
+
+// ClawbackForRefund takes back the share of an order's earned points that
+// matches the share of the order refunded so far. Orders that have not earned
+// their points yet are settled when they do.
+func (uc *loyaltyUseCase) ClawbackForRefund(ctx context.Context, orderID uint, refundedAmount float64) error {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return uc.clawback(ctx, order, refundedAmount)
+}
+
+// AwardDeliveredOrders awards points for delivered orders that have not earned them yet (admin function)
+func (uc *loyaltyUseCase) AwardDeliveredOrders(ctx context.Context) (int, error) {
+	orders, err := uc.loyaltyRepo.GetDeliveredOrdersWithoutEarn(ctx, loyaltyBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	awarded := 0
+	for _, order := range orders {
+		ok, err := uc.awardOrder(ctx, order)
+		if err != nil {
+			return awarded, fmt.Errorf("award order %d: %w", order.ID, err)
+		}
+		if ok {
+			awarded++
+		}
+	}
+
+	return awarded, nil
+}
+
+// ExpirePoints expires points that were not spent in time (admin function)
+func (uc *loyaltyUseCase) ExpirePoints(ctx context.Context) (int, error) {
+	return uc.loyaltyRepo.ExpireDue(ctx, time.Now(), loyaltyBatchSize)
+}
+
+// ListCategoryMultipliers lists the category points multipliers (admin function)
+func (uc *loyaltyUseCase) ListCategoryMultipliers(ctx context.Context) ([]*entity.LoyaltyCategoryMultiplier, error) {
+	return uc.loyaltyRepo.ListMultipliers(ctx)
+}
+
+// SetCategoryMultiplier sets the points multiplier of a category and its subcategories (admin function)
+func (uc *loyaltyUseCase) SetCategoryMultiplier(ctx context.Context, categoryID uint, multiplier float64) (*entity.LoyaltyCategoryMultiplier, error) {
+	if multiplier < 0 {
+		return nil, errors.New("multiplier cannot be negative")
+	}
+	if _, err := uc.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	m := &entity.LoyaltyCategoryMultiplier{
+		CategoryID: categoryID,
+		Multiplier: multiplier,
+	}
+	if err := uc.loyaltyRepo.SetMultiplier(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DeleteCategoryMultiplier removes the points multiplier of a category (admin function)
+func (uc *loyaltyUseCase) DeleteCategoryMultiplier(ctx context.Context, categoryID uint) error {
+	return uc.loyaltyRepo.DeleteMultiplier(ctx, categoryID)
+}
+
+// awardOrder awards the points of a delivered order and settles any refund
+// made before it was delivered. Orders that earn nothing are still recorded
+// so they are not picked up again.
+func (uc *loyaltyUseCase) awardOrder(ctx context.Context, order *entity.Order) (bool, error) {
+	points, err := uc.earnedPoints(ctx, order)
+	if err != nil {
+		return false, err
+	}
+
+	applied, err := uc.loyaltyRepo.Credit(ctx, &entity.LoyaltyTransaction{
+		UserID:      order.UserID,
+		OrderID:     &order.ID,
+		Type:        entity.LoyaltyTransactionEarn,
+		Points:      points,
+		ExpiresAt:   uc.expiresAt(time.Now()),
+		Description: "Earned on order " + order.OrderNumber,
+	})
+	if err != nil || !applied {
+		return false, err
+	}
+
+	if payment, err := uc.paymentRepo.GetByOrderID(ctx, order.ID); err == nil && payment.RefundedTotal() > 0 {
+		if err := uc.clawback(ctx, order, payment.RefundedTotal()); err != nil {
+			return points > 0, err
+		}
+	}
+
+	return points > 0, nil
+}
+
+// earnedPoints computes the points an order earns: its final amount at the
+// earn rate, weighted by the multipliers of the items' categories
+func (uc *loyaltyUseCase) earnedPoints(ctx context.Context, order *entity.Order) (int, error) {
+	if uc.policy.RupiahPerPoint <= 0 || order.FinalAmount <= 0 {
+		return 0, nil
+	}
+
+	items, err := uc.orderItemRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return 0, err
+	}
+	// Gift card orders have no items and earn nothing; the points come when the card is spent
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	multipliers, err := uc.loyaltyRepo.ListMultipliers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	byCategory := make(map[uint]float64, len(multipliers))
+	for _, m := range multipliers {
+		byCategory[m.CategoryID] = m.Multiplier
+	}
+
+	weight := 1.0
+	total, weighted := 0.0, 0.0
+	for _, item := range items {
+		product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return 0, err
+		}
+		multiplier, err := uc.categoryMultiplier(ctx, product.CategoryID, byCategory)
+		if err != nil {
+			return 0, err
+		}
+		amount := item.FinalPrice * float64(item.Quantity)
+		total += amount
+		weighted += amount * multiplier
+	}
+	if total > 0 {
+		weight = weighted / total
+	}
+
+	return int(math.Floor(order.FinalAmount / uc.policy.RupiahPerPoint * weight)), nil
+}
+
+// categoryMultiplier finds the multiplier of a category, falling back to its
+// parents and then to 1
+func (uc *loyaltyUseCase) categoryMultiplier(ctx context.Context, categoryID uint, byCategory map[uint]float64) (float64, error) {
+	if len(byCategory) == 0 {
+		return 1, nil
+	}
+
+	id := &categoryID
+	for depth := 0; id != nil && depth < 10; depth++ {
+		if multiplier, ok := byCategory[*id]; ok {
+			return multiplier, nil
+		}
+		category, err := uc.categoryRepo.GetByID(ctx, *id)
+		if err != nil {
+			return 0, err
+		}
+		id = category.ParentID
+	}
+	return 1, nil
+}
+
+// clawback takes back earned points in proportion to the refunded amount,
+// minus what earlier refunds already took back. Points that were already
+// spent leave the balance negative until they are earned back.
+func (uc *loyaltyUseCase) clawback(ctx context.Context, order *entity.Order, refundedAmount float64) error {
+	transactions, err := uc.loyaltyRepo.GetOrderTransactions(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	earned, clawedBack := 0, 0
+	for _, transaction := range transactions {
+		switch transaction.Type {
+		case entity.LoyaltyTransactionEarn:
+			earned += transaction.Points
+		case entity.LoyaltyTransactionClawback:
+			clawedBack -= transaction.Points
+		}
+	}
+	if earned <= 0 || refundedAmount <= 0 {
+		return nil
+	}
+
+	share := 1.0
+	if order.FinalAmount > 0 {
+		share = math.Min(refundedAmount/order.FinalAmount, 1)
+	}
+	points := int(math.Round(float64(earned)*share)) - clawedBack
+	if points <= 0 {
+		return nil
+	}
+
+	_, err = uc.loyaltyRepo.Debit(ctx, &entity.LoyaltyTransaction{
+		UserID:      order.UserID,
+		OrderID:     &order.ID,
+		Type:        entity.LoyaltyTransactionClawback,
+		Points:      -points,
+		Description: "Refunded on order " + order.OrderNumber,
+	}, true)
+	return err
+}
+
+// expiresAt returns when points credited at the given time expire
+func (uc *loyaltyUseCase) expiresAt(at time.Time) *time.Time {
+	if uc.policy.ExpiryMonths <= 0 {
+		return nil
+	}
+	expiresAt := at.AddDate(0, uc.policy.ExpiryMonths, 0)
+	return &expiresAt
+}
@@ -0,0 +1,93 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+)
+
+func newTestLoyaltyUseCase(loyalty *fakeLoyaltyRepo) (*loyaltyUseCase, *fakeOrderRepo) {
+	orders := newFakeOrderRepo(&entity.Order{
+		ID: 1, UserID: 7, OrderNumber: "ORD-1", Status: entity.OrderStatusPending,
+		TotalAmount: 200000, ShippingCost: 15000, FinalAmount: 215000,
+	})
+	uc := &loyaltyUseCase{
+		loyaltyRepo: loyalty,
+		orderRepo:   orders,
+		paymentRepo: newFakePaymentRepo(),
+		policy:      LoyaltyPolicy{PointValue: 100},
+	}
+	return uc, orders
+}
+
+func TestRedeemPointsUpdatesOrderWithTheDebit(t *testing.T) {
+	loyalty := &fakeLoyaltyRepo{}
+	uc, orders := newTestLoyaltyUseCase(loyalty)
+
+	order, err := uc.RedeemForOrder(context.Background(), 7, 1, 500)
+	if err != nil {
+		t.Fatalf("RedeemForOrder() error = %v", err)
+	}
+
+	if order.LoyaltyPointsRedeemed != 500 || order.DiscountAmount != 50000 || order.FinalAmount != 165000 {
+		t.Errorf("order redeemed %d, discount %.0f, final %.0f, want 500, 50000, 165000", order.LoyaltyPointsRedeemed, order.DiscountAmount, order.FinalAmount)
+	}
+	if len(loyalty.redeemed) != 1 || loyalty.redeemed[0].Points != -500 || loyalty.orders[0].FinalAmount != 165000 {
+		t.Errorf("redeemed = %+v with orders %+v, want -500 points with the discounted order", loyalty.redeemed, loyalty.orders)
+	}
+	// The order is updated by Redeem, in the same transaction as the debit
+	if stored := orders.orders[1]; stored.LoyaltyPointsRedeemed != 0 {
+		t.Errorf("order was updated outside Redeem: %d points", stored.LoyaltyPointsRedeemed)
+	}
+}
+
+func TestRedeemPointsFailureLeavesNothingToRestore(t *testing.T) {
+	loyalty := &fakeLoyaltyRepo{err: repository.ErrInsufficientPoints}
+	uc, _ := newTestLoyaltyUseCase(loyalty)
+
+	if _, err := uc.RedeemForOrder(context.Background(), 7, 1, 500); !errors.Is(err, repository.ErrInsufficientPoints) {
+		t.Fatalf("RedeemForOrder() error = %v, want ErrInsufficientPoints", err)
+	}
+	if len(loyalty.restored) != 0 {
+		t.Errorf("restored points of orders %v, want none", loyalty.restored)
+	}
+}
+
+func TestRedeemPointsAboveOrderValueIsRejected(t *testing.T) {
+	loyalty := &fakeLoyaltyRepo{}
+	uc, _ := newTestLoyaltyUseCase(loyalty)
+
+	if _, err := uc.RedeemForOrder(context.Background(), 7, 1, 2151); err == nil {
+		t.Fatal("RedeemForOrder() succeeded for more than the order is worth")
+	}
+	if len(loyalty.redeemed) != 0 {
+		t.Errorf("redeemed = %+v, want nothing", loyalty.redeemed)
+	}
+}
+
+func TestAwardOrderClawsBackRefundsPaidIntoTheWallet(t *testing.T) {
+	loyalty := &fakeLoyaltyRepo{}
+	uc, orders := newTestLoyaltyUseCase(loyalty)
+	uc.policy.RupiahPerPoint = 1000
+	uc.orderItemRepo = &fakeOrderItemRepo{items: []*entity.OrderItem{{OrderID: 1, ProductID: 3, Quantity: 1, FinalPrice: 200000}}}
+	uc.productRepo = newFakeProductRepo(&entity.Product{ID: 3, CategoryID: 2})
+	uc.paymentRepo = newFakePaymentRepo(&entity.Payment{
+		ID: 1, OrderID: 1, Amount: 215000, WalletRefunded: 107500, Status: entity.PaymentStatusPartiallyRefunded,
+	})
+
+	if _, err := uc.awardOrder(context.Background(), orders.orders[1]); err != nil {
+		t.Fatalf("awardOrder() error = %v", err)
+	}
+
+	balance := 0
+	for _, transaction := range loyalty.transactions {
+		balance += transaction.Points
+	}
+	// 215 points earned, half of them taken back for the half refunded into the wallet
+	if balance != 107 {
+		t.Errorf("points left = %d, want 107", balance)
+	}
+}
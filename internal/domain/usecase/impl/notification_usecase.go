@@ -0,0 +1,88 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type notificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationUseCase creates a new NotificationUseCase instance
+func NewNotificationUseCase(notificationRepo repository.NotificationRepository) usecase.NotificationUseCase {
+	return &notificationUseCase{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// CreateNotification sends a notification to a user
+func (uc *notificationUseCase) CreateNotification(ctx context.Context, userID uint, notificationType entity.NotificationType, title, message string, data map[string]interface{}) error {
+	// The data column holds JSON, so a notification without data stores null
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return uc.notificationRepo.Create(ctx, &entity.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data:    string(encoded),
+	})
+}
+
+// GetNotifications gets a user's notifications with pagination
+func (uc *notificationUseCase) GetNotifications(ctx context.Context, userID uint, page, limit int) ([]*entity.Notification, int64, error) {
+	offset := (page - 1) * limit
+	return uc.notificationRepo.GetByUserID(ctx, userID, offset, limit)
+}
+
+// GetNotificationsByCursor gets a user's notifications, one page after or before a cursor
+func (uc *notificationUseCase) GetNotificationsByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Notification, *entity.PageCursors, error) {
+	return uc.notificationRepo.GetByUserIDByCursor(ctx, userID, page)
+}
+
+// GetUnreadNotifications gets a user's unread notifications
+func (uc *notificationUseCase) GetUnreadNotifications(ctx context.Context, userID uint) ([]*entity.Notification, error) {
+	return uc.notificationRepo.GetUnreadByUserID(ctx, userID)
+}
+
+// MarkAsRead marks one of a user's notifications as read
+func (uc *notificationUseCase) MarkAsRead(ctx context.Context, id, userID uint) error {
+	if _, err := uc.getOwned(ctx, id, userID); err != nil {
+		return err
+	}
+	return uc.notificationRepo.MarkAsRead(ctx, id)
+}
+
+// MarkAllAsRead marks all of a user's notifications as read
+func (uc *notificationUseCase) MarkAllAsRead(ctx context.Context, userID uint) error {
+	return uc.notificationRepo.MarkAllAsRead(ctx, userID)
+}
+
+// DeleteNotification deletes one of a user's notifications
+func (uc *notificationUseCase) DeleteNotification(ctx context.Context, id, userID uint) error {
+	if _, err := uc.getOwned(ctx, id, userID); err != nil {
+		return err
+	}
+	return uc.notificationRepo.Delete(ctx, id)
+}
+
+// getOwned gets a notification, hiding those of other users
+func (uc *notificationUseCase) getOwned(ctx context.Context, id, userID uint) (*entity.Notification, error) {
+	notification, err := uc.notificationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if notification.UserID != userID {
+		return nil, errors.New("notification not found")
+	}
+	return notification, nil
+}
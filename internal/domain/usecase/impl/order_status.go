@@ -0,0 +1,70 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// orderTransitions lists, for each target status, the statuses an order may
+// move from. Orders are fulfilled pending → processing → shipped → delivered,
+// are cancelled before they ship, and can only be refunded once paid: the move
+// to refunded follows the refund of the order's payment, so a cancelled order
+// is only refunded when it was paid after all.
+var orderTransitions = map[entity.OrderStatus][]entity.OrderStatus{
+	entity.OrderStatusProcessing: {entity.OrderStatusPending},
+	entity.OrderStatusShipped:    {entity.OrderStatusProcessing},
+	entity.OrderStatusDelivered:  {entity.OrderStatusShipped},
+	entity.OrderStatusCancelled:  {entity.OrderStatusPending, entity.OrderStatusProcessing},
+	entity.OrderStatusRefunded:   {entity.OrderStatusProcessing, entity.OrderStatusShipped, entity.OrderStatusDelivered, entity.OrderStatusCancelled},
+}
+
+// canTransitionOrder reports whether an order may move from one status to another
+func canTransitionOrder(from, to entity.OrderStatus) bool {
+	return slices.Contains(orderTransitions[to], from)
+}
+
+// orderActor identifies who changes the status of an order
+type orderActor struct {
+	Type entity.OrderStatusActor
+	ID   *uint
+}
+
+// systemActor changes order statuses on behalf of payment notifications and background jobs
+var systemActor = orderActor{Type: entity.OrderStatusActorSystem}
+
+// customerActor returns the actor for a change made by a customer
+func customerActor(userID uint) orderActor {
+	return orderActor{Type: entity.OrderStatusActorCustomer, ID: &userID}
+}
+
+// transitionOrder moves an order to a new status when the state machine allows
+// it and records who made the change. Every status change of an existing
+// order goes through it; order updates leave the status alone.
+func transitionOrder(ctx context.Context, historyRepo repository.OrderStatusHistoryRepository, order *entity.Order, to entity.OrderStatus, actor orderActor, note string) error {
+	if !canTransitionOrder(order.Status, to) {
+		return &usecase.InvalidOrderTransitionError{From: order.Status, To: to}
+	}
+
+	applied, err := historyRepo.Transition(ctx, &entity.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   to,
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		Note:       note,
+	})
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("order was updated concurrently")
+	}
+
+	order.Status = to
+	return nil
+}
@@ -0,0 +1,212 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+)
+
+func TestCanTransitionOrderCancelsOnlyBeforeShipping(t *testing.T) {
+	tests := []struct {
+		from entity.OrderStatus
+		want bool
+	}{
+		{entity.OrderStatusPending, true},
+		{entity.OrderStatusProcessing, true},
+		{entity.OrderStatusShipped, false},
+		{entity.OrderStatusDelivered, false},
+	}
+	for _, tt := range tests {
+		if got := canTransitionOrder(tt.from, entity.OrderStatusCancelled); got != tt.want {
+			t.Errorf("canTransitionOrder(%s, cancelled) = %v, want %v", tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionOrderRecordsHistory(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, Status: entity.OrderStatusPending})
+	history := &fakeOrderStatusRepo{orders: orders}
+	order, _ := orders.GetByID(context.Background(), 1)
+
+	if err := transitionOrder(context.Background(), history, order, entity.OrderStatusProcessing, systemActor, "paid"); err != nil {
+		t.Fatalf("transitionOrder() error = %v", err)
+	}
+	if order.Status != entity.OrderStatusProcessing || orders.orders[1].Status != entity.OrderStatusProcessing {
+		t.Errorf("order status = %s, stored %s, want processing", order.Status, orders.orders[1].Status)
+	}
+	if len(history.history) != 1 || history.history[0].FromStatus != entity.OrderStatusPending || history.history[0].Note != "paid" {
+		t.Errorf("history = %+v, want one pending -> processing change", history.history)
+	}
+}
+
+func TestTransitionOrderRejectsDisallowedMove(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, Status: entity.OrderStatusPending})
+	history := &fakeOrderStatusRepo{orders: orders}
+	order, _ := orders.GetByID(context.Background(), 1)
+
+	err := transitionOrder(context.Background(), history, order, entity.OrderStatusDelivered, systemActor, "")
+	var invalid *usecase.InvalidOrderTransitionError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("transitionOrder() error = %v, want InvalidOrderTransitionError", err)
+	}
+	if orders.orders[1].Status != entity.OrderStatusPending || len(history.history) != 0 {
+		t.Errorf("order moved to %s with %d history rows, want it left pending", orders.orders[1].Status, len(history.history))
+	}
+}
+
+func TestPaidNotificationForCancelledOrderLeavesOrderCancelled(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _, events := newTestPaymentUseCase(gw)
+	uc.orderRepo.(*fakeOrderRepo).orders[1].Status = entity.OrderStatusCancelled
+	payments.payments[1].Status = entity.PaymentStatusPending
+	payments.payments[1].GatewayOrderID = "ORD-1"
+	gw.transaction = &gateway.Transaction{OrderID: "ORD-1", Status: entity.PaymentStatusPaid, Amount: 100000}
+
+	if err := uc.HandlePaymentCallback(context.Background(), "stub", nil, []byte(`{"order_id":"ORD-1"}`)); err != nil {
+		t.Fatalf("HandlePaymentCallback() error = %v", err)
+	}
+
+	event := events.events[len(events.events)-1]
+	if event.Status != entity.PaymentEventStatusProcessed || !strings.Contains(event.Message, "cancelled") {
+		t.Errorf("event = %s %q, want processed and reporting the cancelled order", event.Status, event.Message)
+	}
+	if payments.payments[1].Status != entity.PaymentStatusPaid {
+		t.Errorf("payment status = %s, want paid", payments.payments[1].Status)
+	}
+	if status := uc.orderRepo.(*fakeOrderRepo).orders[1].Status; status != entity.OrderStatusCancelled {
+		t.Errorf("order status = %s, want cancelled", status)
+	}
+}
+
+func TestGetOrderByIDIncludesStatusHistory(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, UserID: 7, Status: entity.OrderStatusPending})
+	history := &fakeOrderStatusRepo{orders: orders}
+	uc := &orderUseCase{orderRepo: orders, orderStatusRepo: history, voucherUseCase: &voucherUseCase{voucherRepo: &fakeVoucherRepo{}}, flashSaleUseCase: &fakeFlashSaleUseCase{}, loyaltyUseCase: &fakeLoyaltyUseCase{}, walletUseCase: &fakeWalletUseCase{}}
+	ctx := context.Background()
+
+	if err := uc.CancelOrder(ctx, 1, 7); err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+
+	order, err := uc.GetOrderByID(ctx, 1, 7)
+	if err != nil {
+		t.Fatalf("GetOrderByID() error = %v", err)
+	}
+	if len(order.StatusHistory) != 1 || order.StatusHistory[0].ToStatus != entity.OrderStatusCancelled {
+		t.Errorf("status history = %+v, want the cancellation", order.StatusHistory)
+	}
+}
+
+// newRefundTest creates an order use case that refunds through a payment use
+// case holding a processing order paid with 100000 and 20000 of store credit
+func newRefundTest(gw *stubGateway) (*orderUseCase, *paymentUseCase, *fakePaymentRepo) {
+	payments, paymentRepo, _, _ := newTestPaymentUseCase(gw)
+	orders := payments.orderRepo.(*fakeOrderRepo)
+	paymentID := uint(1)
+	orders.orders[1].PaymentID = &paymentID
+	uc := &orderUseCase{orderRepo: orders, orderStatusRepo: payments.orderStatusRepo, paymentUseCase: payments}
+	return uc, payments, paymentRepo
+}
+
+func TestUpdateOrderStatusRefundsThroughPayment(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, paymentRepo := newRefundTest(gw)
+
+	if err := uc.UpdateOrderStatus(context.Background(), 1, entity.OrderStatusRefunded, 2, "damaged in transit"); err != nil {
+		t.Fatalf("UpdateOrderStatus() error = %v", err)
+	}
+
+	if payment := paymentRepo.payments[1]; payment.Status != entity.PaymentStatusRefunded || payment.RefundedTotal() != 120000 {
+		t.Errorf("payment is %s with %.0f refunded, want refunded 120000", payment.Status, payment.RefundedTotal())
+	}
+	if len(gw.refunds) != 1 || gw.refunds[0].Amount != 100000 {
+		t.Errorf("gateway refunds = %+v, want one of 100000", gw.refunds)
+	}
+	if clawedBack := payments.loyaltyUseCase.(*fakeLoyaltyUseCase).clawedBack; clawedBack != 120000 {
+		t.Errorf("loyalty points clawed back for %.0f, want 120000", clawedBack)
+	}
+	if status := uc.orderRepo.(*fakeOrderRepo).orders[1].Status; status != entity.OrderStatusRefunded {
+		t.Errorf("order status = %s, want refunded", status)
+	}
+}
+
+func TestUpdateOrderStatusRefusesRefundWithoutPaidPayment(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, _, paymentRepo := newRefundTest(gw)
+	// The order was cancelled before its payment went through
+	uc.orderRepo.(*fakeOrderRepo).orders[1].Status = entity.OrderStatusCancelled
+	paymentRepo.payments[1].Status = entity.PaymentStatusExpired
+
+	err := uc.UpdateOrderStatus(context.Background(), 1, entity.OrderStatusRefunded, 2, "")
+	if err == nil || !strings.Contains(err.Error(), "no paid payment") {
+		t.Fatalf("UpdateOrderStatus() error = %v, want no paid payment to refund", err)
+	}
+	if status := uc.orderRepo.(*fakeOrderRepo).orders[1].Status; status != entity.OrderStatusCancelled {
+		t.Errorf("order status = %s, want cancelled", status)
+	}
+}
+
+func TestUpdateOrderStatusRefundsCancelledPaidOrderOnce(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, paymentRepo := newRefundTest(gw)
+	orderID := uint(1)
+	wallet := payments.walletRepo.(*fakeWalletRepo)
+	wallet.transactions = []*entity.WalletTransaction{
+		{UserID: 7, Type: entity.WalletTransactionPayment, Amount: -20000, OrderID: &orderID, Reference: "order-1-payment"},
+	}
+	uc.voucherUseCase = &voucherUseCase{voucherRepo: &fakeVoucherRepo{}}
+	uc.flashSaleUseCase = &fakeFlashSaleUseCase{}
+	uc.loyaltyUseCase = &fakeLoyaltyUseCase{}
+	uc.walletUseCase = &walletUseCase{walletRepo: wallet, orderRepo: uc.orderRepo, paymentRepo: paymentRepo}
+	refunds := payments.paymentRefundRepo.(*fakePaymentRefundRepo)
+
+	if err := uc.UpdateOrderStatus(context.Background(), 1, entity.OrderStatusCancelled, 2, "out of stock"); err != nil {
+		t.Fatalf("UpdateOrderStatus() error = %v", err)
+	}
+
+	// The store credit goes back once, with the refund, and not as a release
+	if len(wallet.transactions) != 1 {
+		t.Errorf("wallet entries = %+v, want no release of the paid order's store credit", wallet.transactions[1:])
+	}
+	if len(refunds.credits) != 1 || refunds.credits[0].Amount != 20000 {
+		t.Errorf("store credits refunded = %+v, want one of 20000", refunds.credits)
+	}
+	if len(gw.refunds) != 1 || gw.refunds[0].Amount != 100000 {
+		t.Errorf("gateway refunds = %+v, want one of 100000", gw.refunds)
+	}
+	if status := uc.orderRepo.(*fakeOrderRepo).orders[1].Status; status != entity.OrderStatusRefunded {
+		t.Errorf("order status = %s, want refunded", status)
+	}
+}
+
+func TestRefundOrderLeavesReleasedStoreCredit(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	_, payments, paymentRepo := newRefundTest(gw)
+	// The order was cancelled and its store credit released before the
+	// payment went through after all
+	payments.orderRepo.(*fakeOrderRepo).orders[1].Status = entity.OrderStatusCancelled
+	orderID := uint(1)
+	payments.walletRepo.(*fakeWalletRepo).transactions = []*entity.WalletTransaction{
+		{UserID: 7, Type: entity.WalletTransactionPayment, Amount: -20000, OrderID: &orderID, Reference: "order-1-payment"},
+		{UserID: 7, Type: entity.WalletTransactionRelease, Amount: 20000, OrderID: &orderID, Reference: "order-1-release"},
+	}
+
+	if err := payments.RefundOrder(context.Background(), 1, "paid after cancellation"); err != nil {
+		t.Fatalf("RefundOrder() error = %v", err)
+	}
+
+	if len(gw.refunds) != 1 || gw.refunds[0].Amount != 100000 {
+		t.Errorf("gateway refunds = %+v, want one of 100000", gw.refunds)
+	}
+	if credits := payments.paymentRefundRepo.(*fakePaymentRefundRepo).credits; len(credits) != 0 {
+		t.Errorf("store credits refunded = %+v, want none", credits)
+	}
+	if payment := paymentRepo.payments[1]; payment.RefundedTotal() != 100000 {
+		t.Errorf("refunded %.0f, want 100000", payment.RefundedTotal())
+	}
+}
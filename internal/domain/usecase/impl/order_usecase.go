@@ -0,0 +1,445 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+
+	"github.com/google/uuid"
+)
+
+type orderUseCase struct {
+	orderRepo        repository.OrderRepository
+	orderStatusRepo  repository.OrderStatusHistoryRepository
+	cartRepo         repository.CartRepository
+	productRepo      repository.ProductRepository
+	variantRepo      repository.ProductVariantRepository
+	addressRepo      repository.AddressRepository
+	voucherUseCase   usecase.VoucherUseCase
+	flashSaleUseCase usecase.FlashSaleUseCase
+	promotionUseCase usecase.PromotionUseCase
+	loyaltyUseCase   usecase.LoyaltyUseCase
+	walletUseCase    usecase.WalletUseCase
+	paymentUseCase   usecase.PaymentUseCase
+	shippingCost     float64
+}
+
+// NewOrderUseCase creates a new OrderUseCase instance. shippingCost is the
+// flat rate charged for shipping each order.
+func NewOrderUseCase(
+	orderRepo repository.OrderRepository,
+	orderStatusRepo repository.OrderStatusHistoryRepository,
+	cartRepo repository.CartRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	addressRepo repository.AddressRepository,
+	voucherUseCase usecase.VoucherUseCase,
+	flashSaleUseCase usecase.FlashSaleUseCase,
+	promotionUseCase usecase.PromotionUseCase,
+	loyaltyUseCase usecase.LoyaltyUseCase,
+	walletUseCase usecase.WalletUseCase,
+	paymentUseCase usecase.PaymentUseCase,
+	shippingCost float64,
+) usecase.OrderUseCase {
+	return &orderUseCase{
+		orderRepo:        orderRepo,
+		orderStatusRepo:  orderStatusRepo,
+		cartRepo:         cartRepo,
+		productRepo:      productRepo,
+		variantRepo:      variantRepo,
+		addressRepo:      addressRepo,
+		voucherUseCase:   voucherUseCase,
+		flashSaleUseCase: flashSaleUseCase,
+		promotionUseCase: promotionUseCase,
+		loyaltyUseCase:   loyaltyUseCase,
+		walletUseCase:    walletUseCase,
+		paymentUseCase:   paymentUseCase,
+		shippingCost:     shippingCost,
+	}
+}
+
+// CreateOrder places an order for everything in the user's cart, shipped to
+// one of the user's addresses, with the running promotions and the voucher
+// kept on the cart. Items on flash sale are charged the sale price. Part or
+// all of the order can be paid with store credit; the payment method pays
+// the rest. The stock of the items, the voucher use, the flash sale units and
+// the store credit are taken and the cart emptied in the same transaction as
+// the order is created.
+func (uc *orderUseCase) CreateOrder(ctx context.Context, userID uint, addressID uint, paymentMethod entity.PaymentMethod, shippingMethod string, notes string, walletAmount float64) (*entity.Order, error) {
+	if walletAmount < 0 {
+		return nil, errors.New("store credit cannot be negative")
+	}
+
+	address, err := uc.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if address.UserID != userID {
+		return nil, errors.New("address not found")
+	}
+
+	cart, err := uc.cartRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	order := &entity.Order{
+		UserID:          userID,
+		OrderNumber:     newOrderNumber(time.Now()),
+		Status:          entity.OrderStatusPending,
+		ShippingCost:    uc.shippingCost,
+		ShippingAddress: address.ShippingAddress(),
+		ShippingMethod:  strings.TrimSpace(shippingMethod),
+		Notes:           strings.TrimSpace(notes),
+	}
+	placement := &repository.OrderPlacement{
+		CartID:   cart.ID,
+		Creation: &entity.OrderStatusHistory{ActorType: entity.OrderStatusActorCustomer, ActorID: &userID, Note: "Order placed"},
+	}
+
+	if err := uc.priceOrder(ctx, order, cart, placement); err != nil {
+		uc.cancelReservations(ctx, placement)
+		return nil, err
+	}
+	if err := splitTender(order, placement, paymentMethod, walletAmount); err != nil {
+		uc.cancelReservations(ctx, placement)
+		return nil, err
+	}
+	if err := uc.orderRepo.Place(ctx, order, placement); err != nil {
+		uc.cancelReservations(ctx, placement)
+		return nil, err
+	}
+
+	if placement.Payment != nil {
+		order.PaymentID = &placement.Payment.Payment.ID
+		order.Status = placement.Payment.Paid.ToStatus
+	}
+	return order, nil
+}
+
+// splitTender spends walletAmount of store credit on a priced order; with the
+// wallet payment method the whole order is paid with store credit. An order
+// the credit covers in full is paid as it is placed, and the gateway charges
+// whatever is left of the others.
+func splitTender(order *entity.Order, placement *repository.OrderPlacement, paymentMethod entity.PaymentMethod, walletAmount float64) error {
+	if paymentMethod == entity.PaymentMethodWallet {
+		walletAmount = order.FinalAmount
+	}
+	walletAmount = roundPrice(walletAmount)
+	if walletAmount <= 0 {
+		return nil
+	}
+	if walletAmount > order.FinalAmount+0.01 {
+		return fmt.Errorf("at most %.2f of store credit can be applied to this order", order.FinalAmount)
+	}
+
+	order.WalletAmount = math.Min(walletAmount, order.FinalAmount)
+	placement.Wallet = &entity.WalletTransaction{
+		UserID:      order.UserID,
+		Type:        entity.WalletTransactionPayment,
+		Amount:      -order.WalletAmount,
+		Description: "Paid for order " + order.OrderNumber,
+	}
+	if order.FinalAmount-order.WalletAmount <= 0.01 {
+		placement.Payment = newWalletPayment(order, "Paid with store credit")
+	}
+	return nil
+}
+
+// priceOrder adds the cart items to an order at what they sell for now,
+// reserving flash sale quota for the items on sale, then applies the running
+// promotions, which are stored with the order, and the cart's voucher. What
+// the order takes is added to the placement.
+func (uc *orderUseCase) priceOrder(ctx context.Context, order *entity.Order, cart *entity.Cart, placement *repository.OrderPlacement) error {
+	for _, cartItem := range cart.Items {
+		item, err := uc.newOrderItem(ctx, &cartItem)
+		if err != nil {
+			return err
+		}
+
+		sale, err := uc.flashSaleUseCase.Reserve(ctx, order.UserID, item.VariantID, item.Quantity)
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.ProductName, err)
+		}
+		if sale != nil {
+			placement.FlashSales = append(placement.FlashSales, &entity.FlashSalePurchase{
+				FlashSaleItemID: sale.ID,
+				UserID:          order.UserID,
+				Quantity:        item.Quantity,
+			})
+			item.Price = sale.SalePrice
+			item.FinalPrice = sale.SalePrice
+		}
+
+		order.OrderItems = append(order.OrderItems, *item)
+	}
+
+	items := make([]*entity.OrderItem, 0, len(order.OrderItems))
+	for i := range order.OrderItems {
+		items = append(items, &order.OrderItems[i])
+	}
+	promotions, err := uc.promotionUseCase.ApplyToOrderItems(ctx, items)
+	if err != nil {
+		return err
+	}
+	order.Promotions = promotions
+
+	for _, item := range order.OrderItems {
+		order.TotalAmount += item.FinalPrice * float64(item.Quantity)
+	}
+	order.TotalAmount = roundPrice(order.TotalAmount)
+
+	if cart.VoucherCode != "" {
+		redemption, quote, err := uc.voucherUseCase.QuoteForCheckout(ctx, order.UserID, cart.VoucherCode, order.OrderItems, order.ShippingCost)
+		if err != nil {
+			return fmt.Errorf("voucher %s: %w", cart.VoucherCode, err)
+		}
+		placement.Voucher = redemption
+		order.VoucherCode = quote.Code
+		order.DiscountAmount += quote.Discount + quote.ShippingDiscount
+	}
+
+	order.FinalAmount = roundPrice(math.Max(order.TotalAmount-order.DiscountAmount+order.ShippingCost, 0))
+	return nil
+}
+
+// cancelReservations gives back the flash sale quota reserved for an order
+// that was not placed. A failed release keeps the units off sale only until
+// the live counters are reloaded from the recorded purchases.
+func (uc *orderUseCase) cancelReservations(ctx context.Context, placement *repository.OrderPlacement) {
+	for _, purchase := range placement.FlashSales {
+		_ = uc.flashSaleUseCase.CancelReservation(ctx, purchase)
+	}
+}
+
+// newOrderItem prices a cart item at what its product sells for now
+func (uc *orderUseCase) newOrderItem(ctx context.Context, cartItem *entity.CartItem) (*entity.OrderItem, error) {
+	product, err := uc.productRepo.GetByID(ctx, cartItem.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsActive {
+		return nil, fmt.Errorf("%s is no longer available", product.Name)
+	}
+	variant, err := uc.variantRepo.GetByID(ctx, cartItem.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != product.ID || !variant.IsActive {
+		return nil, fmt.Errorf("the selected variant of %s is no longer available", product.Name)
+	}
+
+	price := roundPrice(product.SellingPrice())
+	return &entity.OrderItem{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		VariantID:   variant.ID,
+		VariantInfo: variantInfo(variant),
+		Quantity:    cartItem.Quantity,
+		Price:       price,
+		FinalPrice:  price,
+	}, nil
+}
+
+// GetOrderByID gets one of the user's orders by ID with its status history
+func (uc *orderUseCase) GetOrderByID(ctx context.Context, id uint, userID uint) (*entity.Order, error) {
+	order, err := uc.getUserOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return order, uc.loadStatusHistory(ctx, order)
+}
+
+// GetOrderByNumber gets one of the user's orders by order number with its status history
+func (uc *orderUseCase) GetOrderByNumber(ctx context.Context, orderNumber string, userID uint) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetByOrderNumber(ctx, orderNumber)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	return order, uc.loadStatusHistory(ctx, order)
+}
+
+// getUserOrder gets one of the user's orders by ID
+func (uc *orderUseCase) getUserOrder(ctx context.Context, id uint, userID uint) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	return order, nil
+}
+
+// loadStatusHistory sets the status changes of an order, oldest first
+func (uc *orderUseCase) loadStatusHistory(ctx context.Context, order *entity.Order) error {
+	history, err := uc.orderStatusRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	order.StatusHistory = make([]entity.OrderStatusHistory, 0, len(history))
+	for _, change := range history {
+		order.StatusHistory = append(order.StatusHistory, *change)
+	}
+	return nil
+}
+
+// GetUserOrders gets the user's orders with pagination
+func (uc *orderUseCase) GetUserOrders(ctx context.Context, userID uint, page, limit int) ([]*entity.Order, int64, error) {
+	offset := (page - 1) * limit
+	return uc.orderRepo.GetByUserID(ctx, userID, offset, limit)
+}
+
+// GetUserOrdersByCursor gets the user's orders, one page after or before a cursor
+func (uc *orderUseCase) GetUserOrdersByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error) {
+	return uc.orderRepo.GetByUserIDByCursor(ctx, userID, page)
+}
+
+// CancelOrder cancels one of the user's orders that has not been paid yet
+// and releases what it took
+func (uc *orderUseCase) CancelOrder(ctx context.Context, id uint, userID uint) error {
+	order, err := uc.getUserOrder(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	// Customers only cancel orders they have not paid for
+	if order.Status != entity.OrderStatusPending {
+		return &usecase.InvalidOrderTransitionError{From: order.Status, To: entity.OrderStatusCancelled}
+	}
+
+	if err := transitionOrder(ctx, uc.orderStatusRepo, order, entity.OrderStatusCancelled, customerActor(userID), "Cancelled by customer"); err != nil {
+		return err
+	}
+	return uc.releaseOrder(ctx, order.ID)
+}
+
+// releaseOrder gives back the voucher use, flash sale units, loyalty points
+// and store credit taken by a cancelled order, whose stock was returned with
+// the status change, and marks the order released
+func (uc *orderUseCase) releaseOrder(ctx context.Context, orderID uint) error {
+	if err := uc.voucherUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.flashSaleUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.loyaltyUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.walletUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	return uc.orderRepo.MarkHoldsReleased(ctx, orderID)
+}
+
+// GetAllOrders lists orders with filter and pagination (admin function)
+func (uc *orderUseCase) GetAllOrders(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Order, int64, error) {
+	offset := (page - 1) * limit
+	return uc.orderRepo.List(ctx, filter, offset, limit)
+}
+
+// GetAllOrdersByCursor lists orders with filter, one page after or before a cursor (admin function)
+func (uc *orderUseCase) GetAllOrdersByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error) {
+	return uc.orderRepo.ListByCursor(ctx, filter, page)
+}
+
+// UpdateOrderStatus moves an order to a new status when the state machine
+// allows it, releasing what a cancelled order took. An order is refunded by
+// refunding its payment, which gives back the money and the loyalty points
+// earned with it before the order moves to refunded; a paid order that is
+// cancelled is refunded the same way, store credit included (admin function).
+func (uc *orderUseCase) UpdateOrderStatus(ctx context.Context, id uint, status entity.OrderStatus, adminID uint, note string) error {
+	order, err := uc.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if status == entity.OrderStatusRefunded {
+		if !canTransitionOrder(order.Status, status) {
+			return &usecase.InvalidOrderTransitionError{From: order.Status, To: status}
+		}
+		return uc.paymentUseCase.RefundOrder(ctx, order.ID, refundReason(note))
+	}
+
+	paid, err := uc.isPaid(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	actor := orderActor{Type: entity.OrderStatusActorAdmin, ID: &adminID}
+	if err := transitionOrder(ctx, uc.orderStatusRepo, order, status, actor, note); err != nil {
+		return err
+	}
+	if status != entity.OrderStatusCancelled {
+		return nil
+	}
+	if err := uc.releaseOrder(ctx, order.ID); err != nil {
+		return err
+	}
+	if !paid {
+		return nil
+	}
+	if err := uc.paymentUseCase.RefundOrder(ctx, order.ID, refundReason(note)); err != nil {
+		return fmt.Errorf("order %s cancelled but not refunded, refund it to retry: %w", order.OrderNumber, err)
+	}
+	return nil
+}
+
+// isPaid reports whether the payment of an order went through and was not
+// refunded in full
+func (uc *orderUseCase) isPaid(ctx context.Context, order *entity.Order) (bool, error) {
+	if order.PaymentID == nil {
+		return false, nil
+	}
+	payment, err := uc.paymentUseCase.GetPaymentByID(ctx, *order.PaymentID)
+	if err != nil {
+		return false, err
+	}
+	return payment.Status == entity.PaymentStatusPaid || payment.Status == entity.PaymentStatusPartiallyRefunded, nil
+}
+
+// refundReason returns the reason an admin gave for refunding an order
+func refundReason(note string) string {
+	if note == "" {
+		return "Order refunded"
+	}
+	return note
+}
+
+// UpdateShippingInfo sets the courier tracking number of an order (admin function)
+func (uc *orderUseCase) UpdateShippingInfo(ctx context.Context, id uint, trackingNumber string) error {
+	order, err := uc.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	order.ShippingTrackingNumber = strings.TrimSpace(trackingNumber)
+	return uc.orderRepo.Update(ctx, order)
+}
+
+// GetSalesReport gets the orders placed within a period that were not
+// cancelled or refunded, and their revenue (admin function)
+func (uc *orderUseCase) GetSalesReport(ctx context.Context, startDate, endDate time.Time) ([]*entity.Order, float64, error) {
+	if !endDate.After(startDate) {
+		return nil, 0, errors.New("end date must be after start date")
+	}
+	return uc.orderRepo.GetSalesReport(ctx, startDate, endDate)
+}
+
+// newOrderNumber generates an order number such as ORD-20261019-4F1A9C2E
+func newOrderNumber(now time.Time) string {
+	return fmt.Sprintf("ORD-%s-%s", now.Format("20060102"), strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:8]))
+}
@@ -0,0 +1,292 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// checkoutTest is a cart of two 100000 items with the voucher HEMAT, worth 20000, applied
+type checkoutTest struct {
+	uc         *orderUseCase
+	orders     *fakeOrderRepo
+	vouchers   *fakeVoucherRepo
+	flashSales *fakeFlashSaleUseCase
+	promotions *fakePromotionRepo
+	loyalty    *fakeLoyaltyUseCase
+	wallet     *fakeWalletUseCase
+	cart       *entity.Cart
+}
+
+func newCheckoutTest() *checkoutTest {
+	products := newFakeProductRepo(&entity.Product{
+		ID: 1, Name: "Linen Shirt", CategoryID: 3, Price: 100000, IsActive: true,
+		Variants: []entity.ProductVariant{{ID: 10, ProductID: 1}},
+	})
+	variants := newFakeVariantRepo(&entity.ProductVariant{ID: 10, ProductID: 1, Stock: 5, IsActive: true})
+	vouchers := &fakeVoucherRepo{vouchers: map[string]*entity.Voucher{
+		"HEMAT": {
+			ID: 1, Code: "HEMAT", Type: entity.VoucherTypeFixed, Value: 20000, IsActive: true,
+			StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(time.Hour),
+		},
+	}}
+	cart := &entity.Cart{
+		ID:          1,
+		UserID:      7,
+		Items:       []entity.CartItem{{ProductID: 1, VariantID: 10, Quantity: 2}},
+		VoucherCode: "HEMAT",
+	}
+	orders := newFakeOrderRepo()
+	flashSales := &fakeFlashSaleUseCase{}
+	promotions := &fakePromotionRepo{}
+	loyalty := &fakeLoyaltyUseCase{}
+	wallet := &fakeWalletUseCase{}
+
+	uc := &orderUseCase{
+		orderRepo:        orders,
+		orderStatusRepo:  &fakeOrderStatusRepo{orders: orders},
+		cartRepo:         &fakeCartRepo{cart: cart},
+		productRepo:      products,
+		variantRepo:      variants,
+		addressRepo:      &fakeAddressRepo{},
+		voucherUseCase:   &voucherUseCase{voucherRepo: vouchers, productRepo: products},
+		flashSaleUseCase: flashSales,
+		promotionUseCase: &promotionUseCase{
+			promotionRepo:    promotions,
+			cartRepo:         &fakeCartRepo{cart: cart},
+			productRepo:      products,
+			flashSaleUseCase: flashSales,
+		},
+		loyaltyUseCase: loyalty,
+		walletUseCase:  wallet,
+		shippingCost:   15000,
+	}
+	return &checkoutTest{uc: uc, orders: orders, vouchers: vouchers, flashSales: flashSales, promotions: promotions, loyalty: loyalty, wallet: wallet, cart: cart}
+}
+
+func TestCreateOrderRedeemsCartVoucher(t *testing.T) {
+	tt := newCheckoutTest()
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if order.VoucherCode != "HEMAT" || order.DiscountAmount != 20000 || order.FinalAmount != 195000 {
+		t.Errorf("order = %s, discount %.0f, final %.0f, want HEMAT, 20000, 195000", order.VoucherCode, order.DiscountAmount, order.FinalAmount)
+	}
+	redemption := tt.orders.placements[0].Voucher
+	if redemption == nil || redemption.OrderID != order.ID || redemption.DiscountAmount != 20000 {
+		t.Errorf("redemption = %+v, want 20000 off order %d", redemption, order.ID)
+	}
+}
+
+func TestCreateOrderRoundsPercentageVoucherToWholeRupiah(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.uc.productRepo.(*fakeProductRepo).products[1].Price = 33333
+	voucher := tt.vouchers.vouchers["HEMAT"]
+	voucher.Type, voucher.Value = entity.VoucherTypePercentage, 15
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	// 15% of 66666 is 9999.9, charged as 10000
+	if order.DiscountAmount != 10000 || order.FinalAmount != 71666 {
+		t.Errorf("discount %.2f, final %.2f, want 10000 and 71666", order.DiscountAmount, order.FinalAmount)
+	}
+	lines := order.ShippingCost - order.DiscountAmount
+	for _, item := range order.OrderItems {
+		lines += item.FinalPrice * float64(item.Quantity)
+	}
+	if lines != order.FinalAmount {
+		t.Errorf("order lines add up to %.2f, want the final amount %.2f", lines, order.FinalAmount)
+	}
+}
+
+func TestCreateOrderRejectsExpiredCartVoucher(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.vouchers.vouchers["HEMAT"].EndsAt = time.Now().Add(-time.Minute)
+
+	if _, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0); err == nil {
+		t.Fatal("CreateOrder() succeeded with an expired voucher")
+	}
+	if len(tt.orders.orders) != 0 {
+		t.Errorf("placed %d orders, want none", len(tt.orders.orders))
+	}
+}
+
+func TestCreateOrderRejectsInactiveVariant(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.uc.variantRepo.(*fakeVariantRepo).variants[10].IsActive = false
+
+	if _, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0); err == nil {
+		t.Fatal("CreateOrder() succeeded with a deactivated variant in the cart")
+	}
+	if len(tt.orders.orders) != 0 {
+		t.Errorf("placed %d orders, want none", len(tt.orders.orders))
+	}
+	if stock := tt.uc.variantRepo.(*fakeVariantRepo).variants[10].Stock; stock != 5 {
+		t.Errorf("stock = %d, want 5 left untouched", stock)
+	}
+}
+
+func TestCreateOrderChargesFlashSalePrice(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.cart.VoucherCode = ""
+	tt.flashSales.sales = map[uint]*entity.FlashSaleItem{10: {ID: 4, VariantID: 10, SalePrice: 60000, Quota: 5}}
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if item := order.OrderItems[0]; item.Price != 60000 || item.FinalPrice != 60000 {
+		t.Errorf("item price = %.0f, final %.0f, want the sale price 60000", item.Price, item.FinalPrice)
+	}
+	if order.TotalAmount != 120000 || order.FinalAmount != 135000 {
+		t.Errorf("order total %.0f, final %.0f, want 120000 and 135000", order.TotalAmount, order.FinalAmount)
+	}
+	purchases := tt.orders.placements[0].FlashSales
+	if len(purchases) != 1 || purchases[0].FlashSaleItemID != 4 || purchases[0].Quantity != 2 || purchases[0].OrderID != order.ID {
+		t.Errorf("flash sale purchases = %+v, want 2 units of item 4 on order %d", purchases, order.ID)
+	}
+}
+
+func TestCreateOrderGivesBackFlashSaleQuotaWhenNotPlaced(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.flashSales.sales = map[uint]*entity.FlashSaleItem{10: {ID: 4, VariantID: 10, SalePrice: 60000, Quota: 5}}
+	tt.vouchers.vouchers["HEMAT"].MinSpend = 150000 // not reached at the sale price
+
+	if _, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0); err == nil {
+		t.Fatal("CreateOrder() succeeded below the voucher's minimum spend")
+	}
+	if tt.flashSales.cancelled[4] != 2 {
+		t.Errorf("gave back %d units of item 4, want 2", tt.flashSales.cancelled[4])
+	}
+}
+
+// buyOneGetOneHalfOff is a promotion taking 50% off every second unit
+var buyOneGetOneHalfOff = &entity.Promotion{
+	ID: 5, Name: "B1G1 50%", Type: entity.PromotionTypeBuyXGetY, IsActive: true,
+	BuyQuantity: 1, GetQuantity: 1, GetDiscountPercent: 50,
+}
+
+func TestCreateOrderStoresAppliedPromotions(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.cart.VoucherCode = ""
+	tt.promotions.promotions = []*entity.Promotion{buyOneGetOneHalfOff}
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if len(order.Promotions) != 1 || order.Promotions[0].PromotionID != 5 || order.Promotions[0].DiscountAmount != 50000 {
+		t.Fatalf("promotions = %+v, want 50000 off from promotion 5", order.Promotions)
+	}
+	if item := order.OrderItems[0]; item.Price != 100000 || item.DiscountPrice != 25000 || item.FinalPrice != 75000 {
+		t.Errorf("item price %.0f, discount %.0f, final %.0f, want 100000, 25000, 75000", item.Price, item.DiscountPrice, item.FinalPrice)
+	}
+	if order.TotalAmount != 150000 || order.FinalAmount != 165000 {
+		t.Errorf("order total %.0f, final %.0f, want 150000 and 165000", order.TotalAmount, order.FinalAmount)
+	}
+}
+
+func TestPromotionsApplyToFlashSalePrices(t *testing.T) {
+	tt := newCheckoutTest()
+	tt.cart.VoucherCode = ""
+	tt.promotions.promotions = []*entity.Promotion{buyOneGetOneHalfOff}
+	tt.flashSales.sales = map[uint]*entity.FlashSaleItem{10: {ID: 4, VariantID: 10, SalePrice: 60000, Quota: 5}}
+
+	result, err := tt.uc.promotionUseCase.EvaluateCart(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("EvaluateCart() error = %v", err)
+	}
+	if result.Subtotal != 120000 || result.Discount != 30000 {
+		t.Errorf("cart subtotal %.0f, discount %.0f, want 120000 and 30000", result.Subtotal, result.Discount)
+	}
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if order.Promotions[0].DiscountAmount != result.Discount || order.TotalAmount != result.Total {
+		t.Errorf("order discount %.0f, total %.0f, want what the cart showed: %.0f and %.0f",
+			order.Promotions[0].DiscountAmount, order.TotalAmount, result.Discount, result.Total)
+	}
+}
+
+func TestCancelOrderReleasesHolds(t *testing.T) {
+	tt := newCheckoutTest()
+	ctx := context.Background()
+
+	order, err := tt.uc.CreateOrder(ctx, 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if err := tt.uc.CancelOrder(ctx, order.ID, 7); err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+
+	if len(tt.vouchers.released) != 1 || tt.vouchers.released[0] != order.ID {
+		t.Errorf("released voucher uses of orders %v, want [%d]", tt.vouchers.released, order.ID)
+	}
+	if len(tt.flashSales.released) != 1 || tt.flashSales.released[0] != order.ID {
+		t.Errorf("released flash sale units of orders %v, want [%d]", tt.flashSales.released, order.ID)
+	}
+	if len(tt.loyalty.released) != 1 || tt.loyalty.released[0] != order.ID {
+		t.Errorf("released loyalty points of orders %v, want [%d]", tt.loyalty.released, order.ID)
+	}
+	if len(tt.wallet.released) != 1 || tt.wallet.released[0] != order.ID {
+		t.Errorf("released store credit of orders %v, want [%d]", tt.wallet.released, order.ID)
+	}
+}
+
+func TestCreateOrderSplitsTenderWithStoreCredit(t *testing.T) {
+	tt := newCheckoutTest()
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 50000)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if order.WalletAmount != 50000 || order.Status != entity.OrderStatusPending {
+		t.Errorf("order paid %.0f with store credit, status %s, want 50000 and pending", order.WalletAmount, order.Status)
+	}
+	placement := tt.orders.placements[0]
+	if placement.Wallet == nil || placement.Wallet.Amount != -50000 || placement.Payment != nil {
+		t.Errorf("placement wallet %+v, payment %+v, want a 50000 debit and the rest left to the gateway", placement.Wallet, placement.Payment)
+	}
+}
+
+func TestCreateOrderPaidInFullWithStoreCredit(t *testing.T) {
+	tt := newCheckoutTest()
+
+	order, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodWallet, "jne", "", 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if order.WalletAmount != 195000 || order.Status != entity.OrderStatusProcessing || order.PaymentID == nil {
+		t.Errorf("order paid %.0f with store credit, status %s, payment %v, want 195000, processing and a payment", order.WalletAmount, order.Status, order.PaymentID)
+	}
+	payment := tt.orders.placements[0].Payment.Payment
+	if payment.PaymentMethod != entity.PaymentMethodWallet || payment.WalletAmount != 195000 || payment.Amount != 0 {
+		t.Errorf("payment %s of %.0f + %.0f store credit, want wallet, 0 + 195000", payment.PaymentMethod, payment.Amount, payment.WalletAmount)
+	}
+}
+
+func TestCreateOrderRejectsMoreStoreCreditThanOrderTotal(t *testing.T) {
+	tt := newCheckoutTest()
+
+	if _, err := tt.uc.CreateOrder(context.Background(), 7, 1, entity.PaymentMethodBankTransfer, "jne", "", 200000); err == nil {
+		t.Fatal("CreateOrder() succeeded with more store credit than the order costs")
+	}
+	if len(tt.orders.orders) != 0 {
+		t.Errorf("placed %d orders, want none", len(tt.orders.orders))
+	}
+}
@@ -0,0 +1,55 @@
+package impl
+
+import (
+	"fashion-shop/internal/domain/entity"
+)
+
+// paymentTransitions lists, for each target status, the statuses a payment may move from
+var paymentTransitions = map[entity.PaymentStatus][]entity.PaymentStatus{
+	entity.PaymentStatusChallenge:         {entity.PaymentStatusPending},
+	entity.PaymentStatusPaid:              {entity.PaymentStatusPending, entity.PaymentStatusChallenge},
+	entity.PaymentStatusFailed:            {entity.PaymentStatusPending, entity.PaymentStatusChallenge},
+	entity.PaymentStatusCancelled:         {entity.PaymentStatusPending, entity.PaymentStatusChallenge, entity.PaymentStatusPaid},
+	entity.PaymentStatusExpired:           {entity.PaymentStatusPending},
+	entity.PaymentStatusPartiallyRefunded: {entity.PaymentStatusPaid, entity.PaymentStatusPartiallyRefunded},
+	entity.PaymentStatusRefunded:          {entity.PaymentStatusPaid, entity.PaymentStatusPartiallyRefunded},
+}
+
+// canTransitionPayment reports whether a payment may move from one status to another
+func canTransitionPayment(from, to entity.PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[to] {
+		if allowed == from {
+			return true
+		}
+	}
+	return false
+}
+
+// isRefundStatus reports whether a status means money went back to the customer
+func isRefundStatus(status entity.PaymentStatus) bool {
+	return status == entity.PaymentStatusPartiallyRefunded || status == entity.PaymentStatusRefunded
+}
+
+// orderStatusForPayment returns the order status that follows a payment status
+// change, and false if the order should stay as it is
+func orderStatusForPayment(paymentStatus entity.PaymentStatus, orderStatus entity.OrderStatus) (entity.OrderStatus, bool) {
+	switch paymentStatus {
+	case entity.PaymentStatusPaid:
+		// A cancelled order cannot move on; asking for it reports the conflict
+		if orderStatus == entity.OrderStatusPending || orderStatus == entity.OrderStatusCancelled {
+			return entity.OrderStatusProcessing, true
+		}
+	case entity.PaymentStatusFailed, entity.PaymentStatusExpired, entity.PaymentStatusCancelled:
+		// A captured card payment can still be cancelled before the order ships
+		if orderStatus == entity.OrderStatusPending || orderStatus == entity.OrderStatusProcessing {
+			return entity.OrderStatusCancelled, true
+		}
+	case entity.PaymentStatusRefunded:
+		if orderStatus != entity.OrderStatusRefunded {
+			return entity.OrderStatusRefunded, true
+		}
+	}
+	// Challenged payments keep the order pending until an admin reviews them,
+	// and partial refunds leave the order as it is
+	return "", false
+}
@@ -0,0 +1,929 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+)
+
+// defaultPaymentExpiry is how long a customer has to complete a payment when
+// no window is configured for its payment method
+const defaultPaymentExpiry = 24 * time.Hour
+
+// expireBatchSize caps how many overdue payments are expired in one run
+const expireBatchSize = 100
+
+type paymentUseCase struct {
+	paymentRepo         repository.PaymentRepository
+	paymentRefundRepo   repository.PaymentRefundRepository
+	paymentEventRepo    repository.PaymentEventRepository
+	orderRepo           repository.OrderRepository
+	orderItemRepo       repository.OrderItemRepository
+	orderStatusRepo     repository.OrderStatusHistoryRepository
+	userRepo            repository.UserRepository
+	walletRepo          repository.WalletRepository
+	notificationUseCase usecase.NotificationUseCase
+	voucherUseCase      usecase.VoucherUseCase
+	flashSaleUseCase    usecase.FlashSaleUseCase
+	loyaltyUseCase      usecase.LoyaltyUseCase
+	walletUseCase       usecase.WalletUseCase
+	giftCardUseCase     usecase.GiftCardUseCase
+	gateways            *gateway.Registry
+	expiries            map[entity.PaymentMethod]time.Duration
+	callbackURL         string
+}
+
+// NewPaymentUseCase creates a new PaymentUseCase instance
+func NewPaymentUseCase(
+	paymentRepo repository.PaymentRepository,
+	paymentRefundRepo repository.PaymentRefundRepository,
+	paymentEventRepo repository.PaymentEventRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	orderStatusRepo repository.OrderStatusHistoryRepository,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	notificationUseCase usecase.NotificationUseCase,
+	voucherUseCase usecase.VoucherUseCase,
+	flashSaleUseCase usecase.FlashSaleUseCase,
+	loyaltyUseCase usecase.LoyaltyUseCase,
+	walletUseCase usecase.WalletUseCase,
+	giftCardUseCase usecase.GiftCardUseCase,
+	gateways *gateway.Registry,
+	expiries map[entity.PaymentMethod]time.Duration,
+	callbackURL string,
+) usecase.PaymentUseCase {
+	return &paymentUseCase{
+		paymentRepo:         paymentRepo,
+		paymentRefundRepo:   paymentRefundRepo,
+		paymentEventRepo:    paymentEventRepo,
+		orderRepo:           orderRepo,
+		orderItemRepo:       orderItemRepo,
+		orderStatusRepo:     orderStatusRepo,
+		userRepo:            userRepo,
+		walletRepo:          walletRepo,
+		notificationUseCase: notificationUseCase,
+		voucherUseCase:      voucherUseCase,
+		flashSaleUseCase:    flashSaleUseCase,
+		loyaltyUseCase:      loyaltyUseCase,
+		walletUseCase:       walletUseCase,
+		giftCardUseCase:     giftCardUseCase,
+		gateways:            gateways,
+		expiries:            expiries,
+		callbackURL:         callbackURL,
+	}
+}
+
+// ProcessPayment creates a gateway transaction for an order. Without a channel
+// a hosted payment page is created; with a channel the payment is charged
+// directly and the payment instructions (virtual account number, QRIS string
+// or e-wallet deeplink) are returned on the payment.
+func (uc *paymentUseCase) ProcessPayment(ctx context.Context, orderID uint, paymentMethod entity.PaymentMethod, channel string) (*entity.Payment, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != entity.OrderStatusPending {
+		return nil, errors.New("order is not awaiting payment")
+	}
+
+	if paymentMethod == entity.PaymentMethodCOD {
+		return nil, errors.New("cash on delivery orders do not require online payment")
+	}
+
+	// Store credit applied to the order is already paid; the gateway charges the rest
+	amount := order.FinalAmount - order.WalletAmount
+	if amount < 0.01 {
+		return nil, errors.New("order is paid with store credit")
+	}
+
+	// Each gateway offers its own channels
+	gw := uc.gateways.ForMethod(paymentMethod)
+	if channel != "" && !containsString(gw.Channels(paymentMethod), channel) {
+		return nil, fmt.Errorf("channel %s is not available for %s payments", channel, paymentMethod)
+	}
+
+	// Reuse an existing payment if one is still open
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, orderID)
+	if err == nil {
+		switch payment.Status {
+		case entity.PaymentStatusPaid, entity.PaymentStatusPartiallyRefunded, entity.PaymentStatusRefunded:
+			return nil, errors.New("order has already been paid")
+		case entity.PaymentStatusChallenge:
+			return nil, errors.New("payment is under review")
+		case entity.PaymentStatusPending:
+			if payment.PaymentMethod == paymentMethod && payment.Channel == channel && (payment.PaymentURL != "" || payment.TransactionID != "") {
+				return payment, nil
+			}
+			// The customer could still pay the earlier charge
+			if payment.TransactionID != "" && (payment.ExpiredAt == nil || payment.ExpiredAt.After(time.Now())) {
+				return nil, errors.New("a payment with another method is still pending for this order")
+			}
+		}
+	} else {
+		payment = &entity.Payment{OrderID: order.ID}
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.orderItemRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gateways refuse a second charge for an order_id, so every attempt gets its own
+	attempt := payment.Attempts + 1
+
+	firstName, lastName := splitName(order.ShippingAddress.Recipient)
+	req := &gateway.ChargeRequest{
+		OrderID: gatewayOrderID(order.OrderNumber, attempt),
+		Amount:  amount,
+		Method:  paymentMethod,
+		Channel: channel,
+		Customer: gateway.Customer{
+			FirstName: firstName,
+			LastName:  lastName,
+			Email:     user.Email,
+			Phone:     order.ShippingAddress.Phone,
+		},
+		Items:       make([]gateway.Item, 0, len(items)+2),
+		Expiry:      uc.expiryFor(paymentMethod),
+		CallbackURL: uc.callbackURL,
+	}
+
+	// The item lines must add up to the charged amount
+	for _, item := range items {
+		req.Items = append(req.Items, gateway.Item{
+			ID:       strconv.FormatUint(uint64(item.VariantID), 10),
+			Name:     item.ProductName,
+			Price:    item.FinalPrice,
+			Quantity: item.Quantity,
+		})
+	}
+	if order.ShippingCost > 0 {
+		req.Items = append(req.Items, gateway.Item{
+			ID:       "SHIPPING",
+			Name:     "Shipping " + order.ShippingMethod,
+			Price:    order.ShippingCost,
+			Quantity: 1,
+		})
+	}
+	if order.DiscountAmount > 0 {
+		req.Items = append(req.Items, gateway.Item{
+			ID:       "DISCOUNT",
+			Name:     "Discount",
+			Price:    -order.DiscountAmount,
+			Quantity: 1,
+		})
+	}
+	if order.WalletAmount > 0 {
+		req.Items = append(req.Items, gateway.Item{
+			ID:       "STORE_CREDIT",
+			Name:     "Store credit",
+			Price:    -order.WalletAmount,
+			Quantity: 1,
+		})
+	}
+
+	result, err := gw.Charge(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	expiredAt := time.Now().Add(req.Expiry)
+	if result.ExpiresAt != nil {
+		expiredAt = *result.ExpiresAt
+	}
+
+	payment.Provider = gw.Name()
+	payment.PaymentMethod = paymentMethod
+	payment.Channel = channel
+	payment.Amount = amount
+	payment.WalletAmount = order.WalletAmount
+	payment.Status = entity.PaymentStatusPending
+	payment.Attempts = attempt
+	payment.GatewayOrderID = req.OrderID
+	payment.TransactionID = result.TransactionID
+	payment.PaymentURL = result.PaymentURL
+	payment.VANumber = result.VANumber
+	payment.QRString = result.QRString
+	payment.QRCodeURL = result.QRCodeURL
+	payment.DeeplinkURL = result.DeeplinkURL
+	payment.ExpiredAt = &expiredAt
+
+	if payment.ID == 0 {
+		if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+			return nil, err
+		}
+	} else if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.orderRepo.SetPayment(ctx, order.ID, payment.ID); err != nil {
+		return nil, err
+	}
+	order.PaymentID = &payment.ID
+
+	return payment, nil
+}
+
+// GetPaymentByID gets a payment by ID
+func (uc *paymentUseCase) GetPaymentByID(ctx context.Context, id uint) (*entity.Payment, error) {
+	return uc.paymentRepo.GetByID(ctx, id)
+}
+
+// GetPaymentByOrderID gets a payment by order ID
+func (uc *paymentUseCase) GetPaymentByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error) {
+	return uc.paymentRepo.GetByOrderID(ctx, orderID)
+}
+
+// HandlePaymentCallback verifies and processes a payment gateway notification.
+// Every notification is stored; the status is re-confirmed with the gateway and
+// only applied if it is a valid transition, so duplicate or out-of-order
+// notifications are recorded but never transition an order twice.
+func (uc *paymentUseCase) HandlePaymentCallback(ctx context.Context, provider string, header http.Header, payload []byte) error {
+	gw, err := uc.gateways.Get(provider)
+	if err != nil {
+		return err
+	}
+
+	notification, err := gw.ParseNotification(header, payload)
+	if err != nil {
+		// Keep malformed notifications too, they are what needs investigating
+		event := &entity.PaymentEvent{
+			Provider: gw.Name(),
+			Payload:  rawPayload(payload),
+			Status:   entity.PaymentEventStatusRejected,
+			Message:  "unreadable notification: " + err.Error(),
+		}
+		now := time.Now()
+		event.ProcessedAt = &now
+		if createErr := uc.paymentEventRepo.Create(ctx, event); createErr != nil {
+			return createErr
+		}
+		return fmt.Errorf("%w: %v", usecase.ErrInvalidPaymentNotification, err)
+	}
+
+	event := &entity.PaymentEvent{
+		Provider:          gw.Name(),
+		OrderNumber:       notification.OrderID,
+		TransactionID:     notification.TransactionID,
+		TransactionStatus: notification.RawStatus,
+		FraudStatus:       notification.FraudStatus,
+		StatusCode:        notification.StatusCode,
+		GrossAmount:       notification.GrossAmount,
+		SignatureValid:    notification.SignatureValid,
+		Payload:           string(payload),
+		Status:            entity.PaymentEventStatusReceived,
+	}
+
+	if err := uc.paymentEventRepo.Create(ctx, event); err != nil {
+		return err
+	}
+
+	if !event.SignatureValid {
+		uc.finishEvent(ctx, event, entity.PaymentEventStatusRejected, "signature verification failed")
+		return usecase.ErrInvalidPaymentSignature
+	}
+
+	status, message, err := uc.processEvent(ctx, gw, event)
+	uc.finishEvent(ctx, event, status, message)
+	return err
+}
+
+// processEvent applies a verified notification and returns the event outcome.
+// An error is only returned for transient failures that the gateway should retry.
+func (uc *paymentUseCase) processEvent(ctx context.Context, gw gateway.PaymentGateway, event *entity.PaymentEvent) (entity.PaymentEventStatus, string, error) {
+	// Notifications of an earlier attempt no longer match a payment
+	payment, err := uc.paymentOfEvent(ctx, event)
+	if err != nil {
+		return entity.PaymentEventStatusRejected, "unknown or superseded payment attempt", nil
+	}
+	event.PaymentID = &payment.ID
+
+	order, err := uc.orderRepo.GetByID(ctx, payment.OrderID)
+	if err != nil {
+		return entity.PaymentEventStatusRejected, "unknown order", nil
+	}
+
+	if payment.Provider != "" && payment.Provider != gw.Name() {
+		return entity.PaymentEventStatusRejected, "payment belongs to gateway " + payment.Provider, nil
+	}
+
+	// Never trust the notification body alone: ask the gateway for the current status
+	confirmed, err := gw.GetTransaction(ctx, event.OrderNumber, payment.TransactionID)
+	if err != nil {
+		return entity.PaymentEventStatusFailed, "status confirmation failed", err
+	}
+
+	if confirmed.OrderID != event.OrderNumber {
+		return entity.PaymentEventStatusRejected, fmt.Sprintf("confirmed order %q does not match notification", confirmed.OrderID), nil
+	}
+
+	if math.Abs(confirmed.Amount-payment.Amount) > 0.01 {
+		return entity.PaymentEventStatusRejected, "gross amount does not match payment amount", nil
+	}
+
+	target := confirmed.Status
+	if target == "" {
+		return entity.PaymentEventStatusIgnored, "unknown transaction status " + confirmed.RawStatus, nil
+	}
+
+	current := payment.Status
+	if confirmed.TransactionID != "" {
+		payment.TransactionID = confirmed.TransactionID
+	}
+
+	// Refunds are tracked by amount: the gateway reports the refunded total
+	var refund *entity.PaymentRefund
+	if isRefundStatus(target) {
+		refundedTotal := confirmed.RefundedAmount
+		if target == entity.PaymentStatusRefunded && refundedTotal == 0 {
+			refundedTotal = payment.Amount
+		}
+		// Refunds made from here may be reported before they are completed;
+		// completing them adds them to the payment
+		refundedTotal -= pendingRefunds(payment)
+		if refundedTotal <= payment.RefundedAmount+0.01 {
+			return entity.PaymentEventStatusIgnored, "refund already recorded", nil
+		}
+		refund = &entity.PaymentRefund{
+			PaymentID: payment.ID,
+			RefundKey: fmt.Sprintf("%s-gateway-%.0f", event.OrderNumber, refundedTotal),
+			Amount:    refundedTotal - payment.RefundedAmount,
+			Reason:    "refunded at payment gateway",
+			Source:    "gateway",
+			Status:    entity.PaymentRefundStatusSucceeded,
+		}
+		payment.RefundedAmount = refundedTotal
+		target = payment.RefundStatus()
+	} else if target == current {
+		return entity.PaymentEventStatusIgnored, "payment already " + string(current), nil
+	}
+
+	if !canTransitionPayment(current, target) {
+		return entity.PaymentEventStatusIgnored, fmt.Sprintf("transition %s -> %s not allowed", current, target), nil
+	}
+
+	payment.Status = target
+	if target == entity.PaymentStatusPaid {
+		now := time.Now()
+		payment.PaidAt = &now
+	}
+
+	applied, err := uc.paymentRepo.UpdateIfStatus(ctx, payment, current)
+	if err != nil {
+		return entity.PaymentEventStatusFailed, "payment update failed", err
+	}
+	if !applied {
+		return entity.PaymentEventStatusIgnored, "payment already updated by another notification", nil
+	}
+
+	if refund != nil {
+		if err := uc.paymentRefundRepo.Create(ctx, refund); err != nil {
+			return entity.PaymentEventStatusFailed, "refund record failed", err
+		}
+		if err := uc.loyaltyUseCase.ClawbackForRefund(ctx, order.ID, payment.RefundedTotal()); err != nil {
+			return entity.PaymentEventStatusFailed, "loyalty points clawback failed", err
+		}
+	}
+
+	if err := uc.syncOrderStatus(ctx, order, target); err != nil {
+		// The payment is updated; an order the payment cannot move, such as a
+		// cancelled order that was paid after all, is left for an admin
+		var invalid *usecase.InvalidOrderTransitionError
+		if errors.As(err, &invalid) {
+			return entity.PaymentEventStatusProcessed, fmt.Sprintf("payment %s -> %s, but %v", current, target, invalid), nil
+		}
+		return entity.PaymentEventStatusFailed, "order update failed", err
+	}
+
+	return entity.PaymentEventStatusProcessed, fmt.Sprintf("payment %s -> %s", current, target), nil
+}
+
+// paymentOfEvent finds the payment a notification is about. Refund
+// notifications of some gateways only carry the transaction ID; the event
+// then takes the order_id of the payment.
+func (uc *paymentUseCase) paymentOfEvent(ctx context.Context, event *entity.PaymentEvent) (*entity.Payment, error) {
+	if event.OrderNumber != "" {
+		return uc.paymentRepo.GetByGatewayOrderID(ctx, event.OrderNumber)
+	}
+	if event.TransactionID == "" {
+		return nil, errors.New("payment not found")
+	}
+	payment, err := uc.paymentRepo.GetByTransactionID(ctx, event.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	event.OrderNumber = payment.GatewayOrderID
+	return payment, nil
+}
+
+// syncOrderStatus moves the order along with its payment status when the
+// order state machine allows it. Gift cards are issued once their order is
+// paid. A cancelled order is restocked with the status change; its voucher
+// use, flash sale quota, loyalty points and store credit are released after.
+func (uc *paymentUseCase) syncOrderStatus(ctx context.Context, order *entity.Order, paymentStatus entity.PaymentStatus) error {
+	status, ok := orderStatusForPayment(paymentStatus, order.Status)
+	if !ok {
+		return nil
+	}
+
+	if err := transitionOrder(ctx, uc.orderStatusRepo, order, status, systemActor, "Payment "+string(paymentStatus)); err != nil {
+		return err
+	}
+
+	if status == entity.OrderStatusProcessing {
+		return uc.giftCardUseCase.IssueForOrder(ctx, order)
+	}
+	if status != entity.OrderStatusCancelled {
+		return nil
+	}
+
+	if err := uc.releaseOrderHolds(ctx, order.ID); err != nil {
+		return err
+	}
+
+	if paymentStatus == entity.PaymentStatusExpired {
+		uc.notifyExpired(ctx, order)
+	}
+
+	return nil
+}
+
+// releaseOrderHolds releases the voucher use, flash sale quota, loyalty
+// points, store credit and gift cards taken by a cancelled order and marks
+// the order released. Every release is keyed on the order, so a release that
+// failed part way is retried by ReleaseCancelledOrders.
+func (uc *paymentUseCase) releaseOrderHolds(ctx context.Context, orderID uint) error {
+	if err := uc.voucherUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.flashSaleUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.loyaltyUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.walletUseCase.ReleaseForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if err := uc.giftCardUseCase.CancelForOrder(ctx, orderID); err != nil {
+		return err
+	}
+	return uc.orderRepo.MarkHoldsReleased(ctx, orderID)
+}
+
+// notifyExpired tells the customer their order was cancelled for want of payment
+func (uc *paymentUseCase) notifyExpired(ctx context.Context, order *entity.Order) {
+	// The order is already cancelled; a failed notification must not undo that
+	_ = uc.notificationUseCase.CreateNotification(ctx, order.UserID, entity.NotificationTypeOrder,
+		"Order cancelled",
+		fmt.Sprintf("Your order %s was cancelled because payment was not completed in time.", order.OrderNumber),
+		map[string]interface{}{"order_id": order.ID, "order_number": order.OrderNumber},
+	)
+}
+
+// ExpireOverduePayments expires pending payments that are past their expiry
+// time, cancels their orders and releases the reserved stock. Each payment is
+// expired on its own, so one failure does not hold up the rest; the failures
+// are joined into the returned error. It returns the number of payments expired.
+func (uc *paymentUseCase) ExpireOverduePayments(ctx context.Context) (int, error) {
+	payments, err := uc.paymentRepo.GetExpiredPending(ctx, time.Now(), expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var failures []error
+	for _, payment := range payments {
+		ok, err := uc.expirePayment(ctx, payment)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("expire payment %d: %w", payment.ID, err))
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, errors.Join(failures...)
+}
+
+// expirePayment expires a single overdue payment unless the gateway reports
+// that it was paid after all
+func (uc *paymentUseCase) expirePayment(ctx context.Context, payment *entity.Payment) (bool, error) {
+	order, err := uc.orderRepo.GetByID(ctx, payment.OrderID)
+	if err != nil {
+		return false, err
+	}
+
+	// A payment made just before the deadline may not have been notified yet;
+	// leave it to the webhook. Lookup errors are expected for hosted payments
+	// the customer never opened, and the gateway has expired them too by now.
+	if gw, err := uc.gateways.Get(payment.Provider); err == nil {
+		if confirmed, err := gw.GetTransaction(ctx, payment.GatewayOrderID, payment.TransactionID); err == nil {
+			if confirmed.Status == entity.PaymentStatusPaid || confirmed.Status == entity.PaymentStatusChallenge {
+				return false, nil
+			}
+		}
+	}
+
+	// The payment, the order and its stock change together
+	var cancel *entity.OrderStatusHistory
+	if status, ok := orderStatusForPayment(entity.PaymentStatusExpired, order.Status); ok && canTransitionOrder(order.Status, status) {
+		cancel = &entity.OrderStatusHistory{
+			OrderID:    order.ID,
+			FromStatus: order.Status,
+			ToStatus:   status,
+			ActorType:  systemActor.Type,
+			ActorID:    systemActor.ID,
+			Note:       "Payment " + string(entity.PaymentStatusExpired),
+		}
+	}
+
+	applied, err := uc.paymentRepo.Expire(ctx, payment, cancel)
+	if err != nil {
+		return false, err
+	}
+	if !applied {
+		// A notification changed the payment in the meantime
+		return false, nil
+	}
+	if cancel == nil || cancel.ID == 0 {
+		return true, nil
+	}
+
+	order.Status = cancel.ToStatus
+	if err := uc.releaseOrderHolds(ctx, order.ID); err != nil {
+		return true, fmt.Errorf("order %s cancelled but its holds were not released: %w", order.OrderNumber, err)
+	}
+	uc.notifyExpired(ctx, order)
+
+	return true, nil
+}
+
+// ExpireUnstartedOrders cancels pending orders whose payment was not started
+// within the longest payment window and releases what they hold, such as
+// the store credit spent on them. Each order is cancelled on its own; the
+// failures are joined into the returned error. It returns the number of
+// orders cancelled.
+func (uc *paymentUseCase) ExpireUnstartedOrders(ctx context.Context) (int, error) {
+	window := defaultPaymentExpiry
+	for _, expiry := range uc.expiries {
+		window = max(window, expiry)
+	}
+
+	orders, err := uc.orderRepo.GetUnstarted(ctx, time.Now().Add(-window), expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var failures []error
+	for _, order := range orders {
+		if err := uc.syncOrderStatus(ctx, order, entity.PaymentStatusExpired); err != nil {
+			failures = append(failures, fmt.Errorf("expire order %s: %w", order.OrderNumber, err))
+			continue
+		}
+		expired++
+	}
+
+	return expired, errors.Join(failures...)
+}
+
+// ReleaseCancelledOrders gives back what cancelled orders still hold, such as
+// when releasing it failed after the order was cancelled. Each order is
+// released on its own; the failures are joined into the returned error. It
+// returns the number of orders released.
+func (uc *paymentUseCase) ReleaseCancelledOrders(ctx context.Context) (int, error) {
+	orders, err := uc.orderRepo.GetUnreleased(ctx, expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	var failures []error
+	for _, order := range orders {
+		if err := uc.releaseOrderHolds(ctx, order.ID); err != nil {
+			failures = append(failures, fmt.Errorf("release order %s: %w", order.OrderNumber, err))
+			continue
+		}
+		released++
+	}
+
+	return released, errors.Join(failures...)
+}
+
+// expiryFor returns the payment window of a payment method
+func (uc *paymentUseCase) expiryFor(method entity.PaymentMethod) time.Duration {
+	if expiry, ok := uc.expiries[method]; ok && expiry > 0 {
+		return expiry
+	}
+	return defaultPaymentExpiry
+}
+
+// finishEvent records the outcome of a notification
+func (uc *paymentUseCase) finishEvent(ctx context.Context, event *entity.PaymentEvent, status entity.PaymentEventStatus, message string) {
+	now := time.Now()
+	event.Status = status
+	event.Message = message
+	event.ProcessedAt = &now
+	// The outcome is informational; a failure to record it must not fail the notification
+	_ = uc.paymentEventRepo.Update(ctx, event)
+}
+
+// RefundPayment refunds all or part of a paid payment. Refunds go back to the
+// payment method first and the part paid with store credit goes back to the
+// wallet; with toWallet the whole refund is paid into the wallet. The refunds
+// are recorded as pending before the gateway is asked to pay them out, so a
+// refund the gateway made is never lost and concurrent refunds cannot exceed
+// what was paid; they are completed together with the store credit.
+func (uc *paymentUseCase) RefundPayment(ctx context.Context, paymentID uint, amount float64, reason string, toWallet bool) error {
+	payment, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status != entity.PaymentStatusPaid && payment.Status != entity.PaymentStatusPartiallyRefunded {
+		return errors.New("only paid payments can be refunded")
+	}
+
+	refundable, err := uc.refundable(ctx, payment)
+	if err != nil {
+		return err
+	}
+	if amount < 0.01 || amount > refundable+0.01 {
+		return fmt.Errorf("invalid refund amount, at most %.2f can be refunded", refundable)
+	}
+
+	gatewayAmount := 0.0
+	if !toWallet {
+		gatewayAmount = math.Max(math.Min(amount, payment.Amount-payment.RefundedAmount), 0)
+	}
+	walletAmount := amount - gatewayAmount
+
+	order, err := uc.orderRepo.GetByID(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	// Gift cards bought with the order stop working before the money goes
+	// back; they stay cancelled if the refund fails and is tried again
+	if err := uc.giftCardUseCase.CancelForOrder(ctx, order.ID); err != nil {
+		return err
+	}
+
+	var gatewayRefund, walletRefund *entity.PaymentRefund
+	var refunds []*entity.PaymentRefund
+
+	if gatewayAmount >= 0.01 {
+		gatewayRefund = &entity.PaymentRefund{Amount: gatewayAmount, Reason: reason, Source: "admin"}
+		refunds = append(refunds, gatewayRefund)
+	}
+	if walletAmount >= 0.01 {
+		walletRefund = &entity.PaymentRefund{Amount: walletAmount, Reason: reason, Source: "wallet"}
+		refunds = append(refunds, walletRefund)
+	}
+
+	// The refund keys are numbered while the payment is locked
+	if err := uc.paymentRefundRepo.Reserve(ctx, payment.ID, order.OrderNumber, refunds); err != nil {
+		return err
+	}
+
+	var credits []*entity.WalletTransaction
+	if walletRefund != nil {
+		credits = append(credits, &entity.WalletTransaction{
+			UserID:      order.UserID,
+			Type:        entity.WalletTransactionRefund,
+			Amount:      walletRefund.Amount,
+			OrderID:     &order.ID,
+			Reference:   walletRefund.RefundKey,
+			Description: "Refund for order " + order.OrderNumber,
+		})
+	}
+
+	if gatewayRefund != nil {
+		if err := uc.refundAtGateway(ctx, payment, order, gatewayRefund); err != nil {
+			// Nothing was paid out, so the refunded amount is freed again
+			if failErr := uc.paymentRefundRepo.Fail(ctx, refunds); failErr != nil {
+				return fmt.Errorf("%w (refund %s left pending: %v)", err, refunds[0].RefundKey, failErr)
+			}
+			return err
+		}
+	}
+
+	payment, err = uc.paymentRefundRepo.Complete(ctx, refunds, credits)
+	if err != nil {
+		return fmt.Errorf("refund %s was paid out but not completed: %w", refunds[0].RefundKey, err)
+	}
+
+	if err := uc.loyaltyUseCase.ClawbackForRefund(ctx, order.ID, payment.RefundedTotal()); err != nil {
+		return err
+	}
+
+	return uc.syncOrderStatus(ctx, order, payment.Status)
+}
+
+// RefundOrder refunds what is left of the paid payment of an order the way it
+// was paid, which moves the order to refunded once all of it is back.
+func (uc *paymentUseCase) RefundOrder(ctx context.Context, orderID uint, reason string) error {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.PaymentID == nil {
+		return errors.New("order has no paid payment to refund")
+	}
+
+	payment, err := uc.paymentRepo.GetByID(ctx, *order.PaymentID)
+	if err != nil {
+		return err
+	}
+	if payment.Status != entity.PaymentStatusPaid && payment.Status != entity.PaymentStatusPartiallyRefunded {
+		return errors.New("order has no paid payment to refund")
+	}
+
+	refundable, err := uc.refundable(ctx, payment)
+	if err != nil {
+		return err
+	}
+	if refundable < 0.01 {
+		return errors.New("payment has already been refunded in full")
+	}
+	return uc.RefundPayment(ctx, payment.ID, refundable, reason, false)
+}
+
+// refundable returns what is left to refund of a payment. Store credit given
+// back when the order was cancelled, before the payment went through, was
+// returned already and is not refunded again.
+func (uc *paymentUseCase) refundable(ctx context.Context, payment *entity.Payment) (float64, error) {
+	transactions, err := uc.walletRepo.GetOrderTransactions(ctx, payment.OrderID)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0.0
+	for _, transaction := range transactions {
+		if transaction.Type == entity.WalletTransactionRelease {
+			released += transaction.Amount
+		}
+	}
+	return math.Max(payment.PaidAmount()-payment.RefundedTotal()-released, 0), nil
+}
+
+// refundAtGateway asks the gateway that took a payment to pay out a refund
+func (uc *paymentUseCase) refundAtGateway(ctx context.Context, payment *entity.Payment, order *entity.Order, refund *entity.PaymentRefund) error {
+	gw, err := uc.gateways.Get(payment.Provider)
+	if err != nil {
+		return err
+	}
+
+	transactionID := payment.TransactionID
+	if transactionID == "" {
+		transactionID = payment.GatewayOrderID
+	}
+
+	return gw.Refund(ctx, &gateway.RefundRequest{
+		OrderID:       payment.GatewayOrderID,
+		TransactionID: transactionID,
+		RefundKey:     refund.RefundKey,
+		Amount:        refund.Amount,
+		Reason:        refund.Reason,
+	})
+}
+
+// ReviewChallengedPayment approves or denies a payment flagged by fraud detection (admin function)
+func (uc *paymentUseCase) ReviewChallengedPayment(ctx context.Context, paymentID uint, approve bool) error {
+	payment, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status != entity.PaymentStatusChallenge {
+		return errors.New("payment is not awaiting review")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	transactionID := payment.TransactionID
+	if transactionID == "" {
+		transactionID = payment.GatewayOrderID
+	}
+
+	gw, err := uc.gateways.Get(payment.Provider)
+	if err != nil {
+		return err
+	}
+
+	if approve {
+		if err := gw.Approve(ctx, transactionID); err != nil {
+			return err
+		}
+		now := time.Now()
+		payment.Status = entity.PaymentStatusPaid
+		payment.PaidAt = &now
+	} else {
+		if err := gw.Deny(ctx, transactionID); err != nil {
+			return err
+		}
+		payment.Status = entity.PaymentStatusFailed
+	}
+
+	applied, err := uc.paymentRepo.UpdateIfStatus(ctx, payment, entity.PaymentStatusChallenge)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		// The gateway notification may have got there first with the same outcome
+		current, err := uc.paymentRepo.GetByID(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != payment.Status {
+			return fmt.Errorf("payment was updated to %s while it was being reviewed", current.Status)
+		}
+		return nil
+	}
+
+	return uc.syncOrderStatus(ctx, order, payment.Status)
+}
+
+// ListPayments lists payments (admin function)
+func (uc *paymentUseCase) ListPayments(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Payment, int64, error) {
+	offset := (page - 1) * limit
+	return uc.paymentRepo.List(ctx, filter, offset, limit)
+}
+
+// ListPaymentsByCursor lists payments with filter, one page after or before a cursor (admin function)
+func (uc *paymentUseCase) ListPaymentsByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Payment, *entity.PageCursors, error) {
+	return uc.paymentRepo.ListByCursor(ctx, filter, page)
+}
+
+// gatewayOrderID returns the order_id a payment attempt is charged under: the
+// order number for the first attempt and the order number suffixed with the
+// attempt number for retries
+func gatewayOrderID(orderNumber string, attempt int) string {
+	if attempt <= 1 {
+		return orderNumber
+	}
+	return fmt.Sprintf("%s-%d", orderNumber, attempt)
+}
+
+// rawPayload returns a notification body as JSON for storing, quoting it as a
+// JSON string when it is not JSON itself
+func rawPayload(payload []byte) string {
+	if json.Valid(payload) {
+		return string(payload)
+	}
+	quoted, _ := json.Marshal(string(payload))
+	return string(quoted)
+}
+
+// pendingRefunds is the amount of a payment's gateway refunds that were sent
+// but not completed yet
+func pendingRefunds(payment *entity.Payment) float64 {
+	pending := 0.0
+	for _, refund := range payment.Refunds {
+		if refund.Status == entity.PaymentRefundStatusPending && refund.Source != "wallet" {
+			pending += refund.Amount
+		}
+	}
+	return pending
+}
+
+// splitName splits a full name into first and last name
+func splitName(name string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(name), " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
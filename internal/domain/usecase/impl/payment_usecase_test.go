@@ -0,0 +1,399 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+)
+
+// newTestPaymentUseCase creates a payment use case over fakes holding one
+// processing order paid with 100000 through gw and 20000 of store credit
+func newTestPaymentUseCase(gw *stubGateway) (*paymentUseCase, *fakePaymentRepo, *fakePaymentRefundRepo, *fakePaymentEventRepo) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, UserID: 7, OrderNumber: "ORD-1", Status: entity.OrderStatusProcessing})
+	payments := newFakePaymentRepo(&entity.Payment{
+		ID: 1, OrderID: 1, Provider: gw.name, Amount: 100000, WalletAmount: 20000, Status: entity.PaymentStatusPaid,
+	})
+	refunds := &fakePaymentRefundRepo{payments: payments}
+	events := &fakePaymentEventRepo{}
+
+	uc := &paymentUseCase{
+		paymentRepo:       payments,
+		paymentRefundRepo: refunds,
+		paymentEventRepo:  events,
+		orderRepo:         orders,
+		orderStatusRepo:   &fakeOrderStatusRepo{orders: orders},
+		walletRepo:        &fakeWalletRepo{},
+		loyaltyUseCase:    &fakeLoyaltyUseCase{},
+		giftCardUseCase:   &giftCardUseCase{giftCardRepo: &fakeGiftCardRepo{cards: map[uint]*entity.GiftCard{}}},
+		gateways:          newStubRegistry(gw),
+	}
+	return uc, payments, refunds, events
+}
+
+func TestRefundPaymentCompletesAfterGateway(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, refunds, _ := newTestPaymentUseCase(gw)
+
+	if err := uc.RefundPayment(context.Background(), 1, 110000, "damaged", false); err != nil {
+		t.Fatalf("RefundPayment() error = %v", err)
+	}
+
+	if len(gw.refunds) != 1 || gw.refunds[0].Amount != 100000 {
+		t.Fatalf("gateway refunds = %+v, want one of 100000", gw.refunds)
+	}
+	if len(refunds.refunds) != 2 {
+		t.Fatalf("recorded %d refunds, want gateway and wallet parts", len(refunds.refunds))
+	}
+	for _, refund := range refunds.refunds {
+		if refund.Status != entity.PaymentRefundStatusSucceeded {
+			t.Errorf("refund %s status = %s, want succeeded", refund.RefundKey, refund.Status)
+		}
+	}
+	if len(refunds.credits) != 1 || refunds.credits[0].Amount != 10000 {
+		t.Errorf("store credits = %+v, want one of 10000", refunds.credits)
+	}
+
+	payment := payments.payments[1]
+	if payment.RefundedAmount != 100000 || payment.WalletRefunded != 10000 {
+		t.Errorf("refunded = %v + %v, want 100000 + 10000", payment.RefundedAmount, payment.WalletRefunded)
+	}
+	if payment.Status != entity.PaymentStatusPartiallyRefunded {
+		t.Errorf("payment status = %s, want partially_refunded", payment.Status)
+	}
+}
+
+func TestRefundPaymentCancelsGiftCards(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, _, _, _ := newTestPaymentUseCase(gw)
+	orderID := uint(1)
+	cards := uc.giftCardUseCase.(*giftCardUseCase).giftCardRepo.(*fakeGiftCardRepo)
+	cards.cards[3] = &entity.GiftCard{ID: 3, OrderID: &orderID, Status: entity.GiftCardStatusActive, Balance: 120000}
+
+	if err := uc.RefundPayment(context.Background(), 1, 120000, "changed mind", false); err != nil {
+		t.Fatalf("RefundPayment() error = %v", err)
+	}
+	if status := cards.cards[3].Status; status != entity.GiftCardStatusCancelled {
+		t.Errorf("gift card status = %s, want cancelled", status)
+	}
+}
+
+func TestRefundPaymentRefusesRedeemedGiftCards(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _, _ := newTestPaymentUseCase(gw)
+	orderID := uint(1)
+	cards := uc.giftCardUseCase.(*giftCardUseCase).giftCardRepo.(*fakeGiftCardRepo)
+	cards.cards[3] = &entity.GiftCard{ID: 3, OrderID: &orderID, Status: entity.GiftCardStatusRedeemed}
+
+	if err := uc.RefundPayment(context.Background(), 1, 120000, "changed mind", false); err == nil {
+		t.Fatal("RefundPayment() succeeded for a redeemed gift card")
+	}
+	if len(gw.refunds) != 0 || payments.payments[1].RefundedAmount != 0 {
+		t.Errorf("refunded %+v, want nothing paid out", gw.refunds)
+	}
+}
+
+func TestRefundPaymentGatewayFailureFreesRefund(t *testing.T) {
+	gw := &stubGateway{name: "stub", refundErr: errors.New("gateway down")}
+	uc, payments, refunds, _ := newTestPaymentUseCase(gw)
+
+	if err := uc.RefundPayment(context.Background(), 1, 50000, "damaged", false); err == nil {
+		t.Fatal("RefundPayment() error = nil, want the gateway error")
+	}
+
+	if len(refunds.refunds) != 1 || refunds.refunds[0].Status != entity.PaymentRefundStatusFailed {
+		t.Fatalf("refunds = %+v, want one failed refund", refunds.refunds)
+	}
+	if payment := payments.payments[1]; payment.RefundedAmount != 0 || payment.Status != entity.PaymentStatusPaid {
+		t.Errorf("payment = %+v, want it untouched", payment)
+	}
+
+	// The failed refund no longer counts against the payment
+	gw.refundErr = nil
+	if err := uc.RefundPayment(context.Background(), 1, 120000, "damaged", false); err != nil {
+		t.Fatalf("RefundPayment() after failure error = %v", err)
+	}
+}
+
+func TestRefundPaymentPendingRefundsCountAgainstPayment(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, _, refunds, _ := newTestPaymentUseCase(gw)
+
+	// A refund of another request that is still at the gateway
+	refunds.refunds = append(refunds.refunds, &entity.PaymentRefund{
+		ID: 99, PaymentID: 1, RefundKey: "ORD-1-refund-other", Amount: 100000, Source: "admin", Status: entity.PaymentRefundStatusPending,
+	})
+
+	err := uc.RefundPayment(context.Background(), 1, 50000, "damaged", false)
+	if !errors.Is(err, repository.ErrRefundExceedsPayment) {
+		t.Fatalf("RefundPayment() error = %v, want ErrRefundExceedsPayment", err)
+	}
+	if len(gw.refunds) != 0 {
+		t.Errorf("gateway refunds = %+v, want none", gw.refunds)
+	}
+}
+
+func TestHandlePaymentCallbackStoresUnreadableNotification(t *testing.T) {
+	gw := &stubGateway{name: "stub", parseErr: errors.New("unexpected end of JSON input")}
+	uc, _, _, events := newTestPaymentUseCase(gw)
+
+	err := uc.HandlePaymentCallback(context.Background(), "stub", nil, []byte(`{"order_id":`))
+	if !errors.Is(err, usecase.ErrInvalidPaymentNotification) {
+		t.Fatalf("HandlePaymentCallback() error = %v, want ErrInvalidPaymentNotification", err)
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("stored %d events, want 1", len(events.events))
+	}
+	event := events.events[0]
+	if event.Status != entity.PaymentEventStatusRejected || event.Provider != "stub" {
+		t.Errorf("event = %+v, want a rejected stub event", event)
+	}
+	if event.Payload != `"{\"order_id\":"` {
+		t.Errorf("payload = %s, want the body quoted as JSON", event.Payload)
+	}
+}
+
+func TestRefundPaymentKeysAreUnique(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, _, refunds, _ := newTestPaymentUseCase(gw)
+	ctx := context.Background()
+
+	gw.refundErr = errors.New("gateway down")
+	if err := uc.RefundPayment(ctx, 1, 10000, "late", false); err == nil {
+		t.Fatal("RefundPayment() error = nil, want the gateway error")
+	}
+	gw.refundErr = nil
+	for i := 0; i < 2; i++ {
+		if err := uc.RefundPayment(ctx, 1, 10000, "late", false); err != nil {
+			t.Fatalf("RefundPayment() error = %v", err)
+		}
+	}
+
+	// A failed refund keeps its key, so a retry never reuses it at the gateway
+	want := []string{"ORD-1-refund-1", "ORD-1-refund-2", "ORD-1-refund-3"}
+	if len(refunds.refunds) != len(want) {
+		t.Fatalf("recorded %d refunds, want %d", len(refunds.refunds), len(want))
+	}
+	for i, refund := range refunds.refunds {
+		if refund.RefundKey != want[i] {
+			t.Errorf("refund %d key = %s, want %s", i, refund.RefundKey, want[i])
+		}
+	}
+	for i, req := range gw.refunds {
+		if req.RefundKey != want[i+1] {
+			t.Errorf("gateway refund %d key = %s, want %s", i, req.RefundKey, want[i+1])
+		}
+	}
+}
+
+func TestProcessPaymentRetryGetsNewGatewayOrderID(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _, events := newTestPaymentUseCase(gw)
+	uc.orderRepo.(*fakeOrderRepo).orders[2] = &entity.Order{ID: 2, UserID: 7, OrderNumber: "ORD-2", Status: entity.OrderStatusPending, FinalAmount: 50000}
+	uc.orderItemRepo = &fakeOrderItemRepo{}
+	uc.userRepo = &fakeUserRepo{}
+	ctx := context.Background()
+
+	first, err := uc.ProcessPayment(ctx, 2, entity.PaymentMethodBankTransfer, "bca")
+	if err != nil {
+		t.Fatalf("ProcessPayment() error = %v", err)
+	}
+	if first.GatewayOrderID != "ORD-2" || first.Attempts != 1 {
+		t.Fatalf("first attempt = %s #%d, want ORD-2 #1", first.GatewayOrderID, first.Attempts)
+	}
+
+	// The virtual account lapsed before the expiry job got to it
+	lapsed := time.Now().Add(-time.Minute)
+	payments.payments[first.ID].ExpiredAt = &lapsed
+
+	second, err := uc.ProcessPayment(ctx, 2, entity.PaymentMethodQRIS, "qris")
+	if err != nil {
+		t.Fatalf("ProcessPayment() retry error = %v", err)
+	}
+	if second.ID != first.ID || second.GatewayOrderID != "ORD-2-2" || second.Attempts != 2 {
+		t.Fatalf("retry = payment %d %s #%d, want payment %d ORD-2-2 #2", second.ID, second.GatewayOrderID, second.Attempts, first.ID)
+	}
+	if len(gw.charges) != 2 || gw.charges[1].OrderID != "ORD-2-2" {
+		t.Fatalf("gateway charges = %d, want the retry charged as ORD-2-2", len(gw.charges))
+	}
+
+	// A late notification of the first attempt must not touch the payment
+	if err := uc.HandlePaymentCallback(ctx, "stub", nil, []byte(`{"order_id":"ORD-2"}`)); err != nil {
+		t.Fatalf("HandlePaymentCallback() error = %v", err)
+	}
+	if event := events.events[len(events.events)-1]; event.Status != entity.PaymentEventStatusRejected {
+		t.Errorf("stale notification status = %s, want rejected", event.Status)
+	}
+	if payment := payments.payments[first.ID]; payment.Status != entity.PaymentStatusPending {
+		t.Errorf("payment status = %s, want pending", payment.Status)
+	}
+}
+
+func TestProcessPaymentKeepsConcurrentOrderChanges(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, _, _, _ := newTestPaymentUseCase(gw)
+	orders := uc.orderRepo.(*fakeOrderRepo)
+	orders.orders[2] = &entity.Order{ID: 2, UserID: 7, OrderNumber: "ORD-2", Status: entity.OrderStatusPending, FinalAmount: 50000}
+	uc.orderItemRepo = &fakeOrderItemRepo{}
+	uc.userRepo = &fakeUserRepo{}
+	// An admin adds a note while the gateway is charging
+	gw.onCharge = func() { orders.orders[2].Notes = "Gift wrap" }
+
+	payment, err := uc.ProcessPayment(context.Background(), 2, entity.PaymentMethodBankTransfer, "bca")
+	if err != nil {
+		t.Fatalf("ProcessPayment() error = %v", err)
+	}
+
+	order := orders.orders[2]
+	if order.PaymentID == nil || *order.PaymentID != payment.ID || order.Notes != "Gift wrap" {
+		t.Errorf("order payment %v, notes %q; want payment %d and the note kept", order.PaymentID, order.Notes, payment.ID)
+	}
+}
+
+func TestReviewChallengedPaymentReportsConflictingNotification(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _, _ := newTestPaymentUseCase(gw)
+	payments.payments[1].Status = entity.PaymentStatusChallenge
+
+	// The gateway denies the payment through a notification while the admin approves it
+	gw.onReview = func() { payments.payments[1].Status = entity.PaymentStatusFailed }
+
+	if err := uc.ReviewChallengedPayment(context.Background(), 1, true); err == nil {
+		t.Fatal("ReviewChallengedPayment() succeeded although the payment was denied meanwhile")
+	}
+	if payments.payments[1].Status != entity.PaymentStatusFailed {
+		t.Fatalf("payment status = %s, want failed", payments.payments[1].Status)
+	}
+}
+
+func TestReviewChallengedPaymentAcceptsMatchingNotification(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _, _ := newTestPaymentUseCase(gw)
+	payments.payments[1].Status = entity.PaymentStatusChallenge
+
+	gw.onReview = func() { payments.payments[1].Status = entity.PaymentStatusPaid }
+
+	if err := uc.ReviewChallengedPayment(context.Background(), 1, true); err != nil {
+		t.Fatalf("ReviewChallengedPayment() error = %v", err)
+	}
+}
+
+func TestRefundNotificationWithoutOrderIDFindsPayment(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, refunds, events := newTestPaymentUseCase(gw)
+	payments.payments[1].GatewayOrderID = "ORD-1"
+	payments.payments[1].TransactionID = "tx-1"
+	gw.transaction = &gateway.Transaction{
+		OrderID: "ORD-1", TransactionID: "tx-1", Status: entity.PaymentStatusPartiallyRefunded,
+		Amount: 100000, RefundedAmount: 30000,
+	}
+
+	// Like a Xendit refund callback, the notification only names the transaction
+	if err := uc.HandlePaymentCallback(context.Background(), "stub", nil, []byte(`{"transaction_id":"tx-1"}`)); err != nil {
+		t.Fatalf("HandlePaymentCallback() error = %v", err)
+	}
+
+	if events.events[0].Status != entity.PaymentEventStatusProcessed {
+		t.Fatalf("event status = %s (%s), want processed", events.events[0].Status, events.events[0].Message)
+	}
+	payment := payments.payments[1]
+	if payment.Status != entity.PaymentStatusPartiallyRefunded || payment.RefundedAmount != 30000 {
+		t.Fatalf("payment = %s refunded %.0f, want partially_refunded 30000", payment.Status, payment.RefundedAmount)
+	}
+	if len(refunds.refunds) != 1 || refunds.refunds[0].Amount != 30000 {
+		t.Fatalf("refunds = %+v, want one gateway refund of 30000", refunds.refunds)
+	}
+}
+
+func TestExpireOverduePaymentsContinuesAfterFailure(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _, _ := newTestPaymentUseCase(gw)
+	overdue := time.Now().Add(-time.Hour)
+	// Payment 1 belongs to an order that cannot be loaded; payment 2's order was already cancelled
+	payments.payments[1] = &entity.Payment{ID: 1, OrderID: 99, Provider: "stub", Amount: 100000, Status: entity.PaymentStatusPending, ExpiredAt: &overdue}
+	payments.payments[2] = &entity.Payment{ID: 2, OrderID: 2, Provider: "stub", Amount: 50000, Status: entity.PaymentStatusPending, ExpiredAt: &overdue}
+	uc.orderRepo.(*fakeOrderRepo).orders[2] = &entity.Order{ID: 2, OrderNumber: "ORD-2", Status: entity.OrderStatusCancelled}
+
+	expired, err := uc.ExpireOverduePayments(context.Background())
+	if err == nil {
+		t.Fatal("ExpireOverduePayments() did not report the failed payment")
+	}
+	if expired != 1 {
+		t.Fatalf("expired = %d, want 1", expired)
+	}
+	if payments.payments[1].Status != entity.PaymentStatusPending {
+		t.Errorf("failed payment status = %s, want pending", payments.payments[1].Status)
+	}
+	if payments.payments[2].Status != entity.PaymentStatusExpired {
+		t.Errorf("payment 2 status = %s, want expired", payments.payments[2].Status)
+	}
+}
+
+func TestExpireUnstartedOrdersReleasesStoreCredit(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, UserID: 7, OrderNumber: "ORD-1", Status: entity.OrderStatusPending, WalletAmount: 50000})
+	wallet := &fakeWalletUseCase{}
+	uc := &paymentUseCase{
+		orderRepo:           orders,
+		orderItemRepo:       &fakeOrderItemRepo{},
+		orderStatusRepo:     &fakeOrderStatusRepo{orders: orders},
+		notificationUseCase: &fakeNotificationUseCase{},
+		voucherUseCase:      &voucherUseCase{voucherRepo: &fakeVoucherRepo{}},
+		flashSaleUseCase:    &fakeFlashSaleUseCase{},
+		loyaltyUseCase:      &fakeLoyaltyUseCase{},
+		walletUseCase:       wallet,
+		giftCardUseCase:     &giftCardUseCase{giftCardRepo: &fakeGiftCardRepo{}},
+	}
+
+	expired, err := uc.ExpireUnstartedOrders(context.Background())
+	if err != nil {
+		t.Fatalf("ExpireUnstartedOrders() error = %v", err)
+	}
+
+	if expired != 1 || orders.orders[1].Status != entity.OrderStatusCancelled {
+		t.Errorf("expired %d, order status %s, want 1 and cancelled", expired, orders.orders[1].Status)
+	}
+	if len(wallet.released) != 1 || wallet.released[0] != 1 {
+		t.Errorf("released store credit of orders %v, want [1]", wallet.released)
+	}
+}
+
+func TestReleaseCancelledOrdersRetriesFailedRelease(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{ID: 1, UserID: 7, OrderNumber: "ORD-1", Status: entity.OrderStatusPending, WalletAmount: 50000})
+	wallet := &fakeWalletUseCase{releaseErr: errors.New("database is down")}
+	uc := &paymentUseCase{
+		orderRepo:           orders,
+		orderStatusRepo:     &fakeOrderStatusRepo{orders: orders},
+		notificationUseCase: &fakeNotificationUseCase{},
+		voucherUseCase:      &voucherUseCase{voucherRepo: &fakeVoucherRepo{}},
+		flashSaleUseCase:    &fakeFlashSaleUseCase{},
+		loyaltyUseCase:      &fakeLoyaltyUseCase{},
+		walletUseCase:       wallet,
+		giftCardUseCase:     &giftCardUseCase{giftCardRepo: &fakeGiftCardRepo{}},
+	}
+	ctx := context.Background()
+
+	if _, err := uc.ExpireUnstartedOrders(ctx); err == nil {
+		t.Fatal("ExpireUnstartedOrders() did not report the failed release")
+	}
+	if order := orders.orders[1]; order.Status != entity.OrderStatusCancelled || order.HoldsReleasedAt != nil {
+		t.Fatalf("order is %s, released at %v, want cancelled and not released", order.Status, order.HoldsReleasedAt)
+	}
+
+	wallet.releaseErr = nil
+	released, err := uc.ReleaseCancelledOrders(ctx)
+	if err != nil || released != 1 {
+		t.Fatalf("ReleaseCancelledOrders() = %d, %v, want 1", released, err)
+	}
+	if len(wallet.released) != 1 || orders.orders[1].HoldsReleasedAt == nil {
+		t.Errorf("released store credit of orders %v, released at %v, want [1] and marked", wallet.released, orders.orders[1].HoldsReleasedAt)
+	}
+	if released, err := uc.ReleaseCancelledOrders(ctx); err != nil || released != 0 {
+		t.Errorf("second ReleaseCancelledOrders() = %d, %v, want nothing left", released, err)
+	}
+}
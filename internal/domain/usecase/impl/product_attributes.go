@@ -0,0 +1,110 @@
+package impl
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// attributeValidatedProductUseCase wraps a ProductUseCase so product
+// attributes are checked against the category schema when products are
+// saved, and described by name when a product is shown
+type attributeValidatedProductUseCase struct {
+	usecase.ProductUseCase
+	attributeUseCase usecase.AttributeUseCase
+}
+
+// NewAttributeValidatedProductUseCase creates a ProductUseCase that validates product attributes against their category
+func NewAttributeValidatedProductUseCase(productUseCase usecase.ProductUseCase, attributeUseCase usecase.AttributeUseCase) usecase.ProductUseCase {
+	return &attributeValidatedProductUseCase{
+		ProductUseCase:   productUseCase,
+		attributeUseCase: attributeUseCase,
+	}
+}
+
+// CreateProduct creates a product after validating its attributes
+func (uc *attributeValidatedProductUseCase) CreateProduct(ctx context.Context, product *entity.Product) (*entity.Product, error) {
+	attributes, err := uc.attributeUseCase.ValidateProductAttributes(ctx, product.CategoryID, product.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	product.Attributes = attributes
+	return uc.ProductUseCase.CreateProduct(ctx, product)
+}
+
+// UpdateProduct updates a product after validating its attributes. When no
+// attributes are given the stored ones are kept, dropping those the category
+// no longer defines.
+func (uc *attributeValidatedProductUseCase) UpdateProduct(ctx context.Context, id uint, product *entity.Product) (*entity.Product, error) {
+	existing, err := uc.ProductUseCase.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	categoryID := product.CategoryID
+	if categoryID == 0 {
+		categoryID = existing.CategoryID
+	}
+
+	attributes := product.Attributes
+	if attributes == nil {
+		definitions, err := uc.attributeUseCase.GetCategoryAttributes(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		attributes = make(entity.ProductAttributes, len(existing.Attributes))
+		for _, definition := range definitions {
+			if value, ok := existing.Attributes[definition.Code]; ok {
+				attributes[definition.Code] = value
+			}
+		}
+	}
+
+	if product.Attributes, err = uc.attributeUseCase.ValidateProductAttributes(ctx, categoryID, attributes); err != nil {
+		return nil, err
+	}
+	return uc.ProductUseCase.UpdateProduct(ctx, id, product)
+}
+
+// GetProductByID gets a product by ID with its attributes described
+func (uc *attributeValidatedProductUseCase) GetProductByID(ctx context.Context, id uint) (*entity.Product, error) {
+	product, err := uc.ProductUseCase.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return product, uc.describeAttributes(ctx, product)
+}
+
+// GetProductBySlug gets a product by slug with its attributes described
+func (uc *attributeValidatedProductUseCase) GetProductBySlug(ctx context.Context, slug string) (*entity.Product, error) {
+	product, err := uc.ProductUseCase.GetProductBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return product, uc.describeAttributes(ctx, product)
+}
+
+// describeAttributes lists the attribute values of a product with their
+// names and units in the order of the category schema
+func (uc *attributeValidatedProductUseCase) describeAttributes(ctx context.Context, product *entity.Product) error {
+	definitions, err := uc.attributeUseCase.GetCategoryAttributes(ctx, product.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	product.AttributeValues = make([]entity.ProductAttributeValue, 0, len(product.Attributes))
+	for _, definition := range definitions {
+		value, ok := product.Attributes[definition.Code]
+		if !ok {
+			continue
+		}
+		product.AttributeValues = append(product.AttributeValues, entity.ProductAttributeValue{
+			Code:  definition.Code,
+			Name:  definition.Name,
+			Type:  definition.Type,
+			Value: value,
+			Unit:  definition.Unit,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// processedImageProductUseCase wraps a ProductUseCase so uploaded product
+// images are validated, stripped of metadata and stored in every size and
+// format the storefront uses in srcset
+type processedImageProductUseCase struct {
+	usecase.ProductUseCase
+	imageUseCase     usecase.ImageUseCase
+	productRepo      repository.ProductRepository
+	productImageRepo repository.ProductImageRepository
+}
+
+// NewProcessedImageProductUseCase creates a ProductUseCase that processes uploaded product images
+func NewProcessedImageProductUseCase(
+	productUseCase usecase.ProductUseCase,
+	imageUseCase usecase.ImageUseCase,
+	productRepo repository.ProductRepository,
+	productImageRepo repository.ProductImageRepository,
+) usecase.ProductUseCase {
+	return &processedImageProductUseCase{
+		ProductUseCase:   productUseCase,
+		imageUseCase:     imageUseCase,
+		productRepo:      productRepo,
+		productImageRepo: productImageRepo,
+	}
+}
+
+// UploadProductImage processes and stores an image of a product. Uploading
+// an image the product already has returns the existing image.
+func (uc *processedImageProductUseCase) UploadProductImage(ctx context.Context, productID uint, file *multipart.FileHeader, isPrimary bool) (*entity.ProductImage, error) {
+	if _, err := uc.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	stored, err := uc.imageUseCase.StoreUpload(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := uc.findImage(ctx, productID, stored.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		image = &entity.ProductImage{
+			ProductID:   productID,
+			ContentHash: stored.Hash,
+			URL:         stored.URL,
+			Width:       stored.Width,
+			Height:      stored.Height,
+			Renditions:  stored.Renditions,
+			SrcSet:      stored.SrcSet,
+		}
+		err := uc.productImageRepo.Create(ctx, image)
+		if errors.Is(err, repository.ErrProductImageExists) {
+			// a concurrent upload of the same image got there first
+			image, err = uc.findImage(ctx, productID, stored.Hash)
+			if err == nil && image == nil {
+				err = repository.ErrProductImageExists
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isPrimary && !image.IsPrimary {
+		if err := uc.productImageRepo.SetPrimary(ctx, image.ID, productID); err != nil {
+			return nil, err
+		}
+		image.IsPrimary = true
+	}
+	return image, nil
+}
+
+// findImage gets the image of a product uploaded with the given content, or
+// nil when the product has none
+func (uc *processedImageProductUseCase) findImage(ctx context.Context, productID uint, hash string) (*entity.ProductImage, error) {
+	images, err := uc.productImageRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if image.ContentHash == hash {
+			return image, nil
+		}
+	}
+	return nil, nil
+}
+
+// processedImageCategoryUseCase wraps a CategoryUseCase so uploaded category
+// images are processed like product images
+type processedImageCategoryUseCase struct {
+	usecase.CategoryUseCase
+	imageUseCase usecase.ImageUseCase
+	categoryRepo repository.CategoryRepository
+}
+
+// NewProcessedImageCategoryUseCase creates a CategoryUseCase that processes uploaded category images
+func NewProcessedImageCategoryUseCase(
+	categoryUseCase usecase.CategoryUseCase,
+	imageUseCase usecase.ImageUseCase,
+	categoryRepo repository.CategoryRepository,
+) usecase.CategoryUseCase {
+	return &processedImageCategoryUseCase{
+		CategoryUseCase: categoryUseCase,
+		imageUseCase:    imageUseCase,
+		categoryRepo:    categoryRepo,
+	}
+}
+
+// UploadCategoryImage processes and stores the image of a category,
+// replacing its previous image. Renditions of the previous image are kept
+// as other uploads with the same content may share them.
+func (uc *processedImageCategoryUseCase) UploadCategoryImage(ctx context.Context, id uint, file *multipart.FileHeader) (*entity.Category, error) {
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := uc.imageUseCase.StoreUpload(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if category.ImageHash == stored.Hash {
+		return category, nil
+	}
+
+	category.Image = stored.URL
+	category.ImageHash = stored.Hash
+	category.ImageRenditions = stored.Renditions
+	category.ImageSrcSet = stored.SrcSet
+	if err := uc.categoryRepo.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
@@ -0,0 +1,53 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestUploadProductImageReturnsImageStoredByConcurrentUpload(t *testing.T) {
+	stored := &entity.StoredImage{Hash: "abc123", URL: "/uploads/large.jpg", Width: 800, Height: 600}
+	images := &fakeProductImageRepo{
+		racedWith: &entity.ProductImage{ID: 5, ProductID: 1, ContentHash: "abc123", URL: "/uploads/large.jpg"},
+	}
+	uc := NewProcessedImageProductUseCase(
+		nil,
+		&fakeImageUseCase{stored: stored},
+		newFakeProductRepo(&entity.Product{ID: 1, Name: "Linen Shirt", IsActive: true}),
+		images,
+	)
+
+	image, err := uc.UploadProductImage(context.Background(), 1, nil, false)
+	if err != nil {
+		t.Fatalf("UploadProductImage() error = %v", err)
+	}
+	if image.ID != 5 {
+		t.Errorf("image ID = %d, want the concurrently stored image 5", image.ID)
+	}
+	if len(images.images) != 1 {
+		t.Errorf("product has %d images, want 1", len(images.images))
+	}
+}
+
+func TestUploadProductImageReusesSameContent(t *testing.T) {
+	stored := &entity.StoredImage{Hash: "abc123", URL: "/uploads/large.jpg"}
+	images := &fakeProductImageRepo{
+		images: []*entity.ProductImage{{ID: 3, ProductID: 1, ContentHash: "abc123"}},
+	}
+	uc := NewProcessedImageProductUseCase(
+		nil,
+		&fakeImageUseCase{stored: stored},
+		newFakeProductRepo(&entity.Product{ID: 1, Name: "Linen Shirt", IsActive: true}),
+		images,
+	)
+
+	image, err := uc.UploadProductImage(context.Background(), 1, nil, false)
+	if err != nil {
+		t.Fatalf("UploadProductImage() error = %v", err)
+	}
+	if image.ID != 3 || len(images.images) != 1 {
+		t.Errorf("image ID = %d with %d images, want the existing image 3 only", image.ID, len(images.images))
+	}
+}
@@ -0,0 +1,196 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/search"
+)
+
+const (
+	minSuggestionLength = 2  // characters typed before suggestions are offered
+	maxSuggestions      = 20 // suggestions returned at most per request
+)
+
+type productSearchUseCase struct {
+	productSearchRepo repository.ProductSearchRepository
+	attributeRepo     repository.AttributeRepository
+	flashSaleUseCase  usecase.FlashSaleUseCase
+}
+
+// NewProductSearchUseCase creates a new ProductSearchUseCase instance
+func NewProductSearchUseCase(
+	productSearchRepo repository.ProductSearchRepository,
+	attributeRepo repository.AttributeRepository,
+	flashSaleUseCase usecase.FlashSaleUseCase,
+) usecase.ProductSearchUseCase {
+	return &productSearchUseCase{
+		productSearchRepo: productSearchRepo,
+		attributeRepo:     attributeRepo,
+		flashSaleUseCase:  flashSaleUseCase,
+	}
+}
+
+// SearchProducts searches products by keyword, most relevant first unless another sort is given
+func (uc *productSearchUseCase) SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, 0, errors.New("search keyword is required")
+	}
+
+	offset := (page - 1) * limit
+	return uc.productSearchRepo.Search(ctx, keyword, filter, sort, offset, limit)
+}
+
+// GetSuggestions gets autocomplete suggestions for a partially typed keyword
+func (uc *productSearchUseCase) GetSuggestions(ctx context.Context, keyword string, limit int) ([]*entity.SearchSuggestion, error) {
+	keyword = strings.TrimSpace(keyword)
+	if utf8.RuneCountInString(keyword) < minSuggestionLength {
+		return []*entity.SearchSuggestion{}, nil
+	}
+
+	if limit <= 0 || limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+	return uc.productSearchRepo.Suggest(ctx, keyword, limit)
+}
+
+// FilterProducts lists products matching the storefront filters with their effective prices
+func (uc *productSearchUseCase) FilterProducts(ctx context.Context, filter *entity.ProductFilter, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	if _, err := uc.normalizeFilter(ctx, filter); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	products, count, err := uc.productSearchRepo.Filter(ctx, filter, sort, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, count, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// FilterProductsByCursor lists products matching the storefront filters with
+// their effective prices, one page after or before a cursor. Cursors follow
+// creation time, so only the newest first order can be paged this way.
+func (uc *productSearchUseCase) FilterProductsByCursor(ctx context.Context, filter *entity.ProductFilter, sort string, page *entity.CursorPage) ([]*entity.Product, *entity.PageCursors, error) {
+	if _, err := uc.normalizeFilter(ctx, filter); err != nil {
+		return nil, nil, err
+	}
+	if filter.Keyword != "" || (sort != "" && sort != search.SortNewest) {
+		return nil, nil, errors.New("cursor pagination only supports listings sorted by newest without a keyword")
+	}
+
+	products, cursors, err := uc.productSearchRepo.FilterByCursor(ctx, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	return products, cursors, uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...)
+}
+
+// GetFacets counts the products matching the storefront filters per filter
+// value, including the filterable attributes of the listed category
+func (uc *productSearchUseCase) GetFacets(ctx context.Context, filter *entity.ProductFilter) (*entity.ProductFacets, error) {
+	attributes, err := uc.normalizeFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := uc.productSearchRepo.Facets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	facets.Attributes = make([]entity.AttributeFacet, 0, len(attributes))
+	for _, attribute := range attributes {
+		if containsAttributeFacet(facets.Attributes, attribute.Code) {
+			continue
+		}
+		values, err := uc.productSearchRepo.AttributeFacet(ctx, filter, attribute.Code)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			continue
+		}
+		facets.Attributes = append(facets.Attributes, entity.AttributeFacet{
+			Code:   attribute.Code,
+			Name:   attribute.Name,
+			Values: values,
+		})
+	}
+	return facets, nil
+}
+
+// normalizeFilter normalizes a filter and keeps only the attribute filters
+// on attributes that are filterable under the filtered category, returning
+// those attributes
+func (uc *productSearchUseCase) normalizeFilter(ctx context.Context, filter *entity.ProductFilter) ([]*entity.AttributeDefinition, error) {
+	if err := normalizeProductFilter(filter); err != nil {
+		return nil, err
+	}
+
+	attributes, err := uc.attributeRepo.GetFilterable(ctx, filter.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	filterable := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		filterable[attribute.Code] = true
+	}
+	for code, values := range filter.Attributes {
+		values = trimValues(values)
+		if !filterable[code] || len(values) == 0 {
+			delete(filter.Attributes, code)
+			continue
+		}
+		filter.Attributes[code] = values
+	}
+	return attributes, nil
+}
+
+// containsAttributeFacet reports whether an attribute already has a facet
+func containsAttributeFacet(facets []entity.AttributeFacet, code string) bool {
+	for _, facet := range facets {
+		if facet.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeProductFilter trims the filter values and checks the ranges are valid
+func normalizeProductFilter(filter *entity.ProductFilter) error {
+	filter.Keyword = strings.TrimSpace(filter.Keyword)
+	filter.Sizes = trimValues(filter.Sizes)
+	filter.Colors = trimValues(filter.Colors)
+	filter.Tags = trimValues(filter.Tags)
+
+	if filter.MinPrice != nil && *filter.MinPrice < 0 || filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return errors.New("price range cannot be negative")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return errors.New("minimum price cannot be above maximum price")
+	}
+	if filter.MinRating != nil && (*filter.MinRating < 1 || *filter.MinRating > 5) {
+		return errors.New("rating must be between 1 and 5")
+	}
+	return nil
+}
+
+// trimValues trims filter values, dropping empty and repeated ones
+func trimValues(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !containsString(trimmed, value) {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
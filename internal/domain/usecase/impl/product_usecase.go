@@ -0,0 +1,475 @@
+package impl
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/storage"
+
+	"github.com/google/uuid"
+)
+
+// bulkUploadColumns are the columns of a product bulk upload CSV file. Rows
+// with the same slug are variants of one product; the product fields are
+// taken from its first row.
+var bulkUploadColumns = []string{"name", "slug", "description", "price", "discount_price", "category_id", "sku", "size", "color", "stock", "weight"}
+
+type productUseCase struct {
+	productRepo      repository.ProductRepository
+	productImageRepo repository.ProductImageRepository
+	variantRepo      repository.ProductVariantRepository
+	categoryRepo     repository.CategoryRepository
+	tagRepo          repository.TagRepository
+	store            storage.Storage
+}
+
+// NewProductUseCase creates a new ProductUseCase instance
+func NewProductUseCase(
+	productRepo repository.ProductRepository,
+	productImageRepo repository.ProductImageRepository,
+	variantRepo repository.ProductVariantRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	store storage.Storage,
+) usecase.ProductUseCase {
+	return &productUseCase{
+		productRepo:      productRepo,
+		productImageRepo: productImageRepo,
+		variantRepo:      variantRepo,
+		categoryRepo:     categoryRepo,
+		tagRepo:          tagRepo,
+		store:            store,
+	}
+}
+
+// CreateProduct creates a product with its variants and tags (admin function)
+func (uc *productUseCase) CreateProduct(ctx context.Context, product *entity.Product) (*entity.Product, error) {
+	if err := uc.validateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	if product.Slug == "" {
+		product.Slug = slugify(product.Name)
+	}
+	if existing, err := uc.productRepo.GetBySlug(ctx, product.Slug); err == nil && existing != nil {
+		return nil, errors.New("slug is already used by another product")
+	}
+
+	for i := range product.Variants {
+		if err := uc.validateVariant(ctx, &product.Variants[i], 0); err != nil {
+			return nil, err
+		}
+		product.Variants[i].IsActive = true
+	}
+
+	tags, err := uc.resolveTags(ctx, product.Tags)
+	if err != nil {
+		return nil, err
+	}
+	product.Tags = tags
+	if product.Attributes == nil {
+		product.Attributes = entity.ProductAttributes{}
+	}
+
+	if err := uc.productRepo.Create(ctx, product); err != nil {
+		return nil, err
+	}
+	return uc.productRepo.GetByID(ctx, product.ID)
+}
+
+// GetProductByID gets a product by ID
+func (uc *productUseCase) GetProductByID(ctx context.Context, id uint) (*entity.Product, error) {
+	return uc.productRepo.GetByID(ctx, id)
+}
+
+// GetProductBySlug gets a product by slug
+func (uc *productUseCase) GetProductBySlug(ctx context.Context, slug string) (*entity.Product, error) {
+	return uc.productRepo.GetBySlug(ctx, slug)
+}
+
+// UpdateProduct updates the fields of a product (admin function). A zero
+// category, an empty slug and nil attributes or tags keep what is stored.
+func (uc *productUseCase) UpdateProduct(ctx context.Context, id uint, product *entity.Product) (*entity.Product, error) {
+	existing, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.CategoryID == 0 {
+		product.CategoryID = existing.CategoryID
+	}
+	if err := uc.validateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	if product.Slug != "" && product.Slug != existing.Slug {
+		if other, err := uc.productRepo.GetBySlug(ctx, product.Slug); err == nil && other.ID != id {
+			return nil, errors.New("slug is already used by another product")
+		}
+		existing.Slug = product.Slug
+	}
+
+	existing.Name = product.Name
+	existing.Description = product.Description
+	existing.Price = product.Price
+	existing.DiscountPrice = product.DiscountPrice
+	existing.CategoryID = product.CategoryID
+	existing.IsActive = product.IsActive
+	if product.Attributes != nil {
+		existing.Attributes = product.Attributes
+	}
+	if product.Tags != nil {
+		if existing.Tags, err = uc.resolveTags(ctx, product.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.productRepo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return uc.productRepo.GetByID(ctx, id)
+}
+
+// DeleteProduct deletes a product (admin function)
+func (uc *productUseCase) DeleteProduct(ctx context.Context, id uint) error {
+	if _, err := uc.productRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.productRepo.Delete(ctx, id)
+}
+
+// ListProducts lists products with filter, sort and pagination
+func (uc *productUseCase) ListProducts(ctx context.Context, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	offset := (page - 1) * limit
+	return uc.productRepo.List(ctx, filter, sort, offset, limit)
+}
+
+// SearchProducts searches products by keyword with filter, sort and pagination
+func (uc *productUseCase) SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	offset := (page - 1) * limit
+	return uc.productRepo.Search(ctx, keyword, filter, sort, offset, limit)
+}
+
+// UploadProductImage stores an uploaded image of a product as it is (admin function)
+func (uc *productUseCase) UploadProductImage(ctx context.Context, productID uint, file *multipart.FileHeader, isPrimary bool) (*entity.ProductImage, error) {
+	if _, err := uc.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	url, err := saveUpload(ctx, uc.store, fmt.Sprintf("products/%d", productID), file)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &entity.ProductImage{ProductID: productID, URL: url, IsPrimary: isPrimary}
+	if err := uc.productImageRepo.Create(ctx, image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// DeleteProductImage deletes a product image (admin function)
+func (uc *productUseCase) DeleteProductImage(ctx context.Context, id uint) error {
+	if _, err := uc.productImageRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.productImageRepo.Delete(ctx, id)
+}
+
+// SetPrimaryImage makes an image the primary image of its product (admin function)
+func (uc *productUseCase) SetPrimaryImage(ctx context.Context, id uint, productID uint) error {
+	return uc.productImageRepo.SetPrimary(ctx, id, productID)
+}
+
+// AddVariant adds a variant to a product (admin function)
+func (uc *productUseCase) AddVariant(ctx context.Context, productID uint, variant *entity.ProductVariant) (*entity.ProductVariant, error) {
+	if _, err := uc.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	if err := uc.validateVariant(ctx, variant, 0); err != nil {
+		return nil, err
+	}
+
+	variant.ID = 0
+	variant.ProductID = productID
+	variant.IsActive = true
+	if err := uc.variantRepo.Create(ctx, variant); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// UpdateVariant updates the SKU, size, color, stock and weight of a variant (admin function)
+func (uc *productUseCase) UpdateVariant(ctx context.Context, id uint, variant *entity.ProductVariant) (*entity.ProductVariant, error) {
+	existing, err := uc.variantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.validateVariant(ctx, variant, id); err != nil {
+		return nil, err
+	}
+
+	existing.SKU = variant.SKU
+	existing.Size = variant.Size
+	existing.Color = variant.Color
+	existing.Stock = variant.Stock
+	existing.Weight = variant.Weight
+	if err := uc.variantRepo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// DeleteVariant deletes a variant (admin function)
+func (uc *productUseCase) DeleteVariant(ctx context.Context, id uint) error {
+	if _, err := uc.variantRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.variantRepo.Delete(ctx, id)
+}
+
+// UpdateStock sets the stock of a variant (admin function)
+func (uc *productUseCase) UpdateStock(ctx context.Context, variantID uint, quantity int) error {
+	if quantity < 0 {
+		return errors.New("stock cannot be negative")
+	}
+	return uc.productRepo.UpdateStock(ctx, variantID, quantity)
+}
+
+// GetBestSellers gets the best-selling products
+func (uc *productUseCase) GetBestSellers(ctx context.Context, limit int) ([]*entity.Product, error) {
+	return uc.productRepo.GetBestSellers(ctx, limit)
+}
+
+// GetNewArrivals gets the newest products
+func (uc *productUseCase) GetNewArrivals(ctx context.Context, limit int) ([]*entity.Product, error) {
+	return uc.productRepo.GetNewArrivals(ctx, limit)
+}
+
+// GetTopRated gets the best-rated products
+func (uc *productUseCase) GetTopRated(ctx context.Context, limit int) ([]*entity.Product, error) {
+	return uc.productRepo.GetTopRated(ctx, limit)
+}
+
+// BulkUploadProducts creates products from a CSV file with a header row
+// naming the bulkUploadColumns. Products are created one by one; the upload
+// stops at the first invalid product and returns how many were created
+// before it. (admin function)
+func (uc *productUseCase) BulkUploadProducts(ctx context.Context, file *multipart.FileHeader) (int, error) {
+	src, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	products, err := parseBulkUpload(src)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for i, product := range products {
+		if _, err := uc.CreateProduct(ctx, product); err != nil {
+			return created, fmt.Errorf("product %d (%s): %w", i+1, product.Slug, err)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// validateProduct checks the fields every product needs
+func (uc *productUseCase) validateProduct(ctx context.Context, product *entity.Product) error {
+	product.Name = strings.TrimSpace(product.Name)
+	if product.Name == "" {
+		return errors.New("name is required")
+	}
+	if product.Price <= 0 {
+		return errors.New("price must be greater than 0")
+	}
+	if product.DiscountPrice != nil && (*product.DiscountPrice < 0 || *product.DiscountPrice >= product.Price) {
+		return errors.New("discount price must be lower than the price")
+	}
+	if _, err := uc.categoryRepo.GetByID(ctx, product.CategoryID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateVariant checks a variant and that its SKU is not used by another
+// variant than the one with the given ID
+func (uc *productUseCase) validateVariant(ctx context.Context, variant *entity.ProductVariant, id uint) error {
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	if variant.SKU == "" {
+		return errors.New("SKU is required")
+	}
+	if variant.Stock < 0 {
+		return errors.New("stock cannot be negative")
+	}
+	if variant.Weight <= 0 {
+		return errors.New("weight must be greater than 0")
+	}
+
+	other, err := uc.variantRepo.GetBySKU(ctx, variant.SKU)
+	if err != nil && !errors.Is(err, repository.ErrProductVariantNotFound) {
+		return err
+	}
+	if other != nil && other.ID != id {
+		return fmt.Errorf("SKU %s is already used by another variant", variant.SKU)
+	}
+	return nil
+}
+
+// resolveTags looks up tags by name, creating the ones that do not exist yet
+func (uc *productUseCase) resolveTags(ctx context.Context, tags []entity.Tag) ([]entity.Tag, error) {
+	resolved := make([]entity.Tag, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name := strings.ToLower(strings.TrimSpace(tag.Name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		existing, err := uc.tagRepo.GetByName(ctx, name)
+		if err != nil {
+			existing = &entity.Tag{Name: name}
+			if err := uc.tagRepo.Create(ctx, existing); err != nil {
+				return nil, err
+			}
+		}
+		resolved = append(resolved, *existing)
+	}
+	return resolved, nil
+}
+
+// parseBulkUpload reads the products of a bulk upload CSV file
+func parseBulkUpload(r io.Reader) ([]*entity.Product, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("file is empty or not a CSV file")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range bulkUploadColumns {
+		if _, ok := columns[name]; !ok && name != "discount_price" && name != "description" {
+			return nil, fmt.Errorf("column %s is missing", name)
+		}
+	}
+
+	var products []*entity.Product
+	bySlug := make(map[string]*entity.Product)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		slug := field("slug")
+		if slug == "" {
+			slug = slugify(field("name"))
+		}
+		product, ok := bySlug[slug]
+		if !ok {
+			product = &entity.Product{
+				Name:        field("name"),
+				Slug:        slug,
+				Description: field("description"),
+				IsActive:    true,
+			}
+			if product.Price, err = strconv.ParseFloat(field("price"), 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid price", line)
+			}
+			if value := field("discount_price"); value != "" {
+				discount, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid discount price", line)
+				}
+				product.DiscountPrice = &discount
+			}
+			categoryID, err := strconv.ParseUint(field("category_id"), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid category ID", line)
+			}
+			product.CategoryID = uint(categoryID)
+			bySlug[slug] = product
+			products = append(products, product)
+		}
+
+		stock, err := strconv.Atoi(field("stock"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid stock", line)
+		}
+		weight, err := strconv.ParseFloat(field("weight"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid weight", line)
+		}
+		product.Variants = append(product.Variants, entity.ProductVariant{
+			SKU:    field("sku"),
+			Size:   field("size"),
+			Color:  field("color"),
+			Stock:  stock,
+			Weight: weight,
+		})
+	}
+
+	if len(products) == 0 {
+		return nil, errors.New("file has no products")
+	}
+	return products, nil
+}
+
+// slugify turns a name into a URL slug such as "kemeja-linen-pria"
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// saveUpload stores an uploaded file as it is under a directory, with a random name
+func saveUpload(ctx context.Context, store storage.Storage, dir string, file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", err
+	}
+
+	key := dir + "/" + uuid.NewString() + strings.ToLower(path.Ext(file.Filename))
+	return store.Save(ctx, key, data, file.Header.Get("Content-Type"))
+}
@@ -0,0 +1,487 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type promotionUseCase struct {
+	promotionRepo    repository.PromotionRepository
+	cartRepo         repository.CartRepository
+	productRepo      repository.ProductRepository
+	categoryRepo     repository.CategoryRepository
+	flashSaleUseCase usecase.FlashSaleUseCase
+}
+
+// NewPromotionUseCase creates a new PromotionUseCase instance
+func NewPromotionUseCase(
+	promotionRepo repository.PromotionRepository,
+	cartRepo repository.CartRepository,
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+	flashSaleUseCase usecase.FlashSaleUseCase,
+) usecase.PromotionUseCase {
+	return &promotionUseCase{
+		promotionRepo:    promotionRepo,
+		cartRepo:         cartRepo,
+		productRepo:      productRepo,
+		categoryRepo:     categoryRepo,
+		flashSaleUseCase: flashSaleUseCase,
+	}
+}
+
+// promotionLine is a priced cart or order line promotions are evaluated against
+type promotionLine struct {
+	productID  uint
+	categoryID uint
+	variantID  uint
+	unitPrice  float64
+	quantity   int
+}
+
+// promotionUnit is a single unit of a line. Units taken by a buy-x-get-y or
+// bundle promotion are consumed and cannot be used by another one.
+type promotionUnit struct {
+	line     int
+	price    float64
+	discount float64
+	consumed bool
+}
+
+// net returns what the unit costs after the discounts applied so far
+func (u *promotionUnit) net() float64 {
+	return u.price - u.discount
+}
+
+// EvaluateCart works out the promotions the user's cart qualifies for. Items
+// in a running flash sale are evaluated at the sale price, as at checkout.
+func (uc *promotionUseCase) EvaluateCart(ctx context.Context, userID uint) (*usecase.PromotionResult, error) {
+	cart, err := uc.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*entity.Product, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err := uc.flashSaleUseCase.ApplyEffectivePrices(ctx, products...); err != nil {
+		return nil, err
+	}
+
+	lines := make([]promotionLine, 0, len(cart.Items))
+	for i, item := range cart.Items {
+		lines = append(lines, promotionLine{
+			productID:  products[i].ID,
+			categoryID: products[i].CategoryID,
+			variantID:  item.VariantID,
+			unitPrice:  variantPrice(products[i], item.VariantID),
+			quantity:   item.Quantity,
+		})
+	}
+
+	promotions, err := uc.promotionRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluatePromotions(promotions, lines), nil
+}
+
+// ApplyToOrderItems applies the running promotions to the items of an order
+// being created. Each item's FinalPrice, which is the flash sale price of an
+// item on sale, is taken as its price before promotions; DiscountPrice is set to the promotion discount per unit and
+// FinalPrice to what is paid per unit, so refunds of part of a line are
+// prorated. It must be called once, before the order is saved, and returns
+// the promotions to store with the order.
+func (uc *promotionUseCase) ApplyToOrderItems(ctx context.Context, items []*entity.OrderItem) ([]entity.OrderPromotion, error) {
+	lines := make([]promotionLine, 0, len(items))
+	for _, item := range items {
+		product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, promotionLine{
+			productID:  item.ProductID,
+			categoryID: product.CategoryID,
+			variantID:  item.VariantID,
+			unitPrice:  item.FinalPrice,
+			quantity:   item.Quantity,
+		})
+	}
+
+	promotions, err := uc.promotionRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := evaluatePromotions(promotions, lines)
+	for i, item := range items {
+		item.DiscountPrice = result.Lines[i].DiscountPrice
+		item.FinalPrice = result.Lines[i].FinalPrice
+	}
+
+	applied := make([]entity.OrderPromotion, 0, len(result.Promotions))
+	for _, promotion := range result.Promotions {
+		applied = append(applied, entity.OrderPromotion{
+			PromotionID:    promotion.PromotionID,
+			Name:           promotion.Name,
+			DiscountAmount: promotion.Discount,
+		})
+	}
+
+	return applied, nil
+}
+
+// CreatePromotion creates a promotion (admin function)
+func (uc *promotionUseCase) CreatePromotion(ctx context.Context, promotion *entity.Promotion, productIDs, categoryIDs []uint) error {
+	if err := uc.prepare(ctx, promotion, productIDs, categoryIDs); err != nil {
+		return err
+	}
+	return uc.promotionRepo.Create(ctx, promotion)
+}
+
+// UpdatePromotion updates a promotion with its rules and scope (admin function)
+func (uc *promotionUseCase) UpdatePromotion(ctx context.Context, promotion *entity.Promotion, productIDs, categoryIDs []uint) error {
+	existing, err := uc.promotionRepo.GetByID(ctx, promotion.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.prepare(ctx, promotion, productIDs, categoryIDs); err != nil {
+		return err
+	}
+
+	promotion.CreatedAt = existing.CreatedAt
+	return uc.promotionRepo.Update(ctx, promotion)
+}
+
+// DeletePromotion deletes a promotion (admin function)
+func (uc *promotionUseCase) DeletePromotion(ctx context.Context, id uint) error {
+	if _, err := uc.promotionRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.promotionRepo.Delete(ctx, id)
+}
+
+// GetPromotion gets a promotion with its rules and scope (admin function)
+func (uc *promotionUseCase) GetPromotion(ctx context.Context, id uint) (*entity.Promotion, error) {
+	return uc.promotionRepo.GetByID(ctx, id)
+}
+
+// ListPromotions lists promotions with filter and pagination (admin function)
+func (uc *promotionUseCase) ListPromotions(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Promotion, int64, error) {
+	offset := (page - 1) * limit
+	return uc.promotionRepo.List(ctx, filter, offset, limit)
+}
+
+// prepare validates a promotion created or updated by an admin and resolves its scope
+func (uc *promotionUseCase) prepare(ctx context.Context, promotion *entity.Promotion, productIDs, categoryIDs []uint) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+
+	for _, item := range promotion.BundleItems {
+		if item.ProductID != nil {
+			if _, err := uc.productRepo.GetByID(ctx, *item.ProductID); err != nil {
+				return fmt.Errorf("product %d: %w", *item.ProductID, err)
+			}
+		}
+		if item.CategoryID != nil {
+			if _, err := uc.categoryRepo.GetByID(ctx, *item.CategoryID); err != nil {
+				return fmt.Errorf("category %d: %w", *item.CategoryID, err)
+			}
+		}
+	}
+
+	products, categories, err := loadProductScope(ctx, uc.productRepo, uc.categoryRepo, productIDs, categoryIDs)
+	if err != nil {
+		return err
+	}
+	promotion.Products = products
+	promotion.Categories = categories
+	return nil
+}
+
+// validatePromotion checks the rules of a promotion and clears the settings its type does not use
+func validatePromotion(promotion *entity.Promotion) error {
+	if promotion.Name == "" {
+		return errors.New("promotion name is required")
+	}
+	if !promotion.EndsAt.After(promotion.StartsAt) {
+		return errors.New("promotion must end after it starts")
+	}
+
+	switch promotion.Type {
+	case entity.PromotionTypeBuyXGetY:
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return errors.New("buy and get quantities must be greater than 0")
+		}
+		if promotion.GetDiscountPercent <= 0 || promotion.GetDiscountPercent > 100 {
+			return errors.New("get discount must be between 0 and 100 percent")
+		}
+		promotion.BundlePrice = 0
+		promotion.BundleItems = nil
+		promotion.Tiers = nil
+	case entity.PromotionTypeBundle:
+		if promotion.BundlePrice <= 0 {
+			return errors.New("bundle price must be greater than 0")
+		}
+		units := 0
+		for _, item := range promotion.BundleItems {
+			if (item.ProductID == nil) == (item.CategoryID == nil) {
+				return errors.New("each bundle item needs either a product or a category")
+			}
+			if item.Quantity <= 0 {
+				return errors.New("bundle item quantity must be greater than 0")
+			}
+			units += item.Quantity
+		}
+		if units < 2 {
+			return errors.New("a bundle needs at least 2 items")
+		}
+		promotion.BuyQuantity, promotion.GetQuantity, promotion.GetDiscountPercent = 0, 0, 0
+		promotion.Tiers = nil
+	case entity.PromotionTypeTiered:
+		if len(promotion.Tiers) == 0 {
+			return errors.New("at least one tier is required")
+		}
+		sort.Slice(promotion.Tiers, func(i, j int) bool {
+			return promotion.Tiers[i].MinSpend < promotion.Tiers[j].MinSpend
+		})
+		for i, tier := range promotion.Tiers {
+			if tier.MinSpend <= 0 {
+				return errors.New("tier minimum spend must be greater than 0")
+			}
+			if i > 0 && tier.MinSpend == promotion.Tiers[i-1].MinSpend {
+				return errors.New("tiers must have different minimum spends")
+			}
+			if tier.DiscountPercent <= 0 || tier.DiscountPercent > 100 {
+				return errors.New("tier discount must be between 0 and 100 percent")
+			}
+			if tier.MaxDiscount < 0 {
+				return errors.New("tier maximum discount cannot be negative")
+			}
+		}
+		promotion.BuyQuantity, promotion.GetQuantity, promotion.GetDiscountPercent = 0, 0, 0
+		promotion.BundlePrice = 0
+		promotion.BundleItems = nil
+	default:
+		return errors.New("invalid promotion type")
+	}
+
+	return nil
+}
+
+// evaluatePromotions applies promotions in order to the lines and allocates
+// the discounts to the lines they were earned on. Unit prices and the
+// discount each unit gets are rounded to whole rupiah, so the discounted
+// prices add up to the total charged.
+func evaluatePromotions(promotions []*entity.Promotion, lines []promotionLine) *usecase.PromotionResult {
+	var units []promotionUnit
+	for i := range lines {
+		lines[i].unitPrice = roundPrice(lines[i].unitPrice)
+	}
+	for i, line := range lines {
+		for n := 0; n < line.quantity; n++ {
+			units = append(units, promotionUnit{line: i, price: line.unitPrice})
+		}
+	}
+
+	result := &usecase.PromotionResult{
+		Lines:      make([]usecase.PromotionLineDiscount, 0, len(lines)),
+		Promotions: []usecase.AppliedPromotion{},
+	}
+
+	for _, promotion := range promotions {
+		var discount float64
+		switch promotion.Type {
+		case entity.PromotionTypeBuyXGetY:
+			discount = applyBuyXGetY(promotion, lines, units)
+		case entity.PromotionTypeBundle:
+			discount = applyBundle(promotion, lines, units)
+		case entity.PromotionTypeTiered:
+			discount = applyTiered(promotion, lines, units)
+		}
+		if discount = roundPrice(discount); discount > 0 {
+			result.Promotions = append(result.Promotions, usecase.AppliedPromotion{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Discount:    discount,
+			})
+		}
+	}
+
+	lineDiscounts := make([]float64, len(lines))
+	for _, unit := range units {
+		lineDiscounts[unit.line] += unit.discount
+	}
+
+	for i, line := range lines {
+		var perUnit float64
+		if line.quantity > 0 {
+			perUnit = math.Min(roundPrice(lineDiscounts[i]/float64(line.quantity)), line.unitPrice)
+		}
+		result.Lines = append(result.Lines, usecase.PromotionLineDiscount{
+			ProductID:     line.productID,
+			VariantID:     line.variantID,
+			Quantity:      line.quantity,
+			UnitPrice:     line.unitPrice,
+			DiscountPrice: perUnit,
+			FinalPrice:    roundPrice(line.unitPrice - perUnit),
+		})
+		result.Subtotal += line.unitPrice * float64(line.quantity)
+		result.Discount += perUnit * float64(line.quantity)
+	}
+
+	result.Subtotal = roundPrice(result.Subtotal)
+	result.Discount = roundPrice(result.Discount)
+	result.Total = roundPrice(result.Subtotal - result.Discount)
+	return result
+}
+
+// applyBuyXGetY groups the eligible units from most to least expensive and
+// discounts the cheapest GetQuantity units of every full group
+func applyBuyXGetY(promotion *entity.Promotion, lines []promotionLine, units []promotionUnit) float64 {
+	var eligible []*promotionUnit
+	for i := range units {
+		if !units[i].consumed && promotionApplies(promotion, lines[units[i].line]) {
+			eligible = append(eligible, &units[i])
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return eligible[i].net() > eligible[j].net()
+	})
+
+	group := promotion.BuyQuantity + promotion.GetQuantity
+	total := 0.0
+	for start := 0; start+group <= len(eligible); start += group {
+		for i, unit := range eligible[start : start+group] {
+			unit.consumed = true
+			if i >= promotion.BuyQuantity {
+				discount := unit.net() * promotion.GetDiscountPercent / 100
+				unit.discount += discount
+				total += discount
+			}
+		}
+	}
+
+	return total
+}
+
+// applyBundle forms as many bundles as the units allow, taking the most
+// expensive matching units first, and spreads each bundle's saving over its
+// units in proportion to their prices
+func applyBundle(promotion *entity.Promotion, lines []promotionLine, units []promotionUnit) float64 {
+	total := 0.0
+	for {
+		var picked []*promotionUnit
+		taken := make(map[*promotionUnit]bool)
+		for _, component := range promotion.BundleItems {
+			var candidates []*promotionUnit
+			for i := range units {
+				unit := &units[i]
+				if !unit.consumed && !taken[unit] && bundleItemMatches(component, lines[unit.line]) {
+					candidates = append(candidates, unit)
+				}
+			}
+			if len(candidates) < component.Quantity {
+				return total
+			}
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].net() > candidates[j].net()
+			})
+			for _, unit := range candidates[:component.Quantity] {
+				taken[unit] = true
+				picked = append(picked, unit)
+			}
+		}
+
+		value := 0.0
+		for _, unit := range picked {
+			value += unit.net()
+		}
+		if value <= promotion.BundlePrice {
+			return total
+		}
+
+		saving := value - promotion.BundlePrice
+		for _, unit := range picked {
+			unit.discount += saving * unit.net() / value
+			unit.consumed = true
+		}
+		total += saving
+	}
+}
+
+// applyTiered discounts the eligible units by the highest tier their
+// remaining value reaches, spread in proportion to each unit's value
+func applyTiered(promotion *entity.Promotion, lines []promotionLine, units []promotionUnit) float64 {
+	var eligible []*promotionUnit
+	spend := 0.0
+	for i := range units {
+		if promotionApplies(promotion, lines[units[i].line]) {
+			eligible = append(eligible, &units[i])
+			spend += units[i].net()
+		}
+	}
+
+	var tier *entity.PromotionTier
+	for i := range promotion.Tiers {
+		if spend >= promotion.Tiers[i].MinSpend && (tier == nil || promotion.Tiers[i].MinSpend > tier.MinSpend) {
+			tier = &promotion.Tiers[i]
+		}
+	}
+	if tier == nil || spend <= 0 {
+		return 0
+	}
+
+	discount := spend * tier.DiscountPercent / 100
+	if tier.MaxDiscount > 0 {
+		discount = math.Min(discount, tier.MaxDiscount)
+	}
+
+	for _, unit := range eligible {
+		unit.discount += discount * unit.net() / spend
+	}
+
+	return discount
+}
+
+// promotionApplies reports whether a line is within a promotion's scope
+func promotionApplies(promotion *entity.Promotion, line promotionLine) bool {
+	if len(promotion.Products) == 0 && len(promotion.Categories) == 0 {
+		return true
+	}
+	for _, product := range promotion.Products {
+		if product.ID == line.productID {
+			return true
+		}
+	}
+	for _, category := range promotion.Categories {
+		if category.ID == line.categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleItemMatches reports whether a line can fill a bundle component
+func bundleItemMatches(item entity.PromotionBundleItem, line promotionLine) bool {
+	if item.ProductID != nil {
+		return *item.ProductID == line.productID
+	}
+	return item.CategoryID != nil && *item.CategoryID == line.categoryID
+}
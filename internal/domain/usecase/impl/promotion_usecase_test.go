@@ -0,0 +1,34 @@
+package impl
+
+import (
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestEvaluatePromotionsKeepsWholeRupiah(t *testing.T) {
+	promotions := []*entity.Promotion{{
+		ID: 1, Name: "Spend more, save more", Type: entity.PromotionTypeTiered,
+		Tiers: []entity.PromotionTier{{MinSpend: 50000, DiscountPercent: 15}},
+	}}
+	lines := []promotionLine{
+		{productID: 1, variantID: 10, unitPrice: 33333.4, quantity: 3},
+		{productID: 2, variantID: 20, unitPrice: 19999, quantity: 1},
+	}
+
+	result := evaluatePromotions(promotions, lines)
+
+	total := 0.0
+	for _, line := range result.Lines {
+		if line.FinalPrice != float64(int64(line.FinalPrice)) || line.DiscountPrice != float64(int64(line.DiscountPrice)) {
+			t.Errorf("line %d: final price %.2f, discount %.2f, want whole rupiah", line.VariantID, line.FinalPrice, line.DiscountPrice)
+		}
+		total += line.FinalPrice * float64(line.Quantity)
+	}
+	if total != result.Total {
+		t.Errorf("lines add up to %.2f, want the total %.2f", total, result.Total)
+	}
+	if result.Subtotal != 119998 {
+		t.Errorf("subtotal = %.2f, want 119998", result.Subtotal)
+	}
+}
@@ -0,0 +1,490 @@
+package impl
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+)
+
+// maxSettlementRows caps the size of an imported settlement report
+const maxSettlementRows = 50000
+
+// settlementColumns maps the accepted settlement report headers to the fields they hold
+var settlementColumns = map[string]string{
+	"transaction_id":     "transaction_id",
+	"order_id":           "order_number",
+	"order_number":       "order_number",
+	"gross_amount":       "amount",
+	"amount":             "amount",
+	"transaction_status": "status",
+	"status":             "status",
+}
+
+// statusLookupAttempts is how often a gateway status lookup is tried before a check gives up
+const statusLookupAttempts = 3
+
+// statusLookupRetryDelay is the pause before a failed status lookup is tried again
+var statusLookupRetryDelay = 2 * time.Second
+
+// settledReportStatuses are the report statuses that mean the money was received
+var settledReportStatuses = []string{"settlement", "capture", "success", "settled", "paid"}
+
+// settlementRow is one transaction read from a settlement report
+type settlementRow struct {
+	TransactionID string
+	OrderNumber   string
+	Amount        float64
+	Status        string
+}
+
+type reconciliationUseCase struct {
+	paymentRepo        repository.PaymentRepository
+	orderRepo          repository.OrderRepository
+	reconciliationRepo repository.ReconciliationRepository
+	gateways           *gateway.Registry
+}
+
+// NewReconciliationUseCase creates a new ReconciliationUseCase instance
+func NewReconciliationUseCase(
+	paymentRepo repository.PaymentRepository,
+	orderRepo repository.OrderRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	gateways *gateway.Registry,
+) usecase.ReconciliationUseCase {
+	return &reconciliationUseCase{
+		paymentRepo:        paymentRepo,
+		orderRepo:          orderRepo,
+		reconciliationRepo: reconciliationRepo,
+		gateways:           gateways,
+	}
+}
+
+// ImportSettlementReport reconciles a gateway settlement CSV against the
+// payments paid in the given period (admin function)
+func (uc *reconciliationUseCase) ImportSettlementReport(ctx context.Context, provider, filename string, report io.Reader, start, end time.Time, importedBy uint) (*entity.ReconciliationRun, error) {
+	if !end.After(start) {
+		return nil, errors.New("end of period must be after its start")
+	}
+	if _, err := uc.gateways.Get(provider); err != nil {
+		return nil, err
+	}
+
+	rows, err := parseSettlementReport(report)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &entity.ReconciliationRun{
+		Provider:    provider,
+		Source:      entity.ReconciliationSourceSettlementReport,
+		Filename:    filename,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		CreatedBy:   &importedBy,
+	}
+
+	payments, err := uc.findPayments(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	settled := make(map[uint]bool)
+	for _, row := range rows {
+		item := matchSettlementRow(provider, row, payments.find(row), settled)
+		run.Items = append(run.Items, *item)
+	}
+
+	// Every payment paid in the period should have appeared in the report
+	paid, err := uc.paymentRepo.GetPaidBetween(ctx, provider, start, end)
+	if err != nil {
+		return nil, err
+	}
+	orderNumbers, err := uc.orderNumbers(ctx, paid)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range paid {
+		if settled[payment.ID] {
+			continue
+		}
+		item := newReconciliationItem(payment)
+		item.OrderNumber = orderNumbers[payment.OrderID]
+		item.Status = entity.ReconciliationItemStatusMissing
+		item.Message = "payment is not in the settlement report"
+		run.Items = append(run.Items, *item)
+	}
+
+	if err := uc.saveRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// RunStatusCheck compares the payments created in the given period with the
+// status the gateway reports for them. A lookup that keeps failing for another
+// reason than an unknown transaction fails the whole check, which can then be
+// run again (admin function).
+func (uc *reconciliationUseCase) RunStatusCheck(ctx context.Context, provider string, start, end time.Time, requestedBy *uint) (*entity.ReconciliationRun, error) {
+	if !end.After(start) {
+		return nil, errors.New("end of period must be after its start")
+	}
+	if provider != "" {
+		if _, err := uc.gateways.Get(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	payments, err := uc.paymentRepo.GetCreatedBetween(ctx, provider, start, end)
+	if err != nil {
+		return nil, err
+	}
+	orderNumbers, err := uc.orderNumbers(ctx, payments)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &entity.ReconciliationRun{
+		Provider:    provider,
+		Source:      entity.ReconciliationSourceStatusCheck,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		CreatedBy:   requestedBy,
+	}
+
+	for _, payment := range payments {
+		gw, err := uc.gateways.Get(payment.Provider)
+		if err != nil {
+			// Cash-on-delivery, store credit and retired gateways have nothing to check against
+			continue
+		}
+
+		item := newReconciliationItem(payment)
+		item.OrderNumber = orderNumbers[payment.OrderID]
+
+		transaction, err := lookupTransaction(ctx, gw, payment)
+		if err != nil && !errors.Is(err, gateway.ErrTransactionNotFound) {
+			return nil, fmt.Errorf("%w: status of payment %d: %v", usecase.ErrGatewayUnavailable, payment.ID, err)
+		}
+		if err != nil {
+			if !isSettledStatus(payment.Status) {
+				// Hosted payments the customer never opened are unknown to the gateway
+				continue
+			}
+			item.Status = entity.ReconciliationItemStatusMissing
+			item.Message = "gateway has no transaction for this payment"
+			run.Items = append(run.Items, *item)
+			continue
+		}
+
+		item.TransactionID = transaction.TransactionID
+		item.GatewayAmount = transaction.Amount
+		item.GatewayStatus = transaction.RawStatus
+
+		switch {
+		case isSettledStatus(payment.Status) && !isSettledStatus(transaction.Status):
+			item.Status = entity.ReconciliationItemStatusMissing
+			item.Message = fmt.Sprintf("payment is %s but the gateway reports %s", payment.Status, transaction.Status)
+		case transaction.Status != payment.Status:
+			item.Status = entity.ReconciliationItemStatusStatusMismatch
+			item.Message = fmt.Sprintf("payment is %s but the gateway reports %s", payment.Status, transaction.Status)
+		case math.Abs(transaction.Amount-payment.Amount) > 0.01:
+			item.Status = entity.ReconciliationItemStatusAmountMismatch
+			item.Message = fmt.Sprintf("gateway amount %.2f differs from payment amount %.2f", transaction.Amount, payment.Amount)
+		default:
+			item.Status = entity.ReconciliationItemStatusMatched
+		}
+
+		run.Items = append(run.Items, *item)
+	}
+
+	if err := uc.saveRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// RunScheduledStatusCheck checks the payments created since the end of the
+// previous scheduled check up to the start of today, so late notifications
+// have arrived. A late run catches up on every day it missed and a repeated
+// run finds nothing to do, in which case it returns a nil run.
+func (uc *reconciliationUseCase) RunScheduledStatusCheck(ctx context.Context) (*entity.ReconciliationRun, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -1)
+
+	last, err := uc.reconciliationRepo.GetLastScheduledEnd(ctx, entity.ReconciliationSourceStatusCheck)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		start = *last
+	}
+	if !end.After(start) {
+		return nil, nil
+	}
+
+	return uc.RunStatusCheck(ctx, "", start, end, nil)
+}
+
+// GetRun gets a reconciliation run with its items (admin function)
+func (uc *reconciliationUseCase) GetRun(ctx context.Context, id uint, itemStatus string) (*entity.ReconciliationRun, error) {
+	return uc.reconciliationRepo.GetRunByID(ctx, id, itemStatus)
+}
+
+// ListRuns lists reconciliation runs (admin function)
+func (uc *reconciliationUseCase) ListRuns(ctx context.Context, page, limit int) ([]*entity.ReconciliationRun, int64, error) {
+	offset := (page - 1) * limit
+	return uc.reconciliationRepo.ListRuns(ctx, offset, limit)
+}
+
+// ListRunsByCursor lists reconciliation runs, one page after or before a cursor (admin function)
+func (uc *reconciliationUseCase) ListRunsByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.ReconciliationRun, *entity.PageCursors, error) {
+	return uc.reconciliationRepo.ListRunsByCursor(ctx, page)
+}
+
+// matchSettlementRow compares a settlement row with the payment it refers to,
+// which is nil when there is none
+func matchSettlementRow(provider string, row *settlementRow, payment *entity.Payment, settled map[uint]bool) *entity.ReconciliationItem {
+	item := &entity.ReconciliationItem{
+		OrderNumber:   row.OrderNumber,
+		TransactionID: row.TransactionID,
+		GatewayAmount: row.Amount,
+		GatewayStatus: row.Status,
+	}
+
+	if payment == nil {
+		item.Status = entity.ReconciliationItemStatusOrphan
+		item.Message = "no payment matches this transaction"
+		return item
+	}
+
+	item.PaymentID = &payment.ID
+	item.LocalAmount = payment.Amount
+	item.LocalStatus = string(payment.Status)
+
+	switch {
+	case payment.Provider != provider:
+		item.Status = entity.ReconciliationItemStatusOrphan
+		item.Message = fmt.Sprintf("matching payment was made through %s", payment.Provider)
+	case settled[payment.ID]:
+		item.Status = entity.ReconciliationItemStatusOrphan
+		item.Message = "payment was already settled by another row in this report"
+	case !isSettledStatus(payment.Status):
+		item.Status = entity.ReconciliationItemStatusStatusMismatch
+		item.Message = fmt.Sprintf("gateway settled a payment that is %s", payment.Status)
+	case math.Abs(row.Amount-payment.Amount) > 0.01:
+		item.Status = entity.ReconciliationItemStatusAmountMismatch
+		item.Message = fmt.Sprintf("settled amount %.2f differs from payment amount %.2f", row.Amount, payment.Amount)
+	default:
+		item.Status = entity.ReconciliationItemStatusMatched
+	}
+	settled[payment.ID] = true
+
+	return item
+}
+
+// settlementPayments holds the payments the rows of a settlement report refer to
+type settlementPayments struct {
+	byTransactionID  map[string]*entity.Payment
+	byGatewayOrderID map[string]*entity.Payment
+}
+
+// find finds the payment a settlement row refers to by transaction ID, then by
+// the order_id it was charged under, or nil when there is none
+func (p *settlementPayments) find(row *settlementRow) *entity.Payment {
+	if payment, ok := p.byTransactionID[row.TransactionID]; ok && row.TransactionID != "" {
+		return payment
+	}
+	if payment, ok := p.byGatewayOrderID[row.OrderNumber]; ok && row.OrderNumber != "" {
+		return payment
+	}
+	return nil
+}
+
+// findPayments loads the payments the rows of a settlement report refer to in
+// two queries. Reports list the order_id the payment was charged under, which
+// is the order number for first attempts.
+func (uc *reconciliationUseCase) findPayments(ctx context.Context, rows []*settlementRow) (*settlementPayments, error) {
+	var transactionIDs, gatewayOrderIDs []string
+	for _, row := range rows {
+		if row.TransactionID != "" {
+			transactionIDs = append(transactionIDs, row.TransactionID)
+		}
+		if row.OrderNumber != "" {
+			gatewayOrderIDs = append(gatewayOrderIDs, row.OrderNumber)
+		}
+	}
+
+	payments := &settlementPayments{
+		byTransactionID:  make(map[string]*entity.Payment),
+		byGatewayOrderID: make(map[string]*entity.Payment),
+	}
+
+	byTransaction, err := uc.paymentRepo.GetByTransactionIDs(ctx, transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range byTransaction {
+		payments.byTransactionID[payment.TransactionID] = payment
+	}
+
+	byGatewayOrder, err := uc.paymentRepo.GetByGatewayOrderIDs(ctx, gatewayOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range byGatewayOrder {
+		payments.byGatewayOrderID[payment.GatewayOrderID] = payment
+	}
+
+	return payments, nil
+}
+
+// orderNumbers loads the order numbers of payments, keyed by order ID
+func (uc *reconciliationUseCase) orderNumbers(ctx context.Context, payments []*entity.Payment) (map[uint]string, error) {
+	ids := make([]uint, 0, len(payments))
+	for _, payment := range payments {
+		ids = append(ids, payment.OrderID)
+	}
+	return uc.orderRepo.GetOrderNumbers(ctx, ids)
+}
+
+// lookupTransaction asks the gateway for the status of a payment, trying again
+// after transport and provider failures. An unknown transaction is not retried.
+func lookupTransaction(ctx context.Context, gw gateway.PaymentGateway, payment *entity.Payment) (*gateway.Transaction, error) {
+	for attempt := 1; ; attempt++ {
+		transaction, err := gw.GetTransaction(ctx, payment.GatewayOrderID, payment.TransactionID)
+		if err == nil || errors.Is(err, gateway.ErrTransactionNotFound) || attempt == statusLookupAttempts {
+			return transaction, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(statusLookupRetryDelay):
+		}
+	}
+}
+
+// saveRun tallies the items of a run and stores it
+func (uc *reconciliationUseCase) saveRun(ctx context.Context, run *entity.ReconciliationRun) error {
+	run.TotalItems = len(run.Items)
+	for _, item := range run.Items {
+		switch item.Status {
+		case entity.ReconciliationItemStatusMatched:
+			run.MatchedItems++
+		case entity.ReconciliationItemStatusAmountMismatch:
+			run.AmountMismatches++
+		case entity.ReconciliationItemStatusStatusMismatch:
+			run.StatusMismatches++
+		case entity.ReconciliationItemStatusMissing:
+			run.MissingPayments++
+		case entity.ReconciliationItemStatusOrphan:
+			run.OrphanTransactions++
+		}
+	}
+	return uc.reconciliationRepo.CreateRun(ctx, run)
+}
+
+// newReconciliationItem creates a reconciliation item describing a local payment
+func newReconciliationItem(payment *entity.Payment) *entity.ReconciliationItem {
+	return &entity.ReconciliationItem{
+		PaymentID:     &payment.ID,
+		TransactionID: payment.TransactionID,
+		LocalAmount:   payment.Amount,
+		LocalStatus:   string(payment.Status),
+	}
+}
+
+// isSettledStatus reports whether a payment status means the money was received
+func isSettledStatus(status entity.PaymentStatus) bool {
+	return status == entity.PaymentStatusPaid || isRefundStatus(status)
+}
+
+// parseSettlementReport reads the settled transactions of a settlement CSV.
+// The header row must name a transaction ID or order ID column and an amount
+// column; rows with a status other than a settled one are skipped.
+func parseSettlementReport(report io.Reader) ([]*settlementRow, error) {
+	reader := csv.NewReader(report)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("settlement report is empty or not a CSV file")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+		if field, ok := settlementColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+
+	_, hasTransaction := columns["transaction_id"]
+	_, hasOrder := columns["order_number"]
+	if _, ok := columns["amount"]; !ok || (!hasTransaction && !hasOrder) {
+		return nil, errors.New("settlement report needs an amount column and a transaction_id or order_id column")
+	}
+
+	var rows []*settlementRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+		if len(rows) >= maxSettlementRows {
+			return nil, fmt.Errorf("settlement report has more than %d rows", maxSettlementRows)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := &settlementRow{
+			TransactionID: field("transaction_id"),
+			OrderNumber:   field("order_number"),
+			Status:        strings.ToLower(field("status")),
+		}
+		if row.TransactionID == "" && row.OrderNumber == "" {
+			// Skip blank and summary rows
+			continue
+		}
+		if row.Status != "" && !containsString(settledReportStatuses, row.Status) {
+			continue
+		}
+
+		amount, err := parseAmount(field("amount"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount %q", line, field("amount"))
+		}
+		row.Amount = amount
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("settlement report has no settled rows")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,135 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/gateway"
+)
+
+// fakeReconciliationRepo keeps created runs in memory
+type fakeReconciliationRepo struct {
+	repository.ReconciliationRepository
+	runs          []*entity.ReconciliationRun
+	lastScheduled *time.Time
+}
+
+func (r *fakeReconciliationRepo) CreateRun(ctx context.Context, run *entity.ReconciliationRun) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *fakeReconciliationRepo) GetLastScheduledEnd(ctx context.Context, source entity.ReconciliationSource) (*time.Time, error) {
+	return r.lastScheduled, nil
+}
+
+// newTestReconciliationUseCase creates a reconciliation use case over one paid
+// payment of order ORD-1 created yesterday
+func newTestReconciliationUseCase(gw *stubGateway) (*reconciliationUseCase, *fakePaymentRepo, *fakeReconciliationRepo) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	payments := newFakePaymentRepo(&entity.Payment{
+		ID: 1, OrderID: 1, Provider: gw.name, Amount: 100000, Status: entity.PaymentStatusPaid,
+		TransactionID: "tx-1", GatewayOrderID: "ORD-1", PaidAt: &yesterday, CreatedAt: yesterday,
+	})
+	runs := &fakeReconciliationRepo{}
+	uc := &reconciliationUseCase{
+		paymentRepo:        payments,
+		orderRepo:          newFakeOrderRepo(&entity.Order{ID: 1, OrderNumber: "ORD-1"}),
+		reconciliationRepo: runs,
+		gateways:           newStubRegistry(gw),
+	}
+	return uc, payments, runs
+}
+
+func TestRunStatusCheckRetriesTransportErrors(t *testing.T) {
+	statusLookupRetryDelay = 0
+	gw := &stubGateway{name: "stub", lookupErr: errors.New("dial tcp: i/o timeout")}
+	uc, _, runs := newTestReconciliationUseCase(gw)
+
+	now := time.Now()
+	_, err := uc.RunStatusCheck(context.Background(), "", now.AddDate(0, 0, -2), now, nil)
+	if !errors.Is(err, usecase.ErrGatewayUnavailable) {
+		t.Fatalf("RunStatusCheck() error = %v, want ErrGatewayUnavailable", err)
+	}
+	if gw.lookups != statusLookupAttempts {
+		t.Errorf("lookups = %d, want %d", gw.lookups, statusLookupAttempts)
+	}
+	if len(runs.runs) != 0 {
+		t.Errorf("a run was stored although the gateway could not be reached")
+	}
+}
+
+func TestRunStatusCheckReportsUnknownTransactionAsMissing(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, _, _ := newTestReconciliationUseCase(gw)
+
+	now := time.Now()
+	run, err := uc.RunStatusCheck(context.Background(), "", now.AddDate(0, 0, -2), now, nil)
+	if err != nil {
+		t.Fatalf("RunStatusCheck() error = %v", err)
+	}
+	if run.MissingPayments != 1 || run.Items[0].OrderNumber != "ORD-1" {
+		t.Fatalf("run = %+v, want one missing payment of ORD-1", run)
+	}
+	if gw.lookups != 1 {
+		t.Errorf("lookups = %d, an unknown transaction must not be retried", gw.lookups)
+	}
+}
+
+func TestRunScheduledStatusCheckContinuesFromTheLastRun(t *testing.T) {
+	gw := &stubGateway{name: "stub", transaction: &gateway.Transaction{
+		OrderID: "ORD-1", TransactionID: "tx-1", Status: entity.PaymentStatusPaid, Amount: 100000,
+	}}
+	uc, _, runs := newTestReconciliationUseCase(gw)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	threeDaysAgo := today.AddDate(0, 0, -3)
+	runs.lastScheduled = &threeDaysAgo
+
+	run, err := uc.RunScheduledStatusCheck(context.Background())
+	if err != nil {
+		t.Fatalf("RunScheduledStatusCheck() error = %v", err)
+	}
+	if !run.PeriodStart.Equal(threeDaysAgo) || !run.PeriodEnd.Equal(today) {
+		t.Fatalf("period = %s - %s, want %s - %s", run.PeriodStart, run.PeriodEnd, threeDaysAgo, today)
+	}
+
+	// The next run on the same day has nothing left to check
+	runs.lastScheduled = &run.PeriodEnd
+	run, err = uc.RunScheduledStatusCheck(context.Background())
+	if err != nil || run != nil {
+		t.Fatalf("repeated RunScheduledStatusCheck() = %v, %v, want nothing to do", run, err)
+	}
+}
+
+func TestImportSettlementReportLooksUpPaymentsInBatches(t *testing.T) {
+	gw := &stubGateway{name: "stub"}
+	uc, payments, _ := newTestReconciliationUseCase(gw)
+	payments.payments[2] = &entity.Payment{
+		ID: 2, OrderID: 2, Provider: "stub", Amount: 50000, Status: entity.PaymentStatusPaid, GatewayOrderID: "ORD-2-2",
+	}
+
+	report := "transaction_id,order_id,gross_amount,transaction_status\n" +
+		"tx-1,ORD-1,100000,settlement\n" +
+		",ORD-2-2,50000,settlement\n" +
+		"tx-9,ORD-9,20000,settlement\n"
+	now := time.Now()
+	run, err := uc.ImportSettlementReport(context.Background(), "stub", "report.csv", strings.NewReader(report), now.AddDate(0, 0, -2), now, 1)
+	if err != nil {
+		t.Fatalf("ImportSettlementReport() error = %v", err)
+	}
+
+	if run.MatchedItems != 2 || run.OrphanTransactions != 1 {
+		t.Fatalf("matched %d, orphans %d; want 2 and 1", run.MatchedItems, run.OrphanTransactions)
+	}
+	if payments.batchLookups != 2 {
+		t.Errorf("payment lookups = %d, want one per key", payments.batchLookups)
+	}
+}
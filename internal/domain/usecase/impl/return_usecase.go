@@ -0,0 +1,417 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// maxReturnPhotos caps the number of photos attached to a return request
+const maxReturnPhotos = 5
+
+type returnUseCase struct {
+	returnRepo          repository.ReturnRepository
+	orderRepo           repository.OrderRepository
+	orderItemRepo       repository.OrderItemRepository
+	paymentRepo         repository.PaymentRepository
+	voucherRepo         repository.VoucherRepository
+	paymentUseCase      usecase.PaymentUseCase
+	notificationUseCase usecase.NotificationUseCase
+	returnWindow        time.Duration
+}
+
+// NewReturnUseCase creates a new ReturnUseCase instance
+func NewReturnUseCase(
+	returnRepo repository.ReturnRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	paymentRepo repository.PaymentRepository,
+	voucherRepo repository.VoucherRepository,
+	paymentUseCase usecase.PaymentUseCase,
+	notificationUseCase usecase.NotificationUseCase,
+	returnWindow time.Duration,
+) usecase.ReturnUseCase {
+	return &returnUseCase{
+		returnRepo:          returnRepo,
+		orderRepo:           orderRepo,
+		orderItemRepo:       orderItemRepo,
+		paymentRepo:         paymentRepo,
+		voucherRepo:         voucherRepo,
+		paymentUseCase:      paymentUseCase,
+		notificationUseCase: notificationUseCase,
+		returnWindow:        returnWindow,
+	}
+}
+
+// CreateReturn requests the return of items from a delivered order
+func (uc *returnUseCase) CreateReturn(ctx context.Context, userID, orderID uint, items []usecase.ReturnItemRequest, reason entity.ReturnReason, description string, photoURLs []string) (*entity.ReturnRequest, error) {
+	if len(items) == 0 {
+		return nil, errors.New("at least one item must be returned")
+	}
+	if len(photoURLs) > maxReturnPhotos {
+		return nil, fmt.Errorf("at most %d photos can be attached", maxReturnPhotos)
+	}
+
+	order, err := checkAfterSalesEligibility(ctx, uc.orderRepo, uc.paymentRepo, orderID, userID, uc.returnWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	orderItems, err := uc.orderItemRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := uc.itemDiscount(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := uc.returnRepo.NextRMASequence(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &entity.ReturnRequest{
+		RMANumber:   fmt.Sprintf("RMA-%s-%d", order.OrderNumber, sequence),
+		OrderID:     order.ID,
+		UserID:      userID,
+		Status:      entity.ReturnStatusRequested,
+		Reason:      reason,
+		Description: description,
+	}
+
+	requested := make(map[uint]bool)
+	for _, item := range items {
+		if requested[item.OrderItemID] {
+			return nil, fmt.Errorf("order item %d is listed more than once", item.OrderItemID)
+		}
+		requested[item.OrderItemID] = true
+
+		orderItem := findOrderItem(orderItems, item.OrderItemID)
+		if orderItem == nil {
+			return nil, fmt.Errorf("order item %d is not part of this order", item.OrderItemID)
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity of %s must be positive", orderItem.ProductName)
+		}
+
+		// The order's discount is spread over its items by price, so the
+		// items returned get back only what was paid for them
+		amount := orderItem.FinalPrice * float64(item.Quantity)
+		if order.TotalAmount > 0 {
+			amount = roundPrice(amount * (1 - discount/order.TotalAmount))
+		}
+		ret.Items = append(ret.Items, entity.ReturnItem{
+			OrderItemID:  orderItem.ID,
+			VariantID:    orderItem.VariantID,
+			ProductName:  orderItem.ProductName,
+			Quantity:     item.Quantity,
+			RefundAmount: amount,
+		})
+		ret.RefundAmount += amount
+	}
+
+	for _, url := range photoURLs {
+		ret.Photos = append(ret.Photos, entity.ReturnPhoto{URL: url})
+	}
+
+	check := func(returns []*entity.ReturnRequest, exchanges []*entity.ExchangeRequest) error {
+		returned := afterSalesQuantities(returns, exchanges)
+		for _, item := range ret.Items {
+			if err := checkReturnable(findOrderItem(orderItems, item.OrderItemID), returned, item.Quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := uc.returnRepo.Create(ctx, ret, check); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// itemDiscount returns the part of an order's discount that was taken off its
+// items, such as a voucher or redeemed loyalty points, leaving out what a
+// voucher took off the shipping cost
+func (uc *returnUseCase) itemDiscount(ctx context.Context, order *entity.Order) (float64, error) {
+	discount := order.DiscountAmount
+	if order.VoucherCode != "" {
+		redemption, err := uc.voucherRepo.GetRedemption(ctx, order.ID)
+		if err != nil && !errors.Is(err, repository.ErrVoucherRedemptionNotFound) {
+			return 0, err
+		}
+		if redemption != nil {
+			discount -= redemption.ShippingDiscount
+		}
+	}
+	return math.Min(math.Max(discount, 0), order.TotalAmount), nil
+}
+
+// GetReturnByID gets a return request of a user
+func (uc *returnUseCase) GetReturnByID(ctx context.Context, id, userID uint) (*entity.ReturnRequest, error) {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.UserID != userID {
+		return nil, errors.New("return request not found")
+	}
+	return ret, nil
+}
+
+// GetUserReturns gets the return requests of a user
+func (uc *returnUseCase) GetUserReturns(ctx context.Context, userID uint, page, limit int) ([]*entity.ReturnRequest, int64, error) {
+	offset := (page - 1) * limit
+	return uc.returnRepo.GetByUserID(ctx, userID, offset, limit)
+}
+
+// CancelReturn withdraws a return request before the items are sent back
+func (uc *returnUseCase) CancelReturn(ctx context.Context, id, userID uint) error {
+	ret, err := uc.GetReturnByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	ret.Status = entity.ReturnStatusCancelled
+	return uc.transition(ctx, ret, "only returns that have not been shipped can be cancelled",
+		entity.ReturnStatusRequested, entity.ReturnStatusApproved)
+}
+
+// SubmitReturnShipment records the courier and tracking number the customer sent the items with
+func (uc *returnUseCase) SubmitReturnShipment(ctx context.Context, id, userID uint, courier, trackingNumber string) error {
+	ret, err := uc.GetReturnByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ret.Status = entity.ReturnStatusShipped
+	ret.ReturnCourier = courier
+	ret.ReturnTrackingNumber = trackingNumber
+	ret.ShippedAt = &now
+	return uc.transition(ctx, ret, "only approved returns can be shipped", entity.ReturnStatusApproved)
+}
+
+// ListReturns lists return requests with filter and pagination (admin function)
+func (uc *returnUseCase) ListReturns(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.ReturnRequest, int64, error) {
+	offset := (page - 1) * limit
+	return uc.returnRepo.List(ctx, filter, offset, limit)
+}
+
+// ListReturnsByCursor lists return requests with filter, one page after or before a cursor (admin function)
+func (uc *returnUseCase) ListReturnsByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ReturnRequest, *entity.PageCursors, error) {
+	return uc.returnRepo.ListByCursor(ctx, filter, page)
+}
+
+// GetReturn gets any return request (admin function)
+func (uc *returnUseCase) GetReturn(ctx context.Context, id uint) (*entity.ReturnRequest, error) {
+	return uc.returnRepo.GetByID(ctx, id)
+}
+
+// ApproveReturn accepts a return request so the customer can send the items back (admin function)
+func (uc *returnUseCase) ApproveReturn(ctx context.Context, id uint, note string) error {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ret.Status = entity.ReturnStatusApproved
+	ret.AdminNote = note
+	ret.ApprovedAt = &now
+	if err := uc.transition(ctx, ret, "only requested returns can be approved", entity.ReturnStatusRequested); err != nil {
+		return err
+	}
+
+	uc.notify(ctx, ret, "Return approved",
+		fmt.Sprintf("Your return %s was approved. Please send the items back and submit the tracking number.", ret.RMANumber))
+	return nil
+}
+
+// RejectReturn declines a return request (admin function)
+func (uc *returnUseCase) RejectReturn(ctx context.Context, id uint, note string) error {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ret.Status = entity.ReturnStatusRejected
+	ret.AdminNote = note
+	if err := uc.transition(ctx, ret, "only requested returns can be rejected", entity.ReturnStatusRequested); err != nil {
+		return err
+	}
+
+	uc.notify(ctx, ret, "Return rejected",
+		fmt.Sprintf("Your return %s was rejected: %s", ret.RMANumber, note))
+	return nil
+}
+
+// ReceiveReturn records that the returned items arrived, puts them back in
+// stock and refunds the customer (admin function)
+func (uc *returnUseCase) ReceiveReturn(ctx context.Context, id uint) error {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Customers who drop items off at a store never submit a tracking number
+	now := time.Now()
+	ret.Status = entity.ReturnStatusReceived
+	ret.ReceivedAt = &now
+	applied, err := uc.returnRepo.Receive(ctx, ret, entity.ReturnStatusApproved, entity.ReturnStatusShipped)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New("only approved or shipped returns can be received")
+	}
+
+	return uc.refund(ctx, ret)
+}
+
+// RefundReturn retries the refund of a received return (admin function)
+func (uc *returnUseCase) RefundReturn(ctx context.Context, id uint) error {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ret.Status != entity.ReturnStatusReceived {
+		return errors.New("only received returns can be refunded")
+	}
+
+	return uc.refund(ctx, ret)
+}
+
+// refund refunds the approved amount of a received return. The return is
+// marked refunded first so that concurrent calls cannot refund it twice.
+func (uc *returnUseCase) refund(ctx context.Context, ret *entity.ReturnRequest) error {
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+
+	// Order discounts can make the item prices exceed what is left to refund
+	amount := math.Min(ret.RefundAmount, payment.PaidAmount()-payment.RefundedTotal())
+	if amount <= 0 {
+		return errors.New("payment has already been refunded in full")
+	}
+
+	now := time.Now()
+	ret.Status = entity.ReturnStatusRefunded
+	ret.RefundAmount = amount
+	ret.RefundedAt = &now
+	if err := uc.transition(ctx, ret, "return is already being refunded", entity.ReturnStatusReceived); err != nil {
+		return err
+	}
+
+	if err := uc.paymentUseCase.RefundPayment(ctx, payment.ID, amount, "Return "+ret.RMANumber, false); err != nil {
+		ret.Status = entity.ReturnStatusReceived
+		ret.RefundedAt = nil
+		if _, revertErr := uc.returnRepo.UpdateIfStatus(ctx, ret, entity.ReturnStatusRefunded); revertErr != nil {
+			return fmt.Errorf("refund failed: %v, and the return could not be reverted: %w", err, revertErr)
+		}
+		return fmt.Errorf("refund failed: %w", err)
+	}
+
+	uc.notify(ctx, ret, "Return refunded",
+		fmt.Sprintf("We received your return %s and refunded %.2f.", ret.RMANumber, amount))
+	return nil
+}
+
+// transition saves a return request whose status was changed, failing with
+// message when its stored status is not one of expected
+func (uc *returnUseCase) transition(ctx context.Context, ret *entity.ReturnRequest, message string, expected ...entity.ReturnStatus) error {
+	applied, err := uc.returnRepo.UpdateIfStatus(ctx, ret, expected...)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// notify tells the customer about a change to their return
+func (uc *returnUseCase) notify(ctx context.Context, ret *entity.ReturnRequest, title, message string) {
+	// The return is already updated; a failed notification must not undo that
+	_ = uc.notificationUseCase.CreateNotification(ctx, ret.UserID, entity.NotificationTypeOrder, title, message,
+		map[string]interface{}{"return_id": ret.ID, "rma_number": ret.RMANumber, "order_id": ret.OrderID},
+	)
+}
+
+// checkAfterSalesEligibility gets a user's order and checks that items of it
+// can still be returned or exchanged
+func checkAfterSalesEligibility(ctx context.Context, orderRepo repository.OrderRepository, paymentRepo repository.PaymentRepository, orderID, userID uint, window time.Duration) (*entity.Order, error) {
+	order, err := orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != entity.OrderStatusDelivered {
+		return nil, errors.New("only delivered orders can be returned or exchanged")
+	}
+	if window > 0 && order.DeliveredAt != nil && time.Since(*order.DeliveredAt) > window {
+		return nil, errors.New("the return window for this order has closed")
+	}
+
+	payment, err := paymentRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != entity.PaymentStatusPaid && payment.Status != entity.PaymentStatusPartiallyRefunded {
+		return nil, errors.New("order has not been paid")
+	}
+
+	return order, nil
+}
+
+// afterSalesQuantities sums, per order item, the quantities of returns and
+// exchanges that are still open or done
+func afterSalesQuantities(returns []*entity.ReturnRequest, exchanges []*entity.ExchangeRequest) map[uint]int {
+	quantities := make(map[uint]int)
+	for _, ret := range returns {
+		if ret.Status == entity.ReturnStatusRejected || ret.Status == entity.ReturnStatusCancelled {
+			continue
+		}
+		for _, item := range ret.Items {
+			quantities[item.OrderItemID] += item.Quantity
+		}
+	}
+	for _, exchange := range exchanges {
+		if exchange.Status == entity.ExchangeStatusRejected || exchange.Status == entity.ExchangeStatusCancelled {
+			continue
+		}
+		for _, item := range exchange.Items {
+			quantities[item.OrderItemID] += item.Quantity
+		}
+	}
+	return quantities
+}
+
+// checkReturnable checks that quantity of an order item can still be returned
+// or exchanged, given the quantities already claimed per order item
+func checkReturnable(orderItem *entity.OrderItem, claimed map[uint]int, quantity int) error {
+	returnable := orderItem.Quantity - claimed[orderItem.ID]
+	if quantity > returnable {
+		return fmt.Errorf("at most %d of %s can be returned", max(returnable, 0), orderItem.ProductName)
+	}
+	return nil
+}
+
+// findOrderItem finds an order item by ID
+func findOrderItem(items []*entity.OrderItem, id uint) *entity.OrderItem {
+	for _, item := range items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// fakeReturnRepo runs the checks of Create against the returns it keeps
+type fakeReturnRepo struct {
+	repository.ReturnRepository
+	returns  map[uint]*entity.ReturnRequest
+	sequence int64
+	restocks map[uint]int
+}
+
+func newFakeReturnRepo(returns ...*entity.ReturnRequest) *fakeReturnRepo {
+	r := &fakeReturnRepo{returns: make(map[uint]*entity.ReturnRequest), sequence: 100, restocks: make(map[uint]int)}
+	for _, ret := range returns {
+		r.returns[ret.ID] = ret
+	}
+	return r
+}
+
+func (r *fakeReturnRepo) NextRMASequence(ctx context.Context) (int64, error) {
+	r.sequence++
+	return r.sequence, nil
+}
+
+func (r *fakeReturnRepo) Create(ctx context.Context, ret *entity.ReturnRequest, check repository.AfterSalesCheck) error {
+	var returns []*entity.ReturnRequest
+	for _, existing := range r.returns {
+		if existing.OrderID == ret.OrderID {
+			returns = append(returns, existing)
+		}
+	}
+	if err := check(returns, nil); err != nil {
+		return err
+	}
+	ret.ID = uint(len(r.returns) + 1)
+	r.returns[ret.ID] = ret
+	return nil
+}
+
+func (r *fakeReturnRepo) GetByID(ctx context.Context, id uint) (*entity.ReturnRequest, error) {
+	ret, ok := r.returns[id]
+	if !ok {
+		return nil, errors.New("return request not found")
+	}
+	copied := *ret
+	return &copied, nil
+}
+
+func (r *fakeReturnRepo) UpdateIfStatus(ctx context.Context, ret *entity.ReturnRequest, expected ...entity.ReturnStatus) (bool, error) {
+	stored := r.returns[ret.ID]
+	for _, status := range expected {
+		if stored.Status == status {
+			copied := *ret
+			r.returns[ret.ID] = &copied
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeReturnRepo) Receive(ctx context.Context, ret *entity.ReturnRequest, expected ...entity.ReturnStatus) (bool, error) {
+	applied, err := r.UpdateIfStatus(ctx, ret, expected...)
+	if !applied || err != nil {
+		return applied, err
+	}
+	for _, item := range ret.Items {
+		r.restocks[item.VariantID] += item.Quantity
+	}
+	return true, nil
+}
+
+func newTestReturnUseCase(returnRepo *fakeReturnRepo, order *entity.Order, payments *fakePaymentUseCase) *returnUseCase {
+	items := &fakeOrderItemRepo{items: []*entity.OrderItem{
+		{ID: 11, OrderID: order.ID, VariantID: 7, ProductName: "Linen Shirt", Quantity: 2, FinalPrice: 100000},
+	}}
+	paymentRepo := newFakePaymentRepo(&entity.Payment{ID: 3, OrderID: order.ID, Amount: 200000, Status: entity.PaymentStatusPaid})
+	return NewReturnUseCase(returnRepo, newFakeOrderRepo(order), items, paymentRepo, &fakeVoucherRepo{},
+		payments, &fakeNotificationUseCase{}, 14*24*time.Hour).(*returnUseCase)
+}
+
+func deliveredOrder(deliveredAt time.Time) *entity.Order {
+	return &entity.Order{
+		ID:          1,
+		UserID:      5,
+		OrderNumber: "ORD-1",
+		Status:      entity.OrderStatusDelivered,
+		DeliveredAt: &deliveredAt,
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func TestCreateReturnNumbersFromSequence(t *testing.T) {
+	uc := newTestReturnUseCase(newFakeReturnRepo(), deliveredOrder(time.Now()), &fakePaymentUseCase{})
+
+	ret, err := uc.CreateReturn(context.Background(), 5, 1, []usecase.ReturnItemRequest{{OrderItemID: 11, Quantity: 1}},
+		entity.ReturnReasonDefective, "", nil)
+	if err != nil {
+		t.Fatalf("CreateReturn() error = %v", err)
+	}
+	if ret.RMANumber != "RMA-ORD-1-101" {
+		t.Errorf("RMANumber = %q, want RMA-ORD-1-101", ret.RMANumber)
+	}
+}
+
+func TestCreateReturnSpreadsOrderDiscountOverItems(t *testing.T) {
+	// Two shirts of 100000 bought with a voucher taking 100000 off the items
+	// and 10000 off the shipping cost
+	order := deliveredOrder(time.Now())
+	order.TotalAmount = 200000
+	order.ShippingCost = 15000
+	order.VoucherCode = "SETENGAH"
+	order.DiscountAmount = 110000
+	order.FinalAmount = 105000
+	uc := newTestReturnUseCase(newFakeReturnRepo(), order, &fakePaymentUseCase{})
+	uc.voucherRepo = &fakeVoucherRepo{redeemed: []*entity.VoucherRedemption{
+		{OrderID: order.ID, DiscountAmount: 100000, ShippingDiscount: 10000},
+	}}
+
+	ret, err := uc.CreateReturn(context.Background(), 5, 1, []usecase.ReturnItemRequest{{OrderItemID: 11, Quantity: 1}},
+		entity.ReturnReasonDefective, "", nil)
+	if err != nil {
+		t.Fatalf("CreateReturn() error = %v", err)
+	}
+	if ret.RefundAmount != 50000 || ret.Items[0].RefundAmount != 50000 {
+		t.Errorf("refund = %.0f, item refund %.0f, want 50000 for half the discounted items", ret.RefundAmount, ret.Items[0].RefundAmount)
+	}
+}
+
+func TestCreateReturnChecksQuantityAgainstExistingReturns(t *testing.T) {
+	existing := &entity.ReturnRequest{ID: 1, OrderID: 1, Status: entity.ReturnStatusRequested,
+		Items: []entity.ReturnItem{{OrderItemID: 11, Quantity: 1}}}
+	cancelled := &entity.ReturnRequest{ID: 2, OrderID: 1, Status: entity.ReturnStatusCancelled,
+		Items: []entity.ReturnItem{{OrderItemID: 11, Quantity: 1}}}
+	uc := newTestReturnUseCase(newFakeReturnRepo(existing, cancelled), deliveredOrder(time.Now()), &fakePaymentUseCase{})
+
+	_, err := uc.CreateReturn(context.Background(), 5, 1, []usecase.ReturnItemRequest{{OrderItemID: 11, Quantity: 2}},
+		entity.ReturnReasonDefective, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "at most 1") {
+		t.Fatalf("CreateReturn() error = %v, want at most 1 returnable", err)
+	}
+
+	if _, err := uc.CreateReturn(context.Background(), 5, 1, []usecase.ReturnItemRequest{{OrderItemID: 11, Quantity: 1}},
+		entity.ReturnReasonDefective, "", nil); err != nil {
+		t.Fatalf("CreateReturn() error = %v", err)
+	}
+}
+
+func TestCreateReturnMeasuresWindowFromDelivery(t *testing.T) {
+	// The order was edited today, but delivered a month ago
+	uc := newTestReturnUseCase(newFakeReturnRepo(), deliveredOrder(time.Now().AddDate(0, -1, 0)), &fakePaymentUseCase{})
+
+	_, err := uc.CreateReturn(context.Background(), 5, 1, []usecase.ReturnItemRequest{{OrderItemID: 11, Quantity: 1}},
+		entity.ReturnReasonDefective, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "return window") {
+		t.Fatalf("CreateReturn() error = %v, want the return window to be closed", err)
+	}
+}
+
+func TestReceiveReturnRestocksAndRefunds(t *testing.T) {
+	shipped := &entity.ReturnRequest{ID: 1, OrderID: 1, UserID: 5, Status: entity.ReturnStatusShipped, RefundAmount: 100000,
+		Items: []entity.ReturnItem{{OrderItemID: 11, VariantID: 7, Quantity: 1}}}
+	returnRepo := newFakeReturnRepo(shipped)
+	payments := &fakePaymentUseCase{}
+	uc := newTestReturnUseCase(returnRepo, deliveredOrder(time.Now()), payments)
+
+	if err := uc.ReceiveReturn(context.Background(), 1); err != nil {
+		t.Fatalf("ReceiveReturn() error = %v", err)
+	}
+	if returnRepo.restocks[7] != 1 {
+		t.Errorf("restocked %d of variant 7, want 1", returnRepo.restocks[7])
+	}
+	if len(payments.refunded) != 1 || payments.refunded[0] != 100000 {
+		t.Errorf("refunded %v, want [100000]", payments.refunded)
+	}
+
+	// A second receive must neither restock nor refund again
+	if err := uc.ReceiveReturn(context.Background(), 1); err == nil {
+		t.Fatal("ReceiveReturn() of a refunded return succeeded")
+	}
+	if returnRepo.restocks[7] != 1 || len(payments.refunded) != 1 {
+		t.Errorf("second receive restocked %d and refunded %v", returnRepo.restocks[7], payments.refunded)
+	}
+}
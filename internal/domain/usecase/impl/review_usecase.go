@@ -0,0 +1,164 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// maxReviewImages is how many photos a review can have
+const maxReviewImages = 5
+
+type reviewUseCase struct {
+	reviewRepo   repository.ReviewRepository
+	orderRepo    repository.OrderRepository
+	imageUseCase usecase.ImageUseCase
+}
+
+// NewReviewUseCase creates a new ReviewUseCase instance
+func NewReviewUseCase(
+	reviewRepo repository.ReviewRepository,
+	orderRepo repository.OrderRepository,
+	imageUseCase usecase.ImageUseCase,
+) usecase.ReviewUseCase {
+	return &reviewUseCase{
+		reviewRepo:   reviewRepo,
+		orderRepo:    orderRepo,
+		imageUseCase: imageUseCase,
+	}
+}
+
+// CreateReview reviews a product the user received in a delivered order,
+// optionally saying how its size fit
+func (uc *reviewUseCase) CreateReview(ctx context.Context, userID, productID, orderID uint, rating int, comment string, fit entity.FitFeedback, images []*multipart.FileHeader) (*entity.Review, error) {
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+	if len(images) > maxReviewImages {
+		return nil, errors.New("a review can have at most 5 images")
+	}
+	if fit != "" && !validFitFeedback(fit) {
+		return nil, errors.New("invalid fit")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != entity.OrderStatusDelivered {
+		return nil, errors.New("only delivered orders can be reviewed")
+	}
+	if !orderHasProduct(order, productID) {
+		return nil, errors.New("product is not part of this order")
+	}
+
+	review := &entity.Review{
+		ProductID: productID,
+		UserID:    userID,
+		OrderID:   orderID,
+		Rating:    rating,
+		Comment:   strings.TrimSpace(comment),
+		Fit:       fit,
+	}
+	for _, file := range images {
+		stored, err := uc.imageUseCase.StoreUpload(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		review.Images = append(review.Images, stored.URL)
+	}
+
+	if err := uc.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetReviewByID gets a review by ID
+func (uc *reviewUseCase) GetReviewByID(ctx context.Context, id uint) (*entity.Review, error) {
+	return uc.reviewRepo.GetByID(ctx, id)
+}
+
+// GetProductReviews gets the reviews of a product with pagination
+func (uc *reviewUseCase) GetProductReviews(ctx context.Context, productID uint, page, limit int) ([]*entity.Review, int64, error) {
+	offset := (page - 1) * limit
+	return uc.reviewRepo.GetByProductID(ctx, productID, offset, limit)
+}
+
+// GetProductReviewsByCursor gets the reviews of a product, one page after or before a cursor
+func (uc *reviewUseCase) GetProductReviewsByCursor(ctx context.Context, productID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error) {
+	return uc.reviewRepo.GetByProductIDByCursor(ctx, productID, page)
+}
+
+// GetUserReviews gets the reviews written by a user with pagination
+func (uc *reviewUseCase) GetUserReviews(ctx context.Context, userID uint, page, limit int) ([]*entity.Review, int64, error) {
+	offset := (page - 1) * limit
+	return uc.reviewRepo.GetByUserID(ctx, userID, offset, limit)
+}
+
+// GetUserReviewsByCursor gets the reviews written by a user, one page after or before a cursor
+func (uc *reviewUseCase) GetUserReviewsByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error) {
+	return uc.reviewRepo.GetByUserIDByCursor(ctx, userID, page)
+}
+
+// UpdateReview updates the rating and comment of the user's own review
+func (uc *reviewUseCase) UpdateReview(ctx context.Context, id, userID uint, rating int, comment string) (*entity.Review, error) {
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+
+	review, err := uc.getOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Rating = rating
+	review.Comment = strings.TrimSpace(comment)
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeleteReview deletes the user's own review
+func (uc *reviewUseCase) DeleteReview(ctx context.Context, id, userID uint) error {
+	if _, err := uc.getOwned(ctx, id, userID); err != nil {
+		return err
+	}
+	return uc.reviewRepo.Delete(ctx, id)
+}
+
+// GetAverageRating gets the average rating of a product
+func (uc *reviewUseCase) GetAverageRating(ctx context.Context, productID uint) (float64, error) {
+	return uc.reviewRepo.GetAverageRatingByProductID(ctx, productID)
+}
+
+// getOwned gets a review written by the user
+func (uc *reviewUseCase) getOwned(ctx context.Context, id, userID uint) (*entity.Review, error) {
+	review, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, errors.New("review not found")
+	}
+	return review, nil
+}
+
+// orderHasProduct reports whether an order contains a product
+func orderHasProduct(order *entity.Order, productID uint) bool {
+	for _, item := range order.OrderItems {
+		if item.ProductID == productID {
+			return true
+		}
+	}
+	return false
+}
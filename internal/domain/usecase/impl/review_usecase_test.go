@@ -0,0 +1,34 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestCreateReviewRecordsFit(t *testing.T) {
+	order := &entity.Order{
+		ID:         1,
+		UserID:     7,
+		Status:     entity.OrderStatusDelivered,
+		OrderItems: []entity.OrderItem{{ProductID: 3}},
+	}
+	reviews := &fakeReviewRepo{}
+	uc := &reviewUseCase{reviewRepo: reviews, orderRepo: newFakeOrderRepo(order)}
+
+	if _, err := uc.CreateReview(context.Background(), 7, 3, 1, 4, "", "tight", nil); err == nil {
+		t.Error("CreateReview() accepted an unknown fit")
+	}
+
+	review, err := uc.CreateReview(context.Background(), 7, 3, 1, 4, " Comfy ", entity.FitFeedbackTooLarge, nil)
+	if err != nil {
+		t.Fatalf("CreateReview() error = %v", err)
+	}
+	if review.Fit != entity.FitFeedbackTooLarge {
+		t.Errorf("fit = %q, want %q", review.Fit, entity.FitFeedbackTooLarge)
+	}
+	if len(reviews.created) != 1 {
+		t.Errorf("created %d reviews, want 1", len(reviews.created))
+	}
+}
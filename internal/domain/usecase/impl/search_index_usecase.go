@@ -0,0 +1,187 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/search"
+)
+
+const (
+	searchIndexSyncBatch    = 500 // changed products sent to the index per sync run
+	searchIndexReindexBatch = 500 // products sent to the index per reindex request
+)
+
+type searchIndexUseCase struct {
+	productSearchRepo repository.ProductSearchRepository
+	index             search.SearchIndex
+}
+
+// NewSearchIndexUseCase creates a new SearchIndexUseCase instance
+func NewSearchIndexUseCase(
+	productSearchRepo repository.ProductSearchRepository,
+	index search.SearchIndex,
+) usecase.SearchIndexUseCase {
+	return &searchIndexUseCase{
+		productSearchRepo: productSearchRepo,
+		index:             index,
+	}
+}
+
+// SearchProducts searches products in the external index and loads them from
+// the database. The index lags behind the catalog until the next sync, so the
+// total is an estimate.
+func (uc *searchIndexUseCase) SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, 0, errors.New("search keyword is required")
+	}
+
+	result, err := uc.index.Search(ctx, &search.Query{
+		Keyword: keyword,
+		Filter:  filter,
+		Sort:    sort,
+		Offset:  (page - 1) * limit,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, len(result.Hits))
+	highlights := make(map[uint]*entity.SearchHighlight, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ProductID
+		highlights[hit.ProductID] = hit.Highlight
+	}
+
+	// Products deleted or deactivated since the index was last synced are left out
+	products, err := uc.productSearchRepo.GetActiveByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, product := range products {
+		product.Highlight = highlights[product.ID]
+	}
+
+	total := max(result.Total-int64(len(ids)-len(products)), int64(len(products)))
+	return products, total, nil
+}
+
+// SyncChanges sends the products changed since the last sync to the index.
+// Products that were deleted or deactivated are removed from it.
+func (uc *searchIndexUseCase) SyncChanges(ctx context.Context) (int, error) {
+	changes, err := uc.productSearchRepo.GetIndexChanges(ctx, searchIndexSyncBatch)
+	if err != nil || len(changes) == 0 {
+		return 0, err
+	}
+
+	ids := make([]uint, len(changes))
+	for i, change := range changes {
+		ids[i] = change.ProductID
+	}
+
+	products, err := uc.productSearchRepo.GetForIndex(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	indexedAt := time.Now()
+	documents := make([]*entity.ProductSearchDocument, 0, len(products))
+	indexed := make(map[uint]bool, len(products))
+	for _, product := range products {
+		documents = append(documents, searchDocument(product, indexedAt))
+		indexed[product.ID] = true
+	}
+	var removed []uint
+	for _, id := range ids {
+		if !indexed[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	if err := uc.index.Upsert(ctx, documents); err != nil {
+		return 0, err
+	}
+	if err := uc.index.Delete(ctx, removed); err != nil {
+		return 0, err
+	}
+
+	return len(changes), uc.productSearchRepo.DeleteIndexChanges(ctx, changes)
+}
+
+// Reindex sends every active product to the index and removes all other documents
+func (uc *searchIndexUseCase) Reindex(ctx context.Context) (int, error) {
+	if err := uc.index.Setup(ctx); err != nil {
+		return 0, err
+	}
+
+	// Documents are stamped with the start time so the ones not rewritten
+	// below, and not updated by a sync meanwhile, can be told apart
+	startedAt := time.Now()
+	indexed := 0
+	var afterID uint
+	for {
+		products, err := uc.productSearchRepo.ListForIndex(ctx, afterID, searchIndexReindexBatch)
+		if err != nil {
+			return indexed, err
+		}
+		if len(products) == 0 {
+			break
+		}
+
+		documents := make([]*entity.ProductSearchDocument, len(products))
+		for i, product := range products {
+			documents[i] = searchDocument(product, startedAt)
+		}
+		if err := uc.index.Upsert(ctx, documents); err != nil {
+			return indexed, err
+		}
+
+		indexed += len(products)
+		afterID = products[len(products)-1].ID
+	}
+
+	return indexed, uc.index.DeleteIndexedBefore(ctx, startedAt)
+}
+
+// searchDocument builds the search index document of a product
+func searchDocument(product *entity.Product, indexedAt time.Time) *entity.ProductSearchDocument {
+	document := &entity.ProductSearchDocument{
+		ID:          product.ID,
+		Name:        product.Name,
+		Slug:        product.Slug,
+		Description: product.Description,
+		CategoryID:  product.CategoryID,
+		Category:    product.Category.Name,
+		Tags:        make([]string, 0, len(product.Tags)),
+		Sizes:       []string{},
+		Colors:      []string{},
+		Price:       product.SellingPrice(),
+		CreatedAt:   product.CreatedAt.Unix(),
+		IndexedAt:   indexedAt.UnixMilli(),
+	}
+	for _, tag := range product.Tags {
+		document.Tags = append(document.Tags, tag.Name)
+	}
+	for _, variant := range product.Variants {
+		if !variant.IsActive {
+			continue
+		}
+		if variant.Size != "" && !containsString(document.Sizes, variant.Size) {
+			document.Sizes = append(document.Sizes, variant.Size)
+		}
+		if variant.Color != "" && !containsString(document.Colors, variant.Color) {
+			document.Colors = append(document.Colors, variant.Color)
+		}
+		if variant.Stock > 0 {
+			document.InStock = true
+		}
+	}
+	return document
+}
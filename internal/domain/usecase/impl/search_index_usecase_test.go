@@ -0,0 +1,46 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/infrastructure/search"
+)
+
+func TestSearchIndexSearchProductsSkipsProductsDeactivatedSinceTheSync(t *testing.T) {
+	index := search.NewMemoryIndex()
+	err := index.Upsert(context.Background(), []*entity.ProductSearchDocument{
+		{ID: 1, Name: "Kemeja Linen"},
+		{ID: 2, Name: "Celana Linen"},
+		{ID: 3, Name: "Rok Linen"},
+	})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	uc := &searchIndexUseCase{
+		productSearchRepo: &fakeProductSearchRepo{products: map[uint]*entity.Product{
+			1: {ID: 1, Name: "Kemeja Linen", IsActive: true},
+			2: {ID: 2, Name: "Celana Linen", IsActive: false},
+			3: {ID: 3, Name: "Rok Linen", IsActive: true},
+		}},
+		index: index,
+	}
+
+	products, total, err := uc.SearchProducts(context.Background(), "linen", nil, "", 1, 10)
+	if err != nil {
+		t.Fatalf("SearchProducts() error = %v", err)
+	}
+
+	if len(products) != 2 || total != 2 {
+		t.Fatalf("got %d products of %d, want the 2 active ones", len(products), total)
+	}
+	for _, product := range products {
+		if !product.IsActive {
+			t.Errorf("product %d is inactive", product.ID)
+		}
+		if product.Highlight == nil || product.Highlight.Name == "" {
+			t.Errorf("product %d has no highlight", product.ID)
+		}
+	}
+}
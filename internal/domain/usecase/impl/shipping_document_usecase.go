@@ -0,0 +1,133 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/document"
+)
+
+// maxBulkShippingLabels caps how many labels can be merged into a single PDF
+const maxBulkShippingLabels = 100
+
+type shippingDocumentUseCase struct {
+	orderRepo       repository.OrderRepository
+	orderItemRepo   repository.OrderItemRepository
+	variantRepo     repository.ProductVariantRepository
+	documentService document.ShippingDocumentService
+}
+
+// NewShippingDocumentUseCase creates a new ShippingDocumentUseCase instance
+func NewShippingDocumentUseCase(
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	variantRepo repository.ProductVariantRepository,
+	documentService document.ShippingDocumentService,
+) usecase.ShippingDocumentUseCase {
+	return &shippingDocumentUseCase{
+		orderRepo:       orderRepo,
+		orderItemRepo:   orderItemRepo,
+		variantRepo:     variantRepo,
+		documentService: documentService,
+	}
+}
+
+// GetPackingSlip generates the packing slip PDF for an order (admin function)
+func (uc *shippingDocumentUseCase) GetPackingSlip(ctx context.Context, orderID uint) ([]byte, error) {
+	order, err := uc.getPrintableOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Look up SKUs for the ordered variants
+	skus := make(map[uint]string)
+	for _, item := range order.OrderItems {
+		if _, ok := skus[item.VariantID]; ok {
+			continue
+		}
+		variant, err := uc.variantRepo.GetByID(ctx, item.VariantID)
+		if err != nil {
+			// Variant may have been deleted since the order was placed
+			skus[item.VariantID] = "-"
+			continue
+		}
+		skus[item.VariantID] = variant.SKU
+	}
+
+	return uc.documentService.GeneratePackingSlip(order, skus)
+}
+
+// GetShippingLabel generates the shipping label PDF for an order (admin function)
+func (uc *shippingDocumentUseCase) GetShippingLabel(ctx context.Context, orderID uint) ([]byte, error) {
+	order, err := uc.getPrintableOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.ShippingTrackingNumber == "" {
+		return nil, errors.New("tracking number has not been set for this order")
+	}
+
+	return uc.documentService.GenerateShippingLabel(order)
+}
+
+// GetBulkShippingLabels generates shipping labels for many orders merged into one PDF (admin function)
+func (uc *shippingDocumentUseCase) GetBulkShippingLabels(ctx context.Context, orderIDs []uint) ([]byte, error) {
+	if len(orderIDs) == 0 {
+		return nil, errors.New("no orders selected")
+	}
+	if len(orderIDs) > maxBulkShippingLabels {
+		return nil, fmt.Errorf("cannot print more than %d labels at once", maxBulkShippingLabels)
+	}
+
+	seen := make(map[uint]bool)
+	orders := make([]*entity.Order, 0, len(orderIDs))
+	for _, id := range orderIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		order, err := uc.getPrintableOrder(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("order %d: %w", id, err)
+		}
+		if order.ShippingTrackingNumber == "" {
+			return nil, fmt.Errorf("order %s: tracking number has not been set", order.OrderNumber)
+		}
+		orders = append(orders, order)
+	}
+
+	return uc.documentService.GenerateShippingLabels(orders)
+}
+
+// getPrintableOrder loads an order with its items and checks that it can be shipped
+func (uc *shippingDocumentUseCase) getPrintableOrder(ctx context.Context, orderID uint) (*entity.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch order.Status {
+	case entity.OrderStatusPending:
+		return nil, errors.New("order has not been paid yet")
+	case entity.OrderStatusCancelled, entity.OrderStatusRefunded:
+		return nil, errors.New("order is " + string(order.Status))
+	}
+
+	items, err := uc.orderItemRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	order.OrderItems = make([]entity.OrderItem, 0, len(items))
+	for _, item := range items {
+		order.OrderItems = append(order.OrderItems, *item)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,39 @@
+package impl
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/usecase"
+	"fashion-shop/internal/infrastructure/third_party"
+)
+
+type shippingUseCase struct {
+	rajaOngkirService third_party.RajaOngkirService
+}
+
+// NewShippingUseCase creates a new ShippingUseCase instance
+func NewShippingUseCase(rajaOngkirService third_party.RajaOngkirService) usecase.ShippingUseCase {
+	return &shippingUseCase{
+		rajaOngkirService: rajaOngkirService,
+	}
+}
+
+// GetProvinces gets the provinces couriers ship to
+func (uc *shippingUseCase) GetProvinces(ctx context.Context) ([]map[string]interface{}, error) {
+	return uc.rajaOngkirService.GetProvinces()
+}
+
+// GetCities gets the cities of a province
+func (uc *shippingUseCase) GetCities(ctx context.Context, provinceID string) ([]map[string]interface{}, error) {
+	return uc.rajaOngkirService.GetCities(provinceID)
+}
+
+// CalculateShipping gets the shipping services and costs of a courier between two cities
+func (uc *shippingUseCase) CalculateShipping(ctx context.Context, origin, destination, weight int, courier string) ([]map[string]interface{}, error) {
+	return uc.rajaOngkirService.CalculateShipping(origin, destination, weight, courier)
+}
+
+// TrackShipment gets the delivery status of a waybill
+func (uc *shippingUseCase) TrackShipment(ctx context.Context, waybill, courier string) (map[string]interface{}, error) {
+	return uc.rajaOngkirService.TrackShipment(waybill, courier)
+}
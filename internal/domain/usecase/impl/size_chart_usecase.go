@@ -0,0 +1,413 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+const (
+	minFitFeedback     = 5    // feedback needed before a product is said to run small or large
+	fitTendencyShare   = 0.25 // lead of too small over too large feedback, or the reverse, as a share of all feedback
+	maxBodyMeasurement = 300  // in cm
+	maxBodyWeight      = 500  // in kg
+)
+
+// sizeChartMeasurements are the measurements a size chart can hold
+var sizeChartMeasurements = []string{
+	entity.MeasurementHeight,
+	entity.MeasurementChest,
+	entity.MeasurementWaist,
+	entity.MeasurementHips,
+	entity.MeasurementInseam,
+	entity.MeasurementFootLength,
+}
+
+type sizeChartUseCase struct {
+	sizeChartRepo    repository.SizeChartRepository
+	bodyProfileRepo  repository.BodyProfileRepository
+	productRepo      repository.ProductRepository
+	variantRepo      repository.ProductVariantRepository
+	categoryTreeRepo repository.CategoryTreeRepository
+	reviewRepo       repository.ReviewRepository
+}
+
+// NewSizeChartUseCase creates a new SizeChartUseCase instance
+func NewSizeChartUseCase(
+	sizeChartRepo repository.SizeChartRepository,
+	bodyProfileRepo repository.BodyProfileRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	categoryTreeRepo repository.CategoryTreeRepository,
+	reviewRepo repository.ReviewRepository,
+) usecase.SizeChartUseCase {
+	return &sizeChartUseCase{
+		sizeChartRepo:    sizeChartRepo,
+		bodyProfileRepo:  bodyProfileRepo,
+		productRepo:      productRepo,
+		variantRepo:      variantRepo,
+		categoryTreeRepo: categoryTreeRepo,
+		reviewRepo:       reviewRepo,
+	}
+}
+
+// GetProductSizeChart gets the size chart that applies to a product with the
+// size feedback customers gave on it
+func (uc *sizeChartUseCase) GetProductSizeChart(ctx context.Context, productID uint) (*entity.SizeChart, *entity.FitSummary, error) {
+	product, err := uc.getActiveProduct(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chart, err := uc.findChart(ctx, product)
+	if err != nil {
+		return nil, nil, err
+	}
+	fit, err := uc.getFitSummary(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chart, fit, nil
+}
+
+// RecommendSize suggests the size of a product whose chart best matches the
+// customer's body measurements. When customers say the product runs small or
+// large, or the customer prefers a looser or snugger fit, a customer between
+// sizes is pointed to the next size in that direction.
+func (uc *sizeChartUseCase) RecommendSize(ctx context.Context, userID, productID uint) (*entity.SizeRecommendation, error) {
+	product, err := uc.getActiveProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := uc.findChart(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := uc.bodyProfileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("add your body measurements to get a size recommendation")
+	}
+	fit, err := uc.getFitSummary(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	measurements := profile.Measurements()
+	best := -1
+	var bestDistance, bestPosition float64
+	for i, entry := range chart.Entries {
+		distance, position, matched := matchSize(entry.Measurements, measurements)
+		if matched == 0 {
+			continue
+		}
+		if best < 0 || distance < bestDistance ||
+			distance == bestDistance && math.Abs(position-0.5) < math.Abs(bestPosition-0.5) {
+			best, bestDistance, bestPosition = i, distance, position
+		}
+	}
+	if best < 0 {
+		return nil, errors.New("the size chart of this product uses none of your measurements")
+	}
+
+	index := best
+	var notes []string
+	switch fit.Tendency {
+	case entity.FitTendencyRunsSmall:
+		notes = append(notes, "Customers say this item runs small")
+	case entity.FitTendencyRunsLarge:
+		notes = append(notes, "Customers say this item runs large")
+	}
+	up := fit.Tendency == entity.FitTendencyRunsSmall || profile.FitPreference == entity.FitPreferenceLoose
+	down := fit.Tendency == entity.FitTendencyRunsLarge || profile.FitPreference == entity.FitPreferenceSnug
+	switch {
+	case up && !down && bestPosition >= 0.5 && best+1 < len(chart.Entries):
+		index++
+		notes = append(notes, "you are between sizes, so we suggest one size up")
+	case down && !up && bestPosition <= 0.5 && best > 0:
+		index--
+		notes = append(notes, "you are between sizes, so we suggest one size down")
+	}
+
+	recommendation := &entity.SizeRecommendation{
+		ProductID:  productID,
+		Size:       chart.Entries[index].Size,
+		Confidence: "low",
+		Variants:   make([]*entity.ProductVariant, 0),
+		Fit:        *fit,
+		Note:       strings.Join(notes, "; "),
+	}
+	switch {
+	case bestDistance == 0:
+		recommendation.Confidence = "high"
+	case bestDistance <= 2:
+		recommendation.Confidence = "medium"
+	}
+
+	variants, err := uc.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		if variant.IsActive && strings.EqualFold(variant.Size, recommendation.Size) {
+			recommendation.Variants = append(recommendation.Variants, variant)
+		}
+	}
+	return recommendation, nil
+}
+
+// GetBodyProfile gets the body measurements of a customer
+func (uc *sizeChartUseCase) GetBodyProfile(ctx context.Context, userID uint) (*entity.BodyProfile, error) {
+	return uc.bodyProfileRepo.GetByUserID(ctx, userID)
+}
+
+// SaveBodyProfile sets the body measurements of a customer
+func (uc *sizeChartUseCase) SaveBodyProfile(ctx context.Context, userID uint, profile *entity.BodyProfile) (*entity.BodyProfile, error) {
+	profile.UserID = userID
+	for _, value := range []*float64{profile.Height, profile.Chest, profile.Waist, profile.Hips, profile.Inseam, profile.FootLength} {
+		if value != nil && (*value <= 0 || *value > maxBodyMeasurement) {
+			return nil, fmt.Errorf("measurements must be between 0 and %d cm", maxBodyMeasurement)
+		}
+	}
+	if profile.Weight != nil && (*profile.Weight <= 0 || *profile.Weight > maxBodyWeight) {
+		return nil, fmt.Errorf("weight must be between 0 and %d kg", maxBodyWeight)
+	}
+	switch profile.FitPreference {
+	case "":
+		profile.FitPreference = entity.FitPreferenceRegular
+	case entity.FitPreferenceSnug, entity.FitPreferenceRegular, entity.FitPreferenceLoose:
+	default:
+		return nil, errors.New("invalid fit preference")
+	}
+	if len(profile.Measurements()) == 0 {
+		return nil, errors.New("at least one body measurement is required")
+	}
+
+	if err := uc.bodyProfileRepo.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+	return uc.bodyProfileRepo.GetByUserID(ctx, userID)
+}
+
+// SetReviewFit records how the size fit the author of a review
+func (uc *sizeChartUseCase) SetReviewFit(ctx context.Context, userID, reviewID uint, fit entity.FitFeedback) (*entity.Review, error) {
+	if !validFitFeedback(fit) {
+		return nil, errors.New("invalid fit")
+	}
+
+	review, err := uc.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, errors.New("review not found")
+	}
+
+	review.Fit = fit
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// CreateSizeChart creates the size chart of a product or category (admin function)
+func (uc *sizeChartUseCase) CreateSizeChart(ctx context.Context, chart *entity.SizeChart) (*entity.SizeChart, error) {
+	chart.ID = 0
+	if err := uc.validateSizeChart(ctx, chart); err != nil {
+		return nil, err
+	}
+
+	if err := uc.sizeChartRepo.Create(ctx, chart); err != nil {
+		return nil, err
+	}
+	return uc.sizeChartRepo.GetByID(ctx, chart.ID)
+}
+
+// GetSizeChart gets a size chart by ID (admin function)
+func (uc *sizeChartUseCase) GetSizeChart(ctx context.Context, id uint) (*entity.SizeChart, error) {
+	return uc.sizeChartRepo.GetByID(ctx, id)
+}
+
+// UpdateSizeChart updates a size chart and replaces its sizes (admin function)
+func (uc *sizeChartUseCase) UpdateSizeChart(ctx context.Context, id uint, chart *entity.SizeChart) (*entity.SizeChart, error) {
+	if _, err := uc.sizeChartRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	chart.ID = id
+	if err := uc.validateSizeChart(ctx, chart); err != nil {
+		return nil, err
+	}
+
+	if err := uc.sizeChartRepo.Update(ctx, chart); err != nil {
+		return nil, err
+	}
+	return uc.sizeChartRepo.GetByID(ctx, id)
+}
+
+// DeleteSizeChart deletes a size chart (admin function)
+func (uc *sizeChartUseCase) DeleteSizeChart(ctx context.Context, id uint) error {
+	if _, err := uc.sizeChartRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.sizeChartRepo.Delete(ctx, id)
+}
+
+// ListSizeCharts lists size charts (admin function)
+func (uc *sizeChartUseCase) ListSizeCharts(ctx context.Context, page, limit int) ([]*entity.SizeChart, int64, error) {
+	offset := (page - 1) * limit
+	return uc.sizeChartRepo.List(ctx, offset, limit)
+}
+
+// getActiveProduct gets a product customers can see
+func (uc *sizeChartUseCase) getActiveProduct(ctx context.Context, productID uint) (*entity.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsActive {
+		return nil, errors.New("product not found")
+	}
+	return product, nil
+}
+
+// findChart finds the size chart of a product, or of its nearest category
+func (uc *sizeChartUseCase) findChart(ctx context.Context, product *entity.Product) (*entity.SizeChart, error) {
+	ancestors, err := uc.categoryTreeRepo.GetAncestors(ctx, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make([]uint, len(ancestors))
+	for i, category := range ancestors {
+		categoryIDs[len(ancestors)-1-i] = category.ID
+	}
+	return uc.sizeChartRepo.FindForProduct(ctx, product.ID, categoryIDs)
+}
+
+// getFitSummary gets the size feedback on a product and the tendency it shows
+func (uc *sizeChartUseCase) getFitSummary(ctx context.Context, productID uint) (*entity.FitSummary, error) {
+	fit, err := uc.sizeChartRepo.GetFitSummary(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := fit.TooSmall + fit.TrueToSize + fit.TooLarge
+	if total < minFitFeedback {
+		return fit, nil
+	}
+	lead := float64(fit.TooSmall-fit.TooLarge) / float64(total)
+	switch {
+	case lead >= fitTendencyShare:
+		fit.Tendency = entity.FitTendencyRunsSmall
+	case lead <= -fitTendencyShare:
+		fit.Tendency = entity.FitTendencyRunsLarge
+	default:
+		fit.Tendency = entity.FitTendencyTrueToSize
+	}
+	return fit, nil
+}
+
+// validateSizeChart normalizes a size chart and checks it belongs to exactly
+// one existing product or category that has no other chart
+func (uc *sizeChartUseCase) validateSizeChart(ctx context.Context, chart *entity.SizeChart) error {
+	chart.Name = strings.TrimSpace(chart.Name)
+	if chart.Name == "" {
+		return errors.New("size chart name is required")
+	}
+
+	switch {
+	case chart.ProductID != nil && chart.CategoryID == nil:
+		if _, err := uc.productRepo.GetByID(ctx, *chart.ProductID); err != nil {
+			return err
+		}
+	case chart.CategoryID != nil && chart.ProductID == nil:
+		if _, err := uc.categoryTreeRepo.GetAncestors(ctx, *chart.CategoryID); err != nil {
+			return err
+		}
+	default:
+		return errors.New("a size chart belongs to either a product or a category")
+	}
+	exists, err := uc.sizeChartRepo.ExistsFor(ctx, chart.ProductID, chart.CategoryID, chart.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("this product or category already has a size chart")
+	}
+
+	if len(chart.Entries) == 0 {
+		return errors.New("at least one size is required")
+	}
+	sizes := make(map[string]bool, len(chart.Entries))
+	for i := range chart.Entries {
+		entry := &chart.Entries[i]
+		entry.Size = strings.TrimSpace(entry.Size)
+		entry.Position = i
+		if entry.Size == "" {
+			return errors.New("size is required")
+		}
+		if sizes[strings.ToLower(entry.Size)] {
+			return fmt.Errorf("size %s is listed more than once", entry.Size)
+		}
+		sizes[strings.ToLower(entry.Size)] = true
+
+		if len(entry.Measurements) == 0 {
+			return fmt.Errorf("size %s needs at least one measurement", entry.Size)
+		}
+		for name, measurement := range entry.Measurements {
+			if !containsString(sizeChartMeasurements, name) {
+				return fmt.Errorf("unknown measurement %q, use one of %s", name, strings.Join(sizeChartMeasurements, ", "))
+			}
+			if measurement.Min <= 0 || measurement.Max < measurement.Min || measurement.Max > maxBodyMeasurement {
+				return fmt.Errorf("%s of size %s must be a range between 0 and %d cm", name, entry.Size, maxBodyMeasurement)
+			}
+		}
+	}
+	return nil
+}
+
+// validFitFeedback reports whether fit is one of the known fit feedback values
+func validFitFeedback(fit entity.FitFeedback) bool {
+	switch fit {
+	case entity.FitFeedbackTooSmall, entity.FitFeedbackTrueToSize, entity.FitFeedbackTooLarge:
+		return true
+	}
+	return false
+}
+
+// matchSize compares body measurements with the ranges of a size. It returns
+// the average distance in cm outside the ranges, where the measurements sit
+// within them on average from 0 (lower end) to 1 (upper end), and how many
+// measurements were compared.
+func matchSize(ranges entity.SizeMeasurements, measurements map[string]float64) (float64, float64, int) {
+	var distance, position float64
+	matched := 0
+	for name, value := range measurements {
+		r, ok := ranges[name]
+		if !ok {
+			continue
+		}
+		matched++
+		switch {
+		case value < r.Min:
+			distance += r.Min - value
+		case value > r.Max:
+			distance += value - r.Max
+			position++
+		case r.Max > r.Min:
+			position += (value - r.Min) / (r.Max - r.Min)
+		default:
+			position += 0.5
+		}
+	}
+	if matched == 0 {
+		return 0, 0, 0
+	}
+	return distance / float64(matched), position / float64(matched), matched
+}
@@ -0,0 +1,80 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+func TestSaveBodyProfileChecksWeightInKilograms(t *testing.T) {
+	uc := &sizeChartUseCase{bodyProfileRepo: &fakeBodyProfileRepo{profiles: make(map[uint]*entity.BodyProfile)}}
+
+	// 550 kg is past the weight cap and 400 cm past the measurement cap, but
+	// 320 kg must not be held to the 300 cm cap
+	if _, err := uc.SaveBodyProfile(context.Background(), 7, &entity.BodyProfile{Height: floatPtr(180), Weight: floatPtr(550)}); err == nil {
+		t.Error("SaveBodyProfile() accepted a weight of 550 kg")
+	}
+	if _, err := uc.SaveBodyProfile(context.Background(), 7, &entity.BodyProfile{Height: floatPtr(400)}); err == nil {
+		t.Error("SaveBodyProfile() accepted a height of 400 cm")
+	}
+
+	profile, err := uc.SaveBodyProfile(context.Background(), 7, &entity.BodyProfile{Height: floatPtr(180), Weight: floatPtr(320)})
+	if err != nil {
+		t.Fatalf("SaveBodyProfile() error = %v, want a 320 kg weight accepted", err)
+	}
+	if profile.FitPreference != entity.FitPreferenceRegular {
+		t.Errorf("fit preference = %q, want %q", profile.FitPreference, entity.FitPreferenceRegular)
+	}
+}
+
+func TestSetReviewFitOnlyChangesOwnReview(t *testing.T) {
+	reviews := &fakeReviewRepo{reviews: map[uint]*entity.Review{1: {ID: 1, UserID: 7, ProductID: 1, Rating: 4}}}
+	uc := &sizeChartUseCase{reviewRepo: reviews}
+
+	if _, err := uc.SetReviewFit(context.Background(), 8, 1, entity.FitFeedbackTooSmall); err == nil {
+		t.Error("SetReviewFit() changed another customer's review")
+	}
+	if _, err := uc.SetReviewFit(context.Background(), 7, 1, "tight"); err == nil {
+		t.Error("SetReviewFit() accepted an unknown fit")
+	}
+	if reviews.reviews[1].Fit != "" {
+		t.Fatalf("fit = %q after refused updates, want none", reviews.reviews[1].Fit)
+	}
+
+	if _, err := uc.SetReviewFit(context.Background(), 7, 1, entity.FitFeedbackTooSmall); err != nil {
+		t.Fatalf("SetReviewFit() error = %v", err)
+	}
+	if reviews.reviews[1].Fit != entity.FitFeedbackTooSmall {
+		t.Errorf("fit = %q, want %q", reviews.reviews[1].Fit, entity.FitFeedbackTooSmall)
+	}
+}
+
+func TestGetFitSummaryTendency(t *testing.T) {
+	tests := []struct {
+		name string
+		fit  entity.FitSummary
+		want entity.FitTendency
+	}{
+		{"too little feedback", entity.FitSummary{TooSmall: 4}, ""},
+		{"runs small", entity.FitSummary{TooSmall: 4, TrueToSize: 4, TooLarge: 1}, entity.FitTendencyRunsSmall},
+		{"runs large", entity.FitSummary{TooSmall: 1, TrueToSize: 4, TooLarge: 4}, entity.FitTendencyRunsLarge},
+		{"true to size", entity.FitSummary{TooSmall: 2, TrueToSize: 6, TooLarge: 1}, entity.FitTendencyTrueToSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &sizeChartUseCase{sizeChartRepo: &fakeSizeChartRepo{fit: tt.fit}}
+			fit, err := uc.getFitSummary(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("getFitSummary() error = %v", err)
+			}
+			if fit.Tendency != tt.want {
+				t.Errorf("tendency = %q, want %q", fit.Tendency, tt.want)
+			}
+		})
+	}
+}
@@ -243,6 +243,21 @@ func (uc *userUseCase) GetUsers(ctx context.Context, page, limit int) ([]*entity
 	return users, count, nil
 }
 
+// GetUsersByCursor gets one page of users after or before a cursor (admin function)
+func (uc *userUseCase) GetUsersByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.User, *entity.PageCursors, error) {
+	users, cursors, err := uc.userRepo.ListByCursor(ctx, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Don't return passwords
+	for _, user := range users {
+		user.Password = ""
+	}
+
+	return users, cursors, nil
+}
+
 // GetUserByID gets a user by ID (admin function)
 func (uc *userUseCase) GetUserByID(ctx context.Context, id uint) (*entity.User, error) {
 	user, err := uc.userRepo.GetByID(ctx, id)
@@ -0,0 +1,269 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+const (
+	maxProductOptions    = 3   // options a product can vary by
+	maxVariantsPerMatrix = 100 // variants a generated matrix can hold
+	maxSKULength         = 100
+
+	// defaultSKUTemplate builds SKUs such as BASIC-TEE-M-RED
+	defaultSKUTemplate = "{product}-{options}"
+)
+
+var (
+	// skuPlaceholderPattern matches the placeholders of a SKU template
+	skuPlaceholderPattern = regexp.MustCompile(`\{([a-z0-9_]+)\}`)
+
+	// skuSeparatorPattern matches the characters replaced by a dash in SKU parts
+	skuSeparatorPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+
+	// optionPlaceholderPattern matches the characters replaced by an underscore in option placeholders
+	optionPlaceholderPattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+type variantOptionUseCase struct {
+	productRepo repository.ProductRepository
+	variantRepo repository.ProductVariantRepository
+}
+
+// NewVariantOptionUseCase creates a new VariantOptionUseCase instance
+func NewVariantOptionUseCase(
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+) usecase.VariantOptionUseCase {
+	return &variantOptionUseCase{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+	}
+}
+
+// GetOptions gets the options a product varies by with their values
+func (uc *variantOptionUseCase) GetOptions(ctx context.Context, productID uint) ([]*entity.ProductOption, error) {
+	if _, err := uc.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return uc.variantRepo.GetOptions(ctx, productID)
+}
+
+// GenerateVariants sets the options of a product and builds a variant for
+// every combination of their values. Variants of combinations that already
+// exist keep their SKU and stock; new ones get a SKU from the template, no
+// stock and the given weight. Variants of combinations no longer offered
+// are removed, or deactivated when orders refer to them (admin function).
+//
+// SKU templates may use {product} for the product slug, {id} for the product
+// ID, {options} for all option values and the option names, such as {size}
+// or {cup_size}, for a single value.
+func (uc *variantOptionUseCase) GenerateVariants(ctx context.Context, productID uint, options []*entity.ProductOption, skuTemplate string, weight float64) ([]*entity.ProductVariant, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := normalizeProductOptions(options); err != nil {
+		return nil, err
+	}
+	skuTemplate = strings.TrimSpace(skuTemplate)
+	if skuTemplate == "" {
+		skuTemplate = product.SKUTemplate
+	}
+	if skuTemplate == "" {
+		skuTemplate = defaultSKUTemplate
+	}
+	if err := validateSKUTemplate(skuTemplate, options); err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	byCombination := make(map[string]*entity.ProductVariant, len(existing))
+	for _, variant := range existing {
+		byCombination[combinationKey(variant.Options())] = variant
+	}
+	if weight <= 0 && len(existing) > 0 {
+		weight = existing[0].Weight
+	}
+
+	skus := make(map[string]bool)
+	variants := make([]*entity.ProductVariant, 0)
+	for _, combination := range optionCombinations(options) {
+		values := make(map[string]string, len(combination))
+		optionValues := make([]entity.ProductOptionValue, len(combination))
+		for i, value := range combination {
+			values[strings.ToLower(options[i].Name)] = value
+			optionValues[i] = entity.ProductOptionValue{Value: value, Option: &entity.ProductOption{Name: options[i].Name}}
+		}
+
+		variant, ok := byCombination[combinationKey(values)]
+		if !ok {
+			if weight <= 0 {
+				return nil, errors.New("weight is required for new variants")
+			}
+			sku, err := renderSKU(skuTemplate, product, options, combination)
+			if err != nil {
+				return nil, err
+			}
+			other, err := uc.variantRepo.GetBySKU(ctx, sku)
+			if err != nil && !errors.Is(err, repository.ErrProductVariantNotFound) {
+				return nil, err
+			}
+			if other != nil {
+				return nil, fmt.Errorf("SKU %s is already used by another variant", sku)
+			}
+			variant = &entity.ProductVariant{SKU: sku, Weight: weight}
+		}
+		if skus[variant.SKU] {
+			return nil, fmt.Errorf("SKU template gives more than one variant the SKU %s", variant.SKU)
+		}
+		skus[variant.SKU] = true
+
+		variant.Size = values["size"]
+		variant.Color = values["color"]
+		variant.OptionValues = optionValues
+		variants = append(variants, variant)
+	}
+
+	if err := uc.variantRepo.SaveMatrix(ctx, productID, skuTemplate, options, variants); err != nil {
+		return nil, err
+	}
+	return uc.variantRepo.GetByProductID(ctx, productID)
+}
+
+// normalizeProductOptions trims option names and values and checks they
+// form a matrix of acceptable size
+func normalizeProductOptions(options []*entity.ProductOption) error {
+	if len(options) == 0 {
+		return errors.New("at least one option is required")
+	}
+	if len(options) > maxProductOptions {
+		return fmt.Errorf("a product can have at most %d options", maxProductOptions)
+	}
+
+	names := make(map[string]bool, len(options))
+	combinations := 1
+	for i, option := range options {
+		option.Name = strings.TrimSpace(option.Name)
+		if option.Name == "" {
+			return errors.New("option name is required")
+		}
+		if names[strings.ToLower(option.Name)] {
+			return fmt.Errorf("option %s is listed more than once", option.Name)
+		}
+		names[strings.ToLower(option.Name)] = true
+		option.Position = i
+
+		seen := make(map[string]bool, len(option.Values))
+		values := make([]entity.ProductOptionValue, 0, len(option.Values))
+		for _, value := range option.Values {
+			value.Value = strings.TrimSpace(value.Value)
+			if value.Value == "" || seen[strings.ToLower(value.Value)] {
+				continue
+			}
+			seen[strings.ToLower(value.Value)] = true
+			values = append(values, entity.ProductOptionValue{Value: value.Value, Position: len(values)})
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("option %s needs at least one value", option.Name)
+		}
+		option.Values = values
+
+		combinations *= len(values)
+		if combinations > maxVariantsPerMatrix {
+			return fmt.Errorf("options would create more than %d variants", maxVariantsPerMatrix)
+		}
+	}
+	return nil
+}
+
+// optionCombinations lists every combination of option values, one value per
+// option in option order, varying the last option fastest
+func optionCombinations(options []*entity.ProductOption) [][]string {
+	combinations := [][]string{{}}
+	for _, option := range options {
+		next := make([][]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				extended := make([]string, len(combination), len(combination)+1)
+				copy(extended, combination)
+				next = append(next, append(extended, value.Value))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// combinationKey identifies a combination of option values, ignoring case and order
+func combinationKey(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for name, value := range values {
+		if value != "" {
+			pairs = append(pairs, strings.ToLower(name)+"="+strings.ToLower(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "|")
+}
+
+// optionPlaceholder is the SKU template placeholder of an option, e.g. {cup_size}
+func optionPlaceholder(name string) string {
+	return strings.Trim(optionPlaceholderPattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// validateSKUTemplate checks a SKU template only uses known placeholders
+func validateSKUTemplate(template string, options []*entity.ProductOption) error {
+	known := map[string]bool{"product": true, "id": true, "options": true}
+	for _, option := range options {
+		known[optionPlaceholder(option.Name)] = true
+	}
+	for _, match := range skuPlaceholderPattern.FindAllStringSubmatch(template, -1) {
+		if !known[match[1]] {
+			return fmt.Errorf("SKU template placeholder {%s} is unknown", match[1])
+		}
+	}
+	return nil
+}
+
+// renderSKU builds the SKU of a combination of option values from a template
+func renderSKU(template string, product *entity.Product, options []*entity.ProductOption, combination []string) (string, error) {
+	parts := make(map[string]string, len(options)+3)
+	parts["product"] = skuPart(product.Slug)
+	parts["id"] = strconv.FormatUint(uint64(product.ID), 10)
+	values := make([]string, len(combination))
+	for i, value := range combination {
+		values[i] = skuPart(value)
+		parts[optionPlaceholder(options[i].Name)] = values[i]
+	}
+	parts["options"] = strings.Join(values, "-")
+
+	sku := strings.TrimSpace(skuPlaceholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return parts[placeholder[1:len(placeholder)-1]]
+	}))
+	if sku == "" {
+		return "", errors.New("SKU template gives an empty SKU")
+	}
+	if len(sku) > maxSKULength {
+		return "", fmt.Errorf("SKU %s is longer than %d characters", sku, maxSKULength)
+	}
+	return sku, nil
+}
+
+// skuPart turns text into an uppercase SKU segment such as "NAVY-BLUE"
+func skuPart(text string) string {
+	return strings.Trim(skuSeparatorPattern.ReplaceAllString(strings.ToUpper(text), "-"), "-")
+}
@@ -0,0 +1,23 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestGenerateVariantsReturnsSKULookupErrors(t *testing.T) {
+	lookupErr := errors.New("connection reset")
+	uc := &variantOptionUseCase{
+		productRepo: newFakeProductRepo(&entity.Product{ID: 1, Name: "Basic Tee", Slug: "basic-tee", IsActive: true}),
+		variantRepo: &fakeVariantRepo{variants: map[uint]*entity.ProductVariant{}, skuErr: lookupErr},
+	}
+	options := []*entity.ProductOption{{Name: "Size", Values: []entity.ProductOptionValue{{Value: "M"}}}}
+
+	_, err := uc.GenerateVariants(context.Background(), 1, options, "", 200)
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("GenerateVariants() error = %v, want the SKU lookup error", err)
+	}
+}
@@ -0,0 +1,438 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type voucherUseCase struct {
+	voucherRepo   repository.VoucherRepository
+	cartRepo      repository.CartRepository
+	orderRepo     repository.OrderRepository
+	orderItemRepo repository.OrderItemRepository
+	paymentRepo   repository.PaymentRepository
+	productRepo   repository.ProductRepository
+	categoryRepo  repository.CategoryRepository
+}
+
+// NewVoucherUseCase creates a new VoucherUseCase instance
+func NewVoucherUseCase(
+	voucherRepo repository.VoucherRepository,
+	cartRepo repository.CartRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	paymentRepo repository.PaymentRepository,
+	productRepo repository.ProductRepository,
+	categoryRepo repository.CategoryRepository,
+) usecase.VoucherUseCase {
+	return &voucherUseCase{
+		voucherRepo:   voucherRepo,
+		cartRepo:      cartRepo,
+		orderRepo:     orderRepo,
+		orderItemRepo: orderItemRepo,
+		paymentRepo:   paymentRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+	}
+}
+
+// voucherLine is a priced line a voucher is evaluated against
+type voucherLine struct {
+	productID  uint
+	categoryID uint
+	amount     float64
+}
+
+// ApplyToCart checks a voucher against the user's cart and keeps it on the
+// cart for checkout. The shipping discount of a free-shipping voucher is only
+// known at checkout.
+func (uc *voucherUseCase) ApplyToCart(ctx context.Context, userID uint, code string) (*usecase.VoucherQuote, error) {
+	cart, err := uc.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	voucher, err := uc.usableVoucher(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]voucherLine, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, voucherLine{
+			productID:  product.ID,
+			categoryID: product.CategoryID,
+			amount:     product.SellingPrice() * float64(item.Quantity),
+		})
+	}
+
+	quote, err := quoteVoucher(voucher, lines, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.cartRepo.SetVoucherCode(ctx, cart.ID, voucher.Code); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// RemoveFromCart removes the voucher applied to the user's cart
+func (uc *voucherUseCase) RemoveFromCart(ctx context.Context, userID uint) error {
+	cart, err := uc.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return uc.cartRepo.SetVoucherCode(ctx, cart.ID, "")
+}
+
+// QuoteForCheckout prices the voucher kept on a cart against the items and
+// shipping cost of the order being placed. The returned redemption is counted
+// against the voucher limits when the order is placed.
+func (uc *voucherUseCase) QuoteForCheckout(ctx context.Context, userID uint, code string, items []entity.OrderItem, shippingCost float64) (*entity.VoucherRedemption, *usecase.VoucherQuote, error) {
+	voucher, err := uc.usableVoucher(ctx, userID, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quote, err := uc.quoteItems(ctx, voucher, items, shippingCost)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &entity.VoucherRedemption{
+		VoucherID:        voucher.ID,
+		UserID:           userID,
+		DiscountAmount:   quote.Discount,
+		ShippingDiscount: quote.ShippingDiscount,
+	}, quote, nil
+}
+
+// RedeemForOrder applies a voucher to a pending order before it is paid. The
+// discount is computed from the stored order items and shipping cost; the
+// use is counted against the voucher limits and the order's DiscountAmount
+// and FinalAmount are updated in one transaction.
+func (uc *voucherUseCase) RedeemForOrder(ctx context.Context, userID, orderID uint, code string) (*entity.Order, *usecase.VoucherQuote, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.UserID != userID {
+		return nil, nil, errors.New("order not found")
+	}
+	if order.Status != entity.OrderStatusPending {
+		return nil, nil, errors.New("order is " + string(order.Status))
+	}
+	if order.VoucherCode != "" {
+		return nil, nil, errors.New("order already has a voucher")
+	}
+	if order.OriginalOrderID != nil {
+		return nil, nil, errors.New("vouchers cannot be applied to replacement orders")
+	}
+	if _, err := uc.paymentRepo.GetByOrderID(ctx, order.ID); err == nil || order.WalletAmount > 0 {
+		return nil, nil, errors.New("voucher must be applied before payment")
+	}
+
+	items, err := uc.orderItemRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	orderItems := make([]entity.OrderItem, 0, len(items))
+	for _, item := range items {
+		orderItems = append(orderItems, *item)
+	}
+
+	redemption, quote, err := uc.QuoteForCheckout(ctx, userID, code, orderItems, order.ShippingCost)
+	if err != nil {
+		return nil, nil, err
+	}
+	redemption.OrderID = order.ID
+
+	order.VoucherCode = quote.Code
+	order.DiscountAmount += quote.Discount + quote.ShippingDiscount
+	order.FinalAmount = math.Max(order.TotalAmount+order.ShippingCost-order.DiscountAmount, 0)
+	if err := uc.voucherRepo.Redeem(ctx, redemption, order); err != nil {
+		return nil, nil, err
+	}
+
+	return order, quote, nil
+}
+
+// ReleaseForOrder gives back the voucher use of a cancelled order
+func (uc *voucherUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	return uc.voucherRepo.Release(ctx, orderID)
+}
+
+// quoteItems computes the discount a voucher gives on order items
+func (uc *voucherUseCase) quoteItems(ctx context.Context, voucher *entity.Voucher, items []entity.OrderItem, shippingCost float64) (*usecase.VoucherQuote, error) {
+	lines := make([]voucherLine, 0, len(items))
+	for _, item := range items {
+		product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, voucherLine{
+			productID:  item.ProductID,
+			categoryID: product.CategoryID,
+			amount:     item.FinalPrice * float64(item.Quantity),
+		})
+	}
+	return quoteVoucher(voucher, lines, shippingCost)
+}
+
+// CreateVoucher creates a voucher scoped to the given products and categories (admin function)
+func (uc *voucherUseCase) CreateVoucher(ctx context.Context, voucher *entity.Voucher, productIDs, categoryIDs []uint) error {
+	voucher.Code = normalizeVoucherCode(voucher.Code)
+	if err := validateVoucher(voucher); err != nil {
+		return err
+	}
+
+	if _, err := uc.voucherRepo.GetByCode(ctx, voucher.Code); err == nil {
+		return errors.New("voucher code already exists")
+	}
+
+	if err := uc.loadScope(ctx, voucher, productIDs, categoryIDs); err != nil {
+		return err
+	}
+
+	voucher.UsedCount = 0
+	return uc.voucherRepo.Create(ctx, voucher)
+}
+
+// UpdateVoucher updates a voucher and its scope (admin function)
+func (uc *voucherUseCase) UpdateVoucher(ctx context.Context, voucher *entity.Voucher, productIDs, categoryIDs []uint) error {
+	existing, err := uc.voucherRepo.GetByID(ctx, voucher.ID)
+	if err != nil {
+		return err
+	}
+
+	voucher.Code = normalizeVoucherCode(voucher.Code)
+	if err := validateVoucher(voucher); err != nil {
+		return err
+	}
+
+	if voucher.Code != existing.Code {
+		if _, err := uc.voucherRepo.GetByCode(ctx, voucher.Code); err == nil {
+			return errors.New("voucher code already exists")
+		}
+	}
+
+	if err := uc.loadScope(ctx, voucher, productIDs, categoryIDs); err != nil {
+		return err
+	}
+
+	voucher.UsedCount = existing.UsedCount
+	voucher.CreatedAt = existing.CreatedAt
+	return uc.voucherRepo.Update(ctx, voucher)
+}
+
+// DeleteVoucher deletes a voucher (admin function)
+func (uc *voucherUseCase) DeleteVoucher(ctx context.Context, id uint) error {
+	if _, err := uc.voucherRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return uc.voucherRepo.Delete(ctx, id)
+}
+
+// GetVoucher gets a voucher with its scope (admin function)
+func (uc *voucherUseCase) GetVoucher(ctx context.Context, id uint) (*entity.Voucher, error) {
+	return uc.voucherRepo.GetByID(ctx, id)
+}
+
+// ListVouchers lists vouchers with filter and pagination (admin function)
+func (uc *voucherUseCase) ListVouchers(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Voucher, int64, error) {
+	offset := (page - 1) * limit
+	return uc.voucherRepo.List(ctx, filter, offset, limit)
+}
+
+// ListVouchersByCursor lists vouchers with filter, one page after or before a cursor (admin function)
+func (uc *voucherUseCase) ListVouchersByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Voucher, *entity.PageCursors, error) {
+	return uc.voucherRepo.ListByCursor(ctx, filter, page)
+}
+
+// usableVoucher gets a voucher by code and checks that the user may use it now
+func (uc *voucherUseCase) usableVoucher(ctx context.Context, userID uint, code string) (*entity.Voucher, error) {
+	voucher, err := uc.voucherRepo.GetByCode(ctx, normalizeVoucherCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case !voucher.IsActive:
+		return nil, errors.New("voucher is not active")
+	case now.Before(voucher.StartsAt):
+		return nil, errors.New("voucher is not valid yet")
+	case !now.Before(voucher.EndsAt):
+		return nil, errors.New("voucher has expired")
+	case voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit:
+		return nil, errors.New("voucher usage limit reached")
+	}
+
+	if voucher.UsageLimitPerUser > 0 {
+		used, err := uc.voucherRepo.CountUserRedemptions(ctx, voucher.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(voucher.UsageLimitPerUser) {
+			return nil, errors.New("voucher usage limit per customer reached")
+		}
+	}
+
+	return voucher, nil
+}
+
+// loadScope resolves the products and categories a voucher is limited to
+func (uc *voucherUseCase) loadScope(ctx context.Context, voucher *entity.Voucher, productIDs, categoryIDs []uint) error {
+	products, categories, err := loadProductScope(ctx, uc.productRepo, uc.categoryRepo, productIDs, categoryIDs)
+	if err != nil {
+		return err
+	}
+	voucher.Products = products
+	voucher.Categories = categories
+	return nil
+}
+
+// loadProductScope resolves the products and categories a discount is limited to
+func loadProductScope(ctx context.Context, productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository, productIDs, categoryIDs []uint) ([]entity.Product, []entity.Category, error) {
+	products := make([]entity.Product, 0, len(productIDs))
+	for _, id := range productIDs {
+		product, err := productRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("product %d: %w", id, err)
+		}
+		products = append(products, *product)
+	}
+
+	categories := make([]entity.Category, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		category, err := categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("category %d: %w", id, err)
+		}
+		categories = append(categories, *category)
+	}
+
+	return products, categories, nil
+}
+
+// quoteVoucher computes the discount a voucher gives on the given lines and
+// shipping cost. Only lines within the voucher's product and category scope
+// count towards the minimum spend and the discount.
+func quoteVoucher(voucher *entity.Voucher, lines []voucherLine, shippingCost float64) (*usecase.VoucherQuote, error) {
+	eligible := 0.0
+	for _, line := range lines {
+		if voucherApplies(voucher, line) {
+			eligible += line.amount
+		}
+	}
+
+	if eligible <= 0 {
+		return nil, errors.New("voucher does not apply to any item")
+	}
+	if eligible < voucher.MinSpend {
+		return nil, fmt.Errorf("minimum spend of %.2f not reached", voucher.MinSpend)
+	}
+
+	quote := &usecase.VoucherQuote{
+		Code:           voucher.Code,
+		Type:           voucher.Type,
+		EligibleAmount: eligible,
+	}
+
+	switch voucher.Type {
+	case entity.VoucherTypePercentage:
+		quote.Discount = eligible * voucher.Value / 100
+		if voucher.MaxDiscount > 0 {
+			quote.Discount = math.Min(quote.Discount, voucher.MaxDiscount)
+		}
+	case entity.VoucherTypeFixed:
+		quote.Discount = math.Min(voucher.Value, eligible)
+	case entity.VoucherTypeFreeShipping:
+		quote.FreeShipping = true
+		quote.ShippingDiscount = shippingCost
+		if voucher.MaxDiscount > 0 {
+			quote.ShippingDiscount = math.Min(quote.ShippingDiscount, voucher.MaxDiscount)
+		}
+	}
+
+	quote.Discount = roundPrice(quote.Discount)
+	quote.ShippingDiscount = roundPrice(quote.ShippingDiscount)
+	return quote, nil
+}
+
+// voucherApplies reports whether a line is within a voucher's scope
+func voucherApplies(voucher *entity.Voucher, line voucherLine) bool {
+	if len(voucher.Products) == 0 && len(voucher.Categories) == 0 {
+		return true
+	}
+	for _, product := range voucher.Products {
+		if product.ID == line.productID {
+			return true
+		}
+	}
+	for _, category := range voucher.Categories {
+		if category.ID == line.categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateVoucher checks the settings of a voucher created or updated by an admin
+func validateVoucher(voucher *entity.Voucher) error {
+	if voucher.Code == "" {
+		return errors.New("voucher code is required")
+	}
+	if !voucher.EndsAt.After(voucher.StartsAt) {
+		return errors.New("voucher must end after it starts")
+	}
+	if voucher.MinSpend < 0 || voucher.MaxDiscount < 0 || voucher.UsageLimit < 0 || voucher.UsageLimitPerUser < 0 {
+		return errors.New("voucher limits cannot be negative")
+	}
+
+	switch voucher.Type {
+	case entity.VoucherTypePercentage:
+		if voucher.Value <= 0 || voucher.Value > 100 {
+			return errors.New("percentage must be between 0 and 100")
+		}
+	case entity.VoucherTypeFixed:
+		if voucher.Value <= 0 {
+			return errors.New("discount value must be greater than 0")
+		}
+	case entity.VoucherTypeFreeShipping:
+		voucher.Value = 0
+	default:
+		return errors.New("invalid voucher type")
+	}
+
+	return nil
+}
+
+// normalizeVoucherCode makes voucher codes case-insensitive
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// roundPrice rounds an amount to whole rupiah. The payment gateways charge
+// whole rupiah, so prices, discounts and totals are kept in whole rupiah
+// for the charged items to add up to the charged amount.
+func roundPrice(amount float64) float64 {
+	return math.Round(amount)
+}
@@ -0,0 +1,44 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestRedeemForOrderUpdatesOrderWithTheUse(t *testing.T) {
+	orders := newFakeOrderRepo(&entity.Order{
+		ID: 1, UserID: 7, Status: entity.OrderStatusPending, TotalAmount: 200000, ShippingCost: 15000, FinalAmount: 215000,
+	})
+	vouchers := &fakeVoucherRepo{vouchers: map[string]*entity.Voucher{
+		"ONGKIR": {
+			ID: 2, Code: "ONGKIR", Type: entity.VoucherTypeFreeShipping, MaxDiscount: 10000, IsActive: true,
+			StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(time.Hour),
+		},
+	}}
+	uc := &voucherUseCase{
+		voucherRepo:   vouchers,
+		orderRepo:     orders,
+		orderItemRepo: &fakeOrderItemRepo{items: []*entity.OrderItem{{OrderID: 1, ProductID: 1, Quantity: 2, FinalPrice: 100000}}},
+		paymentRepo:   newFakePaymentRepo(),
+		productRepo:   newFakeProductRepo(&entity.Product{ID: 1, CategoryID: 3, Price: 100000, IsActive: true}),
+	}
+
+	order, quote, err := uc.RedeemForOrder(context.Background(), 7, 1, "ongkir")
+	if err != nil {
+		t.Fatalf("RedeemForOrder() error = %v", err)
+	}
+
+	if quote.ShippingDiscount != 10000 || order.FinalAmount != 205000 || order.VoucherCode != "ONGKIR" {
+		t.Errorf("quote %.0f off shipping, order %s final %.0f, want 10000, ONGKIR, 205000", quote.ShippingDiscount, order.VoucherCode, order.FinalAmount)
+	}
+	if len(vouchers.redeemed) != 1 || vouchers.redeemed[0].OrderID != 1 || vouchers.redeemed[0].ShippingDiscount != 10000 {
+		t.Errorf("redeemed = %+v, want 10000 off the shipping of order 1", vouchers.redeemed)
+	}
+	// The order is updated by Redeem, in the same transaction as the use
+	if stored := orders.orders[1]; stored.VoucherCode != "" {
+		t.Errorf("order was updated outside Redeem: voucher %s", stored.VoucherCode)
+	}
+}
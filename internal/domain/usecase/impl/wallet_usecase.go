@@ -0,0 +1,174 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+// walletProvider is the payment provider recorded on orders paid in full with store credit
+const walletProvider = "wallet"
+
+type walletUseCase struct {
+	walletRepo   repository.WalletRepository
+	giftCardRepo repository.GiftCardRepository
+	orderRepo    repository.OrderRepository
+	paymentRepo  repository.PaymentRepository
+}
+
+// NewWalletUseCase creates a new WalletUseCase instance
+func NewWalletUseCase(
+	walletRepo repository.WalletRepository,
+	giftCardRepo repository.GiftCardRepository,
+	orderRepo repository.OrderRepository,
+	paymentRepo repository.PaymentRepository,
+) usecase.WalletUseCase {
+	return &walletUseCase{
+		walletRepo:   walletRepo,
+		giftCardRepo: giftCardRepo,
+		orderRepo:    orderRepo,
+		paymentRepo:  paymentRepo,
+	}
+}
+
+// GetWallet gets the store credit balance of a user
+func (uc *walletUseCase) GetWallet(ctx context.Context, userID uint) (*entity.Wallet, error) {
+	return uc.walletRepo.GetWallet(ctx, userID)
+}
+
+// GetTransactions gets the store credit ledger of a user
+func (uc *walletUseCase) GetTransactions(ctx context.Context, userID uint, page, limit int) ([]*entity.WalletTransaction, int64, error) {
+	offset := (page - 1) * limit
+	return uc.walletRepo.GetTransactions(ctx, userID, offset, limit)
+}
+
+// ApplyToOrder pays part or all of a pending order with store credit before
+// the rest is paid. The credit is taken from the wallet in the same
+// transaction as the order is updated, and an order it covers in full is paid
+// with it; the credit is given back if the order is cancelled.
+func (uc *walletUseCase) ApplyToOrder(ctx context.Context, userID, orderID uint, amount float64) (*entity.Order, error) {
+	amount = roundPrice(amount)
+	if amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != entity.OrderStatusPending {
+		return nil, errors.New("order is " + string(order.Status))
+	}
+	if order.WalletAmount > 0 {
+		return nil, errors.New("store credit has already been applied to this order")
+	}
+	if _, err := uc.paymentRepo.GetByOrderID(ctx, order.ID); err == nil {
+		return nil, errors.New("store credit must be applied before payment")
+	}
+	if amount > order.FinalAmount+0.01 {
+		return nil, fmt.Errorf("at most %.2f of store credit can be applied to this order", order.FinalAmount)
+	}
+
+	// Store credit cannot buy more store credit
+	cards, err := uc.giftCardRepo.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) > 0 {
+		return nil, errors.New("gift cards cannot be paid with store credit")
+	}
+
+	order.WalletAmount = math.Min(amount, order.FinalAmount)
+	var payment *repository.WalletPayment
+	if order.FinalAmount-order.WalletAmount <= 0.01 {
+		payment = newWalletPayment(order, "Paid with store credit")
+	}
+	err = uc.walletRepo.Pay(ctx, &entity.WalletTransaction{
+		UserID:      userID,
+		Type:        entity.WalletTransactionPayment,
+		Amount:      -amount,
+		OrderID:     &order.ID,
+		Reference:   fmt.Sprintf("order-%d-payment", order.ID),
+		Description: "Paid for order " + order.OrderNumber,
+	}, order, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment != nil {
+		order.PaymentID = &payment.Payment.ID
+		order.Status = payment.Paid.ToStatus
+	}
+	return order, nil
+}
+
+// ReleaseForOrder gives back the store credit that paid for a cancelled order.
+// The store credit of a paid order goes back with the refund of its payment.
+func (uc *walletUseCase) ReleaseForOrder(ctx context.Context, orderID uint) error {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.PaymentID != nil {
+		payment, err := uc.paymentRepo.GetByID(ctx, *order.PaymentID)
+		if err != nil {
+			return err
+		}
+		if payment.Status == entity.PaymentStatusPaid || isRefundStatus(payment.Status) {
+			return nil
+		}
+	}
+
+	transactions, err := uc.walletRepo.GetOrderTransactions(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, transaction := range transactions {
+		if transaction.Type != entity.WalletTransactionPayment {
+			continue
+		}
+		_, err := uc.walletRepo.Record(ctx, &entity.WalletTransaction{
+			UserID:      transaction.UserID,
+			Type:        entity.WalletTransactionRelease,
+			Amount:      -transaction.Amount,
+			OrderID:     &orderID,
+			Reference:   fmt.Sprintf("order-%d-release", orderID),
+			Description: "Order cancelled",
+		})
+		return err
+	}
+
+	return nil
+}
+
+// newWalletPayment prepares the payment of an order covered entirely by
+// store credit, which moves the order on to processing
+func newWalletPayment(order *entity.Order, note string) *repository.WalletPayment {
+	now := time.Now()
+	return &repository.WalletPayment{
+		Payment: &entity.Payment{
+			PaymentMethod: entity.PaymentMethodWallet,
+			Provider:      walletProvider,
+			WalletAmount:  order.WalletAmount,
+			Status:        entity.PaymentStatusPaid,
+			PaidAt:        &now,
+		},
+		Paid: &entity.OrderStatusHistory{
+			FromStatus: entity.OrderStatusPending,
+			ToStatus:   entity.OrderStatusProcessing,
+			ActorType:  systemActor.Type,
+			ActorID:    systemActor.ID,
+			Note:       note,
+		},
+	}
+}
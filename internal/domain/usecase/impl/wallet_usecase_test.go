@@ -0,0 +1,60 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func newTestWalletUseCase(wallet *fakeWalletRepo) (*walletUseCase, *fakeOrderRepo) {
+	orders := newFakeOrderRepo(&entity.Order{
+		ID: 1, UserID: 7, OrderNumber: "ORD-1", Status: entity.OrderStatusPending, FinalAmount: 215000,
+	})
+	uc := &walletUseCase{
+		walletRepo:   wallet,
+		giftCardRepo: &fakeGiftCardRepo{cards: map[uint]*entity.GiftCard{}},
+		orderRepo:    orders,
+		paymentRepo:  newFakePaymentRepo(),
+	}
+	return uc, orders
+}
+
+func TestApplyToOrderLeavesTheRestToTheGateway(t *testing.T) {
+	wallet := &fakeWalletRepo{}
+	uc, orders := newTestWalletUseCase(wallet)
+
+	order, err := uc.ApplyToOrder(context.Background(), 7, 1, 100000)
+	if err != nil {
+		t.Fatalf("ApplyToOrder() error = %v", err)
+	}
+
+	if order.WalletAmount != 100000 || order.Status != entity.OrderStatusPending {
+		t.Errorf("order paid %.0f with store credit, status %s, want 100000 and pending", order.WalletAmount, order.Status)
+	}
+	if len(wallet.debits) != 1 || wallet.debits[0].Amount != -100000 || wallet.payments[0] != nil {
+		t.Errorf("debits = %+v, payments %+v, want one of 100000 without a payment", wallet.debits, wallet.payments)
+	}
+	// The order is updated by Pay, in the same transaction as the debit
+	if stored := orders.orders[1]; stored.WalletAmount != 0 {
+		t.Errorf("order was updated outside Pay: %.0f store credit", stored.WalletAmount)
+	}
+}
+
+func TestApplyToOrderPaysCoveredOrderWithTheDebit(t *testing.T) {
+	wallet := &fakeWalletRepo{}
+	uc, _ := newTestWalletUseCase(wallet)
+
+	order, err := uc.ApplyToOrder(context.Background(), 7, 1, 215000)
+	if err != nil {
+		t.Fatalf("ApplyToOrder() error = %v", err)
+	}
+
+	payment := wallet.payments[0]
+	if payment == nil || payment.Payment.WalletAmount != 215000 || payment.Paid.ToStatus != entity.OrderStatusProcessing {
+		t.Fatalf("payment = %+v, want 215000 of store credit moving the order to processing", payment)
+	}
+	if order.Status != entity.OrderStatusProcessing || order.PaymentID == nil {
+		t.Errorf("order status %s, payment %v, want processing with the payment", order.Status, order.PaymentID)
+	}
+}
@@ -0,0 +1,84 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+	"fashion-shop/internal/domain/usecase"
+)
+
+type wishlistUseCase struct {
+	wishlistRepo repository.WishlistRepository
+	productRepo  repository.ProductRepository
+}
+
+// NewWishlistUseCase creates a new WishlistUseCase instance
+func NewWishlistUseCase(wishlistRepo repository.WishlistRepository, productRepo repository.ProductRepository) usecase.WishlistUseCase {
+	return &wishlistUseCase{
+		wishlistRepo: wishlistRepo,
+		productRepo:  productRepo,
+	}
+}
+
+// GetWishlist gets a user's wishlist and one page of its items
+func (uc *wishlistUseCase) GetWishlist(ctx context.Context, userID uint, page, limit int) (*entity.Wishlist, []*entity.WishlistItem, int64, error) {
+	wishlist, err := uc.wishlistRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	items, total, err := uc.wishlistRepo.GetItems(ctx, wishlist.ID, offset, limit)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return wishlist, items, total, nil
+}
+
+// AddToWishlist adds an active product to a user's wishlist
+func (uc *wishlistUseCase) AddToWishlist(ctx context.Context, userID, productID uint) error {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if !product.IsActive {
+		return errors.New("product not found")
+	}
+
+	wishlist, err := uc.wishlistRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return uc.wishlistRepo.AddItem(ctx, wishlist.ID, productID)
+}
+
+// RemoveFromWishlist removes an item from a user's wishlist
+func (uc *wishlistUseCase) RemoveFromWishlist(ctx context.Context, userID, itemID uint) error {
+	wishlist, err := uc.wishlistRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Only items of the user's own wishlist can be removed
+	items, _, err := uc.wishlistRepo.GetItems(ctx, wishlist.ID, 0, -1)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.ID == itemID {
+			return uc.wishlistRepo.RemoveItem(ctx, itemID)
+		}
+	}
+	return errors.New("wishlist item not found")
+}
+
+// IsInWishlist reports whether a product is in a user's wishlist
+func (uc *wishlistUseCase) IsInWishlist(ctx context.Context, userID, productID uint) (bool, error) {
+	wishlist, err := uc.wishlistRepo.GetOrCreate(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return uc.wishlistRepo.IsProductInWishlist(ctx, wishlist.ID, productID)
+}
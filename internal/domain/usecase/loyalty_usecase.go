@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// LoyaltyUseCase defines the interface for loyalty points business logic
+type LoyaltyUseCase interface {
+	GetAccount(ctx context.Context, userID uint) (*entity.LoyaltyAccount, error)
+	GetLedger(ctx context.Context, userID uint, page, limit int) ([]*entity.LoyaltyTransaction, int64, error)
+	RedeemForOrder(ctx context.Context, userID, orderID uint, points int) (*entity.Order, error)
+	ReleaseForOrder(ctx context.Context, orderID uint) error
+	ClawbackForRefund(ctx context.Context, orderID uint, refundedAmount float64) error
+
+	// Admin functions
+	AwardDeliveredOrders(ctx context.Context) (int, error)
+	ExpirePoints(ctx context.Context) (int, error)
+	ListCategoryMultipliers(ctx context.Context) ([]*entity.LoyaltyCategoryMultiplier, error)
+	SetCategoryMultiplier(ctx context.Context, categoryID uint, multiplier float64) (*entity.LoyaltyCategoryMultiplier, error)
+	DeleteCategoryMultiplier(ctx context.Context, categoryID uint) error
+}
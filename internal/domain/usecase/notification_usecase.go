@@ -10,6 +10,7 @@ import (
 type NotificationUseCase interface {
 	CreateNotification(ctx context.Context, userID uint, notificationType entity.NotificationType, title, message string, data map[string]interface{}) error
 	GetNotifications(ctx context.Context, userID uint, page, limit int) ([]*entity.Notification, int64, error)
+	GetNotificationsByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Notification, *entity.PageCursors, error)
 	GetUnreadNotifications(ctx context.Context, userID uint) ([]*entity.Notification, error)
 	MarkAsRead(ctx context.Context, id, userID uint) error
 	MarkAllAsRead(ctx context.Context, userID uint) error
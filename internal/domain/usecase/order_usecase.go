@@ -2,6 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"time"
 
 	"fashion-shop/internal/domain/entity"
@@ -9,26 +13,134 @@ import (
 
 // OrderUseCase defines the interface for order business logic
 type OrderUseCase interface {
-	CreateOrder(ctx context.Context, userID uint, addressID uint, paymentMethod entity.PaymentMethod, shippingMethod string, notes string) (*entity.Order, error)
-	GetOrderByID(ctx context.Context, id uint, userID uint) (*entity.Order, error)
+	// CreateOrder prices the cart with the running promotions applied to the
+	// items. A non-zero walletAmount pays that much with store credit through
+	// WalletUseCase.ApplyToOrder, leaving the rest for paymentMethod.
+	CreateOrder(ctx context.Context, userID uint, addressID uint, paymentMethod entity.PaymentMethod, shippingMethod string, notes string, walletAmount float64) (*entity.Order, error)
+	GetOrderByID(ctx context.Context, id uint, userID uint) (*entity.Order, error) // includes the status history
 	GetOrderByNumber(ctx context.Context, orderNumber string, userID uint) (*entity.Order, error)
 	GetUserOrders(ctx context.Context, userID uint, page, limit int) ([]*entity.Order, int64, error)
+	GetUserOrdersByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error)
 	CancelOrder(ctx context.Context, id uint, userID uint) error
 
 	// Admin functions
 	GetAllOrders(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Order, int64, error)
-	UpdateOrderStatus(ctx context.Context, id uint, status entity.OrderStatus) error
+	GetAllOrdersByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error)
+	UpdateOrderStatus(ctx context.Context, id uint, status entity.OrderStatus, adminID uint, note string) error // fails with *InvalidOrderTransitionError
 	UpdateShippingInfo(ctx context.Context, id uint, trackingNumber string) error
 	GetSalesReport(ctx context.Context, startDate, endDate time.Time) ([]*entity.Order, float64, error)
 }
 
+// InvalidOrderTransitionError is returned when an order cannot move from its current status to the requested one
+type InvalidOrderTransitionError struct {
+	From entity.OrderStatus
+	To   entity.OrderStatus
+}
+
+func (e *InvalidOrderTransitionError) Error() string {
+	return fmt.Sprintf("order cannot move from %s to %s", e.From, e.To)
+}
+
+// ErrInvalidPaymentSignature is returned when a payment notification fails signature verification
+var ErrInvalidPaymentSignature = errors.New("invalid payment notification signature")
+
+// ErrInvalidPaymentNotification is returned when a payment notification cannot be read
+var ErrInvalidPaymentNotification = errors.New("invalid payment notification")
+
+// ErrGatewayUnavailable is returned when a payment gateway cannot be reached; the operation can be retried
+var ErrGatewayUnavailable = errors.New("payment gateway unavailable")
+
 // PaymentUseCase defines the interface for payment business logic
 type PaymentUseCase interface {
-	ProcessPayment(ctx context.Context, orderID uint, paymentMethod entity.PaymentMethod) (*entity.Payment, error)
+	ProcessPayment(ctx context.Context, orderID uint, paymentMethod entity.PaymentMethod, channel string) (*entity.Payment, error)
 	GetPaymentByID(ctx context.Context, id uint) (*entity.Payment, error)
 	GetPaymentByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error)
-	HandlePaymentCallback(ctx context.Context, transactionID string, status string) error
-	RefundPayment(ctx context.Context, paymentID uint, amount float64, reason string) error
+	HandlePaymentCallback(ctx context.Context, provider string, header http.Header, payload []byte) error // payload is the raw gateway notification body
+	// RefundPayment refunds the way the order was paid, or entirely into store credit with toWallet
+	RefundPayment(ctx context.Context, paymentID uint, amount float64, reason string, toWallet bool) error
+	RefundOrder(ctx context.Context, orderID uint, reason string) error // refunds what is left of the order's paid payment
+
+	// Admin functions
+	ListPayments(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Payment, int64, error)
+	ListPaymentsByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Payment, *entity.PageCursors, error)
+	ReviewChallengedPayment(ctx context.Context, paymentID uint, approve bool) error
+	ExpireOverduePayments(ctx context.Context) (int, error)  // expires unpaid payments past their deadline and cancels their orders
+	ExpireUnstartedOrders(ctx context.Context) (int, error)  // cancels pending orders whose payment was never started
+	ReleaseCancelledOrders(ctx context.Context) (int, error) // retries giving back what cancelled orders still hold
+}
+
+// CODUseCase defines the interface for cash-on-delivery business logic
+type CODUseCase interface {
+	CheckEligibility(ctx context.Context, orderID, userID uint) error
+	PlaceCODPayment(ctx context.Context, orderID, userID uint) (*entity.Payment, error)
+
+	// Admin functions
+	ConfirmCODPayment(ctx context.Context, paymentID uint, amount float64) error // courier confirmed delivery and cash remittance
+	MarkCODUndelivered(ctx context.Context, paymentID uint, reason string) error
+	ImportRemittanceReport(ctx context.Context, courier, filename string, report io.Reader, importedBy uint) (*entity.CODRemittanceBatch, error)
+	GetRemittanceBatch(ctx context.Context, id uint) (*entity.CODRemittanceBatch, error)
+	ListRemittanceBatches(ctx context.Context, page, limit int) ([]*entity.CODRemittanceBatch, int64, error)
+	ListRemittanceBatchesByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.CODRemittanceBatch, *entity.PageCursors, error)
+}
+
+// ReturnItemRequest is an order item and the quantity of it a customer wants to return
+type ReturnItemRequest struct {
+	OrderItemID uint
+	Quantity    int
+}
+
+// ReturnUseCase defines the interface for return (RMA) business logic
+type ReturnUseCase interface {
+	CreateReturn(ctx context.Context, userID, orderID uint, items []ReturnItemRequest, reason entity.ReturnReason, description string, photoURLs []string) (*entity.ReturnRequest, error)
+	GetReturnByID(ctx context.Context, id, userID uint) (*entity.ReturnRequest, error)
+	GetUserReturns(ctx context.Context, userID uint, page, limit int) ([]*entity.ReturnRequest, int64, error)
+	CancelReturn(ctx context.Context, id, userID uint) error
+	SubmitReturnShipment(ctx context.Context, id, userID uint, courier, trackingNumber string) error
+
+	// Admin functions
+	ListReturns(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.ReturnRequest, int64, error)
+	ListReturnsByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ReturnRequest, *entity.PageCursors, error)
+	GetReturn(ctx context.Context, id uint) (*entity.ReturnRequest, error)
+	ApproveReturn(ctx context.Context, id uint, note string) error
+	RejectReturn(ctx context.Context, id uint, note string) error
+	ReceiveReturn(ctx context.Context, id uint) error // restocks the items and refunds the approved amount
+	RefundReturn(ctx context.Context, id uint) error  // retries the refund of a received return
+}
+
+// ExchangeItemRequest is an order item, the variant to swap it for and the quantity to swap
+type ExchangeItemRequest struct {
+	OrderItemID  uint
+	NewVariantID uint
+	Quantity     int
+}
+
+// ExchangeUseCase defines the interface for size and color exchange business logic
+type ExchangeUseCase interface {
+	CreateExchange(ctx context.Context, userID, orderID uint, items []ExchangeItemRequest, reason entity.ReturnReason, description string) (*entity.ExchangeRequest, error)
+	GetExchangeByID(ctx context.Context, id, userID uint) (*entity.ExchangeRequest, error)
+	GetUserExchanges(ctx context.Context, userID uint, page, limit int) ([]*entity.ExchangeRequest, int64, error)
+	CancelExchange(ctx context.Context, id, userID uint) error
+	SubmitExchangeShipment(ctx context.Context, id, userID uint, courier, trackingNumber string) error
+
+	// Admin functions
+	ListExchanges(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.ExchangeRequest, int64, error)
+	ListExchangesByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ExchangeRequest, *entity.PageCursors, error)
+	GetExchange(ctx context.Context, id uint) (*entity.ExchangeRequest, error)
+	ApproveExchange(ctx context.Context, id uint, note string) (*entity.Order, error) // returns the replacement order
+	RejectExchange(ctx context.Context, id uint, note string) error
+	ReceiveExchange(ctx context.Context, id uint) error  // restocks the original items and refunds any price difference
+	CompleteExchange(ctx context.Context, id uint) error // retries the refund of a received exchange
+	CancelUnpaidExchanges(ctx context.Context) (int, error)
+}
+
+// ReconciliationUseCase defines the interface for reconciling payments against gateway records (admin functions)
+type ReconciliationUseCase interface {
+	ImportSettlementReport(ctx context.Context, provider, filename string, report io.Reader, start, end time.Time, importedBy uint) (*entity.ReconciliationRun, error)
+	RunStatusCheck(ctx context.Context, provider string, start, end time.Time, requestedBy *uint) (*entity.ReconciliationRun, error) // empty provider checks every gateway
+	RunScheduledStatusCheck(ctx context.Context) (*entity.ReconciliationRun, error)                                                  // nil run when the previous scheduled check is up to date
+	GetRun(ctx context.Context, id uint, itemStatus string) (*entity.ReconciliationRun, error)
+	ListRuns(ctx context.Context, page, limit int) ([]*entity.ReconciliationRun, int64, error)
+	ListRunsByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.ReconciliationRun, *entity.PageCursors, error)
 }
 
 // CartUseCase defines the interface for cart business logic
@@ -38,7 +150,7 @@ type CartUseCase interface {
 	UpdateCartItem(ctx context.Context, userID, itemID uint, quantity int) error
 	RemoveFromCart(ctx context.Context, userID, itemID uint) error
 	ClearCart(ctx context.Context, userID uint) error
-	GetCartTotals(ctx context.Context, userID uint) (int, float64, error) // returns total items, total amount, error
+	GetCartTotals(ctx context.Context, userID uint) (int, float64, error) // returns total items, total amount after promotions, error
 }
 
 // WishlistUseCase defines the interface for wishlist business logic
@@ -56,3 +168,10 @@ type ShippingUseCase interface {
 	CalculateShipping(ctx context.Context, origin, destination, weight int, courier string) ([]map[string]interface{}, error)
 	TrackShipment(ctx context.Context, waybill, courier string) (map[string]interface{}, error)
 }
+
+// ShippingDocumentUseCase defines the interface for printable shipping documents
+type ShippingDocumentUseCase interface {
+	GetPackingSlip(ctx context.Context, orderID uint) ([]byte, error)
+	GetShippingLabel(ctx context.Context, orderID uint) ([]byte, error)
+	GetBulkShippingLabels(ctx context.Context, orderIDs []uint) ([]byte, error)
+}
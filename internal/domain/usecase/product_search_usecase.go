@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// ProductSearchUseCase defines the interface for product search and filtering business logic
+type ProductSearchUseCase interface {
+	SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error)
+	GetSuggestions(ctx context.Context, keyword string, limit int) ([]*entity.SearchSuggestion, error)
+	FilterProducts(ctx context.Context, filter *entity.ProductFilter, sort string, page, limit int) ([]*entity.Product, int64, error)
+	FilterProductsByCursor(ctx context.Context, filter *entity.ProductFilter, sort string, page *entity.CursorPage) ([]*entity.Product, *entity.PageCursors, error)
+	GetFacets(ctx context.Context, filter *entity.ProductFilter) (*entity.ProductFacets, error)
+}
+
+// ProductSearcher defines the interface for serving storefront product searches
+type ProductSearcher interface {
+	SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error)
+}
+
+// SearchIndexUseCase defines the interface for searching products through the
+// external search index and keeping the index in sync with the catalog
+type SearchIndexUseCase interface {
+	SearchProducts(ctx context.Context, keyword string, filter map[string]interface{}, sort string, page, limit int) ([]*entity.Product, int64, error)
+	SyncChanges(ctx context.Context) (int, error) // returns the number of products sent to the index
+	Reindex(ctx context.Context) (int, error)     // returns the number of products indexed
+}
@@ -48,10 +48,12 @@ type CategoryUseCase interface {
 
 // ReviewUseCase defines the interface for review business logic
 type ReviewUseCase interface {
-	CreateReview(ctx context.Context, userID, productID, orderID uint, rating int, comment string, images []*multipart.FileHeader) (*entity.Review, error)
+	CreateReview(ctx context.Context, userID, productID, orderID uint, rating int, comment string, fit entity.FitFeedback, images []*multipart.FileHeader) (*entity.Review, error)
 	GetReviewByID(ctx context.Context, id uint) (*entity.Review, error)
 	GetProductReviews(ctx context.Context, productID uint, page, limit int) ([]*entity.Review, int64, error)
+	GetProductReviewsByCursor(ctx context.Context, productID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error)
 	GetUserReviews(ctx context.Context, userID uint, page, limit int) ([]*entity.Review, int64, error)
+	GetUserReviewsByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error)
 	UpdateReview(ctx context.Context, id, userID uint, rating int, comment string) (*entity.Review, error)
 	DeleteReview(ctx context.Context, id, userID uint) error
 	GetAverageRating(ctx context.Context, productID uint) (float64, error)
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// AppliedPromotion is a promotion that matched a cart or order and the discount it gives
+type AppliedPromotion struct {
+	PromotionID uint    `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Discount    float64 `json:"discount"`
+}
+
+// PromotionLineDiscount is the promotion discount allocated to a cart or order line
+type PromotionLineDiscount struct {
+	ProductID     uint    `json:"product_id"`
+	VariantID     uint    `json:"variant_id"`
+	Quantity      int     `json:"quantity"`
+	UnitPrice     float64 `json:"unit_price"`
+	DiscountPrice float64 `json:"discount_price"` // per unit
+	FinalPrice    float64 `json:"final_price"`    // per unit
+}
+
+// PromotionResult is the outcome of evaluating the running promotions against a cart or order
+type PromotionResult struct {
+	Subtotal   float64                 `json:"subtotal"`
+	Discount   float64                 `json:"discount"`
+	Total      float64                 `json:"total"`
+	Lines      []PromotionLineDiscount `json:"lines"`
+	Promotions []AppliedPromotion      `json:"promotions"`
+}
+
+// PromotionUseCase defines the interface for promotion business logic
+type PromotionUseCase interface {
+	EvaluateCart(ctx context.Context, userID uint) (*PromotionResult, error)
+	ApplyToOrderItems(ctx context.Context, items []*entity.OrderItem) ([]entity.OrderPromotion, error)
+
+	// Admin functions
+	CreatePromotion(ctx context.Context, promotion *entity.Promotion, productIDs, categoryIDs []uint) error
+	UpdatePromotion(ctx context.Context, promotion *entity.Promotion, productIDs, categoryIDs []uint) error
+	DeletePromotion(ctx context.Context, id uint) error
+	GetPromotion(ctx context.Context, id uint) (*entity.Promotion, error)
+	ListPromotions(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Promotion, int64, error)
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// SizeChartUseCase defines the interface for size chart and fit recommendation business logic
+type SizeChartUseCase interface {
+	GetProductSizeChart(ctx context.Context, productID uint) (*entity.SizeChart, *entity.FitSummary, error)
+	RecommendSize(ctx context.Context, userID, productID uint) (*entity.SizeRecommendation, error)
+	GetBodyProfile(ctx context.Context, userID uint) (*entity.BodyProfile, error)
+	SaveBodyProfile(ctx context.Context, userID uint, profile *entity.BodyProfile) (*entity.BodyProfile, error)
+	SetReviewFit(ctx context.Context, userID, reviewID uint, fit entity.FitFeedback) (*entity.Review, error)
+
+	// Admin functions
+	CreateSizeChart(ctx context.Context, chart *entity.SizeChart) (*entity.SizeChart, error)
+	GetSizeChart(ctx context.Context, id uint) (*entity.SizeChart, error)
+	UpdateSizeChart(ctx context.Context, id uint, chart *entity.SizeChart) (*entity.SizeChart, error)
+	DeleteSizeChart(ctx context.Context, id uint) error
+	ListSizeCharts(ctx context.Context, page, limit int) ([]*entity.SizeChart, int64, error)
+}
@@ -20,6 +20,7 @@ type UserUseCase interface {
 
 	// Admin functions
 	GetUsers(ctx context.Context, page, limit int) ([]*entity.User, int64, error)
+	GetUsersByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.User, *entity.PageCursors, error)
 	GetUserByID(ctx context.Context, id uint) (*entity.User, error)
 	ToggleUserActive(ctx context.Context, id uint, isActive bool) error
 	ResetUserPassword(ctx context.Context, id uint, newPassword string) error
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// VariantOptionUseCase defines the interface for product option and variant matrix business logic
+type VariantOptionUseCase interface {
+	GetOptions(ctx context.Context, productID uint) ([]*entity.ProductOption, error)
+	GenerateVariants(ctx context.Context, productID uint, options []*entity.ProductOption, skuTemplate string, weight float64) ([]*entity.ProductVariant, error)
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// VoucherQuote is the discount a voucher gives on a cart or an order
+type VoucherQuote struct {
+	Code             string             `json:"code"`
+	Type             entity.VoucherType `json:"type"`
+	EligibleAmount   float64            `json:"eligible_amount"`   // subtotal of the items the voucher applies to
+	Discount         float64            `json:"discount"`          // off the items
+	ShippingDiscount float64            `json:"shipping_discount"` // off the shipping cost, known once shipping is
+	FreeShipping     bool               `json:"free_shipping"`
+}
+
+// VoucherUseCase defines the interface for voucher business logic
+type VoucherUseCase interface {
+	ApplyToCart(ctx context.Context, userID uint, code string) (*VoucherQuote, error)
+	RemoveFromCart(ctx context.Context, userID uint) error
+	QuoteForCheckout(ctx context.Context, userID uint, code string, items []entity.OrderItem, shippingCost float64) (*entity.VoucherRedemption, *VoucherQuote, error)
+	RedeemForOrder(ctx context.Context, userID, orderID uint, code string) (*entity.Order, *VoucherQuote, error)
+	ReleaseForOrder(ctx context.Context, orderID uint) error
+
+	// Admin functions
+	CreateVoucher(ctx context.Context, voucher *entity.Voucher, productIDs, categoryIDs []uint) error
+	UpdateVoucher(ctx context.Context, voucher *entity.Voucher, productIDs, categoryIDs []uint) error
+	DeleteVoucher(ctx context.Context, id uint) error
+	GetVoucher(ctx context.Context, id uint) (*entity.Voucher, error)
+	ListVouchers(ctx context.Context, filter map[string]interface{}, page, limit int) ([]*entity.Voucher, int64, error)
+	ListVouchersByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Voucher, *entity.PageCursors, error)
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"context"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// WalletUseCase defines the interface for store credit wallet business logic
+type WalletUseCase interface {
+	GetWallet(ctx context.Context, userID uint) (*entity.Wallet, error)
+	GetTransactions(ctx context.Context, userID uint, page, limit int) ([]*entity.WalletTransaction, int64, error)
+	// ApplyToOrder pays part or all of a pending order with store credit; the
+	// rest is paid through the gateway, and an order covered in full is paid
+	ApplyToOrder(ctx context.Context, userID, orderID uint, amount float64) (*entity.Order, error)
+	ReleaseForOrder(ctx context.Context, orderID uint) error
+}
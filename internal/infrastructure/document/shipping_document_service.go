@@ -0,0 +1,308 @@
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fashion-shop/internal/domain/entity"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+)
+
+// SenderInfo holds the shop details printed as the sender on shipping labels
+type SenderInfo struct {
+	Name       string
+	Phone      string
+	Address    string
+	City       string
+	Province   string
+	PostalCode string
+}
+
+// ShippingDocumentService defines the interface for shipping document generation
+type ShippingDocumentService interface {
+	GeneratePackingSlip(order *entity.Order, skus map[uint]string) ([]byte, error)
+	GenerateShippingLabel(order *entity.Order) ([]byte, error)
+	GenerateShippingLabels(orders []*entity.Order) ([]byte, error)
+}
+
+type pdfShippingDocumentService struct {
+	sender SenderInfo
+}
+
+// NewPDFShippingDocumentService creates a new PDF-based ShippingDocumentService instance
+func NewPDFShippingDocumentService(sender SenderInfo) ShippingDocumentService {
+	return &pdfShippingDocumentService{
+		sender: sender,
+	}
+}
+
+// Label size in millimetres (standard 100x150 thermal label)
+const (
+	labelWidth  = 100.0
+	labelHeight = 150.0
+	labelMargin = 5.0
+)
+
+// GeneratePackingSlip generates an A4 packing slip for an order
+func (s *pdfShippingDocumentService) GeneratePackingSlip(order *entity.Order, skus map[uint]string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	// Header
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(110, 10, tr(s.sender.Name), "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(70, 10, "PACKING SLIP", "", 1, "R", false, 0, "")
+
+	// Order number barcode
+	key := barcode.RegisterCode128(pdf, order.OrderNumber)
+	barcode.Barcode(pdf, key, 125, pdf.GetY()+2, 70, 14, false)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(110, 6, tr("Order Number: "+order.OrderNumber), "", 1, "L", false, 0, "")
+	pdf.CellFormat(110, 6, "Order Date: "+order.CreatedAt.Format("02 Jan 2006"), "", 1, "L", false, 0, "")
+	pdf.CellFormat(110, 6, tr("Shipping Method: "+order.ShippingMethod), "", 1, "L", false, 0, "")
+	if order.ShippingTrackingNumber != "" {
+		pdf.CellFormat(110, 6, tr("Tracking Number: "+order.ShippingTrackingNumber), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Ship to
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, "Ship To", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 5, tr(formatRecipient(order.ShippingAddress)), "", "L", false)
+	pdf.Ln(4)
+
+	// Items table
+	widths := []float64{40, 70, 55, 15}
+	headers := []string{"SKU", "Product", "Variant", "Qty"}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, header := range headers {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	totalQty := 0
+	for _, item := range order.OrderItems {
+		pdf.CellFormat(widths[0], 7, tr(skus[item.VariantID]), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, tr(truncate(pdf, item.ProductName, widths[1]-2)), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, tr(truncate(pdf, formatVariantInfo(item.VariantInfo), widths[2]-2)), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 7, fmt.Sprintf("%d", item.Quantity), "1", 1, "R", false, 0, "")
+		totalQty += item.Quantity
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, "Total Items", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 7, fmt.Sprintf("%d", totalQty), "1", 1, "R", false, 0, "")
+
+	if order.Notes != "" {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(0, 6, "Notes", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 5, tr(order.Notes), "", "L", false)
+	}
+
+	return output(pdf)
+}
+
+// GenerateShippingLabel generates a single shipping label for an order
+func (s *pdfShippingDocumentService) GenerateShippingLabel(order *entity.Order) ([]byte, error) {
+	return s.GenerateShippingLabels([]*entity.Order{order})
+}
+
+// GenerateShippingLabels generates shipping labels for multiple orders merged into one PDF, one label per page
+func (s *pdfShippingDocumentService) GenerateShippingLabels(orders []*entity.Order) ([]byte, error) {
+	if len(orders) == 0 {
+		return nil, errors.New("no orders to print")
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: labelWidth, Ht: labelHeight},
+	})
+	pdf.SetMargins(labelMargin, labelMargin, labelMargin)
+	pdf.SetAutoPageBreak(false, 0)
+
+	for _, order := range orders {
+		s.drawShippingLabel(pdf, order)
+	}
+
+	return output(pdf)
+}
+
+// drawShippingLabel draws a shipping label for an order on a new page
+func (s *pdfShippingDocumentService) drawShippingLabel(pdf *fpdf.Fpdf, order *entity.Order) {
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	contentWidth := labelWidth - 2*labelMargin
+	pdf.AddPage()
+	pdf.Rect(labelMargin, labelMargin, contentWidth, labelHeight-2*labelMargin, "D")
+
+	// Courier and tracking number
+	pdf.SetXY(labelMargin+2, labelMargin+2)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth-4, 8, tr(strings.ToUpper(order.ShippingMethod)), "", 1, "L", false, 0, "")
+	pdf.SetX(labelMargin + 2)
+	pdf.SetFont("Helvetica", "", 9)
+	trackingNumber := order.ShippingTrackingNumber
+	if trackingNumber == "" {
+		trackingNumber = "-"
+	}
+	pdf.CellFormat(contentWidth-4, 5, tr("Tracking No: "+trackingNumber), "", 1, "L", false, 0, "")
+	pdf.Line(labelMargin, pdf.GetY()+2, labelMargin+contentWidth, pdf.GetY()+2)
+
+	// Order number barcode
+	y := pdf.GetY() + 5
+	key := barcode.RegisterCode128(pdf, order.OrderNumber)
+	barcode.Barcode(pdf, key, labelMargin+5, y, contentWidth-10, 18, false)
+	pdf.SetXY(labelMargin+2, y+19)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(contentWidth-4, 5, tr(order.OrderNumber), "", 1, "C", false, 0, "")
+	pdf.Line(labelMargin, pdf.GetY()+2, labelMargin+contentWidth, pdf.GetY()+2)
+
+	// Recipient
+	pdf.SetXY(labelMargin+2, pdf.GetY()+4)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(contentWidth-4, 5, "TO:", "", 1, "L", false, 0, "")
+	pdf.SetX(labelMargin + 2)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(contentWidth-4, 6, tr(order.ShippingAddress.Recipient), "", 1, "L", false, 0, "")
+	pdf.SetX(labelMargin + 2)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(contentWidth-4, 5, tr(formatAddressLines(order.ShippingAddress)), "", "L", false)
+	pdf.SetX(labelMargin + 2)
+	pdf.CellFormat(contentWidth-4, 5, tr("Phone: "+order.ShippingAddress.Phone), "", 1, "L", false, 0, "")
+	pdf.Line(labelMargin, pdf.GetY()+2, labelMargin+contentWidth, pdf.GetY()+2)
+
+	// Sender
+	pdf.SetXY(labelMargin+2, pdf.GetY()+4)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(contentWidth-4, 5, "FROM:", "", 1, "L", false, 0, "")
+	pdf.SetX(labelMargin + 2)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(contentWidth-4, 4, tr(s.formatSender()), "", "L", false)
+
+	// Item summary at the bottom of the label
+	totalQty := 0
+	for _, item := range order.OrderItems {
+		totalQty += item.Quantity
+	}
+	pdf.SetXY(labelMargin+2, labelHeight-labelMargin-8)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.CellFormat(contentWidth-4, 5, fmt.Sprintf("Items: %d  |  Order date: %s", totalQty, order.CreatedAt.Format("02 Jan 2006")), "", 0, "L", false, 0, "")
+}
+
+// formatSender formats the sender block
+func (s *pdfShippingDocumentService) formatSender() string {
+	lines := []string{s.sender.Name}
+	if s.sender.Address != "" {
+		lines = append(lines, s.sender.Address)
+	}
+	cityLine := strings.TrimSpace(strings.Join(nonEmpty(s.sender.City, s.sender.Province), ", ") + " " + s.sender.PostalCode)
+	if cityLine != "" {
+		lines = append(lines, cityLine)
+	}
+	if s.sender.Phone != "" {
+		lines = append(lines, "Phone: "+s.sender.Phone)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatRecipient formats the full recipient block of a shipping address
+func formatRecipient(address entity.ShippingAddress) string {
+	return address.Recipient + "\n" + formatAddressLines(address) + "\nPhone: " + address.Phone
+}
+
+// formatAddressLines formats the street, district, city, province and postal code of an address
+func formatAddressLines(address entity.ShippingAddress) string {
+	lines := []string{address.FullAddress}
+	if district := strings.Join(nonEmpty(address.District, address.City), ", "); district != "" {
+		lines = append(lines, district)
+	}
+	lines = append(lines, strings.TrimSpace(address.Province+" "+address.PostalCode))
+	return strings.Join(lines, "\n")
+}
+
+// formatVariantInfo turns the JSON variant info of an order item into "Color: Red, Size: M"
+func formatVariantInfo(variantInfo string) string {
+	if variantInfo == "" {
+		return "-"
+	}
+
+	var info map[string]interface{}
+	if err := json.Unmarshal([]byte(variantInfo), &info); err != nil {
+		return variantInfo
+	}
+
+	keys := make([]string, 0, len(info))
+	for k := range info {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		if v := fmt.Sprintf("%v", info[k]); v != "" {
+			parts = append(parts, capitalize(k)+": "+v)
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// capitalize upper-cases the first letter of a word, which may not be ASCII
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToTitle(r)) + word[size:]
+}
+
+// truncate shortens text so that it fits within the given width
+func truncate(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// nonEmpty returns the non-empty values
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// output renders the PDF into a byte slice
+func output(pdf *fpdf.Fpdf) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
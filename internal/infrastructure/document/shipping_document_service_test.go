@@ -0,0 +1,27 @@
+package document
+
+import "testing"
+
+func TestFormatVariantInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		variantInfo string
+		want        string
+	}{
+		{"empty", "", "-"},
+		{"not JSON", "Red / M", "Red / M"},
+		{"sorted by option", `{"size":"M","color":"Red"}`, "Color: Red, Size: M"},
+		{"empty key is skipped", `{"":"x","size":"L"}`, "Size: L"},
+		{"empty value is skipped", `{"color":"","size":"L"}`, "Size: L"},
+		{"non-ASCII first letter", `{"ñandú":"sí","écru":"1"}`, "Écru: 1, Ñandú: sí"},
+		{"only empty keys", `{"":"x"}`, "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatVariantInfo(tt.variantInfo); got != tt.want {
+				t.Errorf("formatVariantInfo(%q) = %q, want %q", tt.variantInfo, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// FakeSignatureHeader carries the HMAC-SHA256 of a fake gateway notification body
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// fakeNotification is the body the fake gateway posts to the webhook
+type fakeNotification struct {
+	OrderID       string `json:"order_id"`
+	TransactionID string `json:"transaction_id"`
+	Status        string `json:"status"`
+	GrossAmount   string `json:"gross_amount"`
+}
+
+// fakeChannels maps a payment method to the channels the fake gateway charges through
+var fakeChannels = map[entity.PaymentMethod][]string{
+	entity.PaymentMethodBankTransfer: {"bca", "bni", "bri", "permata"},
+	entity.PaymentMethodEWallet:      {"gopay", "shopeepay"},
+	entity.PaymentMethodQRIS:         {"qris"},
+}
+
+// FakeGateway is an in-memory PaymentGateway for tests and local development.
+// Payments stay pending until Simulate is called, or until the auto-settle
+// delay passes, and every status change is posted to the webhook like a real
+// provider would.
+type FakeGateway struct {
+	secret     string
+	webhookURL string
+	autoSettle time.Duration
+	client     *http.Client
+
+	mu           sync.Mutex
+	transactions map[string]*Transaction
+	sequence     int
+}
+
+// NewFakeGateway creates a new FakeGateway. Notifications are signed with secret
+// and posted to webhookURL; an empty URL disables the webhook sender. A positive
+// autoSettle marks every charge paid after that delay.
+func NewFakeGateway(secret, webhookURL string, autoSettle time.Duration) *FakeGateway {
+	return &FakeGateway{
+		secret:       secret,
+		webhookURL:   webhookURL,
+		autoSettle:   autoSettle,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		transactions: make(map[string]*Transaction),
+	}
+}
+
+// Name returns the provider name
+func (g *FakeGateway) Name() string {
+	return ProviderFake
+}
+
+// Channels returns the direct charge channels the fake gateway offers for a payment method
+func (g *FakeGateway) Channels(method entity.PaymentMethod) []string {
+	return fakeChannels[method]
+}
+
+// Charge records a pending transaction and returns made-up payment instructions
+func (g *FakeGateway) Charge(ctx context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	if req.Channel != "" && !slices.Contains(fakeChannels[req.Method], req.Channel) {
+		return nil, fmt.Errorf("%w: %s on the fake gateway", ErrChannelNotSupported, req.Channel)
+	}
+
+	g.mu.Lock()
+	if tx, ok := g.transactions[req.OrderID]; ok && tx.Status != entity.PaymentStatusExpired && tx.Status != entity.PaymentStatusCancelled {
+		g.mu.Unlock()
+		return nil, errors.New("order id has already been used")
+	}
+	g.sequence++
+	tx := &Transaction{
+		OrderID:       req.OrderID,
+		TransactionID: fmt.Sprintf("fake-%d", g.sequence),
+		RawStatus:     string(entity.PaymentStatusPending),
+		Status:        entity.PaymentStatusPending,
+		Amount:        req.Amount,
+	}
+	g.transactions[req.OrderID] = tx
+	sequence := g.sequence
+	g.mu.Unlock()
+
+	result := &ChargeResult{TransactionID: tx.TransactionID}
+	switch req.Channel {
+	case "":
+		result.PaymentURL = "https://fake-gateway.local/pay/" + tx.TransactionID
+	case "bca", "bni", "bri", "permata":
+		result.VANumber = fmt.Sprintf("8808%012d", sequence)
+	case "qris":
+		result.QRString = "FAKEQRIS." + tx.TransactionID
+		result.QRCodeURL = "https://fake-gateway.local/qr/" + tx.TransactionID
+	case "gopay", "shopeepay":
+		result.DeeplinkURL = "https://fake-gateway.local/" + req.Channel + "/" + tx.TransactionID
+	}
+
+	if req.Expiry > 0 {
+		expiresAt := time.Now().Add(req.Expiry)
+		result.ExpiresAt = &expiresAt
+	}
+
+	if g.autoSettle > 0 {
+		orderID := req.OrderID
+		time.AfterFunc(g.autoSettle, func() {
+			if err := g.Simulate(context.Background(), orderID, entity.PaymentStatusPaid); err != nil {
+				log.Printf("fake gateway: auto-settle of %s failed: %v", orderID, err)
+			}
+		})
+	}
+
+	return result, nil
+}
+
+// GetTransaction gets a recorded transaction
+func (g *FakeGateway) GetTransaction(ctx context.Context, orderID, transactionID string) (*Transaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, orderID)
+	}
+	copied := *tx
+	return &copied, nil
+}
+
+// Refund records a refund against a paid transaction
+func (g *FakeGateway) Refund(ctx context.Context, req *RefundRequest) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[req.OrderID]
+	if !ok {
+		return errors.New("transaction not found")
+	}
+	if tx.Status != entity.PaymentStatusPaid && tx.Status != entity.PaymentStatusPartiallyRefunded {
+		return errors.New("transaction is not refundable")
+	}
+	if req.Amount > tx.Amount-tx.RefundedAmount+0.01 {
+		return errors.New("refund exceeds the remaining amount")
+	}
+
+	tx.RefundedAmount += req.Amount
+	tx.Status = entity.PaymentStatusPartiallyRefunded
+	if tx.RefundedAmount >= tx.Amount-0.01 {
+		tx.Status = entity.PaymentStatusRefunded
+	}
+	tx.RawStatus = string(tx.Status)
+	return nil
+}
+
+// Approve accepts a challenged transaction
+func (g *FakeGateway) Approve(ctx context.Context, transactionID string) error {
+	return g.review(transactionID, entity.PaymentStatusPaid)
+}
+
+// Deny rejects a challenged transaction
+func (g *FakeGateway) Deny(ctx context.Context, transactionID string) error {
+	return g.review(transactionID, entity.PaymentStatusFailed)
+}
+
+// review resolves a challenged transaction
+func (g *FakeGateway) review(transactionID string, status entity.PaymentStatus) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, tx := range g.transactions {
+		if tx.TransactionID != transactionID {
+			continue
+		}
+		if tx.Status != entity.PaymentStatusChallenge {
+			return errors.New("transaction is not challenged")
+		}
+		tx.Status = status
+		tx.RawStatus = string(status)
+		return nil
+	}
+	return errors.New("transaction not found")
+}
+
+// ParseNotification parses a fake gateway notification and verifies its HMAC signature
+func (g *FakeGateway) ParseNotification(header http.Header, payload []byte) (*Notification, error) {
+	var notification fakeNotification
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, errors.New("invalid notification payload")
+	}
+
+	expected := g.sign(payload)
+	return &Notification{
+		OrderID:        notification.OrderID,
+		TransactionID:  notification.TransactionID,
+		RawStatus:      notification.Status,
+		GrossAmount:    notification.GrossAmount,
+		SignatureValid: hmac.Equal([]byte(expected), []byte(header.Get(FakeSignatureHeader))),
+	}, nil
+}
+
+// Simulate moves a transaction to a new status and posts the notification to
+// the webhook. Refund statuses refund the remaining amount.
+func (g *FakeGateway) Simulate(ctx context.Context, orderID string, status entity.PaymentStatus) error {
+	g.mu.Lock()
+	tx, ok := g.transactions[orderID]
+	if !ok {
+		g.mu.Unlock()
+		return errors.New("transaction not found")
+	}
+	tx.Status = status
+	tx.RawStatus = string(status)
+	if status == entity.PaymentStatusRefunded {
+		tx.RefundedAmount = tx.Amount
+	}
+	copied := *tx
+	g.mu.Unlock()
+
+	return g.sendWebhook(ctx, &copied)
+}
+
+// sendWebhook posts a signed notification for a transaction
+func (g *FakeGateway) sendWebhook(ctx context.Context, tx *Transaction) error {
+	if g.webhookURL == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(fakeNotification{
+		OrderID:       tx.OrderID,
+		TransactionID: tx.TransactionID,
+		Status:        tx.RawStatus,
+		GrossAmount:   fmt.Sprintf("%.2f", tx.Amount),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FakeSignatureHeader, g.sign(payload))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 of a payload
+func (g *FakeGateway) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(g.secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestFakeGatewayContract(t *testing.T) {
+	testGatewayContract(t,
+		func() PaymentGateway { return NewFakeGateway("secret", "", 0) },
+		func(gw PaymentGateway, orderID string) {
+			if err := gw.(*FakeGateway).Simulate(context.Background(), orderID, entity.PaymentStatusPaid); err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+		},
+	)
+}
+
+func TestFakeGatewaySimulateSendsSignedNotification(t *testing.T) {
+	notifications := make(chan *Notification, 1)
+	var gw *FakeGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		notification, err := gw.ParseNotification(r.Header, payload)
+		if err != nil {
+			t.Errorf("ParseNotification: %v", err)
+		}
+		notifications <- notification
+	}))
+	defer server.Close()
+	gw = NewFakeGateway("secret", server.URL, 0)
+
+	ctx := context.Background()
+	result, err := gw.Charge(ctx, &ChargeRequest{OrderID: "ORD-1", Amount: 100000, Method: entity.PaymentMethodQRIS, Channel: "qris"})
+	if err != nil {
+		t.Fatalf("Charge: %v", err)
+	}
+	if err := gw.Simulate(ctx, "ORD-1", entity.PaymentStatusPaid); err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+
+	notification := <-notifications
+	if !notification.SignatureValid {
+		t.Fatal("notification signature is not valid")
+	}
+	if notification.OrderID != "ORD-1" || notification.TransactionID != result.TransactionID || notification.RawStatus != "paid" {
+		t.Fatalf("notification = %+v", notification)
+	}
+}
+
+func TestFakeGatewayRejectsForgedNotification(t *testing.T) {
+	gw := NewFakeGateway("secret", "", 0)
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, NewFakeGateway("other", "", 0).sign([]byte(`{"order_id":"ORD-1","status":"paid"}`)))
+
+	notification, err := gw.ParseNotification(header, []byte(`{"order_id":"ORD-1","status":"paid"}`))
+	if err != nil {
+		t.Fatalf("ParseNotification: %v", err)
+	}
+	if notification.SignatureValid {
+		t.Fatal("notification signed with another secret was accepted")
+	}
+
+	if _, err := gw.ParseNotification(header, []byte("not json")); err == nil {
+		t.Fatal("unreadable notification was parsed")
+	}
+}
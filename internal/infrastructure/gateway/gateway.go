@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// Payment gateway providers
+const (
+	ProviderMidtrans = "midtrans"
+	ProviderXendit   = "xendit"
+	ProviderFake     = "fake"
+)
+
+// ErrNotSupported is returned for operations a provider does not offer
+var ErrNotSupported = errors.New("operation not supported by payment gateway")
+
+// ErrChannelNotSupported is returned when a provider cannot charge through a channel
+var ErrChannelNotSupported = errors.New("payment channel not supported by payment gateway")
+
+// ErrTransactionNotFound is returned when the gateway has no transaction for an
+// order. Other lookup errors are transport or provider failures worth retrying.
+var ErrTransactionNotFound = errors.New("transaction not found at payment gateway")
+
+// ErrUnknownGateway is returned when no gateway is configured for a provider
+var ErrUnknownGateway = errors.New("unknown payment gateway")
+
+// Customer holds the payer details sent to the gateway
+type Customer struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+}
+
+// Item is a line of the charge shown to the payer
+type Item struct {
+	ID       string
+	Name     string
+	Price    float64
+	Quantity int
+}
+
+// ChargeRequest holds the details of a new payment
+type ChargeRequest struct {
+	OrderID     string
+	Amount      float64
+	Method      entity.PaymentMethod
+	Channel     string // empty for a hosted payment page, otherwise one of the gateway's Channels
+	Customer    Customer
+	Items       []Item
+	Expiry      time.Duration
+	CallbackURL string // where e-wallet apps redirect after payment
+}
+
+// ChargeResult holds what the payer needs to complete a payment
+type ChargeResult struct {
+	TransactionID string
+	PaymentURL    string
+	VANumber      string
+	QRString      string
+	QRCodeURL     string
+	DeeplinkURL   string
+	ExpiresAt     *time.Time
+}
+
+// Transaction is the gateway's current view of a payment
+type Transaction struct {
+	OrderID        string
+	TransactionID  string
+	RawStatus      string
+	Status         entity.PaymentStatus // empty when the raw status is not handled
+	Amount         float64
+	RefundedAmount float64
+}
+
+// RefundRequest holds the details of a refund
+type RefundRequest struct {
+	OrderID       string
+	TransactionID string
+	RefundKey     string // unique per refund
+	Amount        float64
+	Reason        string
+}
+
+// Notification is a parsed webhook from a gateway
+type Notification struct {
+	OrderID        string
+	TransactionID  string
+	RawStatus      string
+	FraudStatus    string
+	StatusCode     string
+	GrossAmount    string
+	SignatureValid bool
+}
+
+// PaymentGateway defines the operations every payment provider supports
+type PaymentGateway interface {
+	Name() string
+	Channels(method entity.PaymentMethod) []string // direct charge channels offered for a payment method
+	Charge(ctx context.Context, req *ChargeRequest) (*ChargeResult, error)
+	GetTransaction(ctx context.Context, orderID, transactionID string) (*Transaction, error)
+	Refund(ctx context.Context, req *RefundRequest) error
+	Approve(ctx context.Context, transactionID string) error
+	Deny(ctx context.Context, transactionID string) error
+	ParseNotification(header http.Header, payload []byte) (*Notification, error)
+}
+
+// Registry picks the gateway used for each payment method
+type Registry struct {
+	gateways       map[string]PaymentGateway
+	methods        map[entity.PaymentMethod]string
+	defaultGateway string
+}
+
+// NewRegistry creates a new Registry. Payment methods without an entry in
+// methods use the default provider.
+func NewRegistry(defaultProvider string, methods map[entity.PaymentMethod]string, gateways ...PaymentGateway) (*Registry, error) {
+	r := &Registry{
+		gateways:       make(map[string]PaymentGateway),
+		methods:        make(map[entity.PaymentMethod]string),
+		defaultGateway: defaultProvider,
+	}
+
+	for _, gw := range gateways {
+		r.gateways[gw.Name()] = gw
+	}
+
+	if _, ok := r.gateways[defaultProvider]; !ok {
+		return nil, fmt.Errorf("default payment gateway %q is not configured", defaultProvider)
+	}
+
+	for method, provider := range methods {
+		if provider == "" {
+			continue
+		}
+		if _, ok := r.gateways[provider]; !ok {
+			return nil, fmt.Errorf("payment gateway %q for %s is not configured", provider, method)
+		}
+		r.methods[method] = provider
+	}
+
+	return r, nil
+}
+
+// ForMethod gets the gateway that handles a payment method
+func (r *Registry) ForMethod(method entity.PaymentMethod) PaymentGateway {
+	if provider, ok := r.methods[method]; ok {
+		return r.gateways[provider]
+	}
+	return r.gateways[r.defaultGateway]
+}
+
+// Get gets a gateway by provider name
+func (r *Registry) Get(provider string) (PaymentGateway, error) {
+	gw, ok := r.gateways[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownGateway, provider)
+	}
+	return gw, nil
+}
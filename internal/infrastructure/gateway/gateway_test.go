@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// testGatewayContract checks the behavior the payment usecase relies on from
+// every PaymentGateway. settle moves a charged order to paid on the provider.
+func testGatewayContract(t *testing.T, newGateway func() PaymentGateway, settle func(gw PaymentGateway, orderID string)) {
+	ctx := context.Background()
+
+	t.Run("charge starts pending", func(t *testing.T) {
+		gw := newGateway()
+		channel := gw.Channels(entity.PaymentMethodBankTransfer)[0]
+		result, err := gw.Charge(ctx, &ChargeRequest{OrderID: "ORD-1", Amount: 100000, Method: entity.PaymentMethodBankTransfer, Channel: channel})
+		if err != nil {
+			t.Fatalf("Charge: %v", err)
+		}
+		if result.TransactionID == "" || result.VANumber == "" {
+			t.Fatalf("charge result is missing payment instructions: %+v", result)
+		}
+
+		tx, err := gw.GetTransaction(ctx, "ORD-1", result.TransactionID)
+		if err != nil {
+			t.Fatalf("GetTransaction: %v", err)
+		}
+		if tx.Status != entity.PaymentStatusPending || tx.Amount != 100000 {
+			t.Fatalf("transaction = %+v, want pending 100000", tx)
+		}
+	})
+
+	t.Run("order id is charged once", func(t *testing.T) {
+		gw := newGateway()
+		req := &ChargeRequest{OrderID: "ORD-1", Amount: 100000, Method: entity.PaymentMethodQRIS, Channel: "qris"}
+		if _, err := gw.Charge(ctx, req); err != nil {
+			t.Fatalf("Charge: %v", err)
+		}
+		if _, err := gw.Charge(ctx, req); err == nil {
+			t.Fatal("second charge under the same order id succeeded")
+		}
+	})
+
+	t.Run("unsupported channel leaves no transaction", func(t *testing.T) {
+		gw := newGateway()
+		_, err := gw.Charge(ctx, &ChargeRequest{OrderID: "ORD-1", Amount: 100000, Method: entity.PaymentMethodQRIS, Channel: "bca"})
+		if !errors.Is(err, ErrChannelNotSupported) {
+			t.Fatalf("Charge error = %v, want ErrChannelNotSupported", err)
+		}
+		if _, err := gw.GetTransaction(ctx, "ORD-1", ""); !errors.Is(err, ErrTransactionNotFound) {
+			t.Fatalf("GetTransaction error = %v, want ErrTransactionNotFound: the rejected charge left a transaction behind", err)
+		}
+	})
+
+	t.Run("refunds are capped at the paid amount", func(t *testing.T) {
+		gw := newGateway()
+		result, err := gw.Charge(ctx, &ChargeRequest{OrderID: "ORD-1", Amount: 100000, Method: entity.PaymentMethodQRIS, Channel: "qris"})
+		if err != nil {
+			t.Fatalf("Charge: %v", err)
+		}
+		settle(gw, "ORD-1")
+
+		refund := &RefundRequest{OrderID: "ORD-1", TransactionID: result.TransactionID, RefundKey: "ORD-1-refund-1", Amount: 40000}
+		if err := gw.Refund(ctx, refund); err != nil {
+			t.Fatalf("Refund: %v", err)
+		}
+		tx, _ := gw.GetTransaction(ctx, "ORD-1", result.TransactionID)
+		if tx.Status != entity.PaymentStatusPartiallyRefunded || tx.RefundedAmount != 40000 {
+			t.Fatalf("transaction = %+v, want partially refunded 40000", tx)
+		}
+
+		refund = &RefundRequest{OrderID: "ORD-1", TransactionID: result.TransactionID, RefundKey: "ORD-1-refund-2", Amount: 70000}
+		if err := gw.Refund(ctx, refund); err == nil {
+			t.Fatal("refund above the remaining amount succeeded")
+		}
+
+		refund.Amount = 60000
+		if err := gw.Refund(ctx, refund); err != nil {
+			t.Fatalf("Refund: %v", err)
+		}
+		tx, _ = gw.GetTransaction(ctx, "ORD-1", result.TransactionID)
+		if tx.Status != entity.PaymentStatusRefunded {
+			t.Fatalf("status = %s, want refunded", tx.Status)
+		}
+	})
+}
+
+func TestRegistryUnknownProvider(t *testing.T) {
+	registry, err := NewRegistry(ProviderFake, nil, NewFakeGateway("secret", "", 0))
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	if _, err := registry.Get("paypal"); !errors.Is(err, ErrUnknownGateway) {
+		t.Fatalf("Get error = %v, want ErrUnknownGateway", err)
+	}
+	if gw, err := registry.Get(ProviderFake); err != nil || gw.Name() != ProviderFake {
+		t.Fatalf("Get(fake) = %v, %v", gw, err)
+	}
+}
@@ -0,0 +1,360 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+// midtransLocation is the timezone Midtrans reports times in (Western Indonesia Time)
+var midtransLocation = time.FixedZone("WIB", 7*60*60)
+
+// midtransSnapPayments maps a payment method to the payment types offered on the Snap page
+var midtransSnapPayments = map[entity.PaymentMethod][]snap.SnapPaymentType{
+	entity.PaymentMethodCreditCard:   {snap.PaymentTypeCreditCard},
+	entity.PaymentMethodBankTransfer: {snap.PaymentTypeBCAVA, snap.PaymentTypeBNIVA, snap.PaymentTypeBRIVA, snap.PaymentTypePermataVA, snap.PaymentTypeEChannel},
+	entity.PaymentMethodEWallet:      {snap.PaymentTypeGopay, snap.PaymentTypeShopeepay},
+	entity.PaymentMethodQRIS:         {snap.SnapPaymentType("other_qris")},
+}
+
+// midtransChannels maps a payment method to the channels Core API charges can go through
+var midtransChannels = map[entity.PaymentMethod][]string{
+	entity.PaymentMethodBankTransfer: {"bca", "bni", "bri", "permata"},
+	entity.PaymentMethodEWallet:      {"gopay", "shopeepay"},
+	entity.PaymentMethodQRIS:         {"qris"},
+}
+
+// midtransNotification is the subset of a Midtrans HTTP notification used for verification
+type midtransNotification struct {
+	OrderID           string `json:"order_id"`
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+}
+
+type midtransGateway struct {
+	serverKey  string
+	snapClient snap.Client
+	coreClient coreapi.Client
+}
+
+// NewMidtransGateway creates a new Midtrans PaymentGateway
+func NewMidtransGateway(serverKey, environment string) PaymentGateway {
+	env := midtrans.Sandbox
+	if environment == "production" {
+		env = midtrans.Production
+	}
+
+	snapClient := snap.Client{}
+	snapClient.New(serverKey, env)
+
+	coreClient := coreapi.Client{}
+	coreClient.New(serverKey, env)
+
+	return &midtransGateway{
+		serverKey:  serverKey,
+		snapClient: snapClient,
+		coreClient: coreClient,
+	}
+}
+
+// Name returns the provider name
+func (g *midtransGateway) Name() string {
+	return ProviderMidtrans
+}
+
+// Channels returns the direct charge channels Midtrans offers for a payment method
+func (g *midtransGateway) Channels(method entity.PaymentMethod) []string {
+	return midtransChannels[method]
+}
+
+// Charge creates a Snap payment page, or a Core API charge when a channel is given
+func (g *midtransGateway) Charge(ctx context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	if req.Channel != "" {
+		return g.chargeCore(req)
+	}
+	return g.chargeSnap(req)
+}
+
+// chargeSnap creates a Snap transaction and returns its redirect URL
+func (g *midtransGateway) chargeSnap(req *ChargeRequest) (*ChargeResult, error) {
+	snapReq := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  req.OrderID,
+			GrossAmt: int64(math.Round(req.Amount)),
+		},
+		CustomerDetail: &midtrans.CustomerDetails{
+			FName: req.Customer.FirstName,
+			LName: req.Customer.LastName,
+			Email: req.Customer.Email,
+			Phone: req.Customer.Phone,
+		},
+		EnabledPayments: midtransSnapPayments[req.Method],
+	}
+
+	// Midtrans requires the item details to add up to the gross amount
+	items := make([]midtrans.ItemDetails, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, midtrans.ItemDetails{
+			ID:    item.ID,
+			Name:  truncateName(item.Name, 50),
+			Price: int64(math.Round(item.Price)),
+			Qty:   int32(item.Quantity),
+		})
+	}
+	snapReq.Items = &items
+
+	if req.Expiry > 0 {
+		snapReq.Expiry = &snap.ExpiryDetails{
+			Unit:     "minute",
+			Duration: int64(req.Expiry / time.Minute),
+		}
+	}
+
+	resp, err := g.snapClient.CreateTransaction(snapReq)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChargeResult{PaymentURL: resp.RedirectURL}
+	if req.Expiry > 0 {
+		expiresAt := time.Now().Add(req.Expiry)
+		result.ExpiresAt = &expiresAt
+	}
+	return result, nil
+}
+
+// chargeCore creates a Core API charge that returns payment instructions
+// (virtual account number, QRIS string or e-wallet deeplink) instead of a redirect URL
+func (g *midtransGateway) chargeCore(req *ChargeRequest) (*ChargeResult, error) {
+	chargeReq := &coreapi.ChargeReq{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  req.OrderID,
+			GrossAmt: int64(math.Round(req.Amount)),
+		},
+		CustomerDetails: &midtrans.CustomerDetails{
+			FName: req.Customer.FirstName,
+			LName: req.Customer.LastName,
+			Email: req.Customer.Email,
+			Phone: req.Customer.Phone,
+		},
+	}
+
+	switch req.Channel {
+	case "bca", "bni", "bri", "permata":
+		chargeReq.PaymentType = coreapi.PaymentTypeBankTransfer
+		chargeReq.BankTransfer = &coreapi.BankTransferDetails{Bank: midtrans.Bank(req.Channel)}
+	case "gopay":
+		chargeReq.PaymentType = coreapi.PaymentTypeGopay
+		chargeReq.Gopay = &coreapi.GopayDetails{
+			EnableCallback: req.CallbackURL != "",
+			CallbackUrl:    req.CallbackURL,
+		}
+	case "shopeepay":
+		chargeReq.PaymentType = coreapi.PaymentTypeShopeepay
+		chargeReq.ShopeePay = &coreapi.ShopeePayDetails{CallbackUrl: req.CallbackURL}
+	case "qris":
+		chargeReq.PaymentType = coreapi.PaymentTypeQris
+		chargeReq.Qris = &coreapi.QrisDetails{Acquirer: "gopay"}
+	default:
+		return nil, fmt.Errorf("%w: %s on midtrans", ErrChannelNotSupported, req.Channel)
+	}
+
+	if req.Expiry > 0 {
+		chargeReq.CustomExpiry = &coreapi.CustomExpiry{
+			ExpiryDuration: int(req.Expiry / time.Minute),
+			Unit:           "minute",
+		}
+	}
+
+	resp, err := g.coreClient.ChargeTransaction(chargeReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != "201" && resp.StatusCode != "200" {
+		return nil, errors.New(resp.StatusMessage)
+	}
+
+	result := &ChargeResult{
+		TransactionID: resp.TransactionID,
+		QRString:      resp.QRString,
+		VANumber:      resp.PermataVaNumber,
+	}
+
+	if len(resp.VaNumbers) > 0 && result.VANumber == "" {
+		result.VANumber = resp.VaNumbers[0].VANumber
+	}
+
+	for _, action := range resp.Actions {
+		switch action.Name {
+		case "generate-qr-code":
+			result.QRCodeURL = action.URL
+		case "deeplink-redirect":
+			result.DeeplinkURL = action.URL
+		}
+	}
+
+	if resp.ExpiryTime != "" {
+		if expiresAt, err := time.ParseInLocation("2006-01-02 15:04:05", resp.ExpiryTime, midtransLocation); err == nil {
+			result.ExpiresAt = &expiresAt
+		}
+	}
+
+	return result, nil
+}
+
+// GetTransaction gets the current status of a transaction from Midtrans
+func (g *midtransGateway) GetTransaction(ctx context.Context, orderID, transactionID string) (*Transaction, error) {
+	resp, err := g.coreClient.CheckTransaction(orderID)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, orderID)
+		}
+		return nil, err
+	}
+
+	amount, parseErr := strconv.ParseFloat(resp.GrossAmount, 64)
+	if parseErr != nil {
+		return nil, fmt.Errorf("invalid gross amount %q", resp.GrossAmount)
+	}
+	refunded, _ := strconv.ParseFloat(resp.RefundAmount, 64)
+
+	return &Transaction{
+		OrderID:        resp.OrderID,
+		TransactionID:  resp.TransactionID,
+		RawStatus:      resp.TransactionStatus,
+		Status:         mapMidtransStatus(resp.TransactionStatus, resp.FraudStatus),
+		Amount:         amount,
+		RefundedAmount: refunded,
+	}, nil
+}
+
+// Refund refunds all or part of a transaction
+func (g *midtransGateway) Refund(ctx context.Context, req *RefundRequest) error {
+	transactionID := req.TransactionID
+	if transactionID == "" {
+		transactionID = req.OrderID
+	}
+
+	resp, err := g.coreClient.RefundTransaction(transactionID, &coreapi.RefundReq{
+		RefundKey: req.RefundKey,
+		Amount:    int64(math.Round(req.Amount)),
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != "200" {
+		return errors.New(resp.StatusMessage)
+	}
+
+	return nil
+}
+
+// Approve accepts a transaction that was challenged by fraud detection
+func (g *midtransGateway) Approve(ctx context.Context, transactionID string) error {
+	resp, err := g.coreClient.ApproveTransaction(transactionID)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != "200" {
+		return errors.New(resp.StatusMessage)
+	}
+
+	return nil
+}
+
+// Deny rejects a transaction that was challenged by fraud detection
+func (g *midtransGateway) Deny(ctx context.Context, transactionID string) error {
+	resp, err := g.coreClient.DenyTransaction(transactionID)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != "200" {
+		return errors.New(resp.StatusMessage)
+	}
+
+	return nil
+}
+
+// ParseNotification parses a Midtrans HTTP notification and verifies its
+// signature_key, which is SHA512(order_id + status_code + gross_amount + server_key)
+func (g *midtransGateway) ParseNotification(header http.Header, payload []byte) (*Notification, error) {
+	var notification midtransNotification
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, errors.New("invalid notification payload")
+	}
+
+	hash := sha512.Sum512([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + g.serverKey))
+	expected := hex.EncodeToString(hash[:])
+
+	return &Notification{
+		OrderID:        notification.OrderID,
+		TransactionID:  notification.TransactionID,
+		RawStatus:      notification.TransactionStatus,
+		FraudStatus:    notification.FraudStatus,
+		StatusCode:     notification.StatusCode,
+		GrossAmount:    notification.GrossAmount,
+		SignatureValid: subtle.ConstantTimeCompare([]byte(expected), []byte(notification.SignatureKey)) == 1,
+	}, nil
+}
+
+// mapMidtransStatus maps a Midtrans transaction_status and fraud_status to a payment status.
+// An empty status is returned for transaction statuses we do not handle.
+func mapMidtransStatus(transactionStatus, fraudStatus string) entity.PaymentStatus {
+	switch transactionStatus {
+	case "capture", "settlement":
+		switch fraudStatus {
+		case "challenge":
+			return entity.PaymentStatusChallenge
+		case "deny":
+			return entity.PaymentStatusFailed
+		default:
+			return entity.PaymentStatusPaid
+		}
+	case "pending", "authorize":
+		return entity.PaymentStatusPending
+	case "deny", "failure":
+		return entity.PaymentStatusFailed
+	case "cancel":
+		return entity.PaymentStatusCancelled
+	case "expire":
+		return entity.PaymentStatusExpired
+	case "partial_refund", "partial_chargeback":
+		return entity.PaymentStatusPartiallyRefunded
+	case "refund", "chargeback":
+		return entity.PaymentStatusRefunded
+	default:
+		return ""
+	}
+}
+
+// truncateName shortens item names to the length a gateway accepts
+func truncateName(name string, max int) string {
+	runes := []rune(name)
+	if len(runes) <= max {
+		return name
+	}
+	return string(runes[:max])
+}
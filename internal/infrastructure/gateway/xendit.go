@@ -0,0 +1,461 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// xenditInvoiceMethods maps a payment method to the payment methods offered on the invoice page
+var xenditInvoiceMethods = map[entity.PaymentMethod][]string{
+	entity.PaymentMethodCreditCard:   {"CREDIT_CARD"},
+	entity.PaymentMethodBankTransfer: {"BCA", "BNI", "MANDIRI"},
+	entity.PaymentMethodEWallet:      {"OVO", "DANA", "SHOPEEPAY", "LINKAJA"},
+	entity.PaymentMethodQRIS:         {"QRIS"},
+}
+
+// xenditChannels maps a payment method to the channels payment requests can go through
+var xenditChannels = map[entity.PaymentMethod][]string{
+	entity.PaymentMethodBankTransfer: {"bca", "bni"},
+	entity.PaymentMethodEWallet:      {"shopeepay"},
+	entity.PaymentMethodQRIS:         {"qris"},
+}
+
+// xenditResponse is the subset of an invoice or payment request used by the adapter
+type xenditResponse struct {
+	ID          string  `json:"id"`
+	ExternalID  string  `json:"external_id"`
+	ReferenceID string  `json:"reference_id"`
+	Status      string  `json:"status"`
+	Amount      float64 `json:"amount"`
+	InvoiceURL  string  `json:"invoice_url"`
+	ExpiryDate  string  `json:"expiry_date"`
+	Actions     []struct {
+		Action  string `json:"action"`
+		URLType string `json:"url_type"`
+		URL     string `json:"url"`
+	} `json:"actions"`
+	PaymentMethod struct {
+		VirtualAccount struct {
+			ChannelProperties struct {
+				VirtualAccountNumber string `json:"virtual_account_number"`
+				ExpiresAt            string `json:"expires_at"`
+			} `json:"channel_properties"`
+		} `json:"virtual_account"`
+		QRCode struct {
+			ChannelProperties struct {
+				QRString  string `json:"qr_string"`
+				ExpiresAt string `json:"expires_at"`
+			} `json:"channel_properties"`
+		} `json:"qr_code"`
+	} `json:"payment_method"`
+}
+
+// xenditCallback is the subset of invoice, payment and refund callbacks used for verification
+type xenditCallback struct {
+	// Invoice callbacks
+	ID         string  `json:"id"`
+	ExternalID string  `json:"external_id"`
+	Status     string  `json:"status"`
+	Amount     float64 `json:"amount"`
+	// Payment request and refund callbacks
+	Event string `json:"event"`
+	Data  struct {
+		PaymentRequestID string  `json:"payment_request_id"`
+		InvoiceID        string  `json:"invoice_id"`
+		ReferenceID      string  `json:"reference_id"` // the refund key for refunds
+		Status           string  `json:"status"`
+		Amount           float64 `json:"amount"`
+	} `json:"data"`
+}
+
+// xenditRefunds is a page of refunds of an invoice or payment request
+type xenditRefunds struct {
+	Data []struct {
+		Status string  `json:"status"`
+		Amount float64 `json:"amount"`
+	} `json:"data"`
+}
+
+type xenditGateway struct {
+	secretKey     string
+	callbackToken string
+	baseURL       string
+	client        *http.Client
+}
+
+// NewXenditGateway creates a new Xendit PaymentGateway
+func NewXenditGateway(secretKey, callbackToken, baseURL string) PaymentGateway {
+	return &xenditGateway{
+		secretKey:     secretKey,
+		callbackToken: callbackToken,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		client:        &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the provider name
+func (g *xenditGateway) Name() string {
+	return ProviderXendit
+}
+
+// Channels returns the direct charge channels Xendit offers for a payment method
+func (g *xenditGateway) Channels(method entity.PaymentMethod) []string {
+	return xenditChannels[method]
+}
+
+// Charge creates an invoice, or a payment request when a channel is given
+func (g *xenditGateway) Charge(ctx context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	if req.Channel != "" {
+		return g.chargePaymentRequest(ctx, req)
+	}
+	return g.chargeInvoice(ctx, req)
+}
+
+// chargeInvoice creates a hosted invoice and returns its URL
+func (g *xenditGateway) chargeInvoice(ctx context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	items := make([]map[string]interface{}, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, map[string]interface{}{
+			"name":     item.Name,
+			"price":    item.Price,
+			"quantity": item.Quantity,
+		})
+	}
+
+	body := map[string]interface{}{
+		"external_id": req.OrderID,
+		"amount":      req.Amount,
+		"currency":    "IDR",
+		"payer_email": req.Customer.Email,
+		"description": "Order " + req.OrderID,
+		"items":       items,
+		"customer": map[string]interface{}{
+			"given_names":   req.Customer.FirstName,
+			"surname":       req.Customer.LastName,
+			"email":         req.Customer.Email,
+			"mobile_number": req.Customer.Phone,
+		},
+	}
+	if methods, ok := xenditInvoiceMethods[req.Method]; ok {
+		body["payment_methods"] = methods
+	}
+	if req.Expiry > 0 {
+		body["invoice_duration"] = int(req.Expiry / time.Second)
+	}
+
+	var resp xenditResponse
+	if err := g.do(ctx, http.MethodPost, "/v2/invoices", body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &ChargeResult{
+		TransactionID: resp.ID,
+		PaymentURL:    resp.InvoiceURL,
+		ExpiresAt:     parseXenditTime(resp.ExpiryDate),
+	}, nil
+}
+
+// chargePaymentRequest creates a payment request that returns payment
+// instructions (virtual account number, QRIS string or e-wallet deeplink)
+func (g *xenditGateway) chargePaymentRequest(ctx context.Context, req *ChargeRequest) (*ChargeResult, error) {
+	var expiresAt string
+	if req.Expiry > 0 {
+		expiresAt = time.Now().Add(req.Expiry).UTC().Format(time.RFC3339)
+	}
+
+	var paymentMethod map[string]interface{}
+	switch req.Channel {
+	case "bca", "bni":
+		paymentMethod = map[string]interface{}{
+			"type":        "VIRTUAL_ACCOUNT",
+			"reusability": "ONE_TIME_USE",
+			"virtual_account": map[string]interface{}{
+				"channel_code": strings.ToUpper(req.Channel),
+				"channel_properties": map[string]interface{}{
+					"customer_name": strings.TrimSpace(req.Customer.FirstName + " " + req.Customer.LastName),
+					"expires_at":    expiresAt,
+				},
+			},
+		}
+	case "shopeepay":
+		paymentMethod = map[string]interface{}{
+			"type":        "EWALLET",
+			"reusability": "ONE_TIME_USE",
+			"ewallet": map[string]interface{}{
+				"channel_code": "SHOPEEPAY",
+				"channel_properties": map[string]interface{}{
+					"success_return_url": req.CallbackURL,
+				},
+			},
+		}
+	case "qris":
+		paymentMethod = map[string]interface{}{
+			"type":        "QR_CODE",
+			"reusability": "ONE_TIME_USE",
+			"qr_code": map[string]interface{}{
+				"channel_code": "QRIS",
+				"channel_properties": map[string]interface{}{
+					"expires_at": expiresAt,
+				},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s on xendit", ErrChannelNotSupported, req.Channel)
+	}
+
+	body := map[string]interface{}{
+		"reference_id":   req.OrderID,
+		"amount":         req.Amount,
+		"currency":       "IDR",
+		"country":        "ID",
+		"payment_method": paymentMethod,
+	}
+
+	var resp xenditResponse
+	if err := g.do(ctx, http.MethodPost, "/payment_requests", body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &ChargeResult{
+		TransactionID: resp.ID,
+		VANumber:      resp.PaymentMethod.VirtualAccount.ChannelProperties.VirtualAccountNumber,
+		QRString:      resp.PaymentMethod.QRCode.ChannelProperties.QRString,
+	}
+
+	for _, action := range resp.Actions {
+		if action.URLType == "DEEPLINK" || (action.URLType == "MOBILE" && result.DeeplinkURL == "") {
+			result.DeeplinkURL = action.URL
+		}
+	}
+
+	result.ExpiresAt = parseXenditTime(resp.PaymentMethod.VirtualAccount.ChannelProperties.ExpiresAt)
+	if result.ExpiresAt == nil {
+		result.ExpiresAt = parseXenditTime(resp.PaymentMethod.QRCode.ChannelProperties.ExpiresAt)
+	}
+
+	return result, nil
+}
+
+// GetTransaction gets the current status of an invoice or payment request from Xendit
+func (g *xenditGateway) GetTransaction(ctx context.Context, orderID, transactionID string) (*Transaction, error) {
+	var resp xenditResponse
+	switch {
+	case strings.HasPrefix(transactionID, "pr-"):
+		if err := g.do(ctx, http.MethodGet, "/payment_requests/"+url.PathEscape(transactionID), nil, &resp); err != nil {
+			return nil, err
+		}
+		resp.ExternalID = resp.ReferenceID
+	case transactionID != "":
+		if err := g.do(ctx, http.MethodGet, "/v2/invoices/"+url.PathEscape(transactionID), nil, &resp); err != nil {
+			return nil, err
+		}
+	default:
+		var invoices []xenditResponse
+		if err := g.do(ctx, http.MethodGet, "/v2/invoices?external_id="+url.QueryEscape(orderID), nil, &invoices); err != nil {
+			return nil, err
+		}
+		if len(invoices) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, orderID)
+		}
+		resp = invoices[0]
+	}
+
+	transaction := &Transaction{
+		OrderID:       resp.ExternalID,
+		TransactionID: resp.ID,
+		RawStatus:     resp.Status,
+		Status:        mapXenditStatus(resp.Status),
+		Amount:        resp.Amount,
+	}
+
+	// Invoices and payment requests stay paid when refunded; the refunds are separate
+	if transaction.Status == entity.PaymentStatusPaid {
+		refunded, err := g.refundedAmount(ctx, resp.ID)
+		if err != nil {
+			return nil, err
+		}
+		if refunded > 0 {
+			transaction.RefundedAmount = refunded
+			transaction.Status = entity.PaymentStatusPartiallyRefunded
+			if refunded >= transaction.Amount-0.01 {
+				transaction.Status = entity.PaymentStatusRefunded
+			}
+		}
+	}
+
+	return transaction, nil
+}
+
+// refundedAmount sums the succeeded refunds of an invoice or payment request
+func (g *xenditGateway) refundedAmount(ctx context.Context, transactionID string) (float64, error) {
+	query := url.Values{}
+	if strings.HasPrefix(transactionID, "pr-") {
+		query.Set("payment_request_id", transactionID)
+	} else {
+		query.Set("invoice_id", transactionID)
+	}
+
+	var refunds xenditRefunds
+	if err := g.do(ctx, http.MethodGet, "/refunds?"+query.Encode(), nil, &refunds); err != nil {
+		return 0, err
+	}
+
+	total := 0.0
+	for _, refund := range refunds.Data {
+		if refund.Status == "SUCCEEDED" {
+			total += refund.Amount
+		}
+	}
+	return total, nil
+}
+
+// Refund refunds all or part of an invoice or payment request
+func (g *xenditGateway) Refund(ctx context.Context, req *RefundRequest) error {
+	body := map[string]interface{}{
+		"reference_id": req.RefundKey,
+		"amount":       req.Amount,
+		"currency":     "IDR",
+		"reason":       "REQUESTED_BY_CUSTOMER",
+		"metadata":     map[string]interface{}{"note": req.Reason},
+	}
+	if strings.HasPrefix(req.TransactionID, "pr-") {
+		body["payment_request_id"] = req.TransactionID
+	} else {
+		body["invoice_id"] = req.TransactionID
+	}
+
+	return g.do(ctx, http.MethodPost, "/refunds", body, nil)
+}
+
+// Approve is not supported; Xendit has no manual fraud review
+func (g *xenditGateway) Approve(ctx context.Context, transactionID string) error {
+	return ErrNotSupported
+}
+
+// Deny is not supported; Xendit has no manual fraud review
+func (g *xenditGateway) Deny(ctx context.Context, transactionID string) error {
+	return ErrNotSupported
+}
+
+// ParseNotification parses a Xendit callback and verifies its x-callback-token header
+func (g *xenditGateway) ParseNotification(header http.Header, payload []byte) (*Notification, error) {
+	var callback xenditCallback
+	if err := json.Unmarshal(payload, &callback); err != nil {
+		return nil, errors.New("invalid notification payload")
+	}
+
+	token := header.Get("x-callback-token")
+	notification := &Notification{
+		SignatureValid: g.callbackToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(g.callbackToken)) == 1,
+	}
+
+	switch {
+	case strings.HasPrefix(callback.Event, "refund."):
+		// Refund callbacks only name the refunded payment; its refunded total
+		// is looked up when the status is confirmed
+		notification.TransactionID = callback.Data.PaymentRequestID
+		if notification.TransactionID == "" {
+			notification.TransactionID = callback.Data.InvoiceID
+		}
+		notification.RawStatus = callback.Event
+		notification.GrossAmount = fmt.Sprintf("%.2f", callback.Data.Amount)
+	case callback.Event != "":
+		notification.OrderID = callback.Data.ReferenceID
+		notification.TransactionID = callback.Data.PaymentRequestID
+		notification.RawStatus = callback.Data.Status
+		notification.GrossAmount = fmt.Sprintf("%.2f", callback.Data.Amount)
+	default:
+		notification.OrderID = callback.ExternalID
+		notification.TransactionID = callback.ID
+		notification.RawStatus = callback.Status
+		notification.GrossAmount = fmt.Sprintf("%.2f", callback.Amount)
+	}
+
+	return notification, nil
+}
+
+// do sends an authenticated request to the Xendit API and decodes the response into out
+func (g *xenditGateway) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.secretKey, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return fmt.Errorf("%w: %s", ErrTransactionNotFound, path)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return fmt.Errorf("xendit request failed with status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("xendit: %s: %s", apiErr.ErrorCode, apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// mapXenditStatus maps an invoice or payment request status to a payment status.
+// An empty status is returned for statuses we do not handle.
+func mapXenditStatus(status string) entity.PaymentStatus {
+	switch status {
+	case "PENDING", "REQUIRES_ACTION", "AWAITING_CAPTURE":
+		return entity.PaymentStatusPending
+	case "PAID", "SETTLED", "SUCCEEDED":
+		return entity.PaymentStatusPaid
+	case "FAILED":
+		return entity.PaymentStatusFailed
+	case "CANCELED", "VOIDED":
+		return entity.PaymentStatusCancelled
+	case "EXPIRED":
+		return entity.PaymentStatusExpired
+	default:
+		return ""
+	}
+}
+
+// parseXenditTime parses an RFC 3339 timestamp, returning nil when it is empty or invalid
+func parseXenditTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,136 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// EXIF orientation values, see the TIFF/EXIF specification for tag 0x0112
+const (
+	orientationNormal = 1
+	orientationMax    = 8
+	tagOrientation    = 0x0112
+)
+
+// jpegOrientation reads the EXIF orientation of a JPEG file, returning
+// orientationNormal when it has none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return orientationNormal
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return orientationNormal
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF: // fill byte
+			i++
+			continue
+		case marker == 0x01 || marker >= 0xD0 && marker <= 0xD7: // markers without a segment
+			i += 2
+			continue
+		case marker == 0xDA || marker == 0xD9: // image data starts, EXIF always comes before it
+			return orientationNormal
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return orientationNormal
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return orientationNormal
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of EXIF data
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return orientationNormal
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return orientationNormal
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) != tagOrientation {
+			continue
+		}
+		// A single SHORT is stored in the first bytes of the value field
+		orientation := int(order.Uint16(tiff[entry+8:]))
+		if orientation < orientationNormal || orientation > orientationMax {
+			return orientationNormal
+		}
+		return orientation
+	}
+	return orientationNormal
+}
+
+// orient turns and mirrors an image so it displays upright without its EXIF
+// orientation. Pixels are copied between RGBA buffers rather than through
+// At and Set, which allocate a color per pixel.
+func orient(src image.Image, orientation int) image.Image {
+	if orientation <= orientationNormal || orientation > orientationMax {
+		return src
+	}
+
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		bounds := src.Bounds()
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+	width, height := w, h
+	if orientation >= 5 { // orientations 5 to 8 swap width and height
+		width, height = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < h; y++ {
+		row := rgba.PixOffset(rgba.Rect.Min.X, rgba.Rect.Min.Y+y)
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // mirrored along the top-left diagonal
+				dx, dy = y, x
+			case 6: // needs turning 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // mirrored along the top-right diagonal
+				dx, dy = h-1-y, w-1-x
+			case 8: // needs turning 90° counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			d := dy*dst.Stride + dx*4
+			copy(dst.Pix[d:d+4], rgba.Pix[row+x*4:row+x*4+4])
+		}
+	}
+	return dst
+}
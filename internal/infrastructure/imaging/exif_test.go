@@ -0,0 +1,115 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// exifJPEG builds a JPEG file start holding an EXIF segment with one
+// orientation entry, in the given byte order
+func exifJPEG(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], tagOrientation)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:], uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, 0xFF, 0xD9)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	var plain bytes.Buffer
+	if err := jpeg.Encode(&plain, image.NewGray(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	truncated := exifJPEG(binary.BigEndian, 6)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"little endian", exifJPEG(binary.LittleEndian, 6), 6},
+		{"big endian", exifJPEG(binary.BigEndian, 8), 8},
+		{"without EXIF", plain.Bytes(), orientationNormal},
+		{"out of range", exifJPEG(binary.LittleEndian, 9), orientationNormal},
+		{"truncated", truncated[:len(truncated)-12], orientationNormal},
+		{"not a JPEG", []byte("\x89PNG\r\n\x1a\n"), orientationNormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != tt.want {
+				t.Errorf("jpegOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image whose pixels all differ, cut from a larger one so its
+	// bounds don't start at the origin
+	canvas := image.NewNRGBA(image.Rect(0, 0, 5, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 5; x++ {
+			canvas.Set(x, y, color.NRGBA{R: uint8(x * 50), G: uint8(y * 60), B: 7, A: 255})
+		}
+	}
+	src := canvas.SubImage(image.Rect(1, 1, 4, 3))
+	at := func(x, y int) color.Color { return src.At(1+x, 1+y) }
+	const w, h = 3, 2
+
+	tests := []struct {
+		orientation   int
+		width, height int
+		source        func(x, y int) (int, int) // the source pixel shown at x, y
+	}{
+		{2, w, h, func(x, y int) (int, int) { return w - 1 - x, y }},
+		{3, w, h, func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }},
+		{4, w, h, func(x, y int) (int, int) { return x, h - 1 - y }},
+		{5, h, w, func(x, y int) (int, int) { return y, x }},
+		{6, h, w, func(x, y int) (int, int) { return y, h - 1 - x }},
+		{7, h, w, func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }},
+		{8, h, w, func(x, y int) (int, int) { return w - 1 - y, x }},
+	}
+	for _, tt := range tests {
+		got := orient(src, tt.orientation)
+		if got.Bounds() != image.Rect(0, 0, tt.width, tt.height) {
+			t.Errorf("orientation %d: bounds = %v, want %dx%d", tt.orientation, got.Bounds(), tt.width, tt.height)
+			continue
+		}
+		for y := 0; y < tt.height; y++ {
+			for x := 0; x < tt.width; x++ {
+				sx, sy := tt.source(x, y)
+				if !sameColor(got.At(x, y), at(sx, sy)) {
+					t.Errorf("orientation %d: pixel %d,%d = %v, want %v from %d,%d", tt.orientation, x, y, got.At(x, y), at(sx, sy), sx, sy)
+				}
+			}
+		}
+	}
+
+	if got := orient(src, orientationNormal); got != src {
+		t.Error("orient() copied an upright image")
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
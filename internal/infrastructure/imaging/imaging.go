@@ -0,0 +1,254 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"fashion-shop/internal/domain/entity"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// Size is an image rendition size
+type Size struct {
+	Name    string
+	MaxEdge int // longest edge in pixels, smaller images are not enlarged
+}
+
+// Sizes are the renditions generated for every upload, smallest first
+var Sizes = []Size{
+	{Name: entity.ImageSizeThumbnail, MaxEdge: 200},
+	{Name: entity.ImageSizeMedium, MaxEdge: 800},
+	{Name: entity.ImageSizeLarge, MaxEdge: 1600},
+}
+
+// contentTypes maps the accepted upload types to their decoder names
+var contentTypes = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// ErrUnsupportedType is returned for uploads that are not JPEG, PNG, GIF or WebP images
+var ErrUnsupportedType = errors.New("image must be a JPEG, PNG, GIF or WebP file")
+
+// Options holds the limits and quality of processed images
+type Options struct {
+	MinWidth      int
+	MinHeight     int
+	MaxWidth      int
+	MaxHeight     int
+	MaxPixels     int // width times height, an image takes 4 bytes per pixel once decoded
+	MaxConcurrent int // images processed at once, others wait for a turn
+	Quality       int // JPEG and WebP quality, 1-100
+}
+
+// Defaults for the options left unset
+const (
+	defaultQuality       = 82
+	defaultMaxPixels     = 25_000_000
+	defaultMaxConcurrent = 2
+)
+
+// Rendition is one encoded size and format of an image
+type Rendition struct {
+	Size        string
+	Format      string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Extension returns the file extension of the rendition's format
+func (r *Rendition) Extension() string {
+	if r.Format == entity.ImageFormatJPEG {
+		return "jpg"
+	}
+	return r.Format
+}
+
+// Image is a processed upload
+type Image struct {
+	Hash       string // SHA-256 of the uploaded file
+	Width      int    // after applying the EXIF orientation
+	Height     int
+	Renditions []Rendition
+}
+
+// Processor validates uploaded images and renders them in every size.
+// Renditions are re-encoded from the decoded pixels, so EXIF and other
+// metadata of the upload never reach them; the EXIF orientation is applied
+// to the pixels first.
+type Processor struct {
+	options Options
+	webp    WebPEncoder   // nil skips WebP renditions
+	slots   chan struct{} // one per image being processed
+}
+
+// NewProcessor creates a new Processor. webp may be nil when no WebP encoder is available.
+func NewProcessor(options Options, webp WebPEncoder) *Processor {
+	if options.Quality < 1 || options.Quality > 100 {
+		options.Quality = defaultQuality
+	}
+	if options.MaxPixels <= 0 {
+		options.MaxPixels = defaultMaxPixels
+	}
+	if options.MaxConcurrent <= 0 {
+		options.MaxConcurrent = defaultMaxConcurrent
+	}
+	return &Processor{
+		options: options,
+		webp:    webp,
+		slots:   make(chan struct{}, options.MaxConcurrent),
+	}
+}
+
+// Hash returns the hex SHA-256 of an uploaded file, used to find duplicate uploads
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Process validates an uploaded image and renders it in every size, as JPEG
+// (or PNG when it has transparency) and as WebP when an encoder is available.
+// At most MaxConcurrent images are processed at once; Process waits for a
+// turn until ctx is done.
+func (p *Processor) Process(ctx context.Context, data []byte) (*Image, error) {
+	decoder, ok := contentTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	// Check the dimensions before decoding so oversized images are never held in memory
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != decoder {
+		return nil, errors.New("image file is corrupt or does not match its type")
+	}
+	if config.Width > p.options.MaxWidth || config.Height > p.options.MaxHeight {
+		return nil, fmt.Errorf("image must be at most %dx%d pixels", p.options.MaxWidth, p.options.MaxHeight)
+	}
+	if config.Width*config.Height > p.options.MaxPixels {
+		return nil, fmt.Errorf("image must be at most %.1f megapixels", float64(p.options.MaxPixels)/1e6)
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		defer func() { <-p.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("image file is corrupt or does not match its type")
+	}
+	if format == "jpeg" {
+		src = orient(src, jpegOrientation(data))
+	}
+	bounds := src.Bounds()
+	if bounds.Dx() < p.options.MinWidth || bounds.Dy() < p.options.MinHeight {
+		return nil, fmt.Errorf("image must be at least %dx%d pixels", p.options.MinWidth, p.options.MinHeight)
+	}
+
+	result := &Image{
+		Hash:   Hash(data),
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}
+	transparent := !isOpaque(src)
+	for _, size := range Sizes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resized := resize(src, size.MaxEdge)
+		rendition, err := p.encode(resized, transparent)
+		if err != nil {
+			return nil, err
+		}
+		rendition.Size = size.Name
+		result.Renditions = append(result.Renditions, *rendition)
+
+		if p.webp != nil {
+			webp, err := p.webp.EncodeWebP(ctx, resized, p.options.Quality)
+			if err != nil {
+				return nil, err
+			}
+			result.Renditions = append(result.Renditions, Rendition{
+				Size:        size.Name,
+				Format:      entity.ImageFormatWebP,
+				ContentType: "image/webp",
+				Width:       resized.Bounds().Dx(),
+				Height:      resized.Bounds().Dy(),
+				Data:        webp,
+			})
+		}
+	}
+	return result, nil
+}
+
+// encode encodes a rendition as JPEG, or as PNG to keep transparency
+func (p *Processor) encode(img image.Image, transparent bool) (*Rendition, error) {
+	rendition := &Rendition{
+		Format:      entity.ImageFormatJPEG,
+		ContentType: "image/jpeg",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+
+	var buf bytes.Buffer
+	if transparent {
+		rendition.Format = entity.ImageFormatPNG
+		rendition.ContentType = "image/png"
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	} else if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.options.Quality}); err != nil {
+		return nil, err
+	}
+	rendition.Data = buf.Bytes()
+	return rendition, nil
+}
+
+// resize scales an image down so its longest edge is at most maxEdge pixels
+func resize(src image.Image, maxEdge int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxEdge || height > maxEdge {
+		if width >= height {
+			width, height = maxEdge, max(1, height*maxEdge/width)
+		} else {
+			width, height = max(1, width*maxEdge/height), maxEdge
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	} else {
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, xdraw.Src, nil)
+	}
+	return dst
+}
+
+// isOpaque reports whether an image has no transparent pixels
+func isOpaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+	return false
+}
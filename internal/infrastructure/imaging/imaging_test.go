@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxEdge       int
+		wantW, wantH  int
+	}{
+		{"landscape", 1000, 500, 200, 200, 100},
+		{"portrait", 500, 1000, 200, 100, 200},
+		{"square", 800, 800, 200, 200, 200},
+		{"smaller than the edge", 100, 50, 200, 100, 50},
+		{"thin strip", 2000, 1, 200, 200, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewNRGBA(image.Rect(10, 10, 10+tt.width, 10+tt.height))
+			got := resize(src, tt.maxEdge).Bounds()
+			if got != image.Rect(0, 0, tt.wantW, tt.wantH) {
+				t.Errorf("resize() bounds = %v, want %dx%d", got, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessRejectsImagesOverThePixelBudget(t *testing.T) {
+	p := NewProcessor(Options{MaxWidth: 1000, MaxHeight: 1000, MaxPixels: 250 * 250}, nil)
+
+	if _, err := p.Process(context.Background(), encodePNG(t, 300, 300)); err == nil {
+		t.Error("Process() accepted 300x300 pixels with a budget of 250x250")
+	}
+	processed, err := p.Process(context.Background(), encodePNG(t, 250, 250))
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(processed.Renditions) != len(Sizes) {
+		t.Errorf("got %d renditions, want %d", len(processed.Renditions), len(Sizes))
+	}
+}
+
+func TestProcessWaitsForATurn(t *testing.T) {
+	p := NewProcessor(Options{MaxWidth: 1000, MaxHeight: 1000, MaxConcurrent: 1}, nil)
+	p.slots <- struct{}{} // another image is being processed
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Process(ctx, encodePNG(t, 250, 250)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Process() error = %v, want it to give up waiting", err)
+	}
+
+	<-p.slots
+	if _, err := p.Process(context.Background(), encodePNG(t, 250, 250)); err != nil {
+		t.Fatalf("Process() error = %v once a turn is free", err)
+	}
+}
@@ -0,0 +1,63 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// WebPEncoder encodes images as WebP. The standard library and
+// golang.org/x/image only decode WebP, so encoding is pluggable.
+type WebPEncoder interface {
+	EncodeWebP(ctx context.Context, img image.Image, quality int) ([]byte, error)
+}
+
+// cwebpEncoder encodes WebP images with the cwebp command from libwebp
+type cwebpEncoder struct {
+	path string
+}
+
+// NewCWebPEncoder creates a WebPEncoder that runs the cwebp binary at path,
+// or found on PATH by name. An error is returned when it cannot be found.
+func NewCWebPEncoder(path string) (WebPEncoder, error) {
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("cwebp not found: %w", err)
+	}
+	return &cwebpEncoder{path: resolved}, nil
+}
+
+// EncodeWebP encodes an image as lossy WebP, keeping its transparency
+func (e *cwebpEncoder) EncodeWebP(ctx context.Context, img image.Image, quality int) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "cwebp-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.png")
+	output := filepath.Join(dir, "output.webp")
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(input, buf.Bytes(), 0o600); err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.path, "-quiet", "-metadata", "none", "-q", strconv.Itoa(quality), input, "-o", output)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to encode WebP: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return os.ReadFile(output)
+}
@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+// NewAddressRepository creates a new AddressRepository instance
+func NewAddressRepository(db *gorm.DB) repository.AddressRepository {
+	return &addressRepository{
+		db: db,
+	}
+}
+
+// Create creates a new address
+func (r *addressRepository) Create(ctx context.Context, address *entity.Address) error {
+	return r.db.WithContext(ctx).Create(address).Error
+}
+
+// GetByID gets an address by ID
+func (r *addressRepository) GetByID(ctx context.Context, id uint) (*entity.Address, error) {
+	var address entity.Address
+	if err := r.db.WithContext(ctx).First(&address, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("address not found")
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+// GetByUserID gets all addresses of a user, the default address first
+func (r *addressRepository) GetByUserID(ctx context.Context, userID uint) ([]*entity.Address, error) {
+	var addresses []*entity.Address
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("is_default DESC, id ASC").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// GetDefaultByUserID gets the default address of a user
+func (r *addressRepository) GetDefaultByUserID(ctx context.Context, userID uint) (*entity.Address, error) {
+	var address entity.Address
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND is_default = ?", userID, true).First(&address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("address not found")
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+// Update updates an address
+func (r *addressRepository) Update(ctx context.Context, address *entity.Address) error {
+	return r.db.WithContext(ctx).Save(address).Error
+}
+
+// Delete deletes an address
+func (r *addressRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Address{}, id).Error
+}
+
+// SetDefault makes an address the default of its user, unsetting the previous default
+func (r *addressRepository) SetDefault(ctx context.Context, id uint, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Address{}).
+			Where("user_id = ? AND is_default = ?", userID, true).
+			Update("is_default", false).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.Address{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("is_default", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("address not found")
+		}
+		return nil
+	})
+}
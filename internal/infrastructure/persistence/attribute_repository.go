@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// relatedCategoriesSQL selects the categories above and below a category in
+// the tree, including the category itself
+const relatedCategoriesSQL = `SELECT c.id FROM categories c JOIN categories target ON target.id = ?
+	WHERE c.deleted_at IS NULL AND c.path <> '' AND target.path <> ''
+		AND (c.path LIKE target.path || '%' OR target.path LIKE c.path || '%')`
+
+type attributeRepository struct {
+	db *gorm.DB
+}
+
+// NewAttributeRepository creates a new AttributeRepository instance
+func NewAttributeRepository(db *gorm.DB) repository.AttributeRepository {
+	return &attributeRepository{
+		db: db,
+	}
+}
+
+// Create creates an attribute definition. The database refuses a code
+// already defined along the category's path or in its subtree.
+func (r *attributeRepository) Create(ctx context.Context, attribute *entity.AttributeDefinition) error {
+	err := r.db.WithContext(ctx).Create(attribute).Error
+	if isUniqueViolation(err) {
+		return repository.ErrAttributeCodeExists
+	}
+	return err
+}
+
+// GetByID gets an attribute definition by ID
+func (r *attributeRepository) GetByID(ctx context.Context, id uint) (*entity.AttributeDefinition, error) {
+	var attribute entity.AttributeDefinition
+	if err := r.db.WithContext(ctx).First(&attribute, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attribute not found")
+		}
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+// Update updates an attribute definition
+func (r *attributeRepository) Update(ctx context.Context, attribute *entity.AttributeDefinition) error {
+	return r.db.WithContext(ctx).Save(attribute).Error
+}
+
+// Delete deletes an attribute definition. Values stored on products are left
+// in place and ignored.
+func (r *attributeRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.AttributeDefinition{}, id).Error
+}
+
+// GetByCategoryIDs gets the attribute definitions of categories
+func (r *attributeRepository) GetByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]*entity.AttributeDefinition, error) {
+	var attributes []*entity.AttributeDefinition
+	err := r.db.WithContext(ctx).
+		Where("category_id IN ?", categoryIDs).
+		Order("sort_order, id").
+		Find(&attributes).Error
+	return attributes, err
+}
+
+// GetFilterable gets the filterable attribute definitions that apply to the
+// products listed under a category: those of the category, its ancestors and
+// its subcategories
+func (r *attributeRepository) GetFilterable(ctx context.Context, categoryID *uint) ([]*entity.AttributeDefinition, error) {
+	query := r.db.WithContext(ctx).Where("filterable = ?", true)
+	if categoryID != nil {
+		query = query.Where("category_id IN ("+relatedCategoriesSQL+")", *categoryID)
+	}
+
+	var attributes []*entity.AttributeDefinition
+	err := query.Order("sort_order, id").Find(&attributes).Error
+	return attributes, err
+}
+
+// CodeExists reports whether a code is already defined on the category, its
+// ancestors or its subcategories by another attribute definition
+func (r *attributeRepository) CodeExists(ctx context.Context, categoryID uint, code string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.AttributeDefinition{}).
+		Where("code = ? AND id <> ?", code, excludeID).
+		Where("category_id IN ("+relatedCategoriesSQL+")", categoryID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+// NewCartRepository creates a new CartRepository instance
+func NewCartRepository(db *gorm.DB) repository.CartRepository {
+	return &cartRepository{
+		db: db,
+	}
+}
+
+// GetOrCreate gets the cart of a user, creating an empty one on first use
+func (r *cartRepository) GetOrCreate(ctx context.Context, userID uint) (*entity.Cart, error) {
+	cart := &entity.Cart{UserID: userID}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Omit("User", "Items").
+		Create(cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByUserID(ctx, userID)
+}
+
+// GetByID gets a cart by ID with its items and their products
+func (r *cartRepository) GetByID(ctx context.Context, id uint) (*entity.Cart, error) {
+	var cart entity.Cart
+	if err := r.withItems(r.db.WithContext(ctx)).First(&cart, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cart not found")
+		}
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// GetByUserID gets the cart of a user with its items and their products
+func (r *cartRepository) GetByUserID(ctx context.Context, userID uint) (*entity.Cart, error) {
+	var cart entity.Cart
+	if err := r.withItems(r.db.WithContext(ctx)).Where("user_id = ?", userID).First(&cart).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cart not found")
+		}
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// AddItem adds an item to a cart, adding to the quantity when the variant is already in it
+func (r *cartRepository) AddItem(ctx context.Context, cartID uint, item *entity.CartItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing entity.CartItem
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("cart_id = ? AND variant_id = ?", cartID, item.VariantID).
+			First(&existing).Error
+		if err == nil {
+			existing.Quantity += item.Quantity
+			if err := tx.Omit("Product").Save(&existing).Error; err != nil {
+				return err
+			}
+			*item = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		item.CartID = cartID
+		return tx.Omit("Product").Create(item).Error
+	})
+}
+
+// UpdateItem updates a cart item
+func (r *cartRepository) UpdateItem(ctx context.Context, item *entity.CartItem) error {
+	return r.db.WithContext(ctx).Omit("Product").Save(item).Error
+}
+
+// RemoveItem removes an item from a cart
+func (r *cartRepository) RemoveItem(ctx context.Context, itemID uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.CartItem{}, itemID).Error
+}
+
+// ClearCart removes every item and the voucher from a cart
+func (r *cartRepository) ClearCart(ctx context.Context, cartID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Cart{}).Where("id = ?", cartID).Update("voucher_code", "").Error; err != nil {
+			return err
+		}
+		return tx.Where("cart_id = ?", cartID).Delete(&entity.CartItem{}).Error
+	})
+}
+
+// GetTotalItems gets the number of units in a cart
+func (r *cartRepository) GetTotalItems(ctx context.Context, cartID uint) (int, error) {
+	var total int
+	err := r.db.WithContext(ctx).
+		Model(&entity.CartItem{}).
+		Where("cart_id = ?", cartID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetTotalAmount gets the list price of a cart, using discount prices where set
+func (r *cartRepository) GetTotalAmount(ctx context.Context, cartID uint) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).
+		Model(&entity.CartItem{}).
+		Joins("JOIN products ON products.id = cart_items.product_id").
+		Where("cart_items.cart_id = ?", cartID).
+		Select(`COALESCE(SUM(cart_items.quantity * CASE
+			WHEN products.discount_price > 0 AND products.discount_price < products.price THEN products.discount_price
+			ELSE products.price END), 0)`).
+		Scan(&total).Error
+	return total, err
+}
+
+// SetVoucherCode sets the voucher applied to a cart, or removes it with an empty code
+func (r *cartRepository) SetVoucherCode(ctx context.Context, cartID uint, code string) error {
+	return r.db.WithContext(ctx).Model(&entity.Cart{}).Where("id = ?", cartID).Update("voucher_code", code).Error
+}
+
+// withItems preloads the items of a cart, oldest first, with their products
+func (r *cartRepository) withItems(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Items.Product")
+}
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new CategoryRepository instance
+func NewCategoryRepository(db *gorm.DB) repository.CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+// Create creates a new category. The database fills in its path and depth.
+func (r *categoryRepository) Create(ctx context.Context, category *entity.Category) error {
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		return err
+	}
+	// Read back the path and depth the database set
+	return r.db.WithContext(ctx).Select("path", "depth").First(category, category.ID).Error
+}
+
+// GetByID gets a category by ID
+func (r *categoryRepository) GetByID(ctx context.Context, id uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetBySlug gets a category by slug
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*entity.Category, error) {
+	var category entity.Category
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Update updates a category. Its place in the tree only changes through
+// CategoryTreeRepository.Move.
+func (r *categoryRepository) Update(ctx context.Context, category *entity.Category) error {
+	return r.db.WithContext(ctx).Omit("parent_id", "sort_order", "Parent").Save(category).Error
+}
+
+// Delete deletes a category
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Category{}, id).Error
+}
+
+// List lists the subcategories of a category in tree order, or the top-level
+// categories when parentID is nil
+func (r *categoryRepository) List(ctx context.Context, parentID *uint) ([]*entity.Category, error) {
+	query := r.db.WithContext(ctx)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	var categories []*entity.Category
+	if err := query.Order(categoryTreeOrder).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
@@ -0,0 +1,287 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categoryTreeOrder orders categories as they appear in the tree
+const categoryTreeOrder = "depth, sort_order, name, id"
+
+type categoryTreeRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryTreeRepository creates a new CategoryTreeRepository instance
+func NewCategoryTreeRepository(db *gorm.DB) repository.CategoryTreeRepository {
+	return &categoryTreeRepository{
+		db: db,
+	}
+}
+
+// GetAll gets every category, parents before their children
+func (r *categoryTreeRepository) GetAll(ctx context.Context) ([]*entity.Category, error) {
+	var categories []*entity.Category
+	err := r.db.WithContext(ctx).Order(categoryTreeOrder).Find(&categories).Error
+	return categories, err
+}
+
+// GetSubtree gets a category and all its descendants, parents before their children
+func (r *categoryTreeRepository) GetSubtree(ctx context.Context, id uint) ([]*entity.Category, error) {
+	category, err := r.getByID(r.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := subtreePattern(category)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []*entity.Category
+	err = r.db.WithContext(ctx).
+		Where("path LIKE ?", pattern).
+		Order(categoryTreeOrder).
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetAncestors gets the categories on the path from the root down to a category
+func (r *categoryTreeRepository) GetAncestors(ctx context.Context, id uint) ([]*entity.Category, error) {
+	category, err := r.getByID(r.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := pathIDs(category.Path)
+	var categories []*entity.Category
+	err = r.db.WithContext(ctx).Where("id IN ?", ids).Order("depth").Find(&categories).Error
+	return categories, err
+}
+
+// Move places a category under a new parent at a position among its siblings.
+// The database rewrites the paths of the moved subtree.
+func (r *categoryTreeRepository) Move(ctx context.Context, id uint, parentID *uint, position int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the category and its new parent in ID order so concurrent
+		// moves cannot deadlock or build a cycle between them
+		ids := []uint{id}
+		if parentID != nil {
+			ids = append(ids, *parentID)
+		}
+		var locked []*entity.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&locked).Error; err != nil {
+			return err
+		}
+
+		var category, parent *entity.Category
+		for _, c := range locked {
+			if c.ID == id {
+				category = c
+			}
+			if parentID != nil && c.ID == *parentID {
+				parent = c
+			}
+		}
+		if category == nil {
+			return errors.New("category not found")
+		}
+		if parentID != nil && parent == nil {
+			return errors.New("parent category not found")
+		}
+		if _, err := subtreePattern(category); err != nil {
+			return err
+		}
+		if parent != nil && strings.HasPrefix(parent.Path, category.Path) {
+			return repository.ErrCategoryCycle
+		}
+
+		siblings, err := r.getChildren(tx, parentID, id)
+		if err != nil {
+			return err
+		}
+		position = min(max(position, 0), len(siblings))
+
+		err = tx.Model(&entity.Category{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"parent_id":  parentID,
+			"sort_order": position,
+		}).Error
+		if isUniqueViolation(err) {
+			return repository.ErrAttributeCodeExists
+		}
+		if err != nil {
+			return err
+		}
+
+		// Renumber the new siblings around the moved category, and close the
+		// gap it left behind when it changed parent
+		ordered := make([]*entity.Category, 0, len(siblings)+1)
+		ordered = append(ordered, siblings[:position]...)
+		ordered = append(ordered, category)
+		ordered = append(ordered, siblings[position:]...)
+		if err := r.renumber(tx, ordered, id); err != nil {
+			return err
+		}
+		if !sameParent(category.ParentID, parentID) {
+			previous, err := r.getChildren(tx, category.ParentID, id)
+			if err != nil {
+				return err
+			}
+			return r.renumber(tx, previous, id)
+		}
+		return nil
+	})
+}
+
+// Delete deletes a category, handling its subcategories and products by rule
+func (r *categoryTreeRepository) Delete(ctx context.Context, id uint, rule entity.CategoryDeleteRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category entity.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("category not found")
+			}
+			return err
+		}
+
+		switch rule {
+		case entity.CategoryDeleteCascade:
+			pattern, err := subtreePattern(&category)
+			if err != nil {
+				return err
+			}
+			subtree := tx.Model(&entity.Category{}).Select("id").Where("path LIKE ?", pattern)
+			err = tx.Model(&entity.Product{}).
+				Where("category_id IN (?)", subtree).
+				Update("is_active", false).Error
+			if err != nil {
+				return err
+			}
+			return tx.Where("path LIKE ?", pattern).Delete(&entity.Category{}).Error
+
+		case entity.CategoryDeleteReparent:
+			var products int64
+			if err := tx.Model(&entity.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+				return err
+			}
+			if products > 0 {
+				if category.ParentID == nil {
+					return repository.ErrCategoryNoParent
+				}
+				err := tx.Model(&entity.Product{}).Where("category_id = ?", id).Update("category_id", *category.ParentID).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			// Subcategories keep their order, placed after the parent's own children
+			siblings, err := r.getChildren(tx, category.ParentID, id)
+			if err != nil {
+				return err
+			}
+			if err := r.renumber(tx, siblings, 0); err != nil {
+				return err
+			}
+			err = tx.Model(&entity.Category{}).Where("parent_id = ?", id).Updates(map[string]interface{}{
+				"parent_id":  category.ParentID,
+				"sort_order": gorm.Expr("sort_order + ?", len(siblings)),
+			}).Error
+			if err != nil {
+				return err
+			}
+			return tx.Delete(&category).Error
+
+		default:
+			var children, products int64
+			if err := tx.Model(&entity.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+				return err
+			}
+			if children > 0 || products > 0 {
+				return repository.ErrCategoryNotEmpty
+			}
+			return tx.Delete(&category).Error
+		}
+	})
+}
+
+// getByID gets a category by ID
+func (r *categoryTreeRepository) getByID(db *gorm.DB, id uint) (*entity.Category, error) {
+	var category entity.Category
+	if err := db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// getChildren gets the children of a parent in order, leaving out one category
+func (r *categoryTreeRepository) getChildren(tx *gorm.DB, parentID *uint, except uint) ([]*entity.Category, error) {
+	query := tx.Where("id <> ?", except)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	var children []*entity.Category
+	err := query.Order("sort_order, name, id").Find(&children).Error
+	return children, err
+}
+
+// renumber stores the list positions of categories as their sort order,
+// skipping the already updated category and the ones already in place
+func (r *categoryTreeRepository) renumber(tx *gorm.DB, categories []*entity.Category, skip uint) error {
+	for i, category := range categories {
+		if category.ID == skip || category.SortOrder == i {
+			continue
+		}
+		if err := tx.Model(&entity.Category{}).Where("id = ?", category.ID).Update("sort_order", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameParent reports whether two parent IDs point to the same parent
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// subtreePattern returns the LIKE pattern matching a category and its
+// descendants. A category without a path would match every category, so
+// it is refused.
+func subtreePattern(category *entity.Category) (string, error) {
+	if category.Path == "" {
+		return "", fmt.Errorf("category %d has no tree path", category.ID)
+	}
+	return category.Path + "%", nil
+}
+
+// pathIDs parses the category IDs of a materialised path such as /1/4/9/
+func pathIDs(path string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
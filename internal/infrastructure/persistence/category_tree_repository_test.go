@@ -0,0 +1,28 @@
+package persistence
+
+import (
+	"slices"
+	"testing"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestSubtreePatternRefusesCategoriesWithoutPath(t *testing.T) {
+	if _, err := subtreePattern(&entity.Category{ID: 3}); err == nil {
+		t.Fatal("subtreePattern() error = nil, want an error for the empty path")
+	}
+
+	pattern, err := subtreePattern(&entity.Category{ID: 9, Path: "/1/4/9/"})
+	if err != nil || pattern != "/1/4/9/%" {
+		t.Errorf("subtreePattern() = %q, %v, want /1/4/9/%%", pattern, err)
+	}
+}
+
+func TestPathIDs(t *testing.T) {
+	if ids := pathIDs("/1/4/9/"); !slices.Equal(ids, []uint{1, 4, 9}) {
+		t.Errorf("pathIDs() = %v, want [1 4 9]", ids)
+	}
+	if ids := pathIDs(""); len(ids) != 0 {
+		t.Errorf("pathIDs(\"\") = %v, want none", ids)
+	}
+}
@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type codRemittanceRepository struct {
+	db *gorm.DB
+}
+
+// NewCODRemittanceRepository creates a new CODRemittanceRepository instance
+func NewCODRemittanceRepository(db *gorm.DB) repository.CODRemittanceRepository {
+	return &codRemittanceRepository{
+		db: db,
+	}
+}
+
+// CreateBatch creates a remittance batch together with its lines and settles
+// the payments of its matched lines in the same transaction, so a batch is
+// never lost for settled payments. A line whose payment was settled or
+// cancelled in the meantime is recorded as a duplicate. Order status changes
+// stop at the first one that no longer applies.
+func (r *codRemittanceRepository) CreateBatch(ctx context.Context, batch *entity.CODRemittanceBatch, settlements []*repository.CODSettlement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, settlement := range settlements {
+			line := &batch.Lines[settlement.Line]
+
+			result := tx.Model(&entity.Payment{}).
+				Where("id = ? AND status = ?", settlement.PaymentID, entity.PaymentStatusPending).
+				Updates(map[string]interface{}{"status": entity.PaymentStatusPaid, "paid_at": settlement.PaidAt})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != 1 {
+				line.Status = entity.CODRemittanceStatusDuplicate
+				line.Message = "payment was already settled"
+				batch.MatchedRows--
+				batch.DuplicateRows++
+				continue
+			}
+
+			for _, change := range settlement.Changes {
+				applied, err := applyOrderStatusChange(tx, change)
+				if err != nil {
+					return err
+				}
+				if !applied {
+					break
+				}
+			}
+		}
+
+		return tx.Create(batch).Error
+	})
+}
+
+// GetBatchByID gets a remittance batch with its lines
+func (r *codRemittanceRepository) GetBatchByID(ctx context.Context, id uint) (*entity.CODRemittanceBatch, error) {
+	var batch entity.CODRemittanceBatch
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&batch, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("remittance batch not found")
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// ListBatches lists remittance batches, newest first
+func (r *codRemittanceRepository) ListBatches(ctx context.Context, offset, limit int) ([]*entity.CODRemittanceBatch, int64, error) {
+	var batches []*entity.CODRemittanceBatch
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.CODRemittanceBatch{})
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&batches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return batches, count, nil
+}
+
+// ListBatchesByCursor lists remittance batches, one page after or before a cursor
+func (r *codRemittanceRepository) ListBatchesByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.CODRemittanceBatch, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.CODRemittanceBatch{})
+	return findByCursor(query, "cod_remittance_batches", page, func(batch *entity.CODRemittanceBatch) entity.Cursor {
+		return entity.Cursor{CreatedAt: batch.CreatedAt, ID: batch.ID}
+	})
+}
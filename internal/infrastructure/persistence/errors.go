@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a unique constraint violation,
+// raised by an index or by a trigger enforcing a uniqueness rule
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,243 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errExchangeNotApplied rolls back an approval whose exchange is no longer requested
+var errExchangeNotApplied = errors.New("exchange is no longer requested")
+
+type exchangeRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangeRepository creates a new ExchangeRepository instance
+func NewExchangeRepository(db *gorm.DB) repository.ExchangeRepository {
+	return &exchangeRepository{
+		db: db,
+	}
+}
+
+// NextExchangeSequence gets the next number of the exchange number sequence
+func (r *exchangeRepository) NextExchangeSequence(ctx context.Context) (int64, error) {
+	var next int64
+	if err := r.db.WithContext(ctx).Raw("SELECT nextval('exchange_requests_number_seq')").Scan(&next).Error; err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// Create creates an exchange request together with its items and reserves the
+// stock of the replacement variants. The order is locked while check runs, so
+// two requests cannot both claim the last exchangeable quantity of an item.
+func (r *exchangeRepository) Create(ctx context.Context, exchange *entity.ExchangeRequest, check repository.AfterSalesCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkAfterSales(tx, exchange.OrderID, check); err != nil {
+			return err
+		}
+
+		for _, item := range exchange.Items {
+			result := tx.Model(&entity.ProductVariant{}).
+				Where("id = ? AND stock >= ?", item.NewVariantID, item.Quantity).
+				Update("stock", gorm.Expr("stock - ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%s is out of stock in the selected variant", item.ProductName)
+			}
+		}
+
+		return tx.Create(exchange).Error
+	})
+}
+
+// GetByID gets an exchange request by ID
+func (r *exchangeRepository) GetByID(ctx context.Context, id uint) (*entity.ExchangeRequest, error) {
+	var exchange entity.ExchangeRequest
+	if err := r.db.WithContext(ctx).Preload("Items").First(&exchange, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("exchange request not found")
+		}
+		return nil, err
+	}
+	return &exchange, nil
+}
+
+// GetByOrderID gets every exchange request of an order, oldest first
+func (r *exchangeRepository) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.ExchangeRequest, error) {
+	var exchanges []*entity.ExchangeRequest
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&exchanges).Error
+	if err != nil {
+		return nil, err
+	}
+	return exchanges, nil
+}
+
+// GetByUserID gets exchange requests by user ID with pagination
+func (r *exchangeRepository) GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.ExchangeRequest, int64, error) {
+	var exchanges []*entity.ExchangeRequest
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.ExchangeRequest{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Items").Order("created_at DESC").Offset(offset).Limit(limit).Find(&exchanges).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return exchanges, count, nil
+}
+
+// UpdateIfStatus saves an exchange request only while its stored status is still
+// one of the expected statuses, so concurrent updates apply at most once
+func (r *exchangeRepository) UpdateIfStatus(ctx context.Context, exchange *entity.ExchangeRequest, expected ...entity.ExchangeStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ExchangeRequest{}).
+		Where("id = ? AND status IN ?", exchange.ID, expected).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(exchange)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Approve saves an approved exchange request and creates its replacement order
+// together with the entry of its first status, all in one transaction. It
+// returns false, changing nothing, when the exchange is no longer requested.
+func (r *exchangeRepository) Approve(ctx context.Context, exchange *entity.ExchangeRequest, replacement *entity.Order, created *entity.OrderStatusHistory) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(replacement).Error; err != nil {
+			return err
+		}
+		exchange.ReplacementOrderID = &replacement.ID
+
+		result := tx.Model(&entity.ExchangeRequest{}).
+			Where("id = ? AND status = ?", exchange.ID, entity.ExchangeStatusRequested).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(exchange)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			// Roll the replacement order back
+			return errExchangeNotApplied
+		}
+
+		created.OrderID = replacement.ID
+		return tx.Create(created).Error
+	})
+	if err != nil {
+		exchange.ReplacementOrderID = nil
+		if errors.Is(err, errExchangeNotApplied) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Receive saves a received exchange request and puts its original items back
+// in stock in the same transaction, only while its stored status is still one
+// of the expected statuses
+func (r *exchangeRepository) Receive(ctx context.Context, exchange *entity.ExchangeRequest, expected ...entity.ExchangeStatus) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.ExchangeRequest{}).
+			Where("id = ? AND status IN ?", exchange.ID, expected).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(exchange)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		applied = true
+
+		for _, item := range exchange.Items {
+			err := tx.Model(&entity.ProductVariant{}).
+				Where("id = ?", item.OldVariantID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error
+			if err != nil {
+				return fmt.Errorf("restock variant %d: %w", item.OldVariantID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// GetAwaitingTopUp gets approved exchanges whose replacement order was
+// cancelled, or still awaits the price difference and was approved before
+// approvedBefore, oldest first
+func (r *exchangeRepository) GetAwaitingTopUp(ctx context.Context, approvedBefore time.Time, limit int) ([]*entity.ExchangeRequest, error) {
+	var exchanges []*entity.ExchangeRequest
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Joins("JOIN orders ON orders.id = exchange_requests.replacement_order_id").
+		Where("exchange_requests.status = ?", entity.ExchangeStatusApproved).
+		Where("orders.status = ? OR (orders.status = ? AND exchange_requests.approved_at < ?)",
+			entity.OrderStatusCancelled, entity.OrderStatusPending, approvedBefore).
+		Order("exchange_requests.approved_at ASC").
+		Limit(limit).
+		Find(&exchanges).Error
+	if err != nil {
+		return nil, err
+	}
+	return exchanges, nil
+}
+
+// List lists exchange requests with filter and pagination
+func (r *exchangeRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.ExchangeRequest, int64, error) {
+	var exchanges []*entity.ExchangeRequest
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.ExchangeRequest{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Items").Order("created_at DESC").Offset(offset).Limit(limit).Find(&exchanges).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return exchanges, count, nil
+}
+
+// ListByCursor lists exchange requests with filter, one page after or before a cursor
+func (r *exchangeRepository) ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ExchangeRequest, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.ExchangeRequest{}).Preload("Items")
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return findByCursor(query, "exchange_requests", page, func(request *entity.ExchangeRequest) entity.Cursor {
+		return entity.Cursor{CreatedAt: request.CreatedAt, ID: request.ID}
+	})
+}
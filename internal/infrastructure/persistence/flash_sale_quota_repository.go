@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveFlashSaleQuota atomically takes quantity units of an item's
+// remaining quota, enforcing the per-user limit when there is one.
+// It returns 1 on success, -1 when a counter is not loaded, -2 when sold out
+// and -3 when the user's limit would be exceeded.
+var reserveFlashSaleQuota = redis.NewScript(`
+local remaining = redis.call('GET', KEYS[1])
+if not remaining then return -1 end
+local quantity = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+if limit > 0 then
+	local purchased = redis.call('GET', KEYS[2])
+	if not purchased then return -1 end
+	if tonumber(purchased) + quantity > limit then return -3 end
+end
+if tonumber(remaining) < quantity then return -2 end
+redis.call('DECRBY', KEYS[1], quantity)
+if limit > 0 then redis.call('INCRBY', KEYS[2], quantity) end
+return 1
+`)
+
+// releaseFlashSaleQuota gives units back to the counters that are still loaded
+var releaseFlashSaleQuota = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then redis.call('INCRBY', KEYS[1], ARGV[1]) end
+if redis.call('EXISTS', KEYS[2]) == 1 then redis.call('DECRBY', KEYS[2], ARGV[1]) end
+return 1
+`)
+
+type flashSaleQuotaRepository struct {
+	redisClient *redis.Client
+}
+
+// NewFlashSaleQuotaRepository creates a new FlashSaleQuotaRepository instance
+func NewFlashSaleQuotaRepository(redisClient *redis.Client) repository.FlashSaleQuotaRepository {
+	return &flashSaleQuotaRepository{
+		redisClient: redisClient,
+	}
+}
+
+// LoadRemaining sets an item's remaining quota unless it is already loaded
+func (r *flashSaleQuotaRepository) LoadRemaining(ctx context.Context, itemID uint, remaining int, ttl time.Duration) error {
+	return r.redisClient.SetNX(ctx, remainingQuotaKey(itemID), remaining, ttl).Err()
+}
+
+// LoadUserPurchased sets how many units of an item a user bought unless it is already loaded
+func (r *flashSaleQuotaRepository) LoadUserPurchased(ctx context.Context, itemID, userID uint, purchased int, ttl time.Duration) error {
+	return r.redisClient.SetNX(ctx, userPurchasedKey(itemID, userID), purchased, ttl).Err()
+}
+
+// Reserve takes units of an item's quota for a user
+func (r *flashSaleQuotaRepository) Reserve(ctx context.Context, itemID, userID uint, quantity, perUserLimit int) error {
+	keys := []string{remainingQuotaKey(itemID), userPurchasedKey(itemID, userID)}
+	result, err := reserveFlashSaleQuota.Run(ctx, r.redisClient, keys, quantity, perUserLimit).Int()
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case 1:
+		return nil
+	case -1:
+		return repository.ErrFlashSaleQuotaNotLoaded
+	case -2:
+		return repository.ErrFlashSaleSoldOut
+	case -3:
+		return repository.ErrFlashSaleUserLimit
+	default:
+		return fmt.Errorf("unexpected flash sale quota result %d", result)
+	}
+}
+
+// Release gives units of an item's quota back
+func (r *flashSaleQuotaRepository) Release(ctx context.Context, itemID, userID uint, quantity int) error {
+	keys := []string{remainingQuotaKey(itemID), userPurchasedKey(itemID, userID)}
+	return releaseFlashSaleQuota.Run(ctx, r.redisClient, keys, quantity).Err()
+}
+
+// Clear removes an item's remaining quota once its sale is over; per-user
+// counters expire on their own
+func (r *flashSaleQuotaRepository) Clear(ctx context.Context, itemID uint) error {
+	return r.redisClient.Del(ctx, remainingQuotaKey(itemID)).Err()
+}
+
+func remainingQuotaKey(itemID uint) string {
+	return fmt.Sprintf("flash_sale:item:%d:remaining", itemID)
+}
+
+func userPurchasedKey(itemID, userID uint) string {
+	return fmt.Sprintf("flash_sale:item:%d:user:%d", itemID, userID)
+}
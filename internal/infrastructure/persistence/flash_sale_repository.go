@@ -0,0 +1,257 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type flashSaleRepository struct {
+	db *gorm.DB
+}
+
+// NewFlashSaleRepository creates a new FlashSaleRepository instance
+func NewFlashSaleRepository(db *gorm.DB) repository.FlashSaleRepository {
+	return &flashSaleRepository{
+		db: db,
+	}
+}
+
+// Create creates a flash sale together with its items
+func (r *flashSaleRepository) Create(ctx context.Context, sale *entity.FlashSale) error {
+	return r.db.WithContext(ctx).Create(sale).Error
+}
+
+// GetByID gets a flash sale by ID
+func (r *flashSaleRepository) GetByID(ctx context.Context, id uint) (*entity.FlashSale, error) {
+	var sale entity.FlashSale
+	if err := r.db.WithContext(ctx).Preload("Items").First(&sale, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("flash sale not found")
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// Update updates a flash sale and replaces its items
+func (r *flashSaleRepository) Update(ctx context.Context, sale *entity.FlashSale) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.FlashSale{}).
+			Where("id = ?", sale.ID).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(sale).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("flash_sale_id = ?", sale.ID).Delete(&entity.FlashSaleItem{}).Error; err != nil {
+			return err
+		}
+		for i := range sale.Items {
+			sale.Items[i].ID = 0
+			sale.Items[i].FlashSaleID = sale.ID
+		}
+		if len(sale.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&sale.Items).Error
+	})
+}
+
+// UpdateIfStatus saves a flash sale only while its stored status is still
+// one of the expected statuses, so concurrent updates apply at most once
+func (r *flashSaleRepository) UpdateIfStatus(ctx context.Context, sale *entity.FlashSale, expected ...entity.FlashSaleStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.FlashSale{}).
+		Where("id = ? AND status IN ?", sale.ID, expected).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(sale)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// List lists flash sales with filter and pagination
+func (r *flashSaleRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.FlashSale, int64, error) {
+	var sales []*entity.FlashSale
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.FlashSale{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Items").Order("starts_at DESC").Offset(offset).Limit(limit).Find(&sales).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return sales, count, nil
+}
+
+// GetCurrent gets the flash sales that are running or still to come, soonest first
+func (r *flashSaleRepository) GetCurrent(ctx context.Context, at time.Time) ([]*entity.FlashSale, error) {
+	var sales []*entity.FlashSale
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("status IN ? AND ends_at > ?", []entity.FlashSaleStatus{entity.FlashSaleStatusScheduled, entity.FlashSaleStatusActive}, at).
+		Order("starts_at ASC").
+		Find(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// GetDueForStatusChange gets the scheduled flash sales that should have
+// started and the running ones that should have ended by the given time
+func (r *flashSaleRepository) GetDueForStatusChange(ctx context.Context, at time.Time) ([]*entity.FlashSale, error) {
+	var sales []*entity.FlashSale
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("(status = ? AND starts_at <= ?) OR (status = ? AND ends_at <= ?)",
+			entity.FlashSaleStatusScheduled, at, entity.FlashSaleStatusActive, at).
+		Order("starts_at ASC").
+		Find(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// GetOverlappingVariantIDs gets which of the given variants are already in
+// another scheduled or running flash sale overlapping the given period
+func (r *flashSaleRepository) GetOverlappingVariantIDs(ctx context.Context, variantIDs []uint, start, end time.Time, excludeSaleID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&entity.FlashSaleItem{}).
+		Joins("JOIN flash_sales ON flash_sales.id = flash_sale_items.flash_sale_id").
+		Where("flash_sale_items.variant_id IN ?", variantIDs).
+		Where("flash_sales.id <> ?", excludeSaleID).
+		Where("flash_sales.status IN ?", []entity.FlashSaleStatus{entity.FlashSaleStatusScheduled, entity.FlashSaleStatusActive}).
+		Where("flash_sales.starts_at < ? AND flash_sales.ends_at > ?", end, start).
+		Distinct().
+		Pluck("flash_sale_items.variant_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetLiveItemsByProductIDs gets the flash sale items of the given products that are on sale at the given time
+func (r *flashSaleRepository) GetLiveItemsByProductIDs(ctx context.Context, productIDs []uint, at time.Time) ([]*entity.FlashSaleItem, error) {
+	var items []*entity.FlashSaleItem
+	err := r.liveItems(ctx, at).
+		Where("flash_sale_items.product_id IN ?", productIDs).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetLiveItemByVariantID gets the flash sale item of a variant on sale at the
+// given time. It returns nil without an error when the variant is not on sale.
+func (r *flashSaleRepository) GetLiveItemByVariantID(ctx context.Context, variantID uint, at time.Time) (*entity.FlashSaleItem, error) {
+	var item entity.FlashSaleItem
+	err := r.liveItems(ctx, at).
+		Where("flash_sale_items.variant_id = ?", variantID).
+		First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// liveItems builds a query for flash sale items of running campaigns
+func (r *flashSaleRepository) liveItems(ctx context.Context, at time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Preload("FlashSale").
+		Joins("JOIN flash_sales ON flash_sales.id = flash_sale_items.flash_sale_id").
+		Where("flash_sales.status = ? AND flash_sales.starts_at <= ? AND flash_sales.ends_at > ?", entity.FlashSaleStatusActive, at, at)
+}
+
+// CountUserPurchased counts the units of a flash sale item a user bought on orders that were not cancelled
+func (r *flashSaleRepository) CountUserPurchased(ctx context.Context, itemID, userID uint) (int, error) {
+	var purchased int
+	err := r.db.WithContext(ctx).
+		Model(&entity.FlashSalePurchase{}).
+		Where("flash_sale_item_id = ? AND user_id = ? AND released_at IS NULL", itemID, userID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&purchased).Error
+	return purchased, err
+}
+
+// recordFlashSalePurchase records flash sale units bought on an order within
+// tx. The item row is locked while the quota is checked, so the sold count
+// never exceeds it even if the live counters were lost.
+func recordFlashSalePurchase(tx *gorm.DB, purchase *entity.FlashSalePurchase) error {
+	var item entity.FlashSaleItem
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, purchase.FlashSaleItemID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("flash sale item not found")
+		}
+		return err
+	}
+
+	if item.Sold+purchase.Quantity > item.Quota {
+		return repository.ErrFlashSaleSoldOut
+	}
+
+	if err := tx.Create(purchase).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entity.FlashSaleItem{}).
+		Where("id = ?", item.ID).
+		Update("sold", gorm.Expr("sold + ?", purchase.Quantity)).Error
+}
+
+// ReleasePurchases gives back the flash sale units bought on an order and
+// returns the purchases released
+func (r *flashSaleRepository) ReleasePurchases(ctx context.Context, orderID uint) ([]*entity.FlashSalePurchase, error) {
+	var purchases []*entity.FlashSalePurchase
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND released_at IS NULL", orderID).
+			Find(&purchases).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, purchase := range purchases {
+			purchase.ReleasedAt = &now
+			if err := tx.Model(purchase).Update("released_at", now).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&entity.FlashSaleItem{}).
+				Where("id = ?", purchase.FlashSaleItemID).
+				Update("sold", gorm.Expr("GREATEST(sold - ?, 0)", purchase.Quantity)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type giftCardRepository struct {
+	db *gorm.DB
+}
+
+// NewGiftCardRepository creates a new GiftCardRepository instance
+func NewGiftCardRepository(db *gorm.DB) repository.GiftCardRepository {
+	return &giftCardRepository{
+		db: db,
+	}
+}
+
+// Create creates a gift card
+func (r *giftCardRepository) Create(ctx context.Context, card *entity.GiftCard) error {
+	return r.db.WithContext(ctx).Create(card).Error
+}
+
+// GetByID gets a gift card by ID
+func (r *giftCardRepository) GetByID(ctx context.Context, id uint) (*entity.GiftCard, error) {
+	var card entity.GiftCard
+	if err := r.db.WithContext(ctx).First(&card, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("gift card not found")
+		}
+		return nil, err
+	}
+	return &card, nil
+}
+
+// GetByCode gets a gift card by code
+func (r *giftCardRepository) GetByCode(ctx context.Context, code string) (*entity.GiftCard, error) {
+	var card entity.GiftCard
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("gift card not found")
+		}
+		return nil, err
+	}
+	return &card, nil
+}
+
+// GetByOrderID gets the gift cards paid for by an order
+func (r *giftCardRepository) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.GiftCard, error) {
+	var cards []*entity.GiftCard
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id").Find(&cards).Error
+	return cards, err
+}
+
+// GetByPurchaser gets the gift cards bought by a user
+func (r *giftCardRepository) GetByPurchaser(ctx context.Context, userID uint, offset, limit int) ([]*entity.GiftCard, int64, error) {
+	return r.List(ctx, map[string]interface{}{"purchaser_id": userID}, offset, limit)
+}
+
+// List lists gift cards with filter and pagination
+func (r *giftCardRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.GiftCard, int64, error) {
+	var cards []*entity.GiftCard
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.GiftCard{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&cards).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return cards, count, nil
+}
+
+// ListByCursor lists gift cards with filter, one page after or before a cursor
+func (r *giftCardRepository) ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.GiftCard, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.GiftCard{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return findByCursor(query, "gift_cards", page, func(card *entity.GiftCard) entity.Cursor {
+		return entity.Cursor{CreatedAt: card.CreatedAt, ID: card.ID}
+	})
+}
+
+// UpdateIfStatus saves a gift card only while its stored status is still one
+// of expected, and reports whether it was saved
+func (r *giftCardRepository) UpdateIfStatus(ctx context.Context, card *entity.GiftCard, expected ...entity.GiftCardStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.GiftCard{}).
+		Where("id = ? AND status IN ?", card.ID, expected).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(card)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Redeem marks an active gift card redeemed and credits its balance to a
+// wallet in one transaction, so a code can only be redeemed once
+func (r *giftCardRepository) Redeem(ctx context.Context, card *entity.GiftCard, transaction *entity.WalletTransaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.GiftCard{}).
+			Where("id = ? AND status = ?", card.ID, entity.GiftCardStatusActive).
+			Updates(map[string]interface{}{
+				"status":      entity.GiftCardStatusRedeemed,
+				"balance":     0,
+				"redeemed_by": card.RedeemedBy,
+				"redeemed_at": card.RedeemedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errors.New("gift card has already been redeemed")
+		}
+
+		_, err := recordWalletTransaction(tx, transaction)
+		return err
+	})
+}
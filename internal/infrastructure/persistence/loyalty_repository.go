@@ -0,0 +1,382 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loyaltyRepository struct {
+	db *gorm.DB
+}
+
+// NewLoyaltyRepository creates a new LoyaltyRepository instance
+func NewLoyaltyRepository(db *gorm.DB) repository.LoyaltyRepository {
+	return &loyaltyRepository{
+		db: db,
+	}
+}
+
+// GetAccount gets the points account of a user, creating an empty one on first use
+func (r *loyaltyRepository) GetAccount(ctx context.Context, userID uint) (*entity.LoyaltyAccount, error) {
+	account := entity.LoyaltyAccount{UserID: userID}
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).FirstOrCreate(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetTransactions gets the points ledger of a user, newest first
+func (r *loyaltyRepository) GetTransactions(ctx context.Context, userID uint, offset, limit int) ([]*entity.LoyaltyTransaction, int64, error) {
+	var transactions []*entity.LoyaltyTransaction
+	var count int64
+
+	// Orders that earned nothing still get an entry so they are not awarded again; keep those out of the ledger
+	query := r.db.WithContext(ctx).Model(&entity.LoyaltyTransaction{}).Where("user_id = ? AND points <> 0", userID)
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, count, nil
+}
+
+// GetOrderTransactions gets the points entries recorded for an order
+func (r *loyaltyRepository) GetOrderTransactions(ctx context.Context, orderID uint) ([]*entity.LoyaltyTransaction, error) {
+	var transactions []*entity.LoyaltyTransaction
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id").Find(&transactions).Error
+	return transactions, err
+}
+
+// Credit adds points to a user's balance. Earn and restore entries are
+// recorded at most once per order; false is returned when the order already
+// has one. Points that pay off a negative balance cannot be spent again, so
+// they are not left on the new entry.
+func (r *loyaltyRepository) Credit(ctx context.Context, transaction *entity.LoyaltyTransaction) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		account, err := lockLoyaltyAccount(tx, transaction.UserID)
+		if err != nil {
+			return err
+		}
+
+		if transaction.OrderID != nil {
+			exists, err := hasOrderLoyaltyEntry(tx, *transaction.OrderID, transaction.Type)
+			if err != nil || exists {
+				return err
+			}
+		}
+
+		transaction.Remaining = transaction.Points
+		if account.Balance < 0 {
+			transaction.Remaining = max(transaction.Points+account.Balance, 0)
+		}
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"balance": gorm.Expr("balance + ?", transaction.Points)}
+		if transaction.Type == entity.LoyaltyTransactionEarn {
+			updates["lifetime_earned"] = gorm.Expr("lifetime_earned + ?", transaction.Points)
+		}
+		if err := tx.Model(account).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// Debit takes points from a user's balance, using up the entries that expire
+// first. Points holds the negative amount taken. Redeem entries are recorded
+// at most once per order; false is returned when the order already has one.
+// Without allowOverdraft, ErrInsufficientPoints is returned when the balance
+// does not cover the debit.
+func (r *loyaltyRepository) Debit(ctx context.Context, transaction *entity.LoyaltyTransaction, allowOverdraft bool) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = debitPoints(tx, transaction, allowOverdraft)
+		return err
+	})
+	return applied, err
+}
+
+// Redeem spends points on a pending order and records the discount on the
+// order in the same transaction, so the points are never taken without the
+// order showing them
+func (r *loyaltyRepository) Redeem(ctx context.Context, transaction *entity.LoyaltyTransaction, order *entity.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		applied, err := debitPoints(tx, transaction, false)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return errors.New("points already redeemed on this order")
+		}
+
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ? AND loyalty_points_redeemed = 0 AND wallet_amount = 0", order.ID, entity.OrderStatusPending).
+			Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id)").
+			Updates(map[string]interface{}{
+				"loyalty_points_redeemed": order.LoyaltyPointsRedeemed,
+				"discount_amount":         order.DiscountAmount,
+				"final_amount":            order.FinalAmount,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("order was updated concurrently")
+		}
+		return nil
+	})
+}
+
+// Restore gives back the points redeemed on an order to the entries they
+// were taken from, so they keep their original expiry. Entries that expired
+// in the meantime are expired again by the next ExpireDue run. Points that
+// pay off a negative balance are not put back. Orders without a redemption,
+// or whose redemption was already restored, are left as they are.
+func (r *loyaltyRepository) Restore(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var redeem entity.LoyaltyTransaction
+		err := tx.Where("order_id = ? AND type = ?", orderID, entity.LoyaltyTransactionRedeem).First(&redeem).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		account, err := lockLoyaltyAccount(tx, redeem.UserID)
+		if err != nil {
+			return err
+		}
+		exists, err := hasOrderLoyaltyEntry(tx, orderID, entity.LoyaltyTransactionRestore)
+		if err != nil || exists {
+			return err
+		}
+
+		var usages []*entity.LoyaltyLotUsage
+		if err := tx.Where("debit_id = ?", redeem.ID).Order("id").Find(&usages).Error; err != nil {
+			return err
+		}
+		payoff := max(-account.Balance, 0)
+		for _, usage := range usages {
+			points := usage.Points - min(payoff, usage.Points)
+			payoff -= usage.Points - points
+			if points == 0 {
+				continue
+			}
+			err := tx.Model(&entity.LoyaltyTransaction{}).
+				Where("id = ?", usage.LotID).
+				Update("remaining", gorm.Expr("remaining + ?", points)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		restore := &entity.LoyaltyTransaction{
+			UserID:      redeem.UserID,
+			OrderID:     &orderID,
+			Type:        entity.LoyaltyTransactionRestore,
+			Points:      -redeem.Points,
+			Description: "Order cancelled",
+		}
+		if err := tx.Create(restore).Error; err != nil {
+			return err
+		}
+		return tx.Model(account).Update("balance", gorm.Expr("balance + ?", restore.Points)).Error
+	})
+}
+
+// debitPoints takes points from a user's balance within tx, using up the
+// entries that expire first and recording what it took from each
+func debitPoints(tx *gorm.DB, transaction *entity.LoyaltyTransaction, allowOverdraft bool) (bool, error) {
+	account, err := lockLoyaltyAccount(tx, transaction.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	if transaction.OrderID != nil && transaction.Type == entity.LoyaltyTransactionRedeem {
+		exists, err := hasOrderLoyaltyEntry(tx, *transaction.OrderID, transaction.Type)
+		if err != nil || exists {
+			return false, err
+		}
+	}
+
+	points := -transaction.Points
+	if !allowOverdraft && account.Balance < points {
+		return false, repository.ErrInsufficientPoints
+	}
+
+	transaction.Remaining = 0
+	if err := tx.Create(transaction).Error; err != nil {
+		return false, err
+	}
+
+	var lots []*entity.LoyaltyTransaction
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND remaining > 0", transaction.UserID).
+		Order("expires_at ASC NULLS LAST, id ASC").
+		Find(&lots).Error
+	if err != nil {
+		return false, err
+	}
+	for _, lot := range lots {
+		if points == 0 {
+			break
+		}
+		used := min(lot.Remaining, points)
+		if err := tx.Model(lot).Update("remaining", lot.Remaining-used).Error; err != nil {
+			return false, err
+		}
+		usage := &entity.LoyaltyLotUsage{DebitID: transaction.ID, LotID: lot.ID, Points: used}
+		if err := tx.Create(usage).Error; err != nil {
+			return false, err
+		}
+		points -= used
+	}
+
+	if err := tx.Model(account).Update("balance", gorm.Expr("balance + ?", transaction.Points)).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExpireDue expires up to limit entries whose points ran out at the given time
+// and returns how many were expired
+func (r *loyaltyRepository) ExpireDue(ctx context.Context, at time.Time, limit int) (int, error) {
+	var due []*entity.LoyaltyTransaction
+	err := r.db.WithContext(ctx).
+		Where("remaining > 0 AND expires_at <= ?", at).
+		Order("expires_at").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, lot := range due {
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			account, err := lockLoyaltyAccount(tx, lot.UserID)
+			if err != nil {
+				return err
+			}
+
+			// The points may have been spent since the entry was read
+			var current entity.LoyaltyTransaction
+			if err := tx.First(&current, lot.ID).Error; err != nil {
+				return err
+			}
+			if current.Remaining <= 0 {
+				return nil
+			}
+
+			if err := tx.Model(&current).Update("remaining", 0).Error; err != nil {
+				return err
+			}
+			entry := &entity.LoyaltyTransaction{
+				UserID:      current.UserID,
+				OrderID:     current.OrderID,
+				Type:        entity.LoyaltyTransactionExpire,
+				Points:      -current.Remaining,
+				Description: "Points expired",
+			}
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(account).Update("balance", gorm.Expr("balance - ?", current.Remaining)).Error; err != nil {
+				return err
+			}
+
+			expired++
+			return nil
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+
+	return expired, nil
+}
+
+// GetDeliveredOrdersWithoutEarn gets delivered orders that have not been awarded points yet
+func (r *loyaltyRepository) GetDeliveredOrdersWithoutEarn(ctx context.Context, limit int) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	err := r.db.WithContext(ctx).
+		Where("status = ?", entity.OrderStatusDelivered).
+		Where("NOT EXISTS (SELECT 1 FROM loyalty_transactions lt WHERE lt.order_id = orders.id AND lt.type = ?)", entity.LoyaltyTransactionEarn).
+		Order("id").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// ListMultipliers lists the category points multipliers
+func (r *loyaltyRepository) ListMultipliers(ctx context.Context) ([]*entity.LoyaltyCategoryMultiplier, error) {
+	var multipliers []*entity.LoyaltyCategoryMultiplier
+	err := r.db.WithContext(ctx).Order("category_id").Find(&multipliers).Error
+	return multipliers, err
+}
+
+// SetMultiplier creates or replaces the points multiplier of a category
+func (r *loyaltyRepository) SetMultiplier(ctx context.Context, multiplier *entity.LoyaltyCategoryMultiplier) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"multiplier", "updated_at"}),
+		}).
+		Create(multiplier).Error
+}
+
+// DeleteMultiplier removes the points multiplier of a category
+func (r *loyaltyRepository) DeleteMultiplier(ctx context.Context, categoryID uint) error {
+	result := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Delete(&entity.LoyaltyCategoryMultiplier{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("loyalty multiplier not found")
+	}
+	return nil
+}
+
+// lockLoyaltyAccount locks the points account of a user for the rest of the
+// transaction, creating it first if needed
+func lockLoyaltyAccount(tx *gorm.DB, userID uint) (*entity.LoyaltyAccount, error) {
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.LoyaltyAccount{UserID: userID}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var account entity.LoyaltyAccount
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// hasOrderLoyaltyEntry reports whether an order already has a points entry of the given type
+func hasOrderLoyaltyEntry(tx *gorm.DB, orderID uint, transactionType entity.LoyaltyTransactionType) (bool, error) {
+	var count int64
+	err := tx.Model(&entity.LoyaltyTransaction{}).
+		Where("order_id = ? AND type = ?", orderID, transactionType).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository instance
+func NewNotificationRepository(db *gorm.DB) repository.NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+// Create creates a new notification
+func (r *notificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	return r.db.WithContext(ctx).Omit("User").Create(notification).Error
+}
+
+// GetByID gets a notification by ID
+func (r *notificationRepository) GetByID(ctx context.Context, id uint) (*entity.Notification, error) {
+	var notification entity.Notification
+	if err := r.db.WithContext(ctx).First(&notification, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification not found")
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// GetByUserID gets the notifications of a user with pagination, newest first
+func (r *notificationRepository) GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.Notification, int64, error) {
+	var notifications []*entity.Notification
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, count, nil
+}
+
+// GetByUserIDByCursor gets the notifications of a user, one page after or before a cursor
+func (r *notificationRepository) GetByUserIDByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Notification, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ?", userID)
+	return findByCursor(query, "notifications", page, func(notification *entity.Notification) entity.Cursor {
+		return entity.Cursor{CreatedAt: notification.CreatedAt, ID: notification.ID}
+	})
+}
+
+// GetUnreadByUserID gets the unread notifications of a user, newest first
+func (r *notificationRepository) GetUnreadByUserID(ctx context.Context, userID uint) ([]*entity.Notification, error) {
+	var notifications []*entity.Notification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Order("created_at DESC, id DESC").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkAsRead marks a notification as read
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&entity.Notification{}).Where("id = ?", id).Update("is_read", true).Error
+}
+
+// MarkAllAsRead marks every notification of a user as read
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Update("is_read", true).Error
+}
+
+// Delete deletes a notification
+func (r *notificationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Notification{}, id).Error
+}
@@ -0,0 +1,349 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new OrderRepository instance
+func NewOrderRepository(db *gorm.DB) repository.OrderRepository {
+	return &orderRepository{
+		db: db,
+	}
+}
+
+// Create creates an order together with its items and applied promotions
+func (r *orderRepository) Create(ctx context.Context, order *entity.Order) error {
+	return r.db.WithContext(ctx).Omit("User", "Payment", "StatusHistory").Create(order).Error
+}
+
+// Place creates an order from a cart in one transaction: it takes the stock
+// of every item, creates the order with its items and promotions, records
+// the creation in the status history, counts the use of the cart's voucher,
+// records the flash sale purchases, spends the store credit, pays the order
+// when the credit covers it and empties the cart. Nothing is kept when an
+// item is out of stock, the voucher has been used up, a flash sale has sold
+// out or the wallet does not hold enough credit.
+func (r *orderRepository) Place(ctx context.Context, order *entity.Order, placement *repository.OrderPlacement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range order.OrderItems {
+			result := tx.Model(&entity.ProductVariant{}).
+				Where("id = ? AND is_active = ? AND stock >= ?", item.VariantID, true, item.Quantity).
+				Update("stock", gorm.Expr("stock - ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("insufficient stock for %s", item.ProductName)
+			}
+		}
+
+		if err := tx.Omit("User", "Payment", "StatusHistory").Create(order).Error; err != nil {
+			return err
+		}
+
+		creation := placement.Creation
+		creation.OrderID = order.ID
+		creation.ToStatus = order.Status
+		if err := tx.Create(creation).Error; err != nil {
+			return err
+		}
+
+		if redemption := placement.Voucher; redemption != nil {
+			redemption.OrderID = order.ID
+			if err := redeemVoucher(tx, redemption); err != nil {
+				return err
+			}
+		}
+		for _, purchase := range placement.FlashSales {
+			purchase.OrderID = order.ID
+			if err := recordFlashSalePurchase(tx, purchase); err != nil {
+				return err
+			}
+		}
+
+		if debit := placement.Wallet; debit != nil {
+			// The same reference as store credit applied after checkout, so it is spent once
+			debit.OrderID = &order.ID
+			debit.Reference = fmt.Sprintf("order-%d-payment", order.ID)
+			if _, err := recordWalletTransaction(tx, debit); err != nil {
+				return err
+			}
+		}
+		if placement.Payment != nil {
+			if err := payInFull(tx, order, placement.Payment); err != nil {
+				return err
+			}
+		}
+
+		err := tx.Model(&entity.Cart{}).Where("id = ?", placement.CartID).Update("voucher_code", "").Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("cart_id = ?", placement.CartID).Delete(&entity.CartItem{}).Error
+	})
+}
+
+// GetByID gets an order by ID with its items, promotions and payment
+func (r *orderRepository) GetByID(ctx context.Context, id uint) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.withDetails(r.db.WithContext(ctx)).First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByOrderNumber gets an order by order number with its items, promotions and payment
+func (r *orderRepository) GetByOrderNumber(ctx context.Context, orderNumber string) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.withDetails(r.db.WithContext(ctx)).Where("order_number = ?", orderNumber).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByTrackingNumber gets the order shipped with a courier tracking number
+func (r *orderRepository) GetByTrackingNumber(ctx context.Context, trackingNumber string) (*entity.Order, error) {
+	var order entity.Order
+	err := r.withDetails(r.db.WithContext(ctx)).
+		Where("shipping_tracking_number = ?", trackingNumber).
+		Order("id DESC").
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetOrderNumbers gets the order numbers of orders by ID
+func (r *orderRepository) GetOrderNumbers(ctx context.Context, ids []uint) (map[uint]string, error) {
+	numbers := make(map[uint]string, len(ids))
+	for start := 0; start < len(ids); start += lookupBatchSize {
+		var rows []struct {
+			ID          uint
+			OrderNumber string
+		}
+		end := min(start+lookupBatchSize, len(ids))
+		err := r.db.WithContext(ctx).
+			Model(&entity.Order{}).
+			Select("id, order_number").
+			Where("id IN ?", ids[start:end]).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			numbers[row.ID] = row.OrderNumber
+		}
+	}
+	return numbers, nil
+}
+
+// GetByUserID gets orders by user ID with pagination, newest first
+func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.Order, int64, error) {
+	var orders []*entity.Order
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Order{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("OrderItems").Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return orders, count, nil
+}
+
+// GetByUserIDByCursor gets orders by user ID, one page after or before a cursor
+func (r *orderRepository) GetByUserIDByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Order{}).Preload("OrderItems").Where("user_id = ?", userID)
+	return findByCursor(query, "orders", page, func(order *entity.Order) entity.Cursor {
+		return entity.Cursor{CreatedAt: order.CreatedAt, ID: order.ID}
+	})
+}
+
+// GetUnstarted gets pending orders placed before placedBefore whose payment
+// was never started, oldest first. Replacement orders of exchanges are left
+// to the exchange that created them.
+func (r *orderRepository) GetUnstarted(ctx context.Context, placedBefore time.Time, limit int) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at < ? AND original_order_id IS NULL", entity.OrderStatusPending, placedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id)").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// GetUnreleased gets cancelled orders whose holds were not given back yet,
+// such as when releasing them failed after the order was cancelled, oldest first
+func (r *orderRepository) GetUnreleased(ctx context.Context, limit int) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND holds_released_at IS NULL", entity.OrderStatusCancelled).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// MarkHoldsReleased records that a cancelled order gave back what it held
+func (r *orderRepository) MarkHoldsReleased(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("id = ?", id).
+		UpdateColumn("holds_released_at", time.Now()).Error
+}
+
+// Update updates the fields of an order, leaving its items and promotions as
+// they are. The status is left alone too: it only changes through
+// OrderStatusHistoryRepository.Transition, which records every change.
+func (r *orderRepository) Update(ctx context.Context, order *entity.Order) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations, "status", "delivered_at", "holds_released_at").Save(order).Error
+}
+
+// SetPayment points an order at its current payment, leaving the rest of
+// the order as it is
+func (r *orderRepository) SetPayment(ctx context.Context, orderID, paymentID uint) error {
+	return r.db.WithContext(ctx).Model(&entity.Order{}).
+		Where("id = ?", orderID).
+		UpdateColumn("payment_id", paymentID).Error
+}
+
+// Delete deletes an order
+func (r *orderRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Order{}, id).Error
+}
+
+// List lists orders with filter and pagination
+func (r *orderRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Order, int64, error) {
+	var orders []*entity.Order
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Order{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("OrderItems").Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return orders, count, nil
+}
+
+// ListByCursor lists orders with filter, one page after or before a cursor
+func (r *orderRepository) ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Order, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Order{}).Preload("OrderItems")
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return findByCursor(query, "orders", page, func(order *entity.Order) entity.Cursor {
+		return entity.Cursor{CreatedAt: order.CreatedAt, ID: order.ID}
+	})
+}
+
+// GetSalesReport gets the orders placed within a period that were not
+// cancelled or refunded, and the sum of their final amounts
+func (r *orderRepository) GetSalesReport(ctx context.Context, startDate, endDate time.Time) ([]*entity.Order, float64, error) {
+	var orders []*entity.Order
+	err := r.db.WithContext(ctx).
+		Preload("OrderItems").
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
+		Where("status NOT IN ?", []entity.OrderStatus{entity.OrderStatusCancelled, entity.OrderStatusRefunded}).
+		Order("created_at ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total float64
+	for _, order := range orders {
+		total += order.FinalAmount
+	}
+	return orders, total, nil
+}
+
+// withDetails preloads what an order page shows
+func (r *orderRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("OrderItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Promotions").
+		Preload("Payment")
+}
+
+type orderItemRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderItemRepository creates a new OrderItemRepository instance
+func NewOrderItemRepository(db *gorm.DB) repository.OrderItemRepository {
+	return &orderItemRepository{
+		db: db,
+	}
+}
+
+// Create creates a new order item
+func (r *orderItemRepository) Create(ctx context.Context, item *entity.OrderItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// GetByID gets an order item by ID
+func (r *orderItemRepository) GetByID(ctx context.Context, id uint) (*entity.OrderItem, error) {
+	var item entity.OrderItem
+	if err := r.db.WithContext(ctx).First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order item not found")
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetByOrderID gets the items of an order
+func (r *orderItemRepository) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.OrderItem, error) {
+	var items []*entity.OrderItem
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update updates an order item
+func (r *orderItemRepository) Update(ctx context.Context, item *entity.OrderItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+// Delete deletes an order item
+func (r *orderItemRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.OrderItem{}, id).Error
+}
@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type orderStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderStatusHistoryRepository creates a new OrderStatusHistoryRepository instance
+func NewOrderStatusHistoryRepository(db *gorm.DB) repository.OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{
+		db: db,
+	}
+}
+
+// Transition moves an order from one status to another and records the change
+// in the same transaction. It returns false, recording nothing, when the order
+// is no longer in the status the change starts from.
+func (r *orderStatusHistoryRepository) Transition(ctx context.Context, change *entity.OrderStatusHistory) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = applyOrderStatusChange(tx, change)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// Record records a status change without updating the order
+func (r *orderStatusHistoryRepository) Record(ctx context.Context, change *entity.OrderStatusHistory) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+// GetByOrderID gets the status changes of an order, oldest first
+func (r *orderStatusHistoryRepository) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.OrderStatusHistory, error) {
+	var history []*entity.OrderStatusHistory
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// applyOrderStatusChange moves an order from one status to another and records
+// the change with tx. Delivered orders also get their delivery time, which the
+// return window is measured from, and cancelled orders put their items back
+// in stock, so the stock is returned exactly once. It returns false, recording
+// nothing, when the order is no longer in the status the change starts from.
+func applyOrderStatusChange(tx *gorm.DB, change *entity.OrderStatusHistory) (bool, error) {
+	updates := map[string]interface{}{"status": change.ToStatus}
+	if change.ToStatus == entity.OrderStatusDelivered {
+		updates["delivered_at"] = time.Now()
+	}
+
+	result := tx.Model(&entity.Order{}).
+		Where("id = ? AND status = ?", change.OrderID, change.FromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+	if change.ToStatus == entity.OrderStatusCancelled {
+		if err := restockOrder(tx, change.OrderID); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Create(change).Error
+}
+
+// restockOrder puts the items of an order back in stock with tx
+func restockOrder(tx *gorm.DB, orderID uint) error {
+	var items []*entity.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		err := tx.Model(&entity.ProductVariant{}).
+			Where("id = ?", item.VariantID).
+			Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error
+		if err != nil {
+			return fmt.Errorf("release stock of variant %d: %w", item.VariantID, err)
+		}
+	}
+	return nil
+}
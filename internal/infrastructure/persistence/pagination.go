@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"slices"
+
+	"fashion-shop/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+// findByCursor loads one page of a query in (created_at, id) order, newest
+// first, relative to the cursor of the page. It reads one extra row instead
+// of counting to tell whether the list goes on.
+func findByCursor[T any](query *gorm.DB, table string, page *entity.CursorPage, key func(*T) entity.Cursor) ([]*T, *entity.PageCursors, error) {
+	createdAt, id := table+".created_at", table+".id"
+	backward := page.Before != nil
+
+	switch {
+	case backward:
+		query = query.
+			Where("("+createdAt+", "+id+") > (?, ?)", page.Before.CreatedAt, page.Before.ID).
+			Order(createdAt + " ASC, " + id + " ASC")
+	case page.After != nil:
+		query = query.
+			Where("("+createdAt+", "+id+") < (?, ?)", page.After.CreatedAt, page.After.ID).
+			Order(createdAt + " DESC, " + id + " DESC")
+	default:
+		query = query.Order(createdAt + " DESC, " + id + " DESC")
+	}
+
+	var items []*T
+	if err := query.Limit(page.Limit + 1).Find(&items).Error; err != nil {
+		return nil, nil, err
+	}
+
+	more := len(items) > page.Limit
+	if more {
+		items = items[:page.Limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+
+	cursors := &entity.PageCursors{}
+	if len(items) == 0 {
+		return items, cursors, nil
+	}
+	first, last := key(items[0]), key(items[len(items)-1])
+	if backward {
+		// The page before a cursor always has the cursor's item after it
+		cursors.Next = &last
+		if more {
+			cursors.Prev = &first
+		}
+	} else {
+		if more {
+			cursors.Next = &last
+		}
+		if page.After != nil {
+			cursors.Prev = &first
+		}
+	}
+	return items, cursors, nil
+}
@@ -0,0 +1,413 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lookupBatchSize caps how many values one IN lookup sends to the database
+const lookupBatchSize = 1000
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new PaymentRepository instance
+func NewPaymentRepository(db *gorm.DB) repository.PaymentRepository {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+// Create creates a new payment
+func (r *paymentRepository) Create(ctx context.Context, payment *entity.Payment) error {
+	return r.db.WithContext(ctx).Create(payment).Error
+}
+
+// GetByID gets a payment by ID
+func (r *paymentRepository) GetByID(ctx context.Context, id uint) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.WithContext(ctx).Preload("Refunds").First(&payment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetByOrderID gets a payment by order ID
+func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID uint) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.WithContext(ctx).Preload("Refunds").Where("order_id = ?", orderID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetByTransactionID gets a payment by gateway transaction ID
+func (r *paymentRepository) GetByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetByGatewayOrderID gets a payment by the order_id of its current attempt at the gateway
+func (r *paymentRepository) GetByGatewayOrderID(ctx context.Context, gatewayOrderID string) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := r.db.WithContext(ctx).Preload("Refunds").Where("gateway_order_id = ?", gatewayOrderID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetByTransactionIDs gets the payments with any of the gateway transaction IDs
+func (r *paymentRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []string) ([]*entity.Payment, error) {
+	return r.getByColumnIn(ctx, "transaction_id", transactionIDs)
+}
+
+// GetByGatewayOrderIDs gets the payments whose current attempt has any of the gateway order_ids
+func (r *paymentRepository) GetByGatewayOrderIDs(ctx context.Context, gatewayOrderIDs []string) ([]*entity.Payment, error) {
+	return r.getByColumnIn(ctx, "gateway_order_id", gatewayOrderIDs)
+}
+
+// getByColumnIn gets the payments with a column matching any of the values,
+// querying in batches to keep the statements small
+func (r *paymentRepository) getByColumnIn(ctx context.Context, column string, values []string) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	for start := 0; start < len(values); start += lookupBatchSize {
+		var batch []*entity.Payment
+		end := min(start+lookupBatchSize, len(values))
+		if err := r.db.WithContext(ctx).Where(column+" IN ?", values[start:end]).Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		payments = append(payments, batch...)
+	}
+	return payments, nil
+}
+
+// Update updates a payment
+func (r *paymentRepository) Update(ctx context.Context, payment *entity.Payment) error {
+	return r.db.WithContext(ctx).Save(payment).Error
+}
+
+// UpdateStatus updates a payment's status
+func (r *paymentRepository) UpdateStatus(ctx context.Context, id uint, status entity.PaymentStatus) error {
+	return r.db.WithContext(ctx).Model(&entity.Payment{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// UpdateIfStatus saves a payment only while its stored status is still one of
+// the expected statuses, so concurrent or repeated updates apply at most once
+func (r *paymentRepository) UpdateIfStatus(ctx context.Context, payment *entity.Payment, expected ...entity.PaymentStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id = ? AND status IN ?", payment.ID, expected).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(payment)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Expire marks a pending payment expired. With cancel it also moves the order
+// to cancelled, records the change and returns the stock of the order's items,
+// all in one transaction. It returns false, changing nothing, when the payment
+// is no longer pending. An order no longer in cancel's from status is left as
+// is; cancel only gets an ID when it was recorded.
+func (r *paymentRepository) Expire(ctx context.Context, payment *entity.Payment, cancel *entity.OrderStatusHistory) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Payment{}).
+			Where("id = ? AND status = ?", payment.ID, entity.PaymentStatusPending).
+			Update("status", entity.PaymentStatusExpired)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		applied = true
+
+		if cancel == nil {
+			return nil
+		}
+		_, err := applyOrderStatusChange(tx, cancel)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if applied {
+		payment.Status = entity.PaymentStatusExpired
+	}
+	return applied, nil
+}
+
+// List lists payments with filter and pagination
+func (r *paymentRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Payment, int64, error) {
+	var payments []*entity.Payment
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Payment{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&payments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return payments, count, nil
+}
+
+// ListByCursor lists payments with filter, one page after or before a cursor
+func (r *paymentRepository) ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Payment, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Payment{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return findByCursor(query, "payments", page, func(payment *entity.Payment) entity.Cursor {
+		return entity.Cursor{CreatedAt: payment.CreatedAt, ID: payment.ID}
+	})
+}
+
+// GetExpiredPending gets pending payments whose expiry time is before the given time, oldest first
+func (r *paymentRepository) GetExpiredPending(ctx context.Context, before time.Time, limit int) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expired_at IS NOT NULL AND expired_at < ?", entity.PaymentStatusPending, before).
+		Order("expired_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// GetPaidBetween gets payments paid within a period, oldest first
+func (r *paymentRepository) GetPaidBetween(ctx context.Context, provider string, start, end time.Time) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	query := r.db.WithContext(ctx).Where("paid_at >= ? AND paid_at < ?", start, end)
+	if provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if err := query.Order("paid_at ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// GetCreatedBetween gets payments created within a period, oldest first
+func (r *paymentRepository) GetCreatedBetween(ctx context.Context, provider string, start, end time.Time) ([]*entity.Payment, error) {
+	var payments []*entity.Payment
+	query := r.db.WithContext(ctx).Where("created_at >= ? AND created_at < ?", start, end)
+	if provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if err := query.Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+type paymentRefundRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRefundRepository creates a new PaymentRefundRepository instance
+func NewPaymentRefundRepository(db *gorm.DB) repository.PaymentRefundRepository {
+	return &paymentRefundRepository{
+		db: db,
+	}
+}
+
+// Create creates a new payment refund
+func (r *paymentRefundRepository) Create(ctx context.Context, refund *entity.PaymentRefund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+// Reserve records refunds of a payment as pending. The payment row is locked
+// so concurrent refunds cannot together exceed what is left to refund: refunds
+// already made and those still pending count against the paid amount. The
+// lock also serialises the numbering of the refund keys, which the gateway
+// uses to tell refunds apart.
+func (r *paymentRefundRepository) Reserve(ctx context.Context, paymentID uint, keyPrefix string, refunds []*entity.PaymentRefund) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, paymentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("payment not found")
+			}
+			return err
+		}
+		if payment.Status != entity.PaymentStatusPaid && payment.Status != entity.PaymentStatusPartiallyRefunded {
+			return errors.New("only paid payments can be refunded")
+		}
+
+		var pending float64
+		err := tx.Model(&entity.PaymentRefund{}).
+			Where("payment_id = ? AND status = ?", paymentID, entity.PaymentRefundStatusPending).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		requested := 0.0
+		for _, refund := range refunds {
+			requested += refund.Amount
+		}
+		if requested > payment.PaidAmount()-payment.RefundedTotal()-pending+0.01 {
+			return repository.ErrRefundExceedsPayment
+		}
+
+		var count int64
+		if err := tx.Model(&entity.PaymentRefund{}).Where("payment_id = ?", paymentID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		for i, refund := range refunds {
+			refund.PaymentID = paymentID
+			refund.RefundKey = fmt.Sprintf("%s-refund-%d", keyPrefix, count+int64(i)+1)
+			refund.Status = entity.PaymentRefundStatusPending
+			if err := tx.Create(refund).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Complete marks pending refunds of a payment as succeeded, adds them to the
+// payment's refunded totals and status and pays the store credits into the
+// customer's wallet, all in one transaction. Refunds that are no longer
+// pending are skipped so a repeated call changes nothing.
+func (r *paymentRefundRepository) Complete(ctx context.Context, refunds []*entity.PaymentRefund, credits []*entity.WalletTransaction) (*entity.Payment, error) {
+	if len(refunds) == 0 {
+		return nil, errors.New("no refunds to complete")
+	}
+
+	var payment entity.Payment
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, refunds[0].PaymentID).Error; err != nil {
+			return err
+		}
+
+		for _, refund := range refunds {
+			result := tx.Model(&entity.PaymentRefund{}).
+				Where("id = ? AND payment_id = ? AND status = ?", refund.ID, payment.ID, entity.PaymentRefundStatusPending).
+				Update("status", entity.PaymentRefundStatusSucceeded)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != 1 {
+				continue
+			}
+			refund.Status = entity.PaymentRefundStatusSucceeded
+			if refund.Source == "wallet" {
+				payment.WalletRefunded += refund.Amount
+			} else {
+				payment.RefundedAmount += refund.Amount
+			}
+		}
+		payment.Status = payment.RefundStatus()
+
+		err := tx.Model(&payment).Updates(map[string]interface{}{
+			"refunded_amount": payment.RefundedAmount,
+			"wallet_refunded": payment.WalletRefunded,
+			"status":          payment.Status,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, credit := range credits {
+			if _, err := recordWalletTransaction(tx, credit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Fail marks pending refunds as failed
+func (r *paymentRefundRepository) Fail(ctx context.Context, refunds []*entity.PaymentRefund) error {
+	ids := make([]uint, 0, len(refunds))
+	for _, refund := range refunds {
+		ids = append(ids, refund.ID)
+		refund.Status = entity.PaymentRefundStatusFailed
+	}
+	return r.db.WithContext(ctx).
+		Model(&entity.PaymentRefund{}).
+		Where("id IN ? AND status = ?", ids, entity.PaymentRefundStatusPending).
+		Update("status", entity.PaymentRefundStatusFailed).Error
+}
+
+// GetByPaymentID gets all refunds of a payment, oldest first
+func (r *paymentRefundRepository) GetByPaymentID(ctx context.Context, paymentID uint) ([]*entity.PaymentRefund, error) {
+	var refunds []*entity.PaymentRefund
+	if err := r.db.WithContext(ctx).Where("payment_id = ?", paymentID).Order("created_at ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+type paymentEventRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentEventRepository creates a new PaymentEventRepository instance
+func NewPaymentEventRepository(db *gorm.DB) repository.PaymentEventRepository {
+	return &paymentEventRepository{
+		db: db,
+	}
+}
+
+// Create stores a payment notification
+func (r *paymentEventRepository) Create(ctx context.Context, event *entity.PaymentEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// Update updates a payment notification
+func (r *paymentEventRepository) Update(ctx context.Context, event *entity.PaymentEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
+
+// GetByPaymentID gets all notifications received for a payment, oldest first
+func (r *paymentEventRepository) GetByPaymentID(ctx context.Context, paymentID uint) ([]*entity.PaymentEvent, error) {
+	var events []*entity.PaymentEvent
+	if err := r.db.WithContext(ctx).Where("payment_id = ?", paymentID).Order("created_at ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
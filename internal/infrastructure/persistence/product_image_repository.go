@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type productImageRepository struct {
+	db *gorm.DB
+}
+
+// NewProductImageRepository creates a new ProductImageRepository instance
+func NewProductImageRepository(db *gorm.DB) repository.ProductImageRepository {
+	return &productImageRepository{
+		db: db,
+	}
+}
+
+// Create creates a new product image. A primary image takes over from the
+// product's previous primary image.
+func (r *productImageRepository) Create(ctx context.Context, image *entity.ProductImage) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if image.IsPrimary {
+			err := tx.Model(&entity.ProductImage{}).
+				Where("product_id = ? AND is_primary = ?", image.ProductID, true).
+				Update("is_primary", false).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Create(image).Error
+	})
+	if isUniqueViolation(err) {
+		return repository.ErrProductImageExists
+	}
+	return err
+}
+
+// GetByID gets a product image by ID
+func (r *productImageRepository) GetByID(ctx context.Context, id uint) (*entity.ProductImage, error) {
+	var image entity.ProductImage
+	if err := r.db.WithContext(ctx).First(&image, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product image not found")
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// GetByProductID gets the images of a product, the primary image first
+func (r *productImageRepository) GetByProductID(ctx context.Context, productID uint) ([]*entity.ProductImage, error) {
+	var images []*entity.ProductImage
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("is_primary DESC, id ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// Update updates a product image
+func (r *productImageRepository) Update(ctx context.Context, image *entity.ProductImage) error {
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// Delete deletes a product image
+func (r *productImageRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.ProductImage{}, id).Error
+}
+
+// SetPrimary makes an image the primary image of its product
+func (r *productImageRepository) SetPrimary(ctx context.Context, id uint, productID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.ProductImage{}).
+			Where("product_id = ? AND is_primary = ?", productID, true).
+			Update("is_primary", false).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.ProductImage{}).
+			Where("id = ? AND product_id = ?", id, productID).
+			Update("is_primary", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("product image not found")
+		}
+		return nil
+	})
+}
+
+// FindByContentHash gets the oldest image of any product uploaded with the
+// same content, or nil when there is none
+func (r *productImageRepository) FindByContentHash(ctx context.Context, hash string) (*entity.ProductImage, error) {
+	var image entity.ProductImage
+	err := r.db.WithContext(ctx).Where("content_hash = ?", hash).Order("id ASC").First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &image, nil
+}
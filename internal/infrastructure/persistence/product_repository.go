@@ -0,0 +1,191 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bestSellerSQL is the number of units of a product sold in orders that were not cancelled or refunded
+const bestSellerSQL = `(SELECT COALESCE(SUM(order_items.quantity), 0) FROM order_items
+	JOIN orders ON orders.id = order_items.order_id
+	WHERE order_items.product_id = products.id AND orders.status NOT IN ('cancelled', 'refunded'))`
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRepository creates a new ProductRepository instance
+func NewProductRepository(db *gorm.DB) repository.ProductRepository {
+	return &productRepository{
+		db: db,
+	}
+}
+
+// Create creates a new product with its variants and tags
+func (r *productRepository) Create(ctx context.Context, product *entity.Product) error {
+	return r.db.WithContext(ctx).Omit("Images", "Options").Create(product).Error
+}
+
+// GetByID gets a product by ID with its images, options, variants and tags
+func (r *productRepository) GetByID(ctx context.Context, id uint) (*entity.Product, error) {
+	var product entity.Product
+	if err := r.withDetails(r.db.WithContext(ctx)).First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetBySlug gets a product by slug with its images, options, variants and tags
+func (r *productRepository) GetBySlug(ctx context.Context, slug string) (*entity.Product, error) {
+	var product entity.Product
+	if err := r.withDetails(r.db.WithContext(ctx)).Where("slug = ?", slug).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// Update updates the fields of a product and replaces its tags. Images,
+// options and variants are managed through their own repositories.
+func (r *productRepository) Update(ctx context.Context, product *entity.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(product).Error; err != nil {
+			return err
+		}
+		return tx.Model(product).Association("Tags").Replace(product.Tags)
+	})
+}
+
+// Delete deletes a product
+func (r *productRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Product{}, id).Error
+}
+
+// List lists products with filter, sort and pagination
+func (r *productRepository) List(ctx context.Context, filter map[string]interface{}, sort string, offset, limit int) ([]*entity.Product, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Product{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return r.findPage(query, sort, offset, limit)
+}
+
+// Search searches products whose name or description contains a keyword
+func (r *productRepository) Search(ctx context.Context, keyword string, filter map[string]interface{}, sort string, offset, limit int) ([]*entity.Product, int64, error) {
+	pattern := "%" + escapeLike(keyword) + "%"
+	query := r.db.WithContext(ctx).Model(&entity.Product{}).
+		Where("products.name ILIKE ? OR products.description ILIKE ?", pattern, pattern)
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return r.findPage(query, sort, offset, limit)
+}
+
+// UpdateStock sets the stock of a product variant
+func (r *productRepository) UpdateStock(ctx context.Context, variantID uint, quantity int) error {
+	result := r.db.WithContext(ctx).Model(&entity.ProductVariant{}).Where("id = ?", variantID).Update("stock", quantity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("product variant not found")
+	}
+	return nil
+}
+
+// GetBestSellers gets the active products that sold the most units
+func (r *productRepository) GetBestSellers(ctx context.Context, limit int) ([]*entity.Product, error) {
+	return r.findFeatured(ctx, bestSellerSQL+" DESC, products.id DESC", limit)
+}
+
+// GetNewArrivals gets the most recently added active products
+func (r *productRepository) GetNewArrivals(ctx context.Context, limit int) ([]*entity.Product, error) {
+	return r.findFeatured(ctx, "products.created_at DESC, products.id DESC", limit)
+}
+
+// GetTopRated gets the active products with the best average review rating
+func (r *productRepository) GetTopRated(ctx context.Context, limit int) ([]*entity.Product, error) {
+	return r.findFeatured(ctx, "COALESCE("+averageRatingSQL+", 0) DESC, products.id DESC", limit)
+}
+
+// findFeatured gets active products in the given order with their listing details
+func (r *productRepository) findFeatured(ctx context.Context, order string, limit int) ([]*entity.Product, error) {
+	var products []*entity.Product
+	err := r.withListing(r.db.WithContext(ctx)).
+		Where("products.is_active = ?", true).
+		Order(order).
+		Limit(limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// findPage counts a product query and loads one page of it in the requested order
+func (r *productRepository) findPage(query *gorm.DB, sort string, offset, limit int) ([]*entity.Product, int64, error) {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []*entity.Product
+	err := r.withListing(query).
+		Order(productListOrder(sort)).
+		Offset(offset).
+		Limit(limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, count, nil
+}
+
+// productListOrder returns the ORDER BY clause of a product sort option, newest first by default
+func productListOrder(sort string) string {
+	switch sort {
+	case searchSortPriceAsc:
+		return "products.price ASC, products.id ASC"
+	case searchSortPriceDesc:
+		return "products.price DESC, products.id DESC"
+	case searchSortName:
+		return "products.name ASC, products.id ASC"
+	default:
+		return "products.created_at DESC, products.id DESC"
+	}
+}
+
+// withListing preloads what product listings show: images and variants
+func (r *productRepository) withListing(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_primary DESC, id ASC")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		})
+}
+
+// withDetails preloads everything a product page shows
+func (r *productRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return r.withListing(db).
+		Preload("Variants.OptionValues.Option").
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).
+		Preload("Options.Values", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).
+		Preload("Tags")
+}
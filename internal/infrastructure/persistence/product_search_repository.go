@@ -0,0 +1,539 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Product search sort options
+const (
+	searchSortRelevance = "relevance"
+	searchSortNewest    = "newest"
+	searchSortPriceAsc  = "price_asc"
+	searchSortPriceDesc = "price_desc"
+	searchSortName      = "name"
+)
+
+// Product filter dimensions, used to leave a filter out when counting its own facet
+const (
+	facetNone     = ""
+	facetCategory = "category"
+	facetPrice    = "price"
+	facetSize     = "size"
+	facetColor    = "color"
+	facetTag      = "tag"
+	facetStock    = "stock"
+	facetRating   = "rating"
+
+	facetAttributePrefix = "attribute:" // followed by the attribute code
+)
+
+// searchHeadlineOptions marks the matched terms in search result snippets
+// with the sentinels entity.SearchSnippet turns into <mark> tags once the
+// text is escaped
+var searchHeadlineOptions = "StartSel=" + entity.SearchMatchStart + ", StopSel=" + entity.SearchMatchStop + ", MinWords=10, MaxWords=30, MaxFragments=2"
+
+// effectivePriceSQL is the lowest price a customer pays for a product now:
+// its discount price or the cheapest live flash sale with quota left
+const effectivePriceSQL = `LEAST(
+	CASE WHEN products.discount_price > 0 AND products.discount_price < products.price THEN products.discount_price ELSE products.price END,
+	(SELECT MIN(fsi.sale_price) FROM flash_sale_items fsi
+		JOIN flash_sales fs ON fs.id = fsi.flash_sale_id
+		WHERE fsi.product_id = products.id AND fsi.sold < fsi.quota
+			AND fs.status = 'active' AND fs.starts_at <= NOW() AND fs.ends_at > NOW()))`
+
+// averageRatingSQL is the average review rating of a product
+const averageRatingSQL = "(SELECT AVG(reviews.rating) FROM reviews WHERE reviews.product_id = products.id)"
+
+// ratingFacetValues are the "N stars & up" buckets of the rating facet
+var ratingFacetValues = []int{4, 3, 2, 1}
+
+type productSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewProductSearchRepository creates a new ProductSearchRepository instance
+func NewProductSearchRepository(db *gorm.DB) repository.ProductSearchRepository {
+	return &productSearchRepository{
+		db: db,
+	}
+}
+
+// searchTsQuery builds the full-text query of a keyword, matching both stemmed
+// Indonesian words and the words as typed
+func searchTsQuery(keyword string) clause.Expr {
+	return clause.Expr{
+		SQL:  "(websearch_to_tsquery('indonesian', ?) || websearch_to_tsquery('simple', ?))",
+		Vars: []interface{}{keyword, keyword},
+	}
+}
+
+// searchMatch matches products by keyword. Products whose name is close to
+// the keyword also match so typos still find them.
+func searchMatch(keyword string) clause.Expr {
+	return clause.Expr{
+		SQL:  "(products.search_vector @@ ? OR ? <% lower(products.name))",
+		Vars: []interface{}{searchTsQuery(keyword), strings.ToLower(keyword)},
+	}
+}
+
+// searchRank scores how well products match a keyword
+func searchRank(keyword string) clause.Expr {
+	if keyword == "" {
+		return clause.Expr{SQL: "0"}
+	}
+	return clause.Expr{
+		SQL:  "ts_rank_cd(products.search_vector, ?, 32) + word_similarity(?, lower(products.name)) * 0.5",
+		Vars: []interface{}{searchTsQuery(keyword), strings.ToLower(keyword)},
+	}
+}
+
+// Search searches active products matching a keyword, ranked by relevance
+func (r *productSearchRepository) Search(ctx context.Context, keyword string, filter map[string]interface{}, sort string, offset, limit int) ([]*entity.Product, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Product{}).
+		Where("products.is_active = ?", true).
+		Where(searchMatch(keyword))
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	return r.findPage(ctx, query, keyword, sort, offset, limit)
+}
+
+// Filter lists active products matching the storefront filters. Listings with
+// a keyword are ranked by relevance, others show the newest products first.
+func (r *productSearchRepository) Filter(ctx context.Context, filter *entity.ProductFilter, sort string, offset, limit int) ([]*entity.Product, int64, error) {
+	if sort == "" && filter.Keyword == "" {
+		sort = searchSortNewest
+	}
+	return r.findPage(ctx, r.filtered(ctx, filter, facetNone), filter.Keyword, sort, offset, limit)
+}
+
+// FilterByCursor lists active products matching the storefront filters,
+// newest first, one page after or before a cursor
+func (r *productSearchRepository) FilterByCursor(ctx context.Context, filter *entity.ProductFilter, page *entity.CursorPage) ([]*entity.Product, *entity.PageCursors, error) {
+	query := r.filtered(ctx, filter, facetNone).
+		Preload("Images").
+		Preload("Variants").
+		Preload("Tags")
+	return findByCursor(query, "products", page, func(product *entity.Product) entity.Cursor {
+		return entity.Cursor{CreatedAt: product.CreatedAt, ID: product.ID}
+	})
+}
+
+// findPage counts the products of a query and loads one sorted page of them
+func (r *productSearchRepository) findPage(ctx context.Context, query *gorm.DB, keyword, sort string, offset, limit int) ([]*entity.Product, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*entity.Product{}, 0, nil
+	}
+
+	var ranked []struct {
+		ID uint
+	}
+	err := query.
+		Select("products.id, ? AS rank, "+effectivePriceSQL+" AS effective_price", searchRank(keyword)).
+		Order(searchOrder(sort)).
+		Offset(offset).
+		Limit(limit).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, len(ranked))
+	for i, row := range ranked {
+		ids[i] = row.ID
+	}
+	if len(ids) == 0 {
+		return []*entity.Product{}, total, nil
+	}
+
+	products, err := r.GetActiveByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if keyword != "" {
+		if err := r.applyHighlights(ctx, products, ids, searchTsQuery(keyword)); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return products, total, nil
+}
+
+// GetActiveByIDs gets the active products among ids with their images,
+// variants and tags, in the order of ids
+func (r *productSearchRepository) GetActiveByIDs(ctx context.Context, ids []uint) ([]*entity.Product, error) {
+	if len(ids) == 0 {
+		return []*entity.Product{}, nil
+	}
+
+	var found []*entity.Product
+	err := r.db.WithContext(ctx).
+		Preload("Images").
+		Preload("Variants").
+		Preload("Tags").
+		Where("id IN ? AND is_active = ?", ids, true).
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*entity.Product, len(found))
+	for _, product := range found {
+		byID[product.ID] = product
+	}
+	products := make([]*entity.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+// applyHighlights sets the highlighted name and description snippets of search results
+func (r *productSearchRepository) applyHighlights(ctx context.Context, products []*entity.Product, ids []uint, tsQuery clause.Expr) error {
+	var highlights []struct {
+		ID          uint
+		Name        string
+		Description string
+	}
+	err := r.db.WithContext(ctx).Model(&entity.Product{}).
+		Select(
+			"id, ts_headline('indonesian', name, ?, ?) AS name, ts_headline('indonesian', coalesce(description, ''), ?, ?) AS description",
+			tsQuery, "HighlightAll=true, "+searchHeadlineOptions, tsQuery, searchHeadlineOptions,
+		).
+		Where("id IN ?", ids).
+		Scan(&highlights).Error
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uint]*entity.SearchHighlight, len(highlights))
+	for _, h := range highlights {
+		highlight := &entity.SearchHighlight{
+			Name:        entity.SearchSnippet(h.Name),
+			Description: entity.SearchSnippet(h.Description),
+		}
+		if highlight.Name != "" || highlight.Description != "" {
+			byID[h.ID] = highlight
+		}
+	}
+	for _, product := range products {
+		product.Highlight = byID[product.ID]
+	}
+	return nil
+}
+
+// searchOrder returns the ORDER BY clause of a search sort option
+func searchOrder(sort string) string {
+	switch sort {
+	case searchSortNewest:
+		return "products.created_at DESC, products.id DESC"
+	case searchSortPriceAsc:
+		return "effective_price ASC, products.id DESC"
+	case searchSortPriceDesc:
+		return "effective_price DESC, products.id DESC"
+	case searchSortName:
+		return "products.name ASC, products.id DESC"
+	default:
+		return "rank DESC, products.id DESC"
+	}
+}
+
+// attributeValues lists the values of a product attribute as text rows,
+// whether the attribute holds a single value or a list. Products without
+// the attribute have no rows.
+func attributeValues(code string) clause.Expr {
+	return clause.Expr{
+		SQL: `jsonb_array_elements_text(CASE
+			WHEN jsonb_typeof(products.attributes -> ?) = 'array' THEN products.attributes -> ?
+			WHEN jsonb_typeof(products.attributes -> ?) IN ('string', 'number', 'boolean') THEN jsonb_build_array(products.attributes -> ?)
+			ELSE '[]'::jsonb END)`,
+		Vars: []interface{}{code, code, code, code},
+	}
+}
+
+// attributeContains matches products whose attribute holds one of values,
+// alone or in a list. It is built from containment checks so the GIN index on
+// products.attributes serves it. Values that read as numbers or booleans
+// also match attributes stored with that type.
+func attributeContains(code string, values []string) clause.Expr {
+	var conditions []string
+	var vars []interface{}
+	for _, value := range values {
+		literal, _ := json.Marshal(value)
+		literals := []json.RawMessage{literal}
+		if value != "null" && json.Valid([]byte(value)) && !strings.ContainsAny(value[:1], `"[{`) {
+			literals = append(literals, json.RawMessage(value))
+		}
+		for _, literal := range literals {
+			for _, stored := range []json.RawMessage{literal, append(append(json.RawMessage{'['}, literal...), ']')} {
+				document, _ := json.Marshal(map[string]json.RawMessage{code: stored})
+				conditions = append(conditions, "products.attributes @> ?::jsonb")
+				vars = append(vars, string(document))
+			}
+		}
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " OR ") + ")", Vars: vars}
+}
+
+// filtered builds a query for the active products matching a filter,
+// leaving out the except dimension
+func (r *productSearchRepository) filtered(ctx context.Context, filter *entity.ProductFilter, except string) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&entity.Product{}).Where("products.is_active = ?", true)
+
+	if filter.Keyword != "" {
+		query = query.Where(searchMatch(filter.Keyword))
+	}
+	if filter.CategoryID != nil && except != facetCategory {
+		// A category without a tree path matches nothing rather than every category
+		query = query.Where(`products.category_id IN (
+			SELECT id FROM categories
+			WHERE deleted_at IS NULL
+				AND path LIKE (SELECT NULLIF(path, '') FROM categories WHERE id = ? AND deleted_at IS NULL) || '%')`, *filter.CategoryID)
+	}
+	if except != facetPrice {
+		if filter.MinPrice != nil {
+			query = query.Where(effectivePriceSQL+" >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			query = query.Where(effectivePriceSQL+" <= ?", *filter.MaxPrice)
+		}
+	}
+	if conditions, vars := variantConditions(filter, except); conditions != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.is_active AND "+conditions+")", vars...)
+	}
+	if len(filter.Tags) > 0 && except != facetTag {
+		query = query.Where(`EXISTS (SELECT 1 FROM product_tags pt JOIN tags t ON t.id = pt.tag_id
+			WHERE pt.product_id = products.id AND t.name IN ?)`, filter.Tags)
+	}
+	if filter.MinRating != nil && except != facetRating {
+		query = query.Where(averageRatingSQL+" >= ?", *filter.MinRating)
+	}
+	for code, values := range filter.Attributes {
+		if len(values) == 0 || except == facetAttributePrefix+code {
+			continue
+		}
+		query = query.Where(attributeContains(code, values))
+	}
+
+	return query
+}
+
+// variantConditions builds the conditions a single variant (aliased pv) must
+// meet for the size, color and stock filters, leaving out the except dimension.
+// They are checked on one variant so "M in red, in stock" means exactly that.
+func variantConditions(filter *entity.ProductFilter, except string) (string, []interface{}) {
+	var conditions []string
+	var vars []interface{}
+	if len(filter.Sizes) > 0 && except != facetSize {
+		conditions = append(conditions, "pv.size IN ?")
+		vars = append(vars, filter.Sizes)
+	}
+	if len(filter.Colors) > 0 && except != facetColor {
+		conditions = append(conditions, "pv.color IN ?")
+		vars = append(vars, filter.Colors)
+	}
+	if filter.InStock && except != facetStock {
+		conditions = append(conditions, "pv.stock > 0")
+	}
+	return strings.Join(conditions, " AND "), vars
+}
+
+// Facets counts the products matching a filter per category, size, color,
+// tag and rating, and finds their price range
+func (r *productSearchRepository) Facets(ctx context.Context, filter *entity.ProductFilter) (*entity.ProductFacets, error) {
+	facets := &entity.ProductFacets{}
+
+	err := r.filtered(ctx, filter, facetCategory).
+		Joins("JOIN categories c ON c.id = products.category_id").
+		Select("CAST(c.id AS TEXT) AS value, c.name AS label, COUNT(*) AS count").
+		Group("c.id, c.name").
+		Order("count DESC, label").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if facets.Sizes, err = r.variantFacet(ctx, filter, facetSize, "pv.size"); err != nil {
+		return nil, err
+	}
+	if facets.Colors, err = r.variantFacet(ctx, filter, facetColor, "pv.color"); err != nil {
+		return nil, err
+	}
+
+	err = r.filtered(ctx, filter, facetTag).
+		Joins("JOIN product_tags pt ON pt.product_id = products.id").
+		Joins("JOIN tags t ON t.id = pt.tag_id").
+		Select("t.name AS value, COUNT(*) AS count").
+		Group("t.name").
+		Order("count DESC, value").
+		Scan(&facets.Tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.filtered(ctx, filter, facetPrice).
+		Select("COALESCE(MIN(" + effectivePriceSQL + "), 0) AS min, COALESCE(MAX(" + effectivePriceSQL + "), 0) AS max").
+		Scan(&facets.Price).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// In stock products need one variant with stock that also meets the
+	// size and color filters, the same check the in_stock filter makes
+	stocked := *filter
+	stocked.InStock = true
+	err = r.filtered(ctx, &stocked, facetNone).Count(&facets.InStock).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rated := r.filtered(ctx, filter, facetRating).Select("products.id, " + averageRatingSQL + " AS rating")
+	facets.Ratings = make([]entity.FacetCount, 0, len(ratingFacetValues))
+	for _, rating := range ratingFacetValues {
+		var count int64
+		err := r.db.WithContext(ctx).Table("(?) AS rated", rated).Where("rating >= ?", rating).Count(&count).Error
+		if err != nil {
+			return nil, err
+		}
+		facets.Ratings = append(facets.Ratings, entity.FacetCount{Value: strconv.Itoa(rating), Count: count})
+	}
+
+	return facets, nil
+}
+
+// variantFacet counts the matching products per value of a variant column.
+// Only variants meeting the other variant filters are counted.
+func (r *productSearchRepository) variantFacet(ctx context.Context, filter *entity.ProductFilter, facet, column string) ([]entity.FacetCount, error) {
+	join := "JOIN product_variants pv ON pv.product_id = products.id AND pv.is_active AND " + column + " <> ''"
+	conditions, vars := variantConditions(filter, facet)
+	if conditions != "" {
+		join += " AND " + conditions
+	}
+
+	var counts []entity.FacetCount
+	err := r.filtered(ctx, filter, facet).
+		Joins(join, vars...).
+		Select(column + " AS value, COUNT(DISTINCT products.id) AS count").
+		Group(column).
+		Order("count DESC, value").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// AttributeFacet counts the matching products per value of a product attribute
+func (r *productSearchRepository) AttributeFacet(ctx context.Context, filter *entity.ProductFilter, code string) ([]entity.FacetCount, error) {
+	var counts []entity.FacetCount
+	err := r.filtered(ctx, filter, facetAttributePrefix+code).
+		Joins("CROSS JOIN LATERAL ? AS attribute_value(value)", attributeValues(code)).
+		Select("attribute_value.value AS value, COUNT(DISTINCT products.id) AS count").
+		Group("attribute_value.value").
+		Order("count DESC, value").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// Suggest suggests product, category and tag names for a partially typed keyword.
+// Names starting with the keyword come first, then the closest fuzzy matches.
+func (r *productSearchRepository) Suggest(ctx context.Context, keyword string, limit int) ([]*entity.SearchSuggestion, error) {
+	lowered := strings.ToLower(keyword)
+	prefix := escapeLike(lowered) + "%"
+
+	var suggestions []*entity.SearchSuggestion
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT text, type, slug FROM (
+			SELECT name AS text, 'product' AS type, slug, word_similarity(@keyword, lower(name)) AS score
+			FROM products
+			WHERE deleted_at IS NULL AND is_active = true AND (lower(name) LIKE @prefix OR @keyword <% lower(name))
+			UNION ALL
+			SELECT name, 'category', slug, word_similarity(@keyword, lower(name))
+			FROM categories
+			WHERE deleted_at IS NULL AND (lower(name) LIKE @prefix OR @keyword <% lower(name))
+			UNION ALL
+			SELECT name, 'tag', '', word_similarity(@keyword, lower(name))
+			FROM tags
+			WHERE lower(name) LIKE @prefix OR @keyword <% lower(name)
+		) AS suggestions
+		ORDER BY lower(text) LIKE @prefix DESC, score DESC, length(text), text
+		LIMIT @limit`,
+		map[string]interface{}{"keyword": lowered, "prefix": prefix, "limit": limit},
+	).Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetIndexChanges gets the oldest products changed since they were last sent to the search index
+func (r *productSearchRepository) GetIndexChanges(ctx context.Context, limit int) ([]*entity.SearchIndexChange, error) {
+	var changes []*entity.SearchIndexChange
+	err := r.db.WithContext(ctx).
+		Order("changed_at ASC").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
+}
+
+// DeleteIndexChanges removes changes that were sent to the search index.
+// A product changed again after its change was read stays queued.
+func (r *productSearchRepository) DeleteIndexChanges(ctx context.Context, changes []*entity.SearchIndexChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	pairs := make([][]interface{}, len(changes))
+	for i, change := range changes {
+		pairs[i] = []interface{}{change.ProductID, change.ChangedAt}
+	}
+	return r.db.WithContext(ctx).
+		Where("(product_id, changed_at) IN ?", pairs).
+		Delete(&entity.SearchIndexChange{}).Error
+}
+
+// GetForIndex gets the active products among ids with everything their search documents need
+func (r *productSearchRepository) GetForIndex(ctx context.Context, ids []uint) ([]*entity.Product, error) {
+	var products []*entity.Product
+	err := r.forIndex(ctx).Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+// ListForIndex lists active products after the given ID for a full reindex
+func (r *productSearchRepository) ListForIndex(ctx context.Context, afterID uint, limit int) ([]*entity.Product, error) {
+	var products []*entity.Product
+	err := r.forIndex(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// forIndex builds a query for active products with their variants, tags and category
+func (r *productSearchRepository) forIndex(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Preload("Variants").
+		Preload("Tags").
+		Preload("Category").
+		Where("is_active = ?", true)
+}
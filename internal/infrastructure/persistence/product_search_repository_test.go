@@ -0,0 +1,32 @@
+package persistence
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAttributeContainsMatchesSingleAndListValues(t *testing.T) {
+	expr := attributeContains("material", []string{"katun"})
+
+	if strings.Count(expr.SQL, "products.attributes @> ?::jsonb") != 2 {
+		t.Errorf("SQL = %s, want two containment checks", expr.SQL)
+	}
+	want := []interface{}{`{"material":"katun"}`, `{"material":["katun"]}`}
+	if !slices.Equal(expr.Vars, want) {
+		t.Errorf("vars = %v, want %v", expr.Vars, want)
+	}
+}
+
+func TestAttributeContainsMatchesTypedValues(t *testing.T) {
+	expr := attributeContains("waterproof", []string{"true", "42"})
+
+	for _, document := range []string{`{"waterproof":true}`, `{"waterproof":"true"}`, `{"waterproof":42}`, `{"waterproof":[42]}`} {
+		if !slices.Contains(expr.Vars, interface{}(document)) {
+			t.Errorf("vars = %v, want them to include %s", expr.Vars, document)
+		}
+	}
+	if len(expr.Vars) != 8 {
+		t.Errorf("got %d containment checks, want 8", len(expr.Vars))
+	}
+}
@@ -0,0 +1,268 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+// NewProductVariantRepository creates a new ProductVariantRepository instance
+func NewProductVariantRepository(db *gorm.DB) repository.ProductVariantRepository {
+	return &productVariantRepository{
+		db: db,
+	}
+}
+
+// Create creates a new product variant
+func (r *productVariantRepository) Create(ctx context.Context, variant *entity.ProductVariant) error {
+	return r.db.WithContext(ctx).Create(variant).Error
+}
+
+// GetByID gets a product variant by ID
+func (r *productVariantRepository) GetByID(ctx context.Context, id uint) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	if err := r.withOptions(r.db.WithContext(ctx)).First(&variant, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrProductVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// GetByProductID gets all variants of a product
+func (r *productVariantRepository) GetByProductID(ctx context.Context, productID uint) ([]*entity.ProductVariant, error) {
+	var variants []*entity.ProductVariant
+	if err := r.withOptions(r.db.WithContext(ctx)).Where("product_id = ?", productID).Order("id ASC").Find(&variants).Error; err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// GetBySKU gets a product variant by SKU
+func (r *productVariantRepository) GetBySKU(ctx context.Context, sku string) (*entity.ProductVariant, error) {
+	var variant entity.ProductVariant
+	if err := r.withOptions(r.db.WithContext(ctx)).Where("sku = ?", sku).First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrProductVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// Update updates a product variant
+func (r *productVariantRepository) Update(ctx context.Context, variant *entity.ProductVariant) error {
+	return r.db.WithContext(ctx).Save(variant).Error
+}
+
+// Delete deletes a product variant
+func (r *productVariantRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.ProductVariant{}, id).Error
+}
+
+// UpdateStock sets the stock of a product variant
+func (r *productVariantRepository) UpdateStock(ctx context.Context, id uint, quantity int) error {
+	return r.db.WithContext(ctx).Model(&entity.ProductVariant{}).Where("id = ?", id).Update("stock", quantity).Error
+}
+
+// AdjustStock atomically adds delta to the stock of a product variant
+func (r *productVariantRepository) AdjustStock(ctx context.Context, id uint, delta int) error {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ProductVariant{}).
+		Where("id = ? AND stock + ? >= 0", id, delta).
+		Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("insufficient stock")
+	}
+	return nil
+}
+
+// GetOptions gets the options of a product with their values in order
+func (r *productVariantRepository) GetOptions(ctx context.Context, productID uint) ([]*entity.ProductOption, error) {
+	var options []*entity.ProductOption
+	err := r.db.WithContext(ctx).
+		Preload("Values", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).
+		Where("product_id = ?", productID).
+		Order("position, id").
+		Find(&options).Error
+	return options, err
+}
+
+// SaveMatrix replaces the options of a product and the variants built from
+// them. Options and values are matched by name so kept values keep their
+// IDs. Variants with an ID are kept with their stock, the others are created,
+// and the product's variants left out are deleted, or deactivated with no
+// stock when orders, carts or flash sales still refer to them.
+func (r *productVariantRepository) SaveMatrix(ctx context.Context, productID uint, skuTemplate string, options []*entity.ProductOption, variants []*entity.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Updating the product first locks it, so concurrent matrix changes
+		// run one after the other
+		result := tx.Model(&entity.Product{}).Where("id = ?", productID).Update("sku_template", skuTemplate)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("product not found")
+		}
+
+		valueIDs, err := r.saveOptions(tx, productID, options)
+		if err != nil {
+			return err
+		}
+
+		kept := make([]uint, 0, len(variants))
+		for _, variant := range variants {
+			if variant.ID == 0 {
+				variant.ProductID = productID
+				variant.IsActive = true
+				if err := tx.Omit(clause.Associations).Create(variant).Error; err != nil {
+					return err
+				}
+			} else {
+				err := tx.Model(&entity.ProductVariant{}).
+					Where("id = ? AND product_id = ?", variant.ID, productID).
+					Updates(map[string]interface{}{"size": variant.Size, "color": variant.Color, "is_active": true}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			links := make([]entity.ProductVariantOption, 0, len(variant.OptionValues))
+			for _, value := range variant.OptionValues {
+				if value.Option == nil {
+					return errors.New("variant option value has no option")
+				}
+				id, ok := valueIDs[optionValueKey(value.Option.Name, value.Value)]
+				if !ok {
+					return errors.New("variant option value is not among the product options")
+				}
+				links = append(links, entity.ProductVariantOption{VariantID: variant.ID, OptionValueID: id})
+			}
+			if err := tx.Where("variant_id = ?", variant.ID).Delete(&entity.ProductVariantOption{}).Error; err != nil {
+				return err
+			}
+			if len(links) > 0 {
+				if err := tx.Create(&links).Error; err != nil {
+					return err
+				}
+			}
+			kept = append(kept, variant.ID)
+		}
+
+		var removed []*entity.ProductVariant
+		query := tx.Where("product_id = ?", productID)
+		if len(kept) > 0 {
+			query = query.Where("id NOT IN ?", kept)
+		}
+		if err := query.Find(&removed).Error; err != nil {
+			return err
+		}
+		for _, variant := range removed {
+			// The delete runs in a savepoint so a foreign key violation only
+			// undoes the delete
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				return tx.Delete(variant).Error
+			})
+			if err == nil {
+				continue
+			}
+			err = tx.Model(&entity.ProductVariant{}).
+				Where("id = ?", variant.ID).
+				Updates(map[string]interface{}{"is_active": false, "stock": 0}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// saveOptions replaces the options of a product, returning the IDs of their
+// values keyed by option name and value
+func (r *productVariantRepository) saveOptions(tx *gorm.DB, productID uint, options []*entity.ProductOption) (map[string]uint, error) {
+	var existing []*entity.ProductOption
+	if err := tx.Preload("Values").Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*entity.ProductOption, len(existing))
+	for _, option := range existing {
+		byName[strings.ToLower(option.Name)] = option
+	}
+
+	valueIDs := make(map[string]uint)
+	keptOptions := make([]uint, 0, len(options))
+	for i, option := range options {
+		current, ok := byName[strings.ToLower(option.Name)]
+		if !ok {
+			current = &entity.ProductOption{ProductID: productID}
+		}
+		current.Name = option.Name
+		current.Position = i
+		if err := tx.Omit("Values").Save(current).Error; err != nil {
+			return nil, err
+		}
+		keptOptions = append(keptOptions, current.ID)
+
+		currentValues := make(map[string]*entity.ProductOptionValue, len(current.Values))
+		for j := range current.Values {
+			currentValues[strings.ToLower(current.Values[j].Value)] = &current.Values[j]
+		}
+		keptValues := make([]uint, 0, len(option.Values))
+		for j, value := range option.Values {
+			saved, ok := currentValues[strings.ToLower(value.Value)]
+			if !ok {
+				saved = &entity.ProductOptionValue{OptionID: current.ID}
+			}
+			saved.Value = value.Value
+			saved.Position = j
+			if err := tx.Omit("Option").Save(saved).Error; err != nil {
+				return nil, err
+			}
+			keptValues = append(keptValues, saved.ID)
+			valueIDs[optionValueKey(option.Name, value.Value)] = saved.ID
+		}
+
+		query := tx.Where("option_id = ?", current.ID)
+		if len(keptValues) > 0 {
+			query = query.Where("id NOT IN ?", keptValues)
+		}
+		if err := query.Delete(&entity.ProductOptionValue{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query := tx.Where("product_id = ?", productID)
+	if len(keptOptions) > 0 {
+		query = query.Where("id NOT IN ?", keptOptions)
+	}
+	if err := query.Delete(&entity.ProductOption{}).Error; err != nil {
+		return nil, err
+	}
+	return valueIDs, nil
+}
+
+// withOptions preloads the option values of variants with their option names
+func (r *productVariantRepository) withOptions(db *gorm.DB) *gorm.DB {
+	return db.Preload("OptionValues.Option")
+}
+
+// optionValueKey identifies an option value by its option name and value, ignoring case
+func optionValueKey(option, value string) string {
+	return strings.ToLower(option) + "\x00" + strings.ToLower(value)
+}
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository creates a new PromotionRepository instance
+func NewPromotionRepository(db *gorm.DB) repository.PromotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
+
+// Create creates a promotion together with its rules and scope
+func (r *promotionRepository) Create(ctx context.Context, promotion *entity.Promotion) error {
+	return r.db.WithContext(ctx).Create(promotion).Error
+}
+
+// GetByID gets a promotion by ID
+func (r *promotionRepository) GetByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	err := r.withRules(r.db.WithContext(ctx)).First(&promotion, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// Update updates a promotion and replaces its rules and scope
+func (r *promotionRepository) Update(ctx context.Context, promotion *entity.Promotion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Promotion{}).
+			Where("id = ?", promotion.ID).
+			Select("*").
+			Omit("id", "created_at", "deleted_at", clause.Associations).
+			Updates(promotion).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("promotion_id = ?", promotion.ID).Delete(&entity.PromotionBundleItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("promotion_id = ?", promotion.ID).Delete(&entity.PromotionTier{}).Error; err != nil {
+			return err
+		}
+		for i := range promotion.BundleItems {
+			promotion.BundleItems[i].ID = 0
+			promotion.BundleItems[i].PromotionID = promotion.ID
+		}
+		for i := range promotion.Tiers {
+			promotion.Tiers[i].ID = 0
+			promotion.Tiers[i].PromotionID = promotion.ID
+		}
+		if len(promotion.BundleItems) > 0 {
+			if err := tx.Create(&promotion.BundleItems).Error; err != nil {
+				return err
+			}
+		}
+		if len(promotion.Tiers) > 0 {
+			if err := tx.Create(&promotion.Tiers).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(promotion).Association("Products").Replace(promotion.Products); err != nil {
+			return err
+		}
+		return tx.Model(promotion).Association("Categories").Replace(promotion.Categories)
+	})
+}
+
+// Delete deletes a promotion
+func (r *promotionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Promotion{}, id).Error
+}
+
+// List lists promotions with filter and pagination
+func (r *promotionRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Promotion, int64, error) {
+	var promotions []*entity.Promotion
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Promotion{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("priority ASC, id ASC").Offset(offset).Limit(limit).Find(&promotions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return promotions, count, nil
+}
+
+// GetActive gets the promotions running at the given time in the order they are applied
+func (r *promotionRepository) GetActive(ctx context.Context, at time.Time) ([]*entity.Promotion, error) {
+	var promotions []*entity.Promotion
+	err := r.withRules(r.db.WithContext(ctx)).
+		Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, at, at).
+		Order("priority ASC, id ASC").
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// withRules preloads everything needed to evaluate a promotion
+func (r *promotionRepository) withRules(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("BundleItems").
+		Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("min_spend ASC")
+		}).
+		Preload("Products").
+		Preload("Categories")
+}
@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type reconciliationRepository struct {
+	db *gorm.DB
+}
+
+// NewReconciliationRepository creates a new ReconciliationRepository instance
+func NewReconciliationRepository(db *gorm.DB) repository.ReconciliationRepository {
+	return &reconciliationRepository{
+		db: db,
+	}
+}
+
+// CreateRun creates a reconciliation run together with its items
+func (r *reconciliationRepository) CreateRun(ctx context.Context, run *entity.ReconciliationRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// GetRunByID gets a reconciliation run with its items, optionally only items with the given status
+func (r *reconciliationRepository) GetRunByID(ctx context.Context, id uint, itemStatus string) (*entity.ReconciliationRun, error) {
+	var run entity.ReconciliationRun
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			if itemStatus != "" {
+				db = db.Where("status = ?", itemStatus)
+			}
+			return db.Order("id ASC")
+		}).
+		First(&run, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("reconciliation run not found")
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListRuns lists reconciliation runs, newest first
+func (r *reconciliationRepository) ListRuns(ctx context.Context, offset, limit int) ([]*entity.ReconciliationRun, int64, error) {
+	var runs []*entity.ReconciliationRun
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.ReconciliationRun{})
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, count, nil
+}
+
+// GetLastScheduledEnd gets the end of the period of the latest scheduled run
+// from a source, or nil when there has been none
+func (r *reconciliationRepository) GetLastScheduledEnd(ctx context.Context, source entity.ReconciliationSource) (*time.Time, error) {
+	var runs []*entity.ReconciliationRun
+	err := r.db.WithContext(ctx).
+		Where("source = ? AND created_by IS NULL", source).
+		Order("period_end DESC").
+		Limit(1).
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0].PeriodEnd, nil
+}
+
+// ListRunsByCursor lists reconciliation runs, one page after or before a cursor
+func (r *reconciliationRepository) ListRunsByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.ReconciliationRun, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.ReconciliationRun{})
+	return findByCursor(query, "reconciliation_runs", page, func(run *entity.ReconciliationRun) entity.Cursor {
+		return entity.Cursor{CreatedAt: run.CreatedAt, ID: run.ID}
+	})
+}
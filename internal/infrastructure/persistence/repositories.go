@@ -8,41 +8,77 @@ import (
 
 // Repositories holds all repository implementations
 type Repositories struct {
-	User           repository.UserRepository
-	Address        repository.AddressRepository
-	Product        repository.ProductRepository
-	Category       repository.CategoryRepository
-	ProductImage   repository.ProductImageRepository
-	ProductVariant repository.ProductVariantRepository
-	Review         repository.ReviewRepository
-	Tag            repository.TagRepository
-	Order          repository.OrderRepository
-	OrderItem      repository.OrderItemRepository
-	Payment        repository.PaymentRepository
-	Cart           repository.CartRepository
-	Wishlist       repository.WishlistRepository
-	Notification   repository.NotificationRepository
-	db             *gorm.DB
+	User               repository.UserRepository
+	Address            repository.AddressRepository
+	Product            repository.ProductRepository
+	Category           repository.CategoryRepository
+	CategoryTree       repository.CategoryTreeRepository
+	Attribute          repository.AttributeRepository
+	ProductImage       repository.ProductImageRepository
+	ProductVariant     repository.ProductVariantRepository
+	Review             repository.ReviewRepository
+	SizeChart          repository.SizeChartRepository
+	BodyProfile        repository.BodyProfileRepository
+	Tag                repository.TagRepository
+	ProductSearch      repository.ProductSearchRepository
+	Order              repository.OrderRepository
+	OrderItem          repository.OrderItemRepository
+	Payment            repository.PaymentRepository
+	PaymentRefund      repository.PaymentRefundRepository
+	PaymentEvent       repository.PaymentEventRepository
+	CODRemittance      repository.CODRemittanceRepository
+	Reconciliation     repository.ReconciliationRepository
+	Return             repository.ReturnRepository
+	Exchange           repository.ExchangeRepository
+	OrderStatusHistory repository.OrderStatusHistoryRepository
+	Voucher            repository.VoucherRepository
+	FlashSale          repository.FlashSaleRepository
+	Promotion          repository.PromotionRepository
+	Loyalty            repository.LoyaltyRepository
+	Wallet             repository.WalletRepository
+	GiftCard           repository.GiftCardRepository
+	Cart               repository.CartRepository
+	Wishlist           repository.WishlistRepository
+	Notification       repository.NotificationRepository
+	db                 *gorm.DB
 }
 
 // NewRepositories creates a new Repositories instance
 func NewRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		User:           NewUserRepository(db),
-		Address:        NewAddressRepository(db),
-		Product:        NewProductRepository(db),
-		Category:       NewCategoryRepository(db),
-		ProductImage:   NewProductImageRepository(db),
-		ProductVariant: NewProductVariantRepository(db),
-		Review:         NewReviewRepository(db),
-		Tag:            NewTagRepository(db),
-		Order:          NewOrderRepository(db),
-		OrderItem:      NewOrderItemRepository(db),
-		Payment:        NewPaymentRepository(db),
-		Cart:           NewCartRepository(db),
-		Wishlist:       NewWishlistRepository(db),
-		Notification:   NewNotificationRepository(db),
-		db:             db,
+		User:               NewUserRepository(db),
+		Address:            NewAddressRepository(db),
+		Product:            NewProductRepository(db),
+		Category:           NewCategoryRepository(db),
+		CategoryTree:       NewCategoryTreeRepository(db),
+		Attribute:          NewAttributeRepository(db),
+		ProductImage:       NewProductImageRepository(db),
+		ProductVariant:     NewProductVariantRepository(db),
+		Review:             NewReviewRepository(db),
+		SizeChart:          NewSizeChartRepository(db),
+		BodyProfile:        NewBodyProfileRepository(db),
+		Tag:                NewTagRepository(db),
+		ProductSearch:      NewProductSearchRepository(db),
+		Order:              NewOrderRepository(db),
+		OrderItem:          NewOrderItemRepository(db),
+		Payment:            NewPaymentRepository(db),
+		PaymentRefund:      NewPaymentRefundRepository(db),
+		PaymentEvent:       NewPaymentEventRepository(db),
+		CODRemittance:      NewCODRemittanceRepository(db),
+		Reconciliation:     NewReconciliationRepository(db),
+		Return:             NewReturnRepository(db),
+		Exchange:           NewExchangeRepository(db),
+		OrderStatusHistory: NewOrderStatusHistoryRepository(db),
+		Voucher:            NewVoucherRepository(db),
+		FlashSale:          NewFlashSaleRepository(db),
+		Promotion:          NewPromotionRepository(db),
+		Loyalty:            NewLoyaltyRepository(db),
+		Wallet:             NewWalletRepository(db),
+		GiftCard:           NewGiftCardRepository(db),
+		Cart:               NewCartRepository(db),
+		Wishlist:           NewWishlistRepository(db),
+		Notification:       NewNotificationRepository(db),
+		db:                 db,
 	}
 }
 
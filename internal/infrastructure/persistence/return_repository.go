@@ -0,0 +1,192 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type returnRepository struct {
+	db *gorm.DB
+}
+
+// NewReturnRepository creates a new ReturnRepository instance
+func NewReturnRepository(db *gorm.DB) repository.ReturnRepository {
+	return &returnRepository{
+		db: db,
+	}
+}
+
+// NextRMASequence gets the next number of the RMA number sequence
+func (r *returnRepository) NextRMASequence(ctx context.Context) (int64, error) {
+	var next int64
+	if err := r.db.WithContext(ctx).Raw("SELECT nextval('return_requests_rma_seq')").Scan(&next).Error; err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// Create creates a return request together with its items and photos. The
+// order is locked while check runs, so two requests cannot both claim the
+// last returnable quantity of an item.
+func (r *returnRepository) Create(ctx context.Context, ret *entity.ReturnRequest, check repository.AfterSalesCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkAfterSales(tx, ret.OrderID, check); err != nil {
+			return err
+		}
+		return tx.Create(ret).Error
+	})
+}
+
+// GetByID gets a return request by ID
+func (r *returnRepository) GetByID(ctx context.Context, id uint) (*entity.ReturnRequest, error) {
+	var ret entity.ReturnRequest
+	if err := r.db.WithContext(ctx).Preload("Items").Preload("Photos").First(&ret, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("return request not found")
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// GetByOrderID gets every return request of an order, oldest first
+func (r *returnRepository) GetByOrderID(ctx context.Context, orderID uint) ([]*entity.ReturnRequest, error) {
+	var returns []*entity.ReturnRequest
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&returns).Error
+	if err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+// GetByUserID gets return requests by user ID with pagination
+func (r *returnRepository) GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.ReturnRequest, int64, error) {
+	var returns []*entity.ReturnRequest
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.ReturnRequest{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Items").Order("created_at DESC").Offset(offset).Limit(limit).Find(&returns).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return returns, count, nil
+}
+
+// UpdateIfStatus saves a return request only while its stored status is still
+// one of the expected statuses, so concurrent updates apply at most once
+func (r *returnRepository) UpdateIfStatus(ctx context.Context, ret *entity.ReturnRequest, expected ...entity.ReturnStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.ReturnRequest{}).
+		Where("id = ? AND status IN ?", ret.ID, expected).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(ret)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Receive saves a received return request and puts its items back in stock in
+// the same transaction, only while its stored status is still one of the
+// expected statuses
+func (r *returnRepository) Receive(ctx context.Context, ret *entity.ReturnRequest, expected ...entity.ReturnStatus) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.ReturnRequest{}).
+			Where("id = ? AND status IN ?", ret.ID, expected).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(ret)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+		applied = true
+
+		for _, item := range ret.Items {
+			err := tx.Model(&entity.ProductVariant{}).
+				Where("id = ?", item.VariantID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error
+			if err != nil {
+				return fmt.Errorf("restock variant %d: %w", item.VariantID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// List lists return requests with filter and pagination
+func (r *returnRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.ReturnRequest, int64, error) {
+	var returns []*entity.ReturnRequest
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.ReturnRequest{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Items").Order("created_at DESC").Offset(offset).Limit(limit).Find(&returns).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return returns, count, nil
+}
+
+// ListByCursor lists return requests with filter, one page after or before a cursor
+func (r *returnRepository) ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.ReturnRequest, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.ReturnRequest{}).Preload("Items")
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return findByCursor(query, "return_requests", page, func(request *entity.ReturnRequest) entity.Cursor {
+		return entity.Cursor{CreatedAt: request.CreatedAt, ID: request.ID}
+	})
+}
+
+// checkAfterSales locks an order and runs check on its returns and exchanges
+// with tx
+func checkAfterSales(tx *gorm.DB, orderID uint, check repository.AfterSalesCheck) error {
+	var order entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("order not found")
+		}
+		return err
+	}
+
+	var returns []*entity.ReturnRequest
+	if err := tx.Preload("Items").Where("order_id = ?", orderID).Find(&returns).Error; err != nil {
+		return err
+	}
+	var exchanges []*entity.ExchangeRequest
+	if err := tx.Preload("Items").Where("order_id = ?", orderID).Find(&exchanges).Error; err != nil {
+		return err
+	}
+	return check(returns, exchanges)
+}
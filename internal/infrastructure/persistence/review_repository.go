@@ -0,0 +1,169 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// reviewImage is a photo attached to a review
+type reviewImage struct {
+	ID        uint `gorm:"primaryKey"`
+	ReviewID  uint
+	URL       string
+	CreatedAt time.Time
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository creates a new ReviewRepository instance
+func NewReviewRepository(db *gorm.DB) repository.ReviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+// Create creates a new review with its photos
+func (r *reviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Product", "User").Create(review).Error; err != nil {
+			return err
+		}
+		if len(review.Images) == 0 {
+			return nil
+		}
+
+		images := make([]reviewImage, 0, len(review.Images))
+		for _, url := range review.Images {
+			images = append(images, reviewImage{ReviewID: review.ID, URL: url})
+		}
+		return tx.Create(&images).Error
+	})
+}
+
+// GetByID gets a review by ID with its photos
+func (r *reviewRepository) GetByID(ctx context.Context, id uint) (*entity.Review, error) {
+	var review entity.Review
+	if err := r.db.WithContext(ctx).First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("review not found")
+		}
+		return nil, err
+	}
+	if err := r.loadImages(ctx, []*entity.Review{&review}); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByProductID gets the reviews of a product with pagination, newest first
+func (r *reviewRepository) GetByProductID(ctx context.Context, productID uint, offset, limit int) ([]*entity.Review, int64, error) {
+	return r.findPage(ctx, r.db.WithContext(ctx).Model(&entity.Review{}).Where("product_id = ?", productID), offset, limit)
+}
+
+// GetByProductIDByCursor gets the reviews of a product, one page after or before a cursor
+func (r *reviewRepository) GetByProductIDByCursor(ctx context.Context, productID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Review{}).Where("product_id = ?", productID)
+	return r.findByCursor(ctx, query, page)
+}
+
+// GetByUserID gets the reviews written by a user with pagination, newest first
+func (r *reviewRepository) GetByUserID(ctx context.Context, userID uint, offset, limit int) ([]*entity.Review, int64, error) {
+	return r.findPage(ctx, r.db.WithContext(ctx).Model(&entity.Review{}).Where("user_id = ?", userID), offset, limit)
+}
+
+// GetByUserIDByCursor gets the reviews written by a user, one page after or before a cursor
+func (r *reviewRepository) GetByUserIDByCursor(ctx context.Context, userID uint, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Review{}).Where("user_id = ?", userID)
+	return r.findByCursor(ctx, query, page)
+}
+
+// Update updates a review
+func (r *reviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	return r.db.WithContext(ctx).Omit("Product", "User").Save(review).Error
+}
+
+// Delete deletes a review with its photos
+func (r *reviewRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", id).Delete(&reviewImage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Review{}, id).Error
+	})
+}
+
+// GetAverageRatingByProductID gets the average rating of a product, 0 without reviews
+func (r *reviewRepository) GetAverageRatingByProductID(ctx context.Context, productID uint) (float64, error) {
+	var average float64
+	err := r.db.WithContext(ctx).
+		Model(&entity.Review{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(AVG(rating), 0)").
+		Scan(&average).Error
+	if err != nil {
+		return 0, err
+	}
+	return average, nil
+}
+
+// findPage counts a review query and loads one page of it, newest first
+func (r *reviewRepository) findPage(ctx context.Context, query *gorm.DB, offset, limit int) ([]*entity.Review, int64, error) {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []*entity.Review
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadImages(ctx, reviews); err != nil {
+		return nil, 0, err
+	}
+	return reviews, count, nil
+}
+
+// findByCursor loads one page of a review query relative to a cursor, with the photos
+func (r *reviewRepository) findByCursor(ctx context.Context, query *gorm.DB, page *entity.CursorPage) ([]*entity.Review, *entity.PageCursors, error) {
+	reviews, cursors, err := findByCursor(query, "reviews", page, func(review *entity.Review) entity.Cursor {
+		return entity.Cursor{CreatedAt: review.CreatedAt, ID: review.ID}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.loadImages(ctx, reviews); err != nil {
+		return nil, nil, err
+	}
+	return reviews, cursors, nil
+}
+
+// loadImages fills in the photo URLs of reviews
+func (r *reviewRepository) loadImages(ctx context.Context, reviews []*entity.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	byID := make(map[uint]*entity.Review, len(reviews))
+	ids := make([]uint, 0, len(reviews))
+	for _, review := range reviews {
+		byID[review.ID] = review
+		ids = append(ids, review.ID)
+	}
+
+	var images []reviewImage
+	if err := r.db.WithContext(ctx).Where("review_id IN ?", ids).Order("id ASC").Find(&images).Error; err != nil {
+		return err
+	}
+	for _, image := range images {
+		review := byID[image.ReviewID]
+		review.Images = append(review.Images, image.URL)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sizeChartRepository struct {
+	db *gorm.DB
+}
+
+// NewSizeChartRepository creates a new SizeChartRepository instance
+func NewSizeChartRepository(db *gorm.DB) repository.SizeChartRepository {
+	return &sizeChartRepository{
+		db: db,
+	}
+}
+
+// Create creates a size chart with its entries
+func (r *sizeChartRepository) Create(ctx context.Context, chart *entity.SizeChart) error {
+	return r.db.WithContext(ctx).Create(chart).Error
+}
+
+// GetByID gets a size chart by ID
+func (r *sizeChartRepository) GetByID(ctx context.Context, id uint) (*entity.SizeChart, error) {
+	var chart entity.SizeChart
+	if err := r.withEntries(r.db.WithContext(ctx)).First(&chart, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("size chart not found")
+		}
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// Update updates a size chart and replaces its entries
+func (r *sizeChartRepository) Update(ctx context.Context, chart *entity.SizeChart) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.SizeChart{}).
+			Where("id = ?", chart.ID).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(chart).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("size_chart_id = ?", chart.ID).Delete(&entity.SizeChartEntry{}).Error; err != nil {
+			return err
+		}
+		for i := range chart.Entries {
+			chart.Entries[i].ID = 0
+			chart.Entries[i].SizeChartID = chart.ID
+		}
+		if len(chart.Entries) > 0 {
+			return tx.Create(&chart.Entries).Error
+		}
+		return nil
+	})
+}
+
+// Delete deletes a size chart with its entries
+func (r *sizeChartRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.SizeChart{}, id).Error
+}
+
+// List lists size charts
+func (r *sizeChartRepository) List(ctx context.Context, offset, limit int) ([]*entity.SizeChart, int64, error) {
+	var charts []*entity.SizeChart
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&entity.SizeChart{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.withEntries(r.db.WithContext(ctx)).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&charts).Error
+	return charts, count, err
+}
+
+// FindForProduct finds the size chart of a product, or else the chart of
+// the nearest of its categories
+func (r *sizeChartRepository) FindForProduct(ctx context.Context, productID uint, categoryIDs []uint) (*entity.SizeChart, error) {
+	query := r.withEntries(r.db.WithContext(ctx)).Where("product_id = ?", productID)
+	if len(categoryIDs) > 0 {
+		query = query.Or("category_id IN ?", categoryIDs)
+	}
+
+	var charts []*entity.SizeChart
+	if err := query.Find(&charts).Error; err != nil {
+		return nil, err
+	}
+
+	for _, chart := range charts {
+		if chart.ProductID != nil {
+			return chart, nil
+		}
+	}
+	for _, categoryID := range categoryIDs {
+		for _, chart := range charts {
+			if chart.CategoryID != nil && *chart.CategoryID == categoryID {
+				return chart, nil
+			}
+		}
+	}
+	return nil, errors.New("size chart not found")
+}
+
+// ExistsFor reports whether another size chart belongs to the product or category
+func (r *sizeChartRepository) ExistsFor(ctx context.Context, productID, categoryID *uint, excludeID uint) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&entity.SizeChart{}).Where("id <> ?", excludeID)
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	} else if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetFitSummary counts the size feedback on a product: returns and
+// exchanges of items that were too small or too large, unless rejected or
+// cancelled, and the fit reviewers reported. Returns and exchanges with the
+// older wrong_size reason don't say which way the size was off, so they are
+// not counted and the summary only fills up with requests made since.
+func (r *sizeChartRepository) GetFitSummary(ctx context.Context, productID uint) (*entity.FitSummary, error) {
+	var rows []struct {
+		Feedback entity.FitFeedback
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT feedback, COUNT(*) AS count FROM (
+			SELECT rr.reason AS feedback
+			FROM return_items ri
+			JOIN return_requests rr ON rr.id = ri.return_request_id
+			JOIN product_variants pv ON pv.id = ri.variant_id
+			WHERE pv.product_id = @product AND rr.reason IN @reasons AND rr.status NOT IN @returnClosed
+			UNION ALL
+			SELECT er.reason
+			FROM exchange_items ei
+			JOIN exchange_requests er ON er.id = ei.exchange_request_id
+			WHERE ei.product_id = @product AND er.reason IN @reasons AND er.status NOT IN @exchangeClosed
+			UNION ALL
+			SELECT fit FROM reviews WHERE product_id = @product AND fit IN @fits
+		) AS feedback
+		GROUP BY feedback`,
+		map[string]interface{}{
+			"product":        productID,
+			"reasons":        []entity.ReturnReason{entity.ReturnReasonTooSmall, entity.ReturnReasonTooLarge},
+			"fits":           []entity.FitFeedback{entity.FitFeedbackTooSmall, entity.FitFeedbackTrueToSize, entity.FitFeedbackTooLarge},
+			"returnClosed":   []entity.ReturnStatus{entity.ReturnStatusRejected, entity.ReturnStatusCancelled},
+			"exchangeClosed": []entity.ExchangeStatus{entity.ExchangeStatusRejected, entity.ExchangeStatusCancelled},
+		},
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entity.FitSummary{}
+	for _, row := range rows {
+		switch row.Feedback {
+		case entity.FitFeedbackTooSmall:
+			summary.TooSmall += row.Count
+		case entity.FitFeedbackTrueToSize:
+			summary.TrueToSize += row.Count
+		case entity.FitFeedbackTooLarge:
+			summary.TooLarge += row.Count
+		}
+	}
+	return summary, nil
+}
+
+// withEntries preloads the entries of size charts, smallest size first
+func (r *sizeChartRepository) withEntries(db *gorm.DB) *gorm.DB {
+	return db.Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	})
+}
+
+type bodyProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewBodyProfileRepository creates a new BodyProfileRepository instance
+func NewBodyProfileRepository(db *gorm.DB) repository.BodyProfileRepository {
+	return &bodyProfileRepository{
+		db: db,
+	}
+}
+
+// GetByUserID gets the body profile of a user
+func (r *bodyProfileRepository) GetByUserID(ctx context.Context, userID uint) (*entity.BodyProfile, error) {
+	var profile entity.BodyProfile
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("body profile not found")
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Save creates or replaces the body profile of a user
+func (r *bodyProfileRepository) Save(ctx context.Context, profile *entity.BodyProfile) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"height", "chest", "waist", "hips", "inseam", "foot_length", "weight", "fit_preference", "updated_at",
+		}),
+	}).Create(profile).Error
+}
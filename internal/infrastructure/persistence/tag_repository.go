@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new TagRepository instance
+func NewTagRepository(db *gorm.DB) repository.TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+// Create creates a new tag
+func (r *tagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+// GetByID gets a tag by ID
+func (r *tagRepository) GetByID(ctx context.Context, id uint) (*entity.Tag, error) {
+	var tag entity.Tag
+	if err := r.db.WithContext(ctx).First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByName gets a tag by name
+func (r *tagRepository) GetByName(ctx context.Context, name string) (*entity.Tag, error) {
+	var tag entity.Tag
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// List lists all tags by name
+func (r *tagRepository) List(ctx context.Context) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Update updates a tag
+func (r *tagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	return r.db.WithContext(ctx).Save(tag).Error
+}
+
+// Delete deletes a tag and unlinks it from its products
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Tag{}, id).Error
+	})
+}
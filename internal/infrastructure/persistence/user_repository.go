@@ -77,6 +77,14 @@ func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity
 	return users, count, nil
 }
 
+// ListByCursor lists users, one page after or before a cursor
+func (r *userRepository) ListByCursor(ctx context.Context, page *entity.CursorPage) ([]*entity.User, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.User{})
+	return findByCursor(query, "users", page, func(user *entity.User) entity.Cursor {
+		return entity.Cursor{CreatedAt: user.CreatedAt, ID: user.ID}
+	})
+}
+
 // UpdateLastLogin updates a user's last login time
 func (r *userRepository) UpdateLastLogin(ctx context.Context, id uint) error {
 	now := time.Now()
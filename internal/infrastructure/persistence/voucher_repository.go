@@ -0,0 +1,219 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type voucherRepository struct {
+	db *gorm.DB
+}
+
+// NewVoucherRepository creates a new VoucherRepository instance
+func NewVoucherRepository(db *gorm.DB) repository.VoucherRepository {
+	return &voucherRepository{
+		db: db,
+	}
+}
+
+// Create creates a voucher together with its product and category scope
+func (r *voucherRepository) Create(ctx context.Context, voucher *entity.Voucher) error {
+	return r.db.WithContext(ctx).Create(voucher).Error
+}
+
+// GetByID gets a voucher by ID
+func (r *voucherRepository) GetByID(ctx context.Context, id uint) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	if err := r.db.WithContext(ctx).Preload("Products").Preload("Categories").First(&voucher, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("voucher not found")
+		}
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// GetByCode gets a voucher by code
+func (r *voucherRepository) GetByCode(ctx context.Context, code string) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := r.db.WithContext(ctx).
+		Preload("Products").
+		Preload("Categories").
+		Where("code = ?", code).
+		First(&voucher).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("voucher not found")
+		}
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// Update updates a voucher and replaces its product and category scope.
+// UsedCount is left alone, it only changes through Redeem and Release.
+func (r *voucherRepository) Update(ctx context.Context, voucher *entity.Voucher) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Voucher{}).
+			Where("id = ?", voucher.ID).
+			Select("*").
+			Omit("id", "used_count", "created_at", "deleted_at", clause.Associations).
+			Updates(voucher).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(voucher).Association("Products").Replace(voucher.Products); err != nil {
+			return err
+		}
+		return tx.Model(voucher).Association("Categories").Replace(voucher.Categories)
+	})
+}
+
+// Delete deletes a voucher
+func (r *voucherRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Voucher{}, id).Error
+}
+
+// List lists vouchers with filter and pagination
+func (r *voucherRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*entity.Voucher, int64, error) {
+	var vouchers []*entity.Voucher
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Voucher{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&vouchers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return vouchers, count, nil
+}
+
+// ListByCursor lists vouchers with filter, one page after or before a cursor
+func (r *voucherRepository) ListByCursor(ctx context.Context, filter map[string]interface{}, page *entity.CursorPage) ([]*entity.Voucher, *entity.PageCursors, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Voucher{})
+	if len(filter) > 0 {
+		query = query.Where(filter)
+	}
+	return findByCursor(query, "vouchers", page, func(voucher *entity.Voucher) entity.Cursor {
+		return entity.Cursor{CreatedAt: voucher.CreatedAt, ID: voucher.ID}
+	})
+}
+
+// CountUserRedemptions counts the uses of a voucher by a user that were not released
+func (r *voucherRepository) CountUserRedemptions(ctx context.Context, voucherID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.VoucherRedemption{}).
+		Where("voucher_id = ? AND user_id = ? AND released_at IS NULL", voucherID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetRedemption gets the use of a voucher on an order
+func (r *voucherRepository) GetRedemption(ctx context.Context, orderID uint) (*entity.VoucherRedemption, error) {
+	var redemption entity.VoucherRedemption
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&redemption).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrVoucherRedemptionNotFound
+		}
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+// Redeem records a use of a voucher on an order and sets the order's voucher
+// code, DiscountAmount and FinalAmount in the same transaction. The order
+// must still be pending, without a voucher and without a payment.
+func (r *voucherRepository) Redeem(ctx context.Context, redemption *entity.VoucherRedemption, order *entity.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := redeemVoucher(tx, redemption); err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ? AND voucher_code = '' AND wallet_amount = 0", order.ID, entity.OrderStatusPending).
+			Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id)").
+			Updates(map[string]interface{}{
+				"voucher_code":    order.VoucherCode,
+				"discount_amount": order.DiscountAmount,
+				"final_amount":    order.FinalAmount,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("order was updated concurrently")
+		}
+		return nil
+	})
+}
+
+// redeemVoucher counts a use of a voucher and records it within tx. The use
+// is counted with a conditional update, whose row lock also makes concurrent
+// checkouts check the per-customer limit one at a time.
+func redeemVoucher(tx *gorm.DB, redemption *entity.VoucherRedemption) error {
+	result := tx.Model(&entity.Voucher{}).
+		Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", redemption.VoucherID).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("voucher usage limit reached")
+	}
+
+	var voucher entity.Voucher
+	if err := tx.First(&voucher, redemption.VoucherID).Error; err != nil {
+		return err
+	}
+	if voucher.UsageLimitPerUser > 0 {
+		var used int64
+		err := tx.Model(&entity.VoucherRedemption{}).
+			Where("voucher_id = ? AND user_id = ? AND released_at IS NULL", voucher.ID, redemption.UserID).
+			Count(&used).Error
+		if err != nil {
+			return err
+		}
+		if used >= int64(voucher.UsageLimitPerUser) {
+			return errors.New("voucher usage limit per customer reached")
+		}
+	}
+
+	return tx.Create(redemption).Error
+}
+
+// Release gives back the voucher use of an order, if any
+func (r *voucherRepository) Release(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var redemption entity.VoucherRedemption
+		result := tx.Model(&redemption).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "voucher_id"}}}).
+			Where("order_id = ? AND released_at IS NULL", orderID).
+			Update("released_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+
+		return tx.Model(&entity.Voucher{}).
+			Where("id = ? AND used_count > 0", redemption.VoucherID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error
+	})
+}
@@ -0,0 +1,161 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type walletRepository struct {
+	db *gorm.DB
+}
+
+// NewWalletRepository creates a new WalletRepository instance
+func NewWalletRepository(db *gorm.DB) repository.WalletRepository {
+	return &walletRepository{
+		db: db,
+	}
+}
+
+// GetWallet gets the wallet of a user, creating an empty one on first use
+func (r *walletRepository) GetWallet(ctx context.Context, userID uint) (*entity.Wallet, error) {
+	wallet := entity.Wallet{UserID: userID}
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).FirstOrCreate(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// GetTransactions gets the store credit ledger of a user, newest first
+func (r *walletRepository) GetTransactions(ctx context.Context, userID uint, offset, limit int) ([]*entity.WalletTransaction, int64, error) {
+	var transactions []*entity.WalletTransaction
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.WalletTransaction{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, count, nil
+}
+
+// GetOrderTransactions gets the store credit entries recorded for an order
+func (r *walletRepository) GetOrderTransactions(ctx context.Context, orderID uint) ([]*entity.WalletTransaction, error) {
+	var transactions []*entity.WalletTransaction
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id").Find(&transactions).Error
+	return transactions, err
+}
+
+// Record adds a credit or a debit to a user's wallet; debits hold a negative
+// amount. False is returned when an entry with the same reference was already
+// recorded, and ErrInsufficientWalletBalance when a debit exceeds the balance.
+func (r *walletRepository) Record(ctx context.Context, transaction *entity.WalletTransaction) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = recordWalletTransaction(tx, transaction)
+		return err
+	})
+	return applied, err
+}
+
+// Pay spends store credit on a pending order that has no payment yet and
+// records the amount on the order in the same transaction. With a payment
+// the credit covers the whole order: the payment is recorded and the order
+// moved on, so the credit is never taken without the order showing it paid.
+func (r *walletRepository) Pay(ctx context.Context, transaction *entity.WalletTransaction, order *entity.Order, payment *repository.WalletPayment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		applied, err := recordWalletTransaction(tx, transaction)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return errors.New("store credit has already been applied to this order")
+		}
+
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ? AND wallet_amount = 0", order.ID, entity.OrderStatusPending).
+			Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id)").
+			Update("wallet_amount", order.WalletAmount)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("order was updated concurrently")
+		}
+
+		if payment == nil {
+			return nil
+		}
+		return payInFull(tx, order, payment)
+	})
+}
+
+// payInFull records the payment of an order covered entirely by store credit
+// within tx and moves the order on
+func payInFull(tx *gorm.DB, order *entity.Order, payment *repository.WalletPayment) error {
+	payment.Payment.OrderID = order.ID
+	if err := tx.Create(payment.Payment).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&entity.Order{}).Where("id = ?", order.ID).Update("payment_id", payment.Payment.ID).Error; err != nil {
+		return err
+	}
+
+	payment.Paid.OrderID = order.ID
+	moved, err := applyOrderStatusChange(tx, payment.Paid)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return errors.New("order was updated concurrently")
+	}
+	return nil
+}
+
+// recordWalletTransaction applies a wallet entry inside a transaction. The
+// wallet row is locked so concurrent checkouts cannot spend the same credit.
+func recordWalletTransaction(tx *gorm.DB, transaction *entity.WalletTransaction) (bool, error) {
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.Wallet{UserID: transaction.UserID}).Error
+	if err != nil {
+		return false, err
+	}
+
+	var wallet entity.Wallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", transaction.UserID).First(&wallet).Error; err != nil {
+		return false, err
+	}
+
+	var count int64
+	if err := tx.Model(&entity.WalletTransaction{}).Where("reference = ?", transaction.Reference).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if wallet.Balance+transaction.Amount < -0.005 {
+		return false, repository.ErrInsufficientWalletBalance
+	}
+
+	transaction.BalanceAfter = wallet.Balance + transaction.Amount
+	if err := tx.Create(transaction).Error; err != nil {
+		return false, err
+	}
+
+	if err := tx.Model(&wallet).Update("balance", transaction.BalanceAfter).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
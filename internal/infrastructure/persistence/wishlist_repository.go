@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"fashion-shop/internal/domain/entity"
+	"fashion-shop/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWishlistRepository creates a new WishlistRepository instance
+func NewWishlistRepository(db *gorm.DB) repository.WishlistRepository {
+	return &wishlistRepository{
+		db: db,
+	}
+}
+
+// GetOrCreate gets the wishlist of a user, creating an empty one on first use
+func (r *wishlistRepository) GetOrCreate(ctx context.Context, userID uint) (*entity.Wishlist, error) {
+	wishlist := &entity.Wishlist{UserID: userID}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Omit("User", "Items").
+		Create(wishlist).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByUserID(ctx, userID)
+}
+
+// GetByID gets a wishlist by ID
+func (r *wishlistRepository) GetByID(ctx context.Context, id uint) (*entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	if err := r.db.WithContext(ctx).First(&wishlist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("wishlist not found")
+		}
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+// GetByUserID gets the wishlist of a user
+func (r *wishlistRepository) GetByUserID(ctx context.Context, userID uint) (*entity.Wishlist, error) {
+	var wishlist entity.Wishlist
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&wishlist).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("wishlist not found")
+		}
+		return nil, err
+	}
+	return &wishlist, nil
+}
+
+// AddItem adds a product to a wishlist, doing nothing when it is already there
+func (r *wishlistRepository) AddItem(ctx context.Context, wishlistID uint, productID uint) error {
+	item := &entity.WishlistItem{WishlistID: wishlistID, ProductID: productID}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "wishlist_id"}, {Name: "product_id"}}, DoNothing: true}).
+		Omit("Product").
+		Create(item).Error
+}
+
+// RemoveItem removes an item from a wishlist
+func (r *wishlistRepository) RemoveItem(ctx context.Context, itemID uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.WishlistItem{}, itemID).Error
+}
+
+// IsProductInWishlist reports whether a product is in a wishlist
+func (r *wishlistRepository) IsProductInWishlist(ctx context.Context, wishlistID uint, productID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.WishlistItem{}).
+		Where("wishlist_id = ? AND product_id = ?", wishlistID, productID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetItems gets the items of a wishlist with their products and pagination, newest first
+func (r *wishlistRepository) GetItems(ctx context.Context, wishlistID uint, offset, limit int) ([]*entity.WishlistItem, int64, error) {
+	var items []*entity.WishlistItem
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&entity.WishlistItem{}).Where("wishlist_id = ?", wishlistID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Preload("Product.Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_primary DESC, id ASC")
+		}).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// JobFunc is a unit of background work
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler runs jobs at fixed intervals in the background
+type Scheduler struct {
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new Scheduler instance
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job that runs once per interval. Jobs must be registered
+// before Start. A job without a positive interval is logged and not scheduled.
+func (s *Scheduler) Every(name string, interval time.Duration, run JobFunc) {
+	if interval <= 0 {
+		log.Printf("scheduler: job %s not scheduled, interval %s is not positive", name, interval)
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start starts running all registered jobs
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop stops all jobs and waits for running ones to finish
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// loop runs a job on every tick until the context is cancelled
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.run(ctx); err != nil {
+				log.Printf("scheduler: job %s failed: %v", j.name, err)
+			}
+		}
+	}
+}
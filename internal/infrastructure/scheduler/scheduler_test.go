@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEverySkipsJobsWithoutPositiveInterval(t *testing.T) {
+	s := NewScheduler()
+	s.Every("never", 0, func(ctx context.Context) error { return nil })
+	s.Every("negative", -time.Second, func(ctx context.Context) error { return nil })
+
+	if len(s.jobs) != 0 {
+		t.Fatalf("registered %d jobs, want none", len(s.jobs))
+	}
+
+	// Starting must not panic on the skipped jobs
+	s.Start()
+	s.Stop()
+}
+
+func TestSchedulerRunsJobs(t *testing.T) {
+	runs := make(chan struct{}, 1)
+	s := NewScheduler()
+	s.Every("tick", time.Millisecond, func(ctx context.Context) error {
+		select {
+		case runs <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	s.Start()
+	defer s.Stop()
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+}
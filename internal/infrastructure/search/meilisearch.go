@@ -0,0 +1,199 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// meilisearchSettings configures how the product index is searched
+var meilisearchSettings = map[string]interface{}{
+	"searchableAttributes": []string{"name", "tags", "category", "description"},
+	"filterableAttributes": []string{"id", "category_id", "tags", "sizes", "colors", "price", "in_stock", "indexed_at"},
+	"sortableAttributes":   []string{"name", "price", "created_at"},
+}
+
+// meilisearchSorts maps a sort option to Meilisearch sort rules
+var meilisearchSorts = map[string][]string{
+	SortNewest:    {"created_at:desc"},
+	SortPriceAsc:  {"price:asc"},
+	SortPriceDesc: {"price:desc"},
+	SortName:      {"name:asc"},
+}
+
+// meilisearchSearchResponse is the subset of a search response used by the adapter
+type meilisearchSearchResponse struct {
+	Hits []struct {
+		ID        uint `json:"id"`
+		Formatted struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"_formatted"`
+	} `json:"hits"`
+	EstimatedTotalHits int64 `json:"estimatedTotalHits"`
+}
+
+type meilisearchIndex struct {
+	apiKey  string
+	index   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewMeilisearchIndex creates a new Meilisearch SearchIndex
+func NewMeilisearchIndex(baseURL, apiKey, index string) SearchIndex {
+	return &meilisearchIndex{
+		apiKey:  apiKey,
+		index:   index,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the engine name
+func (m *meilisearchIndex) Name() string {
+	return EngineMeilisearch
+}
+
+// Setup applies the index settings, which creates the index if it does not exist
+func (m *meilisearchIndex) Setup(ctx context.Context) error {
+	return m.do(ctx, http.MethodPatch, m.indexPath("/settings"), meilisearchSettings, nil)
+}
+
+// Upsert adds documents or replaces the stored ones with the same ID
+func (m *meilisearchIndex) Upsert(ctx context.Context, documents []*entity.ProductSearchDocument) error {
+	if len(documents) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, m.indexPath("/documents?primaryKey=id"), documents, nil)
+}
+
+// Delete removes the documents of products
+func (m *meilisearchIndex) Delete(ctx context.Context, productIDs []uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return m.do(ctx, http.MethodPost, m.indexPath("/documents/delete-batch"), productIDs, nil)
+}
+
+// DeleteIndexedBefore removes documents last written before the given time
+func (m *meilisearchIndex) DeleteIndexedBefore(ctx context.Context, before time.Time) error {
+	return m.do(ctx, http.MethodPost, m.indexPath("/documents/delete"), map[string]interface{}{
+		"filter": fmt.Sprintf("indexed_at < %d", before.UnixMilli()),
+	}, nil)
+}
+
+// Search searches the index. Meilisearch ranks by relevance and tolerates typos on its own.
+func (m *meilisearchIndex) Search(ctx context.Context, query *Query) (*Result, error) {
+	filters := make([]string, 0, len(query.Filter))
+	for field, value := range query.Filter {
+		filters = append(filters, fmt.Sprintf("%s = %s", field, meilisearchValue(value)))
+	}
+
+	body := map[string]interface{}{
+		"q":                     query.Keyword,
+		"offset":                query.Offset,
+		"limit":                 query.Limit,
+		"filter":                filters,
+		"attributesToRetrieve":  []string{"id"},
+		"attributesToHighlight": []string{"name", "description"},
+		"attributesToCrop":      []string{"description"},
+		"cropLength":            30,
+		"highlightPreTag":       entity.SearchMatchStart,
+		"highlightPostTag":      entity.SearchMatchStop,
+	}
+	if sort, ok := meilisearchSorts[query.Sort]; ok {
+		body["sort"] = sort
+	}
+
+	var resp meilisearchSearchResponse
+	if err := m.do(ctx, http.MethodPost, m.indexPath("/search"), body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Hits:  make([]Hit, 0, len(resp.Hits)),
+		Total: resp.EstimatedTotalHits,
+	}
+	for _, h := range resp.Hits {
+		hit := Hit{ProductID: h.ID}
+		highlight := &entity.SearchHighlight{
+			Name:        entity.SearchSnippet(h.Formatted.Name),
+			Description: entity.SearchSnippet(h.Formatted.Description),
+		}
+		if highlight.Name != "" || highlight.Description != "" {
+			hit.Highlight = highlight
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
+
+// meilisearchValue formats a filter value, quoting strings
+func meilisearchValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// indexPath returns the API path of the product index
+func (m *meilisearchIndex) indexPath(path string) string {
+	return "/indexes/" + m.index + path
+}
+
+// do sends a request to the Meilisearch API. Writes are queued as tasks that
+// Meilisearch applies in order.
+func (m *meilisearchIndex) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return fmt.Errorf("meilisearch request failed with status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("meilisearch: %s: %s", apiErr.Code, apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
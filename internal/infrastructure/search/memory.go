@@ -0,0 +1,225 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// memoryIndex is a SearchIndex kept in process memory, for tests and local
+// development. It matches whole and partial words without typo tolerance.
+type memoryIndex struct {
+	mu        sync.RWMutex
+	documents map[uint]*entity.ProductSearchDocument
+}
+
+// NewMemoryIndex creates a new in-memory SearchIndex
+func NewMemoryIndex() SearchIndex {
+	return &memoryIndex{
+		documents: make(map[uint]*entity.ProductSearchDocument),
+	}
+}
+
+// Name returns the engine name
+func (m *memoryIndex) Name() string {
+	return EngineMemory
+}
+
+// Setup does nothing, the index needs no configuration
+func (m *memoryIndex) Setup(ctx context.Context) error {
+	return nil
+}
+
+// Upsert adds documents or replaces the stored ones with the same ID
+func (m *memoryIndex) Upsert(ctx context.Context, documents []*entity.ProductSearchDocument) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, document := range documents {
+		stored := *document
+		m.documents[document.ID] = &stored
+	}
+	return nil
+}
+
+// Delete removes the documents of products
+func (m *memoryIndex) Delete(ctx context.Context, productIDs []uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range productIDs {
+		delete(m.documents, id)
+	}
+	return nil
+}
+
+// DeleteIndexedBefore removes documents last written before the given time
+func (m *memoryIndex) DeleteIndexedBefore(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, document := range m.documents {
+		if document.IndexedAt < before.UnixMilli() {
+			delete(m.documents, id)
+		}
+	}
+	return nil
+}
+
+// Search finds documents containing every keyword term, names weighing most
+func (m *memoryIndex) Search(ctx context.Context, query *Query) (*Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	terms := strings.Fields(strings.ToLower(query.Keyword))
+
+	type scored struct {
+		document *entity.ProductSearchDocument
+		score    int
+	}
+	var matches []scored
+	for _, document := range m.documents {
+		ok, err := matchesFilter(document, query.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if score, ok := scoreDocument(document, terms); ok {
+			matches = append(matches, scored{document: document, score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i].document, matches[j].document
+		switch query.Sort {
+		case SortNewest:
+			if a.CreatedAt != b.CreatedAt {
+				return a.CreatedAt > b.CreatedAt
+			}
+		case SortPriceAsc:
+			if a.Price != b.Price {
+				return a.Price < b.Price
+			}
+		case SortPriceDesc:
+			if a.Price != b.Price {
+				return a.Price > b.Price
+			}
+		case SortName:
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		default:
+			if matches[i].score != matches[j].score {
+				return matches[i].score > matches[j].score
+			}
+		}
+		return a.ID > b.ID
+	})
+
+	result := &Result{Total: int64(len(matches))}
+	start := min(query.Offset, len(matches))
+	end := min(start+query.Limit, len(matches))
+	for _, match := range matches[start:end] {
+		hit := Hit{ProductID: match.document.ID}
+		if name := entity.SearchSnippet(highlightTerms(match.document.Name, terms)); name != "" {
+			hit.Highlight = &entity.SearchHighlight{Name: name}
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
+
+// scoreDocument scores a document against the search terms, reporting
+// whether every term is found
+func scoreDocument(document *entity.ProductSearchDocument, terms []string) (int, bool) {
+	fields := []struct {
+		text   string
+		weight int
+	}{
+		{strings.ToLower(document.Name), 4},
+		{strings.ToLower(strings.Join(document.Tags, " ")), 2},
+		{strings.ToLower(document.Category), 2},
+		{strings.ToLower(document.Description), 1},
+	}
+
+	score := 0
+	for _, term := range terms {
+		found := false
+		for _, field := range fields {
+			if strings.Contains(field.text, term) {
+				score += field.weight
+				found = true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// matchesFilter reports whether a document has the filtered field values
+func matchesFilter(document *entity.ProductSearchDocument, filter map[string]interface{}) (bool, error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return false, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false, err
+	}
+
+	for field, want := range filter {
+		got, ok := fields[field]
+		if !ok {
+			return false, fmt.Errorf("search index cannot filter on %q", field)
+		}
+		if values, ok := got.([]interface{}); ok {
+			if !containsValue(values, want) {
+				return false, nil
+			}
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// containsValue reports whether a list field holds a value
+func containsValue(values []interface{}, want interface{}) bool {
+	for _, value := range values {
+		if fmt.Sprint(value) == fmt.Sprint(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightTerms wraps the search terms found in a text in the match sentinels
+func highlightTerms(text string, terms []string) string {
+	if len(terms) == 0 {
+		return text
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	pattern := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+	return pattern.ReplaceAllString(text, entity.SearchMatchStart+"$0"+entity.SearchMatchStop)
+}
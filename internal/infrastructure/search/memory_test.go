@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+func TestMemoryIndexEscapesHighlights(t *testing.T) {
+	ctx := context.Background()
+	index := NewMemoryIndex()
+	err := index.Upsert(ctx, []*entity.ProductSearchDocument{
+		{ID: 1, Name: `Kemeja <script>alert("x")</script> Linen`},
+	})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	result, err := index.Search(ctx, &Query{Keyword: "linen", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	want := `Kemeja &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>Linen</mark>`
+	if len(result.Hits) != 1 || result.Hits[0].Highlight == nil || result.Hits[0].Highlight.Name != want {
+		t.Fatalf("hits = %+v, want the name highlighted as %s", result.Hits, want)
+	}
+}
+
+func newTestMemoryIndex(t *testing.T, documents ...*entity.ProductSearchDocument) SearchIndex {
+	t.Helper()
+	index := NewMemoryIndex()
+	if err := index.Upsert(context.Background(), documents); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	return index
+}
+
+func hitIDs(result *Result) []uint {
+	ids := make([]uint, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ProductID
+	}
+	return ids
+}
+
+func TestMemoryIndexSearchRanksNameMatchesFirstAndPages(t *testing.T) {
+	index := newTestMemoryIndex(t,
+		&entity.ProductSearchDocument{ID: 1, Name: "Kaos Polos", Description: "Bahan linen"},
+		&entity.ProductSearchDocument{ID: 2, Name: "Kemeja Linen"},
+		&entity.ProductSearchDocument{ID: 3, Name: "Celana Linen", Tags: []string{"linen"}},
+		&entity.ProductSearchDocument{ID: 4, Name: "Jaket Denim"},
+	)
+
+	result, err := index.Search(context.Background(), &Query{Keyword: "LINEN", Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	// Celana scores name and tag, Kemeja the name only, Kaos the description only
+	if result.Total != 3 {
+		t.Errorf("total = %d, want 3", result.Total)
+	}
+	if ids := hitIDs(result); len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("second page = %v, want [2]", ids)
+	}
+}
+
+func TestMemoryIndexSearchRequiresEveryTerm(t *testing.T) {
+	index := newTestMemoryIndex(t,
+		&entity.ProductSearchDocument{ID: 1, Name: "Kemeja Linen Putih"},
+		&entity.ProductSearchDocument{ID: 2, Name: "Kemeja Linen Hitam"},
+	)
+
+	result, err := index.Search(context.Background(), &Query{Keyword: "linen putih", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if ids := hitIDs(result); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("hits = %v, want [1]", ids)
+	}
+}
+
+func TestMemoryIndexSearchFiltersAndSorts(t *testing.T) {
+	index := newTestMemoryIndex(t,
+		&entity.ProductSearchDocument{ID: 1, Name: "Kemeja A", CategoryID: 3, Sizes: []string{"M"}, Price: 150000},
+		&entity.ProductSearchDocument{ID: 2, Name: "Kemeja B", CategoryID: 3, Sizes: []string{"M", "L"}, Price: 90000},
+		&entity.ProductSearchDocument{ID: 3, Name: "Kemeja C", CategoryID: 3, Sizes: []string{"S"}, Price: 50000},
+		&entity.ProductSearchDocument{ID: 4, Name: "Kemeja D", CategoryID: 4, Sizes: []string{"M"}, Price: 10000},
+	)
+
+	result, err := index.Search(context.Background(), &Query{
+		Keyword: "kemeja",
+		Filter:  map[string]interface{}{"category_id": 3, "sizes": "M"},
+		Sort:    SortPriceAsc,
+		Limit:   10,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if ids := hitIDs(result); len(ids) != 2 || ids[0] != 2 || ids[1] != 1 {
+		t.Errorf("hits = %v, want [2 1]", ids)
+	}
+}
+
+func TestMemoryIndexSearchRejectsUnknownFilter(t *testing.T) {
+	index := newTestMemoryIndex(t, &entity.ProductSearchDocument{ID: 1, Name: "Kemeja"})
+
+	if _, err := index.Search(context.Background(), &Query{Keyword: "kemeja", Filter: map[string]interface{}{"brand": "x"}, Limit: 10}); err == nil {
+		t.Fatal("Search() error = nil, want an error for the unknown field")
+	}
+}
+
+func TestMemoryIndexDeleteIndexedBeforeKeepsNewerDocuments(t *testing.T) {
+	startedAt := time.Now()
+	index := newTestMemoryIndex(t,
+		&entity.ProductSearchDocument{ID: 1, Name: "Kemeja Lama", IndexedAt: startedAt.Add(-time.Minute).UnixMilli()},
+		&entity.ProductSearchDocument{ID: 2, Name: "Kemeja Baru", IndexedAt: startedAt.UnixMilli()},
+	)
+	ctx := context.Background()
+
+	if err := index.DeleteIndexedBefore(ctx, startedAt); err != nil {
+		t.Fatalf("DeleteIndexedBefore() error = %v", err)
+	}
+
+	result, err := index.Search(ctx, &Query{Keyword: "kemeja", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if ids := hitIDs(result); len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("hits = %v, want only the document written by the reindex", ids)
+	}
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// Search engines
+const (
+	EnginePostgres    = "postgres" // full-text search in the database, no external index
+	EngineMeilisearch = "meilisearch"
+	EngineMemory      = "memory"
+)
+
+// Sort options supported by every index, matching the database search
+const (
+	SortRelevance = "relevance"
+	SortNewest    = "newest"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortName      = "name"
+)
+
+// Query holds the parameters of a product search
+type Query struct {
+	Keyword string
+	Filter  map[string]interface{} // equality filters on document fields, e.g. category_id
+	Sort    string
+	Offset  int
+	Limit   int
+}
+
+// Hit is a product found by a search with its highlighted matches
+type Hit struct {
+	ProductID uint
+	Highlight *entity.SearchHighlight // nil when nothing is highlighted
+}
+
+// Result holds one page of search hits
+type Result struct {
+	Hits  []Hit
+	Total int64 // may be an estimate, and counts documents not yet removed from the index
+}
+
+// SearchIndex defines the operations every external search index supports
+type SearchIndex interface {
+	Name() string
+	Setup(ctx context.Context) error // creates the index and configures its searchable, filterable and sortable fields
+	Upsert(ctx context.Context, documents []*entity.ProductSearchDocument) error
+	Delete(ctx context.Context, productIDs []uint) error
+	DeleteIndexedBefore(ctx context.Context, before time.Time) error // removes documents a full reindex did not write
+	Search(ctx context.Context, query *Query) (*Result, error)
+}
+
+// NewSearchIndex creates the SearchIndex of an engine. The postgres engine
+// has no external index, so nil is returned for it.
+func NewSearchIndex(engine, meilisearchURL, meilisearchAPIKey, meilisearchIndex string) (SearchIndex, error) {
+	switch engine {
+	case EnginePostgres, "":
+		return nil, nil
+	case EngineMeilisearch:
+		if meilisearchURL == "" {
+			return nil, errors.New("meilisearch URL is not configured")
+		}
+		return NewMeilisearchIndex(meilisearchURL, meilisearchAPIKey, meilisearchIndex), nil
+	case EngineMemory:
+		return NewMemoryIndex(), nil
+	default:
+		return nil, fmt.Errorf("unknown search engine %q", engine)
+	}
+}
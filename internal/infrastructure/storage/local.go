@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// localStorage keeps files in a directory on the server
+type localStorage struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalStorage creates a Storage that writes files under root, served from baseURL
+func NewLocalStorage(root, baseURL string) Storage {
+	return &localStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Save writes a file, replacing any file with the same key
+func (s *localStorage) Save(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	filename, err := s.filename(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return "", err
+	}
+	return s.URL(key), nil
+}
+
+// Exists reports whether a file is stored under a key
+func (s *localStorage) Exists(ctx context.Context, key string) (bool, error) {
+	filename, err := s.filename(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(filename); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes a file, succeeding when it does not exist
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	filename, err := s.filename(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL returns the public URL of a key
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + strings.TrimPrefix(path.Clean("/"+key), "/")
+}
+
+// filename maps a key to a path under the root, rejecting keys that escape it
+func (s *localStorage) filename(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
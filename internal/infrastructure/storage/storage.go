@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// Storage types
+const (
+	TypeLocal = "local"
+)
+
+// Storage defines the operations every file store supports. Keys are
+// slash-separated paths such as images/ab/abcdef/large.jpg.
+type Storage interface {
+	Save(ctx context.Context, key string, data []byte, contentType string) (string, error) // returns the public URL of the file
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorage creates the Storage of a storage type
+func NewStorage(storageType, localPath, baseURL string) (Storage, error) {
+	switch storageType {
+	case TypeLocal, "":
+		return NewLocalStorage(localPath, baseURL), nil
+	default:
+		return nil, fmt.Errorf("storage type %q is not supported", storageType)
+	}
+}
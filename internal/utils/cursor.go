@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"fashion-shop/internal/domain/entity"
+)
+
+// cursorTimeLayout keeps the wall clock time of a cursor at database precision.
+// The zone is left out because created_at columns are stored without one.
+const cursorTimeLayout = "2006-01-02T15:04:05.000000"
+
+// ErrInvalidCursor is returned for cursors that were not issued by EncodeCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor encodes a cursor into an opaque string for clients
+func EncodeCursor(cursor *entity.Cursor) string {
+	raw := cursor.CreatedAt.Format(cursorTimeLayout) + "|" + strconv.FormatUint(uint64(cursor.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodes a cursor given by a client
+func DecodeCursor(encoded string) (*entity.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(cursorTimeLayout, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &entity.Cursor{CreatedAt: t, ID: uint(n)}, nil
+}
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payment_events_transaction_id;
+DROP INDEX IF EXISTS idx_payment_events_payment_id;
+DROP INDEX IF EXISTS idx_payment_events_order_number;
+
+-- Drop tables
+DROP TABLE IF EXISTS payment_events;
@@ -0,0 +1,24 @@
+-- Create payment_events table
+CREATE TABLE payment_events (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    order_number VARCHAR(50),
+    payment_id INTEGER REFERENCES payments(id),
+    transaction_id VARCHAR(100),
+    transaction_status VARCHAR(30),
+    fraud_status VARCHAR(20),
+    status_code VARCHAR(10),
+    gross_amount VARCHAR(30),
+    signature_valid BOOLEAN NOT NULL DEFAULT FALSE,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'received',
+    message TEXT,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_payment_events_order_number ON payment_events(order_number);
+CREATE INDEX idx_payment_events_payment_id ON payment_events(payment_id);
+CREATE INDEX idx_payment_events_transaction_id ON payment_events(transaction_id);
@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payments_status;
+DROP INDEX IF EXISTS idx_payment_refunds_payment_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS payment_refunds;
+
+-- Drop columns
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_amount;
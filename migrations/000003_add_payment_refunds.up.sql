@@ -0,0 +1,18 @@
+-- Track refunded totals on payments
+ALTER TABLE payments ADD COLUMN refunded_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Create payment_refunds table
+CREATE TABLE payment_refunds (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payments(id),
+    refund_key VARCHAR(100) NOT NULL UNIQUE,
+    amount DECIMAL(10, 2) NOT NULL,
+    reason TEXT,
+    source VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_payment_refunds_payment_id ON payment_refunds(payment_id);
+CREATE INDEX idx_payments_status ON payments(status);
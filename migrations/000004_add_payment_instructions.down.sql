@@ -0,0 +1,6 @@
+-- Drop columns
+ALTER TABLE payments DROP COLUMN IF EXISTS deeplink_url;
+ALTER TABLE payments DROP COLUMN IF EXISTS qr_code_url;
+ALTER TABLE payments DROP COLUMN IF EXISTS qr_string;
+ALTER TABLE payments DROP COLUMN IF EXISTS va_number;
+ALTER TABLE payments DROP COLUMN IF EXISTS channel;
@@ -0,0 +1,6 @@
+-- Store direct charge payment instructions on payments
+ALTER TABLE payments ADD COLUMN channel VARCHAR(20);
+ALTER TABLE payments ADD COLUMN va_number VARCHAR(50);
+ALTER TABLE payments ADD COLUMN qr_string TEXT;
+ALTER TABLE payments ADD COLUMN qr_code_url TEXT;
+ALTER TABLE payments ADD COLUMN deeplink_url TEXT;
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payments_provider;
+
+-- Drop columns
+ALTER TABLE payments DROP COLUMN IF EXISTS provider;
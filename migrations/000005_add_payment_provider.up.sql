@@ -0,0 +1,5 @@
+-- Record which payment gateway handles each payment
+ALTER TABLE payments ADD COLUMN provider VARCHAR(20) NOT NULL DEFAULT 'midtrans';
+
+-- Create indexes
+CREATE INDEX idx_payments_provider ON payments(provider);
@@ -0,0 +1,2 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payments_pending_expiry;
@@ -0,0 +1,2 @@
+-- Create indexes
+CREATE INDEX idx_payments_pending_expiry ON payments(expired_at) WHERE status = 'pending';
@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_orders_shipping_tracking_number;
+DROP INDEX IF EXISTS idx_cod_remittances_order_id;
+DROP INDEX IF EXISTS idx_cod_remittances_tracking_number;
+DROP INDEX IF EXISTS idx_cod_remittances_batch_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS cod_remittances;
+DROP TABLE IF EXISTS cod_remittance_batches;
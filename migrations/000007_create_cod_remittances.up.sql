@@ -0,0 +1,37 @@
+-- Create cod_remittance_batches table
+CREATE TABLE cod_remittance_batches (
+    id SERIAL PRIMARY KEY,
+    courier VARCHAR(20) NOT NULL,
+    filename VARCHAR(255),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    matched_rows INTEGER NOT NULL DEFAULT 0,
+    mismatched_rows INTEGER NOT NULL DEFAULT 0,
+    unmatched_rows INTEGER NOT NULL DEFAULT 0,
+    duplicate_rows INTEGER NOT NULL DEFAULT 0,
+    total_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    imported_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create cod_remittances table
+CREATE TABLE cod_remittances (
+    id SERIAL PRIMARY KEY,
+    batch_id INTEGER NOT NULL REFERENCES cod_remittance_batches(id) ON DELETE CASCADE,
+    tracking_number VARCHAR(100),
+    order_number VARCHAR(50),
+    amount DECIMAL(10, 2) NOT NULL,
+    remitted_at TIMESTAMP,
+    order_id INTEGER REFERENCES orders(id),
+    payment_id INTEGER REFERENCES payments(id),
+    status VARCHAR(20) NOT NULL,
+    message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_cod_remittances_batch_id ON cod_remittances(batch_id);
+CREATE INDEX idx_cod_remittances_tracking_number ON cod_remittances(tracking_number);
+CREATE INDEX idx_cod_remittances_order_id ON cod_remittances(order_id);
+CREATE INDEX idx_orders_shipping_tracking_number ON orders(shipping_tracking_number);
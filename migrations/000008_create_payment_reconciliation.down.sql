@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payments_paid_at;
+DROP INDEX IF EXISTS idx_reconciliation_items_payment_id;
+DROP INDEX IF EXISTS idx_reconciliation_items_run_id_status;
+
+-- Drop tables
+DROP TABLE IF EXISTS reconciliation_items;
+DROP TABLE IF EXISTS reconciliation_runs;
@@ -0,0 +1,40 @@
+-- Create reconciliation_runs table
+CREATE TABLE reconciliation_runs (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(20),
+    source VARCHAR(20) NOT NULL,
+    filename VARCHAR(255),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    total_items INTEGER NOT NULL DEFAULT 0,
+    matched_items INTEGER NOT NULL DEFAULT 0,
+    amount_mismatches INTEGER NOT NULL DEFAULT 0,
+    status_mismatches INTEGER NOT NULL DEFAULT 0,
+    missing_payments INTEGER NOT NULL DEFAULT 0,
+    orphan_transactions INTEGER NOT NULL DEFAULT 0,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create reconciliation_items table
+CREATE TABLE reconciliation_items (
+    id SERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    payment_id INTEGER REFERENCES payments(id),
+    order_number VARCHAR(50),
+    transaction_id VARCHAR(100),
+    local_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    gateway_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    local_status VARCHAR(20),
+    gateway_status VARCHAR(30),
+    status VARCHAR(20) NOT NULL,
+    message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_reconciliation_items_run_id_status ON reconciliation_items(run_id, status);
+CREATE INDEX idx_reconciliation_items_payment_id ON reconciliation_items(payment_id);
+CREATE INDEX idx_payments_paid_at ON payments(paid_at);
@@ -0,0 +1,12 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_return_photos_return_request_id;
+DROP INDEX IF EXISTS idx_return_items_order_item_id;
+DROP INDEX IF EXISTS idx_return_items_return_request_id;
+DROP INDEX IF EXISTS idx_return_requests_status;
+DROP INDEX IF EXISTS idx_return_requests_user_id;
+DROP INDEX IF EXISTS idx_return_requests_order_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS return_photos;
+DROP TABLE IF EXISTS return_items;
+DROP TABLE IF EXISTS return_requests;
@@ -0,0 +1,49 @@
+-- Create return_requests table
+CREATE TABLE return_requests (
+    id SERIAL PRIMARY KEY,
+    rma_number VARCHAR(60) NOT NULL UNIQUE,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    reason VARCHAR(20) NOT NULL,
+    description TEXT,
+    refund_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    return_courier VARCHAR(50),
+    return_tracking_number VARCHAR(100),
+    admin_note TEXT,
+    approved_at TIMESTAMP,
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    refunded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create return_items table
+CREATE TABLE return_items (
+    id SERIAL PRIMARY KEY,
+    return_request_id INTEGER NOT NULL REFERENCES return_requests(id) ON DELETE CASCADE,
+    order_item_id INTEGER NOT NULL REFERENCES order_items(id),
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id),
+    product_name VARCHAR(255),
+    quantity INTEGER NOT NULL,
+    refund_amount DECIMAL(12, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create return_photos table
+CREATE TABLE return_photos (
+    id SERIAL PRIMARY KEY,
+    return_request_id INTEGER NOT NULL REFERENCES return_requests(id) ON DELETE CASCADE,
+    url VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_return_requests_order_id ON return_requests(order_id);
+CREATE INDEX idx_return_requests_user_id ON return_requests(user_id);
+CREATE INDEX idx_return_requests_status ON return_requests(status);
+CREATE INDEX idx_return_items_return_request_id ON return_items(return_request_id);
+CREATE INDEX idx_return_items_order_item_id ON return_items(order_item_id);
+CREATE INDEX idx_return_photos_return_request_id ON return_photos(return_request_id);
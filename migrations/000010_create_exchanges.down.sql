@@ -0,0 +1,14 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_orders_original_order_id;
+DROP INDEX IF EXISTS idx_exchange_items_order_item_id;
+DROP INDEX IF EXISTS idx_exchange_items_exchange_request_id;
+DROP INDEX IF EXISTS idx_exchange_requests_status;
+DROP INDEX IF EXISTS idx_exchange_requests_user_id;
+DROP INDEX IF EXISTS idx_exchange_requests_order_id;
+
+-- Drop replacement order link
+ALTER TABLE orders DROP COLUMN IF EXISTS original_order_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS exchange_items;
+DROP TABLE IF EXISTS exchange_requests;
@@ -0,0 +1,50 @@
+-- Create exchange_requests table
+CREATE TABLE exchange_requests (
+    id SERIAL PRIMARY KEY,
+    exchange_number VARCHAR(60) NOT NULL UNIQUE,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    replacement_order_id INTEGER REFERENCES orders(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    reason VARCHAR(20) NOT NULL,
+    description TEXT,
+    price_difference DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    refunded_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    return_courier VARCHAR(50),
+    return_tracking_number VARCHAR(100),
+    admin_note TEXT,
+    approved_at TIMESTAMP,
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create exchange_items table
+CREATE TABLE exchange_items (
+    id SERIAL PRIMARY KEY,
+    exchange_request_id INTEGER NOT NULL REFERENCES exchange_requests(id) ON DELETE CASCADE,
+    order_item_id INTEGER NOT NULL REFERENCES order_items(id),
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    product_name VARCHAR(255),
+    old_variant_id INTEGER NOT NULL REFERENCES product_variants(id),
+    new_variant_id INTEGER NOT NULL REFERENCES product_variants(id),
+    new_variant_info TEXT,
+    quantity INTEGER NOT NULL,
+    paid_unit_price DECIMAL(10, 2) NOT NULL,
+    new_unit_price DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Link replacement orders to the order they replace
+ALTER TABLE orders ADD COLUMN original_order_id INTEGER REFERENCES orders(id);
+
+-- Create indexes
+CREATE INDEX idx_exchange_requests_order_id ON exchange_requests(order_id);
+CREATE INDEX idx_exchange_requests_user_id ON exchange_requests(user_id);
+CREATE INDEX idx_exchange_requests_status ON exchange_requests(status);
+CREATE INDEX idx_exchange_items_exchange_request_id ON exchange_items(exchange_request_id);
+CREATE INDEX idx_exchange_items_order_item_id ON exchange_items(order_item_id);
+CREATE INDEX idx_orders_original_order_id ON orders(original_order_id);
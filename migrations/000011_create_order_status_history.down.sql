@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_order_status_history_order_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS order_status_history;
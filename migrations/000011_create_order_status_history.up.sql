@@ -0,0 +1,19 @@
+-- Create order_status_history table
+CREATE TABLE order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id INTEGER REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_order_status_history_order_id ON order_status_history(order_id, created_at);
+
+-- Seed the current status of existing orders
+INSERT INTO order_status_history (order_id, to_status, actor_type, note, created_at)
+SELECT id, status, 'system', 'Status before history was recorded', updated_at
+FROM orders;
@@ -0,0 +1,15 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_voucher_redemptions_user_id;
+DROP INDEX IF EXISTS idx_voucher_redemptions_voucher_user;
+DROP INDEX IF EXISTS idx_vouchers_deleted_at;
+DROP INDEX IF EXISTS idx_vouchers_code;
+
+-- Drop voucher columns
+ALTER TABLE orders DROP COLUMN IF EXISTS voucher_code;
+ALTER TABLE carts DROP COLUMN IF EXISTS voucher_code;
+
+-- Drop tables
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS voucher_categories;
+DROP TABLE IF EXISTS voucher_products;
+DROP TABLE IF EXISTS vouchers;
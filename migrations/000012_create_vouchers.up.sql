@@ -0,0 +1,56 @@
+-- Create vouchers table
+CREATE TABLE vouchers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    type VARCHAR(20) NOT NULL,
+    value DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    min_spend DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    max_discount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    usage_limit INTEGER NOT NULL DEFAULT 0,
+    usage_limit_per_user INTEGER NOT NULL DEFAULT 0,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Create voucher_products table
+CREATE TABLE voucher_products (
+    voucher_id INTEGER NOT NULL REFERENCES vouchers(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    PRIMARY KEY (voucher_id, product_id)
+);
+
+-- Create voucher_categories table
+CREATE TABLE voucher_categories (
+    voucher_id INTEGER NOT NULL REFERENCES vouchers(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id),
+    PRIMARY KEY (voucher_id, category_id)
+);
+
+-- Create voucher_redemptions table
+CREATE TABLE voucher_redemptions (
+    id SERIAL PRIMARY KEY,
+    voucher_id INTEGER NOT NULL REFERENCES vouchers(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    order_id INTEGER NOT NULL UNIQUE REFERENCES orders(id),
+    discount_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    shipping_discount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    released_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Remember the voucher applied to carts and orders
+ALTER TABLE carts ADD COLUMN voucher_code VARCHAR(50);
+ALTER TABLE orders ADD COLUMN voucher_code VARCHAR(50);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_vouchers_code ON vouchers(code) WHERE deleted_at IS NULL;
+CREATE INDEX idx_vouchers_deleted_at ON vouchers(deleted_at);
+CREATE INDEX idx_voucher_redemptions_voucher_user ON voucher_redemptions(voucher_id, user_id);
+CREATE INDEX idx_voucher_redemptions_user_id ON voucher_redemptions(user_id);
@@ -0,0 +1,12 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_flash_sale_purchases_order_id;
+DROP INDEX IF EXISTS idx_flash_sale_purchases_item_user;
+DROP INDEX IF EXISTS idx_flash_sale_items_variant_id;
+DROP INDEX IF EXISTS idx_flash_sale_items_product_id;
+DROP INDEX IF EXISTS idx_flash_sales_ends_at;
+DROP INDEX IF EXISTS idx_flash_sales_status_starts_at;
+
+-- Drop tables
+DROP TABLE IF EXISTS flash_sale_purchases;
+DROP TABLE IF EXISTS flash_sale_items;
+DROP TABLE IF EXISTS flash_sales;
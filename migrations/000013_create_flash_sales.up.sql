@@ -0,0 +1,45 @@
+-- Create flash_sales table
+CREATE TABLE flash_sales (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create flash_sale_items table
+CREATE TABLE flash_sale_items (
+    id SERIAL PRIMARY KEY,
+    flash_sale_id INTEGER NOT NULL REFERENCES flash_sales(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id),
+    sale_price DECIMAL(10, 2) NOT NULL,
+    quota INTEGER NOT NULL,
+    sold INTEGER NOT NULL DEFAULT 0,
+    per_user_limit INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (flash_sale_id, variant_id)
+);
+
+-- Create flash_sale_purchases table
+CREATE TABLE flash_sale_purchases (
+    id SERIAL PRIMARY KEY,
+    flash_sale_item_id INTEGER NOT NULL REFERENCES flash_sale_items(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    quantity INTEGER NOT NULL,
+    released_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_flash_sales_status_starts_at ON flash_sales(status, starts_at);
+CREATE INDEX idx_flash_sales_ends_at ON flash_sales(ends_at);
+CREATE INDEX idx_flash_sale_items_product_id ON flash_sale_items(product_id);
+CREATE INDEX idx_flash_sale_items_variant_id ON flash_sale_items(variant_id);
+CREATE INDEX idx_flash_sale_purchases_item_user ON flash_sale_purchases(flash_sale_item_id, user_id);
+CREATE INDEX idx_flash_sale_purchases_order_id ON flash_sale_purchases(order_id);
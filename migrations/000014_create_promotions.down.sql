@@ -0,0 +1,15 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_order_promotions_promotion_id;
+DROP INDEX IF EXISTS idx_order_promotions_order_id;
+DROP INDEX IF EXISTS idx_promotion_tiers_promotion_id;
+DROP INDEX IF EXISTS idx_promotion_bundle_items_promotion_id;
+DROP INDEX IF EXISTS idx_promotions_deleted_at;
+DROP INDEX IF EXISTS idx_promotions_active_window;
+
+-- Drop tables
+DROP TABLE IF EXISTS order_promotions;
+DROP TABLE IF EXISTS promotion_categories;
+DROP TABLE IF EXISTS promotion_products;
+DROP TABLE IF EXISTS promotion_tiers;
+DROP TABLE IF EXISTS promotion_bundle_items;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,68 @@
+-- Create promotions table
+CREATE TABLE promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    type VARCHAR(20) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    get_discount_percent DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    bundle_price DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Create promotion_bundle_items table
+CREATE TABLE promotion_bundle_items (
+    id SERIAL PRIMARY KEY,
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id),
+    category_id INTEGER REFERENCES categories(id),
+    quantity INTEGER NOT NULL
+);
+
+-- Create promotion_tiers table
+CREATE TABLE promotion_tiers (
+    id SERIAL PRIMARY KEY,
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    min_spend DECIMAL(12, 2) NOT NULL,
+    discount_percent DECIMAL(5, 2) NOT NULL,
+    max_discount DECIMAL(12, 2) NOT NULL DEFAULT 0
+);
+
+-- Create promotion_products table
+CREATE TABLE promotion_products (
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    PRIMARY KEY (promotion_id, product_id)
+);
+
+-- Create promotion_categories table
+CREATE TABLE promotion_categories (
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id),
+    PRIMARY KEY (promotion_id, category_id)
+);
+
+-- Create order_promotions table
+CREATE TABLE order_promotions (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id),
+    name VARCHAR(255) NOT NULL,
+    discount_amount DECIMAL(12, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_promotions_active_window ON promotions(is_active, starts_at, ends_at);
+CREATE INDEX idx_promotions_deleted_at ON promotions(deleted_at);
+CREATE INDEX idx_promotion_bundle_items_promotion_id ON promotion_bundle_items(promotion_id);
+CREATE INDEX idx_promotion_tiers_promotion_id ON promotion_tiers(promotion_id);
+CREATE INDEX idx_order_promotions_order_id ON order_promotions(order_id);
+CREATE INDEX idx_order_promotions_promotion_id ON order_promotions(promotion_id);
@@ -0,0 +1,13 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_loyalty_transactions_order_type;
+DROP INDEX IF EXISTS idx_loyalty_transactions_expiring;
+DROP INDEX IF EXISTS idx_loyalty_transactions_order_id;
+DROP INDEX IF EXISTS idx_loyalty_transactions_user_id;
+
+-- Drop loyalty columns
+ALTER TABLE orders DROP COLUMN IF EXISTS loyalty_points_redeemed;
+
+-- Drop tables
+DROP TABLE IF EXISTS loyalty_category_multipliers;
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_accounts;
@@ -0,0 +1,46 @@
+-- Create loyalty_accounts table
+CREATE TABLE loyalty_accounts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL UNIQUE REFERENCES users(id),
+    balance INTEGER NOT NULL DEFAULT 0,
+    lifetime_earned INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create loyalty_transactions table
+CREATE TABLE loyalty_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    order_id INTEGER REFERENCES orders(id),
+    type VARCHAR(20) NOT NULL,
+    points INTEGER NOT NULL,
+    remaining INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    description VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create loyalty_category_multipliers table
+CREATE TABLE loyalty_category_multipliers (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL UNIQUE REFERENCES categories(id) ON DELETE CASCADE,
+    multiplier DECIMAL(6, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Remember the points spent on orders
+ALTER TABLE orders ADD COLUMN loyalty_points_redeemed INTEGER NOT NULL DEFAULT 0;
+
+-- Orders delivered before the program started do not earn points
+INSERT INTO loyalty_transactions (user_id, order_id, type, points, description)
+SELECT user_id, id, 'earn', 0, 'Delivered before the loyalty program started'
+FROM orders
+WHERE status = 'delivered';
+
+-- Create indexes
+CREATE INDEX idx_loyalty_transactions_user_id ON loyalty_transactions(user_id, created_at);
+CREATE INDEX idx_loyalty_transactions_order_id ON loyalty_transactions(order_id);
+CREATE INDEX idx_loyalty_transactions_expiring ON loyalty_transactions(expires_at) WHERE remaining > 0;
+CREATE UNIQUE INDEX idx_loyalty_transactions_order_type ON loyalty_transactions(order_id, type) WHERE type IN ('earn', 'redeem', 'restore');
@@ -0,0 +1,16 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_wallet_transactions_order_id;
+DROP INDEX IF EXISTS idx_wallet_transactions_user_id;
+DROP INDEX IF EXISTS idx_gift_cards_order_id;
+DROP INDEX IF EXISTS idx_gift_cards_purchaser_id;
+DROP INDEX IF EXISTS idx_gift_cards_status;
+
+-- Drop split tender columns
+ALTER TABLE payments DROP COLUMN IF EXISTS wallet_refunded;
+ALTER TABLE payments DROP COLUMN IF EXISTS wallet_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS wallet_amount;
+
+-- Drop tables
+DROP TABLE IF EXISTS wallet_transactions;
+DROP TABLE IF EXISTS gift_cards;
+DROP TABLE IF EXISTS wallets;
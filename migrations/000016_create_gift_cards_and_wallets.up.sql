@@ -0,0 +1,53 @@
+-- Create wallets table
+CREATE TABLE wallets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL UNIQUE REFERENCES users(id),
+    balance DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create gift_cards table
+CREATE TABLE gift_cards (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    amount DECIMAL(12, 2) NOT NULL,
+    balance DECIMAL(12, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    purchaser_id INTEGER REFERENCES users(id),
+    order_id INTEGER REFERENCES orders(id),
+    recipient_name VARCHAR(100),
+    recipient_email VARCHAR(255),
+    message TEXT,
+    redeemed_by INTEGER REFERENCES users(id),
+    redeemed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create wallet_transactions table
+CREATE TABLE wallet_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL,
+    balance_after DECIMAL(12, 2) NOT NULL,
+    order_id INTEGER REFERENCES orders(id),
+    gift_card_id INTEGER REFERENCES gift_cards(id),
+    reference VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Split tender: the part of an order paid with store credit
+ALTER TABLE orders ADD COLUMN wallet_amount DECIMAL(12, 2) NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN wallet_amount DECIMAL(12, 2) NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN wallet_refunded DECIMAL(12, 2) NOT NULL DEFAULT 0;
+
+-- Create indexes
+CREATE INDEX idx_gift_cards_status ON gift_cards(status);
+CREATE INDEX idx_gift_cards_purchaser_id ON gift_cards(purchaser_id);
+CREATE INDEX idx_gift_cards_order_id ON gift_cards(order_id);
+CREATE INDEX idx_wallet_transactions_user_id ON wallet_transactions(user_id, created_at);
+CREATE INDEX idx_wallet_transactions_order_id ON wallet_transactions(order_id);
@@ -0,0 +1,21 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_tags_name_trgm;
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS categories_search_vector_refresh ON categories;
+DROP TRIGGER IF EXISTS tags_search_vector_refresh ON tags;
+DROP TRIGGER IF EXISTS product_tags_search_vector_refresh ON product_tags;
+DROP TRIGGER IF EXISTS products_search_vector_refresh ON products;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS categories_search_vector_refresh();
+DROP FUNCTION IF EXISTS tags_search_vector_refresh();
+DROP FUNCTION IF EXISTS product_tags_search_vector_refresh();
+DROP FUNCTION IF EXISTS products_search_vector_refresh();
+DROP FUNCTION IF EXISTS product_search_vector(INTEGER, TEXT, TEXT, INTEGER);
+
+-- Drop search columns
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
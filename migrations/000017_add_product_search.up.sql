@@ -0,0 +1,88 @@
+-- Enable trigram matching for typo tolerant search and suggestions
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Add the full-text search document of products
+ALTER TABLE products ADD COLUMN search_vector tsvector;
+
+-- Build the search document of a product. Name and description are stemmed
+-- with the Indonesian dictionary; the name, tags and category are also kept
+-- as-is with the simple dictionary so brand names and English terms match.
+CREATE OR REPLACE FUNCTION product_search_vector(p_id INTEGER, p_name TEXT, p_description TEXT, p_category_id INTEGER)
+RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('indonesian', coalesce(p_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(p_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce((
+            SELECT string_agg(t.name, ' ')
+            FROM product_tags pt
+            JOIN tags t ON t.id = pt.tag_id
+            WHERE pt.product_id = p_id
+        ), '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce((
+            SELECT c.name FROM categories c WHERE c.id = p_category_id
+        ), '')), 'B') ||
+        setweight(to_tsvector('indonesian', coalesce(p_description, '')), 'C')
+$$ LANGUAGE sql STABLE;
+
+-- Keep the search document up to date when a product changes
+CREATE OR REPLACE FUNCTION products_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := product_search_vector(NEW.id, NEW.name, NEW.description, NEW.category_id);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_search_vector_refresh
+    BEFORE INSERT OR UPDATE OF name, description, category_id ON products
+    FOR EACH ROW EXECUTE FUNCTION products_search_vector_refresh();
+
+-- ... when tags are added to or removed from a product
+CREATE OR REPLACE FUNCTION product_tags_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products
+    SET search_vector = product_search_vector(id, name, description, category_id)
+    WHERE id = CASE WHEN TG_OP = 'DELETE' THEN OLD.product_id ELSE NEW.product_id END;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER product_tags_search_vector_refresh
+    AFTER INSERT OR DELETE ON product_tags
+    FOR EACH ROW EXECUTE FUNCTION product_tags_search_vector_refresh();
+
+-- ... when a tag is renamed
+CREATE OR REPLACE FUNCTION tags_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products
+    SET search_vector = product_search_vector(id, name, description, category_id)
+    WHERE id IN (SELECT product_id FROM product_tags WHERE tag_id = NEW.id);
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tags_search_vector_refresh
+    AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION tags_search_vector_refresh();
+
+-- ... and when a category is renamed
+CREATE OR REPLACE FUNCTION categories_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products
+    SET search_vector = product_search_vector(id, name, description, category_id)
+    WHERE category_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_search_vector_refresh
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION categories_search_vector_refresh();
+
+-- Index the existing catalog
+UPDATE products SET search_vector = product_search_vector(id, name, description, category_id);
+
+-- Create indexes
+CREATE INDEX idx_products_search_vector ON products USING GIN (search_vector);
+CREATE INDEX idx_products_name_trgm ON products USING GIN (lower(name) gin_trgm_ops);
+CREATE INDEX idx_categories_name_trgm ON categories USING GIN (lower(name) gin_trgm_ops);
+CREATE INDEX idx_tags_name_trgm ON tags USING GIN (lower(name) gin_trgm_ops);
@@ -0,0 +1,14 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS categories_search_index_change ON categories;
+DROP TRIGGER IF EXISTS product_tags_search_index_change ON product_tags;
+DROP TRIGGER IF EXISTS product_variants_search_index_change ON product_variants;
+DROP TRIGGER IF EXISTS products_search_index_change ON products;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS categories_search_index_change();
+DROP FUNCTION IF EXISTS product_children_search_index_change();
+DROP FUNCTION IF EXISTS products_search_index_change();
+DROP FUNCTION IF EXISTS record_search_index_change(INTEGER);
+
+-- Drop tables
+DROP TABLE IF EXISTS search_index_changes;
@@ -0,0 +1,62 @@
+-- Create search index changes table: products whose search document must be
+-- sent to the external search index. One row per product, so it stays small
+-- when no external index consumes it.
+CREATE TABLE search_index_changes (
+    product_id INTEGER PRIMARY KEY,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Record a change of a product
+CREATE OR REPLACE FUNCTION record_search_index_change(p_product_id INTEGER) RETURNS void AS $$
+    INSERT INTO search_index_changes (product_id, changed_at)
+    VALUES (p_product_id, clock_timestamp())
+    ON CONFLICT (product_id) DO UPDATE SET changed_at = EXCLUDED.changed_at
+$$ LANGUAGE sql;
+
+-- ... when a product is created, updated or deleted
+CREATE OR REPLACE FUNCTION products_search_index_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM record_search_index_change(CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END);
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_search_index_change
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION products_search_index_change();
+
+-- ... when a variant or its stock changes, or the product's tags change
+CREATE OR REPLACE FUNCTION product_children_search_index_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM record_search_index_change(OLD.product_id);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        PERFORM record_search_index_change(NEW.product_id);
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER product_variants_search_index_change
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION product_children_search_index_change();
+
+CREATE TRIGGER product_tags_search_index_change
+    AFTER INSERT OR DELETE ON product_tags
+    FOR EACH ROW EXECUTE FUNCTION product_children_search_index_change();
+
+-- ... and when a category is renamed
+CREATE OR REPLACE FUNCTION categories_search_index_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM record_search_index_change(id) FROM products WHERE category_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_search_index_change
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION categories_search_index_change();
+
+-- Create indexes
+CREATE INDEX idx_search_index_changes_changed_at ON search_index_changes(changed_at);
@@ -0,0 +1,15 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_cod_remittance_batches_cursor;
+DROP INDEX IF EXISTS idx_reconciliation_runs_cursor;
+DROP INDEX IF EXISTS idx_gift_cards_cursor;
+DROP INDEX IF EXISTS idx_vouchers_cursor;
+DROP INDEX IF EXISTS idx_exchange_requests_cursor;
+DROP INDEX IF EXISTS idx_return_requests_cursor;
+DROP INDEX IF EXISTS idx_payments_cursor;
+DROP INDEX IF EXISTS idx_users_cursor;
+DROP INDEX IF EXISTS idx_products_cursor;
+DROP INDEX IF EXISTS idx_reviews_user_cursor;
+DROP INDEX IF EXISTS idx_reviews_product_cursor;
+DROP INDEX IF EXISTS idx_notifications_user_cursor;
+DROP INDEX IF EXISTS idx_orders_cursor;
+DROP INDEX IF EXISTS idx_orders_user_cursor;
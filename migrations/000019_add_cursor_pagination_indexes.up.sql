@@ -0,0 +1,15 @@
+-- Create indexes backing keyset pagination on (created_at, id), newest first
+CREATE INDEX idx_orders_user_cursor ON orders(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_orders_cursor ON orders(created_at DESC, id DESC);
+CREATE INDEX idx_notifications_user_cursor ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_reviews_product_cursor ON reviews(product_id, created_at DESC, id DESC);
+CREATE INDEX idx_reviews_user_cursor ON reviews(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_products_cursor ON products(created_at DESC, id DESC);
+CREATE INDEX idx_users_cursor ON users(created_at DESC, id DESC);
+CREATE INDEX idx_payments_cursor ON payments(created_at DESC, id DESC);
+CREATE INDEX idx_return_requests_cursor ON return_requests(created_at DESC, id DESC);
+CREATE INDEX idx_exchange_requests_cursor ON exchange_requests(created_at DESC, id DESC);
+CREATE INDEX idx_vouchers_cursor ON vouchers(created_at DESC, id DESC);
+CREATE INDEX idx_gift_cards_cursor ON gift_cards(created_at DESC, id DESC);
+CREATE INDEX idx_reconciliation_runs_cursor ON reconciliation_runs(created_at DESC, id DESC);
+CREATE INDEX idx_cod_remittance_batches_cursor ON cod_remittance_batches(created_at DESC, id DESC);
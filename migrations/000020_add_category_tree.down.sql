@@ -0,0 +1,14 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_categories_parent_sort;
+DROP INDEX IF EXISTS idx_categories_path;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS categories_maintain_path ON categories;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS categories_maintain_path();
+
+-- Drop tree columns
+ALTER TABLE categories DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE categories DROP COLUMN IF EXISTS depth;
+ALTER TABLE categories DROP COLUMN IF EXISTS path;
@@ -0,0 +1,67 @@
+-- Add the materialised path of each category (ancestor IDs such as /1/4/9/),
+-- its depth and its position among its siblings
+ALTER TABLE categories ADD COLUMN path TEXT NOT NULL DEFAULT '';
+ALTER TABLE categories ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE categories ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Fill the paths of existing categories
+WITH RECURSIVE tree AS (
+    SELECT id, '/' || id || '/' AS path, 0 AS depth
+    FROM categories
+    WHERE parent_id IS NULL
+    UNION ALL
+    SELECT c.id, tree.path || c.id || '/', tree.depth + 1
+    FROM categories c
+    JOIN tree ON c.parent_id = tree.id
+)
+UPDATE categories SET path = tree.path, depth = tree.depth
+FROM tree
+WHERE categories.id = tree.id;
+
+-- Order existing siblings by name
+UPDATE categories SET sort_order = ranked.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY name) - 1 AS position
+    FROM categories
+) AS ranked
+WHERE categories.id = ranked.id;
+
+-- Keep the path up to date when a category is created or moved, rewriting
+-- the paths of its subtree on a move. Moving a category under itself or one
+-- of its subcategories is refused.
+CREATE OR REPLACE FUNCTION categories_maintain_path() RETURNS trigger AS $$
+DECLARE
+    parent_path TEXT := '/';
+    parent_depth INTEGER := -1;
+BEGIN
+    IF NEW.parent_id IS NOT NULL THEN
+        SELECT path, depth INTO parent_path, parent_depth FROM categories WHERE id = NEW.parent_id;
+        IF NOT FOUND THEN
+            RAISE EXCEPTION 'parent category % does not exist', NEW.parent_id USING ERRCODE = 'foreign_key_violation';
+        END IF;
+        IF TG_OP = 'UPDATE' AND parent_path LIKE OLD.path || '%' THEN
+            RAISE EXCEPTION 'category % cannot be moved under its own subtree', NEW.id USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+
+    NEW.path := parent_path || NEW.id || '/';
+    NEW.depth := parent_depth + 1;
+
+    IF TG_OP = 'UPDATE' AND NEW.path <> OLD.path THEN
+        UPDATE categories
+        SET path = NEW.path || substr(path, length(OLD.path) + 1),
+            depth = depth + (NEW.depth - OLD.depth)
+        WHERE path LIKE OLD.path || '_%';
+    END IF;
+
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_maintain_path
+    BEFORE INSERT OR UPDATE OF parent_id ON categories
+    FOR EACH ROW EXECUTE FUNCTION categories_maintain_path();
+
+-- Create indexes
+CREATE INDEX idx_categories_path ON categories(path text_pattern_ops);
+CREATE INDEX idx_categories_parent_sort ON categories(parent_id, sort_order);
@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_products_attributes;
+DROP INDEX IF EXISTS idx_attribute_definitions_filterable;
+
+-- Drop product attribute values
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+
+-- Drop tables
+DROP TABLE IF EXISTS attribute_definitions;
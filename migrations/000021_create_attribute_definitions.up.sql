@@ -0,0 +1,24 @@
+-- Create attribute_definitions table. A category's attributes also apply to
+-- its subcategories, so a code is unique along every path of the tree.
+CREATE TABLE attribute_definitions (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    allowed_values JSONB NOT NULL DEFAULT '[]',
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    filterable BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (category_id, code)
+);
+
+-- Store the attribute values of products keyed by attribute code
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Create indexes
+CREATE INDEX idx_attribute_definitions_filterable ON attribute_definitions(category_id) WHERE filterable;
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes);
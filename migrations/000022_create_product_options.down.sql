@@ -0,0 +1,13 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_variant_options_option_value_id;
+DROP INDEX IF EXISTS idx_product_option_values_option_value;
+DROP INDEX IF EXISTS idx_product_options_product_name;
+
+-- Drop columns
+ALTER TABLE products DROP COLUMN IF EXISTS sku_template;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS is_active;
+
+-- Drop tables
+DROP TABLE IF EXISTS product_variant_options;
+DROP TABLE IF EXISTS product_option_values;
+DROP TABLE IF EXISTS product_options;
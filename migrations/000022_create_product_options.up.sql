@@ -0,0 +1,68 @@
+-- Create product_options table
+CREATE TABLE product_options (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create product_option_values table
+CREATE TABLE product_option_values (
+    id SERIAL PRIMARY KEY,
+    option_id INTEGER NOT NULL REFERENCES product_options(id) ON DELETE CASCADE,
+    value VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+-- Create product_variant_options table linking each variant to its option values
+CREATE TABLE product_variant_options (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    option_value_id INTEGER NOT NULL REFERENCES product_option_values(id) ON DELETE CASCADE,
+    PRIMARY KEY (variant_id, option_value_id)
+);
+
+-- Variants removed from the option matrix but referenced by orders are kept inactive
+ALTER TABLE product_variants ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Remember the SKU pattern variants were generated with
+ALTER TABLE products ADD COLUMN sku_template VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Turn the sizes and colors of existing variants into Size and Color options
+INSERT INTO product_options (product_id, name, position)
+SELECT DISTINCT product_id, 'Size', 0 FROM product_variants WHERE COALESCE(size, '') <> '';
+
+INSERT INTO product_options (product_id, name, position)
+SELECT DISTINCT product_id, 'Color', 1 FROM product_variants WHERE COALESCE(color, '') <> '';
+
+INSERT INTO product_option_values (option_id, value, position)
+SELECT o.id, MIN(v.size), ROW_NUMBER() OVER (PARTITION BY o.id ORDER BY MIN(v.id)) - 1
+FROM product_variants v
+JOIN product_options o ON o.product_id = v.product_id AND o.name = 'Size'
+WHERE COALESCE(v.size, '') <> ''
+GROUP BY o.id, lower(v.size);
+
+INSERT INTO product_option_values (option_id, value, position)
+SELECT o.id, MIN(v.color), ROW_NUMBER() OVER (PARTITION BY o.id ORDER BY MIN(v.id)) - 1
+FROM product_variants v
+JOIN product_options o ON o.product_id = v.product_id AND o.name = 'Color'
+WHERE COALESCE(v.color, '') <> ''
+GROUP BY o.id, lower(v.color);
+
+INSERT INTO product_variant_options (variant_id, option_value_id)
+SELECT v.id, ov.id
+FROM product_variants v
+JOIN product_options o ON o.product_id = v.product_id AND o.name = 'Size'
+JOIN product_option_values ov ON ov.option_id = o.id AND lower(ov.value) = lower(v.size);
+
+INSERT INTO product_variant_options (variant_id, option_value_id)
+SELECT v.id, ov.id
+FROM product_variants v
+JOIN product_options o ON o.product_id = v.product_id AND o.name = 'Color'
+JOIN product_option_values ov ON ov.option_id = o.id AND lower(ov.value) = lower(v.color);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_product_options_product_name ON product_options(product_id, lower(name));
+CREATE UNIQUE INDEX idx_product_option_values_option_value ON product_option_values(option_id, lower(value));
+CREATE INDEX idx_product_variant_options_option_value_id ON product_variant_options(option_value_id);
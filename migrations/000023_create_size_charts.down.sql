@@ -0,0 +1,12 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_reviews_fit;
+DROP INDEX IF EXISTS idx_return_requests_fit_reason;
+DROP INDEX IF EXISTS idx_size_chart_entries_size_chart_id;
+
+-- Drop review fit feedback
+ALTER TABLE reviews DROP COLUMN IF EXISTS fit;
+
+-- Drop tables
+DROP TABLE IF EXISTS body_profiles;
+DROP TABLE IF EXISTS size_chart_entries;
+DROP TABLE IF EXISTS size_charts;
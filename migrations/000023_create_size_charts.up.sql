@@ -0,0 +1,42 @@
+-- Create size_charts table, belonging to either a product or a category
+CREATE TABLE size_charts (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    product_id INTEGER UNIQUE REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER UNIQUE REFERENCES categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((product_id IS NULL) <> (category_id IS NULL))
+);
+
+-- Create size_chart_entries table
+CREATE TABLE size_chart_entries (
+    id SERIAL PRIMARY KEY,
+    size_chart_id INTEGER NOT NULL REFERENCES size_charts(id) ON DELETE CASCADE,
+    size VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    measurements JSONB NOT NULL DEFAULT '{}'
+);
+
+-- Create body_profiles table
+CREATE TABLE body_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    height DECIMAL(5, 1),
+    chest DECIMAL(5, 1),
+    waist DECIMAL(5, 1),
+    hips DECIMAL(5, 1),
+    inseam DECIMAL(5, 1),
+    foot_length DECIMAL(5, 1),
+    weight DECIMAL(5, 1),
+    fit_preference VARCHAR(20) NOT NULL DEFAULT 'regular',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Remember how the size fit reviewers
+ALTER TABLE reviews ADD COLUMN fit VARCHAR(20);
+
+-- Create indexes
+CREATE INDEX idx_size_chart_entries_size_chart_id ON size_chart_entries(size_chart_id, position);
+CREATE INDEX idx_return_requests_fit_reason ON return_requests(reason) WHERE reason IN ('too_small', 'too_large');
+CREATE INDEX idx_reviews_fit ON reviews(product_id) WHERE fit IS NOT NULL;
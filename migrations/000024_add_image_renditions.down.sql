@@ -0,0 +1,17 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_images_product_content_hash;
+DROP INDEX IF EXISTS idx_product_images_content_hash;
+
+-- Drop category image renditions
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS image_src_set,
+    DROP COLUMN IF EXISTS image_renditions,
+    DROP COLUMN IF EXISTS image_hash;
+
+-- Drop product image renditions
+ALTER TABLE product_images
+    DROP COLUMN IF EXISTS src_set,
+    DROP COLUMN IF EXISTS renditions,
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width,
+    DROP COLUMN IF EXISTS content_hash;
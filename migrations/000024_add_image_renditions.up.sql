@@ -0,0 +1,17 @@
+-- Store the processed renditions of product images
+ALTER TABLE product_images
+    ADD COLUMN content_hash VARCHAR(64),
+    ADD COLUMN width INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN height INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN renditions JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN src_set JSONB NOT NULL DEFAULT '{}';
+
+-- Store the processed renditions of category images
+ALTER TABLE categories
+    ADD COLUMN image_hash VARCHAR(64),
+    ADD COLUMN image_renditions JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN image_src_set JSONB NOT NULL DEFAULT '{}';
+
+-- Create indexes
+CREATE INDEX idx_product_images_content_hash ON product_images(content_hash) WHERE content_hash IS NOT NULL;
+CREATE UNIQUE INDEX idx_product_images_product_content_hash ON product_images(product_id, content_hash) WHERE content_hash IS NOT NULL;
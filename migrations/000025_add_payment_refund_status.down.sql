@@ -0,0 +1,8 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payment_refunds_pending;
+
+-- Drop refunds that never went through
+DELETE FROM payment_refunds WHERE status <> 'succeeded';
+
+-- Drop columns
+ALTER TABLE payment_refunds DROP COLUMN IF EXISTS status;
@@ -0,0 +1,7 @@
+-- Track refunds from before they are sent to the gateway; earlier refunds were
+-- only recorded once they succeeded
+ALTER TABLE payment_refunds ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'succeeded';
+ALTER TABLE payment_refunds ALTER COLUMN status DROP DEFAULT;
+
+-- Create indexes
+CREATE INDEX idx_payment_refunds_pending ON payment_refunds(payment_id) WHERE status = 'pending';
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_payments_gateway_order_id;
+
+-- Drop columns
+ALTER TABLE payments DROP COLUMN IF EXISTS attempts;
+ALTER TABLE payments DROP COLUMN IF EXISTS gateway_order_id;
@@ -0,0 +1,10 @@
+-- Track the order_id each payment attempt was charged under; gateways refuse
+-- a second charge for an order_id, so retries get a numbered suffix
+ALTER TABLE payments ADD COLUMN gateway_order_id VARCHAR(60);
+ALTER TABLE payments ADD COLUMN attempts INTEGER NOT NULL DEFAULT 1;
+
+-- Earlier payments were charged under the order number
+UPDATE payments p SET gateway_order_id = o.order_number FROM orders o WHERE o.id = p.order_id;
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_payments_gateway_order_id ON payments(gateway_order_id) WHERE gateway_order_id IS NOT NULL AND gateway_order_id <> '';
@@ -0,0 +1,5 @@
+-- Drop sequences
+DROP SEQUENCE IF EXISTS return_requests_rma_seq;
+
+-- Drop columns
+ALTER TABLE orders DROP COLUMN IF EXISTS delivered_at;
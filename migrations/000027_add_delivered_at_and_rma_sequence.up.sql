@@ -0,0 +1,16 @@
+-- Record when an order was delivered; the return window is measured from it
+-- and updated_at moves on every later edit
+ALTER TABLE orders ADD COLUMN delivered_at TIMESTAMP;
+
+-- Delivered orders take the time of their last move to delivered, or their
+-- last update when that move predates the status history
+UPDATE orders o SET delivered_at = COALESCE(
+    (SELECT MAX(h.created_at) FROM order_status_history h WHERE h.order_id = o.id AND h.to_status = 'delivered'),
+    o.updated_at
+)
+WHERE o.status = 'delivered';
+
+-- Number return requests from a sequence so concurrent requests for an order
+-- never share an RMA number
+CREATE SEQUENCE return_requests_rma_seq;
+SELECT setval('return_requests_rma_seq', COALESCE((SELECT MAX(id) FROM return_requests), 0) + 1, false);
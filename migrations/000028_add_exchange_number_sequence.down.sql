@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_exchange_requests_replacement_order_id;
+
+-- Drop sequences
+DROP SEQUENCE IF EXISTS exchange_requests_number_seq;
@@ -0,0 +1,7 @@
+-- Number exchange requests from a sequence so concurrent requests for an
+-- order never share an exchange number
+CREATE SEQUENCE exchange_requests_number_seq;
+SELECT setval('exchange_requests_number_seq', COALESCE((SELECT MAX(id) FROM exchange_requests), 0) + 1, false);
+
+-- Find approved exchanges whose replacement order awaits the price difference
+CREATE INDEX idx_exchange_requests_replacement_order_id ON exchange_requests(replacement_order_id) WHERE status = 'approved';
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_loyalty_lot_usages_debit_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS loyalty_lot_usages;
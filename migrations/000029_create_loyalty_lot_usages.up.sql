@@ -0,0 +1,12 @@
+-- Remember which entries each debit took its points from, so points given
+-- back on a cancelled order keep the expiry they were earned with
+CREATE TABLE loyalty_lot_usages (
+    id SERIAL PRIMARY KEY,
+    debit_id INTEGER NOT NULL REFERENCES loyalty_transactions(id),
+    lot_id INTEGER NOT NULL REFERENCES loyalty_transactions(id),
+    points INTEGER NOT NULL CHECK (points > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_loyalty_lot_usages_debit_id ON loyalty_lot_usages(debit_id);
@@ -0,0 +1,5 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS tags_search_index_change ON tags;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS tags_search_index_change();
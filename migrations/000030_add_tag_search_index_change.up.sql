@@ -0,0 +1,11 @@
+-- Resend the products of a tag to the search index when the tag is renamed
+CREATE OR REPLACE FUNCTION tags_search_index_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM record_search_index_change(product_id) FROM product_tags WHERE tag_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tags_search_index_change
+    AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION tags_search_index_change();
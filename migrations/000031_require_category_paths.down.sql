@@ -0,0 +1,3 @@
+-- Drop constraints
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_path_not_empty;
+ALTER TABLE categories ALTER COLUMN path SET DEFAULT '';
@@ -0,0 +1,16 @@
+-- Every category needs a tree path: an empty one matches every category in
+-- the path LIKE subtree queries. Stop here, listing them, when a category
+-- was left without one, e.g. because its parent no longer exists.
+DO $$
+DECLARE
+    missing TEXT;
+BEGIN
+    SELECT string_agg(id::TEXT, ', ' ORDER BY id) INTO missing FROM categories WHERE path = '';
+    IF missing IS NOT NULL THEN
+        RAISE EXCEPTION 'categories without a tree path: %, fix their parent_id first', missing;
+    END IF;
+END
+$$;
+
+ALTER TABLE categories ALTER COLUMN path DROP DEFAULT;
+ALTER TABLE categories ADD CONSTRAINT categories_path_not_empty CHECK (path <> '');
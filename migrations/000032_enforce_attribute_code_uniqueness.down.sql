@@ -0,0 +1,7 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS categories_unique_attribute_codes ON categories;
+DROP TRIGGER IF EXISTS attribute_definitions_unique_code ON attribute_definitions;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS categories_unique_attribute_codes();
+DROP FUNCTION IF EXISTS attribute_definitions_unique_code();
@@ -0,0 +1,56 @@
+-- An attribute code is unique along every path of the category tree: a
+-- category's attributes also apply to its subcategories. Refuse a definition
+-- whose code is already defined on the category, an ancestor or a
+-- subcategory. Definitions of one code are serialised with an advisory lock
+-- so concurrent inserts cannot both pass the check.
+CREATE OR REPLACE FUNCTION attribute_definitions_unique_code() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('attribute_definitions:' || NEW.code));
+
+    IF EXISTS (
+        SELECT 1 FROM attribute_definitions a
+        JOIN categories c ON c.id = a.category_id
+        JOIN categories target ON target.id = NEW.category_id
+        WHERE a.code = NEW.code AND a.id <> NEW.id AND c.deleted_at IS NULL
+            AND (c.path LIKE target.path || '%' OR target.path LIKE c.path || '%')
+    ) THEN
+        RAISE EXCEPTION 'attribute % is already defined on category %, a parent or a subcategory', NEW.code, NEW.category_id
+            USING ERRCODE = 'unique_violation';
+    END IF;
+
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER attribute_definitions_unique_code
+    BEFORE INSERT OR UPDATE OF code, category_id ON attribute_definitions
+    FOR EACH ROW EXECUTE FUNCTION attribute_definitions_unique_code();
+
+-- Moving a category must not bring a code defined in its subtree under an
+-- ancestor defining it too
+CREATE OR REPLACE FUNCTION categories_unique_attribute_codes() RETURNS trigger AS $$
+DECLARE
+    duplicate TEXT;
+BEGIN
+    SELECT inner_def.code INTO duplicate
+    FROM attribute_definitions inner_def
+    JOIN categories inner_cat ON inner_cat.id = inner_def.category_id
+    JOIN attribute_definitions outer_def ON outer_def.code = inner_def.code
+    JOIN categories outer_cat ON outer_cat.id = outer_def.category_id
+    WHERE inner_cat.path LIKE NEW.path || '%' AND inner_cat.deleted_at IS NULL
+        AND NEW.path LIKE outer_cat.path || '_%' AND outer_cat.deleted_at IS NULL
+    LIMIT 1;
+
+    IF duplicate IS NOT NULL THEN
+        RAISE EXCEPTION 'attribute % would be defined twice along the path of category %', duplicate, NEW.id
+            USING ERRCODE = 'unique_violation';
+    END IF;
+
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_unique_attribute_codes
+    AFTER UPDATE OF parent_id ON categories
+    FOR EACH ROW WHEN (OLD.parent_id IS DISTINCT FROM NEW.parent_id)
+    EXECUTE FUNCTION categories_unique_attribute_codes();
@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_images_content_hash;
+DROP INDEX IF EXISTS idx_product_images_product_content_hash;
+
+-- Restore the previous indexes
+CREATE INDEX idx_product_images_content_hash ON product_images(content_hash) WHERE content_hash IS NOT NULL;
+CREATE UNIQUE INDEX idx_product_images_product_content_hash ON product_images(product_id, content_hash) WHERE content_hash IS NOT NULL;
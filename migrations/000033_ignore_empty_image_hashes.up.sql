@@ -0,0 +1,9 @@
+-- Images stored without a content hash have an empty one
+UPDATE product_images SET content_hash = NULL WHERE content_hash = '';
+UPDATE categories SET image_hash = NULL WHERE image_hash = '';
+
+-- Recreate indexes so images without a content hash never conflict
+DROP INDEX IF EXISTS idx_product_images_content_hash;
+DROP INDEX IF EXISTS idx_product_images_product_content_hash;
+CREATE INDEX idx_product_images_content_hash ON product_images(content_hash) WHERE content_hash IS NOT NULL AND content_hash <> '';
+CREATE UNIQUE INDEX idx_product_images_product_content_hash ON product_images(product_id, content_hash) WHERE content_hash IS NOT NULL AND content_hash <> '';
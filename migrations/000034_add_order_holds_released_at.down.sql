@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_orders_unreleased;
+
+-- Drop columns
+ALTER TABLE orders DROP COLUMN IF EXISTS holds_released_at;
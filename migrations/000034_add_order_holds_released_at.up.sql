@@ -0,0 +1,10 @@
+-- Record when the voucher use, flash sale quota, loyalty points, store credit
+-- and gift cards held by a cancelled order were given back, so a release that
+-- failed after the order was cancelled is retried
+ALTER TABLE orders ADD COLUMN holds_released_at TIMESTAMP;
+
+-- Orders cancelled before now were released when they were cancelled
+UPDATE orders SET holds_released_at = updated_at WHERE status = 'cancelled';
+
+-- Create indexes
+CREATE INDEX idx_orders_unreleased ON orders(updated_at) WHERE status = 'cancelled' AND holds_released_at IS NULL;
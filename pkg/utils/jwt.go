@@ -1,46 +0,0 @@
-package utils
-
-import (
-	"clothing-shop-api/internal/config"
-	"errors"
-	"time"
-
-	"github.com/dgrijalva/jwt-go"
-)
-
-type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.StandardClaims
-}
-
-func GenerateToken(userID uint, email string, role string) (string, error) {
-	cfg := config.GetConfig()
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.JWTSecret))
-}
-
-func ValidateToken(tokenString string) (*Claims, error) {
-	cfg := config.GetConfig()
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.JWTSecret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return nil, errors.New("invalid token")
-	}
-
-	return claims, nil
-}